@@ -14,6 +14,6 @@ func main() {
 	defer cancel()
 
 	if err := cmd.ExecuteContext(ctx, os.Args[1:]); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -13,34 +13,71 @@ import (
 
 // GetAudienceGroups returns a list of audience groups
 func (c *Client) GetAudienceGroups(ctx context.Context) ([]generated.AudienceGroup, error) {
-	data, err := c.Get(ctx, "/v2/bot/audienceGroup/list?page=1&size=40")
+	resp, err := c.GetAudienceGroupsPage(ctx, 1, 0)
 	if err != nil {
 		return nil, err
 	}
-	var resp generated.GetAudienceGroupsResponse
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse audience groups: %w", err)
-	}
 	if resp.AudienceGroups == nil {
 		return []generated.AudienceGroup{}, nil
 	}
 	return *resp.AudienceGroups, nil
 }
 
+// GetAudienceGroupsPage returns a single page of audience groups, including
+// the paging metadata (hasNextPage, page, totalCount). A size below 1 uses
+// the maximum of 40.
+// GET /v2/bot/audienceGroup/list?page={page}&size={size}
+func (c *Client) GetAudienceGroupsPage(ctx context.Context, page, size int64) (*generated.GetAudienceGroupsResponse, error) {
+	page, size = normalizeAudiencePage(page, size)
+	data, err := c.Get(ctx, fmt.Sprintf("/v2/bot/audienceGroup/list?page=%d&size=%d", page, size))
+	if err != nil {
+		return nil, err
+	}
+	var resp generated.GetAudienceGroupsResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse audience groups: %w", err)
+	}
+	return &resp, nil
+}
+
+// AudienceGroupDetail is the response of GetAudienceGroup. ExpireTimestamp
+// is read from audienceGroup.expireTimestamp, which the API returns for some
+// audiences but the generated type omits.
+type AudienceGroupDetail struct {
+	generated.GetAudienceDataResponse
+	ExpireTimestamp *int64 `json:"expireTimestamp,omitempty"`
+}
+
 // GetAudienceGroup returns a single audience group by ID
-func (c *Client) GetAudienceGroup(ctx context.Context, audienceGroupID int64) (*generated.GetAudienceDataResponse, error) {
+func (c *Client) GetAudienceGroup(ctx context.Context, audienceGroupID int64) (*AudienceGroupDetail, error) {
 	path := fmt.Sprintf("/v2/bot/audienceGroup/%d", audienceGroupID)
 	data, err := c.Get(ctx, path)
 	if err != nil {
 		return nil, err
 	}
-	var resp generated.GetAudienceDataResponse
-	if err := json.Unmarshal(data, &resp); err != nil {
+	var resp AudienceGroupDetail
+	if err := json.Unmarshal(data, &resp.GetAudienceDataResponse); err != nil {
 		return nil, fmt.Errorf("failed to parse audience group: %w", err)
 	}
+	var expiry struct {
+		AudienceGroup struct {
+			ExpireTimestamp *int64 `json:"expireTimestamp"`
+		} `json:"audienceGroup"`
+	}
+	if err := json.Unmarshal(data, &expiry); err == nil {
+		resp.ExpireTimestamp = expiry.AudienceGroup.ExpireTimestamp
+	}
 	return &resp, nil
 }
 
+// ActivateAudienceGroup activates an inactive audience group
+// PUT /v2/bot/audienceGroup/{audienceGroupId}/activate
+func (c *Client) ActivateAudienceGroup(ctx context.Context, audienceGroupID int64) error {
+	path := fmt.Sprintf("/v2/bot/audienceGroup/%d/activate", audienceGroupID)
+	_, err := c.Put(ctx, path, nil)
+	return err
+}
+
 // DeleteAudienceGroup deletes an audience group
 func (c *Client) DeleteAudienceGroup(ctx context.Context, audienceGroupID int64) error {
 	path := fmt.Sprintf("/v2/bot/audienceGroup/%d", audienceGroupID)
@@ -186,10 +223,25 @@ func (c *Client) UpdateAudienceDescription(ctx context.Context, audienceGroupID
 	return err
 }
 
-// GetSharedAudienceGroups lists shared audience groups
+// GetSharedAudienceGroups lists the first page of shared audience groups
 // GET /v2/bot/audienceGroup/shared/list
 func (c *Client) GetSharedAudienceGroups(ctx context.Context) ([]generated.AudienceGroup, error) {
-	data, err := c.Get(ctx, "/v2/bot/audienceGroup/shared/list?page=1&size=40")
+	resp, err := c.GetSharedAudienceGroupsPage(ctx, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if resp.AudienceGroups == nil {
+		return []generated.AudienceGroup{}, nil
+	}
+	return *resp.AudienceGroups, nil
+}
+
+// GetSharedAudienceGroupsPage returns a single page of shared audience
+// groups with its paging metadata. A size below 1 uses the maximum of 40.
+// GET /v2/bot/audienceGroup/shared/list?page={page}&size={size}
+func (c *Client) GetSharedAudienceGroupsPage(ctx context.Context, page, size int64) (*generated.GetSharedAudienceGroupsResponse, error) {
+	page, size = normalizeAudiencePage(page, size)
+	data, err := c.Get(ctx, fmt.Sprintf("/v2/bot/audienceGroup/shared/list?page=%d&size=%d", page, size))
 	if err != nil {
 		return nil, err
 	}
@@ -197,10 +249,19 @@ func (c *Client) GetSharedAudienceGroups(ctx context.Context) ([]generated.Audie
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, fmt.Errorf("failed to parse shared audience groups: %w", err)
 	}
-	if resp.AudienceGroups == nil {
-		return []generated.AudienceGroup{}, nil
+	return &resp, nil
+}
+
+// normalizeAudiencePage clamps paging parameters to what the audience list
+// endpoints accept.
+func normalizeAudiencePage(page, size int64) (int64, int64) {
+	if page < 1 {
+		page = 1
 	}
-	return *resp.AudienceGroups, nil
+	if size < 1 || size > 40 {
+		size = 40
+	}
+	return page, size
 }
 
 // GetSharedAudienceGroup gets a shared audience group by ID
@@ -37,6 +37,34 @@ func TestClient_GetAudienceGroups(t *testing.T) {
 	}
 }
 
+func TestClient_GetAudienceGroupsPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "3" {
+			t.Errorf("expected page=3, got %s", r.URL.Query().Get("page"))
+		}
+		if r.URL.Query().Get("size") != "10" {
+			t.Errorf("expected size=10, got %s", r.URL.Query().Get("size"))
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"audienceGroups":[{"audienceGroupId":1}],"hasNextPage":true,"page":3}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+
+	resp, err := client.GetAudienceGroupsPage(context.Background(), 3, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.HasNextPage == nil || !*resp.HasNextPage {
+		t.Error("expected hasNextPage to be true")
+	}
+	if resp.Page == nil || *resp.Page != 3 {
+		t.Errorf("expected page 3, got %v", resp.Page)
+	}
+}
+
 func TestClient_GetAudienceGroup(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/v2/bot/audienceGroup/12345" {
@@ -65,6 +93,47 @@ func TestClient_GetAudienceGroup(t *testing.T) {
 	}
 }
 
+func TestClient_GetAudienceGroup_ExpireTimestamp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"audienceGroup":{"audienceGroupId":12345,"isIfaAudience":false,"expireTimestamp":1735689600}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+
+	resp, err := client.GetAudienceGroup(context.Background(), 12345)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ExpireTimestamp == nil || *resp.ExpireTimestamp != 1735689600 {
+		t.Errorf("expected expireTimestamp 1735689600, got %v", resp.ExpireTimestamp)
+	}
+	if resp.AudienceGroup.IsIfaAudience == nil || *resp.AudienceGroup.IsIfaAudience {
+		t.Errorf("expected isIfaAudience false, got %v", resp.AudienceGroup.IsIfaAudience)
+	}
+}
+
+func TestClient_ActivateAudienceGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/audienceGroup/12345/activate" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+
+	if err := client.ActivateAudienceGroup(context.Background(), 12345); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestClient_DeleteAudienceGroup(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/v2/bot/audienceGroup/12345" {
@@ -263,6 +332,34 @@ func TestClient_UpdateAudienceDescription(t *testing.T) {
 	}
 }
 
+func TestClient_GetSharedAudienceGroupsPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "2" {
+			t.Errorf("expected page=2, got %s", r.URL.Query().Get("page"))
+		}
+		if r.URL.Query().Get("size") != "40" {
+			t.Errorf("expected size clamped to 40, got %s", r.URL.Query().Get("size"))
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"audienceGroups":[{"audienceGroupId":1}],"hasNextPage":false,"page":2}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+
+	resp, err := client.GetSharedAudienceGroupsPage(context.Background(), 2, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.HasNextPage == nil || *resp.HasNextPage {
+		t.Error("expected hasNextPage to be false")
+	}
+	if resp.AudienceGroups == nil || len(*resp.AudienceGroups) != 1 {
+		t.Errorf("expected 1 group, got %v", resp.AudienceGroups)
+	}
+}
+
 func TestClient_GetSharedAudienceGroups(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/v2/bot/audienceGroup/shared/list" {
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// CacheOptions configure SetCache.
+type CacheOptions struct {
+	// Dir holds the cache, one subdirectory per Key
+	Dir string
+	// Key separates entries, normally by account, so one account is never
+	// answered with another's data
+	Key string
+	// TTL is how long a response is served from the cache
+	TTL time.Duration
+}
+
+// cacheablePaths are the read-only endpoints SetCache serves from disk.
+// They change rarely and are fetched over and over by shell completion and
+// scripts; anything that reports progress or usage is left out.
+var cacheablePaths = []*regexp.Regexp{
+	regexp.MustCompile(`^/v2/bot/info$`),
+	regexp.MustCompile(`^/v2/bot/profile/[^/]+$`),
+	regexp.MustCompile(`^/v2/bot/richmenu/list$`),
+	regexp.MustCompile(`^/v2/bot/richmenu/alias/list$`),
+	regexp.MustCompile(`^/v2/bot/audienceGroup/list$`),
+	regexp.MustCompile(`^/v2/bot/audienceGroup/shared/list$`),
+	regexp.MustCompile(`^/liff/v1/apps$`),
+	regexp.MustCompile(`^/v2/bot/channel/webhook/endpoint$`),
+}
+
+// Cacheable reports whether SetCache caches GET requests to path.
+func Cacheable(path string) bool {
+	for _, re := range cacheablePaths {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheEntry is a cached response as stored on disk.
+type cacheEntry struct {
+	URL         string    `json:"url"`
+	StoredAt    time.Time `json:"storedAt"`
+	ContentType string    `json:"contentType,omitempty"`
+	Body        []byte    `json:"body"`
+}
+
+// SetCache answers GET requests to Cacheable endpoints from an on-disk
+// cache for opts.TTL after a successful response. Any successful request
+// that is not a GET clears the entries under opts.Key, so a change made
+// through the client is seen at once.
+func (c *Client) SetCache(opts CacheOptions) error {
+	if opts.TTL <= 0 {
+		return fmt.Errorf("cache TTL must be positive")
+	}
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = &cacheTransport{
+		next: next,
+		dir:  filepath.Join(opts.Dir, cacheKeyDir(opts.Key)),
+		ttl:  opts.TTL,
+		now:  func() time.Time { return c.now() },
+	}
+	return nil
+}
+
+// CountCache returns the number of entries under dir, or only those for
+// key if it is not empty.
+func CountCache(dir, key string) (int, error) {
+	if key != "" {
+		dir = filepath.Join(dir, cacheKeyDir(key))
+	}
+	n := 0
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".json" {
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache: %w", err)
+	}
+	return n, nil
+}
+
+// ClearCache removes every entry under dir, or only those for key if it is
+// not empty. It returns the number of entries removed.
+func ClearCache(dir, key string) (int, error) {
+	n, err := CountCache(dir, key)
+	if err != nil {
+		return 0, err
+	}
+	if key != "" {
+		dir = filepath.Join(dir, cacheKeyDir(key))
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return n, nil
+}
+
+var cacheKeyUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// cacheKeyDir returns a directory name for key that is safe on any file
+// system.
+func cacheKeyDir(key string) string {
+	name := cacheKeyUnsafe.ReplaceAllString(key, "_")
+	if name == "" || name == "." || name == ".." {
+		name = "_default"
+	}
+	return name
+}
+
+// cacheTransport is an http.RoundTripper that serves cacheable GET
+// requests from disk.
+type cacheTransport struct {
+	next http.RoundTripper
+	dir  string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 300 {
+			_ = os.RemoveAll(t.dir)
+		}
+		return resp, err
+	}
+	if !Cacheable(req.URL.Path) {
+		return t.next.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	if entry, ok := t.load(path, req.URL.String()); ok {
+		header := http.Header{"X-Line-Cli-Cache": {"hit"}}
+		if entry.ContentType != "" {
+			header.Set("Content-Type", entry.ContentType)
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+			ContentLength: int64(len(entry.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// Failing to cache is never an error; the next request refetches
+	_ = t.store(path, cacheEntry{
+		URL:         req.URL.String(),
+		StoredAt:    t.now(),
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	return resp, nil
+}
+
+// path returns the file of the entry for req, named after a hash of its
+// URL so user IDs in paths do not end up in file names.
+func (t *cacheTransport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// load returns the entry in path if it is for url and has not expired.
+func (t *cacheTransport) load(path, url string) (cacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return cacheEntry{}, false
+	}
+	if age := t.now().Sub(entry.StoredAt); age < 0 || age >= t.ttl {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (t *cacheTransport) store(path string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(t.dir, ".entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
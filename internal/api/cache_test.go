@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/v2/bot/profile/Ufail" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"displayName":"Bot"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	newClient := func(key string) *Client {
+		c := NewClient("test-token", false, false)
+		c.SetBaseURL(server.URL)
+		c.SetClock(func() time.Time { return now })
+		if err := c.SetCache(CacheOptions{Dir: dir, Key: key, TTL: time.Minute}); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	ctx := context.Background()
+	get := func(c *Client, path string) {
+		t.Helper()
+		_, _ = c.Get(ctx, path)
+	}
+	expect := func(want int32, what string) {
+		t.Helper()
+		if got := requests.Load(); got != want {
+			t.Errorf("%s: expected %d requests, got %d", what, want, got)
+		}
+	}
+
+	shop := newClient("shop")
+	get(shop, "/v2/bot/info")
+	get(shop, "/v2/bot/info")
+	expect(1, "repeated cacheable GET")
+
+	data, err := newClient("shop").Get(ctx, "/v2/bot/info")
+	if err != nil || string(data) != `{"displayName":"Bot"}` {
+		t.Errorf("cached body = %s, %v", data, err)
+	}
+	expect(1, "new client for the same key")
+
+	get(newClient("other"), "/v2/bot/info")
+	expect(2, "another key")
+
+	get(shop, "/v2/bot/message/quota")
+	get(shop, "/v2/bot/message/quota")
+	expect(4, "non-cacheable GET")
+
+	get(shop, "/v2/bot/profile/Ufail")
+	get(shop, "/v2/bot/profile/Ufail")
+	expect(6, "failed GET")
+
+	now = now.Add(time.Minute)
+	get(shop, "/v2/bot/info")
+	expect(7, "expired entry")
+
+	other := newClient("other")
+	get(other, "/v2/bot/info")
+	expect(8, "expired entry of another key")
+	_, _ = shop.Post(ctx, "/v2/bot/richmenu", map[string]string{})
+	expect(9, "POST")
+	get(shop, "/v2/bot/info")
+	expect(10, "GET after a change")
+	get(other, "/v2/bot/info")
+	expect(10, "another key after a change")
+}
+
+func TestClearCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for _, key := range []string{"shop", "other"} {
+		c := NewClient("test-token", false, false)
+		c.SetBaseURL(server.URL)
+		if err := c.SetCache(CacheOptions{Dir: dir, Key: key, TTL: time.Hour}); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = c.Get(context.Background(), "/v2/bot/info")
+		_, _ = c.Get(context.Background(), "/v2/bot/richmenu/list")
+	}
+
+	if n, err := CountCache(dir, ""); err != nil || n != 4 {
+		t.Errorf("CountCache(all) = %d, %v; want 4", n, err)
+	}
+	if n, err := ClearCache(dir, "shop"); err != nil || n != 2 {
+		t.Errorf("ClearCache(shop) = %d, %v; want 2", n, err)
+	}
+	if n, err := ClearCache(dir, ""); err != nil || n != 2 {
+		t.Errorf("ClearCache(all) = %d, %v; want 2", n, err)
+	}
+	if n, err := ClearCache(dir, ""); err != nil || n != 0 {
+		t.Errorf("ClearCache(empty) = %d, %v; want 0", n, err)
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/v2/bot/info":                    true,
+		"/v2/bot/profile/U123":            true,
+		"/v2/bot/richmenu/list":           true,
+		"/v2/bot/audienceGroup/list":      true,
+		"/v2/bot/audienceGroup/123":       false,
+		"/v2/bot/message/quota":           false,
+		"/v2/bot/richmenu/progress/batch": false,
+	} {
+		if got := Cacheable(path); got != want {
+			t.Errorf("Cacheable(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
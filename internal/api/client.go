@@ -3,103 +3,197 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/logging"
 )
 
 const BaseURL = "https://api.line.me"
 
 type Client struct {
-	httpClient         *http.Client
-	channelAccessToken string
-	baseURL            string
-	debug              bool
-	dryRun             bool
+	httpClient *http.Client
+	tokens     TokenProvider
+	baseURL    string
+	logger     *slog.Logger
+	dryRun     bool
+	now        func() time.Time
+	newUUID    func() string
+	retry      RetryOptions
+	sleep      func(ctx context.Context, d time.Duration) error
 }
 
 func NewClient(channelAccessToken string, debug bool, dryRun bool) *Client {
+	logger := logging.Discard()
+	if debug || dryRun { // dry-run implies debug
+		logger = logging.NewTerminal(os.Stderr, slog.LevelDebug)
+	}
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		channelAccessToken: channelAccessToken,
-		baseURL:            BaseURL,
-		debug:              debug || dryRun, // dry-run implies debug
-		dryRun:             dryRun,
+		tokens:  StaticToken(channelAccessToken),
+		baseURL: BaseURL,
+		logger:  logger,
+		dryRun:  dryRun,
+		now:     time.Now,
+		newUUID: RandomUUID,
+		sleep:   sleepContext,
 	}
 }
 
-// SetBaseURL sets the base URL for API requests (used for testing)
+// SetBaseURL sets the base URL for API requests, e.g. a proxy or a test server
 func (c *Client) SetBaseURL(url string) {
 	c.baseURL = url
 }
 
-const debugMaxBodyLen = 500
+// SetLogger sets the logger requests, responses, retries, and failovers are
+// logged to. Requests and responses are logged at debug level.
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
 
-// debugLog prints a debug message to stderr with [DEBUG] prefix
-func (c *Client) debugLog(format string, args ...any) {
-	if !c.debug {
-		return
+// SetClock sets the time source used by the client. Defaults to time.Now.
+func (c *Client) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// SetUUIDGenerator sets the generator used for request identifiers such as
+// the X-Line-Retry-Key of sends. Defaults to RandomUUID.
+func (c *Client) SetUUIDGenerator(fn func() string) {
+	c.newUUID = fn
+}
+
+// Now returns the current time from the client's clock.
+func (c *Client) Now() time.Time {
+	return c.now()
+}
+
+// NewUUID returns a new identifier from the client's UUID generator.
+func (c *Client) NewUUID() string {
+	return c.newUUID()
+}
+
+// RandomUUID returns a random (version 4) UUID string.
+func RandomUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])      // never returns an error
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// OnResponse registers fn to be called with the status code of every HTTP
+// response the client receives. It is not called for dry-run or failed
+// (network error) requests.
+func (c *Client) OnResponse(fn func(statusCode int)) {
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = responseObserver{next: next, fn: fn}
+}
+
+// responseObserver is an http.RoundTripper that reports response status codes.
+type responseObserver struct {
+	next http.RoundTripper
+	fn   func(statusCode int)
+}
+
+func (o responseObserver) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := o.next.RoundTrip(req)
+	if err == nil {
+		o.fn(resp.StatusCode)
 	}
-	fmt.Fprintf(os.Stderr, "[DEBUG] "+format+"\n", args...)
+	return resp, err
+}
+
+const debugMaxBodyLen = 500
+
+// log writes a record to the client's logger.
+func (c *Client) log(level slog.Level, msg string, args ...any) {
+	c.logger.Log(context.Background(), level, msg, args...)
+}
+
+// debugEnabled reports whether debug records are logged, so callers can skip
+// building them.
+func (c *Client) debugEnabled() bool {
+	return c.logger.Enabled(context.Background(), slog.LevelDebug)
 }
 
 // debugLogRequest logs HTTP request details (method, URL, headers, body preview)
 func (c *Client) debugLogRequest(req *http.Request, body []byte) {
-	if !c.debug {
+	if !c.debugEnabled() {
 		return
 	}
-	c.debugLog(">>> %s %s", req.Method, req.URL.String())
-	c.debugLogHeaders(">>> ", req.Header, true)
+	args := []any{"method", req.Method, "url", req.URL.String(), logHeaders(req.Header, true)}
 	if len(body) > 0 {
-		c.debugLogBody(">>> Body: ", body)
+		args = append(args, "body", bodyPreview(body))
 	}
+	c.log(slog.LevelDebug, "http request", args...)
 }
 
 // debugLogResponse logs HTTP response details (status, headers, body preview)
 func (c *Client) debugLogResponse(resp *http.Response, body []byte) {
-	if !c.debug {
+	if !c.debugEnabled() {
 		return
 	}
-	c.debugLog("<<< %s", resp.Status)
-	c.debugLogHeaders("<<< ", resp.Header, false)
+	args := []any{"status", resp.StatusCode, logHeaders(resp.Header, false)}
 	if len(body) > 0 {
-		c.debugLogBody("<<< Body: ", body)
+		args = append(args, "body", bodyPreview(body))
 	}
+	c.log(slog.LevelDebug, "http response", args...)
 }
 
-// debugLogHeaders logs headers, redacting Authorization token
-func (c *Client) debugLogHeaders(prefix string, headers http.Header, redactAuth bool) {
-	for name, values := range headers {
-		for _, value := range values {
-			if redactAuth && strings.EqualFold(name, "Authorization") {
-				// Redact the token but show it's a Bearer token
-				if strings.HasPrefix(value, "Bearer ") {
-					c.debugLog("%s%s: Bearer [REDACTED]", prefix, name)
-				} else {
-					c.debugLog("%s%s: [REDACTED]", prefix, name)
-				}
+// debugLogBinaryRequest logs a request whose body is not shown, such as an
+// image or multipart upload, by its description
+func (c *Client) debugLogBinaryRequest(req *http.Request, body string) {
+	if !c.debugEnabled() {
+		return
+	}
+	c.log(slog.LevelDebug, "http request", "method", req.Method, "url", req.URL.String(), logHeaders(req.Header, true), "body", body)
+}
+
+// logHeaders groups headers for a log record, redacting the Authorization
+// token
+func logHeaders(headers http.Header, redactAuth bool) slog.Attr {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(headers[name], ", ")
+		if redactAuth && strings.EqualFold(name, "Authorization") {
+			// Redact the token but show it's a Bearer token
+			if strings.HasPrefix(value, "Bearer ") {
+				value = "Bearer [REDACTED]"
 			} else {
-				c.debugLog("%s%s: %s", prefix, name, value)
+				value = "[REDACTED]"
 			}
 		}
+		attrs = append(attrs, slog.String(name, value))
 	}
+	return slog.Group("headers", attrs...)
 }
 
-// debugLogBody logs body content, truncating if too long
-func (c *Client) debugLogBody(prefix string, body []byte) {
+// bodyPreview returns body, truncated if too long
+func bodyPreview(body []byte) string {
 	bodyStr := string(body)
 	if len(bodyStr) > debugMaxBodyLen {
-		c.debugLog("%s%s... (%d bytes truncated)", prefix, bodyStr[:debugMaxBodyLen], len(bodyStr)-debugMaxBodyLen)
-	} else {
-		c.debugLog("%s%s", prefix, bodyStr)
+		return fmt.Sprintf("%s... (%d bytes truncated)", bodyStr[:debugMaxBodyLen], len(bodyStr)-debugMaxBodyLen)
 	}
+	return bodyStr
 }
 
 // dryRunLog prints a dry-run message to stderr
@@ -140,8 +234,13 @@ func (c *Client) doWithHeaders(ctx context.Context, method, path string, body an
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.channelAccessToken)
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", "application/json")
+	if method == http.MethodPost && retryKeyEndpoints[path] {
+		req.Header.Set("X-Line-Retry-Key", c.NewUUID())
+	}
 
 	c.debugLogRequest(req, bodyData)
 
@@ -150,7 +249,7 @@ func (c *Client) doWithHeaders(ctx context.Context, method, path string, body an
 		return c.mockDryRunResponse(method), nil
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -164,7 +263,7 @@ func (c *Client) doWithHeaders(ctx context.Context, method, path string, body an
 	c.debugLogResponse(resp, respBody)
 
 	if resp.StatusCode >= 400 {
-		return nil, ParseAPIError(resp.StatusCode, method, path, respBody)
+		return nil, responseError(resp, method, path, respBody)
 	}
 
 	return &Response{Body: respBody, Headers: resp.Header}, nil
@@ -282,7 +381,9 @@ func (c *Client) GetBinary(ctx context.Context, path string) ([]byte, string, er
 		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.channelAccessToken)
+	if err := c.authorize(req); err != nil {
+		return nil, "", err
+	}
 
 	c.debugLogRequest(req, nil)
 
@@ -292,7 +393,7 @@ func (c *Client) GetBinary(ctx context.Context, path string) ([]byte, string, er
 		return []byte{}, "application/octet-stream", nil
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, "", fmt.Errorf("request failed: %w", err)
 	}
@@ -301,7 +402,7 @@ func (c *Client) GetBinary(ctx context.Context, path string) ([]byte, string, er
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
 		c.debugLogResponse(resp, body)
-		return nil, "", ParseAPIError(resp.StatusCode, http.MethodGet, path, body)
+		return nil, "", responseError(resp, http.MethodGet, path, body)
 	}
 
 	data, err := io.ReadAll(resp.Body)
@@ -310,9 +411,9 @@ func (c *Client) GetBinary(ctx context.Context, path string) ([]byte, string, er
 	}
 
 	// For binary responses, log status and headers but not body (it's binary data)
-	c.debugLog("<<< %s", resp.Status)
-	c.debugLogHeaders("<<< ", resp.Header, false)
-	c.debugLog("<<< Body: [binary data, %d bytes]", len(data))
+	if c.debugEnabled() {
+		c.log(slog.LevelDebug, "http response", "status", resp.StatusCode, logHeaders(resp.Header, false), "body", fmt.Sprintf("[binary data, %d bytes]", len(data)))
+	}
 
 	contentType := resp.Header.Get("Content-Type")
 	return data, contentType, nil
@@ -322,7 +423,7 @@ func (c *Client) GetMessageContent(ctx context.Context, messageID string) ([]byt
 	// Use data API endpoint for content downloads (only swap if using production URL)
 	if c.baseURL == BaseURL {
 		originalBaseURL := c.baseURL
-		c.baseURL = "https://api-data.line.me"
+		c.baseURL = DataBaseURL
 		defer func() { c.baseURL = originalBaseURL }()
 	}
 
@@ -335,13 +436,13 @@ func (c *Client) PostBinary(ctx context.Context, path string, contentType string
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.channelAccessToken)
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", contentType)
 
 	// Log request with binary body indicator
-	c.debugLog(">>> %s %s", req.Method, req.URL.String())
-	c.debugLogHeaders(">>> ", req.Header, true)
-	c.debugLog(">>> Body: [binary data, %d bytes]", len(data))
+	c.debugLogBinaryRequest(req, fmt.Sprintf("[binary data, %d bytes]", len(data)))
 
 	// In dry-run mode, return mock success
 	if c.dryRun {
@@ -349,7 +450,7 @@ func (c *Client) PostBinary(ctx context.Context, path string, contentType string
 		return []byte("{}"), nil
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -363,7 +464,7 @@ func (c *Client) PostBinary(ctx context.Context, path string, contentType string
 	c.debugLogResponse(resp, respBody)
 
 	if resp.StatusCode >= 400 {
-		return nil, ParseAPIError(resp.StatusCode, http.MethodPost, path, respBody)
+		return nil, responseError(resp, http.MethodPost, path, respBody)
 	}
 
 	return respBody, nil
@@ -375,7 +476,7 @@ func (c *Client) GetMessageContentPreview(ctx context.Context, messageID string)
 	// Use data API endpoint for content downloads (only swap if using production URL)
 	if c.baseURL == BaseURL {
 		originalBaseURL := c.baseURL
-		c.baseURL = "https://api-data.line.me"
+		c.baseURL = DataBaseURL
 		defer func() { c.baseURL = originalBaseURL }()
 	}
 
@@ -450,13 +551,13 @@ func (c *Client) PostMultipart(ctx context.Context, path string, fieldName, file
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.channelAccessToken)
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// Log multipart request
-	c.debugLog(">>> %s %s", req.Method, req.URL.String())
-	c.debugLogHeaders(">>> ", req.Header, true)
-	c.debugLog(">>> Body: [multipart/form-data, file=%s, %d bytes]", fileName, len(fileContent))
+	c.debugLogBinaryRequest(req, fmt.Sprintf("[multipart/form-data, file=%s, %d bytes]", fileName, len(fileContent)))
 
 	// In dry-run mode, return mock success
 	if c.dryRun {
@@ -464,7 +565,7 @@ func (c *Client) PostMultipart(ctx context.Context, path string, fieldName, file
 		return []byte("{}"), nil
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -478,7 +579,7 @@ func (c *Client) PostMultipart(ctx context.Context, path string, fieldName, file
 	c.debugLogResponse(resp, respBody)
 
 	if resp.StatusCode >= 400 {
-		return nil, ParseAPIError(resp.StatusCode, http.MethodPost, path, respBody)
+		return nil, responseError(resp, http.MethodPost, path, respBody)
 	}
 
 	return respBody, nil
@@ -514,13 +615,13 @@ func (c *Client) PutMultipart(ctx context.Context, path string, fieldName, fileN
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.channelAccessToken)
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	// Log multipart request
-	c.debugLog(">>> %s %s", req.Method, req.URL.String())
-	c.debugLogHeaders(">>> ", req.Header, true)
-	c.debugLog(">>> Body: [multipart/form-data, file=%s, %d bytes]", fileName, len(fileContent))
+	c.debugLogBinaryRequest(req, fmt.Sprintf("[multipart/form-data, file=%s, %d bytes]", fileName, len(fileContent)))
 
 	// In dry-run mode, return mock success
 	if c.dryRun {
@@ -528,7 +629,7 @@ func (c *Client) PutMultipart(ctx context.Context, path string, fieldName, fileN
 		return []byte("{}"), nil
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -542,7 +643,7 @@ func (c *Client) PutMultipart(ctx context.Context, path string, fieldName, fileN
 	c.debugLogResponse(resp, respBody)
 
 	if resp.StatusCode >= 400 {
-		return nil, ParseAPIError(resp.StatusCode, http.MethodPut, path, respBody)
+		return nil, responseError(resp, http.MethodPut, path, respBody)
 	}
 
 	return respBody, nil
@@ -1,11 +1,19 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/logging"
 )
 
 func TestClient_Get(t *testing.T) {
@@ -34,6 +42,7 @@ func TestClient_Get(t *testing.T) {
 
 func TestClient_APIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Line-Request-Id", "req-123")
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte(`{"message":"bad request"}`))
 	}))
@@ -46,6 +55,9 @@ func TestClient_APIError(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
+	if apiErr := AsAPIError(err); apiErr == nil || apiErr.RequestID != "req-123" {
+		t.Errorf("expected APIError with request ID req-123, got %#v", err)
+	}
 }
 
 func TestClient_PostBinary(t *testing.T) {
@@ -209,3 +221,111 @@ func TestClient_IssueLinkToken(t *testing.T) {
 		t.Errorf("expected abc123token, got %s", token)
 	}
 }
+
+func TestClient_OnResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var codes []int
+	client.OnResponse(func(statusCode int) { codes = append(codes, statusCode) })
+
+	_, _ = client.Get(context.Background(), "/ok")
+	_, _ = client.Get(context.Background(), "/fail")
+
+	if len(codes) != 2 || codes[0] != http.StatusOK || codes[1] != http.StatusUnauthorized {
+		t.Errorf("unexpected observed status codes: %v", codes)
+	}
+}
+
+func TestClient_ClockAndUUIDInjection(t *testing.T) {
+	client := NewClient("test-token", false, false)
+
+	if time.Since(client.Now()) > time.Minute {
+		t.Error("default clock should be the wall clock")
+	}
+	if !uuidPattern.MatchString(client.NewUUID()) {
+		t.Error("default generator should return a v4 UUID")
+	}
+
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	client.SetClock(func() time.Time { return fixed })
+	client.SetUUIDGenerator(func() string { return "fixed-id" })
+
+	if !client.Now().Equal(fixed) {
+		t.Errorf("Now() = %v, want %v", client.Now(), fixed)
+	}
+	if got := client.NewUUID(); got != "fixed-id" {
+		t.Errorf("NewUUID() = %q, want fixed-id", got)
+	}
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRandomUUID(t *testing.T) {
+	a, b := RandomUUID(), RandomUUID()
+	if !uuidPattern.MatchString(a) {
+		t.Errorf("RandomUUID() = %q, not a v4 UUID", a)
+	}
+	if a == b {
+		t.Error("RandomUUID() returned the same value twice")
+	}
+}
+
+func TestClient_SetLogger(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.Header().Set("X-Line-Request-Id", "req-1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, slog.LevelDebug, logging.FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient("secret-token", false, false)
+	client.SetBaseURL(server.URL)
+	client.SetRetry(RetryOptions{MaxRetries: 1, Backoff: time.Millisecond})
+	client.SetLogger(logger)
+
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("the access token was logged:\n%s", buf.String())
+	}
+
+	var messages []string
+	var retry map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("not a JSON record: %q", line)
+		}
+		messages = append(messages, record["msg"].(string))
+		if record["msg"] == "retrying request" {
+			retry = record
+		}
+	}
+	if want := []string{"http request", "retrying request", "http response"}; strings.Join(messages, ",") != strings.Join(want, ",") {
+		t.Errorf("messages = %v, want %v", messages, want)
+	}
+	if retry == nil || retry["level"] != "INFO" || retry["status"] != float64(503) || retry["requestId"] != "req-1" {
+		t.Errorf("unexpected retry record: %v", retry)
+	}
+}
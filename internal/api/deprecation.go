@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationNotice describes deprecation signals found on an API response.
+type DeprecationNotice struct {
+	// Endpoint is the method and path with ID segments replaced by {id},
+	// e.g. "GET /v2/bot/audienceGroup/{id}"
+	Endpoint string
+	// Deprecated is set when the Deprecation header is present
+	Deprecated bool
+	// Since is when the endpoint was (or will be) deprecated, if given
+	Since time.Time
+	// Sunset is when the endpoint is scheduled to stop working, if given
+	Sunset time.Time
+	// Warnings are the texts of Warning headers
+	Warnings []string
+	// Link is a documentation URL from a rel="deprecation" or rel="sunset"
+	// Link header
+	Link string
+}
+
+// OnDeprecation registers fn to be called for every HTTP response that
+// carries a Deprecation, Sunset, or Warning header.
+func (c *Client) OnDeprecation(fn func(DeprecationNotice)) {
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = deprecationObserver{next: next, fn: fn}
+}
+
+// deprecationObserver is an http.RoundTripper that reports deprecation
+// headers.
+type deprecationObserver struct {
+	next http.RoundTripper
+	fn   func(DeprecationNotice)
+}
+
+func (o deprecationObserver) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := o.next.RoundTrip(req)
+	if err == nil {
+		if n, ok := ParseDeprecation(req.Method, req.URL.Path, resp.Header); ok {
+			o.fn(n)
+		}
+	}
+	return resp, err
+}
+
+// ParseDeprecation reads the Deprecation (RFC 9745), Sunset (RFC 8594), and
+// Warning headers of a response. It reports false if none are present.
+func ParseDeprecation(method, path string, h http.Header) (DeprecationNotice, bool) {
+	n := DeprecationNotice{Endpoint: method + " " + endpointTemplate(path)}
+
+	if v := strings.TrimSpace(h.Get("Deprecation")); v != "" {
+		n.Deprecated = true
+		n.Since = parseDeprecationDate(v)
+	}
+	if v := strings.TrimSpace(h.Get("Sunset")); v != "" {
+		if t, err := http.ParseTime(v); err == nil {
+			n.Sunset = t
+		}
+	}
+	for _, v := range h.Values("Warning") {
+		if text := warningText(v); text != "" {
+			n.Warnings = append(n.Warnings, text)
+		}
+	}
+	if !n.Deprecated && n.Sunset.IsZero() && len(n.Warnings) == 0 {
+		return DeprecationNotice{}, false
+	}
+	n.Link = deprecationLink(h.Values("Link"))
+	return n, true
+}
+
+// parseDeprecationDate accepts the RFC 9745 form ("@1688169599") and the
+// HTTP-date form used by earlier drafts. "true" and unknown values yield
+// the zero time.
+func parseDeprecationDate(v string) time.Time {
+	if strings.HasPrefix(v, "@") {
+		if sec, err := strconv.ParseInt(v[1:], 10, 64); err == nil {
+			return time.Unix(sec, 0).UTC()
+		}
+		return time.Time{}
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// warningValue matches `299 - "text"` with an optional trailing date.
+var warningValue = regexp.MustCompile(`^\d{3}\s+\S+\s+"((?:[^"\\]|\\.)*)"`)
+
+// warningText returns the quoted text of a Warning header value, or the
+// whole value if it is not in the RFC 7234 format.
+func warningText(v string) string {
+	v = strings.TrimSpace(v)
+	if m := warningValue.FindStringSubmatch(v); m != nil {
+		return strings.ReplaceAll(m[1], `\"`, `"`)
+	}
+	return v
+}
+
+// linkValue matches one `<url>; params` element of a Link header.
+var linkValue = regexp.MustCompile(`<([^>]*)>\s*;([^,]*)`)
+
+// deprecationLink returns the first Link URL with rel="deprecation" or
+// rel="sunset".
+func deprecationLink(values []string) string {
+	for _, v := range values {
+		for _, m := range linkValue.FindAllStringSubmatch(v, -1) {
+			params := strings.ToLower(m[2])
+			if strings.Contains(params, `rel="deprecation"`) || strings.Contains(params, "rel=deprecation") ||
+				strings.Contains(params, `rel="sunset"`) || strings.Contains(params, "rel=sunset") {
+				return m[1]
+			}
+		}
+	}
+	return ""
+}
+
+// versionSegment matches API version path segments such as "v2".
+var versionSegment = regexp.MustCompile(`^v\d+$`)
+
+// endpointTemplate replaces path segments that look like IDs (any segment
+// containing a digit, other than version segments) with {id}, so notices
+// are tracked per endpoint rather than per resource.
+func endpointTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if versionSegment.MatchString(s) {
+			continue
+		}
+		if strings.ContainsAny(s, "0123456789") {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
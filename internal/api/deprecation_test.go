@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseDeprecation(t *testing.T) {
+	h := http.Header{}
+	h.Set("Deprecation", "@1767225600")
+	h.Set("Sunset", "Wed, 30 Dec 2026 00:00:00 GMT")
+	h.Add("Warning", `299 - "Use /v3/bot/info instead"`)
+	h.Add("Link", `<https://example.com/docs>; rel="alternate", <https://example.com/sunset>; rel="sunset"`)
+
+	n, ok := ParseDeprecation(http.MethodGet, "/v2/bot/richmenu/richmenu-8dfd/content", h)
+	if !ok {
+		t.Fatal("expected notice")
+	}
+	if n.Endpoint != "GET /v2/bot/richmenu/{id}/content" {
+		t.Errorf("Endpoint = %q", n.Endpoint)
+	}
+	if !n.Deprecated || !n.Since.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Deprecated = %v, Since = %v", n.Deprecated, n.Since)
+	}
+	if !n.Sunset.Equal(time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Sunset = %v", n.Sunset)
+	}
+	if len(n.Warnings) != 1 || n.Warnings[0] != "Use /v3/bot/info instead" {
+		t.Errorf("Warnings = %v", n.Warnings)
+	}
+	if n.Link != "https://example.com/sunset" {
+		t.Errorf("Link = %q", n.Link)
+	}
+}
+
+func TestParseDeprecation_Variants(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		wantOK     bool
+		wantDepr   bool
+		wantSince  time.Time
+		wantWarned string
+	}{
+		{"none", "X-Other", "1", false, false, time.Time{}, ""},
+		{"boolean", "Deprecation", "true", true, true, time.Time{}, ""},
+		{"http date", "Deprecation", "Sun, 01 Mar 2026 00:00:00 GMT", true, true, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"free-form warning", "Warning", "endpoint will be removed", true, false, time.Time{}, "endpoint will be removed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set(tt.header, tt.value)
+			n, ok := ParseDeprecation(http.MethodPost, "/v2/bot/message/push", h)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if n.Deprecated != tt.wantDepr || !n.Since.Equal(tt.wantSince) {
+				t.Errorf("Deprecated = %v, Since = %v", n.Deprecated, n.Since)
+			}
+			if tt.wantWarned != "" && (len(n.Warnings) != 1 || n.Warnings[0] != tt.wantWarned) {
+				t.Errorf("Warnings = %v", n.Warnings)
+			}
+		})
+	}
+}
+
+func TestEndpointTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/v2/bot/info":                             "/v2/bot/info",
+		"/v2/bot/audienceGroup/12345":              "/v2/bot/audienceGroup/{id}",
+		"/v2/bot/profile/U4af4980629a0b1c2d3e4f5a": "/v2/bot/profile/{id}",
+		"/v2/bot/message/quota/consumption":        "/v2/bot/message/quota/consumption",
+	}
+	for path, want := range tests {
+		if got := endpointTemplate(path); got != want {
+			t.Errorf("endpointTemplate(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
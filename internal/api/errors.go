@@ -16,6 +16,12 @@ type APIError struct {
 	Details    []ErrorDetail
 	Hint       string
 	RawBody    string
+	// RequestID is the X-Line-Request-Id of the failed request, for LINE
+	// support
+	RequestID string
+	// RetriedRequestIDs are the request IDs of earlier attempts that failed
+	// and were retried, oldest first
+	RetriedRequestIDs []string
 }
 
 // ErrorDetail represents a specific validation error detail from the LINE API.
@@ -40,6 +46,14 @@ func (e *APIError) Error() string {
 	// Endpoint
 	sb.WriteString(fmt.Sprintf("Endpoint: %s %s\n", e.Method, e.Endpoint))
 
+	// Request IDs, for LINE support
+	if len(e.RetriedRequestIDs) > 0 {
+		ids := append(append([]string{}, e.RetriedRequestIDs...), e.RequestID)
+		sb.WriteString(fmt.Sprintf("Attempts: %d (request IDs: %s)\n", len(ids), strings.Join(ids, ", ")))
+	} else if e.RequestID != "" {
+		sb.WriteString(fmt.Sprintf("Request ID: %s\n", e.RequestID))
+	}
+
 	// Message
 	if e.Message != "" {
 		sb.WriteString(fmt.Sprintf("Message: %s\n", e.Message))
@@ -93,6 +107,14 @@ func ParseAPIError(statusCode int, method, endpoint string, body []byte) *APIErr
 	return apiErr
 }
 
+// responseError is ParseAPIError for a response, recording its request ID.
+func responseError(resp *http.Response, method, endpoint string, body []byte) *APIError {
+	apiErr := ParseAPIError(resp.StatusCode, method, endpoint, body)
+	apiErr.RequestID = resp.Header.Get("X-Line-Request-Id")
+	apiErr.RetriedRequestIDs = resp.Header[retryHistoryHeader]
+	return apiErr
+}
+
 // getHintForStatusCode returns actionable hints for common HTTP status codes.
 func getHintForStatusCode(statusCode int) string {
 	switch statusCode {
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DataBaseURL is the host for binary content endpoints (uploads and downloads).
+const DataBaseURL = "https://api-data.line.me"
+
+// FailoverOptions configures SetFailover.
+type FailoverOptions struct {
+	// Secondaries maps a primary base URL (BaseURL, DataBaseURL) to the base
+	// URL tried when the primary fails.
+	Secondaries map[string]string
+	// Threshold is the number of consecutive failures that opens a primary
+	// host's circuit (default 3).
+	Threshold int
+	// Cooldown is how long an open circuit sends requests straight to the
+	// secondary before the primary is tried again (default 30s).
+	Cooldown time.Duration
+}
+
+// SetFailover retries failed requests against secondary hosts, with a
+// circuit breaker per primary host.
+//
+// A primary attempt fails on a network error or a 500/502/503/504 response.
+// GET and HEAD requests are always retried on the secondary. Other methods
+// are retried only if the request never reached the primary (connection
+// refused, DNS failure) or carries an X-Line-Retry-Key header, so a send the
+// primary may have processed is never repeated. While a circuit is open,
+// every request goes straight to the secondary.
+func (c *Client) SetFailover(opts FailoverOptions) error {
+	routes := make(map[string]*url.URL)
+	for primary, secondary := range opts.Secondaries {
+		if secondary == "" {
+			continue
+		}
+		p, err := url.Parse(primary)
+		if err != nil || p.Host == "" {
+			return fmt.Errorf("invalid primary base URL %q", primary)
+		}
+		s, err := url.Parse(strings.TrimSuffix(secondary, "/"))
+		if err != nil || s.Host == "" || (s.Scheme != "http" && s.Scheme != "https") {
+			return fmt.Errorf("invalid failover base URL %q", secondary)
+		}
+		routes[p.Host] = s
+	}
+	if len(routes) == 0 {
+		return nil
+	}
+
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = 3
+	}
+	cooldown := opts.Cooldown
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = &failoverTransport{
+		next:      next,
+		routes:    routes,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       func() time.Time { return c.now() },
+		log:       c.log,
+		circuits:  make(map[string]*circuit),
+	}
+	return nil
+}
+
+// circuit tracks consecutive failures of one primary host.
+type circuit struct {
+	failures  int
+	openUntil time.Time
+}
+
+// failoverTransport is an http.RoundTripper that retries requests for
+// routed hosts against their secondary base URL.
+type failoverTransport struct {
+	next      http.RoundTripper
+	routes    map[string]*url.URL
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	log       func(level slog.Level, msg string, args ...any)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	secondary, ok := t.routes[req.URL.Host]
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	host := req.URL.Host
+
+	if t.allow(host) {
+		resp, err := t.next.RoundTrip(req)
+		if !isHostFailure(resp, err) {
+			t.record(host, true)
+			return resp, err
+		}
+		t.record(host, false)
+		if !canRetryElsewhere(req, err) || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			t.log(slog.LevelWarn, "API host failed; retrying on the secondary", "host", host, "status", resp.StatusCode, "secondary", secondary.Host)
+		} else {
+			t.log(slog.LevelWarn, "API host failed; retrying on the secondary", "host", host, "error", err, "secondary", secondary.Host)
+		}
+	} else {
+		t.log(slog.LevelInfo, "circuit open; using the secondary", "host", host, "secondary", secondary.Host)
+	}
+
+	retry, err := rerouteRequest(req, secondary)
+	if err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(retry)
+}
+
+// allow reports whether the primary host should be tried. After the
+// cooldown a single attempt is let through; another failure reopens it.
+func (t *failoverTransport) allow(host string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.circuits[host]
+	return c == nil || !t.now().Before(c.openUntil)
+}
+
+// record updates the host's circuit with the outcome of an attempt.
+func (t *failoverTransport) record(host string, success bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.circuits[host]
+	if c == nil {
+		c = &circuit{}
+		t.circuits[host] = c
+	}
+	if success {
+		c.failures = 0
+		c.openUntil = time.Time{}
+		return
+	}
+	c.failures++
+	if c.failures >= t.threshold {
+		c.openUntil = t.now().Add(t.cooldown)
+	}
+}
+
+// isHostFailure reports whether an attempt failed in a way that suggests
+// the host itself, rather than the request, is the problem.
+func isHostFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// canRetryElsewhere reports whether a failed request is safe to repeat on
+// another host.
+func canRetryElsewhere(req *http.Request, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+	if req.Header.Get("X-Line-Retry-Key") != "" {
+		return true
+	}
+	// A failed dial means the request was never sent
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// rerouteRequest copies req with its URL pointed at base.
+func rerouteRequest(req *http.Request, base *url.URL) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	retry.URL.Scheme = base.Scheme
+	retry.URL.Host = base.Host
+	retry.URL.Path = base.Path + req.URL.Path
+	if req.URL.RawPath != "" {
+		retry.URL.RawPath = base.Path + req.URL.RawPath
+	}
+	retry.Host = ""
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay request body: %w", err)
+		}
+		retry.Body = body
+	}
+	return retry, nil
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFailoverTestServers returns a primary that answers with primaryStatus
+// and a secondary that echoes the request body.
+func newFailoverTestServers(t *testing.T, primaryStatus int) (primary, secondary *httptest.Server, primaryHits, secondaryHits *atomic.Int32) {
+	t.Helper()
+	primaryHits, secondaryHits = new(atomic.Int32), new(atomic.Int32)
+	primary = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		w.WriteHeader(primaryStatus)
+		_, _ = w.Write([]byte(`{"message":"primary"}`))
+	}))
+	secondary = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryHits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if len(body) == 0 {
+			body = []byte(`{"from":"secondary"}`)
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(primary.Close)
+	t.Cleanup(secondary.Close)
+	return primary, secondary, primaryHits, secondaryHits
+}
+
+func TestFailover_GetRetriedOnSecondary(t *testing.T) {
+	primary, secondary, primaryHits, secondaryHits := newFailoverTestServers(t, http.StatusServiceUnavailable)
+
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL(primary.URL)
+	if err := client.SetFailover(FailoverOptions{Secondaries: map[string]string{primary.URL: secondary.URL}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := client.Get(context.Background(), "/v2/bot/info")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"from":"secondary"}` {
+		t.Errorf("expected secondary response, got %s", data)
+	}
+	if primaryHits.Load() != 1 || secondaryHits.Load() != 1 {
+		t.Errorf("expected 1 hit each, got primary=%d secondary=%d", primaryHits.Load(), secondaryHits.Load())
+	}
+}
+
+func TestFailover_PostNotRepeatedAfterServerError(t *testing.T) {
+	primary, secondary, _, secondaryHits := newFailoverTestServers(t, http.StatusInternalServerError)
+
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL(primary.URL)
+	if err := client.SetFailover(FailoverOptions{Secondaries: map[string]string{primary.URL: secondary.URL}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := client.Post(context.Background(), "/v2/bot/message/reply", map[string]string{"replyToken": "r1"})
+	if err == nil {
+		t.Fatal("expected error from primary")
+	}
+	if secondaryHits.Load() != 0 {
+		t.Error("a send the primary may have processed must not be repeated")
+	}
+}
+
+func TestFailover_PostRetriedWhenPrimaryUnreachable(t *testing.T) {
+	primary, secondary, _, secondaryHits := newFailoverTestServers(t, http.StatusOK)
+	primaryURL := primary.URL
+	primary.Close()
+
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL(primaryURL)
+	if err := client.SetFailover(FailoverOptions{Secondaries: map[string]string{primaryURL: secondary.URL}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := client.Post(context.Background(), "/v2/bot/message/push", map[string]string{"to": "U1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secondaryHits.Load() != 1 || !strings.Contains(string(data), `"to":"U1"`) {
+		t.Errorf("expected body replayed on secondary, got %s", data)
+	}
+}
+
+func TestFailover_CircuitBreaker(t *testing.T) {
+	primary, secondary, primaryHits, _ := newFailoverTestServers(t, http.StatusBadGateway)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL(primary.URL)
+	client.SetClock(func() time.Time { return now })
+	err := client.SetFailover(FailoverOptions{
+		Secondaries: map[string]string{primary.URL: secondary.URL},
+		Threshold:   2,
+		Cooldown:    time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.Get(context.Background(), "/v2/bot/info"); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+	}
+	if got := primaryHits.Load(); got != 2 {
+		t.Errorf("expected circuit to open after 2 failures, primary hit %d times", got)
+	}
+
+	// After the cooldown the primary gets one more try
+	now = now.Add(2 * time.Minute)
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := primaryHits.Load(); got != 3 {
+		t.Errorf("expected primary retried after cooldown, hit %d times", got)
+	}
+}
+
+func TestFailover_InvalidSecondary(t *testing.T) {
+	client := NewClient("test-token", false, false)
+	err := client.SetFailover(FailoverOptions{Secondaries: map[string]string{BaseURL: "not a url"}})
+	if err == nil {
+		t.Error("expected error for invalid secondary URL")
+	}
+}
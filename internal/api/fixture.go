@@ -0,0 +1,293 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Fixture is a recorded request and the response LINE sent to it, stored
+// as one JSON file by SetRecord and served back by SetReplay.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest is the recorded part of a request. Credentials are never
+// recorded.
+type FixtureRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	FixtureBody
+}
+
+// FixtureResponse is a recorded response.
+type FixtureResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	FixtureBody
+}
+
+// FixtureBody holds a body as JSON when it is JSON, so fixtures can be read
+// and edited, and base64-encoded otherwise.
+type FixtureBody struct {
+	Body       json.RawMessage `json:"body,omitempty"`
+	BodyBase64 string          `json:"bodyBase64,omitempty"`
+}
+
+func newFixtureBody(data []byte) FixtureBody {
+	if len(data) == 0 {
+		return FixtureBody{}
+	}
+	if json.Valid(data) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err == nil {
+			return FixtureBody{Body: buf.Bytes()}
+		}
+	}
+	return FixtureBody{BodyBase64: base64.StdEncoding.EncodeToString(data)}
+}
+
+// bytes returns the body as sent, undoing the indentation JSON bodies get
+// in fixture files.
+func (b FixtureBody) bytes() ([]byte, error) {
+	if b.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(b.BodyBase64)
+	}
+	if len(b.Body) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b.Body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unrecordedHeaders are left out of fixtures: credentials, and values that
+// differ on every run.
+var unrecordedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Line-Retry-Key", "Date"}
+
+func sanitizeHeaders(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range unrecordedHeaders {
+		h.Del(name)
+	}
+	if len(h) == 0 {
+		return nil
+	}
+	return h
+}
+
+// SetRecord saves every request the client sends, with its response, as a
+// fixture file in dir for SetReplay. Authorization headers are not saved,
+// but bodies are, so fixtures contain user IDs and message text.
+func (c *Client) SetRecord(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create fixtures directory: %w", err)
+	}
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = &fixtureRecorder{next: next, fixtures: newFixtureDir(dir)}
+	return nil
+}
+
+// SetReplay answers every request from the fixtures SetRecord saved in dir,
+// without network access. A request with no fixture fails. Identical
+// requests get their recorded responses in order; once those run out, the
+// last one is repeated, so polling loops still finish.
+func (c *Client) SetReplay(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open fixtures directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("fixtures path %s is not a directory", dir)
+	}
+	c.httpClient.Transport = &fixtureReplayer{fixtures: newFixtureDir(dir)}
+	return nil
+}
+
+// fixtureDir names fixture files. A file is named after its request's
+// method and path, a hash of everything that identifies the request, and
+// how many identical requests came before it.
+type fixtureDir struct {
+	dir  string
+	mu   sync.Mutex
+	seen map[string]int
+}
+
+func newFixtureDir(dir string) *fixtureDir {
+	return &fixtureDir{dir: dir, seen: map[string]int{}}
+}
+
+var fixtureNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// next returns the key of req and which occurrence of it this is, from 1.
+func (d *fixtureDir) next(req *http.Request, body []byte) (string, int) {
+	sum := sha256.New()
+	_, _ = io.WriteString(sum, req.Method+" "+req.URL.RequestURI()+"\n")
+	_, _ = sum.Write(normalizeMultipart(req.Header.Get("Content-Type"), body))
+
+	slug := strings.Trim(fixtureNameUnsafe.ReplaceAllString(req.URL.Path, "_"), "_")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	key := fmt.Sprintf("%s_%s_%s", req.Method, slug, hex.EncodeToString(sum.Sum(nil))[:12])
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seen[key]++
+	return key, d.seen[key]
+}
+
+func (d *fixtureDir) path(key string, n int) string {
+	return filepath.Join(d.dir, fmt.Sprintf("%s_%d.json", key, n))
+}
+
+// normalizeMultipart replaces the random boundary of a multipart body, so
+// identical uploads hash the same on every run.
+func normalizeMultipart(contentType string, body []byte) []byte {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return body
+	}
+	return bytes.ReplaceAll(body, []byte(params["boundary"]), []byte("BOUNDARY"))
+}
+
+// readRequestBody returns a copy of the body of req, leaving req unchanged.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		return data, err
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+	return io.ReadAll(body)
+}
+
+// fixtureRecorder is an http.RoundTripper that saves requests and
+// responses as fixtures.
+type fixtureRecorder struct {
+	next     http.RoundTripper
+	fixtures *fixtureDir
+}
+
+func (r *fixtureRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method:      req.Method,
+			URL:         req.URL.RequestURI(),
+			Headers:     sanitizeHeaders(req.Header),
+			FixtureBody: newFixtureBody(reqBody),
+		},
+		Response: FixtureResponse{
+			StatusCode:  resp.StatusCode,
+			Headers:     sanitizeHeaders(resp.Header),
+			FixtureBody: newFixtureBody(respBody),
+		},
+	}
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	key, n := r.fixtures.next(req, reqBody)
+	if err := os.WriteFile(r.fixtures.path(key, n), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return resp, nil
+}
+
+// fixtureReplayer is an http.RoundTripper that answers requests from
+// fixtures.
+type fixtureReplayer struct {
+	fixtures *fixtureDir
+}
+
+func (r *fixtureReplayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	key, n := r.fixtures.next(req, reqBody)
+
+	var data []byte
+	for ; n > 0; n-- {
+		data, err = os.ReadFile(r.fixtures.path(key, n))
+		if !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s in %s", req.Method, req.URL.RequestURI(), r.fixtures.dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", r.fixtures.path(key, n), err)
+	}
+	body, err := fixture.Response.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", r.fixtures.path(key, n), err)
+	}
+	header := fixture.Response.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		StatusCode:    fixture.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("X-Line-Request-Id", "req-1")
+		switch r.URL.Path {
+		case "/v2/bot/info":
+			_, _ = w.Write([]byte(`{"userId":"Ubot","displayName":"Bot"}`))
+		case "/v1/audienceGroup/42":
+			// The status changes between polls
+			if n < 4 {
+				_, _ = w.Write([]byte(`{"status":"IN_PROGRESS"}`))
+			} else {
+				_, _ = w.Write([]byte(`{"status":"READY"}`))
+			}
+		case "/v2/bot/message/push":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"The request body has 1 error(s)"}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	recorder := NewClient("secret-token", false, false)
+	recorder.SetBaseURL(server.URL)
+	if err := recorder.SetRecord(dir); err != nil {
+		t.Fatalf("SetRecord: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := recorder.Get(ctx, "/v2/bot/info"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := recorder.Get(ctx, "/v1/audienceGroup/42"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := recorder.Post(ctx, "/v2/bot/message/push", map[string]string{"to": "U1"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 fixtures, got %d", len(entries))
+	}
+	for _, e := range entries {
+		data, _ := os.ReadFile(filepath.Join(dir, e.Name()))
+		if strings.Contains(string(data), "secret-token") {
+			t.Errorf("fixture %s contains the token:\n%s", e.Name(), data)
+		}
+	}
+
+	server.Close()
+	replayer := NewClient("", false, false)
+	replayer.SetBaseURL("https://unreachable.invalid")
+	if err := replayer.SetReplay(dir); err != nil {
+		t.Fatalf("SetReplay: %v", err)
+	}
+
+	body, err := replayer.Get(ctx, "/v2/bot/info")
+	if err != nil || string(body) != `{"userId":"Ubot","displayName":"Bot"}` {
+		t.Errorf("replayed bot info = %s, %v", body, err)
+	}
+	want := []string{"IN_PROGRESS", "IN_PROGRESS", "READY", "READY"}
+	for i, status := range want {
+		body, err := replayer.Get(ctx, "/v1/audienceGroup/42")
+		if err != nil || !strings.Contains(string(body), status) {
+			t.Errorf("poll %d = %s, %v; want %s", i+1, body, err, status)
+		}
+	}
+
+	_, err = replayer.Post(ctx, "/v2/bot/message/push", map[string]string{"to": "U1"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusBadRequest || apiErr.RequestID != "req-1" {
+		t.Errorf("expected replayed 400 with request ID, got %v", err)
+	}
+
+	_, err = replayer.Post(ctx, "/v2/bot/message/push", map[string]string{"to": "U2"})
+	if err == nil || !strings.Contains(err.Error(), "no recorded response for POST /v2/bot/message/push") {
+		t.Errorf("expected missing fixture error, got %v", err)
+	}
+}
+
+func TestRecordReplay_Multipart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"audienceGroupId":7}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := NewClient("token", false, false)
+	recorder.SetBaseURL(server.URL)
+	if err := recorder.SetRecord(dir); err != nil {
+		t.Fatalf("SetRecord: %v", err)
+	}
+	ctx := context.Background()
+	fields := map[string]string{"description": "vip"}
+	if _, err := recorder.PostMultipart(ctx, "/v1/audienceGroup/upload/byFile", "file", "ids.txt", []byte("U1\nU2\n"), fields); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A new multipart writer picks a new boundary, which must not matter
+	replayer := NewClient("", false, false)
+	if err := replayer.SetReplay(dir); err != nil {
+		t.Fatalf("SetReplay: %v", err)
+	}
+	body, err := replayer.PostMultipart(ctx, "/v1/audienceGroup/upload/byFile", "file", "ids.txt", []byte("U1\nU2\n"), fields)
+	if err != nil || string(body) != `{"audienceGroupId":7}` {
+		t.Errorf("replayed upload = %s, %v", body, err)
+	}
+}
+
+func TestSetReplay_MissingDir(t *testing.T) {
+	client := NewClient("", false, false)
+	if err := client.SetReplay(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 )
 
 type MembershipPlan struct {
@@ -11,34 +13,48 @@ type MembershipPlan struct {
 	Title        string   `json:"title"`
 	Description  string   `json:"description"`
 	Benefits     []string `json:"benefits,omitempty"`
-	Price        int64    `json:"price"`
+	Price        float64  `json:"price"`
 	Currency     string   `json:"currency"`
-	IsPublished  bool     `json:"isPublished"`
-	IsInSale     bool     `json:"isInSale"`
+	MemberCount  int64    `json:"memberCount"`
+	// MemberLimit is nil when the plan has no subscriber cap
+	MemberLimit *int64 `json:"memberLimit"`
+	IsInApp     bool   `json:"isInApp"`
+	IsPublished bool   `json:"isPublished"`
 }
 
 type MembershipPlansResponse struct {
 	Memberships []MembershipPlan `json:"memberships"`
 }
 
-type UserMembershipStatus struct {
-	MembershipID      int64  `json:"membershipId"`
-	SubscriptionState string `json:"subscriptionState"`
-	StartTime         int64  `json:"startTime,omitempty"`
-	EndTime           int64  `json:"endTime,omitempty"`
+// MembershipSubscriber describes a user's standing in one membership.
+// Times are Unix seconds.
+type MembershipSubscriber struct {
+	MembershipNo            int64 `json:"membershipNo"`
+	JoinedTime              int64 `json:"joinedTime"`
+	LastJoinedTime          int64 `json:"lastJoinedTime"`
+	LastPaidTime            int64 `json:"lastPaidTime"`
+	TotalSubscriptionMonths int64 `json:"totalSubscriptionMonths"`
 }
 
-type UserMembershipResponse struct {
-	Memberships []UserMembershipStatus `json:"memberships"`
+// MembershipSubscription is one membership a user is subscribed to.
+type MembershipSubscription struct {
+	Membership MembershipPlan       `json:"membership"`
+	User       MembershipSubscriber `json:"user"`
+}
+
+type MembershipSubscriptionResponse struct {
+	Subscriptions []MembershipSubscription `json:"subscriptions"`
 }
 
 type MembershipUsersResponse struct {
-	MemberIDs []string `json:"memberIds"`
-	Next      string   `json:"next,omitempty"`
+	UserIDs []string `json:"userIds"`
+	Next    string   `json:"next,omitempty"`
 }
 
+// GetMembershipPlans returns the membership plans offered by the account.
+// GET /v2/bot/membership/list
 func (c *Client) GetMembershipPlans(ctx context.Context) ([]MembershipPlan, error) {
-	data, err := c.Get(ctx, "/v2/bot/membership/plans")
+	data, err := c.Get(ctx, "/v2/bot/membership/list")
 	if err != nil {
 		return nil, err
 	}
@@ -49,24 +65,36 @@ func (c *Client) GetMembershipPlans(ctx context.Context) ([]MembershipPlan, erro
 	return resp.Memberships, nil
 }
 
-func (c *Client) GetUserMembershipStatus(ctx context.Context, userID string) ([]UserMembershipStatus, error) {
-	path := fmt.Sprintf("/v2/bot/users/%s/membership/subscription", userID)
-	data, err := c.Get(ctx, path)
+// GetMembershipSubscriptions returns the memberships a user is subscribed to.
+// GET /v2/bot/membership/subscription/{userId}
+func (c *Client) GetMembershipSubscriptions(ctx context.Context, userID string) ([]MembershipSubscription, error) {
+	data, err := c.Get(ctx, "/v2/bot/membership/subscription/"+url.PathEscape(userID))
 	if err != nil {
 		return nil, err
 	}
-	var resp UserMembershipResponse
+	var resp MembershipSubscriptionResponse
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
-	return resp.Memberships, nil
+	return resp.Subscriptions, nil
 }
 
-func (c *Client) GetMembershipUsers(ctx context.Context, start string) (*MembershipUsersResponse, error) {
-	path := "/v2/bot/membership/users"
+// GetMembershipUsers returns one page of the user IDs subscribed to a
+// membership. limit may be 0 for the API default (max 1000).
+// GET /v2/bot/membership/{membershipId}/users/ids?start={start}&limit={limit}
+func (c *Client) GetMembershipUsers(ctx context.Context, membershipID int64, start string, limit int) (*MembershipUsersResponse, error) {
+	params := url.Values{}
 	if start != "" {
-		path += "?start=" + start
+		params.Set("start", start)
 	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	path := fmt.Sprintf("/v2/bot/membership/%d/users/ids", membershipID)
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
 	data, err := c.Get(ctx, path)
 	if err != nil {
 		return nil, err
@@ -9,7 +9,7 @@ import (
 
 func TestClient_GetMembershipPlans(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/v2/bot/membership/plans" {
+		if r.URL.Path != "/v2/bot/membership/list" {
 			t.Errorf("unexpected path: %s", r.URL.Path)
 		}
 		if r.Method != http.MethodGet {
@@ -17,7 +17,7 @@ func TestClient_GetMembershipPlans(t *testing.T) {
 		}
 
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"memberships":[{"membershipId":12345,"title":"Premium Plan","description":"Full access","benefits":["Exclusive content","Early access"],"price":500,"currency":"JPY","isPublished":true,"isInSale":true},{"membershipId":67890,"title":"Basic Plan","description":"Limited access","price":100,"currency":"JPY","isPublished":true,"isInSale":false}]}`))
+		_, _ = w.Write([]byte(`{"memberships":[{"membershipId":12345,"title":"Premium Plan","description":"Full access","benefits":["Exclusive content","Early access"],"price":500,"currency":"JPY","memberCount":12,"memberLimit":100,"isInApp":true,"isPublished":true},{"membershipId":67890,"title":"Basic Plan","description":"Limited access","price":100.5,"currency":"JPY","memberCount":3,"memberLimit":null,"isInApp":false,"isPublished":false}]}`))
 	}))
 	defer server.Close()
 
@@ -46,24 +46,30 @@ func TestClient_GetMembershipPlans(t *testing.T) {
 		t.Errorf("expected 2 benefits, got %d", len(plans[0].Benefits))
 	}
 	if plans[0].Price != 500 {
-		t.Errorf("expected price 500, got %d", plans[0].Price)
+		t.Errorf("expected price 500, got %v", plans[0].Price)
 	}
 	if plans[0].Currency != "JPY" {
 		t.Errorf("expected currency 'JPY', got %s", plans[0].Currency)
 	}
-	if !plans[0].IsPublished {
-		t.Error("expected isPublished to be true")
+	if plans[0].MemberCount != 12 || plans[0].MemberLimit == nil || *plans[0].MemberLimit != 100 {
+		t.Errorf("expected 12/100 members, got %d/%v", plans[0].MemberCount, plans[0].MemberLimit)
 	}
-	if !plans[0].IsInSale {
-		t.Error("expected isInSale to be true")
+	if !plans[0].IsPublished || !plans[0].IsInApp {
+		t.Error("expected isPublished and isInApp to be true")
 	}
 
 	// Verify second plan
 	if plans[1].MembershipID != 67890 {
 		t.Errorf("expected membershipId 67890, got %d", plans[1].MembershipID)
 	}
-	if plans[1].IsInSale {
-		t.Error("expected isInSale to be false")
+	if plans[1].Price != 100.5 {
+		t.Errorf("expected price 100.5, got %v", plans[1].Price)
+	}
+	if plans[1].MemberLimit != nil {
+		t.Errorf("expected no member limit, got %d", *plans[1].MemberLimit)
+	}
+	if plans[1].IsPublished {
+		t.Error("expected isPublished to be false")
 	}
 }
 
@@ -102,9 +108,9 @@ func TestClient_GetMembershipPlans_Error(t *testing.T) {
 	}
 }
 
-func TestClient_GetUserMembershipStatus(t *testing.T) {
+func TestClient_GetMembershipSubscriptions(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/v2/bot/users/U1234567890abcdef/membership/subscription" {
+		if r.URL.Path != "/v2/bot/membership/subscription/U1234567890abcdef" {
 			t.Errorf("unexpected path: %s", r.URL.Path)
 		}
 		if r.Method != http.MethodGet {
@@ -112,54 +118,58 @@ func TestClient_GetUserMembershipStatus(t *testing.T) {
 		}
 
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"memberships":[{"membershipId":12345,"subscriptionState":"ACTIVE","startTime":1609459200,"endTime":1640995200}]}`))
+		_, _ = w.Write([]byte(`{"subscriptions":[{"membership":{"membershipId":12345,"title":"Premium Plan","price":500,"currency":"JPY"},"user":{"membershipNo":7,"joinedTime":1609459200,"lastJoinedTime":1609459200,"lastPaidTime":1640995200,"totalSubscriptionMonths":12}}]}`))
 	}))
 	defer server.Close()
 
 	client := NewClient("test-token", false, false)
 	client.baseURL = server.URL
 
-	statuses, err := client.GetUserMembershipStatus(context.Background(), "U1234567890abcdef")
+	subscriptions, err := client.GetMembershipSubscriptions(context.Background(), "U1234567890abcdef")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(statuses) != 1 {
-		t.Fatalf("expected 1 status, got %d", len(statuses))
+	if len(subscriptions) != 1 {
+		t.Fatalf("expected 1 subscription, got %d", len(subscriptions))
+	}
+	s := subscriptions[0]
+	if s.Membership.MembershipID != 12345 {
+		t.Errorf("expected membershipId 12345, got %d", s.Membership.MembershipID)
 	}
-	if statuses[0].MembershipID != 12345 {
-		t.Errorf("expected membershipId 12345, got %d", statuses[0].MembershipID)
+	if s.User.MembershipNo != 7 {
+		t.Errorf("expected membershipNo 7, got %d", s.User.MembershipNo)
 	}
-	if statuses[0].SubscriptionState != "ACTIVE" {
-		t.Errorf("expected subscriptionState 'ACTIVE', got %s", statuses[0].SubscriptionState)
+	if s.User.JoinedTime != 1609459200 {
+		t.Errorf("expected joinedTime 1609459200, got %d", s.User.JoinedTime)
 	}
-	if statuses[0].StartTime != 1609459200 {
-		t.Errorf("expected startTime 1609459200, got %d", statuses[0].StartTime)
+	if s.User.LastPaidTime != 1640995200 {
+		t.Errorf("expected lastPaidTime 1640995200, got %d", s.User.LastPaidTime)
 	}
-	if statuses[0].EndTime != 1640995200 {
-		t.Errorf("expected endTime 1640995200, got %d", statuses[0].EndTime)
+	if s.User.TotalSubscriptionMonths != 12 {
+		t.Errorf("expected 12 months, got %d", s.User.TotalSubscriptionMonths)
 	}
 }
 
-func TestClient_GetUserMembershipStatus_NoMembership(t *testing.T) {
+func TestClient_GetMembershipSubscriptions_NoMembership(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"memberships":[]}`))
+		_, _ = w.Write([]byte(`{"subscriptions":[]}`))
 	}))
 	defer server.Close()
 
 	client := NewClient("test-token", false, false)
 	client.baseURL = server.URL
 
-	statuses, err := client.GetUserMembershipStatus(context.Background(), "U1234567890abcdef")
+	subscriptions, err := client.GetMembershipSubscriptions(context.Background(), "U1234567890abcdef")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(statuses) != 0 {
-		t.Errorf("expected 0 statuses, got %d", len(statuses))
+	if len(subscriptions) != 0 {
+		t.Errorf("expected 0 subscriptions, got %d", len(subscriptions))
 	}
 }
 
-func TestClient_GetUserMembershipStatus_NotFound(t *testing.T) {
+func TestClient_GetMembershipSubscriptions_NotFound(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		_, _ = w.Write([]byte(`{"message":"User not found"}`))
@@ -169,7 +179,7 @@ func TestClient_GetUserMembershipStatus_NotFound(t *testing.T) {
 	client := NewClient("test-token", false, false)
 	client.baseURL = server.URL
 
-	_, err := client.GetUserMembershipStatus(context.Background(), "Uinvalid")
+	_, err := client.GetMembershipSubscriptions(context.Background(), "Uinvalid")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -179,17 +189,18 @@ func TestClient_GetMembershipUsers(t *testing.T) {
 	tests := []struct {
 		name         string
 		start        string
+		limit        int
 		expectedPath string
 	}{
 		{
 			name:         "without start",
-			start:        "",
-			expectedPath: "/v2/bot/membership/users",
+			expectedPath: "/v2/bot/membership/12345/users/ids",
 		},
 		{
-			name:         "with start",
+			name:         "with start and limit",
 			start:        "cursor123",
-			expectedPath: "/v2/bot/membership/users?start=cursor123",
+			limit:        500,
+			expectedPath: "/v2/bot/membership/12345/users/ids?limit=500&start=cursor123",
 		},
 	}
 
@@ -208,28 +219,25 @@ func TestClient_GetMembershipUsers(t *testing.T) {
 				}
 
 				w.WriteHeader(http.StatusOK)
-				_, _ = w.Write([]byte(`{"memberIds":["U123","U456","U789"],"next":"cursor456"}`))
+				_, _ = w.Write([]byte(`{"userIds":["U123","U456","U789"],"next":"cursor456"}`))
 			}))
 			defer server.Close()
 
 			client := NewClient("test-token", false, false)
 			client.baseURL = server.URL
 
-			resp, err := client.GetMembershipUsers(context.Background(), tt.start)
+			resp, err := client.GetMembershipUsers(context.Background(), 12345, tt.start, tt.limit)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if len(resp.MemberIDs) != 3 {
-				t.Errorf("expected 3 member IDs, got %d", len(resp.MemberIDs))
-			}
-			if resp.MemberIDs[0] != "U123" {
-				t.Errorf("expected first member ID 'U123', got %s", resp.MemberIDs[0])
+			if len(resp.UserIDs) != 3 {
+				t.Errorf("expected 3 user IDs, got %d", len(resp.UserIDs))
 			}
-			if resp.MemberIDs[1] != "U456" {
-				t.Errorf("expected second member ID 'U456', got %s", resp.MemberIDs[1])
+			if resp.UserIDs[0] != "U123" {
+				t.Errorf("expected first user ID 'U123', got %s", resp.UserIDs[0])
 			}
-			if resp.MemberIDs[2] != "U789" {
-				t.Errorf("expected third member ID 'U789', got %s", resp.MemberIDs[2])
+			if resp.UserIDs[2] != "U789" {
+				t.Errorf("expected third user ID 'U789', got %s", resp.UserIDs[2])
 			}
 			if resp.Next != "cursor456" {
 				t.Errorf("expected next 'cursor456', got %s", resp.Next)
@@ -241,19 +249,19 @@ func TestClient_GetMembershipUsers(t *testing.T) {
 func TestClient_GetMembershipUsers_Empty(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"memberIds":[]}`))
+		_, _ = w.Write([]byte(`{"userIds":[]}`))
 	}))
 	defer server.Close()
 
 	client := NewClient("test-token", false, false)
 	client.baseURL = server.URL
 
-	resp, err := client.GetMembershipUsers(context.Background(), "")
+	resp, err := client.GetMembershipUsers(context.Background(), 12345, "", 0)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(resp.MemberIDs) != 0 {
-		t.Errorf("expected 0 member IDs, got %d", len(resp.MemberIDs))
+	if len(resp.UserIDs) != 0 {
+		t.Errorf("expected 0 user IDs, got %d", len(resp.UserIDs))
 	}
 	if resp.Next != "" {
 		t.Errorf("expected empty next, got %s", resp.Next)
@@ -270,7 +278,7 @@ func TestClient_GetMembershipUsers_Error(t *testing.T) {
 	client := NewClient("test-token", false, false)
 	client.baseURL = server.URL
 
-	_, err := client.GetMembershipUsers(context.Background(), "")
+	_, err := client.GetMembershipUsers(context.Background(), 12345, "", 0)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -292,7 +300,7 @@ func TestClient_GetMembershipPlans_InvalidJSON(t *testing.T) {
 	}
 }
 
-func TestClient_GetUserMembershipStatus_InvalidJSON(t *testing.T) {
+func TestClient_GetMembershipSubscriptions_InvalidJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(`{invalid json`))
@@ -302,7 +310,7 @@ func TestClient_GetUserMembershipStatus_InvalidJSON(t *testing.T) {
 	client := NewClient("test-token", false, false)
 	client.baseURL = server.URL
 
-	_, err := client.GetUserMembershipStatus(context.Background(), "U123")
+	_, err := client.GetMembershipSubscriptions(context.Background(), "U123")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -318,7 +326,7 @@ func TestClient_GetMembershipUsers_InvalidJSON(t *testing.T) {
 	client := NewClient("test-token", false, false)
 	client.baseURL = server.URL
 
-	_, err := client.GetMembershipUsers(context.Background(), "")
+	_, err := client.GetMembershipUsers(context.Background(), 12345, "", 0)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -7,8 +7,17 @@ import (
 )
 
 type TextMessage struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type   string  `json:"type"`
+	Text   string  `json:"text"`
+	Emojis []Emoji `json:"emojis,omitempty"`
+}
+
+// Emoji is a LINE emoji in a text message, drawn in place of the "$" at
+// Index (counted in UTF-16 code units) of the text.
+type Emoji struct {
+	Index     int    `json:"index"`
+	ProductID string `json:"productId"`
+	EmojiID   string `json:"emojiId"`
 }
 
 type FlexMessage struct {
@@ -53,6 +62,9 @@ type LocationMessage struct {
 type PushMessageRequest struct {
 	To       string `json:"to"`
 	Messages []any  `json:"messages"`
+	// CustomAggregationUnits names the unit the message's insights are
+	// aggregated under (at most one)
+	CustomAggregationUnits []string `json:"customAggregationUnits,omitempty"`
 }
 
 type BroadcastMessageRequest struct {
@@ -60,8 +72,9 @@ type BroadcastMessageRequest struct {
 }
 
 type MulticastMessageRequest struct {
-	To       []string `json:"to"`
-	Messages []any    `json:"messages"`
+	To                     []string `json:"to"`
+	Messages               []any    `json:"messages"`
+	CustomAggregationUnits []string `json:"customAggregationUnits,omitempty"`
 }
 
 type ReplyMessageRequest struct {
@@ -91,6 +104,17 @@ type DeliveryStats struct {
 	RequestCount int64  `json:"requestCount,omitempty"`
 }
 
+// ReplyMessage replies to a webhook event with message, one of the message
+// types in this package.
+func (c *Client) ReplyMessage(ctx context.Context, replyToken string, message any) error {
+	req := ReplyMessageRequest{
+		ReplyToken: replyToken,
+		Messages:   []any{message},
+	}
+	_, err := c.Post(ctx, "/v2/bot/message/reply", req)
+	return err
+}
+
 func (c *Client) ReplyTextMessage(ctx context.Context, replyToken, text string) error {
 	req := ReplyMessageRequest{
 		ReplyToken: replyToken,
@@ -247,13 +271,22 @@ type NarrowcastFilter struct {
 	Demographic *DemographicFilter `json:"demographic,omitempty"`
 }
 
+// DemographicFilter is a narrowcast demographic condition. Leaf conditions
+// set Type (gender, age, appType, area, subscriptionPeriod) with OneOf or
+// Gte/Lt; operator conditions set Type "operator" with exactly one of And,
+// Or, or Not.
 type DemographicFilter struct {
-	Type      string   `json:"type,omitempty"`
-	OneOf     []any    `json:"oneOf,omitempty"`
-	Ages      []string `json:"ages,omitempty"`
-	Genders   []string `json:"genders,omitempty"`
-	AppTypes  []string `json:"appTypes,omitempty"`
-	AreaCodes []string `json:"areaCodes,omitempty"`
+	Type      string               `json:"type,omitempty"`
+	OneOf     []any                `json:"oneOf,omitempty"`
+	Gte       string               `json:"gte,omitempty"`
+	Lt        string               `json:"lt,omitempty"`
+	And       []*DemographicFilter `json:"and,omitempty"`
+	Or        []*DemographicFilter `json:"or,omitempty"`
+	Not       *DemographicFilter   `json:"not,omitempty"`
+	Ages      []string             `json:"ages,omitempty"`
+	Genders   []string             `json:"genders,omitempty"`
+	AppTypes  []string             `json:"appTypes,omitempty"`
+	AreaCodes []string             `json:"areaCodes,omitempty"`
 }
 
 type NarrowcastLimit struct {
@@ -275,6 +308,12 @@ func (c *Client) NarrowcastTextMessage(ctx context.Context, text string, audienc
 			AudienceGroupID: audienceGroupID,
 		}
 	}
+	return c.Narrowcast(ctx, req)
+}
+
+// Narrowcast sends a narrowcast request with any combination of recipient,
+// demographic filter, and limit.
+func (c *Client) Narrowcast(ctx context.Context, req NarrowcastMessageRequest) (*NarrowcastResponse, error) {
 	resp, err := c.PostWithHeaders(ctx, "/v2/bot/message/narrowcast", req)
 	if err != nil {
 		return nil, err
@@ -284,6 +323,21 @@ func (c *Client) NarrowcastTextMessage(ctx context.Context, text string, audienc
 	return &NarrowcastResponse{RequestID: requestID}, nil
 }
 
+// BroadcastResponse identifies a sent broadcast.
+type BroadcastResponse struct {
+	RequestID string `json:"requestId"`
+}
+
+// Broadcast sends messages to every follower and returns the request ID,
+// which insights about the broadcast are looked up by.
+func (c *Client) Broadcast(ctx context.Context, req BroadcastMessageRequest) (*BroadcastResponse, error) {
+	resp, err := c.PostWithHeaders(ctx, "/v2/bot/message/broadcast", req)
+	if err != nil {
+		return nil, err
+	}
+	return &BroadcastResponse{RequestID: resp.Headers.Get("X-Line-Request-Id")}, nil
+}
+
 func (c *Client) GetNarrowcastProgress(ctx context.Context, requestID string) (map[string]any, error) {
 	path := fmt.Sprintf("/v2/bot/message/progress/narrowcast?requestId=%s", requestID)
 	data, err := c.Get(ctx, path)
@@ -419,24 +473,42 @@ func (c *Client) MarkMessagesAsReadByToken(ctx context.Context, chatToken string
 // targetType must be "push", "broadcast", or "multicast".
 // For "push", userID must be set. For "multicast", userIDs must be set.
 func (c *Client) SendMessage(ctx context.Context, targetType string, userID string, userIDs []string, message any) error {
+	return c.SendMessages(ctx, targetType, userID, userIDs, []any{message})
+}
+
+// SendMessages is SendMessage for up to 5 messages sent together.
+func (c *Client) SendMessages(ctx context.Context, targetType string, userID string, userIDs []string, messages []any) error {
+	return c.SendMessagesInUnit(ctx, targetType, userID, userIDs, messages, "")
+}
+
+// SendMessagesInUnit is SendMessages with the messages' insights aggregated
+// under the custom aggregation unit unit. LINE supports units for push and
+// multicast only; unit must be empty for broadcast.
+func (c *Client) SendMessagesInUnit(ctx context.Context, targetType string, userID string, userIDs []string, messages []any, unit string) error {
+	var units []string
+	if unit != "" {
+		units = []string{unit}
+	}
 	switch targetType {
 	case "push":
 		req := PushMessageRequest{
-			To:       userID,
-			Messages: []any{message},
+			To:                     userID,
+			Messages:               messages,
+			CustomAggregationUnits: units,
 		}
 		_, err := c.Post(ctx, "/v2/bot/message/push", req)
 		return err
 	case "broadcast":
-		req := BroadcastMessageRequest{
-			Messages: []any{message},
+		if unit != "" {
+			return fmt.Errorf("aggregation units are not supported for broadcast messages")
 		}
-		_, err := c.Post(ctx, "/v2/bot/message/broadcast", req)
+		_, err := c.Broadcast(ctx, BroadcastMessageRequest{Messages: messages})
 		return err
 	case "multicast":
 		req := MulticastMessageRequest{
-			To:       userIDs,
-			Messages: []any{message},
+			To:                     userIDs,
+			Messages:               messages,
+			CustomAggregationUnits: units,
 		}
 		_, err := c.Post(ctx, "/v2/bot/message/multicast", req)
 		return err
@@ -731,3 +731,54 @@ func TestClient_MarkMessagesAsReadByToken_Error(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestClient_OnSend(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/bot/message/push" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"bad"}`))
+			return
+		}
+		w.Header().Set("X-Line-Request-Id", "req-"+r.URL.Path[len("/v2/bot/message/"):])
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	var sent []SentMessage
+	client.OnSend(func(s SentMessage) { sent = append(sent, s) })
+
+	ctx := context.Background()
+	text := TextMessage{Type: "text", Text: "Hello"}
+	if err := client.SendMessage(ctx, "multicast", "", []string{"U1", "U2", "U3"}, text); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Broadcast(ctx, BroadcastMessageRequest{Messages: []any{text}}); err != nil {
+		t.Fatal(err)
+	}
+	// Neither a rejected send nor a request that sends nothing is reported
+	if err := client.SendMessage(ctx, "push", "U1", nil, text); err == nil {
+		t.Fatal("expected the push to fail")
+	}
+	if _, err := client.GetMessageQuota(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 sends, got %+v", sent)
+	}
+	if sent[0].Type != "multicast" || sent[0].Recipients != 3 || sent[0].RequestID != "req-multicast" {
+		t.Errorf("unexpected multicast: %+v", sent[0])
+	}
+	if sent[1].Type != "broadcast" || sent[1].Recipients != 0 || sent[1].RequestID != "req-broadcast" {
+		t.Errorf("unexpected broadcast: %+v", sent[1])
+	}
+	if sent[0].PayloadHash == "" || sent[0].PayloadHash != sent[1].PayloadHash {
+		t.Errorf("expected the same message to hash the same: %q, %q", sent[0].PayloadHash, sent[1].PayloadHash)
+	}
+	var messages []TextMessage
+	if err := json.Unmarshal(sent[0].Messages, &messages); err != nil || len(messages) != 1 || messages[0].Text != "Hello" {
+		t.Errorf("unexpected messages: %s", sent[0].Messages)
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// NetworkOptions configure NewTransport for networks that reach the LINE
+// API through a proxy, possibly one that intercepts TLS.
+type NetworkOptions struct {
+	// Proxy is the proxy URL (http, https, or socks5) for every request.
+	// Empty uses HTTPS_PROXY, HTTP_PROXY, and NO_PROXY from the environment.
+	Proxy string
+	// CACertFile is a PEM file of CA certificates trusted in addition to
+	// the system ones, such as a TLS-intercepting proxy's
+	CACertFile string
+	// InsecureSkipVerify turns off TLS certificate verification entirely
+	InsecureSkipVerify bool
+	// ConnectTimeout bounds connecting to a host, including the TLS
+	// handshake. 0 keeps the defaults of http.DefaultTransport.
+	ConnectTimeout time.Duration
+}
+
+// IsZero reports whether opts change nothing from the default transport.
+func (opts NetworkOptions) IsZero() bool {
+	return opts == NetworkOptions{}
+}
+
+// NewTransport returns a copy of http.DefaultTransport configured by opts.
+func NewTransport(opts NetworkOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https, or socks5", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if opts.ConnectTimeout > 0 {
+		dialer := &net.Dialer{Timeout: opts.ConnectTimeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = opts.ConnectTimeout
+	}
+
+	if opts.CACertFile != "" || opts.InsecureSkipVerify {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if transport.TLSClientConfig != nil {
+			tlsConfig = transport.TLSClientConfig.Clone()
+		}
+		if opts.CACertFile != "" {
+			pem, err := os.ReadFile(opts.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in %s", opts.CACertFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		// The caller asked for it explicitly and is warned
+		tlsConfig.InsecureSkipVerify = opts.InsecureSkipVerify //nolint:gosec
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
+
+// SetTransport sets the transport requests are finally sent with, such as
+// one from NewTransport. Call it before the Set methods that wrap the
+// transport (SetTrace, SetFailover, SetRateLimit, ...), which it would
+// otherwise replace. Stateless tokens are issued through it too.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+	if p, ok := c.tokens.(*StatelessTokenProvider); ok {
+		p.issuer.httpClient.Transport = rt
+	}
+}
+
+// SetTimeout bounds each HTTP request, from sending it to reading the whole
+// response, to d; 0 means no limit. Every retry gets its own d. The default
+// is 30 seconds.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.httpClient.Timeout = d
+	if p, ok := c.tokens.(*StatelessTokenProvider); ok {
+		p.issuer.httpClient.Timeout = d
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewTransport_CACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"userId":"U1","basicId":"@bot","displayName":"Bot","chatMode":"chat","markAsReadMode":"auto"}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the CA the server's certificate is not trusted
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	client.SetTransport(http.DefaultTransport)
+	if _, err := client.GetBotInfo(context.Background()); err == nil {
+		t.Fatal("expected certificate error without --ca-cert")
+	}
+
+	for name, opts := range map[string]NetworkOptions{
+		"ca cert":  {CACertFile: caFile},
+		"insecure": {InsecureSkipVerify: true},
+	} {
+		transport, err := NewTransport(opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		client := NewClient("test-token", false, false)
+		client.SetBaseURL(server.URL)
+		client.SetTransport(transport)
+		info, err := client.GetBotInfo(context.Background())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if info.UserID != "U1" {
+			t.Errorf("%s: unexpected bot info: %+v", name, info)
+		}
+	}
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxy is sent the absolute URL of the target
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+		_, _ = w.Write([]byte(`{"userId":"U1"}`))
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(NetworkOptions{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := NewClient("test-token", false, false)
+	client.SetBaseURL("http://api.line.invalid")
+	client.SetTransport(transport)
+	if _, err := client.GetBotInfo(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(proxied) != 1 || proxied[0] != "GET http://api.line.invalid/v2/bot/info" {
+		t.Errorf("proxied requests = %v", proxied)
+	}
+}
+
+func TestNewTransport_Errors(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "not.pem")
+	if err := os.WriteFile(notPEM, []byte("hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts NetworkOptions
+		want string
+	}{
+		{"bad scheme", NetworkOptions{Proxy: "ftp://proxy:21"}, "scheme must be http, https, or socks5"},
+		{"no host", NetworkOptions{Proxy: "proxy.example.com"}, "invalid proxy URL"},
+		{"missing CA file", NetworkOptions{CACertFile: filepath.Join(t.TempDir(), "missing.pem")}, "failed to read CA certificate"},
+		{"not PEM", NetworkOptions{CACertFile: notPEM}, "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTransport(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import "context"
+
+// PageOptions control Paginate.
+type PageOptions struct {
+	// Cursor is where to start: the next cursor of an earlier call, or ""
+	// for the first page
+	Cursor string
+	// Limit caps the items returned, fetching as many pages as needed to
+	// reach it; 0 means no cap
+	Limit int
+	// All follows next cursors to the last page. Without All or Limit only
+	// one page is fetched.
+	All bool
+	// PageSize is the items to ask for per request; 0 for the API default
+	PageSize int
+	// MaxPageSize is the most items the API returns per request. When set,
+	// requests under Limit ask for no more than are still wanted, so no
+	// page is cut short. 0 for APIs whose page size cannot be chosen.
+	MaxPageSize int
+}
+
+// PageFunc fetches the page at cursor, asking for size items (0 for the API
+// default). It returns the page's items and the cursor of the following
+// page, or "" after the last page.
+type PageFunc[T any] func(ctx context.Context, cursor string, size int) (items []T, next string, err error)
+
+// Paginate fetches pages with fetch as opts ask, and returns their items and
+// the cursor to continue from, or "" if there are no more.
+//
+// If a page has to be cut short to honor Limit, the cursor returned is the
+// one that fetched it, so continuing repeats part of that page rather than
+// skipping the rest of it.
+func Paginate[T any](ctx context.Context, opts PageOptions, fetch PageFunc[T]) ([]T, string, error) {
+	items := []T{}
+	next, err := PaginateEach(ctx, opts, fetch, func(page []T) error {
+		items = append(items, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return items, next, nil
+}
+
+// PaginateEach is Paginate that hands each page's items to fn as they
+// arrive instead of collecting them, for output streamed page by page. It
+// stops at the first error from fn.
+func PaginateEach[T any](ctx context.Context, opts PageOptions, fetch PageFunc[T], fn func(items []T) error) (string, error) {
+	n := 0
+	cursor := opts.Cursor
+	for {
+		size := opts.PageSize
+		if opts.Limit > 0 && opts.MaxPageSize > 0 {
+			size = min(opts.Limit-n, opts.MaxPageSize)
+		}
+		page, next, err := fetch(ctx, cursor, size)
+		if err != nil {
+			return "", err
+		}
+		if opts.Limit > 0 && n+len(page) > opts.Limit {
+			return cursor, fn(page[:opts.Limit-n])
+		}
+		if err := fn(page); err != nil {
+			return "", err
+		}
+		n += len(page)
+		if next == "" || next == cursor {
+			return "", nil
+		}
+		cursor = next
+		if opts.Limit > 0 {
+			if n == opts.Limit {
+				return next, nil
+			}
+		} else if !opts.All {
+			return next, nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// numberPages serves the numbers 1..total, size at a time (pageSize when
+// size is 0), with the next number as the cursor.
+func numberPages(total, pageSize int, calls *[]string) PageFunc[int] {
+	return func(ctx context.Context, cursor string, size int) ([]int, string, error) {
+		*calls = append(*calls, fmt.Sprintf("%s/%d", cursor, size))
+		if size == 0 {
+			size = pageSize
+		}
+		first := 1
+		if cursor != "" {
+			first, _ = strconv.Atoi(cursor)
+		}
+		var items []int
+		for n := first; n <= total && len(items) < size; n++ {
+			items = append(items, n)
+		}
+		next := ""
+		if first+len(items) <= total {
+			next = strconv.Itoa(first + len(items))
+		}
+		return items, next, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      PageOptions
+		wantItems string
+		wantNext  string
+		wantCalls string
+	}{
+		{"one page", PageOptions{}, "1,2,3", "4", "/0"},
+		{"cursor", PageOptions{Cursor: "4"}, "4,5,6", "7", "4/0"},
+		{"all", PageOptions{All: true}, "1,2,3,4,5,6,7", "", "/0,4/0,7/0"},
+		{"limit sizes requests", PageOptions{Limit: 5, PageSize: 3, MaxPageSize: 2}, "1,2,3,4,5", "6", "/2,3/2,5/1"},
+		{"limit cuts a page", PageOptions{Limit: 4}, "1,2,3,4", "4", "/0,4/0"},
+		{"limit past the end", PageOptions{Limit: 50, All: true}, "1,2,3,4,5,6,7", "", "/0,4/0,7/0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			items, next, err := Paginate(context.Background(), tt.opts, numberPages(7, 3, &calls))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, n := range items {
+				got = append(got, strconv.Itoa(n))
+			}
+			if strings.Join(got, ",") != tt.wantItems || next != tt.wantNext {
+				t.Errorf("got items %v next %q, want %s next %q", got, next, tt.wantItems, tt.wantNext)
+			}
+			if strings.Join(calls, ",") != tt.wantCalls {
+				t.Errorf("calls = %v, want %s", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate is a request budget: Requests per Per. Up to Requests calls may be
+// made at once, after which they are spaced evenly.
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+// String formats r as ParseRate accepts it, e.g. "200/s".
+func (r Rate) String() string {
+	unit := map[time.Duration]string{time.Second: "s", time.Minute: "m", time.Hour: "h"}[r.Per]
+	if unit == "" {
+		unit = r.Per.String()
+	}
+	return fmt.Sprintf("%d/%s", r.Requests, unit)
+}
+
+// ParseRate parses a rate such as "200/s", "60/m", "60/h", or "10/500ms".
+func ParseRate(s string) (Rate, error) {
+	n, per, ok := strings.Cut(strings.TrimSpace(s), "/")
+	requests, err := strconv.Atoi(strings.TrimSpace(n))
+	if !ok || err != nil || requests <= 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: want requests/period, e.g. 200/s", s)
+	}
+	var d time.Duration
+	switch per = strings.TrimSpace(per); per {
+	case "s", "sec":
+		d = time.Second
+	case "m", "min":
+		d = time.Minute
+	case "h", "hour":
+		d = time.Hour
+	default:
+		if d, err = time.ParseDuration(per); err != nil || d <= 0 {
+			return Rate{}, fmt.Errorf("invalid rate %q: period must be s, m, h, or a duration", s)
+		}
+	}
+	return Rate{Requests: requests, Per: d}, nil
+}
+
+// Endpoint classes with their own rate budgets.
+const (
+	ClassDefault    = "default"
+	ClassMulticast  = "multicast"
+	ClassBroadcast  = "broadcast"
+	ClassNarrowcast = "narrowcast"
+	ClassAudience   = "audience"
+	ClassProfile    = "profile"
+	ClassFollowers  = "followers"
+)
+
+// DefaultRateLimits are LINE's documented rate limits per endpoint class.
+var DefaultRateLimits = map[string]Rate{
+	ClassDefault:    {Requests: 2000, Per: time.Second},
+	ClassMulticast:  {Requests: 200, Per: time.Second},
+	ClassBroadcast:  {Requests: 60, Per: time.Hour},
+	ClassNarrowcast: {Requests: 60, Per: time.Hour},
+	ClassAudience:   {Requests: 60, Per: time.Minute},
+	ClassProfile:    {Requests: 2000, Per: time.Second},
+	ClassFollowers:  {Requests: 2000, Per: time.Second},
+}
+
+// EndpointClass returns the rate limit class of a request: multicast,
+// broadcast, narrowcast, audience (creating and updating audiences),
+// profile (user and member profiles), followers, or default.
+func EndpointClass(method, path string) string {
+	// Ignore the path of a proxy base URL
+	if i := strings.Index(path, "/v2/"); i > 0 {
+		path = path[i:]
+	}
+	switch {
+	case path == "/v2/bot/message/multicast":
+		return ClassMulticast
+	case path == "/v2/bot/message/broadcast":
+		return ClassBroadcast
+	case path == "/v2/bot/message/narrowcast":
+		return ClassNarrowcast
+	case strings.HasPrefix(path, "/v2/bot/audienceGroup/") && method != http.MethodGet:
+		return ClassAudience
+	case strings.HasPrefix(path, "/v2/bot/profile/"),
+		strings.Contains(path, "/member/") && (strings.HasPrefix(path, "/v2/bot/group/") || strings.HasPrefix(path, "/v2/bot/room/")):
+		return ClassProfile
+	case strings.HasPrefix(path, "/v2/bot/followers/"):
+		return ClassFollowers
+	}
+	return ClassDefault
+}
+
+// SetRateLimit spaces out requests so each endpoint class stays within its
+// budget. limits override DefaultRateLimits per class; a class without a
+// limit uses the default class's. Requests wait (or fail when their context
+// ends) instead of being sent over budget.
+func (c *Client) SetRateLimit(limits map[string]Rate) {
+	rates := make(map[string]Rate, len(DefaultRateLimits))
+	for class, rate := range DefaultRateLimits {
+		rates[class] = rate
+	}
+	for class, rate := range limits {
+		rates[class] = rate
+	}
+
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = &rateLimiter{
+		next:    next,
+		rates:   rates,
+		buckets: make(map[string]*tokenBucket),
+		now:     func() time.Time { return c.now() },
+		sleep:   func(ctx context.Context, d time.Duration) error { return c.sleep(ctx, d) },
+		log:     c.log,
+	}
+}
+
+// rateLimiter is an http.RoundTripper that delays requests to keep each
+// endpoint class within its rate.
+type rateLimiter struct {
+	next  http.RoundTripper
+	rates map[string]Rate
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+	log   func(level slog.Level, msg string, args ...any)
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func (l *rateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	class := EndpointClass(req.Method, req.URL.Path)
+	if wait := l.bucket(class).take(l.now()); wait > 0 {
+		l.log(slog.LevelDebug, "rate limit: waiting", "class", class, "wait", wait.Round(time.Millisecond))
+		if err := l.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+	return l.next.RoundTrip(req)
+}
+
+func (l *rateLimiter) bucket(class string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[class]; ok {
+		return b
+	}
+	rate, ok := l.rates[class]
+	if !ok {
+		rate = l.rates[ClassDefault]
+	}
+	b := &tokenBucket{rate: rate, tokens: float64(rate.Requests)}
+	l.buckets[class] = b
+	return b
+}
+
+// tokenBucket holds up to rate.Requests tokens, refilled continuously at
+// rate.Requests per rate.Per. It is safe for concurrent use.
+type tokenBucket struct {
+	rate Rate
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// take removes a token and returns how long the caller must wait before
+// using it. Tokens may go negative, so concurrent callers queue up in turn.
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	perToken := b.rate.Per / time.Duration(b.rate.Requests)
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens += float64(now.Sub(b.last)) / float64(perToken)
+		b.tokens = min(b.tokens, float64(b.rate.Requests))
+	}
+	if b.last.IsZero() || now.After(b.last) {
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(perToken))
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Rate
+		err  bool
+	}{
+		{in: "200/s", want: Rate{200, time.Second}},
+		{in: "60/m", want: Rate{60, time.Minute}},
+		{in: " 60 / h ", want: Rate{60, time.Hour}},
+		{in: "10/500ms", want: Rate{10, 500 * time.Millisecond}},
+		{in: "200", err: true},
+		{in: "0/s", err: true},
+		{in: "5/fortnight", err: true},
+		{in: "5/-1s", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.in)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+	if s := (Rate{60, time.Hour}).String(); s != "60/h" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestEndpointClass(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodPost, "/v2/bot/message/multicast", ClassMulticast},
+		{http.MethodPost, "/v2/bot/message/broadcast", ClassBroadcast},
+		{http.MethodPost, "/v2/bot/message/narrowcast", ClassNarrowcast},
+		{http.MethodPost, "/v2/bot/message/push", ClassDefault},
+		{http.MethodPost, "/v2/bot/audienceGroup/upload", ClassAudience},
+		{http.MethodGet, "/v2/bot/audienceGroup/list", ClassDefault},
+		{http.MethodGet, "/v2/bot/profile/U123", ClassProfile},
+		{http.MethodGet, "/v2/bot/group/C123/member/U123", ClassProfile},
+		{http.MethodGet, "/v2/bot/group/C123/members/ids", ClassDefault},
+		{http.MethodGet, "/v2/bot/followers/ids", ClassFollowers},
+		{http.MethodPost, "/proxy/v2/bot/message/multicast", ClassMulticast},
+	}
+	for _, tt := range tests {
+		if got := EndpointClass(tt.method, tt.path); got != tt.want {
+			t.Errorf("EndpointClass(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := &tokenBucket{rate: Rate{2, time.Second}, tokens: 2}
+
+	// The burst is free, then calls are spaced 500ms apart
+	for i, want := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if got := b.take(start); got != want {
+			t.Errorf("take %d = %s, want %s", i, got, want)
+		}
+	}
+
+	// After the queue drains and a second passes, the bucket refills
+	b = &tokenBucket{rate: Rate{2, time.Second}, tokens: 2}
+	b.take(start)
+	b.take(start)
+	if got := b.take(start.Add(2 * time.Second)); got != 0 {
+		t.Errorf("take after refill = %s, want 0", got)
+	}
+}
+
+func TestSetRateLimit_DelaysPerClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+	client.SetClock(func() time.Time { return now })
+	var waits []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	client.SetRateLimit(map[string]Rate{ClassMulticast: {1, time.Second}})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Post(ctx, "/v2/bot/message/multicast", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Other classes have their own budget
+	if _, err := client.Get(ctx, "/v2/bot/info"); err != nil {
+		t.Fatal(err)
+	}
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Errorf("waits = %v, want [1s 2s]", waits)
+	}
+}
+
+func TestSetRateLimit_CanceledWhileWaiting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+	client.SetRateLimit(map[string]Rate{ClassDefault: {1, time.Hour}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Get(ctx, "/v2/bot/info"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(ctx, "/v2/bot/info"); err == nil {
+		t.Error("expected the second request to give up when its context ended")
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// RawResponse is a response returned as is by Raw.
+type RawResponse struct {
+	StatusCode int
+	Status     string
+	Headers    http.Header
+	Body       []byte
+}
+
+// Raw sends an authorized request with body as is, for endpoints the client
+// does not wrap yet. It goes to the data host (api-data.line.me) instead
+// when dataHost is set and the client uses the production URL.
+//
+// The response is returned whatever its status; a status of 400 or more
+// also returns an *APIError describing it.
+func (c *Client) Raw(ctx context.Context, method, path string, body []byte, contentType string, dataHost bool) (*RawResponse, error) {
+	baseURL := c.baseURL
+	if dataHost && baseURL == BaseURL {
+		baseURL = DataBaseURL
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	c.debugLogRequest(req, body)
+
+	if c.dryRun {
+		mock := c.mockDryRunResponse(method)
+		return &RawResponse{StatusCode: http.StatusOK, Status: "200 OK", Headers: mock.Headers, Body: mock.Body}, nil
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	c.debugLogResponse(resp, respBody)
+
+	raw := &RawResponse{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Header,
+		Body:       respBody,
+	}
+	if resp.StatusCode >= 400 {
+		return raw, responseError(resp, method, path, respBody)
+	}
+	return raw, nil
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryOptions configures SetRetry.
+type RetryOptions struct {
+	// MaxRetries is how many times a failed request is repeated (0 disables
+	// retries).
+	MaxRetries int
+	// Backoff is the delay before the first retry (default 500ms). It
+	// doubles with each retry, with jitter, up to maxRetryDelay.
+	Backoff time.Duration
+}
+
+const (
+	// maxRetryDelay caps the exponential backoff between retries.
+	maxRetryDelay = 30 * time.Second
+	// maxRetryAfter is the longest Retry-After the client waits for; a
+	// longer one ends the retries.
+	maxRetryAfter = time.Minute
+	// retryHistoryHeader carries the request IDs of earlier attempts from
+	// send to responseError. It is set on responses only, never sent.
+	retryHistoryHeader = "X-Line-Cli-Retried-Request-Ids"
+)
+
+// retryKeyEndpoints are the send endpoints that accept an X-Line-Retry-Key.
+// Each send gets a new key, and its retries repeat it, so LINE accepts the
+// send at most once: a retry of a send it already accepted fails with 409
+// rather than sending twice.
+var retryKeyEndpoints = map[string]bool{
+	"/v2/bot/message/push":       true,
+	"/v2/bot/message/multicast":  true,
+	"/v2/bot/message/broadcast":  true,
+	"/v2/bot/message/narrowcast": true,
+}
+
+// SetRetry repeats requests that fail with 429 or a 5xx status, waiting for
+// the response's Retry-After if it has one and otherwise backing off
+// exponentially with jitter.
+//
+// A 429 means the request was not processed, so it is retried for any
+// method. A 5xx is retried only for idempotent methods (GET, HEAD, PUT,
+// DELETE) and requests with an X-Line-Retry-Key header, such as pushes and
+// multicasts (see retryKeyEndpoints), so a send the API may have processed
+// is never repeated without one.
+func (c *Client) SetRetry(opts RetryOptions) {
+	if opts.Backoff <= 0 {
+		opts.Backoff = 500 * time.Millisecond
+	}
+	c.retry = opts
+}
+
+// shouldRetry reports whether a request that got resp may be repeated.
+func shouldRetry(req *http.Request, resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode < 500:
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("X-Line-Retry-Key") != ""
+}
+
+// retryDelay returns how long to wait before retry number attempt (from 1):
+// the response's Retry-After, or the jittered exponential backoff. It
+// returns false if Retry-After asks for longer than maxRetryAfter.
+func (c *Client) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), c.now()); ok {
+		return after, after <= maxRetryAfter
+	}
+	delay := c.retry.Backoff << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	// Equal jitter: half the delay plus a random share of the other half,
+	// so concurrent clients spread out without retrying immediately
+	half := delay / 2
+	return half + rand.N(half+1), true
+}
+
+// parseRetryAfter parses a Retry-After value in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sendWithRetry performs req with sendOnce, repeating it as configured by
+// SetRetry. The request IDs of failed attempts are recorded on the final
+// response for responseError.
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	resp, err := c.sendOnce(req)
+	var requestIDs []string
+	for attempt := 1; attempt <= c.retry.MaxRetries && err == nil && shouldRetry(req, resp); attempt++ {
+		if req.Body != nil && req.GetBody == nil {
+			break
+		}
+		delay, ok := c.retryDelay(resp, attempt)
+		if !ok {
+			break
+		}
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				break
+			}
+			retry.Body = body
+		}
+
+		requestIDs = append(requestIDs, resp.Header.Get("X-Line-Request-Id"))
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		c.log(slog.LevelInfo, "retrying request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "delay", delay.Round(time.Millisecond), "retry", attempt, "maxRetries", c.retry.MaxRetries, "requestId", resp.Header.Get("X-Line-Request-Id"))
+		if err := c.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		resp, err = c.sendOnce(retry)
+	}
+	if err == nil && len(requestIDs) > 0 {
+		resp.Header[retryHistoryHeader] = requestIDs
+	}
+	return resp, err
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRetryTestClient returns a client for server that records its retry
+// delays instead of sleeping.
+func newRetryTestClient(server *httptest.Server, maxRetries int) (*Client, *[]time.Duration) {
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+	client.SetRetry(RetryOptions{MaxRetries: maxRetries, Backoff: 100 * time.Millisecond})
+	var delays []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return client, &delays
+}
+
+func TestRetry_ServerErrorThenSuccess(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, delays := newRetryTestClient(server, 3)
+	data, err := client.Get(context.Background(), "/test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"status":"ok"}` || calls.Load() != 3 {
+		t.Errorf("got %s after %d calls", data, calls.Load())
+	}
+
+	// Jittered exponential backoff: [50ms, 100ms], then [100ms, 200ms]
+	if len(*delays) != 2 {
+		t.Fatalf("delays = %v", *delays)
+	}
+	for i, d := range *delays {
+		base := 100 * time.Millisecond << i
+		if d < base/2 || d > base {
+			t.Errorf("delay %d = %s, want between %s and %s", i, d, base/2, base)
+		}
+	}
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	// A 429 was not processed, so even a POST is repeated, with its body
+	client, delays := newRetryTestClient(server, 3)
+	if _, err := client.Post(context.Background(), "/v2/bot/message/push", map[string]string{"to": "U1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 2*time.Second {
+		t.Errorf("delays = %v, want [2s]", *delays)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Errorf("expected the body to be sent again, got %q", bodies)
+	}
+}
+
+func TestRetry_ExhaustedErrorListsRequestIDs(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Line-Request-Id", fmt.Sprintf("req-%d", calls.Add(1)))
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"Too many requests"}`))
+	}))
+	defer server.Close()
+
+	client, _ := newRetryTestClient(server, 2)
+	_, err := client.Get(context.Background(), "/v2/bot/info")
+	apiErr := AsAPIError(err)
+	if apiErr == nil {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+	if apiErr.RequestID != "req-3" || strings.Join(apiErr.RetriedRequestIDs, ",") != "req-1,req-2" {
+		t.Errorf("request IDs = %q, %q", apiErr.RequestID, apiErr.RetriedRequestIDs)
+	}
+	if !strings.Contains(apiErr.Error(), "Attempts: 3 (request IDs: req-1, req-2, req-3)") {
+		t.Errorf("unexpected message:\n%s", apiErr.Error())
+	}
+}
+
+func TestRetry_NotRetried(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		method     string
+	}{
+		{"client error", http.StatusBadRequest, "", http.MethodGet},
+		{"server error on POST", http.StatusInternalServerError, "", http.MethodPost},
+		{"Retry-After too long", http.StatusTooManyRequests, "3600", http.MethodGet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client, _ := newRetryTestClient(server, 3)
+			var err error
+			if tt.method == http.MethodPost {
+				_, err = client.Post(context.Background(), "/v2/bot/message/reply", nil)
+			} else {
+				_, err = client.Get(context.Background(), "/test")
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if calls.Load() != 1 {
+				t.Errorf("expected 1 attempt, got %d", calls.Load())
+			}
+		})
+	}
+}
+
+func TestRetry_Disabled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+	if _, err := client.Get(context.Background(), "/test"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected no retries by default, got %d attempts", calls.Load())
+	}
+}
+
+func TestRetry_CanceledWhileWaiting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := newRetryTestClient(server, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	client.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+	if _, err := client.Get(ctx, "/test"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestShouldRetry_RetryKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v2/bot/message/push", nil)
+	resp := &http.Response{StatusCode: http.StatusBadGateway}
+	if shouldRetry(req, resp) {
+		t.Error("expected a POST without a retry key not to be retried on 502")
+	}
+	req.Header.Set("X-Line-Retry-Key", "123e4567-e89b-12d3-a456-426614174000")
+	if !shouldRetry(req, resp) {
+		t.Error("expected a POST with a retry key to be retried on 502")
+	}
+}
+
+func TestRetry_SendRepeatsRetryKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("X-Line-Retry-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, _ := newRetryTestClient(server, 3)
+	n := 0
+	client.SetUUIDGenerator(func() string {
+		n++
+		return fmt.Sprintf("key-%d", n)
+	})
+	if _, err := client.Post(context.Background(), "/v2/bot/message/push", map[string]string{"to": "U1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "key-1" || keys[1] != "key-1" {
+		t.Errorf("retry keys = %v, want key-1 on both attempts", keys)
+	}
+	if _, err := client.Post(context.Background(), "/v2/bot/message/reply", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys[2] != "" {
+		t.Errorf("reply sent retry key %q", keys[2])
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"5", 5 * time.Second, true},
+		{"Sun, 01 Mar 2026 12:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 01 Mar 2026 11:59:00 GMT", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+		{"-1", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type RichMenu struct {
@@ -107,7 +108,7 @@ func (c *Client) UploadRichMenuImage(ctx context.Context, richMenuID string, con
 	// Use data API endpoint for binary uploads (only switch if using production URL)
 	originalBaseURL := c.baseURL
 	if c.baseURL == BaseURL {
-		c.baseURL = "https://api-data.line.me"
+		c.baseURL = DataBaseURL
 		defer func() { c.baseURL = originalBaseURL }()
 	}
 
@@ -223,14 +224,15 @@ func (c *Client) ListRichMenuAliases(ctx context.Context) ([]RichMenuAlias, erro
 
 // Bulk operations - link/unlink menu to/from multiple users at once
 
-// maxBulkUserIDs is the maximum number of user IDs allowed in a single bulk request
-const maxBulkUserIDs = 500
+// MaxBulkUserIDs is the maximum number of user IDs allowed in a single bulk
+// link or unlink request
+const MaxBulkUserIDs = 500
 
 // LinkRichMenuToUsers links a rich menu to multiple users at once
 // POST /v2/bot/richmenu/bulk/link
 func (c *Client) LinkRichMenuToUsers(ctx context.Context, richMenuID string, userIDs []string) error {
-	if len(userIDs) > maxBulkUserIDs {
-		return fmt.Errorf("too many user IDs: max %d, got %d", maxBulkUserIDs, len(userIDs))
+	if len(userIDs) > MaxBulkUserIDs {
+		return fmt.Errorf("too many user IDs: max %d, got %d", MaxBulkUserIDs, len(userIDs))
 	}
 	req := struct {
 		RichMenuID string   `json:"richMenuId"`
@@ -246,8 +248,8 @@ func (c *Client) LinkRichMenuToUsers(ctx context.Context, richMenuID string, use
 // UnlinkRichMenuFromUsers unlinks rich menus from multiple users at once
 // POST /v2/bot/richmenu/bulk/unlink
 func (c *Client) UnlinkRichMenuFromUsers(ctx context.Context, userIDs []string) error {
-	if len(userIDs) > maxBulkUserIDs {
-		return fmt.Errorf("too many user IDs: max %d, got %d", maxBulkUserIDs, len(userIDs))
+	if len(userIDs) > MaxBulkUserIDs {
+		return fmt.Errorf("too many user IDs: max %d, got %d", MaxBulkUserIDs, len(userIDs))
 	}
 	req := struct {
 		UserIDs []string `json:"userIds"`
@@ -298,6 +300,104 @@ func (c *Client) RichMenuBatch(ctx context.Context, operations []RichMenuBatchOp
 	return resp.RequestID, nil
 }
 
+// Limits of a single rich menu batch request.
+const (
+	// MaxRichMenuBatchOperations is how many operations one request holds
+	MaxRichMenuBatchOperations = 1000
+	// MaxRichMenuBatchUsers is how many user IDs one operation holds
+	MaxRichMenuBatchUsers = 500
+)
+
+// RichMenuBatchOptions control RichMenuBatchAll.
+type RichMenuBatchOptions struct {
+	// ResumeRequestID resumes the first request submitted
+	ResumeRequestID string
+	// Skip is how many requests of an earlier run already succeeded; they
+	// are not submitted again
+	Skip int
+	// Wait waits for each request to finish before submitting the next,
+	// polling its progress every PollInterval (default 2s). A request that
+	// fails stops the rest.
+	Wait         bool
+	PollInterval time.Duration
+	// OnSubmit is called as each request is accepted, with its position in
+	// the requests (from 0) and its operations
+	OnSubmit func(index int, requestID string, operations []RichMenuBatchOperation)
+}
+
+// SplitRichMenuBatch splits operations into sets that each fit in one
+// batch request, keeping their order: an operation with more than
+// MaxRichMenuBatchUsers users becomes several, and the operations are
+// grouped MaxRichMenuBatchOperations at a time.
+func SplitRichMenuBatch(operations []RichMenuBatchOperation) [][]RichMenuBatchOperation {
+	var split []RichMenuBatchOperation
+	for _, op := range operations {
+		users := op.UserIDs
+		for len(users) > MaxRichMenuBatchUsers {
+			part := op
+			part.UserIDs = users[:MaxRichMenuBatchUsers]
+			split = append(split, part)
+			users = users[MaxRichMenuBatchUsers:]
+		}
+		op.UserIDs = users
+		split = append(split, op)
+	}
+
+	var sets [][]RichMenuBatchOperation
+	for len(split) > MaxRichMenuBatchOperations {
+		sets = append(sets, split[:MaxRichMenuBatchOperations])
+		split = split[MaxRichMenuBatchOperations:]
+	}
+	if len(split) > 0 {
+		sets = append(sets, split)
+	}
+	return sets
+}
+
+// RichMenuBatchAll executes operations of any size as sequential batch
+// requests, split by SplitRichMenuBatch. It returns the IDs of the requests
+// submitted, also when it stops partway with an error. Each request is
+// atomic, but the set as a whole is not.
+func (c *Client) RichMenuBatchAll(ctx context.Context, operations []RichMenuBatchOperation, opts RichMenuBatchOptions) ([]string, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	sets := SplitRichMenuBatch(operations)
+	var requestIDs []string
+	resume := opts.ResumeRequestID
+	for i := opts.Skip; i < len(sets); i++ {
+		requestID, err := c.RichMenuBatch(ctx, sets[i], resume)
+		if err != nil {
+			return requestIDs, fmt.Errorf("request %d of %d: %w", i+1, len(sets), err)
+		}
+		resume = ""
+		requestIDs = append(requestIDs, requestID)
+		if opts.OnSubmit != nil {
+			opts.OnSubmit(i, requestID, sets[i])
+		}
+		if !opts.Wait {
+			continue
+		}
+		for {
+			progress, err := c.GetRichMenuBatchProgress(ctx, requestID)
+			if err != nil {
+				return requestIDs, fmt.Errorf("request %d of %d (%s): %w", i+1, len(sets), requestID, err)
+			}
+			if progress.Phase == "failed" {
+				return requestIDs, fmt.Errorf("request %d of %d (%s) failed", i+1, len(sets), requestID)
+			}
+			if progress.Phase != "ongoing" {
+				break
+			}
+			if err := c.sleep(ctx, interval); err != nil {
+				return requestIDs, err
+			}
+		}
+	}
+	return requestIDs, nil
+}
+
 // ValidateRichMenuBatch validates batch operations without executing
 // POST /v2/bot/richmenu/validate/batch
 func (c *Client) ValidateRichMenuBatch(ctx context.Context, operations []RichMenuBatchOperation) error {
@@ -343,7 +443,7 @@ func (c *Client) DownloadRichMenuImage(ctx context.Context, richMenuID string) (
 	// Use data API endpoint for binary downloads (only switch if using production URL)
 	originalBaseURL := c.baseURL
 	if c.baseURL == BaseURL {
-		c.baseURL = "https://api-data.line.me"
+		c.baseURL = DataBaseURL
 		defer func() { c.baseURL = originalBaseURL }()
 	}
 
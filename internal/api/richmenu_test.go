@@ -3,10 +3,12 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestClient_LinkRichMenuToUsers(t *testing.T) {
@@ -162,6 +164,131 @@ func TestClient_RichMenuBatch_WithResume(t *testing.T) {
 	}
 }
 
+func TestSplitRichMenuBatch(t *testing.T) {
+	users := func(n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("U%d", i)
+		}
+		return ids
+	}
+
+	sets := SplitRichMenuBatch([]RichMenuBatchOperation{
+		{Type: "link", RichMenuID: "richmenu-a", UserIDs: users(1200)},
+		{Type: "unlink", UserIDs: users(3)},
+	})
+	if len(sets) != 1 || len(sets[0]) != 4 {
+		t.Fatalf("expected one request of 4 operations, got %v", len(sets))
+	}
+	for i, want := range []int{500, 500, 200, 3} {
+		if got := len(sets[0][i].UserIDs); got != want {
+			t.Errorf("operation %d: %d users, want %d", i, got, want)
+		}
+	}
+	if sets[0][2].RichMenuID != "richmenu-a" || sets[0][3].Type != "unlink" || sets[0][2].UserIDs[0] != "U1000" {
+		t.Errorf("unexpected operations: %+v", sets[0][2:])
+	}
+
+	many := make([]RichMenuBatchOperation, 2*MaxRichMenuBatchOperations+1)
+	for i := range many {
+		many[i] = RichMenuBatchOperation{Type: "unlink", UserIDs: []string{"U1"}}
+	}
+	sets = SplitRichMenuBatch(many)
+	if len(sets) != 3 || len(sets[0]) != MaxRichMenuBatchOperations || len(sets[2]) != 1 {
+		t.Errorf("expected requests of 1000, 1000, and 1 operations, got %d requests", len(sets))
+	}
+}
+
+func TestClient_RichMenuBatchAll_WaitsForEachRequest(t *testing.T) {
+	var events []string
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/richmenu/batch":
+			var req struct {
+				Operations      []RichMenuBatchOperation `json:"operations"`
+				ResumeRequestID string                   `json:"resumeRequestId"`
+			}
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &req)
+			id := fmt.Sprintf("req-%d", len(events))
+			events = append(events, fmt.Sprintf("submit %d resume=%q", len(req.Operations), req.ResumeRequestID))
+			_, _ = fmt.Fprintf(w, `{"requestId":%q}`, id)
+		case "/v2/bot/richmenu/progress/batch":
+			polls++
+			phase := "succeeded"
+			if polls%2 == 1 {
+				phase = "ongoing"
+			}
+			events = append(events, "poll "+r.URL.Query().Get("requestId")+" "+phase)
+			_, _ = fmt.Fprintf(w, `{"phase":%q}`, phase)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+	client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	operations := make([]RichMenuBatchOperation, MaxRichMenuBatchOperations+1)
+	for i := range operations {
+		operations[i] = RichMenuBatchOperation{Type: "unlink", UserIDs: []string{"U1"}}
+	}
+	var submitted []int
+	ids, err := client.RichMenuBatchAll(context.Background(), operations, RichMenuBatchOptions{
+		ResumeRequestID: "req-old",
+		Wait:            true,
+		OnSubmit: func(index int, requestID string, ops []RichMenuBatchOperation) {
+			submitted = append(submitted, index)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || fmt.Sprint(submitted) != "[0 1]" {
+		t.Errorf("unexpected request IDs %v, submitted %v", ids, submitted)
+	}
+	want := []string{
+		`submit 1000 resume="req-old"`,
+		"poll req-0 ongoing",
+		"poll req-0 succeeded",
+		`submit 1 resume=""`,
+		"poll req-3 ongoing",
+		"poll req-3 succeeded",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events =\n%v\nwant\n%v", events, want)
+	}
+}
+
+func TestClient_RichMenuBatchAll_StopsAfterFailure(t *testing.T) {
+	submits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/bot/richmenu/batch" {
+			submits++
+			_, _ = fmt.Fprintf(w, `{"requestId":"req-%d"}`, submits)
+			return
+		}
+		_, _ = w.Write([]byte(`{"phase":"failed"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", false, false)
+	client.baseURL = server.URL
+
+	operations := make([]RichMenuBatchOperation, 3*MaxRichMenuBatchOperations)
+	for i := range operations {
+		operations[i] = RichMenuBatchOperation{Type: "unlink", UserIDs: []string{"U1"}}
+	}
+	ids, err := client.RichMenuBatchAll(context.Background(), operations, RichMenuBatchOptions{Skip: 1, Wait: true})
+	if err == nil || err.Error() != "request 2 of 3 (req-1) failed" {
+		t.Fatalf("expected the second request to fail, got %v", err)
+	}
+	if submits != 1 || len(ids) != 1 || ids[0] != "req-1" {
+		t.Errorf("expected only the skipped-to request to be submitted, got %d submits, IDs %v", submits, ids)
+	}
+}
+
 func TestClient_ValidateRichMenuBatch(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
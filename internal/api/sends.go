@@ -0,0 +1,100 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// sendEndpoints maps the paths that send messages to the kind of send.
+var sendEndpoints = map[string]string{
+	"/v2/bot/message/push":       "push",
+	"/v2/bot/message/multicast":  "multicast",
+	"/v2/bot/message/broadcast":  "broadcast",
+	"/v2/bot/message/narrowcast": "narrowcast",
+	"/v2/bot/message/reply":      "reply",
+	"/bot/pnp/push":              "pnp",
+}
+
+// SentMessage describes a send that LINE accepted.
+type SentMessage struct {
+	// Type is push, multicast, broadcast, narrowcast, reply, or pnp
+	Type      string
+	RequestID string
+	// Recipients is the number of users named in the request: one for a
+	// push, reply, or PNP message, the list's length for a multicast, and
+	// zero for broadcasts and narrowcasts, whose recipients LINE chooses
+	Recipients int
+	// Messages is the messages array of the request
+	Messages json.RawMessage
+	// PayloadHash is the hex SHA-256 of Messages, to tell identical sends
+	// apart from different ones
+	PayloadHash string
+}
+
+// OnSend registers fn to be called for every message send that LINE
+// accepts. It is not called for dry-run or failed requests.
+func (c *Client) OnSend(fn func(SentMessage)) {
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = sendObserver{next: next, fn: fn}
+}
+
+// sendObserver is an http.RoundTripper that reports accepted sends.
+type sendObserver struct {
+	next http.RoundTripper
+	fn   func(SentMessage)
+}
+
+func (o sendObserver) RoundTrip(req *http.Request) (*http.Response, error) {
+	sendType, ok := sendEndpoints[req.URL.Path]
+	if !ok || req.Method != http.MethodPost || req.GetBody == nil {
+		return o.next.RoundTrip(req)
+	}
+	resp, err := o.next.RoundTrip(req)
+	if err != nil || resp.StatusCode >= 300 {
+		return resp, err
+	}
+	if sent, ok := parseSend(req, sendType); ok {
+		sent.RequestID = resp.Header.Get("X-Line-Request-Id")
+		o.fn(sent)
+	}
+	return resp, err
+}
+
+// parseSend reads what was sent from a copy of the request body.
+func parseSend(req *http.Request, sendType string) (SentMessage, bool) {
+	body, err := req.GetBody()
+	if err != nil {
+		return SentMessage{}, false
+	}
+	defer func() { _ = body.Close() }()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return SentMessage{}, false
+	}
+	var payload struct {
+		To       json.RawMessage `json:"to"`
+		Messages json.RawMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return SentMessage{}, false
+	}
+
+	sent := SentMessage{Type: sendType, Messages: payload.Messages}
+	switch sendType {
+	case "push", "reply", "pnp":
+		sent.Recipients = 1
+	case "multicast":
+		var to []string
+		_ = json.Unmarshal(payload.To, &to)
+		sent.Recipients = len(to)
+	}
+	sum := sha256.Sum256(payload.Messages)
+	sent.PayloadHash = hex.EncodeToString(sum[:])
+	return sent, true
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+)
+
+// AccountSettings are the account settings the Messaging API exposes. Most
+// other settings, such as greeting messages, auto-responses, webhook
+// redelivery, and error notifications, can only be changed in LINE Official
+// Account Manager.
+type AccountSettings struct {
+	// ChatMode is "bot" or "chat"
+	ChatMode string `json:"chatMode"`
+	// MarkAsReadMode is "auto" or "manual"
+	MarkAsReadMode string              `json:"markAsReadMode"`
+	Webhook        WebhookEndpointInfo `json:"webhook"`
+}
+
+// GetAccountSettings collects the account settings readable through the
+// Messaging API from the bot info and webhook endpoint.
+func (c *Client) GetAccountSettings(ctx context.Context) (*AccountSettings, error) {
+	info, err := c.GetBotInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := c.GetWebhookEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountSettings{
+		ChatMode:       info.ChatMode,
+		MarkAsReadMode: info.MarkAsReadMode,
+		Webhook:        *webhook,
+	}, nil
+}
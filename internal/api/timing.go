@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// CallTiming is how long one HTTP request took.
+type CallTiming struct {
+	// Endpoint is the method and path with ID segments replaced by {id},
+	// e.g. "GET /v2/bot/richmenu/{id}"
+	Endpoint string
+	// StatusCode is the response status, or 0 if the request failed
+	StatusCode int
+	// Duration is the time until the response headers arrived
+	Duration time.Duration
+}
+
+// OnTiming registers fn to be called with the timing of every HTTP request
+// the client sends, including failed ones. Durations are measured with the
+// client's clock. Dry-run requests are never sent, so they are not reported.
+func (c *Client) OnTiming(fn func(CallTiming)) {
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = timingObserver{next: next, now: c.Now, fn: fn}
+}
+
+// timingObserver is an http.RoundTripper that reports request durations.
+type timingObserver struct {
+	next http.RoundTripper
+	now  func() time.Time
+	fn   func(CallTiming)
+}
+
+func (o timingObserver) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := o.now()
+	resp, err := o.next.RoundTrip(req)
+	t := CallTiming{
+		Endpoint: req.Method + " " + endpointTemplate(req.URL.Path),
+		Duration: o.now().Sub(start),
+	}
+	if err == nil {
+		t.StatusCode = resp.StatusCode
+	}
+	o.fn(t)
+	return resp, err
+}
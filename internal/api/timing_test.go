@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOnTiming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/bot/richmenu/richmenu-8dfd" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	// Each reading of the clock is two seconds after the last
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := NewClient("token", false, false)
+	client.SetBaseURL(server.URL)
+	client.SetClock(func() time.Time {
+		now = now.Add(2 * time.Second)
+		return now
+	})
+
+	var timings []CallTiming
+	client.OnTiming(func(t CallTiming) { timings = append(timings, t) })
+
+	_, _ = client.Get(context.Background(), "/v2/bot/info")
+	_, _ = client.Get(context.Background(), "/v2/bot/richmenu/richmenu-8dfd")
+
+	if len(timings) != 2 {
+		t.Fatalf("expected 2 timings, got %d", len(timings))
+	}
+	if timings[0].Endpoint != "GET /v2/bot/info" || timings[0].StatusCode != 200 {
+		t.Errorf("unexpected first timing: %+v", timings[0])
+	}
+	if timings[1].Endpoint != "GET /v2/bot/richmenu/{id}" || timings[1].StatusCode != 404 {
+		t.Errorf("unexpected second timing: %+v", timings[1])
+	}
+	for _, tm := range timings {
+		if tm.Duration != 2*time.Second {
+			t.Errorf("expected 2s duration, got %v", tm.Duration)
+		}
+	}
+}
+
+func TestOnTiming_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client := NewClient("token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var timings []CallTiming
+	client.OnTiming(func(t CallTiming) { timings = append(timings, t) })
+
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(timings) != 1 || timings[0].StatusCode != 0 {
+		t.Errorf("expected one failed timing, got %+v", timings)
+	}
+}
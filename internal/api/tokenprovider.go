@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenProvider supplies the channel access token sent with each request.
+type TokenProvider interface {
+	// Token returns the token to use for the next request.
+	Token(ctx context.Context) (string, error)
+	// Invalidate reports that token was rejected with 401. It returns true
+	// if the next call to Token will return a different token, in which case
+	// the request is retried once.
+	Invalidate(token string) bool
+}
+
+// StaticToken is a TokenProvider for a long-lived token that never changes.
+type StaticToken string
+
+// Token returns the token itself.
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Invalidate returns false; a static token cannot be replaced.
+func (t StaticToken) Invalidate(token string) bool {
+	return false
+}
+
+// statelessRefreshMargin is how long before expiry a cached stateless token
+// is replaced, so a request never starts with a token about to expire.
+const statelessRefreshMargin = time.Minute
+
+// StatelessTokenProvider issues stateless channel access tokens (v3) from a
+// channel ID and secret and caches each until shortly before it expires.
+// It is safe for concurrent use.
+type StatelessTokenProvider struct {
+	issuer        *Client
+	channelID     string
+	channelSecret string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewStatelessTokenProvider returns a provider that issues tokens with
+// issuer, which only needs the right base URL and clock; it is never sent
+// an access token.
+func NewStatelessTokenProvider(issuer *Client, channelID, channelSecret string) *StatelessTokenProvider {
+	return &StatelessTokenProvider{issuer: issuer, channelID: channelID, channelSecret: channelSecret}
+}
+
+// Token returns the cached token, issuing a new one if there is none or it
+// is about to expire.
+func (p *StatelessTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.issuer.Now()
+	if p.token != "" && now.Before(p.expiresAt.Add(-statelessRefreshMargin)) {
+		return p.token, nil
+	}
+
+	resp, err := p.issuer.IssueStatelessToken(ctx, p.channelID, p.channelSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to issue stateless token: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", fmt.Errorf("failed to issue stateless token: empty token in response")
+	}
+	p.token = resp.AccessToken
+	p.expiresAt = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	return p.token, nil
+}
+
+// Invalidate drops token from the cache so the next call issues a new one.
+func (p *StatelessTokenProvider) Invalidate(token string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Another request may already have replaced it
+	if p.token == token {
+		p.token = ""
+	}
+	return true
+}
+
+// NewClientWithTokenProvider creates a client that gets its access token
+// from tokens before each request.
+func NewClientWithTokenProvider(tokens TokenProvider, debug bool, dryRun bool) *Client {
+	c := NewClient("", debug, dryRun)
+	c.tokens = tokens
+	return c
+}
+
+// authorize sets the Authorization header on req.
+func (c *Client) authorize(req *http.Request) error {
+	if c.dryRun {
+		// The request is never sent, so don't issue a token for it
+		req.Header.Set("Authorization", "Bearer dry-run")
+		return nil
+	}
+	token, err := c.tokens.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// send performs req, retrying failures as configured by SetRetry.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	return c.sendWithRetry(req)
+}
+
+// sendOnce performs req. If the token is rejected with 401 and the provider
+// can supply a new one, the request is retried once with the new token.
+func (c *Client) sendOnce(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	rejected := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !c.tokens.Invalidate(rejected) || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if err := c.authorize(retry); err != nil {
+		return nil, err
+	}
+	c.log(slog.LevelInfo, "access token rejected; retrying with a new token")
+	return c.httpClient.Do(retry)
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// statelessServer issues numbered stateless tokens and accepts API requests
+// only with the token listed in valid (when set).
+type statelessServer struct {
+	issued int
+	valid  string
+	bodies []string
+}
+
+func (s *statelessServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth2/v3/token" {
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+			if r.PostForm.Get("client_id") != "1234" || r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+				return
+			}
+			s.issued++
+			_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":900,"token_type":"Bearer"}`, s.issued)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		s.bodies = append(s.bodies, string(body))
+		if s.valid != "" && r.Header.Get("Authorization") != "Bearer "+s.valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func newStatelessTestClient(t *testing.T, s *statelessServer, secret string, now *time.Time) *Client {
+	t.Helper()
+	server := httptest.NewServer(s.handler(t))
+	t.Cleanup(server.Close)
+
+	issuer := NewClient("", false, false)
+	issuer.SetBaseURL(server.URL)
+	issuer.SetClock(func() time.Time { return *now })
+
+	client := NewClientWithTokenProvider(NewStatelessTokenProvider(issuer, "1234", secret), false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestStatelessTokenProvider_CachesUntilExpiry(t *testing.T) {
+	s := &statelessServer{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := newStatelessTestClient(t, s, "secret", &now)
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get(context.Background(), "/v2/bot/info"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if s.issued != 1 {
+		t.Errorf("expected 1 token issued for 3 requests, got %d", s.issued)
+	}
+
+	// 15 minute tokens are replaced a minute before they expire
+	now = now.Add(14*time.Minute + time.Second)
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.issued != 2 {
+		t.Errorf("expected a new token near expiry, got %d issued", s.issued)
+	}
+}
+
+func TestStatelessTokenProvider_RefreshesOn401(t *testing.T) {
+	s := &statelessServer{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := newStatelessTestClient(t, s, "secret", &now)
+
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The cached token-1 is no longer accepted
+	s.valid = "token-2"
+	if _, err := client.Post(context.Background(), "/v2/bot/message/push", map[string]string{"to": "U1"}); err != nil {
+		t.Fatalf("expected retry with a new token to succeed, got %v", err)
+	}
+	if s.issued != 2 {
+		t.Errorf("expected 2 tokens issued, got %d", s.issued)
+	}
+	last := s.bodies[len(s.bodies)-1]
+	if last != `{"to":"U1"}` {
+		t.Errorf("expected the retry to resend the body, got %q", last)
+	}
+
+	// A token that is rejected again is not retried forever
+	s.valid = "never"
+	_, err := client.Get(context.Background(), "/v2/bot/info")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if s.issued != 3 {
+		t.Errorf("expected exactly one retry, got %d tokens issued", s.issued)
+	}
+}
+
+func TestStatelessTokenProvider_IssueError(t *testing.T) {
+	s := &statelessServer{}
+	now := time.Now()
+	client := newStatelessTestClient(t, s, "wrong", &now)
+
+	_, err := client.Get(context.Background(), "/v2/bot/info")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(s.bodies) != 0 {
+		t.Error("expected no API request without a token")
+	}
+}
+
+func TestStaticToken_NoRetryOn401(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("expired-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err == nil {
+		t.Fatal("expected error")
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestStatelessTokenProvider_DryRunDoesNotIssue(t *testing.T) {
+	s := &statelessServer{}
+	server := httptest.NewServer(s.handler(t))
+	defer server.Close()
+
+	issuer := NewClient("", false, false)
+	issuer.SetBaseURL(server.URL)
+	client := NewClientWithTokenProvider(NewStatelessTokenProvider(issuer, "1234", "secret"), false, true)
+	client.SetBaseURL(server.URL)
+
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.issued != 0 {
+		t.Errorf("expected no token issued in dry-run, got %d", s.issued)
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceOptions configures SetTrace.
+type TraceOptions struct {
+	// Writer receives the trace, usually stderr
+	Writer io.Writer
+	// Bodies includes request and response bodies
+	Bodies bool
+	// NoRedact shows Authorization headers, tokens, and user IDs as they
+	// are
+	NoRedact bool
+}
+
+// traceMaxBodyLen caps each traced body.
+const traceMaxBodyLen = 64 << 10
+
+// userIDPattern matches LINE user, group, and room IDs.
+var userIDPattern = regexp.MustCompile(`\b[UCR][0-9a-f]{32}\b`)
+
+// secretParamPattern matches OAuth credentials in a query string or form
+// body, e.g. access_token=... in the token verify URL.
+var secretParamPattern = regexp.MustCompile(`\b(access_token|refresh_token|id_token|client_secret|client_assertion)=[^&\s"]*`)
+
+// secretFieldPattern matches the same credentials as JSON string fields,
+// e.g. in a token endpoint response.
+var secretFieldPattern = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|client_secret|client_assertion)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// RedactSecrets replaces OAuth tokens, client secrets, and client
+// assertions in query strings, form bodies, and JSON bodies with
+// [REDACTED].
+func RedactSecrets(s string) string {
+	s = secretParamPattern.ReplaceAllString(s, "$1=[REDACTED]")
+	return secretFieldPattern.ReplaceAllString(s, `$1"[REDACTED]"`)
+}
+
+// RedactIDs replaces LINE user, group, and room IDs in s with their first
+// character and last four digits, e.g. U…9f3c, so traces stay correlatable
+// without exposing who was contacted.
+func RedactIDs(s string) string {
+	return userIDPattern.ReplaceAllStringFunc(s, func(id string) string {
+		return id[:1] + "…" + id[len(id)-4:]
+	})
+}
+
+// SetTrace logs every HTTP request the client sends to opts.Writer: method,
+// URL, and headers, then status, latency, and X-Line-Request-Id, with
+// bodies if opts.Bodies is set. Unless opts.NoRedact is set, credentials
+// (Authorization headers and access_token, client_secret, and
+// client_assertion values in URLs and bodies) and user IDs are redacted. Call it before SetFailover so retries on a
+// secondary host are traced too.
+func (c *Client) SetTrace(opts TraceOptions) {
+	next := c.httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.httpClient.Transport = &traceTransport{next: next, opts: opts, now: func() time.Time { return c.now() }}
+}
+
+// traceTransport is an http.RoundTripper that logs requests and responses.
+type traceTransport struct {
+	next http.RoundTripper
+	opts TraceOptions
+	now  func() time.Time
+	mu   sync.Mutex // keeps each request's lines together
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if t.opts.Bodies && req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(body, traceMaxBodyLen+1))
+			_ = body.Close()
+		}
+	}
+
+	start := t.now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := t.now().Sub(start).Round(time.Millisecond)
+
+	var respBody []byte
+	if t.opts.Bodies && err == nil {
+		data, rerr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if rerr != nil {
+			// Hand the error to the caller when it reads the body
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), errReader{rerr}))
+		}
+		respBody = data
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[HTTP] --> %s %s\n", req.Method, t.redact(req.URL.String()))
+	for _, name := range sortedHeaderNames(req.Header) {
+		for _, value := range req.Header[name] {
+			if !t.opts.NoRedact && isSecretHeader(name) {
+				value = redactCredential(value)
+			}
+			fmt.Fprintf(&b, "[HTTP]     %s: %s\n", name, t.redact(value))
+		}
+	}
+	t.writeBody(&b, reqBody, req.Header.Get("Content-Type"))
+	if err != nil {
+		fmt.Fprintf(&b, "[HTTP] <-- error after %s: %v\n", elapsed, err)
+	} else {
+		fmt.Fprintf(&b, "[HTTP] <-- %s (%s)", resp.Status, elapsed)
+		if id := resp.Header.Get("X-Line-Request-Id"); id != "" {
+			fmt.Fprintf(&b, " x-line-request-id: %s", id)
+		}
+		b.WriteByte('\n')
+		t.writeBody(&b, respBody, resp.Header.Get("Content-Type"))
+	}
+
+	t.mu.Lock()
+	_, _ = io.WriteString(t.opts.Writer, b.String())
+	t.mu.Unlock()
+	return resp, err
+}
+
+func (t *traceTransport) redact(s string) string {
+	if t.opts.NoRedact {
+		return s
+	}
+	return RedactIDs(RedactSecrets(s))
+}
+
+// writeBody adds a body to the trace; binary content is summarized.
+func (t *traceTransport) writeBody(b *strings.Builder, body []byte, contentType string) {
+	if len(body) == 0 {
+		return
+	}
+	if !isTextContent(contentType) {
+		fmt.Fprintf(b, "[HTTP]     [%s, %d bytes]\n", contentType, len(body))
+		return
+	}
+	suffix := ""
+	if len(body) > traceMaxBodyLen {
+		body = body[:traceMaxBodyLen]
+		suffix = " …(truncated)"
+	}
+	fmt.Fprintf(b, "[HTTP]     %s%s\n", t.redact(string(body)), suffix)
+}
+
+func isTextContent(contentType string) bool {
+	return contentType == "" || strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "x-www-form-urlencoded")
+}
+
+func isSecretHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Cookie", "Set-Cookie":
+		return true
+	}
+	return false
+}
+
+// redactCredential keeps the scheme of an Authorization value, e.g.
+// "Bearer [REDACTED]".
+func redactCredential(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok {
+		return scheme + " [REDACTED]"
+	}
+	return "[REDACTED]"
+}
+
+func sortedHeaderNames(h http.Header) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// errReader returns err once its data is exhausted.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
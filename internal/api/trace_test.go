@@ -0,0 +1,185 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const traceUserID = "U4af4980629a1b2c3d4e5f60718293a4b"
+
+func newTraceTestClient(t *testing.T, opts TraceOptions) (*Client, *bytes.Buffer) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !bytes.Contains(body, []byte(traceUserID)) && r.Method == http.MethodPost {
+			t.Errorf("server got redacted body %s", body)
+		}
+		w.Header().Set("X-Line-Request-Id", "req-123")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"userId":"` + traceUserID + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	// Each reading of the clock is 150ms after the last
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := NewClient("secret-token", false, false)
+	client.SetBaseURL(server.URL)
+	client.SetClock(func() time.Time {
+		now = now.Add(150 * time.Millisecond)
+		return now
+	})
+	var buf bytes.Buffer
+	opts.Writer = &buf
+	client.SetTrace(opts)
+	return client, &buf
+}
+
+func TestSetTrace(t *testing.T) {
+	client, buf := newTraceTestClient(t, TraceOptions{})
+
+	body, err := client.Get(context.Background(), "/v2/bot/profile/"+traceUserID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), traceUserID) {
+		t.Errorf("caller got redacted body %s", body)
+	}
+
+	trace := buf.String()
+	for _, want := range []string{
+		"[HTTP] --> GET ",
+		"/v2/bot/profile/U…3a4b\n",
+		"Authorization: Bearer [REDACTED]",
+		"[HTTP] <-- 200 OK (150ms) x-line-request-id: req-123",
+	} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+	for _, leak := range []string{"secret-token", traceUserID} {
+		if strings.Contains(trace, leak) {
+			t.Errorf("trace leaks %q:\n%s", leak, trace)
+		}
+	}
+	if strings.Contains(trace, `"userId"`) {
+		t.Errorf("trace includes body without Bodies:\n%s", trace)
+	}
+}
+
+func TestSetTrace_Bodies(t *testing.T) {
+	client, buf := newTraceTestClient(t, TraceOptions{Bodies: true})
+
+	body, err := client.Post(context.Background(), "/v2/bot/message/push", map[string]string{"to": traceUserID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), traceUserID) {
+		t.Errorf("caller got redacted body %s", body)
+	}
+
+	trace := buf.String()
+	for _, want := range []string{`{"to":"U…3a4b"}`, `{"userId":"U…3a4b"}`} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+	if strings.Contains(trace, traceUserID) {
+		t.Errorf("trace leaks user ID:\n%s", trace)
+	}
+}
+
+func TestSetTrace_NoRedact(t *testing.T) {
+	client, buf := newTraceTestClient(t, TraceOptions{Bodies: true, NoRedact: true})
+
+	if _, err := client.Get(context.Background(), "/v2/bot/profile/"+traceUserID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trace := buf.String()
+	for _, want := range []string{"Authorization: Bearer secret-token", "/v2/bot/profile/" + traceUserID, `{"userId":"` + traceUserID + `"}`} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+}
+
+func TestSetTrace_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client := NewClient("token", false, false)
+	client.SetBaseURL(server.URL)
+	client.SetRetry(RetryOptions{})
+	var buf bytes.Buffer
+	client.SetTrace(TraceOptions{Writer: &buf})
+
+	if _, err := client.Get(context.Background(), "/v2/bot/info"); err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(buf.String(), "[HTTP] <-- error after ") {
+		t.Errorf("expected traced error, got:\n%s", buf.String())
+	}
+}
+
+func TestRedactIDs(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"/v2/bot/profile/" + traceUserID, "/v2/bot/profile/U…3a4b"},
+		{"group Cffffffffffffffffffffffffffffff01 room R0123456789abcdef0123456789abcdef", "group C…ff01 room R…cdef"},
+		{"richmenu-8dfd U123", "richmenu-8dfd U123"},
+		{"X" + traceUserID[1:], "X" + traceUserID[1:]},
+	}
+	for _, tt := range tests {
+		if got := RedactIDs(tt.in); got != tt.want {
+			t.Errorf("RedactIDs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetTrace_RedactsTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"issued-token","expires_in":2592000,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", false, false)
+	client.SetBaseURL(server.URL)
+	var buf bytes.Buffer
+	client.SetTrace(TraceOptions{Writer: &buf, Bodies: true})
+
+	if _, err := client.Get(context.Background(), "/oauth2/v2.1/verify?access_token=query-token"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trace := buf.String()
+	for _, want := range []string{"/oauth2/v2.1/verify?access_token=[REDACTED]", `"access_token":"[REDACTED]"`, `"expires_in":2592000`} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+	for _, leak := range []string{"query-token", "issued-token"} {
+		if strings.Contains(trace, leak) {
+			t.Errorf("trace leaks %q:\n%s", leak, trace)
+		}
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"grant_type=client_credentials&client_id=123&client_secret=s3cret", "grant_type=client_credentials&client_id=123&client_secret=[REDACTED]"},
+		{"client_assertion_type=jwt-bearer&client_assertion=eyJ.x.y", "client_assertion_type=jwt-bearer&client_assertion=[REDACTED]"},
+		{"access_token=abc&x=1", "access_token=[REDACTED]&x=1"},
+		{`{"access_token": "a\"b", "key_id":"k"}`, `{"access_token": "[REDACTED]", "key_id":"k"}`},
+		{`{"client_secret":"s"}`, `{"client_secret":"[REDACTED]"}`},
+		{"no secrets here", "no secrets here"},
+	}
+	for _, tt := range tests {
+		if got := RedactSecrets(tt.in); got != tt.want {
+			t.Errorf("RedactSecrets(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
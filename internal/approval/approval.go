@@ -0,0 +1,188 @@
+// Package approval signs and verifies campaign files so that sends can be
+// gated on an approver's sign-off.
+//
+// Keys are standard Ed25519 keys in PEM form (PKCS#8 private keys and PKIX
+// public keys), so they can also be generated with
+// "openssl genpkey -algorithm ed25519".
+package approval
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// payloadVersion prefixes the signed payload so the format can evolve.
+const payloadVersion = "line-campaign-approval/v1"
+
+// Signature is the contents of a campaign approval (.sig) file.
+type Signature struct {
+	// Campaign is the campaign file path, relative to the signature file
+	Campaign string `json:"campaign"`
+	// SHA256 is the hex digest of the campaign file contents
+	SHA256 string `json:"sha256"`
+	// Approver is a free-form name for the person who approved the campaign
+	Approver string `json:"approver,omitempty"`
+	// KeyID identifies the public key that verifies the signature
+	KeyID    string    `json:"keyId"`
+	SignedAt time.Time `json:"signedAt"`
+	// Signature is the base64-encoded Ed25519 signature
+	Signature string `json:"signature"`
+}
+
+// GenerateKey creates a new Ed25519 key pair.
+func GenerateKey() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	return ed25519.GenerateKey(rand.Reader)
+}
+
+// KeyID returns a short fingerprint of a public key.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Sign approves campaign data with the given private key.
+func Sign(campaign string, data []byte, approver string, key ed25519.PrivateKey, now time.Time) Signature {
+	sum := sha256.Sum256(data)
+	sig := Signature{
+		Campaign: campaign,
+		SHA256:   hex.EncodeToString(sum[:]),
+		Approver: approver,
+		KeyID:    KeyID(key.Public().(ed25519.PublicKey)),
+		SignedAt: now.UTC().Truncate(time.Second),
+	}
+	sig.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, sig.payload()))
+	return sig
+}
+
+// Verify checks that sig is a valid approval of data by one of the trusted
+// keys.
+func Verify(sig Signature, data []byte, trusted []ed25519.PublicKey) error {
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != sig.SHA256 {
+		return errors.New("campaign file has changed since it was approved")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	for _, pub := range trusted {
+		if KeyID(pub) != sig.KeyID {
+			continue
+		}
+		if !ed25519.Verify(pub, sig.payload(), raw) {
+			return errors.New("signature verification failed")
+		}
+		return nil
+	}
+	return fmt.Errorf("campaign was signed by key %s, which is not a trusted key", sig.KeyID)
+}
+
+// payload returns the bytes covered by the signature.
+func (s Signature) payload() []byte {
+	return fmt.Appendf(nil, "%s\n%s\n%s\n%s\n%s\n",
+		payloadVersion, s.SHA256, s.Approver, s.KeyID, s.SignedAt.UTC().Format(time.RFC3339))
+}
+
+// ReadSignature reads a signature file.
+func ReadSignature(path string) (Signature, error) {
+	var sig Signature
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sig, fmt.Errorf("failed to read approval file: %w", err)
+	}
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return sig, fmt.Errorf("invalid approval file: %w", err)
+	}
+	if sig.SHA256 == "" || sig.KeyID == "" || sig.Signature == "" {
+		return sig, errors.New("invalid approval file: missing sha256, keyId, or signature")
+	}
+	return sig, nil
+}
+
+// WriteSignature writes a signature file.
+func WriteSignature(path string, sig Signature) error {
+	data, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// ReadPrivateKey reads a PEM-encoded PKCS#8 Ed25519 private key.
+func ReadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key %s: %w", path, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an Ed25519 key", path)
+	}
+	return priv, nil
+}
+
+// ReadPublicKey reads a PEM-encoded PKIX Ed25519 public key.
+func ReadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %w", path, err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return pub, nil
+}
+
+// WriteKeyPair writes a key pair as PEM files. The private key file is only
+// readable by the current user.
+func WriteKeyPair(privPath, pubPath string, pub ed25519.PublicKey, priv ed25519.PrivateKey) error {
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}), 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0644); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	return nil
+}
+
+func readPEM(path, blockType string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s does not contain a PEM %q block", path, blockType)
+	}
+	return block, nil
+}
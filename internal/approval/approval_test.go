@@ -0,0 +1,91 @@
+package approval
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	pub, priv, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("name: launch\n")
+	sig := Sign("campaign.yaml", data, "Jane", priv, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+
+	if err := Verify(sig, data, []ed25519.PublicKey{otherPub, pub}); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		sig     Signature
+		data    []byte
+		trusted []ed25519.PublicKey
+		wantErr string
+	}{
+		{"modified campaign", sig, []byte("name: launch2\n"), []ed25519.PublicKey{pub}, "has changed"},
+		{"untrusted key", sig, data, []ed25519.PublicKey{otherPub}, "not a trusted key"},
+		{"tampered approver", func() Signature { s := sig; s.Approver = "Mallory"; return s }(), data, []ed25519.PublicKey{pub}, "verification failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.sig, tt.data, tt.trusted)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyAndSignatureFiles(t *testing.T) {
+	dir := t.TempDir()
+	privPath := filepath.Join(dir, "team.key")
+	pubPath := filepath.Join(dir, "team.pub")
+
+	pub, priv, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteKeyPair(privPath, pubPath, pub, priv); err != nil {
+		t.Fatalf("WriteKeyPair() error = %v", err)
+	}
+
+	readPriv, err := ReadPrivateKey(privPath)
+	if err != nil {
+		t.Fatalf("ReadPrivateKey() error = %v", err)
+	}
+	readPub, err := ReadPublicKey(pubPath)
+	if err != nil {
+		t.Fatalf("ReadPublicKey() error = %v", err)
+	}
+	if !readPub.Equal(pub) || !readPriv.Equal(priv) {
+		t.Fatal("keys did not round-trip")
+	}
+
+	// A public key file is not accepted as a private key
+	if _, err := ReadPrivateKey(pubPath); err == nil {
+		t.Error("expected error reading public key as private key")
+	}
+
+	data := []byte("sends: []\n")
+	sigPath := filepath.Join(dir, "campaign.sig")
+	if err := WriteSignature(sigPath, Sign("campaign.yaml", data, "", readPriv, time.Now())); err != nil {
+		t.Fatalf("WriteSignature() error = %v", err)
+	}
+	sig, err := ReadSignature(sigPath)
+	if err != nil {
+		t.Fatalf("ReadSignature() error = %v", err)
+	}
+	if err := Verify(sig, data, []ed25519.PublicKey{readPub}); err != nil {
+		t.Errorf("Verify() after round-trip error = %v", err)
+	}
+}
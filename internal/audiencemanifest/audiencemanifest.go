@@ -0,0 +1,190 @@
+// Package audiencemanifest remembers which user IDs have been uploaded to
+// each audience group, so repeat uploads can be diffed against history.
+//
+// Manifests are kept per account and audience group as plain text files with
+// one user ID per line.
+package audiencemanifest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// Dir returns the directory holding an account's manifests.
+func Dir(account string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audience-manifests", url.PathEscape(account)), nil
+}
+
+// Path returns the manifest file for one audience group.
+func Path(account string, audienceGroupID int64) (string, error) {
+	dir, err := Dir(account)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, strconv.FormatInt(audienceGroupID, 10)+".txt"), nil
+}
+
+// Load returns the set of user IDs previously uploaded to an audience group.
+// A missing manifest yields an empty set.
+func Load(account string, audienceGroupID int64) (map[string]bool, error) {
+	path, err := Path(account, audienceGroupID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audience manifest: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	ids := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			ids[id] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audience manifest: %w", err)
+	}
+	return ids, nil
+}
+
+// Record appends the user IDs not already in the audience group's manifest
+// and returns how many were new.
+func Record(account string, audienceGroupID int64, userIDs []string) (int, error) {
+	rec, err := NewRecorder(account, audienceGroupID)
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if err := rec.Add(id); err != nil {
+			_, _ = rec.Close()
+			return 0, err
+		}
+	}
+	return rec.Close()
+}
+
+// Recorder appends user IDs to an audience group's manifest as they are
+// streamed in, skipping IDs recorded by earlier uploads. Only the existing
+// manifest is held in memory; IDs added through a Recorder are not
+// remembered, so callers must not add the same ID twice.
+type Recorder struct {
+	path  string
+	known map[string]bool
+	f     *os.File
+	w     *bufio.Writer
+	added int
+}
+
+// NewRecorder returns a Recorder for an audience group's manifest.
+func NewRecorder(account string, audienceGroupID int64) (*Recorder, error) {
+	known, err := Load(account, audienceGroupID)
+	if err != nil {
+		return nil, err
+	}
+	path, err := Path(account, audienceGroupID)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{path: path, known: known}, nil
+}
+
+// Add records id unless an earlier upload already did.
+func (r *Recorder) Add(id string) error {
+	if r.known[id] {
+		return nil
+	}
+	if r.w == nil {
+		if err := os.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+			return fmt.Errorf("failed to create audience manifest directory: %w", err)
+		}
+		f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open audience manifest: %w", err)
+		}
+		r.f = f
+		r.w = bufio.NewWriter(f)
+	}
+	if _, err := r.w.WriteString(id + "\n"); err != nil {
+		return fmt.Errorf("failed to write audience manifest: %w", err)
+	}
+	r.added++
+	return nil
+}
+
+// Close flushes the manifest and returns how many IDs were added.
+func (r *Recorder) Close() (int, error) {
+	if r.w == nil {
+		return 0, nil
+	}
+	err := r.w.Flush()
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write audience manifest: %w", err)
+	}
+	return r.added, nil
+}
+
+// CountAccount returns how many audience groups have a manifest for account.
+func CountAccount(account string) (int, error) {
+	dir, err := Dir(account)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audience manifests: %w", err)
+	}
+	n := 0
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".txt") {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// RemoveAccount deletes every manifest for account and returns how many
+// were removed.
+func RemoveAccount(account string) (int, error) {
+	n, err := CountAccount(account)
+	if err != nil {
+		return 0, err
+	}
+	dir, err := Dir(account)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to remove audience manifests: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,89 @@
+package audiencemanifest
+
+import "testing"
+
+func TestRecordAndLoad(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	added, err := Record("prod", 42, []string{"U1", "U2", "U2", " "})
+	if err != nil || added != 2 {
+		t.Fatalf("Record() = %d, %v; want 2, nil", added, err)
+	}
+	added, err = Record("prod", 42, []string{"U2", "U3"})
+	if err != nil || added != 1 {
+		t.Fatalf("Record() = %d, %v; want 1, nil", added, err)
+	}
+
+	ids, err := Load("prod", 42)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(ids) != 3 || !ids["U1"] || !ids["U2"] || !ids["U3"] {
+		t.Errorf("Load() = %v, want U1, U2, U3", ids)
+	}
+
+	// Manifests are separate per audience and per account
+	if ids, _ := Load("prod", 43); len(ids) != 0 {
+		t.Errorf("Load(other audience) = %v, want empty", ids)
+	}
+	if ids, _ := Load("staging", 42); len(ids) != 0 {
+		t.Errorf("Load(other account) = %v, want empty", ids)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if _, err := Record("prod", 42, []string{"U1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := NewRecorder("prod", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"U1", "U2", "U3"} {
+		if err := rec.Add(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if added, err := rec.Close(); err != nil || added != 2 {
+		t.Fatalf("Close() = %d, %v; want 2, nil", added, err)
+	}
+	if ids, _ := Load("prod", 42); len(ids) != 3 {
+		t.Errorf("Load() = %v, want U1, U2, U3", ids)
+	}
+
+	// Nothing new creates no manifest
+	rec, err = NewRecorder("prod", 43)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added, err := rec.Close(); err != nil || added != 0 {
+		t.Errorf("Close() = %d, %v; want 0, nil", added, err)
+	}
+	if n, _ := CountAccount("prod"); n != 1 {
+		t.Errorf("expected 1 manifest, got %d", n)
+	}
+}
+
+func TestRemoveAccount(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	_, _ = Record("prod", 1, []string{"U1"})
+	_, _ = Record("prod", 2, []string{"U2"})
+	_, _ = Record("staging", 1, []string{"U3"})
+
+	if n, err := CountAccount("prod"); err != nil || n != 2 {
+		t.Fatalf("CountAccount() = %d, %v; want 2, nil", n, err)
+	}
+	if n, err := RemoveAccount("prod"); err != nil || n != 2 {
+		t.Fatalf("RemoveAccount() = %d, %v; want 2, nil", n, err)
+	}
+	if n, _ := CountAccount("prod"); n != 0 {
+		t.Errorf("CountAccount() after remove = %d, want 0", n)
+	}
+	if ids, _ := Load("staging", 1); !ids["U3"] {
+		t.Error("expected other account's manifest to be kept")
+	}
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/jsonl"
+)
+
+// Entry is a single audit log record. Entries are appended to the log as
+// one JSON object per line.
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Account string         `json:"account,omitempty"`
+	Action  string         `json:"action"`
+	Reason  string         `json:"reason,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+var store = jsonl.Store[Entry]{
+	Name:    "audit log",
+	File:    "audit.log",
+	Account: func(e Entry) string { return e.Account },
+}
+
+// Path returns the location of the audit log file.
+func Path() (string, error) {
+	return store.Path()
+}
+
+// Append writes an entry to the audit log, creating the file if needed.
+// A zero Time is replaced with the current UTC time.
+func Append(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	return store.Append(entry)
+}
+
+// CountAccount returns the number of entries recorded for account.
+func CountAccount(account string) (int, error) {
+	return store.CountAccount(account)
+}
+
+// RemoveAccount rewrites the audit log without the entries recorded for
+// account and returns how many were removed.
+func RemoveAccount(account string) (int, error) {
+	return store.RemoveAccount(account)
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestAppend_WritesJSONLines(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if err := Append(Entry{Action: "freeze.override", Reason: "launch"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Append(Entry{Action: "freeze.override", Account: "prod"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid JSON line: %v", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Reason != "launch" || entries[0].Time.IsZero() {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Account != "prod" {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestRemoveAccount(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	for _, account := range []string{"a", "b", "a"} {
+		if err := Append(Entry{Account: account, Action: "campaign.send"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := CountAccount("a"); err != nil || n != 2 {
+		t.Fatalf("CountAccount() = %d, %v; want 2", n, err)
+	}
+	removed, err := RemoveAccount("a")
+	if err != nil || removed != 2 {
+		t.Fatalf("RemoveAccount() = %d, %v; want 2", removed, err)
+	}
+	if n, _ := CountAccount("a"); n != 0 {
+		t.Errorf("expected no entries for a, got %d", n)
+	}
+	if n, _ := CountAccount("b"); n != 1 {
+		t.Errorf("expected entry for b to be kept, got %d", n)
+	}
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+)
+
+// NewClient returns an API client for stored credentials. Accounts saved
+// with a channel ID and secret instead of a token get stateless tokens,
+// issued on first use and refreshed when they expire or are rejected.
+func NewClient(creds secrets.Credentials, debug bool, dryRun bool) *api.Client {
+	if !creds.UsesChannelSecret() {
+		return api.NewClient(creds.ChannelAccessToken, debug, dryRun)
+	}
+	issuer := api.NewClient("", debug, false)
+	return api.NewClientWithTokenProvider(api.NewStatelessTokenProvider(issuer, creds.ChannelID, creds.ChannelSecret), debug, dryRun)
+}
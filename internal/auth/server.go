@@ -3,17 +3,21 @@ package auth
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"net"
 	"net/http"
+	"os"
 	"os/exec"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/salmonumbrella/line-official-cli/internal/api"
 	"github.com/salmonumbrella/line-official-cli/internal/secrets"
 )
 
@@ -30,43 +34,106 @@ type SetupServer struct {
 	pendingResult *SetupResult
 	csrfToken     string
 	store         secrets.Store
+	addr          string
+	out           io.Writer
+
+	// code is the one-time code of a headless setup, which a browser must
+	// enter before it is given a session; empty when the server opens a
+	// browser itself
+	mu           sync.Mutex
+	code         string
+	codeAttempts int
+	session      string
 }
 
+// maxCodeAttempts is how many wrong one-time codes a headless setup
+// accepts before it refuses every code.
+const maxCodeAttempts = 5
+
+// sessionCookie carries the session of a browser that entered the
+// one-time code.
+const sessionCookie = "line_setup_session"
+
 func NewSetupServer(store secrets.Store) (*SetupServer, error) {
-	tokenBytes := make([]byte, 32)
-	if _, err := rand.Read(tokenBytes); err != nil {
-		return nil, fmt.Errorf("failed to generate CSRF token: %w", err)
+	csrfToken, err := NewCSRFToken()
+	if err != nil {
+		return nil, err
 	}
 
 	return &SetupServer{
 		result:    make(chan SetupResult, 1),
 		shutdown:  make(chan struct{}),
-		csrfToken: hex.EncodeToString(tokenBytes),
+		csrfToken: csrfToken,
 		store:     store,
+		addr:      "127.0.0.1:0",
+		out:       os.Stdout,
 	}, nil
 }
 
-func (s *SetupServer) Start(ctx context.Context) (*SetupResult, error) {
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
+// NewHeadlessSetupServer returns a setup server for machines without a
+// browser, such as over SSH. It listens on addr, prints its URL and a
+// one-time code to out instead of opening a browser, and serves the setup
+// page only to a browser that enters the code.
+func NewHeadlessSetupServer(store secrets.Store, addr string, out io.Writer) (*SetupServer, error) {
+	s, err := NewSetupServer(store)
 	if err != nil {
-		return nil, fmt.Errorf("failed to start server: %w", err)
+		return nil, err
 	}
+	s.code, err = newOneTimeCode()
+	if err != nil {
+		return nil, err
+	}
+	if addr != "" {
+		s.addr = addr
+	}
+	s.out = out
+	return s, nil
+}
 
-	port := listener.Addr().(*net.TCPAddr).Port
-	baseURL := fmt.Sprintf("http://127.0.0.1:%d", port)
+// codeAlphabet leaves out letters and digits that are easily confused.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
 
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", s.handleSetup)
-	mux.HandleFunc("/validate", s.handleValidate)
-	mux.HandleFunc("/submit", s.handleSubmit)
-	mux.HandleFunc("/success", s.handleSuccess)
-	mux.HandleFunc("/complete", s.handleComplete)
-	mux.HandleFunc("/accounts", s.handleListAccounts)
-	mux.HandleFunc("/set-primary", s.handleSetPrimary)
-	mux.HandleFunc("/remove-account", s.handleRemoveAccount)
+// newOneTimeCode returns a random code like "K7QX-M2PA".
+func newOneTimeCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate one-time code: %w", err)
+	}
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b[:4]) + "-" + string(b[4:]), nil
+}
+
+// normalizeCode makes a typed code comparable: case, spaces, and the
+// dash do not matter.
+func normalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// NewCSRFToken returns a random token for the X-CSRF-Token header that
+// state-changing requests to a local server must carry.
+func NewCSRFToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate CSRF token: %w", err)
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+func (s *SetupServer) Start(ctx context.Context) (*SetupResult, error) {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start server: %w", err)
+	}
 
 	server := &http.Server{
-		Handler:      mux,
+		Handler:      s.handler(),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
@@ -75,11 +142,17 @@ func (s *SetupServer) Start(ctx context.Context) (*SetupResult, error) {
 		_ = server.Serve(listener)
 	}()
 
-	fmt.Printf("Open this URL in your browser to authenticate:\n  %s\n", baseURL)
-	fmt.Println("Attempting to open browser automatically...")
-	if err := openBrowser(baseURL); err != nil {
-		fmt.Printf("Could not open browser automatically: %v\n", err)
-		fmt.Println("Please open the URL manually in your browser.")
+	addr := listener.Addr().(*net.TCPAddr)
+	if s.code != "" {
+		s.printHeadless(addr)
+	} else {
+		baseURL := fmt.Sprintf("http://127.0.0.1:%d", addr.Port)
+		_, _ = fmt.Fprintf(s.out, "Open this URL in your browser to authenticate:\n  %s\n", baseURL)
+		_, _ = fmt.Fprintln(s.out, "Attempting to open browser automatically...")
+		if err := openBrowser(baseURL); err != nil {
+			_, _ = fmt.Fprintf(s.out, "Could not open browser automatically: %v\n", err)
+			_, _ = fmt.Fprintln(s.out, "Please open the URL manually in your browser.")
+		}
 	}
 
 	select {
@@ -110,6 +183,125 @@ func (s *SetupServer) Start(ctx context.Context) (*SetupResult, error) {
 	}
 }
 
+// handler routes the setup pages, behind the one-time code of a headless
+// setup.
+func (s *SetupServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleSetup)
+	mux.HandleFunc("/validate", s.handleValidate)
+	mux.HandleFunc("/submit", s.handleSubmit)
+	mux.HandleFunc("/success", s.handleSuccess)
+	mux.HandleFunc("/complete", s.handleComplete)
+	mux.HandleFunc("/accounts", s.handleListAccounts)
+	mux.HandleFunc("/set-primary", s.handleSetPrimary)
+	mux.HandleFunc("/remove-account", s.handleRemoveAccount)
+	if s.code == "" {
+		return mux
+	}
+	return s.requireCode(mux)
+}
+
+// printHeadless prints where to open the setup page of a headless setup
+// and its one-time code.
+func (s *SetupServer) printHeadless(addr *net.TCPAddr) {
+	host := addr.IP.String()
+	if addr.IP.IsUnspecified() {
+		if name, err := os.Hostname(); err == nil {
+			host = name
+		}
+	}
+	_, _ = fmt.Fprintf(s.out, "Open this URL in a browser on any machine:\n  http://%s\n", net.JoinHostPort(host, fmt.Sprint(addr.Port)))
+	_, _ = fmt.Fprintf(s.out, "and enter the one-time code: %s\n", s.code)
+	if addr.IP.IsLoopback() {
+		_, _ = fmt.Fprintf(s.out, "\nThe server only accepts local connections. Over SSH, forward its port first:\n  ssh -L %d:%s:%d <this host>\n", addr.Port, host, addr.Port)
+	}
+	_, _ = fmt.Fprintln(s.out, "\nWaiting for the form to be submitted (Ctrl+C to cancel)...")
+}
+
+// requireCode serves next only to a browser with the session given for
+// the one-time code, and the code form to any other.
+func (s *SetupServer) requireCode(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/code" {
+			s.handleCode(w, r)
+			return
+		}
+		if s.hasSession(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method == http.MethodGet && r.URL.Path == "/" {
+			renderCodePage(w, http.StatusOK, "")
+			return
+		}
+		http.Error(w, "Enter the one-time code first", http.StatusForbidden)
+	})
+}
+
+// hasSession reports whether r comes from the browser that entered the
+// one-time code.
+func (s *SetupServer) hasSession(r *http.Request) bool {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.session != "" && subtle.ConstantTimeCompare([]byte(c.Value), []byte(s.session)) == 1
+}
+
+// handleCode checks a one-time code and gives the browser that entered it
+// a session. The code works once, and stops working after
+// maxCodeAttempts wrong codes.
+func (s *SetupServer) handleCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.session != "":
+		renderCodePage(w, http.StatusForbidden, "The code has already been used. Run line auth login --no-browser again for a new one.")
+		return
+	case s.codeAttempts >= maxCodeAttempts:
+		renderCodePage(w, http.StatusForbidden, "Too many wrong codes. Run line auth login --no-browser again for a new one.")
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(normalizeCode(r.FormValue("code"))), []byte(normalizeCode(s.code))) != 1 {
+		s.codeAttempts++
+		renderCodePage(w, http.StatusForbidden, "Wrong code. Check the terminal and try again.")
+		return
+	}
+
+	session, err := NewCSRFToken()
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	s.session = session
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    session,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func renderCodePage(w http.ResponseWriter, status int, errMsg string) {
+	tmpl, err := template.New("code").Parse(codeTemplate)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = tmpl.Execute(w, map[string]string{"Error": errMsg})
+}
+
 func (s *SetupServer) handleSetup(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
 		http.NotFound(w, r)
@@ -130,6 +322,30 @@ func (s *SetupServer) handleSetup(w http.ResponseWriter, r *http.Request) {
 	_ = tmpl.Execute(w, data)
 }
 
+// setupRequest is the body of a validate or submit request. Either an
+// access token or a channel ID and secret must be given.
+type setupRequest struct {
+	AccountName   string `json:"account_name"`
+	AccessToken   string `json:"access_token"`
+	ChannelID     string `json:"channel_id"`
+	ChannelSecret string `json:"channel_secret"`
+}
+
+// credentials returns the credentials to test and store, or a message
+// describing what is missing.
+func (r setupRequest) credentials() (secrets.Credentials, string) {
+	if r.AccessToken != "" {
+		return secrets.Credentials{ChannelAccessToken: r.AccessToken}, ""
+	}
+	if r.ChannelID == "" && r.ChannelSecret == "" {
+		return secrets.Credentials{}, "Access token is required"
+	}
+	if r.ChannelID == "" || r.ChannelSecret == "" {
+		return secrets.Credentials{}, "Channel ID and channel secret are both required"
+	}
+	return secrets.Credentials{ChannelID: r.ChannelID, ChannelSecret: r.ChannelSecret}, ""
+}
+
 // handleValidate tests credentials without saving
 func (s *SetupServer) handleValidate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -143,39 +359,37 @@ func (s *SetupServer) handleValidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		AccountName string `json:"account_name"`
-		AccessToken string `json:"access_token"`
-	}
+	var req setupRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]any{
+		WriteJSON(w, http.StatusBadRequest, map[string]any{
 			"success": false,
 			"error":   "Invalid request body",
 		})
 		return
 	}
 
-	if req.AccessToken == "" {
-		writeJSON(w, http.StatusOK, map[string]any{
+	creds, errMsg := req.credentials()
+	if errMsg != "" {
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"success": false,
-			"error":   "Access token is required",
+			"error":   errMsg,
 		})
 		return
 	}
 
 	// Test the credentials by getting bot info
-	client := api.NewClient(req.AccessToken, false, false)
+	client := NewClient(creds, false, false)
 	botInfo, err := client.GetBotInfo(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusOK, map[string]any{
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"success": false,
 			"error":   fmt.Sprintf("Connection failed: %v", err),
 		})
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	WriteJSON(w, http.StatusOK, map[string]any{
 		"success":  true,
 		"message":  "Connection successful!",
 		"bot_name": botInfo.DisplayName,
@@ -196,23 +410,21 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		AccountName string `json:"account_name"`
-		AccessToken string `json:"access_token"`
-	}
+	var req setupRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]any{
+		WriteJSON(w, http.StatusBadRequest, map[string]any{
 			"success": false,
 			"error":   "Invalid request body",
 		})
 		return
 	}
 
-	if req.AccessToken == "" {
-		writeJSON(w, http.StatusOK, map[string]any{
+	creds, errMsg := req.credentials()
+	if errMsg != "" {
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"success": false,
-			"error":   "Access token is required",
+			"error":   errMsg,
 		})
 		return
 	}
@@ -222,10 +434,10 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate first by getting bot info
-	client := api.NewClient(req.AccessToken, false, false)
+	client := NewClient(creds, false, false)
 	botInfo, err := client.GetBotInfo(r.Context())
 	if err != nil {
-		writeJSON(w, http.StatusOK, map[string]any{
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"success": false,
 			"error":   fmt.Sprintf("Connection failed: %v", err),
 		})
@@ -233,11 +445,9 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save to keychain
-	err = s.store.Set(req.AccountName, secrets.Credentials{
-		ChannelAccessToken: req.AccessToken,
-	}, botInfo.DisplayName)
+	err = s.store.Set(req.AccountName, creds, botInfo.DisplayName)
 	if err != nil {
-		writeJSON(w, http.StatusOK, map[string]any{
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"success": false,
 			"error":   fmt.Sprintf("Failed to save credentials: %v", err),
 		})
@@ -251,7 +461,7 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 		BotName:            botInfo.DisplayName,
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	WriteJSON(w, http.StatusOK, map[string]any{
 		"success":      true,
 		"account_name": req.AccountName,
 		"bot_name":     botInfo.DisplayName,
@@ -280,7 +490,7 @@ func (s *SetupServer) handleComplete(w http.ResponseWriter, r *http.Request) {
 		s.result <- *s.pendingResult
 	}
 	close(s.shutdown)
-	writeJSON(w, http.StatusOK, map[string]any{"success": true})
+	WriteJSON(w, http.StatusOK, map[string]any{"success": true})
 }
 
 func (s *SetupServer) handleListAccounts(w http.ResponseWriter, r *http.Request) {
@@ -291,7 +501,7 @@ func (s *SetupServer) handleListAccounts(w http.ResponseWriter, r *http.Request)
 
 	accounts, err := s.store.List()
 	if err != nil {
-		writeJSON(w, http.StatusOK, map[string]any{
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"accounts": []any{},
 		})
 		return
@@ -307,7 +517,7 @@ func (s *SetupServer) handleListAccounts(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	WriteJSON(w, http.StatusOK, map[string]any{
 		"accounts": result,
 	})
 }
@@ -328,7 +538,7 @@ func (s *SetupServer) handleSetPrimary(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]any{
+		WriteJSON(w, http.StatusBadRequest, map[string]any{
 			"success": false,
 			"error":   "Invalid request",
 		})
@@ -336,7 +546,7 @@ func (s *SetupServer) handleSetPrimary(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]any{
+		WriteJSON(w, http.StatusBadRequest, map[string]any{
 			"success": false,
 			"error":   "Account name is required",
 		})
@@ -344,14 +554,14 @@ func (s *SetupServer) handleSetPrimary(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.store.SetPrimary(req.Name); err != nil {
-		writeJSON(w, http.StatusOK, map[string]any{
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"success": false,
 			"error":   fmt.Sprintf("Failed to set primary: %v", err),
 		})
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	WriteJSON(w, http.StatusOK, map[string]any{
 		"success": true,
 	})
 }
@@ -372,7 +582,7 @@ func (s *SetupServer) handleRemoveAccount(w http.ResponseWriter, r *http.Request
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]any{
+		WriteJSON(w, http.StatusBadRequest, map[string]any{
 			"success": false,
 			"error":   "Invalid request",
 		})
@@ -380,7 +590,7 @@ func (s *SetupServer) handleRemoveAccount(w http.ResponseWriter, r *http.Request
 	}
 
 	if req.Name == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]any{
+		WriteJSON(w, http.StatusBadRequest, map[string]any{
 			"success": false,
 			"error":   "Account name is required",
 		})
@@ -388,20 +598,20 @@ func (s *SetupServer) handleRemoveAccount(w http.ResponseWriter, r *http.Request
 	}
 
 	if err := s.store.Delete(req.Name); err != nil {
-		writeJSON(w, http.StatusOK, map[string]any{
+		WriteJSON(w, http.StatusOK, map[string]any{
 			"success": false,
 			"error":   fmt.Sprintf("Failed to remove account: %v", err),
 		})
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
+	WriteJSON(w, http.StatusOK, map[string]any{
 		"success": true,
 	})
 }
 
-// writeJSON writes a JSON response
-func writeJSON(w http.ResponseWriter, status int, data any) {
+// WriteJSON writes a JSON response with the given status
+func WriteJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
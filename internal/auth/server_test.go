@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNewOneTimeCode(t *testing.T) {
+	code, err := newOneTimeCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}-[A-HJ-NP-Z2-9]{4}$`).MatchString(code) {
+		t.Errorf("unexpected code %q", code)
+	}
+	if normalizeCode(" k7qx m2pa ") != "K7QXM2PA" {
+		t.Errorf("unexpected normalized code %q", normalizeCode(" k7qx m2pa "))
+	}
+}
+
+func TestHeadlessSetupServer_RequiresCode(t *testing.T) {
+	var out strings.Builder
+	s, err := NewHeadlessSetupServer(nil, "", &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(s.handler())
+	defer server.Close()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	get := func(cookie *http.Cookie) string {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	postCode := func(code string) *http.Response {
+		resp, err := client.PostForm(server.URL+"/code", url.Values{"code": {code}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		return resp
+	}
+
+	// Without the code, the setup page and API are not served
+	resp, err := client.Post(server.URL+"/submit", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for /submit without a session, got %d", resp.StatusCode)
+	}
+
+	if resp := postCode("AAAA-AAAA"); resp.StatusCode != http.StatusForbidden || len(resp.Cookies()) != 0 {
+		t.Errorf("expected a wrong code to be refused, got %d", resp.StatusCode)
+	}
+
+	resp = postCode(strings.ToLower(s.code))
+	if resp.StatusCode != http.StatusSeeOther || len(resp.Cookies()) != 1 {
+		t.Fatalf("expected the code to give a session, got %d", resp.StatusCode)
+	}
+	session := resp.Cookies()[0]
+	if page := get(session); !strings.Contains(page, "Add LINE Account") {
+		t.Errorf("expected the setup page with a session, got:\n%s", page)
+	}
+	if page := get(&http.Cookie{Name: sessionCookie, Value: "forged"}); !strings.Contains(page, "Enter the one-time code") {
+		t.Errorf("expected the code page for a forged session, got:\n%s", page)
+	}
+
+	// The code works once
+	if resp := postCode(s.code); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected a used code to be refused, got %d", resp.StatusCode)
+	}
+}
+
+func TestHeadlessSetupServer_LimitsAttempts(t *testing.T) {
+	s, err := NewHeadlessSetupServer(nil, "", new(strings.Builder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(s.handler())
+	defer server.Close()
+
+	for i := 0; i <= maxCodeAttempts; i++ {
+		resp, err := http.PostForm(server.URL+"/code", url.Values{"code": {"wrong"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	resp, err := http.PostForm(server.URL+"/code", url.Values{"code": {s.code}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected the right code to be refused after too many wrong ones, got %d", resp.StatusCode)
+	}
+}
@@ -820,6 +820,19 @@ const setupTemplate = `<!DOCTYPE html>
                     </div>
 
                     <div class="form-group">
+                        <div class="label-row">
+                            <label for="credentialType">Credentials</label>
+                        </div>
+                        <div class="select-wrapper">
+                            <select id="credentialType" name="credential_type">
+                                <option value="token">Long-lived channel access token</option>
+                                <option value="secret">Channel ID + secret (stateless tokens)</option>
+                            </select>
+                        </div>
+                        <div class="input-hint">With a channel secret, a short-lived token is issued automatically for each session</div>
+                    </div>
+
+                    <div id="tokenFields" class="form-group">
                         <div class="label-row">
                             <label for="accessToken">Channel Access Token</label>
                             <span class="badge">Required</span>
@@ -830,13 +843,44 @@ const setupTemplate = `<!DOCTYPE html>
                             name="access_token"
                             class="mono"
                             placeholder="Paste your long-lived channel access token"
-                            required
                         >
                         <div class="input-hint">
                             Found in <a href="https://developers.line.biz/console/" target="_blank" rel="noopener noreferrer">LINE Developers Console</a> &rarr; Messaging API tab
                         </div>
                     </div>
 
+                    <div id="secretFields" class="hidden">
+                        <div class="form-group">
+                            <div class="label-row">
+                                <label for="channelId">Channel ID</label>
+                                <span class="badge">Required</span>
+                            </div>
+                            <input
+                                type="text"
+                                id="channelId"
+                                name="channel_id"
+                                class="mono"
+                                placeholder="e.g., 1234567890"
+                            >
+                        </div>
+                        <div class="form-group">
+                            <div class="label-row">
+                                <label for="channelSecret">Channel Secret</label>
+                                <span class="badge">Required</span>
+                            </div>
+                            <input
+                                type="password"
+                                id="channelSecret"
+                                name="channel_secret"
+                                class="mono"
+                                placeholder="Paste your channel secret"
+                            >
+                            <div class="input-hint">
+                                Found in <a href="https://developers.line.biz/console/" target="_blank" rel="noopener noreferrer">LINE Developers Console</a> &rarr; Basic settings tab
+                            </div>
+                        </div>
+                    </div>
+
                     <div class="btn-group">
                         <button type="button" id="testBtn" class="btn-secondary">Test Connection</button>
                         <button type="submit" id="submitBtn" class="btn-primary">Save & Connect</button>
@@ -1027,19 +1071,48 @@ const setupTemplate = `<!DOCTYPE html>
             status.className = 'status';
         }
 
+        const credentialType = document.getElementById('credentialType');
+        credentialType.addEventListener('change', function() {
+            var useSecret = credentialType.value === 'secret';
+            document.getElementById('tokenFields').classList.toggle('hidden', useSecret);
+            document.getElementById('secretFields').classList.toggle('hidden', !useSecret);
+            hideStatus();
+        });
+
         function getFormData() {
-            return {
+            var data = {
                 account_name: document.getElementById('accountName').value.trim() || 'default',
-                access_token: document.getElementById('accessToken').value.trim(),
                 api_type: document.getElementById('apiType').value
             };
+            if (credentialType.value === 'secret') {
+                data.channel_id = document.getElementById('channelId').value.trim();
+                data.channel_secret = document.getElementById('channelSecret').value.trim();
+            } else {
+                data.access_token = document.getElementById('accessToken').value.trim();
+            }
+            return data;
+        }
+
+        // missingCredentials returns a message if the form is incomplete
+        function missingCredentials(data) {
+            if (credentialType.value === 'secret') {
+                if (!data.channel_id || !data.channel_secret) {
+                    return 'Please enter your channel ID and channel secret';
+                }
+                return '';
+            }
+            if (!data.access_token) {
+                return 'Please enter your channel access token';
+            }
+            return '';
         }
 
         testBtn.addEventListener('click', async function() {
             var data = getFormData();
 
-            if (!data.access_token) {
-                showStatus('error', 'Please enter your channel access token');
+            var missing = missingCredentials(data);
+            if (missing) {
+                showStatus('error', missing);
                 return;
             }
 
@@ -1077,8 +1150,9 @@ const setupTemplate = `<!DOCTYPE html>
 
             var data = getFormData();
 
-            if (!data.access_token) {
-                showStatus('error', 'Please enter your channel access token');
+            var missing = missingCredentials(data);
+            if (missing) {
+                showStatus('error', missing);
                 return;
             }
 
@@ -1441,3 +1515,81 @@ const successTemplate = `<!DOCTYPE html>
     </script>
 </body>
 </html>`
+
+// codeTemplate asks for the one-time code of a headless setup before the
+// setup page is shown.
+const codeTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>LINE CLI - Enter Code</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, sans-serif;
+            background: #0F0F0F;
+            color: #FFFFFF;
+            min-height: 100vh;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+            padding: 2rem 1.5rem;
+        }
+        .card {
+            background: #1A1A1A;
+            border: 1px solid #333333;
+            border-radius: 16px;
+            padding: 2rem;
+            width: 100%;
+            max-width: 380px;
+        }
+        h1 { font-size: 1.25rem; margin-bottom: 0.5rem; }
+        p { color: #B3B3B3; font-size: 0.875rem; margin-bottom: 1.5rem; }
+        input {
+            width: 100%;
+            padding: 0.75rem 1rem;
+            background: #252525;
+            border: 1px solid #333333;
+            border-radius: 10px;
+            color: #FFFFFF;
+            font-family: 'JetBrains Mono', monospace;
+            font-size: 1.25rem;
+            letter-spacing: 0.15em;
+            text-align: center;
+            text-transform: uppercase;
+        }
+        input:focus { outline: none; border-color: #06C755; }
+        button {
+            width: 100%;
+            margin-top: 1rem;
+            padding: 0.75rem 1rem;
+            background: #06C755;
+            border: none;
+            border-radius: 10px;
+            color: #FFFFFF;
+            font-size: 0.9375rem;
+            font-weight: 600;
+            cursor: pointer;
+        }
+        button:hover { background: #05A847; }
+        .error {
+            margin-top: 1rem;
+            padding: 0.75rem 1rem;
+            border-radius: 10px;
+            background: rgba(239, 68, 68, 0.15);
+            color: #EF4444;
+            font-size: 0.875rem;
+        }
+    </style>
+</head>
+<body>
+    <form class="card" method="POST" action="/code" autocomplete="off">
+        <h1>Enter the one-time code</h1>
+        <p>Find the code in the terminal running <code>line auth login --no-browser</code>.</p>
+        <input type="text" name="code" placeholder="XXXX-XXXX" maxlength="9" autofocus required>
+        <button type="submit">Continue</button>
+        {{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+    </form>
+</body>
+</html>`
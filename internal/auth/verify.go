@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+)
+
+// TokenStatus is what Verify learns about an account's credentials.
+type TokenStatus struct {
+	// Valid is false when the API rejected the credentials; Err is set
+	// when they could not be checked at all
+	Valid bool
+	Err   error
+	// ExpiresAt is when a stored token expires: zero for tokens that do not
+	// expire, and for stateless tokens, which are issued for each command
+	ExpiresAt time.Time
+	BotName   string
+	BasicID   string
+}
+
+// Verify checks creds with client, a client built from them: it fetches the
+// bot's info and, for a stored token, asks LINE when the token expires.
+func Verify(ctx context.Context, client *api.Client, creds secrets.Credentials, now time.Time) TokenStatus {
+	var status TokenStatus
+	info, err := client.GetBotInfo(ctx)
+	if err != nil {
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) && apiErr.IsUnauthorized() {
+			return status
+		}
+		status.Err = err
+		return status
+	}
+	status.Valid, status.BotName, status.BasicID = true, info.DisplayName, info.BasicID
+
+	if !creds.UsesChannelSecret() {
+		status.ExpiresAt, _ = TokenExpiry(ctx, client, creds.ChannelAccessToken, now)
+	}
+	return status
+}
+
+// TokenExpiry asks LINE when token expires. The time is zero for tokens
+// that do not expire.
+func TokenExpiry(ctx context.Context, client *api.Client, token string, now time.Time) (time.Time, error) {
+	info, err := client.VerifyChannelTokenByJWT(ctx, token)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if info.ExpiresIn <= 0 {
+		return time.Time{}, nil
+	}
+	return now.Add(time.Duration(info.ExpiresIn) * time.Second), nil
+}
@@ -0,0 +1,286 @@
+// Package campaign runs a narrowcast campaign described in a YAML file as
+// a pipeline of steps: create the audience from a CSV file, wait until it is
+// ready, validate the messages, send, follow the delivery, and export the
+// delivery statistics.
+//
+// Progress is saved to a state file after every step, so a run that is
+// interrupted or fails picks up where it stopped when run again, and a
+// campaign that has been sent is never sent twice.
+package campaign
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaxMessages is the most messages one narrowcast may carry.
+const MaxMessages = 5
+
+// Spec is a campaign file.
+type Spec struct {
+	Name     string       `yaml:"name"`
+	Audience AudienceSpec `yaml:"audience"`
+	// Text sends a single plain text message
+	Text string `yaml:"text,omitempty"`
+	// Messages are Messaging API message objects (flex, image, ...)
+	Messages []map[string]any `yaml:"messages,omitempty"`
+	Limit    *LimitSpec       `yaml:"limit,omitempty"`
+	Wait     WaitSpec         `yaml:"wait,omitempty"`
+	Stats    StatsSpec        `yaml:"stats,omitempty"`
+}
+
+// AudienceSpec names the audience to send to: either a new audience
+// created from File, or an existing one by ID.
+type AudienceSpec struct {
+	// ID is an existing audience group to send to
+	ID int64 `yaml:"id,omitempty"`
+	// Name is the description of the audience created from File
+	Name string `yaml:"name,omitempty"`
+	// File is a CSV file of user IDs, relative to the campaign file
+	File string `yaml:"file,omitempty"`
+	// Column is the header of the CSV column holding the user IDs. Without
+	// it the first column is used.
+	Column string `yaml:"column,omitempty"`
+}
+
+// LimitSpec caps how many users the narrowcast reaches.
+type LimitSpec struct {
+	Max                int  `yaml:"max,omitempty"`
+	UpToRemainingQuota bool `yaml:"upToRemainingQuota,omitempty"`
+}
+
+// WaitSpec bounds the waits for the audience and the delivery.
+type WaitSpec struct {
+	Audience time.Duration `yaml:"audience,omitempty"`
+	Delivery time.Duration `yaml:"delivery,omitempty"`
+	Interval time.Duration `yaml:"interval,omitempty"`
+}
+
+// StatsSpec configures the statistics export.
+type StatsSpec struct {
+	// File is where the statistics are written, relative to the campaign
+	// file. It defaults to <campaign file>-stats.json.
+	File string `yaml:"file,omitempty"`
+}
+
+// Defaults of WaitSpec.
+const (
+	DefaultAudienceWait = 30 * time.Minute
+	DefaultDeliveryWait = 2 * time.Hour
+	DefaultPollInterval = 10 * time.Second
+)
+
+// Parse parses and validates a campaign file and fills in defaults.
+func Parse(data []byte) (*Spec, error) {
+	var s Spec
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid campaign file: %w", err)
+	}
+	if s.Name == "" {
+		return nil, errors.New("invalid campaign file: name is required")
+	}
+
+	a := s.Audience
+	switch {
+	case a.ID != 0 && (a.File != "" || a.Name != "" || a.Column != ""):
+		return nil, errors.New("invalid campaign file: audience takes either id or file, not both")
+	case a.ID < 0:
+		return nil, errors.New("invalid campaign file: audience.id must be positive")
+	case a.ID == 0 && a.File == "":
+		return nil, errors.New("invalid campaign file: audience needs an id or a file")
+	case a.File != "" && a.Name == "":
+		return nil, errors.New("invalid campaign file: audience.name is required with audience.file")
+	}
+
+	if (s.Text == "") == (len(s.Messages) == 0) {
+		return nil, errors.New("invalid campaign file: specify exactly one of text or messages")
+	}
+	if len(s.Messages) > MaxMessages {
+		return nil, fmt.Errorf("invalid campaign file: at most %d messages, got %d", MaxMessages, len(s.Messages))
+	}
+	for i, m := range s.Messages {
+		if t, _ := m["type"].(string); t == "" {
+			return nil, fmt.Errorf("invalid campaign file: message %d has no type", i+1)
+		}
+	}
+	if s.Limit != nil && s.Limit.Max < 0 {
+		return nil, errors.New("invalid campaign file: limit.max must be positive")
+	}
+
+	if s.Wait.Audience < 0 || s.Wait.Delivery < 0 || s.Wait.Interval < 0 {
+		return nil, errors.New("invalid campaign file: wait durations must be positive")
+	}
+	if s.Wait.Audience == 0 {
+		s.Wait.Audience = DefaultAudienceWait
+	}
+	if s.Wait.Delivery == 0 {
+		s.Wait.Delivery = DefaultDeliveryWait
+	}
+	if s.Wait.Interval == 0 {
+		s.Wait.Interval = DefaultPollInterval
+	}
+	return &s, nil
+}
+
+// Load reads and parses a campaign file. It also returns the SHA-256 of
+// the file, which the state records so a changed campaign is noticed.
+func Load(path string) (*Spec, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read campaign file: %w", err)
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(data)
+	return spec, hex.EncodeToString(sum[:]), nil
+}
+
+// MessageObjects returns the messages to send.
+func (s *Spec) MessageObjects() []any {
+	if s.Text != "" {
+		return []any{map[string]any{"type": "text", "text": s.Text}}
+	}
+	messages := make([]any, len(s.Messages))
+	for i, m := range s.Messages {
+		messages[i] = m
+	}
+	return messages
+}
+
+// StatePath returns the default state file of a campaign file:
+// campaign.yaml keeps its state in campaign.state.json.
+func StatePath(specPath string) string {
+	return trimExt(specPath) + ".state.json"
+}
+
+// StatsPath returns where the statistics of the campaign in specPath are
+// exported.
+func (s *Spec) StatsPath(specPath string) string {
+	if s.Stats.File != "" {
+		return resolve(specPath, s.Stats.File)
+	}
+	return trimExt(specPath) + "-stats.json"
+}
+
+// AudienceFile returns the path of the audience CSV file.
+func (s *Spec) AudienceFile(specPath string) string {
+	return resolve(specPath, s.Audience.File)
+}
+
+func trimExt(path string) string {
+	return path[:len(path)-len(filepath.Ext(path))]
+}
+
+// resolve returns path relative to the directory of the campaign file.
+func resolve(specPath, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(specPath), path)
+}
+
+// Steps of a run, in order.
+const (
+	StepAudience = "audience"
+	StepReady    = "ready"
+	StepValidate = "validate"
+	StepSend     = "send"
+	StepDelivery = "delivery"
+	StepStats    = "stats"
+)
+
+// Steps lists every step in the order they run.
+var Steps = []string{StepAudience, StepReady, StepValidate, StepSend, StepDelivery, StepStats}
+
+// Step states. A send that is started but not done was interrupted before
+// LINE confirmed it, so it may or may not have gone out.
+const (
+	StatusStarted = "started"
+	StatusDone    = "done"
+)
+
+// StepState is the progress of one step.
+type StepState struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+	// Detail describes the outcome, such as the audience size
+	Detail string `json:"detail,omitempty"`
+}
+
+// State is the saved progress of a campaign run.
+type State struct {
+	Campaign string `json:"campaign"`
+	// SpecSHA256 is the digest of the campaign file the run started with
+	SpecSHA256      string               `json:"specSha256"`
+	AudienceGroupID int64                `json:"audienceGroupId,omitempty"`
+	RequestID       string               `json:"requestId,omitempty"`
+	Steps           map[string]StepState `json:"steps"`
+	UpdatedAt       time.Time            `json:"updatedAt"`
+}
+
+// Done reports whether step has completed.
+func (s *State) Done(step string) bool {
+	return s.Steps[step].Status == StatusDone
+}
+
+// Started reports whether any step has made progress.
+func (s *State) Started() bool {
+	return len(s.Steps) > 0
+}
+
+// LoadState reads the state file in path. A missing file yields a new,
+// empty state.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{Steps: map[string]StepState{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read campaign state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid campaign state %s: %w", path, err)
+	}
+	if s.Steps == nil {
+		s.Steps = map[string]StepState{}
+	}
+	return &s, nil
+}
+
+// Save writes the state to path, replacing the old file atomically.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".campaign-state-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save campaign state: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save campaign state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save campaign state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save campaign state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package campaign
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/api/generated"
+)
+
+const testSpec = `name: spring-sale
+audience:
+  name: Spring sale
+  file: targets.csv
+  column: user_id
+text: "Spring sale starts today!"
+wait:
+  interval: 1ms
+`
+
+const (
+	user1 = "U11111111111111111111111111111111"
+	user2 = "U22222222222222222222222222222222"
+)
+
+func TestParse(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if spec.Wait.Interval != time.Millisecond || spec.Wait.Audience != DefaultAudienceWait || spec.Wait.Delivery != DefaultDeliveryWait {
+		t.Errorf("unexpected wait: %+v", spec.Wait)
+	}
+	if got := spec.MessageObjects(); len(got) != 1 {
+		t.Errorf("MessageObjects() = %v", got)
+	}
+
+	tests := []struct {
+		name, spec, want string
+	}{
+		{"no name", "audience: {id: 1}\ntext: hi\n", "name is required"},
+		{"no audience", "name: x\ntext: hi\n", "needs an id or a file"},
+		{"id and file", "name: x\naudience: {id: 1, file: a.csv}\ntext: hi\n", "either id or file"},
+		{"file without name", "name: x\naudience: {file: a.csv}\ntext: hi\n", "audience.name is required"},
+		{"no message", "name: x\naudience: {id: 1}\n", "exactly one of text or messages"},
+		{"untyped message", "name: x\naudience: {id: 1}\nmessages: [{text: hi}]\n", "message 1 has no type"},
+		{"unknown field", "name: x\naudience: {id: 1}\ntext: hi\nsend: now\n", "field send not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.spec)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractUserIDs(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.txt")
+
+	byColumn := filepath.Join(dir, "by-column.csv")
+	writeFile(t, byColumn, "name,user_id\nAlice,"+user1+"\nBob,"+user2+"\nAlice again,"+user1+"\n")
+	n, err := ExtractUserIDs(byColumn, "user_id", out)
+	if err != nil || n != 2 {
+		t.Fatalf("ExtractUserIDs() = %d, %v", n, err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != user1+"\n"+user2+"\n" {
+		t.Errorf("unexpected IDs: %q", data)
+	}
+
+	firstColumn := filepath.Join(dir, "first.csv")
+	writeFile(t, firstColumn, "userId,score\n"+user2+",3\n")
+	if n, err := ExtractUserIDs(firstColumn, "", out); err != nil || n != 1 {
+		t.Errorf("ExtractUserIDs() without column = %d, %v", n, err)
+	}
+
+	if _, err := ExtractUserIDs(byColumn, "missing", out); err == nil || !strings.Contains(err.Error(), `no column "missing"`) {
+		t.Errorf("expected missing column error, got %v", err)
+	}
+	bad := filepath.Join(dir, "bad.csv")
+	writeFile(t, bad, user1+"\nnot-a-user\n")
+	if _, err := ExtractUserIDs(bad, "", out); err == nil {
+		t.Error("expected error for an invalid user ID")
+	}
+}
+
+// fakeAPI answers a run with an audience that becomes READY on the second
+// poll and a narrowcast that succeeds on the second poll.
+type fakeAPI struct {
+	calls         []string
+	audiencePolls int
+	progressPolls int
+	narrowcastErr error
+	sent          api.NarrowcastMessageRequest
+}
+
+func (f *fakeAPI) CreateAudienceFromFile(ctx context.Context, description, filePath string) (*api.CreateAudienceResponse, error) {
+	f.calls = append(f.calls, "create")
+	return &api.CreateAudienceResponse{AudienceGroupID: 42}, nil
+}
+
+func (f *fakeAPI) GetAudienceGroup(ctx context.Context, audienceGroupID int64) (*api.AudienceGroupDetail, error) {
+	f.calls = append(f.calls, "audience")
+	f.audiencePolls++
+	status := generated.AudienceGroupStatusINPROGRESS
+	if f.audiencePolls > 1 {
+		status = generated.AudienceGroupStatusREADY
+	}
+	count := int64(2)
+	resp := &api.AudienceGroupDetail{}
+	resp.AudienceGroup = &generated.AudienceGroup{Status: &status, AudienceCount: &count}
+	return resp, nil
+}
+
+func (f *fakeAPI) ValidateMessage(ctx context.Context, messageType string, messages []json.RawMessage) error {
+	f.calls = append(f.calls, "validate")
+	return nil
+}
+
+func (f *fakeAPI) Narrowcast(ctx context.Context, req api.NarrowcastMessageRequest) (*api.NarrowcastResponse, error) {
+	f.calls = append(f.calls, "send")
+	f.sent = req
+	if f.narrowcastErr != nil {
+		return nil, f.narrowcastErr
+	}
+	return &api.NarrowcastResponse{RequestID: "req-1"}, nil
+}
+
+func (f *fakeAPI) GetNarrowcastProgress(ctx context.Context, requestID string) (map[string]any, error) {
+	f.calls = append(f.calls, "progress")
+	f.progressPolls++
+	if f.progressPolls == 1 {
+		return map[string]any{"phase": "sending"}, nil
+	}
+	return map[string]any{"phase": "succeeded", "successCount": float64(2), "failureCount": float64(0)}, nil
+}
+
+func (f *fakeAPI) GetMessageEventStats(ctx context.Context, requestID string) (*api.MessageEventResponse, error) {
+	f.calls = append(f.calls, "events")
+	return &api.MessageEventResponse{Overview: &api.MessageEventOverview{RequestID: requestID, Delivered: 2}}, nil
+}
+
+func newTestRunner(t *testing.T, fake *fakeAPI) *Runner {
+	t.Helper()
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "campaign.yaml")
+	writeFile(t, specPath, testSpec)
+	writeFile(t, filepath.Join(dir, "targets.csv"), "user_id\n"+user1+"\n"+user2+"\n")
+	spec, sum, err := Load(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadState(StatePath(specPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Runner{API: fake, Spec: spec, SpecPath: specPath, SpecSHA: sum, State: state, StatePath: StatePath(specPath)}
+}
+
+func TestRunner_Run(t *testing.T) {
+	fake := &fakeAPI{}
+	r := newTestRunner(t, fake)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "create,audience,audience,validate,send,progress,progress,progress,events"
+	if got := strings.Join(fake.calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+	if fake.sent.Recipient == nil || fake.sent.Recipient.AudienceGroupID != 42 {
+		t.Errorf("unexpected recipient: %+v", fake.sent.Recipient)
+	}
+
+	saved, err := LoadState(r.StatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range Steps {
+		if !saved.Done(step) {
+			t.Errorf("step %s not done in saved state: %+v", step, saved.Steps)
+		}
+	}
+	if saved.AudienceGroupID != 42 || saved.RequestID != "req-1" {
+		t.Errorf("unexpected saved state: %+v", saved)
+	}
+
+	var stats Stats
+	data, err := os.ReadFile(r.Spec.StatsPath(r.SpecPath))
+	if err != nil {
+		t.Fatalf("stats not written: %v", err)
+	}
+	if err := json.Unmarshal(data, &stats); err != nil || stats.RequestID != "req-1" || stats.Events.Overview.Delivered != 2 {
+		t.Errorf("unexpected stats: %s", data)
+	}
+
+	// A second run has nothing left to do
+	fake.calls = nil
+	r.State = saved
+	if err := r.Run(context.Background()); err != nil || len(fake.calls) != 0 {
+		t.Errorf("rerun: calls = %v, err = %v", fake.calls, err)
+	}
+}
+
+func TestRunner_ResumesAfterFailedSend(t *testing.T) {
+	fake := &fakeAPI{narrowcastErr: &api.APIError{StatusCode: 400, Message: "bad"}}
+	r := newTestRunner(t, fake)
+	if err := r.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "send step failed") {
+		t.Fatalf("expected send failure, got %v", err)
+	}
+
+	// LINE rejected the send, so the next run sends without --retry-send
+	// and skips the steps already done
+	fake.narrowcastErr = nil
+	fake.calls = nil
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.Join(fake.calls, ","); !strings.HasPrefix(got, "send,") {
+		t.Errorf("expected the run to resume at send, calls = %s", got)
+	}
+}
+
+func TestRunner_InterruptedSend(t *testing.T) {
+	fake := &fakeAPI{narrowcastErr: errors.New("connection reset")}
+	r := newTestRunner(t, fake)
+	if err := r.Run(context.Background()); err == nil {
+		t.Fatal("expected send failure")
+	}
+
+	fake.narrowcastErr = nil
+	if err := r.Run(context.Background()); !errors.Is(err, ErrSendUncertain) {
+		t.Fatalf("expected ErrSendUncertain, got %v", err)
+	}
+
+	r.RetrySend = true
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run() with RetrySend error = %v", err)
+	}
+}
+
+func TestRunner_SpecChanged(t *testing.T) {
+	r := newTestRunner(t, &fakeAPI{})
+	r.State.Steps[StepAudience] = StepState{Status: StatusDone}
+	r.State.SpecSHA256 = "old"
+	if err := r.Run(context.Background()); !errors.Is(err, ErrSpecChanged) {
+		t.Errorf("expected ErrSpecChanged, got %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
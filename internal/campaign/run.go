@@ -0,0 +1,427 @@
+package campaign
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+)
+
+// API is the part of the Messaging API a run uses. *api.Client implements
+// it.
+type API interface {
+	CreateAudienceFromFile(ctx context.Context, description string, filePath string) (*api.CreateAudienceResponse, error)
+	GetAudienceGroup(ctx context.Context, audienceGroupID int64) (*api.AudienceGroupDetail, error)
+	ValidateMessage(ctx context.Context, messageType string, messages []json.RawMessage) error
+	Narrowcast(ctx context.Context, req api.NarrowcastMessageRequest) (*api.NarrowcastResponse, error)
+	GetNarrowcastProgress(ctx context.Context, requestID string) (map[string]any, error)
+	GetMessageEventStats(ctx context.Context, requestID string) (*api.MessageEventResponse, error)
+}
+
+// ErrSendUncertain is returned when an earlier run was interrupted while
+// sending, so the campaign may already have gone out.
+var ErrSendUncertain = errors.New("an earlier run was interrupted while sending, so the campaign may already have been sent")
+
+// ErrSpecChanged is returned when the campaign file has changed since its
+// run started.
+var ErrSpecChanged = errors.New("campaign file has changed since the run started")
+
+// Runner runs the steps of a campaign that are not done yet.
+type Runner struct {
+	API       API
+	Spec      *Spec
+	SpecPath  string
+	SpecSHA   string
+	State     *State
+	StatePath string
+	// RetrySend sends again after an interrupted send instead of failing
+	// with ErrSendUncertain
+	RetrySend bool
+	// Log reports progress; it may be nil
+	Log func(step, format string, args ...any)
+	// Uploaded is called with the cleaned user ID file after an audience is
+	// created from it; it may be nil
+	Uploaded func(audienceGroupID int64, file string)
+	Now      func() time.Time
+}
+
+// Run runs every step that is not done, saving the state after each.
+func (r *Runner) Run(ctx context.Context) error {
+	if r.State.Started() && r.State.SpecSHA256 != r.SpecSHA {
+		return ErrSpecChanged
+	}
+	r.State.Campaign = r.Spec.Name
+	r.State.SpecSHA256 = r.SpecSHA
+
+	steps := []struct {
+		name string
+		run  func(context.Context) (string, error)
+	}{
+		{StepAudience, r.createAudience},
+		{StepReady, r.waitForAudience},
+		{StepValidate, r.validate},
+		{StepSend, r.send},
+		{StepDelivery, r.waitForDelivery},
+		{StepStats, r.exportStats},
+	}
+	for _, step := range steps {
+		if r.State.Done(step.name) {
+			r.logf(step.name, "already done")
+			continue
+		}
+		detail, err := step.run(ctx)
+		if err != nil {
+			return fmt.Errorf("%s step failed: %w", step.name, err)
+		}
+		if err := r.mark(step.name, StatusDone, detail); err != nil {
+			return err
+		}
+		r.logf(step.name, "%s", detail)
+	}
+	return nil
+}
+
+func (r *Runner) mark(step, status, detail string) error {
+	now := r.now()
+	r.State.Steps[step] = StepState{Status: status, At: now, Detail: detail}
+	r.State.UpdatedAt = now
+	return r.State.Save(r.StatePath)
+}
+
+func (r *Runner) now() time.Time {
+	if r.Now != nil {
+		return r.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (r *Runner) logf(step, format string, args ...any) {
+	if r.Log != nil {
+		r.Log(step, format, args...)
+	}
+}
+
+func (r *Runner) createAudience(ctx context.Context) (string, error) {
+	if id := r.Spec.Audience.ID; id != 0 {
+		r.State.AudienceGroupID = id
+		return fmt.Sprintf("using existing audience %d", id), nil
+	}
+
+	dir, err := os.MkdirTemp("", "line-campaign-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	cleaned := filepath.Join(dir, "users.txt")
+	count, err := ExtractUserIDs(r.Spec.AudienceFile(r.SpecPath), r.Spec.Audience.Column, cleaned)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.API.CreateAudienceFromFile(ctx, r.Spec.Audience.Name, cleaned)
+	if err != nil {
+		return "", fmt.Errorf("failed to create audience: %w", err)
+	}
+	r.State.AudienceGroupID = resp.AudienceGroupID
+	if r.Uploaded != nil {
+		r.Uploaded(resp.AudienceGroupID, cleaned)
+	}
+	return fmt.Sprintf("created audience %d with %d users", resp.AudienceGroupID, count), nil
+}
+
+func (r *Runner) waitForAudience(ctx context.Context) (string, error) {
+	id := r.State.AudienceGroupID
+	var status string
+	var count int64
+	err := r.poll(ctx, StepReady, r.Spec.Wait.Audience, func(ctx context.Context) (bool, string, error) {
+		resp, err := r.API.GetAudienceGroup(ctx, id)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get audience group: %w", err)
+		}
+		status = ""
+		if g := resp.AudienceGroup; g != nil {
+			if g.Status != nil {
+				status = string(*g.Status)
+			}
+			if g.AudienceCount != nil {
+				count = *g.AudienceCount
+			}
+		}
+		switch status {
+		case "READY":
+			return true, status, nil
+		case "FAILED", "EXPIRED":
+			return false, status, fmt.Errorf("audience %d is %s", id, status)
+		}
+		return false, status, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("audience %d is READY with %d users", id, count), nil
+}
+
+func (r *Runner) validate(ctx context.Context) (string, error) {
+	messages, err := rawMessages(r.Spec.MessageObjects())
+	if err != nil {
+		return "", err
+	}
+	if err := r.API.ValidateMessage(ctx, "narrowcast", messages); err != nil {
+		return "", fmt.Errorf("messages are not valid for narrowcast: %w", err)
+	}
+	return fmt.Sprintf("%d message(s) valid for narrowcast", len(messages)), nil
+}
+
+func (r *Runner) send(ctx context.Context) (string, error) {
+	if r.State.Steps[StepSend].Status == StatusStarted && !r.RetrySend {
+		return "", ErrSendUncertain
+	}
+	// Mark the send started first, so an interruption before LINE answers
+	// is not mistaken for a send that never happened
+	if err := r.mark(StepSend, StatusStarted, ""); err != nil {
+		return "", err
+	}
+
+	req := api.NarrowcastMessageRequest{
+		Messages:  r.Spec.MessageObjects(),
+		Recipient: &api.NarrowcastRecipient{Type: "audience", AudienceGroupID: r.State.AudienceGroupID},
+	}
+	if l := r.Spec.Limit; l != nil {
+		req.Limit = &api.NarrowcastLimit{Max: l.Max, UpToRemainingQuota: l.UpToRemainingQuota}
+	}
+	resp, err := r.API.Narrowcast(ctx, req)
+	if err != nil {
+		// LINE rejected the request, so nothing was sent and it is safe to
+		// try again
+		if api.AsAPIError(err) != nil {
+			delete(r.State.Steps, StepSend)
+			_ = r.State.Save(r.StatePath)
+		}
+		return "", fmt.Errorf("failed to narrowcast: %w", err)
+	}
+	r.State.RequestID = resp.RequestID
+	return fmt.Sprintf("narrowcast accepted with request ID %s", resp.RequestID), nil
+}
+
+func (r *Runner) waitForDelivery(ctx context.Context) (string, error) {
+	var progress map[string]any
+	err := r.poll(ctx, StepDelivery, r.Spec.Wait.Delivery, func(ctx context.Context) (bool, string, error) {
+		var err error
+		progress, err = r.API.GetNarrowcastProgress(ctx, r.State.RequestID)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get narrowcast progress: %w", err)
+		}
+		phase, _ := progress["phase"].(string)
+		switch phase {
+		case "succeeded":
+			return true, phase, nil
+		case "failed":
+			reason, _ := progress["failedDescription"].(string)
+			if reason == "" {
+				reason = fmt.Sprint(progress["errorCode"])
+			}
+			return false, phase, fmt.Errorf("narrowcast failed: %s", reason)
+		}
+		return false, phase, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("delivered to %v users (%v failed)", number(progress["successCount"]), number(progress["failureCount"])), nil
+}
+
+// Stats is the statistics export of a campaign.
+type Stats struct {
+	Campaign        string                    `json:"campaign"`
+	AudienceGroupID int64                     `json:"audienceGroupId"`
+	RequestID       string                    `json:"requestId"`
+	ExportedAt      time.Time                 `json:"exportedAt"`
+	Delivery        map[string]any            `json:"delivery"`
+	Events          *api.MessageEventResponse `json:"events"`
+}
+
+func (r *Runner) exportStats(ctx context.Context) (string, error) {
+	progress, err := r.API.GetNarrowcastProgress(ctx, r.State.RequestID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get narrowcast progress: %w", err)
+	}
+	events, err := r.API.GetMessageEventStats(ctx, r.State.RequestID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get message event stats: %w", err)
+	}
+	stats := Stats{
+		Campaign:        r.Spec.Name,
+		AudienceGroupID: r.State.AudienceGroupID,
+		RequestID:       r.State.RequestID,
+		ExportedAt:      r.now(),
+		Delivery:        progress,
+		Events:          events,
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := r.Spec.StatsPath(r.SpecPath)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write stats: %w", err)
+	}
+	return fmt.Sprintf("stats written to %s", path), nil
+}
+
+// poll calls check every Wait.Interval until it reports done, logging each
+// change of state.
+func (r *Runner) poll(ctx context.Context, step string, timeout time.Duration, check func(ctx context.Context) (bool, string, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var last string
+	for {
+		done, state, err := check(ctx)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timed out after %s (last status: %s)", timeout, last)
+			}
+			return err
+		}
+		if done {
+			return nil
+		}
+		if state != last {
+			r.logf(step, "%s", state)
+			last = state
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("timed out after %s (last status: %s)", timeout, last)
+			}
+			return ctx.Err()
+		case <-time.After(r.Spec.Wait.Interval):
+		}
+	}
+}
+
+// ExtractUserIDs writes the unique user IDs in a CSV file to out, one per
+// line, and returns how many there are. The IDs are read from the column
+// headed column, or from the first column if column is empty, in which case
+// a header row is skipped if there is one.
+func ExtractUserIDs(csvPath, column, out string) (int, error) {
+	in, err := os.Open(csvPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audience file: %w", err)
+	}
+	defer func() { _ = in.Close() }()
+
+	cr := csv.NewReader(bufio.NewReader(in))
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	index := 0
+	if column != "" {
+		header, err := cr.Read()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read audience file header: %w", err)
+		}
+		index = -1
+		for i, name := range header {
+			if strings.TrimSpace(name) == column {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return 0, fmt.Errorf("audience file has no column %q", column)
+		}
+	}
+
+	// Pipe the column through userids so IDs are validated and de-duplicated
+	// the same way as every other users file
+	pr, pw := io.Pipe()
+	go func() {
+		w := bufio.NewWriter(pw)
+		first := column == ""
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(fmt.Errorf("failed to read audience file: %w", err))
+				return
+			}
+			if index >= len(record) {
+				continue
+			}
+			id := strings.TrimSpace(record[index])
+			if first {
+				first = false
+				if !strings.HasPrefix(id, "U") {
+					continue // header row
+				}
+			}
+			_, _ = w.WriteString(id + "\n")
+		}
+		pw.CloseWithError(w.Flush())
+	}()
+
+	f, err := os.Create(out)
+	if err != nil {
+		_ = pr.Close()
+		return 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	ids := userids.NewReader(pr, userids.Options{})
+	for ids.Next() {
+		_, _ = w.WriteString(ids.ID() + "\n")
+	}
+	_ = pr.Close()
+	err = ids.Err()
+	if ferr := w.Flush(); err == nil {
+		err = ferr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid audience file: %w", err)
+	}
+	n := ids.Stats().Unique
+	if n == 0 {
+		return 0, fmt.Errorf("invalid audience file: %w", userids.ErrNoUsers)
+	}
+	return n, nil
+}
+
+// rawMessages encodes message objects for the validate endpoint.
+func rawMessages(messages []any) ([]json.RawMessage, error) {
+	raw := make([]json.RawMessage, len(messages))
+	for i, m := range messages {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message %d: %w", i+1, err)
+		}
+		raw[i] = data
+	}
+	return raw, nil
+}
+
+// number formats a JSON number from a progress response, which decodes
+// as float64, without a decimal point.
+func number(v any) string {
+	if f, ok := v.(float64); ok {
+		return fmt.Sprintf("%.0f", f)
+	}
+	if v == nil {
+		return "0"
+	}
+	return fmt.Sprint(v)
+}
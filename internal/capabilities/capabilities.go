@@ -0,0 +1,174 @@
+// Package capabilities records which plan-dependent API features each
+// account has, so a 403 from a feature the plan does not include can be
+// explained instead of reported raw.
+package capabilities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// Feature is an API feature that LINE enables by plan or account type.
+type Feature struct {
+	Name        string
+	Description string
+	// Paths are the endpoint path prefixes that belong to the feature
+	Paths []string
+}
+
+// Features are the plan-dependent features, in the order they are shown.
+var Features = []Feature{
+	{
+		Name:        "richmenu-bulk",
+		Description: "Bulk rich menu linking",
+		Paths:       []string{"/v2/bot/richmenu/bulk/"},
+	},
+	{
+		Name:        "richmenu-batch",
+		Description: "Rich menu batch operations",
+		Paths:       []string{"/v2/bot/richmenu/batch", "/v2/bot/richmenu/validate/batch", "/v2/bot/richmenu/progress/batch"},
+	},
+	{
+		Name:        "narrowcast",
+		Description: "Narrowcast messages",
+		Paths:       []string{"/v2/bot/message/narrowcast", "/v2/bot/message/progress/narrowcast"},
+	},
+	{
+		Name:        "demographics",
+		Description: "Follower demographics",
+		Paths:       []string{"/v2/bot/insight/demographic"},
+	},
+	{
+		Name:        "follower-ids",
+		Description: "Follower IDs",
+		Paths:       []string{"/v2/bot/followers/ids"},
+	},
+	{
+		Name:        "audiences",
+		Description: "Audience management",
+		Paths:       []string{"/v2/bot/audienceGroup"},
+	},
+	{
+		Name:        "membership",
+		Description: "Memberships",
+		Paths:       []string{"/v2/bot/membership/"},
+	},
+}
+
+// FeatureFor returns the feature an endpoint path belongs to.
+func FeatureFor(endpoint string) (Feature, bool) {
+	path, _, _ := strings.Cut(endpoint, "?")
+	for _, f := range Features {
+		for _, prefix := range f.Paths {
+			if strings.HasPrefix(path, prefix) {
+				return f, true
+			}
+		}
+	}
+	return Feature{}, false
+}
+
+// Record is what a probe of an account's features found.
+type Record struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	// Available maps feature names to whether the plan includes them;
+	// features that could not be checked are absent
+	Available map[string]bool `json:"available"`
+}
+
+// mu serializes read-modify-write cycles on the state file within a process.
+var mu sync.Mutex
+
+// Path returns the location of the capabilities file.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "capabilities.json"), nil
+}
+
+// Load returns the records for all accounts. A missing file yields an
+// empty map.
+func Load() (map[string]*Record, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capabilities: %w", err)
+	}
+	records := map[string]*Record{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid capabilities file: %w", err)
+	}
+	return records, nil
+}
+
+func save(records map[string]*Record) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Get returns the record for account, or nil if it has not been probed.
+func Get(account string) (*Record, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	records, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return records[account], nil
+}
+
+// Set records what a probe of account found.
+func Set(account string, rec *Record) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	records, err := Load()
+	if err != nil {
+		return err
+	}
+	records[account] = rec
+	return save(records)
+}
+
+// Forget removes the record for account and reports whether one existed.
+func Forget(account string) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	records, err := Load()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := records[account]; !ok {
+		return false, nil
+	}
+	delete(records, account)
+	return true, save(records)
+}
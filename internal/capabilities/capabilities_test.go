@@ -0,0 +1,52 @@
+package capabilities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFeatureFor(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"/v2/bot/richmenu/bulk/link", "richmenu-bulk"},
+		{"/v2/bot/insight/demographic", "demographics"},
+		{"/v2/bot/followers/ids?limit=1", "follower-ids"},
+		{"/v2/bot/membership/list", "membership"},
+		{"/v2/bot/richmenu/list", ""},
+		{"/v2/bot/message/push", ""},
+	}
+	for _, tt := range tests {
+		f, ok := FeatureFor(tt.endpoint)
+		if f.Name != tt.want || ok != (tt.want != "") {
+			t.Errorf("FeatureFor(%q) = %q, %v; want %q", tt.endpoint, f.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestSetGetForget(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if rec, err := Get("prod"); err != nil || rec != nil {
+		t.Fatalf("Get() before probing = %v, %v", rec, err)
+	}
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := Set("prod", &Record{CheckedAt: now, Available: map[string]bool{"richmenu-bulk": false}}); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := Get("prod")
+	if err != nil || rec == nil {
+		t.Fatalf("Get() = %v, %v", rec, err)
+	}
+	if available, ok := rec.Available["richmenu-bulk"]; !ok || available || !rec.CheckedAt.Equal(now) {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+
+	if ok, err := Forget("prod"); err != nil || !ok {
+		t.Fatalf("Forget() = %v, %v", ok, err)
+	}
+	if ok, _ := Forget("prod"); ok {
+		t.Error("expected nothing left to forget")
+	}
+}
@@ -0,0 +1,535 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/auth"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/spf13/cobra"
+)
+
+func newAccountCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Manage stored accounts",
+		Long: `Manage stored accounts without the browser setup, and choose which one
+commands use by default.
+
+An account given with --account or LINE_ACCOUNT always takes precedence
+over the default for that invocation.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newAuthListCmd())
+	cmd.AddCommand(newAccountAddCmd())
+	cmd.AddCommand(newAccountRemoveCmd())
+	cmd.AddCommand(newAccountShowCmd())
+	cmd.AddCommand(newAccountUseCmd())
+	cmd.AddCommand(newAccountCurrentCmd())
+	cmd.AddCommand(newAccountCapabilitiesCmd())
+	cmd.AddCommand(newAccountMigrateStoreCmd())
+
+	return cmd
+}
+
+func newAccountUseCmd() *cobra.Command {
+	return newAccountUseCmdWithStore(nil)
+}
+
+func newAccountUseCmdWithStore(store secrets.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "use <name>",
+		Aliases: []string{"set-primary"},
+		Short:   "Set the default account",
+		Long:    "Make a stored account the default for commands run without --account or LINE_ACCOUNT.",
+		Example: `  line account use my-shop
+  line account use staging`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return fmt.Errorf("account name is required")
+			}
+
+			var err error
+			if store == nil {
+				store, err = openSecretsStore()
+				if err != nil {
+					return fmt.Errorf("failed to open keyring: %w", err)
+				}
+			}
+
+			acc, err := findStoredAccount(store, name)
+			if err != nil {
+				return err
+			}
+			found := acc.Name
+
+			if err := store.SetPrimary(found); err != nil {
+				return fmt.Errorf("failed to set default account: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Default account: %s\n", found)
+			if flags.Account != "" && !strings.EqualFold(flags.Account, found) {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Note: %s is still selected by --account, LINE_ACCOUNT, or the config file and takes precedence\n", flags.Account)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newAccountCurrentCmd() *cobra.Command {
+	return newAccountCurrentCmdWithStore(nil)
+}
+
+func newAccountCurrentCmdWithStore(store secrets.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "current",
+		Short: "Print the account commands will use",
+		Long:  "Print the name of the account commands will use, resolved from --account, LINE_ACCOUNT, the config file, or the default account.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account, source := flags.Account, "flag"
+			if account == "" {
+				var err error
+				if store == nil {
+					store, err = openSecretsStore()
+					if err != nil {
+						return fmt.Errorf("failed to open keyring: %w", err)
+					}
+				}
+				account, err = defaultStoredAccount(store)
+				if err != nil {
+					return err
+				}
+				source = "default"
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]string{"account": account, "source": source})
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), account)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newAccountAddCmd() *cobra.Command {
+	return newAccountAddCmdWithClient(nil, nil)
+}
+
+// newAccountAddCmdWithClient uses client, if set, to validate the
+// credentials instead of a client built from them.
+func newAccountAddCmdWithClient(client *api.Client, store secrets.Store) *cobra.Command {
+	var accountName string
+	var channelAccessToken string
+	var channelID string
+	var channelSecret string
+	var primary bool
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add an account from a token or channel secret",
+		Long: `Add an account without the browser setup, e.g. on a remote server.
+
+The credentials are checked by fetching the bot's info before they are
+stored, and the bot's display name is saved with the account.`,
+		Example: `  line account add --name my-shop --token YOUR_TOKEN
+  line account add --name my-shop --channel-id 1234567890 --channel-secret SECRET --primary`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			accountName = strings.TrimSpace(accountName)
+			if accountName == "" {
+				return fmt.Errorf("--name is required")
+			}
+			creds, err := credentialsFromFlags(channelAccessToken, channelID, channelSecret)
+			if err != nil {
+				return err
+			}
+			if creds == nil {
+				return fmt.Errorf("--token, or --channel-id with --channel-secret, is required")
+			}
+
+			if store == nil {
+				store, err = openSecretsStore()
+				if err != nil {
+					return fmt.Errorf("failed to open keyring: %w", err)
+				}
+			}
+			if _, err := findStoredAccount(store, accountName); err == nil {
+				return fmt.Errorf("account %q already exists; remove it first with: line account remove %s", accountName, accountName)
+			}
+
+			if flags.DryRun {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[DRY-RUN] Would validate and add account %s\n", accountName)
+				return nil
+			}
+
+			if client == nil {
+				client = auth.NewClient(*creds, flags.Debug, false)
+				applyNetwork(client)
+				client.SetLogger(logger)
+			}
+			info, err := client.GetBotInfo(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to validate credentials: %w", err)
+			}
+
+			if err := store.Set(accountName, *creds, info.DisplayName); err != nil {
+				return fmt.Errorf("failed to save credentials: %w", err)
+			}
+			if primary {
+				if err := store.SetPrimary(accountName); err != nil {
+					return fmt.Errorf("failed to set default account: %w", err)
+				}
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"account": accountName, "botName": info.DisplayName, "basicId": info.BasicID, "primary": primary})
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added account %s (%s %s)\n", accountName, info.DisplayName, info.BasicID)
+			if primary {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Default account: %s\n", accountName)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&accountName, "name", "", "Account name (required)")
+	cmd.Flags().StringVar(&channelAccessToken, "token", "", "Channel access token")
+	cmd.Flags().StringVar(&channelID, "channel-id", "", "Channel ID, to issue stateless tokens instead of storing one")
+	cmd.Flags().StringVar(&channelSecret, "channel-secret", "", "Channel secret, used with --channel-id")
+	cmd.Flags().BoolVar(&primary, "primary", false, "Make this the default account")
+
+	return cmd
+}
+
+func newAccountRemoveCmd() *cobra.Command {
+	return newAccountRemoveCmdWithStore(nil)
+}
+
+func newAccountRemoveCmdWithStore(store secrets.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a stored account",
+		Long: `Remove an account's stored credentials from the keyring.
+
+Other local data for the account (token health, audit entries, upload
+manifests) is kept; use line purge to remove everything.`,
+		Example: `  line account remove old-shop
+  line account remove old-shop --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if store == nil {
+				store, err = openSecretsStore()
+				if err != nil {
+					return fmt.Errorf("failed to open keyring: %w", err)
+				}
+			}
+
+			acc, err := findStoredAccount(store, args[0])
+			if err != nil {
+				return err
+			}
+
+			if flags.DryRun {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[DRY-RUN] Would remove account %s\n", acc.Name)
+				return nil
+			}
+
+			if !flags.Yes {
+				if !askYesNo(cmd.OutOrStdout(), cmd.InOrStdin(), fmt.Sprintf("Remove stored credentials for %s?", acc.Name)) {
+					return fmt.Errorf("remove cancelled")
+				}
+			}
+
+			if err := store.Delete(acc.Name); err != nil {
+				return fmt.Errorf("failed to remove credentials: %w", err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed account %s\n", acc.Name)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newAccountShowCmd() *cobra.Command {
+	return newAccountShowCmdWithStore(nil)
+}
+
+func newAccountShowCmdWithStore(store secrets.Store) *cobra.Command {
+	var revealToken bool
+
+	cmd := &cobra.Command{
+		Use:   "show [name]",
+		Short: "Show a stored account",
+		Long: `Show a stored account: its bot, whether it is the default, and how it
+authenticates. The account defaults to the one commands would use.
+
+The token is masked unless --reveal-token is given; reveals are recorded
+in the audit log.`,
+		Example: `  line account show
+  line account show my-shop --reveal-token`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{noPagerAnnotation: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if store == nil {
+				store, err = openSecretsStore()
+				if err != nil {
+					return fmt.Errorf("failed to open keyring: %w", err)
+				}
+			}
+
+			name := flags.Account
+			if len(args) == 1 {
+				name = args[0]
+			}
+			if name == "" {
+				name, err = defaultStoredAccount(store)
+				if err != nil {
+					return err
+				}
+			}
+			acc, err := findStoredAccount(store, name)
+			if err != nil {
+				return err
+			}
+			creds, err := store.Get(acc.Name)
+			if err != nil {
+				return fmt.Errorf("failed to get credentials for %s: %w", acc.Name, err)
+			}
+
+			credentials := "channel access token"
+			token := maskSecret(creds.ChannelAccessToken)
+			if creds.UsesChannelSecret() {
+				credentials = "channel ID and secret (stateless tokens)"
+				token = ""
+			} else if revealToken {
+				if err := recordTokenReveal(cmd, acc.Name, creds.ChannelAccessToken, "show"); err != nil {
+					return err
+				}
+				token = creds.ChannelAccessToken
+			}
+
+			if flags.Output == "json" {
+				out := map[string]any{
+					"name":        acc.Name,
+					"botName":     acc.BotName,
+					"primary":     acc.IsPrimary,
+					"credentials": credentials,
+				}
+				if !acc.CreatedAt.IsZero() {
+					out["createdAt"] = acc.CreatedAt
+				}
+				if creds.ChannelID != "" {
+					out["channelId"] = creds.ChannelID
+				}
+				if token != "" {
+					out["channelAccessToken"] = token
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+
+			w := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(w, "Account:     %s\n", acc.Name)
+			if acc.BotName != "" {
+				_, _ = fmt.Fprintf(w, "Bot:         %s\n", acc.BotName)
+			}
+			_, _ = fmt.Fprintf(w, "Primary:     %v\n", acc.IsPrimary)
+			if !acc.CreatedAt.IsZero() {
+				_, _ = fmt.Fprintf(w, "Created:     %s\n", acc.CreatedAt.Format("2006-01-02"))
+			}
+			_, _ = fmt.Fprintf(w, "Credentials: %s\n", credentials)
+			if creds.ChannelID != "" {
+				_, _ = fmt.Fprintf(w, "Channel ID:  %s\n", creds.ChannelID)
+			}
+			if token != "" {
+				_, _ = fmt.Fprintf(w, "Token:       %s\n", token)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&revealToken, "reveal-token", false, "Show the full token (recorded in the audit log)")
+
+	return cmd
+}
+
+func newAccountMigrateStoreCmd() *cobra.Command {
+	return newAccountMigrateStoreCmdWithStores(nil)
+}
+
+// newAccountMigrateStoreCmdWithStores uses openStore, if set, to open the
+// store of each kind instead of the real keychain and credentials file.
+func newAccountMigrateStoreCmdWithStores(openStore func(kind string) (*secrets.KeyringStore, error)) *cobra.Command {
+	var from string
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "migrate-store",
+		Short: "Move stored credentials between credential stores",
+		Long: `Move every stored account from one credential store to another and switch
+the credential_store config setting to match. Stores are:
+
+  keychain        the OS keychain
+  file            a plaintext file readable only by you
+  encrypted-file  a file encrypted with a passphrase, read from
+                  LINE_CREDENTIALS_PASSPHRASE or prompted for
+
+Credentials move from the store in use (--from) to --to. Accounts already
+in the destination are never overwritten; if one exists in both, nothing
+is moved.`,
+		Example: `  # Headless server without a keychain
+  line account migrate-store --to encrypted-file
+
+  # Back to the OS keychain
+  line account migrate-store --from encrypted-file --to keychain`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				from = getDefault(configuredCredentialStore(), secrets.StoreKeychain)
+			}
+			for _, kind := range []string{from, to} {
+				switch kind {
+				case secrets.StoreKeychain, secrets.StoreFile, secrets.StoreEncryptedFile:
+				default:
+					return fmt.Errorf("unknown credential store %q (use %s, %s, or %s)", kind, secrets.StoreKeychain, secrets.StoreFile, secrets.StoreEncryptedFile)
+				}
+			}
+			if from == to {
+				return fmt.Errorf("credentials are already in the %s store; use --from to name the store to move them from", to)
+			}
+
+			open := openStore
+			if open == nil {
+				open = openStoreKind
+			}
+			src, err := open(from)
+			if err != nil {
+				return fmt.Errorf("failed to open %s store: %w", from, err)
+			}
+			dst, err := open(to)
+			if err != nil {
+				return fmt.Errorf("failed to open %s store: %w", to, err)
+			}
+
+			if flags.DryRun {
+				accounts, err := src.List()
+				if err != nil {
+					return fmt.Errorf("failed to list accounts: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[DRY-RUN] Would move %d account(s) from %s to %s\n", len(accounts), from, to)
+				for _, acc := range accounts {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", acc.Name)
+				}
+				return nil
+			}
+
+			moved, err := secrets.Migrate(src, dst)
+			if err != nil {
+				return fmt.Errorf("failed to migrate credentials: %w", err)
+			}
+			path, err := config.Set("credential_store", to)
+			if err != nil {
+				return fmt.Errorf("moved %d account(s), but failed to update config: %w; run: line config set credential-store %s", len(moved), err, to)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"from": from, "to": to, "accounts": moved, "config": path})
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Moved %d account(s) from %s to %s\n", len(moved), from, to)
+			for _, name := range moved {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", name)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Set credential_store = %s in %s\n", to, path)
+			if to == secrets.StoreFile {
+				if file, err := secrets.FilePath(); err == nil {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Note: credentials are stored unencrypted in %s\n", file)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Store to move credentials from (default: the configured store)")
+	cmd.Flags().StringVar(&to, "to", "", "Store to move credentials to: keychain, file, or encrypted-file (required)")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// openStoreKind opens the store of the given kind without the keychain
+// store's fallback to the file. Without an OS keychain, accounts can still
+// be moved out of the encrypted file earlier versions used instead.
+func openStoreKind(kind string) (*secrets.KeyringStore, error) {
+	if kind != secrets.StoreKeychain {
+		return secrets.Open(kind, credentialsPassphrase)
+	}
+	store, err := secrets.NewKeychainStore()
+	if errors.Is(err, secrets.ErrNoKeychain) {
+		return secrets.NewLegacyFileStore()
+	}
+	return store, err
+}
+
+// findStoredAccount returns the stored account called name, ignoring case.
+func findStoredAccount(store secrets.Store, name string) (*secrets.AccountInfo, error) {
+	name = strings.TrimSpace(name)
+	accounts, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	names := make([]string, 0, len(accounts))
+	for i := range accounts {
+		if strings.EqualFold(accounts[i].Name, name) {
+			return &accounts[i], nil
+		}
+		names = append(names, accounts[i].Name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("account %q not found: no accounts configured. Run: line auth login", name)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("account %q not found (configured: %s)", name, strings.Join(names, ", "))
+}
+
+// defaultStoredAccount returns the account commands use when none is given.
+func defaultStoredAccount(store secrets.Store) (string, error) {
+	account, err := store.GetPrimary()
+	if err != nil {
+		return "", fmt.Errorf("failed to get default account: %w", err)
+	}
+	if account == "" {
+		return "", fmt.Errorf("no accounts configured. Run: line auth login")
+	}
+	return account, nil
+}
+
+// maskSecret shows only the first and last four characters of s.
+func maskSecret(s string) string {
+	if len(s) <= 12 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:4] + strings.Repeat("*", 8) + s[len(s)-4:]
+}
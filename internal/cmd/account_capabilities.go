@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/capabilities"
+	"github.com/spf13/cobra"
+)
+
+// probeRequestID is a request ID no job has, for probing progress
+// endpoints: LINE answers 400 or 404 when the feature is on the plan.
+const probeRequestID = "00000000-0000-0000-0000-000000000000"
+
+// capabilityProbes make a request that changes nothing to each feature.
+// Requests that LINE rejects as invalid still show that the feature is on
+// the plan; only a 403 shows it is not.
+var capabilityProbes = map[string]func(ctx context.Context, c *api.Client) error{
+	"richmenu-bulk": func(ctx context.Context, c *api.Client) error {
+		_, err := c.Post(ctx, "/v2/bot/richmenu/bulk/unlink", map[string]any{"userIds": []string{}})
+		return err
+	},
+	"richmenu-batch": func(ctx context.Context, c *api.Client) error {
+		_, err := c.Get(ctx, "/v2/bot/richmenu/progress/batch?requestId="+probeRequestID)
+		return err
+	},
+	"narrowcast": func(ctx context.Context, c *api.Client) error {
+		_, err := c.Get(ctx, "/v2/bot/message/progress/narrowcast?requestId="+probeRequestID)
+		return err
+	},
+	"demographics": func(ctx context.Context, c *api.Client) error {
+		_, err := c.Get(ctx, "/v2/bot/insight/demographic")
+		return err
+	},
+	"follower-ids": func(ctx context.Context, c *api.Client) error {
+		_, err := c.GetFollowerIDs(ctx, "", 1)
+		return err
+	},
+	"audiences": func(ctx context.Context, c *api.Client) error {
+		_, err := c.GetAudienceGroupsPage(ctx, 1, 1)
+		return err
+	},
+	"membership": func(ctx context.Context, c *api.Client) error {
+		_, err := c.GetMembershipPlans(ctx)
+		return err
+	},
+}
+
+// featureStatus is a feature in 'line account capabilities' output.
+type featureStatus struct {
+	Feature     string `json:"feature"`
+	Description string `json:"description"`
+	// Status is available, unavailable, or unknown
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func newAccountCapabilitiesCmd() *cobra.Command {
+	return newAccountCapabilitiesCmdWithClient(nil)
+}
+
+func newAccountCapabilitiesCmdWithClient(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "Check which plan-dependent features the account has",
+		Long: `Probe the API features that LINE enables by plan or account type, such
+as bulk rich menu linking, narrowcast, demographics, and memberships, and
+record which ones the account has.
+
+Probes change nothing: they read, or send requests LINE rejects as
+invalid. A feature is unavailable only when LINE answers 403. Commands that
+then get a 403 from an unavailable feature say so instead of reporting the
+raw error.`,
+		Example: `  line account capabilities
+  line account capabilities --account staging --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.DryRun {
+				return withExitCode(ExitUsage, fmt.Errorf("capabilities cannot be probed with --dry-run"))
+			}
+			if client == nil {
+				var err error
+				client, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			statuses := make([]featureStatus, len(capabilities.Features))
+			tasks := make([]func(context.Context) error, len(capabilities.Features))
+			for i, f := range capabilities.Features {
+				tasks[i] = func(ctx context.Context) error {
+					statuses[i] = probeFeature(ctx, client, f)
+					return nil
+				}
+			}
+			_ = runConcurrently(cmd.Context(), tasks...)
+
+			rec := &capabilities.Record{CheckedAt: clockNow(), Available: map[string]bool{}}
+			for _, st := range statuses {
+				if st.Status != "unknown" {
+					rec.Available[st.Feature] = st.Status == "available"
+				}
+			}
+			if clientAccountKey != "" {
+				if err := capabilities.Set(clientAccountKey, rec); err != nil {
+					warnf(cmd.ErrOrStderr(), "could not record capabilities: %v", err)
+				}
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			}
+
+			table := NewTable("FEATURE", "STATUS", "DESCRIPTION")
+			for _, st := range statuses {
+				desc := st.Description
+				if st.Error != "" {
+					desc += " (" + st.Error + ")"
+				}
+				table.AddRow(st.Feature, st.Status, desc)
+			}
+			table.Render(cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// probeFeature reports whether the account's plan includes f.
+func probeFeature(ctx context.Context, client *api.Client, f capabilities.Feature) featureStatus {
+	st := featureStatus{Feature: f.Name, Description: f.Description, Status: "available"}
+	err := capabilityProbes[f.Name](ctx, client)
+	var apiErr *api.APIError
+	switch {
+	case err == nil:
+	case errors.As(err, &apiErr) && apiErr.IsForbidden():
+		st.Status = "unavailable"
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusBadRequest || apiErr.IsNotFound()):
+		// Rejected as invalid, so the endpoint is open to the account
+	default:
+		st.Status = "unknown"
+		st.Error = "could not check"
+		if apiErr != nil {
+			st.Error = fmt.Sprintf("LINE answered %d", apiErr.StatusCode)
+		}
+	}
+	return st
+}
+
+// planError explains a 403 from a feature the account's plan does not
+// include, unless 'line account capabilities' found the feature available,
+// in which case the 403 is about something else and err is returned as is.
+func planError(err error) error {
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsForbidden() {
+		return err
+	}
+	f, ok := capabilities.FeatureFor(apiErr.Endpoint)
+	if !ok {
+		return err
+	}
+	hint := "run 'line account capabilities' to see which features it has"
+	if clientAccountKey != "" {
+		if rec, _ := capabilities.Get(clientAccountKey); rec != nil {
+			if available, checked := rec.Available[f.Name]; checked {
+				if available {
+					return err
+				}
+				hint = "checked " + rec.CheckedAt.Local().Format("2006-01-02")
+			}
+		}
+	}
+	return &planRestrictedError{feature: f, hint: hint, err: apiErr}
+}
+
+// planRestrictedError is a 403 from a feature the plan does not include.
+// It unwraps to the API error, so exit codes and JSON errors still carry
+// its status and request ID.
+type planRestrictedError struct {
+	feature capabilities.Feature
+	hint    string
+	err     *api.APIError
+}
+
+func (e *planRestrictedError) Error() string {
+	msg := fmt.Sprintf("%s is not available on your plan (LINE answered 403 to %s %s; %s)",
+		e.feature.Description, e.err.Method, e.err.Endpoint, e.hint)
+	if e.err.RequestID != "" {
+		msg += "\nRequest ID: " + e.err.RequestID
+	}
+	return msg
+}
+
+func (e *planRestrictedError) Unwrap() error { return e.err }
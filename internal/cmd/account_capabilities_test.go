@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/capabilities"
+)
+
+func TestAccountCapabilitiesCmd_ProbesAndRecords(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	oldKey := clientAccountKey
+	clientAccountKey = "prod"
+	defer func() { clientAccountKey = oldKey }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v2/bot/richmenu/bulk/"), r.URL.Path == "/v2/bot/insight/demographic":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"Access to this API is not available for your account"}`))
+		case strings.Contains(r.URL.Path, "/progress/"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		case r.URL.Path == "/v2/bot/membership/list":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient("token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAccountCapabilitiesCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var statuses []featureStatus
+	if err := json.Unmarshal(out.Bytes(), &statuses); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	got := map[string]string{}
+	for _, st := range statuses {
+		got[st.Feature] = st.Status
+	}
+	want := map[string]string{
+		"richmenu-bulk":  "unavailable",
+		"richmenu-batch": "available",
+		"narrowcast":     "available",
+		"demographics":   "unavailable",
+		"follower-ids":   "available",
+		"audiences":      "available",
+		"membership":     "unknown",
+	}
+	for feature, status := range want {
+		if got[feature] != status {
+			t.Errorf("%s: got %q, want %q", feature, got[feature], status)
+		}
+	}
+
+	rec, err := capabilities.Get("prod")
+	if err != nil || rec == nil {
+		t.Fatalf("expected recorded capabilities, got %v, %v", rec, err)
+	}
+	if available, ok := rec.Available["richmenu-bulk"]; !ok || available {
+		t.Errorf("expected richmenu-bulk recorded unavailable: %+v", rec.Available)
+	}
+	if _, ok := rec.Available["membership"]; ok {
+		t.Errorf("expected unchecked membership not to be recorded: %+v", rec.Available)
+	}
+}
+
+func TestPlanError(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	oldKey := clientAccountKey
+	clientAccountKey = "prod"
+	defer func() { clientAccountKey = oldKey }()
+
+	forbidden := func(endpoint string) error {
+		return &api.APIError{StatusCode: http.StatusForbidden, Method: "POST", Endpoint: endpoint, RequestID: "req-1"}
+	}
+
+	err := planError(forbidden("/v2/bot/richmenu/bulk/link"))
+	if !strings.HasPrefix(err.Error(), "Bulk rich menu linking is not available on your plan") ||
+		!strings.Contains(err.Error(), "run 'line account capabilities'") {
+		t.Errorf("unexpected message: %v", err)
+	}
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) || ExitCode(err) != ExitAuth {
+		t.Errorf("expected the API error to stay wrapped, got %v", err)
+	}
+
+	// Other 403s and other errors are left alone
+	if err := forbidden("/v2/bot/message/push"); planError(err) != err {
+		t.Error("expected a 403 outside the known features to be left alone")
+	}
+	notFound := &api.APIError{StatusCode: http.StatusNotFound, Endpoint: "/v2/bot/richmenu/bulk/link"}
+	if planError(notFound) != error(notFound) {
+		t.Error("expected a 404 to be left alone")
+	}
+
+	// A feature found available is not blamed on the plan
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	_ = capabilities.Set("prod", &capabilities.Record{CheckedAt: now, Available: map[string]bool{"richmenu-bulk": true, "demographics": false}})
+	if err := forbidden("/v2/bot/richmenu/bulk/link"); planError(err) != err {
+		t.Error("expected a 403 from an available feature to be left alone")
+	}
+	if err := planError(forbidden("/v2/bot/insight/demographic")); !strings.Contains(err.Error(), "checked 2026-03-01") {
+		t.Errorf("expected the recorded check in the message, got %v", err)
+	}
+}
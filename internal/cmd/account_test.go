@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+)
+
+func TestAccountCmd_HasSubcommands(t *testing.T) {
+	cmd := newAccountCmd()
+	for _, name := range []string{"list", "add", "remove", "show", "use", "current", "migrate-store"} {
+		found := false
+		for _, sub := range cmd.Commands() {
+			if sub.Name() == name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected subcommand %q", name)
+		}
+	}
+}
+
+func TestAccountUseCmd_SetsPrimary(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+	_ = store.Set("staging", secrets.Credentials{ChannelAccessToken: "token2"}, "")
+	_ = store.SetPrimary("staging")
+
+	cmd := newAccountUseCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"My-Shop"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if primary, _ := store.GetPrimary(); primary != "my-shop" {
+		t.Errorf("expected my-shop to be primary, got %q", primary)
+	}
+	if !strings.Contains(out.String(), "Default account: my-shop") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestAccountUseCmd_UnknownAccount(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+	_ = store.SetPrimary("my-shop")
+
+	cmd := newAccountUseCmdWithStore(store)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"other"})
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), `account "other" not found (configured: my-shop)`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if primary, _ := store.GetPrimary(); primary != "my-shop" {
+		t.Errorf("expected primary to be unchanged, got %q", primary)
+	}
+}
+
+func TestAccountUseCmd_WarnsWhenOverridden(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+
+	oldAccount := flags.Account
+	flags.Account = "staging"
+	defer func() { flags.Account = oldAccount }()
+
+	cmd := newAccountUseCmdWithStore(store)
+	var stderr bytes.Buffer
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"my-shop"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "staging is still selected") {
+		t.Errorf("expected override note, got: %s", stderr.String())
+	}
+}
+
+func TestAccountCurrentCmd(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+	_ = store.Set("staging", secrets.Credentials{ChannelAccessToken: "token2"}, "")
+	_ = store.SetPrimary("staging")
+
+	oldAccount, oldOutput := flags.Account, flags.Output
+	defer func() { flags.Account, flags.Output = oldAccount, oldOutput }()
+
+	flags.Account, flags.Output = "", "text"
+	cmd := newAccountCurrentCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "staging\n" {
+		t.Errorf("expected default account, got %q", out.String())
+	}
+
+	flags.Account, flags.Output = "my-shop", "json"
+	cmd = newAccountCurrentCmdWithStore(store)
+	out.Reset()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got["account"] != "my-shop" || got["source"] != "flag" {
+		t.Errorf("unexpected result: %v", got)
+	}
+}
+
+func TestAccountCurrentCmd_NoAccounts(t *testing.T) {
+	oldAccount := flags.Account
+	flags.Account = ""
+	defer func() { flags.Account = oldAccount }()
+
+	cmd := newAccountCurrentCmdWithStore(newMockStore())
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestAccountCmd_SetPrimaryAlias(t *testing.T) {
+	c, _, err := newAccountCmd().Find([]string{"set-primary"})
+	if err != nil || c.Name() != "use" {
+		t.Errorf("expected set-primary to run use, got %v, %v", c, err)
+	}
+}
+
+// newBotInfoClient returns a client for a server that answers bot info
+// requests with status.
+func newBotInfoClient(t *testing.T, status int) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/info" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"userId":"U1","basicId":"@shop","displayName":"My Shop"}`))
+		} else {
+			_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestAccountAddCmd_ValidatesAndStores(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("staging", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+
+	cmd := newAccountAddCmdWithClient(newBotInfoClient(t, http.StatusOK), store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--name", "my-shop", "--token", "new-token", "--primary"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	creds, err := store.Get("my-shop")
+	if err != nil || creds.ChannelAccessToken != "new-token" {
+		t.Fatalf("expected stored token, got %v, %v", creds, err)
+	}
+	if store.accountMeta["my-shop"].BotName != "My Shop" {
+		t.Errorf("expected bot name to be stored, got %+v", store.accountMeta["my-shop"])
+	}
+	if primary, _ := store.GetPrimary(); primary != "my-shop" {
+		t.Errorf("expected my-shop to be primary, got %q", primary)
+	}
+	if !strings.Contains(out.String(), "Added account my-shop (My Shop @shop)") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestAccountAddCmd_InvalidCredentials(t *testing.T) {
+	store := newMockStore()
+
+	cmd := newAccountAddCmdWithClient(newBotInfoClient(t, http.StatusUnauthorized), store)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--name", "my-shop", "--token", "bad-token"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "failed to validate credentials") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, err := store.Get("my-shop"); err == nil {
+		t.Error("expected invalid credentials not to be stored")
+	}
+}
+
+func TestAccountAddCmd_Validation(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing name", []string{"--token", "t"}, "--name is required"},
+		{"missing credentials", []string{"--name", "new"}, "is required"},
+		{"secret without id", []string{"--name", "new", "--channel-secret", "s"}, "must be used together"},
+		{"existing account", []string{"--name", "My-Shop", "--token", "t"}, "already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newAccountAddCmdWithClient(newBotInfoClient(t, http.StatusOK), store)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAccountRemoveCmd(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+
+	// Declining the prompt keeps the account
+	cmd := newAccountRemoveCmdWithStore(store)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{"my-shop"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected cancellation error")
+	}
+	if _, err := store.Get("my-shop"); err != nil {
+		t.Fatal("expected account to be kept")
+	}
+
+	cmd = newAccountRemoveCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader("y\n"))
+	cmd.SetArgs([]string{"my-shop"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.Get("my-shop"); err == nil {
+		t.Error("expected account to be removed")
+	}
+	if !strings.Contains(out.String(), "Removed account my-shop") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestAccountShowCmd_MasksToken(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "abcdefgh12345678xyz9"}, "My Shop")
+	_ = store.SetPrimary("my-shop")
+
+	oldAccount, oldOutput := flags.Account, flags.Output
+	flags.Account, flags.Output = "", "text"
+	defer func() { flags.Account, flags.Output = oldAccount, oldOutput }()
+
+	cmd := newAccountShowCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"Account:     my-shop", "Bot:         My Shop", "Primary:     true", "Token:       abcd********xyz9"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, got)
+		}
+	}
+	if n, _ := audit.CountAccount("my-shop"); n != 0 {
+		t.Errorf("expected no audit entry without --reveal-token, got %d", n)
+	}
+}
+
+func TestAccountShowCmd_RevealToken(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store := newMockStore()
+	_ = store.Set("my-shop", secrets.Credentials{ChannelAccessToken: "abcdefgh12345678xyz9"}, "")
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAccountShowCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"my-shop", "--reveal-token"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got["channelAccessToken"] != "abcdefgh12345678xyz9" {
+		t.Errorf("expected full token, got %v", got["channelAccessToken"])
+	}
+	if n, _ := audit.CountAccount("my-shop"); n != 1 {
+		t.Errorf("expected the reveal to be audited, got %d entries", n)
+	}
+}
+
+func TestAccountMigrateStoreCmd(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	stores := map[string]*secrets.KeyringStore{
+		secrets.StoreKeychain: secrets.NewFileStore(filepath.Join(dir, "keychain.json")),
+		secrets.StoreFile:     secrets.NewFileStore(filepath.Join(dir, "file.json")),
+	}
+	_ = stores[secrets.StoreKeychain].Set("my-shop", secrets.Credentials{ChannelAccessToken: "token1"}, "")
+	open := func(kind string) (*secrets.KeyringStore, error) { return stores[kind], nil }
+
+	cmd := newAccountMigrateStoreCmdWithStores(open)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--from", "keychain", "--to", "file"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := stores[secrets.StoreFile].Get("my-shop"); err != nil {
+		t.Errorf("expected account in the file store: %v", err)
+	}
+	if _, err := stores[secrets.StoreKeychain].Get("my-shop"); err == nil {
+		t.Error("expected account to be removed from the keychain store")
+	}
+	if !strings.Contains(out.String(), "Moved 1 account(s) from keychain to file") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	loaded, err := config.Load()
+	if err != nil || loaded.CredentialStore != "file" {
+		t.Errorf("expected credential_store to be set to file, got %+v, %v", loaded, err)
+	}
+}
+
+func TestAccountMigrateStoreCmd_InvalidTarget(t *testing.T) {
+	cmd := newAccountMigrateStoreCmdWithStores(func(kind string) (*secrets.KeyringStore, error) {
+		t.Fatal("expected no store to be opened")
+		return nil, nil
+	})
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--to", "vault"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// rawRequestMethods are the methods "api request" sends.
+var rawRequestMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true,
+}
+
+func newAPICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api",
+		Short: "Make raw Messaging API requests",
+		Long:  "Call Messaging API endpoints directly, for endpoints the CLI does not wrap yet.",
+	}
+
+	cmd.AddCommand(newAPIRequestCmd())
+
+	return cmd
+}
+
+func newAPIRequestCmd() *cobra.Command {
+	return newAPIRequestCmdWithClient(nil)
+}
+
+func newAPIRequestCmdWithClient(client *api.Client) *cobra.Command {
+	var body string
+	var bodyFile string
+	var contentType string
+	var dataHost bool
+	var include bool
+
+	cmd := &cobra.Command{
+		Use:   "request METHOD PATH",
+		Short: "Send an authorized request and print the raw response",
+		Long: `Send a request to a Messaging API endpoint with the account's credentials
+and print the response body as is.
+
+PATH is a path such as /v2/bot/info, or a full https://api.line.me or
+https://api-data.line.me URL. Use --data-host for content endpoints on
+api-data.line.me when giving a path.
+
+The body comes from --body or --body-file (- for stdin) and is sent as JSON
+unless --content-type says otherwise. A response with status 400 or more is
+printed too, and the command then fails with the usual API error.
+
+Global flags apply: --account, --dry-run, --debug, --max-retries, and
+--rate-limit.`,
+		Example: `  # List rich menus
+  line api request GET /v2/bot/richmenu/list
+
+  # Send a push message
+  line api request POST /v2/bot/message/push --body '{"to":"U123","messages":[{"type":"text","text":"Hi"}]}'
+
+  # Body from a file, with the status line and headers
+  line api request POST /v2/bot/richmenu --body-file menu.json --include
+
+  # Upload a rich menu image to api-data.line.me
+  line api request POST /v2/bot/richmenu/richmenu-xxx/content --data-host --body-file menu.png --content-type image/png`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method := strings.ToUpper(args[0])
+			if !rawRequestMethods[method] {
+				return withExitCode(ExitUsage, fmt.Errorf("unsupported method %q: use GET, POST, PUT, PATCH, DELETE, or HEAD", args[0]))
+			}
+			path, onDataHost, err := parseRequestPath(args[1])
+			if err != nil {
+				return withExitCode(ExitUsage, err)
+			}
+			if body != "" && bodyFile != "" {
+				return withExitCode(ExitUsage, fmt.Errorf("--body and --body-file cannot be used together"))
+			}
+
+			var data []byte
+			switch {
+			case body != "":
+				data = []byte(body)
+			case bodyFile != "":
+				data, err = readFileOrStdin(bodyFile)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read body: %w", err)
+			}
+			if data != nil && (method == "GET" || method == "HEAD") {
+				return withExitCode(ExitUsage, fmt.Errorf("%s requests cannot have a body", method))
+			}
+			if data != nil && contentType == "application/json" && !json.Valid(data) {
+				return withExitCode(ExitUsage, fmt.Errorf("body is not valid JSON: use --content-type for other bodies"))
+			}
+
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			resp, reqErr := c.Raw(cmd.Context(), method, path, data, contentType, dataHost || onDataHost)
+			if resp == nil {
+				return reqErr
+			}
+
+			out := cmd.OutOrStdout()
+			if include {
+				_, _ = fmt.Fprintf(out, "HTTP %s\n", resp.Status)
+				names := make([]string, 0, len(resp.Headers))
+				for name := range resp.Headers {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					for _, v := range resp.Headers[name] {
+						_, _ = fmt.Fprintf(out, "%s: %s\n", name, v)
+					}
+				}
+				_, _ = fmt.Fprintln(out)
+			}
+			_, _ = out.Write(resp.Body)
+			if len(resp.Body) > 0 && !bytes.HasSuffix(resp.Body, []byte("\n")) {
+				_, _ = fmt.Fprintln(out)
+			}
+			return reqErr
+		},
+	}
+
+	cmd.Flags().StringVar(&body, "body", "", "Request body")
+	cmd.Flags().StringVar(&bodyFile, "body-file", "", "File to read the request body from (- for stdin)")
+	cmd.Flags().StringVar(&contentType, "content-type", "application/json", "Content-Type of the request body")
+	cmd.Flags().BoolVar(&dataHost, "data-host", false, "Send to api-data.line.me, the host for content uploads and downloads")
+	cmd.Flags().BoolVarP(&include, "include", "i", false, "Print the response status line and headers before the body")
+
+	return cmd
+}
+
+// parseRequestPath returns the path (with query) of an "api request" target
+// and whether it names the data host. target is a path or a URL on one of
+// the LINE API hosts.
+func parseRequestPath(target string) (string, bool, error) {
+	if strings.HasPrefix(target, "/") {
+		return target, false, nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "https" {
+		return "", false, fmt.Errorf("invalid path %q: use a path like /v2/bot/info or an https://api.line.me URL", target)
+	}
+	switch "https://" + u.Host {
+	case api.BaseURL, api.DataBaseURL:
+	default:
+		return "", false, fmt.Errorf("invalid host %q: only %s and %s are allowed, so credentials are never sent elsewhere", u.Host, api.BaseURL, api.DataBaseURL)
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path, "https://"+u.Host == api.DataBaseURL, nil
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+type rawRequest struct {
+	method, uri, contentType, auth string
+	body                           string
+}
+
+func rawRequestServer(t *testing.T, status int, respBody string, got *rawRequest) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		*got = rawRequest{
+			method:      r.Method,
+			uri:         r.URL.RequestURI(),
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+			body:        string(data),
+		}
+		w.Header().Set("X-Line-Request-Id", "req-1")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(respBody))
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestAPIRequestCmd_Get(t *testing.T) {
+	var got rawRequest
+	cmd := newAPIRequestCmdWithClient(rawRequestServer(t, http.StatusOK, `{"richmenus":[]}`, &got))
+	cmd.SetArgs([]string{"get", "/v2/bot/richmenu/list?limit=5"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.method != "GET" || got.uri != "/v2/bot/richmenu/list?limit=5" || got.auth != "Bearer test-token" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if out.String() != "{\"richmenus\":[]}\n" {
+		t.Errorf("expected raw body, got %q", out.String())
+	}
+}
+
+func TestAPIRequestCmd_PostBodyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(path, []byte(`{"to":"U123"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var got rawRequest
+	cmd := newAPIRequestCmdWithClient(rawRequestServer(t, http.StatusOK, `{}`, &got))
+	cmd.SetArgs([]string{"POST", "https://api.line.me/v2/bot/message/push", "--body-file", path, "--include"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.method != "POST" || got.uri != "/v2/bot/message/push" || got.body != `{"to":"U123"}` || got.contentType != "application/json" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if !strings.HasPrefix(out.String(), "HTTP 200 OK\n") || !strings.Contains(out.String(), "X-Line-Request-Id: req-1\n") {
+		t.Errorf("expected status line and headers, got %q", out.String())
+	}
+}
+
+func TestAPIRequestCmd_ErrorStatusPrintsBody(t *testing.T) {
+	var got rawRequest
+	cmd := newAPIRequestCmdWithClient(rawRequestServer(t, http.StatusBadRequest, `{"message":"The request body has 1 error(s)"}`, &got))
+	cmd.SetArgs([]string{"POST", "/v2/bot/message/push", "--body", `{}`})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err := cmd.Execute()
+	if apiErr := api.AsAPIError(err); apiErr == nil || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected API error 400, got %v", err)
+	}
+	if !strings.Contains(out.String(), "The request body has 1 error(s)") {
+		t.Errorf("expected error body on stdout, got %q", out.String())
+	}
+}
+
+func TestAPIRequestCmd_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"bad method", []string{"FETCH", "/v2/bot/info"}, "unsupported method"},
+		{"other host", []string{"GET", "https://example.com/v2/bot/info"}, "invalid host"},
+		{"relative path", []string{"GET", "v2/bot/info"}, "invalid path"},
+		{"GET with body", []string{"GET", "/v2/bot/info", "--body", "{}"}, "cannot have a body"},
+		{"invalid JSON", []string{"POST", "/v2/bot/message/push", "--body", "{"}, "not valid JSON"},
+		{"both bodies", []string{"POST", "/x", "--body", "{}", "--body-file", "b.json"}, "cannot be used together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newAPIRequestCmdWithClient(api.NewClient("test-token", false, false))
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+			if ExitCode(err) != ExitUsage {
+				t.Errorf("expected usage exit code, got %d", ExitCode(err))
+			}
+		})
+	}
+}
+
+func TestParseRequestPath_DataHost(t *testing.T) {
+	path, data, err := parseRequestPath("https://api-data.line.me/v2/bot/message/123/content?x=1")
+	if err != nil || path != "/v2/bot/message/123/content?x=1" || !data {
+		t.Errorf("got %q, %v, %v", path, data, err)
+	}
+}
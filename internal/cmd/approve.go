@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/approval"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// campaignFile is a set of sends that is approved and executed as a unit.
+type campaignFile struct {
+	Name  string         `yaml:"name"`
+	Sends []campaignSend `yaml:"sends"`
+}
+
+// campaignSend is a single message send within a campaign.
+type campaignSend struct {
+	// Type is push, multicast, or broadcast
+	Type string `yaml:"type"`
+	// To is the recipient user ID (push) or user IDs (multicast)
+	To stringList `yaml:"to,omitempty"`
+	// Text sends a plain text message
+	Text string `yaml:"text,omitempty"`
+	// Message is any Messaging API message object (flex, image, ...)
+	Message map[string]any `yaml:"message,omitempty"`
+}
+
+// stringList unmarshals from either a single YAML string or a list of strings.
+type stringList []string
+
+func (s *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = stringList{node.Value}
+		return nil
+	}
+	var values []string
+	if err := node.Decode(&values); err != nil {
+		return err
+	}
+	*s = values
+	return nil
+}
+
+// parseCampaign parses and validates a campaign file.
+func parseCampaign(data []byte) (*campaignFile, error) {
+	var c campaignFile
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid campaign file: %w", err)
+	}
+	if len(c.Sends) == 0 {
+		return nil, fmt.Errorf("invalid campaign file: no sends defined")
+	}
+
+	for i, s := range c.Sends {
+		n := i + 1
+		switch s.Type {
+		case "push":
+			if len(s.To) != 1 {
+				return nil, fmt.Errorf("send %d: push requires exactly one recipient in 'to'", n)
+			}
+		case "multicast":
+			if len(s.To) == 0 || len(s.To) > 500 {
+				return nil, fmt.Errorf("send %d: multicast requires 1-500 recipients in 'to', got %d", n, len(s.To))
+			}
+		case "broadcast":
+			if len(s.To) > 0 {
+				return nil, fmt.Errorf("send %d: broadcast does not take 'to'", n)
+			}
+		default:
+			return nil, fmt.Errorf("send %d: unsupported type %q (use push, multicast, or broadcast)", n, s.Type)
+		}
+		if (s.Text == "") == (s.Message == nil) {
+			return nil, fmt.Errorf("send %d: specify exactly one of 'text' or 'message'", n)
+		}
+	}
+	return &c, nil
+}
+
+// message returns the Messaging API message object for the send.
+func (s campaignSend) message() any {
+	if s.Text != "" {
+		return api.TextMessage{Type: "text", Text: s.Text}
+	}
+	return s.Message
+}
+
+func newApproveCmd() *cobra.Command {
+	var campaignPath string
+	var keyPath string
+	var approver string
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "Sign a campaign file so it can be sent",
+		Long: `Sign a campaign file with an approver's Ed25519 private key.
+
+The signature file records the SHA-256 of the campaign, so any edit after
+approval invalidates it. Run the campaign with 'line send --approval',
+which verifies the signature against trusted public keys before sending.
+
+A campaign file lists the sends to perform:
+
+  name: spring-sale
+  sends:
+    - type: broadcast
+      text: "Spring sale starts today!"
+    - type: multicast
+      to: [U123..., U456...]
+      message: {type: sticker, packageId: "446", stickerId: "1988"}
+
+Keys are standard PEM Ed25519 keys; create a pair with 'line approve keygen'
+or 'openssl genpkey -algorithm ed25519'.`,
+		Example: `  # Approve a campaign (writes campaign.sig next to the file)
+  line approve --file campaign.yaml --key approver.key --approver "Jane Doe"
+
+  # Create a key pair (approver.key and approver.pub)
+  line approve keygen --out approver`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if campaignPath == "" {
+				return fmt.Errorf("--file is required")
+			}
+			if keyPath == "" {
+				return fmt.Errorf("--key is required")
+			}
+
+			data, err := os.ReadFile(campaignPath)
+			if err != nil {
+				return fmt.Errorf("failed to read campaign file: %w", err)
+			}
+			campaign, err := parseCampaign(data)
+			if err != nil {
+				return err
+			}
+
+			key, err := approval.ReadPrivateKey(keyPath)
+			if err != nil {
+				return err
+			}
+
+			if outPath == "" {
+				outPath = strings.TrimSuffix(campaignPath, filepath.Ext(campaignPath)) + ".sig"
+			}
+			rel, err := filepath.Rel(filepath.Dir(outPath), campaignPath)
+			if err != nil {
+				rel = campaignPath
+			}
+
+			sig := approval.Sign(filepath.ToSlash(rel), data, approver, key, clockNow())
+			if err := approval.WriteSignature(outPath, sig); err != nil {
+				return fmt.Errorf("failed to write approval file: %w", err)
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{
+					"approval": outPath,
+					"campaign": campaign.Name,
+					"sends":    len(campaign.Sends),
+					"sha256":   sig.SHA256,
+					"keyId":    sig.KeyID,
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Approved %s (%d sends) with key %s\n", campaignPath, len(campaign.Sends), sig.KeyID)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", outPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&campaignPath, "file", "", "Campaign YAML file to approve (required)")
+	cmd.Flags().StringVar(&keyPath, "key", "", "Approver's Ed25519 private key (PEM) (required)")
+	cmd.Flags().StringVar(&approver, "approver", "", "Name of the approver, recorded in the signature")
+	cmd.Flags().StringVar(&outPath, "out", "", "Approval file to write (default: campaign file with .sig extension)")
+
+	cmd.AddCommand(newApproveKeygenCmd())
+
+	return cmd
+}
+
+func newApproveKeygenCmd() *cobra.Command {
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Create an approver key pair",
+		Long: `Create an Ed25519 key pair for approving campaigns. Writes <out>.key
+(private, keep it secret) and <out>.pub (share with whoever runs 'line send').`,
+		Example: `  line approve keygen --out approver`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if out == "" {
+				return fmt.Errorf("--out is required")
+			}
+			privPath, pubPath := out+".key", out+".pub"
+			for _, p := range []string{privPath, pubPath} {
+				if _, err := os.Stat(p); err == nil {
+					return fmt.Errorf("%s already exists", p)
+				}
+			}
+
+			pub, priv, err := approval.GenerateKey()
+			if err != nil {
+				return fmt.Errorf("failed to generate key: %w", err)
+			}
+			if err := approval.WriteKeyPair(privPath, pubPath, pub, priv); err != nil {
+				return err
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{"privateKey": privPath, "publicKey": pubPath, "keyId": approval.KeyID(pub)}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created key %s\n", approval.KeyID(pub))
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Private key: %s\nPublic key: %s\n", privPath, pubPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "Path prefix for the key files (required)")
+
+	return cmd
+}
+
+func newSendCmd() *cobra.Command {
+	return newSendCmdWithClient(nil)
+}
+
+func newSendCmdWithClient(client *api.Client) *cobra.Command {
+	var approvalPath string
+	var campaignPath string
+	var keyPaths []string
+	var overrideFreeze string
+
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send an approved campaign",
+		Long: `Send every message in a campaign file, but only if its approval file
+carries a valid signature from one of the trusted public keys and the
+campaign has not changed since it was approved.
+
+The campaign file is located from the approval file unless --file is
+given. Freeze windows apply to each send, and the send is recorded in the
+audit log.`,
+		Example: `  # Verify and send an approved campaign
+  line send --approval campaign.sig --key team.pub
+
+  # Trust several approvers
+  line send --approval campaign.sig --key alice.pub --key bob.pub`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if approvalPath == "" {
+				return fmt.Errorf("--approval is required")
+			}
+			if len(keyPaths) == 0 {
+				return fmt.Errorf("--key is required: specify at least one trusted public key")
+			}
+
+			sig, err := approval.ReadSignature(approvalPath)
+			if err != nil {
+				return err
+			}
+			if campaignPath == "" {
+				if sig.Campaign == "" {
+					return fmt.Errorf("approval file does not name a campaign: use --file")
+				}
+				campaignPath = filepath.Join(filepath.Dir(approvalPath), filepath.FromSlash(sig.Campaign))
+			}
+
+			data, err := os.ReadFile(campaignPath)
+			if err != nil {
+				return fmt.Errorf("failed to read campaign file: %w", err)
+			}
+
+			trusted := make([]ed25519.PublicKey, 0, len(keyPaths))
+			for _, p := range keyPaths {
+				pub, err := approval.ReadPublicKey(p)
+				if err != nil {
+					return err
+				}
+				trusted = append(trusted, pub)
+			}
+			if err := approval.Verify(sig, data, trusted); err != nil {
+				return fmt.Errorf("campaign is not approved: %w", err)
+			}
+
+			campaign, err := parseCampaign(data)
+			if err != nil {
+				return err
+			}
+
+			hasBroadcast := false
+			checked := make(map[string]bool)
+			for _, s := range campaign.Sends {
+				hasBroadcast = hasBroadcast || s.Type == "broadcast"
+				if checked[s.Type] {
+					continue
+				}
+				checked[s.Type] = true
+				if err := enforceFreeze(cmd, s.Type, overrideFreeze); err != nil {
+					return err
+				}
+			}
+
+			// Require confirmation for broadcasts unless --yes is set
+			if hasBroadcast && !flags.Yes {
+				if !askYesNo(cmd.OutOrStdout(), cmd.InOrStdin(), "This campaign broadcasts to ALL followers. Continue?") {
+					return fmt.Errorf("send cancelled")
+				}
+			}
+
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			for i, s := range campaign.Sends {
+				var userID string
+				if s.Type == "push" {
+					userID = s.To[0]
+				}
+				if err := c.SendMessage(cmd.Context(), s.Type, userID, s.To, s.message()); err != nil {
+					return fmt.Errorf("failed to send %d of %d (%s): %w", i+1, len(campaign.Sends), s.Type, err)
+				}
+				if flags.Output != "json" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Sent %d/%d: %s\n", i+1, len(campaign.Sends), describeCampaignSend(s))
+				}
+			}
+
+			if err := audit.Append(audit.Entry{
+				Time:    clockNow().UTC(),
+				Account: flags.Account,
+				Action:  "campaign.send",
+				Details: map[string]any{
+					"campaign": campaign.Name,
+					"sha256":   sig.SHA256,
+					"approver": sig.Approver,
+					"keyId":    sig.KeyID,
+					"sends":    len(campaign.Sends),
+				},
+			}); err != nil {
+				return fmt.Errorf("failed to record campaign send: %w", err)
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{
+					"campaign":   campaign.Name,
+					"status":     "sent",
+					"sends":      len(campaign.Sends),
+					"approvedBy": sig.Approver,
+					"keyId":      sig.KeyID,
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&approvalPath, "approval", "", "Approval (.sig) file from 'line approve' (required)")
+	cmd.Flags().StringVar(&campaignPath, "file", "", "Campaign file (default: the file named in the approval)")
+	cmd.Flags().StringArrayVar(&keyPaths, "key", nil, "Trusted approver public key (PEM); repeatable (required)")
+	addFreezeOverrideFlag(cmd, &overrideFreeze)
+
+	return cmd
+}
+
+// describeCampaignSend returns a one-line summary of a campaign send.
+func describeCampaignSend(s campaignSend) string {
+	msgType := "text"
+	if s.Message != nil {
+		if t, ok := s.Message["type"].(string); ok {
+			msgType = t
+		}
+	}
+	switch s.Type {
+	case "push":
+		return fmt.Sprintf("%s push to %s", msgType, s.To[0])
+	case "multicast":
+		return fmt.Sprintf("%s multicast to %d users", msgType, len(s.To))
+	default:
+		return fmt.Sprintf("%s broadcast", msgType)
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+const testCampaign = `name: spring-sale
+sends:
+  - type: push
+    to: U123
+    text: "Hello"
+  - type: multicast
+    to: [U1, U2]
+    message:
+      type: sticker
+      packageId: "446"
+      stickerId: "1988"
+`
+
+// approveTestCampaign writes a campaign and key pair, approves the campaign,
+// and returns the campaign, approval, and public key paths.
+func approveTestCampaign(t *testing.T, campaign string) (campaignPath, sigPath, pubPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	campaignPath = filepath.Join(dir, "campaign.yaml")
+	if err := os.WriteFile(campaignPath, []byte(campaign), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keygen := newApproveCmd()
+	keygen.SetOut(new(bytes.Buffer))
+	keygen.SetArgs([]string{"keygen", "--out", filepath.Join(dir, "team")})
+	if err := keygen.Execute(); err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+
+	approve := newApproveCmd()
+	approve.SetOut(new(bytes.Buffer))
+	approve.SetArgs([]string{"--file", campaignPath, "--key", filepath.Join(dir, "team.key"), "--approver", "Jane"})
+	if err := approve.Execute(); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+
+	return campaignPath, filepath.Join(dir, "campaign.sig"), filepath.Join(dir, "team.pub")
+}
+
+func TestParseCampaign_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"no sends", "name: x\n", "no sends"},
+		{"bad type", "sends:\n  - type: reply\n    text: hi\n", "unsupported type"},
+		{"push without recipient", "sends:\n  - type: push\n    text: hi\n", "exactly one recipient"},
+		{"broadcast with recipient", "sends:\n  - type: broadcast\n    to: U1\n    text: hi\n", "does not take 'to'"},
+		{"no content", "sends:\n  - type: broadcast\n", "exactly one of 'text' or 'message'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCampaign([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	c, err := parseCampaign([]byte(testCampaign))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Sends) != 2 || c.Sends[0].To[0] != "U123" || len(c.Sends[1].To) != 2 {
+		t.Errorf("unexpected campaign: %+v", c)
+	}
+}
+
+func TestSendCmd_SendsApprovedCampaign(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.URL.Path+" "+string(body))
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	_, sigPath, pubPath := approveTestCampaign(t, testCampaign)
+
+	cmd := newSendCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--approval", sigPath, "--key", pubPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 sends, got %d", len(bodies))
+	}
+	if !strings.HasPrefix(bodies[0], "/v2/bot/message/push ") || !strings.Contains(bodies[0], `"to":"U123"`) {
+		t.Errorf("unexpected push request: %s", bodies[0])
+	}
+	if !strings.HasPrefix(bodies[1], "/v2/bot/message/multicast ") || !strings.Contains(bodies[1], `"stickerId":"1988"`) {
+		t.Errorf("unexpected multicast request: %s", bodies[1])
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if result["approvedBy"] != "Jane" || result["sends"] != float64(2) {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestSendCmd_RejectsModifiedCampaign(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	campaignPath, sigPath, pubPath := approveTestCampaign(t, testCampaign)
+	tampered := strings.Replace(testCampaign, "U123", "U999", 1)
+	if err := os.WriteFile(campaignPath, []byte(tampered), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newSendCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--approval", sigPath, "--key", pubPath})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "campaign is not approved") {
+		t.Fatalf("expected approval error, got: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no API requests, got %d", requests)
+	}
+}
+
+func TestSendCmd_RejectsUntrustedKey(t *testing.T) {
+	_, sigPath, _ := approveTestCampaign(t, testCampaign)
+	_, _, otherPub := approveTestCampaign(t, testCampaign)
+
+	cmd := newSendCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--approval", sigPath, "--key", otherPub})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "not a trusted key") {
+		t.Errorf("expected untrusted key error, got: %v", err)
+	}
+}
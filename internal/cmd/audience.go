@@ -1,13 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/api/generated"
+	"github.com/salmonumbrella/line-official-cli/internal/audiencemanifest"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
 	"github.com/spf13/cobra"
 )
 
@@ -24,9 +26,13 @@ func newAudienceCmd() *cobra.Command {
 	cmd.AddCommand(newAudienceDeleteCmd())
 	cmd.AddCommand(newAudienceCreateCmd())
 	cmd.AddCommand(newAudienceAddUsersCmd())
+	cmd.AddCommand(newAudienceDiffCmd())
+	cmd.AddCommand(newAudienceOpsCmd())
 	cmd.AddCommand(newAudienceCreateClickCmd())
 	cmd.AddCommand(newAudienceCreateImpressionCmd())
 	cmd.AddCommand(newAudienceUpdateDescriptionCmd())
+	cmd.AddCommand(newAudienceActivateCmd())
+	cmd.AddCommand(newAudienceStatusCmd())
 	cmd.AddCommand(newAudienceSharedCmd())
 
 	return cmd
@@ -37,10 +43,31 @@ func newAudienceListCmd() *cobra.Command {
 }
 
 func newAudienceListCmdWithClient(client *api.Client) *cobra.Command {
-	return &cobra.Command{
+	var opts audienceListOptions
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List audience groups",
-		Long:  "Get a list of all audience groups associated with your LINE Official Account.",
+		Long: `Get a list of audience groups associated with your LINE Official Account.
+
+Only the first page is fetched unless --all or --limit is given. When more
+pages are available, text output ends with a "Next cursor" line and JSON
+output includes the "next" cursor; pass it back with --cursor to fetch the
+following page. --status, --description-contains, and --created-after
+filter the fetched audience groups locally.
+
+With --output ndjson, each audience group is written as a JSON line as its
+page arrives, so "--all --output ndjson" can be piped before the last page
+is fetched.`,
+		Annotations: map[string]string{ndjsonStreamAnnotation: ""},
+		Example: `  # List the first page of audience groups
+  line audience list
+
+  # Fetch the next page using the cursor from a previous JSON response
+  line audience list --cursor 2 --output json
+
+  # Every ready audience created this year
+  line audience list --all --status READY --created-after 2026-01-01`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := client
 			if c == nil {
@@ -51,94 +78,56 @@ func newAudienceListCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
-			groups, err := c.GetAudienceGroups(cmd.Context())
+			fetch := func(ctx context.Context, page, size int64) ([]generated.AudienceGroup, bool, error) {
+				resp, err := c.GetAudienceGroupsPage(ctx, page, size)
+				if err != nil {
+					return nil, false, fmt.Errorf("failed to list audience groups: %w", err)
+				}
+				var pageGroups []generated.AudienceGroup
+				if resp.AudienceGroups != nil {
+					pageGroups = *resp.AudienceGroups
+				}
+				return pageGroups, resp.HasNextPage != nil && *resp.HasNextPage, nil
+			}
+			if flags.Output == "ndjson" {
+				pageOpts, pages, err := audienceGroupPages(opts, fetch)
+				if err != nil {
+					return err
+				}
+				return streamNDJSON(cmd, pageOpts, pages, func(g generated.AudienceGroup) any { return g })
+			}
+
+			groups, next, err := fetchAudienceGroups(cmd.Context(), opts, fetch)
 			if err != nil {
-				return fmt.Errorf("failed to list audience groups: %w", err)
+				return err
 			}
 
 			if flags.Output == "json" {
+				result := map[string]any{"audienceGroups": groups}
+				if next != "" {
+					result["next"] = next
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				return enc.Encode(groups)
+				return enc.Encode(result)
 			}
 
 			if len(groups) == 0 {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No audience groups found")
-				return nil
-			}
-
-			if flags.Output == "table" {
-				table := NewTable("ID", "DESCRIPTION", "STATUS", "USERS", "CREATED")
-				for _, g := range groups {
-					var created string
-					if g.Created != nil {
-						created = time.Unix(*g.Created, 0).Format("2006-01-02")
-					}
-
-					var audienceCount string
-					if g.AudienceCount != nil {
-						audienceCount = fmt.Sprintf("%d", *g.AudienceCount)
-					}
-
-					var status string
-					if g.Status != nil {
-						status = string(*g.Status)
-					}
-
-					var description string
-					if g.Description != nil {
-						description = *g.Description
-					}
-
-					var audienceGroupID string
-					if g.AudienceGroupId != nil {
-						audienceGroupID = fmt.Sprintf("%d", *g.AudienceGroupId)
-					}
-
-					table.AddRow(audienceGroupID, description, status, audienceCount, created)
-				}
-				table.Render(cmd.OutOrStdout())
-				return nil
+			} else {
+				renderAudienceGroups(cmd, "Audience Groups:", groups)
 			}
 
-			// Default text output
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Audience Groups:")
-			for _, g := range groups {
-				var created string
-				if g.Created != nil {
-					created = time.Unix(*g.Created, 0).Format("2006-01-02")
-				} else {
-					created = "unknown"
-				}
-
-				var audienceCount int64
-				if g.AudienceCount != nil {
-					audienceCount = *g.AudienceCount
-				}
-
-				var status string
-				if g.Status != nil {
-					status = string(*g.Status)
-				} else {
-					status = "unknown"
-				}
-
-				var description string
-				if g.Description != nil {
-					description = *g.Description
-				}
-
-				var audienceGroupID int64
-				if g.AudienceGroupId != nil {
-					audienceGroupID = *g.AudienceGroupId
-				}
-
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %d  %s  (%s, %d users, created %s)\n",
-					audienceGroupID, description, status, audienceCount, created)
+			if flags.Output != "table" {
+				printNextCursor(cmd.OutOrStdout(), next)
 			}
 			return nil
 		},
 	}
+
+	addAudienceListFlags(cmd, &opts)
+
+	return cmd
 }
 
 func newAudienceGetCmd() *cobra.Command {
@@ -147,13 +136,17 @@ func newAudienceGetCmd() *cobra.Command {
 
 func newAudienceGetCmdWithClient(client *api.Client) *cobra.Command {
 	var audienceGroupID int64
+	var name string
 
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Get audience group details",
-		Long:  "Get detailed information about a specific audience group.",
+		Long: `Get detailed information about a specific audience group, given by its
+ID or, with --name, by its description if no other audience has it.`,
+		Example: `  line audience get --id 12345
+  line audience get --name "VIP 2024"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if audienceGroupID <= 0 {
+			if name == "" && audienceGroupID <= 0 {
 				return fmt.Errorf("invalid audience group ID: must be positive")
 			}
 
@@ -166,6 +159,11 @@ func newAudienceGetCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
+			audienceGroupID, err := resolveAudienceID(cmd.Context(), c, audienceGroupID, name)
+			if err != nil {
+				return err
+			}
+
 			resp, err := c.GetAudienceGroup(cmd.Context(), audienceGroupID)
 			if err != nil {
 				return fmt.Errorf("failed to get audience group: %w", err)
@@ -220,15 +218,25 @@ func newAudienceGetCmdWithClient(client *api.Client) *cobra.Command {
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID:          %d\n", audienceGroupIDVal)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\n", description)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Type:        %s\n", groupType)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:      %s\n", status)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:      %s\n", colorStatus(cmd.OutOrStdout(), status))
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Users:       %d\n", audienceCount)
+			if g.IsIfaAudience != nil {
+				recipients := "user IDs"
+				if *g.IsIfaAudience {
+					recipients = "IFAs"
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Recipients:  %s\n", recipients)
+			}
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created:     %s\n", created)
+			if resp.ExpireTimestamp != nil {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Expires:     %s\n", time.Unix(*resp.ExpireTimestamp, 0).Format("2006-01-02 15:04:05"))
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID (required)")
-	_ = cmd.MarkFlagRequired("id")
+	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID")
+	addNameFlag(cmd, &name, "audience group (its description)")
 
 	return cmd
 }
@@ -239,13 +247,14 @@ func newAudienceDeleteCmd() *cobra.Command {
 
 func newAudienceDeleteCmdWithClient(client *api.Client) *cobra.Command {
 	var audienceGroupID int64
+	var name string
 
 	cmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete an audience group",
-		Long:  "Delete an audience group by its ID.",
+		Long:  "Delete an audience group by its ID, or by its description with --name.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if audienceGroupID <= 0 {
+			if name == "" && audienceGroupID <= 0 {
 				return fmt.Errorf("invalid audience group ID: must be positive")
 			}
 
@@ -258,6 +267,14 @@ func newAudienceDeleteCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
+			audienceGroupID, err := resolveAudienceID(cmd.Context(), c, audienceGroupID, name)
+			if err != nil {
+				return err
+			}
+			if err := confirmDestructive(cmd, "delete", fmt.Sprintf("Delete audience group %d?", audienceGroupID)); err != nil {
+				return err
+			}
+
 			if err := c.DeleteAudienceGroup(cmd.Context(), audienceGroupID); err != nil {
 				return fmt.Errorf("failed to delete audience group: %w", err)
 			}
@@ -274,8 +291,8 @@ func newAudienceDeleteCmdWithClient(client *api.Client) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID to delete (required)")
-	_ = cmd.MarkFlagRequired("id")
+	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID to delete")
+	addNameFlag(cmd, &name, "audience group (its description)")
 
 	return cmd
 }
@@ -288,57 +305,79 @@ func newAudienceCreateCmdWithClient(client *api.Client) *cobra.Command {
 	var description string
 	var userIDsFile string
 	var userIDs []string
+	var maxUsers int
+	var skipInvalid bool
+	var wait bool
+	var timeout time.Duration
+	var interval time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create an audience group",
 		Long: `Create an audience group from a list of user IDs.
 User IDs can be provided via --users flag or from a file (one per line).
-When using --file, the file is uploaded directly to LINE for better performance with large files.`,
+When using --file, the file is uploaded directly to LINE for better performance with large files.
+
+User IDs are checked before anything is uploaded. Whitespace, quotes, and
+upper case hex digits are cleaned up and repeated IDs are removed. Any ID
+that is still not U followed by 32 hex digits fails the command, listing
+the line numbers of every invalid ID; --skip-invalid leaves them out
+instead and lists them as a warning.`,
 		Example: `  # Create from user IDs
   line audience create --name "VIP Users" --users U123,U456,U789
 
   # Create from file (bulk upload)
-  line audience create --name "Campaign Target" --file users.txt`,
+  line audience create --name "Campaign Target" --file users.txt
+
+  # Create and wait until the audience is ready
+  line audience create --name "VIP Users" --file users.txt --wait
+
+  # Leave out malformed IDs from an export instead of failing
+  line audience create --name "Campaign Target" --file export.txt --skip-invalid`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if description == "" {
 				return fmt.Errorf("--name is required")
 			}
 
 			c := client
+			account := flags.Account
 			if c == nil {
 				var err error
 				c, err = newAPIClient()
 				if err != nil {
 					return err
 				}
+				account, _ = requireAccount(&flags)
 			}
 
 			var resp *api.CreateAudienceResponse
 			var usersCount int
 			var apiErr error
 
+			var uploadedFile string
 			if userIDsFile != "" {
-				// Use file upload API for bulk operations
-				data, err := os.ReadFile(userIDsFile)
+				// Stream the file into a validated, de-duplicated copy and
+				// upload that with the file upload API
+				cleaned, stats, cleanup, err := cleanUsersFile(userIDsFile, userids.Options{MaxUsers: maxUsers, SkipInvalid: skipInvalid})
 				if err != nil {
-					return fmt.Errorf("failed to read file: %w", err)
-				}
-				for _, line := range strings.Split(string(data), "\n") {
-					line = strings.TrimSpace(line)
-					if line != "" {
-						usersCount++
-					}
-				}
-				if usersCount == 0 {
-					return fmt.Errorf("file contains no user IDs")
+					return err
 				}
+				defer cleanup()
+				warnSkippedUsers(cmd, stats, "line")
+				usersCount = stats.Unique
 
-				resp, apiErr = c.CreateAudienceFromFile(cmd.Context(), description, userIDsFile)
+				resp, apiErr = c.CreateAudienceFromFile(cmd.Context(), description, cleaned)
 				if apiErr != nil {
 					return fmt.Errorf("failed to create audience: %w", apiErr)
 				}
+				uploadedFile = cleaned
 			} else if len(userIDs) > 0 {
+				cleaned, stats, err := cleanUserIDs(userIDs, skipInvalid)
+				if err != nil {
+					return withExitCode(ExitUsage, err)
+				}
+				warnSkippedUsers(cmd, stats, "entry")
+				userIDs = cleaned
 				usersCount = len(userIDs)
 				resp, apiErr = c.CreateAudienceGroup(cmd.Context(), description, userIDs)
 				if apiErr != nil {
@@ -347,22 +386,60 @@ When using --file, the file is uploaded directly to LINE for better performance
 			} else {
 				return fmt.Errorf("specify --users or --file")
 			}
+			if !flags.DryRun {
+				if uploadedFile != "" {
+					recordAudienceUploadFile(account, resp.AudienceGroupID, uploadedFile)
+				} else {
+					recordAudienceUpload(account, resp.AudienceGroupID, userIDs)
+				}
+			}
+
+			if !wait {
+				if flags.Output == "json" {
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					enc.SetIndent("", "  ")
+					return enc.Encode(resp)
+				}
+
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created audience group: %d (%s)\n", resp.AudienceGroupID, description)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Users: %d\n", usersCount)
+				return nil
+			}
+
+			progress, _, err := waitForAudience(cmd, c, resp.AudienceGroupID, timeout, interval)
+			if err != nil {
+				return fmt.Errorf("created audience group %d but %w", resp.AudienceGroupID, err)
+			}
 
 			if flags.Output == "json" {
+				result := map[string]any{
+					"audienceGroupId": resp.AudienceGroupID,
+					"type":            resp.Type,
+					"description":     resp.Description,
+					"created":         resp.Created,
+					"status":          progress.Status,
+					"audienceCount":   progress.AudienceCount,
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				return enc.Encode(resp)
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created audience group: %d (%s)\n", resp.AudienceGroupID, description)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Users: %d\n", usersCount)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status: %s\n", colorStatus(cmd.OutOrStdout(), progress.Status))
 			}
-
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created audience group: %d (%s)\n", resp.AudienceGroupID, description)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Users: %d\n", usersCount)
-			return nil
+			return progress.err()
 		},
 	}
 
 	cmd.Flags().StringVar(&description, "name", "", "Audience group name/description (required)")
 	cmd.Flags().StringSliceVar(&userIDs, "users", nil, "Comma-separated user IDs")
-	cmd.Flags().StringVar(&userIDsFile, "file", "", "File containing user IDs (one per line)")
+	cmd.Flags().StringVar(&userIDsFile, "file", "", "File containing user IDs (one per line), or - for stdin")
+	addMaxUsersFlag(cmd, &maxUsers)
+	addInvalidUsersFlags(cmd, &skipInvalid)
+	addAudienceWaitFlags(cmd, &wait, &timeout, &interval)
 	_ = cmd.MarkFlagRequired("name")
 
 	return cmd
@@ -377,12 +454,27 @@ func newAudienceAddUsersCmdWithClient(client *api.Client) *cobra.Command {
 	var userIDs []string
 	var userIDsFile string
 	var description string
+	var maxUsers int
+	var skipInvalid bool
+	var diff bool
+	var wait bool
+	var timeout time.Duration
+	var interval time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "add-users",
 		Short: "Add users to an existing audience group",
 		Long: `Add user IDs to an existing audience group.
-User IDs can be provided via --users flag or from a file (one per line).`,
+User IDs can be provided via --users flag or from a file (one per line).
+
+The CLI keeps a local manifest of the IDs uploaded to each audience group.
+Use --diff to preview an upload: it fetches the audience's current count and
+reports how many IDs are duplicates within the batch, were already uploaded,
+or are new, then exits without uploading. --dry-run shows the same report
+without fetching the count.
+
+As with "audience create", invalid user IDs fail the command with their
+line numbers unless --skip-invalid is given.`,
 		Example: `  # Add users to audience
   line audience add-users --id 12345 --users U123,U456,U789
 
@@ -390,49 +482,114 @@ User IDs can be provided via --users flag or from a file (one per line).`,
   line audience add-users --id 12345 --file more-users.txt
 
   # Add users with description
-  line audience add-users --id 12345 --users U123,U456 --description "Added batch 2"`,
+  line audience add-users --id 12345 --users U123,U456 --description "Added batch 2"
+
+  # Add users and wait for the upload job to finish
+  line audience add-users --id 12345 --file more-users.txt --wait
+
+  # Preview how many of the IDs are new before uploading
+  line audience add-users --id 12345 --file more-users.txt --diff`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if audienceGroupID <= 0 {
 				return fmt.Errorf("invalid audience group ID: must be positive")
 			}
 
 			c := client
+			account := flags.Account
 			if c == nil {
 				var err error
 				c, err = newAPIClient()
 				if err != nil {
 					return err
 				}
+				account, _ = requireAccount(&flags)
 			}
 
-			var usersCount int
-
-			if userIDsFile != "" {
-				// Use file upload API for bulk operations
-				data, err := os.ReadFile(userIDsFile)
+			if userIDsFile == "" && len(userIDs) == 0 {
+				return fmt.Errorf("specify --users or --file")
+			}
+			provided := len(userIDs)
+			var listStats userids.Stats
+			if userIDsFile == "" {
+				var err error
+				userIDs, listStats, err = cleanUserIDs(userIDs, skipInvalid)
 				if err != nil {
-					return fmt.Errorf("failed to read file: %w", err)
+					return withExitCode(ExitUsage, err)
+				}
+				warnSkippedUsers(cmd, listStats, "entry")
+			}
+			fileOpts := userids.Options{MaxUsers: maxUsers, SkipInvalid: skipInvalid}
+
+			if diff || flags.DryRun {
+				var uploaded map[string]bool
+				if account != "" {
+					var err error
+					uploaded, err = audiencemanifest.Load(account, audienceGroupID)
+					if err != nil {
+						return err
+					}
+				}
+				var d audienceUploadDiff
+				if userIDsFile != "" {
+					var err error
+					d, err = diffAudienceUploadFile(audienceGroupID, userIDsFile, fileOpts, uploaded)
+					if err != nil {
+						return err
+					}
+				} else {
+					d = diffAudienceUpload(audienceGroupID, userIDs, uploaded)
+					d.Provided = provided
+					d.DuplicatesInInput = listStats.Duplicates
+					d.Invalid = listStats.Invalid
 				}
-				for _, line := range strings.Split(string(data), "\n") {
-					line = strings.TrimSpace(line)
-					if line != "" {
-						usersCount++
+
+				// Dry-run clients return empty responses, so only --diff
+				// fetches the live count
+				if !flags.DryRun {
+					resp, err := c.GetAudienceGroup(cmd.Context(), audienceGroupID)
+					if err != nil {
+						return fmt.Errorf("failed to get audience group: %w", err)
+					}
+					if g := resp.AudienceGroup; g != nil && g.AudienceCount != nil {
+						current := *g.AudienceCount
+						expected := current + int64(d.New)
+						d.CurrentCount = &current
+						d.ExpectedCount = &expected
 					}
 				}
-				if usersCount == 0 {
-					return fmt.Errorf("file contains no user IDs")
+				return printAudienceUploadDiff(cmd, d)
+			}
+
+			usersCount := len(userIDs)
+			if userIDsFile != "" {
+				// Stream the file into a validated, de-duplicated copy and
+				// upload that with the file upload API
+				cleaned, stats, cleanup, err := cleanUsersFile(userIDsFile, fileOpts)
+				if err != nil {
+					return err
 				}
+				defer cleanup()
+				warnSkippedUsers(cmd, stats, "line")
+				usersCount = stats.Unique
 
-				if err := c.AddUsersToAudienceFromFile(cmd.Context(), audienceGroupID, userIDsFile, description); err != nil {
+				if err := c.AddUsersToAudienceFromFile(cmd.Context(), audienceGroupID, cleaned, description); err != nil {
 					return fmt.Errorf("failed to add users to audience: %w", err)
 				}
-			} else if len(userIDs) > 0 {
-				usersCount = len(userIDs)
+				recordAudienceUploadFile(account, audienceGroupID, cleaned)
+			} else {
 				if err := c.AddUsersToAudience(cmd.Context(), audienceGroupID, userIDs, description); err != nil {
 					return fmt.Errorf("failed to add users to audience: %w", err)
 				}
-			} else {
-				return fmt.Errorf("specify --users or --file")
+				recordAudienceUpload(account, audienceGroupID, userIDs)
+			}
+
+			var progress audienceProgress
+			if wait {
+				var err error
+				progress, _, err = waitForAudience(cmd, c, audienceGroupID, timeout, interval)
+				if err != nil {
+					return fmt.Errorf("added users to audience group %d but %w", audienceGroupID, err)
+				}
 			}
 
 			if flags.Output == "json" {
@@ -440,20 +597,35 @@ User IDs can be provided via --users flag or from a file (one per line).`,
 					"audienceGroupId": audienceGroupID,
 					"usersAdded":      usersCount,
 				}
+				if wait {
+					result["status"] = progress.Status
+					result["jobStatus"] = progress.JobStatus
+					result["audienceCount"] = progress.AudienceCount
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				return enc.Encode(result)
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+				return progress.err()
 			}
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added %d users to audience group %d\n", usersCount, audienceGroupID)
-			return nil
+			if wait {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status: %s\n", colorStatus(cmd.OutOrStdout(), progress.state()))
+			}
+			return progress.err()
 		},
 	}
 
 	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID (required)")
 	cmd.Flags().StringSliceVar(&userIDs, "users", nil, "Comma-separated user IDs")
-	cmd.Flags().StringVar(&userIDsFile, "file", "", "File containing user IDs (one per line)")
+	cmd.Flags().StringVar(&userIDsFile, "file", "", "File containing user IDs (one per line), or - for stdin")
 	cmd.Flags().StringVar(&description, "description", "", "Description for this upload batch")
+	cmd.Flags().BoolVar(&diff, "diff", false, "Compare against the current count and previous uploads without uploading")
+	addMaxUsersFlag(cmd, &maxUsers)
+	addInvalidUsersFlags(cmd, &skipInvalid)
+	addAudienceWaitFlags(cmd, &wait, &timeout, &interval)
 	_ = cmd.MarkFlagRequired("id")
 
 	return cmd
@@ -576,19 +748,33 @@ func newAudienceUpdateDescriptionCmd() *cobra.Command {
 func newAudienceUpdateDescriptionCmdWithClient(client *api.Client) *cobra.Command {
 	var audienceGroupID int64
 	var description string
+	var name string
 
 	cmd := &cobra.Command{
-		Use:   "update-description",
-		Short: "Update audience group description",
-		Long:  "Update the description of an existing audience group.",
+		Use:     "update-description",
+		Aliases: []string{"rename"},
+		Short:   "Update audience group description",
+		Long: `Update the description (the audience's name) of an existing audience group.
+
+The description is the only audience group field the Messaging API allows
+changing after creation.`,
 		Example: `  # Update audience description
-  line audience update-description --id 12345 --description "Updated VIP Users"`,
+  line audience update-description --id 12345 --description "Updated VIP Users"
+
+  # Same, using the rename alias
+  line audience rename --id 12345 --name "Updated VIP Users"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if audienceGroupID <= 0 {
 				return fmt.Errorf("invalid audience group ID: must be positive")
 			}
+			if name != "" {
+				if description != "" && description != name {
+					return fmt.Errorf("--name and --description cannot both be set")
+				}
+				description = name
+			}
 			if description == "" {
-				return fmt.Errorf("--description is required")
+				return fmt.Errorf("--description (or --name) is required")
 			}
 
 			c := client
@@ -620,9 +806,94 @@ func newAudienceUpdateDescriptionCmdWithClient(client *api.Client) *cobra.Comman
 	}
 
 	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID (required)")
-	cmd.Flags().StringVar(&description, "description", "", "New description (required)")
+	cmd.Flags().StringVar(&description, "description", "", "New description (required unless --name is set)")
+	cmd.Flags().StringVar(&name, "name", "", "New name (same as --description)")
 	_ = cmd.MarkFlagRequired("id")
-	_ = cmd.MarkFlagRequired("description")
+
+	return cmd
+}
+
+func newAudienceActivateCmd() *cobra.Command {
+	return newAudienceActivateCmdWithClient(nil)
+}
+
+func newAudienceActivateCmdWithClient(client *api.Client) *cobra.Command {
+	var audienceGroupID int64
+	var name string
+	var wait bool
+	var timeout time.Duration
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "activate",
+		Short: "Activate an inactive audience group",
+		Long: `Activate an audience group whose status is INACTIVE so it can be used
+for messaging again. The audience moves through ACTIVATING to READY; use
+--wait to block until it finishes.`,
+		Example: `  # Activate an audience
+  line audience activate --id 12345
+
+  # Activate and wait until it is ready
+  line audience activate --id 12345 --wait
+
+  # Activate by description
+  line audience activate --name "VIP 2024"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" && audienceGroupID <= 0 {
+				return fmt.Errorf("invalid audience group ID: must be positive")
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			audienceGroupID, err := resolveAudienceID(cmd.Context(), c, audienceGroupID, name)
+			if err != nil {
+				return err
+			}
+
+			if err := c.ActivateAudienceGroup(cmd.Context(), audienceGroupID); err != nil {
+				return fmt.Errorf("failed to activate audience group: %w", err)
+			}
+
+			var progress audienceProgress
+			if wait {
+				var err error
+				progress, _, err = waitForAudience(cmd, c, audienceGroupID, timeout, interval)
+				if err != nil {
+					return fmt.Errorf("activated audience group %d but %w", audienceGroupID, err)
+				}
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{"activated": audienceGroupID}
+				if wait {
+					result["status"] = progress.Status
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+				return progress.err()
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Activated audience group: %d\n", audienceGroupID)
+			if wait {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status: %s\n", colorStatus(cmd.OutOrStdout(), progress.Status))
+			}
+			return progress.err()
+		},
+	}
+
+	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID")
+	addNameFlag(cmd, &name, "audience group (its description)")
+	addAudienceWaitFlags(cmd, &wait, &timeout, &interval)
 
 	return cmd
 }
@@ -645,10 +916,17 @@ func newAudienceSharedListCmd() *cobra.Command {
 }
 
 func newAudienceSharedListCmdWithClient(client *api.Client) *cobra.Command {
-	return &cobra.Command{
+	var opts audienceListOptions
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List shared audience groups",
-		Long:  "Get a list of all shared audience groups.",
+		Long: `Get a list of shared audience groups.
+
+Only the first page is fetched unless --all or --limit is given; text
+output ends with a "Next cursor" line when more pages are available.
+--status, --description-contains, and --created-after filter the fetched
+audience groups locally.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := client
 			if c == nil {
@@ -659,9 +937,19 @@ func newAudienceSharedListCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
-			groups, err := c.GetSharedAudienceGroups(cmd.Context())
+			groups, next, err := fetchAudienceGroups(cmd.Context(), opts, func(ctx context.Context, page, size int64) ([]generated.AudienceGroup, bool, error) {
+				resp, err := c.GetSharedAudienceGroupsPage(ctx, page, size)
+				if err != nil {
+					return nil, false, fmt.Errorf("failed to list shared audience groups: %w", err)
+				}
+				var pageGroups []generated.AudienceGroup
+				if resp.AudienceGroups != nil {
+					pageGroups = *resp.AudienceGroups
+				}
+				return pageGroups, resp.HasNextPage != nil && *resp.HasNextPage, nil
+			})
 			if err != nil {
-				return fmt.Errorf("failed to list shared audience groups: %w", err)
+				return err
 			}
 
 			if flags.Output == "json" {
@@ -672,81 +960,20 @@ func newAudienceSharedListCmdWithClient(client *api.Client) *cobra.Command {
 
 			if len(groups) == 0 {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No shared audience groups found")
-				return nil
-			}
-
-			if flags.Output == "table" {
-				table := NewTable("ID", "DESCRIPTION", "STATUS", "USERS", "CREATED")
-				for _, g := range groups {
-					var created string
-					if g.Created != nil {
-						created = time.Unix(*g.Created, 0).Format("2006-01-02")
-					}
-
-					var audienceCount string
-					if g.AudienceCount != nil {
-						audienceCount = fmt.Sprintf("%d", *g.AudienceCount)
-					}
-
-					var status string
-					if g.Status != nil {
-						status = string(*g.Status)
-					}
-
-					var description string
-					if g.Description != nil {
-						description = *g.Description
-					}
-
-					var audienceGroupID string
-					if g.AudienceGroupId != nil {
-						audienceGroupID = fmt.Sprintf("%d", *g.AudienceGroupId)
-					}
-
-					table.AddRow(audienceGroupID, description, status, audienceCount, created)
-				}
-				table.Render(cmd.OutOrStdout())
-				return nil
+			} else {
+				renderAudienceGroups(cmd, "Shared Audience Groups:", groups)
 			}
 
-			// Default text output
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Shared Audience Groups:")
-			for _, g := range groups {
-				var created string
-				if g.Created != nil {
-					created = time.Unix(*g.Created, 0).Format("2006-01-02")
-				} else {
-					created = "unknown"
-				}
-
-				var audienceCount int64
-				if g.AudienceCount != nil {
-					audienceCount = *g.AudienceCount
-				}
-
-				var status string
-				if g.Status != nil {
-					status = string(*g.Status)
-				} else {
-					status = "unknown"
-				}
-
-				var description string
-				if g.Description != nil {
-					description = *g.Description
-				}
-
-				var audienceGroupID int64
-				if g.AudienceGroupId != nil {
-					audienceGroupID = *g.AudienceGroupId
-				}
-
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %d  %s  (%s, %d users, created %s)\n",
-					audienceGroupID, description, status, audienceCount, created)
+			if flags.Output != "table" {
+				printNextCursor(cmd.OutOrStdout(), next)
 			}
 			return nil
 		},
 	}
+
+	addAudienceListFlags(cmd, &opts)
+
+	return cmd
 }
 
 func newAudienceSharedGetCmd() *cobra.Command {
@@ -828,7 +1055,7 @@ func newAudienceSharedGetCmdWithClient(client *api.Client) *cobra.Command {
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID:          %d\n", audienceGroupIDVal)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\n", description)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Type:        %s\n", groupType)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:      %s\n", status)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:      %s\n", colorStatus(cmd.OutOrStdout(), status))
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Users:       %d\n", audienceCount)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created:     %s\n", created)
 
@@ -852,3 +1079,203 @@ func newAudienceSharedGetCmdWithClient(client *api.Client) *cobra.Command {
 
 	return cmd
 }
+
+func newAudienceStatusCmd() *cobra.Command {
+	return newAudienceStatusCmdWithClient(nil)
+}
+
+func newAudienceStatusCmdWithClient(client *api.Client) *cobra.Command {
+	var audienceGroupID int64
+	var name string
+	var timeout time.Duration
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Wait for an audience group to finish processing",
+		Long: `Poll an audience group until it reaches a terminal state.
+
+An audience is still processing while its status is IN_PROGRESS or
+ACTIVATING, or while its latest upload job is QUEUED or WORKING. The command
+exits non-zero if the audience or its latest job fails, or if --timeout
+elapses first.`,
+		Example: `  # Wait for an audience to become ready
+  line audience status --id 12345
+
+  # Poll every 10 seconds for up to 30 minutes
+  line audience status --id 12345 --interval 10s --timeout 30m
+
+  # Wait for an audience given by its description
+  line audience status --name "VIP 2024"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name == "" && audienceGroupID <= 0 {
+				return fmt.Errorf("invalid audience group ID: must be positive")
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			audienceGroupID, err := resolveAudienceID(cmd.Context(), c, audienceGroupID, name)
+			if err != nil {
+				return err
+			}
+
+			progress, polls, err := waitForAudience(cmd, c, audienceGroupID, timeout, interval)
+			if err != nil {
+				return err
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{
+					"audienceGroupId": progress.AudienceGroupID,
+					"description":     progress.Description,
+					"status":          progress.Status,
+					"audienceCount":   progress.AudienceCount,
+					"jobStatus":       progress.JobStatus,
+					"polls":           polls,
+				}
+				if progress.FailedType != "" {
+					result["failedType"] = progress.FailedType
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID:          %d\n", progress.AudienceGroupID)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\n", progress.Description)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:      %s\n", colorStatus(cmd.OutOrStdout(), progress.Status))
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Users:       %d\n", progress.AudienceCount)
+				if progress.JobStatus != "" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Last Job:    %s\n", progress.JobStatus)
+				}
+			}
+
+			return progress.err()
+		},
+	}
+
+	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID")
+	addNameFlag(cmd, &name, "audience group (its description)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "Maximum time to wait")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Polling interval")
+
+	return cmd
+}
+
+// addAudienceWaitFlags registers --wait, --timeout, and --interval for
+// commands that start audience processing.
+func addAudienceWaitFlags(cmd *cobra.Command, wait *bool, timeout, interval *time.Duration) {
+	cmd.Flags().BoolVar(wait, "wait", false, "Wait until the audience finishes processing")
+	cmd.Flags().DurationVar(timeout, "timeout", 10*time.Minute, "Maximum time to wait with --wait")
+	cmd.Flags().DurationVar(interval, "interval", 5*time.Second, "Polling interval with --wait")
+}
+
+// audienceProgress summarizes an audience group's processing state.
+type audienceProgress struct {
+	AudienceGroupID int64
+	Description     string
+	Status          string
+	AudienceCount   int64
+	// JobStatus and FailedType describe the most recent upload job, if any
+	JobStatus  string
+	FailedType string
+}
+
+// newAudienceProgress extracts processing state from an audience response.
+func newAudienceProgress(resp *api.AudienceGroupDetail) audienceProgress {
+	var p audienceProgress
+	if resp == nil {
+		return p
+	}
+	if g := resp.AudienceGroup; g != nil {
+		if g.AudienceGroupId != nil {
+			p.AudienceGroupID = *g.AudienceGroupId
+		}
+		if g.Description != nil {
+			p.Description = *g.Description
+		}
+		if g.Status != nil {
+			p.Status = string(*g.Status)
+		}
+		if g.AudienceCount != nil {
+			p.AudienceCount = *g.AudienceCount
+		}
+	}
+	if resp.Jobs != nil {
+		var latest *generated.AudienceGroupJob
+		for i, job := range *resp.Jobs {
+			if latest == nil || (job.Created != nil && (latest.Created == nil || *job.Created > *latest.Created)) {
+				latest = &(*resp.Jobs)[i]
+			}
+		}
+		if latest != nil {
+			if latest.JobStatus != nil {
+				p.JobStatus = string(*latest.JobStatus)
+			}
+			if latest.FailedType != nil {
+				p.FailedType = string(*latest.FailedType)
+			}
+		}
+	}
+	return p
+}
+
+// settled reports whether the audience and its latest job are done processing.
+func (p audienceProgress) settled() bool {
+	switch generated.AudienceGroupStatus(p.Status) {
+	case generated.AudienceGroupStatusINPROGRESS, generated.AudienceGroupStatusACTIVATING:
+		return false
+	}
+	switch generated.AudienceGroupJobStatus(p.JobStatus) {
+	case generated.AudienceGroupJobStatusQUEUED, generated.AudienceGroupJobStatusWORKING:
+		return false
+	}
+	return true
+}
+
+// state describes the progress for the wait spinner.
+func (p audienceProgress) state() string {
+	if p.JobStatus == "" {
+		return p.Status
+	}
+	return fmt.Sprintf("%s (job %s)", p.Status, p.JobStatus)
+}
+
+// err returns an error if the audience or its latest job failed.
+func (p audienceProgress) err() error {
+	if generated.AudienceGroupStatus(p.Status) == generated.AudienceGroupStatusFAILED {
+		return fmt.Errorf("audience group %d failed", p.AudienceGroupID)
+	}
+	if generated.AudienceGroupJobStatus(p.JobStatus) == generated.AudienceGroupJobStatusFAILED {
+		if p.FailedType != "" {
+			return fmt.Errorf("upload job for audience group %d failed: %s", p.AudienceGroupID, p.FailedType)
+		}
+		return fmt.Errorf("upload job for audience group %d failed", p.AudienceGroupID)
+	}
+	return nil
+}
+
+// waitForAudience polls an audience group until it settles.
+func waitForAudience(cmd *cobra.Command, c *api.Client, audienceGroupID int64, timeout, interval time.Duration) (audienceProgress, int, error) {
+	var progress audienceProgress
+	polls, err := pollUntil(cmd, fmt.Sprintf("audience %d", audienceGroupID), timeout, interval, func(ctx context.Context) (bool, string, error) {
+		resp, err := c.GetAudienceGroup(ctx, audienceGroupID)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get audience group: %w", err)
+		}
+		progress = newAudienceProgress(resp)
+		if progress.AudienceGroupID == 0 {
+			progress.AudienceGroupID = audienceGroupID
+		}
+		return progress.settled(), progress.state(), nil
+	})
+	return progress, polls, err
+}
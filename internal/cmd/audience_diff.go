@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audiencemanifest"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+	"github.com/spf13/cobra"
+)
+
+// audienceUploadDiff compares a batch of user IDs against the IDs this CLI
+// has previously uploaded to the same audience group.
+type audienceUploadDiff struct {
+	AudienceGroupID   int64  `json:"audienceGroupId"`
+	CurrentCount      *int64 `json:"currentCount,omitempty"`
+	Provided          int    `json:"provided"`
+	DuplicatesInInput int    `json:"duplicatesInInput"`
+	// Invalid counts IDs left out with --skip-invalid
+	Invalid            int `json:"invalid,omitempty"`
+	PreviouslyUploaded int `json:"previouslyUploaded"`
+	New                int `json:"new"`
+	// ExpectedCount is an upper bound: LINE does not count users who have
+	// blocked the account or cannot be reached
+	ExpectedCount *int64 `json:"expectedCount,omitempty"`
+}
+
+// diffAudienceUpload counts how many of userIDs are repeated within the
+// batch, already in uploaded, or new.
+func diffAudienceUpload(audienceGroupID int64, userIDs []string, uploaded map[string]bool) audienceUploadDiff {
+	d := audienceUploadDiff{AudienceGroupID: audienceGroupID, Provided: len(userIDs)}
+	seen := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		switch {
+		case seen[id]:
+			d.DuplicatesInInput++
+		case uploaded[id]:
+			d.PreviouslyUploaded++
+		default:
+			d.New++
+		}
+		seen[id] = true
+	}
+	return d
+}
+
+func printAudienceUploadDiff(cmd *cobra.Command, d audienceUploadDiff) error {
+	if flags.Output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "Audience group %d (preview, nothing uploaded)\n", d.AudienceGroupID)
+	if d.CurrentCount != nil {
+		_, _ = fmt.Fprintf(out, "  Current users:       %d\n", *d.CurrentCount)
+	}
+	_, _ = fmt.Fprintf(out, "  IDs provided:        %d\n", d.Provided)
+	_, _ = fmt.Fprintf(out, "  Duplicates in input: %d\n", d.DuplicatesInInput)
+	if d.Invalid > 0 {
+		_, _ = fmt.Fprintf(out, "  Invalid (skipped):   %d\n", d.Invalid)
+	}
+	_, _ = fmt.Fprintf(out, "  Already uploaded:    %d\n", d.PreviouslyUploaded)
+	_, _ = fmt.Fprintf(out, "  New IDs:             %d\n", d.New)
+	if d.ExpectedCount != nil {
+		_, _ = fmt.Fprintf(out, "  Expected users:      up to %d\n", *d.ExpectedCount)
+	}
+	return nil
+}
+
+// diffAudienceUploadFile is diffAudienceUpload for a users file, streamed
+// so that only the manifest, not the file, is held in memory.
+func diffAudienceUploadFile(audienceGroupID int64, path string, opts userids.Options, uploaded map[string]bool) (audienceUploadDiff, error) {
+	d := audienceUploadDiff{AudienceGroupID: audienceGroupID}
+	stats, err := readUsersFile(path, opts, usersFileBatchSize, func(batch []string) error {
+		for _, id := range batch {
+			if uploaded[id] {
+				d.PreviouslyUploaded++
+			} else {
+				d.New++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return d, err
+	}
+	d.Provided = stats.Unique + stats.Duplicates + stats.Invalid
+	d.DuplicatesInInput = stats.Duplicates
+	d.Invalid = stats.Invalid
+	return d, nil
+}
+
+// recordAudienceUpload adds userIDs to the account's local manifest for the
+// audience group. Errors are ignored so tracking never fails a completed
+// upload.
+func recordAudienceUpload(account string, audienceGroupID int64, userIDs []string) {
+	if account == "" {
+		return
+	}
+	_, _ = audiencemanifest.Record(account, audienceGroupID, userIDs)
+}
+
+// audienceFileDiff compares a users file with the IDs this CLI has
+// uploaded to an audience group.
+type audienceFileDiff struct {
+	AudienceGroupID int64  `json:"audienceGroupId"`
+	CurrentCount    *int64 `json:"currentCount,omitempty"`
+	InFile          int    `json:"inFile"`
+	Uploaded        int    `json:"uploaded"`
+	InBoth          int    `json:"inBoth"`
+	// OnlyInFile are the IDs to add with add-users
+	OnlyInFile int `json:"onlyInFile"`
+	// OnlyUploaded are the IDs uploaded before that the file no longer has
+	OnlyUploaded int `json:"onlyUploaded"`
+	Invalid      int `json:"invalid,omitempty"`
+}
+
+func newAudienceDiffCmd() *cobra.Command {
+	return newAudienceDiffCmdWithClient(nil)
+}
+
+func newAudienceDiffCmdWithClient(client *api.Client) *cobra.Command {
+	var audienceGroupID int64
+	var userIDsFile string
+	var newOut string
+	var staleOut string
+	var maxUsers int
+	var skipInvalid bool
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare a users file with an audience's uploads",
+		Long: `Compare a users file with the user IDs uploaded to an audience group,
+reporting how many are in both, only in the file, or only in the audience.
+
+LINE does not list an audience's users, so the audience side is the
+CLI's local manifest of the IDs it has uploaded for the account; uploads
+made elsewhere are not included. The audience's current count is fetched
+for reference.
+
+Use --new-out to write the IDs only in the file, ready for
+'line audience add-users --file'. Use --stale-out to write the IDs only in
+the audience: LINE cannot remove users from an audience, so dropping them
+means creating a new audience from the file. The file is streamed, so only
+the manifest is held in memory.`,
+		Example: `  line audience diff --id 12345 --file current.txt
+
+  # Upload only the users the audience does not have yet
+  line audience diff --id 12345 --file current.txt --new-out new.txt
+  line audience add-users --id 12345 --file new.txt`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if audienceGroupID <= 0 {
+				return fmt.Errorf("invalid audience group ID: must be positive")
+			}
+
+			c := client
+			account := flags.Account
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+				account, _ = requireAccount(&flags)
+			}
+			if account == "" {
+				return fmt.Errorf("audience diff compares against the uploads recorded for a stored account; use --account")
+			}
+
+			uploaded, err := audiencemanifest.Load(account, audienceGroupID)
+			if err != nil {
+				return err
+			}
+			d := audienceFileDiff{AudienceGroupID: audienceGroupID, Uploaded: len(uploaded)}
+
+			var newIDs *usersOutput
+			if newOut != "" {
+				if newIDs, err = createUsersOutput(cmd, newOut); err != nil {
+					return err
+				}
+				defer newIDs.abort()
+			}
+			// Matched IDs are removed from uploaded, leaving the ones only
+			// in the audience
+			stats, err := readUsersFile(userIDsFile, userids.Options{MaxUsers: maxUsers, SkipInvalid: skipInvalid}, usersFileBatchSize, func(batch []string) error {
+				for _, id := range batch {
+					if uploaded[id] {
+						d.InBoth++
+						delete(uploaded, id)
+						continue
+					}
+					d.OnlyInFile++
+					if newIDs != nil {
+						if err := newIDs.write(id); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			})
+			if errors.Is(err, userids.ErrNoUsers) {
+				return fmt.Errorf("no user IDs found in file")
+			}
+			if err != nil {
+				return err
+			}
+			warnSkippedUsers(cmd, stats, "line")
+			d.InFile, d.Invalid, d.OnlyUploaded = stats.Unique, stats.Invalid, len(uploaded)
+
+			if newIDs != nil {
+				if err := newIDs.close(); err != nil {
+					return err
+				}
+			}
+			if staleOut != "" {
+				stale := make([]string, 0, len(uploaded))
+				for id := range uploaded {
+					stale = append(stale, id)
+				}
+				sort.Strings(stale)
+				out, err := createUsersOutput(cmd, staleOut)
+				if err != nil {
+					return err
+				}
+				defer out.abort()
+				for _, id := range stale {
+					if err := out.write(id); err != nil {
+						return err
+					}
+				}
+				if err := out.close(); err != nil {
+					return err
+				}
+			}
+
+			// Dry-run clients return empty responses, so the count is only
+			// fetched for real
+			if !flags.DryRun {
+				resp, err := c.GetAudienceGroup(cmd.Context(), audienceGroupID)
+				if err != nil {
+					return fmt.Errorf("failed to get audience group: %w", err)
+				}
+				if g := resp.AudienceGroup; g != nil && g.AudienceCount != nil {
+					current := *g.AudienceCount
+					d.CurrentCount = &current
+				}
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(d)
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Audience group %d\n", d.AudienceGroupID)
+			if d.CurrentCount != nil {
+				_, _ = fmt.Fprintf(out, "  Current users:      %d\n", *d.CurrentCount)
+			}
+			_, _ = fmt.Fprintf(out, "  IDs in file:        %d\n", d.InFile)
+			_, _ = fmt.Fprintf(out, "  IDs uploaded:       %d\n", d.Uploaded)
+			_, _ = fmt.Fprintf(out, "  In both:            %d\n", d.InBoth)
+			_, _ = fmt.Fprintf(out, "  Only in file:       %d\n", d.OnlyInFile)
+			_, _ = fmt.Fprintf(out, "  Only in audience:   %d\n", d.OnlyUploaded)
+			if newOut != "" {
+				_, _ = fmt.Fprintf(out, "Wrote %d new IDs to %s\n", d.OnlyInFile, newOut)
+			}
+			if staleOut != "" {
+				_, _ = fmt.Fprintf(out, "Wrote %d IDs only in the audience to %s\n", d.OnlyUploaded, staleOut)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int64Var(&audienceGroupID, "id", 0, "Audience group ID (required)")
+	cmd.Flags().StringVar(&userIDsFile, "file", "", "File containing user IDs (one per line), or - for stdin (required)")
+	cmd.Flags().StringVar(&newOut, "new-out", "", "Write the IDs only in the file to this file")
+	cmd.Flags().StringVar(&staleOut, "stale-out", "", "Write the IDs only in the audience to this file")
+	addMaxUsersFlag(cmd, &maxUsers)
+	addInvalidUsersFlags(cmd, &skipInvalid)
+	_ = cmd.MarkFlagRequired("id")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
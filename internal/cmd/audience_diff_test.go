@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audiencemanifest"
+)
+
+func TestDiffAudienceUpload(t *testing.T) {
+	uploaded := map[string]bool{"U1": true, "U2": true}
+	d := diffAudienceUpload(7, []string{"U1", "U3", "U3", "U4", "U2"}, uploaded)
+
+	if d.AudienceGroupID != 7 || d.Provided != 5 || d.DuplicatesInInput != 1 || d.PreviouslyUploaded != 2 || d.New != 2 {
+		t.Errorf("unexpected diff: %+v", d)
+	}
+}
+
+// newAudienceUploadServer serves an audience with 100 users and counts
+// upload requests.
+func newAudienceUploadServer(t *testing.T, uploads *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/bot/audienceGroup/12345" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"audienceGroup": map[string]any{"audienceGroupId": 12345, "audienceCount": 100},
+			})
+		case r.URL.Path == "/v2/bot/audienceGroup/upload" && r.Method == http.MethodPut:
+			*uploads++
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestAudienceAddUsersCmd_RecordsAndDiffs(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	var uploads int
+	server := newAudienceUploadServer(t, &uploads)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.Output = "prod", "text"
+
+	cmd := newAudienceAddUsersCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "12345", "--users", testUserID(1) + "," + testUserID(2) + "," + testUserID(3)})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids, _ := audiencemanifest.Load("prod", 12345); len(ids) != 3 {
+		t.Fatalf("expected 3 IDs in manifest, got %v", ids)
+	}
+
+	file := writeUsersFile(t, testUserID(2), testUserID(3), testUserID(4), testUserID(4), testUserID(5))
+
+	t.Run("text", func(t *testing.T) {
+		cmd := newAudienceAddUsersCmdWithClient(client)
+		cmd.SetArgs([]string{"--id", "12345", "--file", file, "--diff"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, want := range []string{
+			"preview, nothing uploaded",
+			"Current users:       100",
+			"IDs provided:        5",
+			"Duplicates in input: 1",
+			"Already uploaded:    2",
+			"New IDs:             2",
+			"Expected users:      up to 102",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("expected %q in output, got: %s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		flags.Output = "json"
+		defer func() { flags.Output = "text" }()
+
+		cmd := newAudienceAddUsersCmdWithClient(client)
+		cmd.SetArgs([]string{"--id", "12345", "--file", file, "--diff"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var d audienceUploadDiff
+		if err := json.Unmarshal(out.Bytes(), &d); err != nil {
+			t.Fatalf("expected valid JSON, got: %s", out.String())
+		}
+		if d.CurrentCount == nil || *d.CurrentCount != 100 || d.New != 2 || d.ExpectedCount == nil || *d.ExpectedCount != 102 {
+			t.Errorf("unexpected diff: %+v", d)
+		}
+	})
+
+	if uploads != 1 {
+		t.Errorf("expected only the first call to upload, got %d uploads", uploads)
+	}
+	if ids, _ := audiencemanifest.Load("prod", 12345); len(ids) != 3 {
+		t.Errorf("--diff must not change the manifest, got %v", ids)
+	}
+}
+
+func TestAudienceAddUsersCmd_DryRunDiffSkipsCount(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if _, err := audiencemanifest.Record("prod", 12345, []string{testUserID(1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.Output, flags.DryRun = "prod", "text", true
+
+	// Any request would fail against this server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newAudienceAddUsersCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "12345", "--users", testUserID(1) + "," + testUserID(2)})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := out.String()
+	if strings.Contains(output, "Current users") {
+		t.Errorf("dry run must not report a current count, got: %s", output)
+	}
+	for _, want := range []string{"Already uploaded:    1", "New IDs:             1"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestAudienceDiffCmd(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if _, err := audiencemanifest.Record("prod", 12345, []string{testUserID(1), testUserID(2), testUserID(3)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var uploads int
+	server := newAudienceUploadServer(t, &uploads)
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.Output = "prod", "text"
+
+	file := writeUsersFile(t, testUserID(2), testUserID(4), testUserID(3), testUserID(5), testUserID(4))
+	dir := t.TempDir()
+	newPath, stalePath := filepath.Join(dir, "new.txt"), filepath.Join(dir, "stale.txt")
+
+	cmd := newAudienceDiffCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "12345", "--file", file, "--new-out", newPath, "--stale-out", stalePath})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"Current users:      100",
+		"IDs in file:        4",
+		"IDs uploaded:       3",
+		"In both:            2",
+		"Only in file:       2",
+		"Only in audience:   1",
+		"Wrote 2 new IDs to " + newPath,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, out.String())
+		}
+	}
+	if got, _ := os.ReadFile(newPath); string(got) != testUserID(4)+"\n"+testUserID(5)+"\n" {
+		t.Errorf("unexpected new IDs: %q", got)
+	}
+	if got, _ := os.ReadFile(stalePath); string(got) != testUserID(1)+"\n" {
+		t.Errorf("unexpected stale IDs: %q", got)
+	}
+	if uploads != 0 {
+		t.Errorf("diff must not upload, got %d uploads", uploads)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		flags.Output = "json"
+		defer func() { flags.Output = "text" }()
+
+		cmd := newAudienceDiffCmdWithClient(client)
+		cmd.SetArgs([]string{"--id", "12345", "--file", file})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var d audienceFileDiff
+		if err := json.Unmarshal(out.Bytes(), &d); err != nil {
+			t.Fatalf("expected valid JSON, got: %s", out.String())
+		}
+		if d.CurrentCount == nil || *d.CurrentCount != 100 || d.InBoth != 2 || d.OnlyInFile != 2 || d.OnlyUploaded != 1 {
+			t.Errorf("unexpected diff: %+v", d)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/api/generated"
+	"github.com/spf13/cobra"
+)
+
+// audienceStatuses are the values accepted by --status.
+var audienceStatuses = []generated.AudienceGroupStatus{
+	generated.AudienceGroupStatusINPROGRESS,
+	generated.AudienceGroupStatusREADY,
+	generated.AudienceGroupStatusFAILED,
+	generated.AudienceGroupStatusEXPIRED,
+	generated.AudienceGroupStatusINACTIVE,
+	generated.AudienceGroupStatusACTIVATING,
+}
+
+// audienceListOptions holds the paging and filter flags shared by
+// "audience list" and "audience shared list".
+type audienceListOptions struct {
+	paging              pageFlags
+	page                int64
+	size                int64
+	status              string
+	descriptionContains string
+	createdAfter        string
+}
+
+func addAudienceListFlags(cmd *cobra.Command, o *audienceListOptions) {
+	addPageFlags(cmd, &o.paging, "audience groups")
+	cmd.Flags().Int64Var(&o.page, "page", 1, "Page number to fetch (the same as --cursor)")
+	cmd.Flags().Int64Var(&o.size, "size", 40, "Audience groups per page (max 40)")
+	cmd.Flags().StringVar(&o.status, "status", "", "Only show audiences with this status (READY, IN_PROGRESS, FAILED, EXPIRED, INACTIVE, ACTIVATING)")
+	cmd.Flags().StringVar(&o.descriptionContains, "description-contains", "", "Only show audiences whose description contains this text (case-insensitive)")
+	cmd.Flags().StringVar(&o.createdAfter, "created-after", "", "Only show audiences created on or after this date, e.g. 2024-07-01 or -30d")
+}
+
+// audienceGroupFilter is the parsed form of the client-side filter flags.
+type audienceGroupFilter struct {
+	status              generated.AudienceGroupStatus
+	descriptionContains string
+	createdAfter        time.Time
+}
+
+func (o audienceListOptions) filter() (audienceGroupFilter, error) {
+	var f audienceGroupFilter
+	if o.status != "" {
+		status := generated.AudienceGroupStatus(strings.ToUpper(o.status))
+		valid := false
+		for _, s := range audienceStatuses {
+			if s == status {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return f, fmt.Errorf("invalid --status %q (valid: READY, IN_PROGRESS, FAILED, EXPIRED, INACTIVE, ACTIVATING)", o.status)
+		}
+		f.status = status
+	}
+	f.descriptionContains = strings.ToLower(o.descriptionContains)
+	if o.createdAfter != "" {
+		t, err := parseFlagTime("--created-after", o.createdAfter)
+		if err != nil {
+			return f, err
+		}
+		f.createdAfter = t
+	}
+	return f, nil
+}
+
+func (f audienceGroupFilter) match(g generated.AudienceGroup) bool {
+	if f.status != "" && (g.Status == nil || *g.Status != f.status) {
+		return false
+	}
+	if f.descriptionContains != "" && (g.Description == nil || !strings.Contains(strings.ToLower(*g.Description), f.descriptionContains)) {
+		return false
+	}
+	if !f.createdAfter.IsZero() && (g.Created == nil || time.Unix(*g.Created, 0).Before(f.createdAfter)) {
+		return false
+	}
+	return true
+}
+
+// audiencePageFunc fetches one page of audience groups and reports whether
+// another page follows.
+type audiencePageFunc func(ctx context.Context, page, size int64) ([]generated.AudienceGroup, bool, error)
+
+// fetchAudienceGroups fetches audience groups as the pagination flags ask
+// and applies the filters, which --limit counts after. next is the page to
+// continue from, or "" if there are no more.
+func fetchAudienceGroups(ctx context.Context, o audienceListOptions, fetch audiencePageFunc) (groups []generated.AudienceGroup, next string, err error) {
+	opts, pages, err := audienceGroupPages(o, fetch)
+	if err != nil {
+		return nil, "", err
+	}
+	return api.Paginate(ctx, opts, pages)
+}
+
+// audienceGroupPages checks the list flags and returns the pagination
+// options and a PageFunc of filtered audience groups. The audience group
+// endpoints page by number, so the cursors are page numbers.
+func audienceGroupPages(o audienceListOptions, fetch audiencePageFunc) (api.PageOptions, api.PageFunc[generated.AudienceGroup], error) {
+	if o.page < 1 {
+		return api.PageOptions{}, nil, fmt.Errorf("--page must be at least 1")
+	}
+	if o.size < 1 || o.size > 40 {
+		return api.PageOptions{}, nil, fmt.Errorf("--size must be between 1 and 40")
+	}
+	f, err := o.filter()
+	if err != nil {
+		return api.PageOptions{}, nil, err
+	}
+	opts, err := o.paging.options(int(o.size), 0)
+	if err != nil {
+		return api.PageOptions{}, nil, err
+	}
+	switch {
+	case opts.Cursor == "":
+		opts.Cursor = strconv.FormatInt(o.page, 10)
+	case o.page != 1:
+		return api.PageOptions{}, nil, fmt.Errorf("use either --cursor or --page, not both")
+	}
+	if page, err := strconv.ParseInt(opts.Cursor, 10, 64); err != nil || page < 1 {
+		return api.PageOptions{}, nil, fmt.Errorf("invalid --cursor %q: audience cursors are page numbers", opts.Cursor)
+	}
+
+	return opts, func(ctx context.Context, cursor string, _ int) ([]generated.AudienceGroup, string, error) {
+		page, _ := strconv.ParseInt(cursor, 10, 64)
+		pageGroups, hasNext, err := fetch(ctx, page, o.size)
+		if err != nil {
+			return nil, "", err
+		}
+		var matched []generated.AudienceGroup
+		for _, g := range pageGroups {
+			if f.match(g) {
+				matched = append(matched, g)
+			}
+		}
+		if !hasNext {
+			return matched, "", nil
+		}
+		return matched, strconv.FormatInt(page+1, 10), nil
+	}, nil
+}
+
+// renderAudienceGroups writes groups in text or table format under heading.
+func renderAudienceGroups(cmd *cobra.Command, heading string, groups []generated.AudienceGroup) {
+	if flags.Output == "table" {
+		table := NewTable("ID", "DESCRIPTION", "STATUS", "USERS", "CREATED")
+		for _, g := range groups {
+			var created string
+			if g.Created != nil {
+				created = time.Unix(*g.Created, 0).Format("2006-01-02")
+			}
+
+			var audienceCount string
+			if g.AudienceCount != nil {
+				audienceCount = fmt.Sprintf("%d", *g.AudienceCount)
+			}
+
+			var status string
+			if g.Status != nil {
+				status = string(*g.Status)
+			}
+
+			var description string
+			if g.Description != nil {
+				description = *g.Description
+			}
+
+			var audienceGroupID string
+			if g.AudienceGroupId != nil {
+				audienceGroupID = fmt.Sprintf("%d", *g.AudienceGroupId)
+			}
+
+			table.AddRow(audienceGroupID, description, status, audienceCount, created)
+		}
+		table.Render(cmd.OutOrStdout())
+		return
+	}
+
+	// Default text output
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), heading)
+	for _, g := range groups {
+		var created string
+		if g.Created != nil {
+			created = time.Unix(*g.Created, 0).Format("2006-01-02")
+		} else {
+			created = "unknown"
+		}
+
+		var audienceCount int64
+		if g.AudienceCount != nil {
+			audienceCount = *g.AudienceCount
+		}
+
+		var status string
+		if g.Status != nil {
+			status = string(*g.Status)
+		} else {
+			status = "unknown"
+		}
+
+		var description string
+		if g.Description != nil {
+			description = *g.Description
+		}
+
+		var audienceGroupID int64
+		if g.AudienceGroupId != nil {
+			audienceGroupID = *g.AudienceGroupId
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %d  %s  (%s, %d users, created %s)\n",
+			audienceGroupID, description, colorStatus(cmd.OutOrStdout(), status), audienceCount, created)
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/api/generated"
+)
+
+// newPagedAudienceServer serves three pages of two audience groups each on
+// both the owned and shared list endpoints.
+func newPagedAudienceServer(t *testing.T, pages *[]string) *httptest.Server {
+	t.Helper()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		*pages = append(*pages, r.URL.Query().Get("page")+"/"+r.URL.Query().Get("size"))
+		groups := []map[string]any{
+			{"audienceGroupId": page*10 + 1, "description": "Spring campaign", "status": "READY", "created": created + int64(page)*86400},
+			{"audienceGroupId": page*10 + 2, "description": "Old list", "status": "EXPIRED", "created": created - 86400*30},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"audienceGroups": groups,
+			"hasNextPage":    page < 3,
+			"page":           page,
+		})
+	}))
+}
+
+func TestAudienceListCmd_AllFollowsPages(t *testing.T) {
+	var pages []string
+	server := newPagedAudienceServer(t, &pages)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAudienceListCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--all", "--size", "2"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(pages, ",") != "1/2,2/2,3/2" {
+		t.Errorf("expected pages 1-3 with size 2, got %v", pages)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if groups := result["audienceGroups"].([]any); len(groups) != 6 {
+		t.Errorf("expected 6 audience groups, got %d", len(groups))
+	}
+	if _, ok := result["next"]; ok {
+		t.Errorf("expected no next cursor after --all, got %v", result["next"])
+	}
+}
+
+func TestAudienceListCmd_Filters(t *testing.T) {
+	var pages []string
+	server := newPagedAudienceServer(t, &pages)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAudienceListCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--all", "--status", "ready", "--description-contains", "SPRING", "--created-after", "2026-01-03T00:00:00Z"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		AudienceGroups []generated.AudienceGroup `json:"audienceGroups"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	// Pages 2 and 3 have a READY "Spring campaign" created on or after Jan 3
+	if len(result.AudienceGroups) != 2 {
+		t.Fatalf("expected 2 matching groups, got %d", len(result.AudienceGroups))
+	}
+	if *result.AudienceGroups[0].AudienceGroupId != 21 || *result.AudienceGroups[1].AudienceGroupId != 31 {
+		t.Errorf("unexpected groups: %d, %d", *result.AudienceGroups[0].AudienceGroupId, *result.AudienceGroups[1].AudienceGroupId)
+	}
+}
+
+func TestAudienceListCmd_PageFlag(t *testing.T) {
+	var pages []string
+	server := newPagedAudienceServer(t, &pages)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "text"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAudienceListCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--page", "2", "--size", "5"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(pages, ",") != "2/5" {
+		t.Errorf("expected a single request for page 2, got %v", pages)
+	}
+	if !strings.Contains(out.String(), "Next cursor: 3") {
+		t.Errorf("expected next page hint, got: %s", out.String())
+	}
+}
+
+func TestAudienceListCmd_InvalidListFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"start and page", []string{"--start", "2", "--page", "3"}, "either --cursor or --page"},
+		{"size too large", []string{"--size", "41"}, "--size must be between 1 and 40"},
+		{"page zero", []string{"--page", "0"}, "--page must be at least 1"},
+		{"bad status", []string{"--status", "DONE"}, "invalid --status"},
+		{"bad date", []string{"--created-after", "last spring"}, "invalid --created-after"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newAudienceListCmdWithClient(api.NewClient("test-token", false, false))
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAudienceSharedListCmd_AllAndFilters(t *testing.T) {
+	var pages []string
+	server := newPagedAudienceServer(t, &pages)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAudienceSharedListCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--all", "--status", "EXPIRED"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pages) != 3 {
+		t.Errorf("expected 3 page requests, got %v", pages)
+	}
+
+	var groups []generated.AudienceGroup
+	if err := json.Unmarshal(out.Bytes(), &groups); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(groups) != 3 {
+		t.Errorf("expected 3 expired groups, got %d", len(groups))
+	}
+}
+
+func TestAudienceSharedListCmd_NextPageHint(t *testing.T) {
+	var pages []string
+	server := newPagedAudienceServer(t, &pages)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "text"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAudienceSharedListCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Next cursor: 2") {
+		t.Errorf("expected next page hint, got: %s", out.String())
+	}
+}
+
+func TestAudienceListCmd_NDJSONLimit(t *testing.T) {
+	var pages []string
+	server := newPagedAudienceServer(t, &pages)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "ndjson"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAudienceListCmdWithClient(client)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"--status", "READY", "--limit", "2", "--size", "2"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(pages, ",") != "1/2,2/2" {
+		t.Errorf("expected pages 1-2, got %v", pages)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var ids []int64
+	for _, line := range lines {
+		var g generated.AudienceGroup
+		if err := json.Unmarshal([]byte(line), &g); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", line, err)
+		}
+		ids = append(ids, *g.AudienceGroupId)
+	}
+	if len(ids) != 2 || ids[0] != 11 || ids[1] != 21 {
+		t.Errorf("expected the READY groups 11 and 21, got %v", ids)
+	}
+	if !strings.Contains(errOut.String(), "Next cursor: 3") {
+		t.Errorf("expected the next cursor on stderr, got %q", errOut.String())
+	}
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+	"github.com/spf13/cobra"
+)
+
+// audienceOpsInput is a users file read by 'line audience ops'.
+type audienceOpsInput struct {
+	Op    string `json:"op"`
+	File  string `json:"file"`
+	Users int    `json:"users"`
+}
+
+// audienceOpsResult summarizes 'line audience ops'.
+type audienceOpsResult struct {
+	Inputs []audienceOpsInput `json:"inputs"`
+	// Candidates is the size of the union before --intersect and --minus
+	Candidates int    `json:"candidates"`
+	Result     int    `json:"result"`
+	Out        string `json:"out"`
+}
+
+func newAudienceOpsCmd() *cobra.Command {
+	var unions []string
+	var intersects []string
+	var minuses []string
+	var outPath string
+	var maxUsers int
+	var skipInvalid bool
+
+	cmd := &cobra.Command{
+		Use:   "ops [USERS_FILE...]",
+		Short: "Combine users files with set operations",
+		Long: `Combine users files locally before uploading them: the result is the
+users in any --union file, that are also in every --intersect file, and in
+no --minus file. Files given as arguments are added to the union.
+
+Files are streamed, so million-line exports work: only the --intersect
+and --minus sets and the IDs written so far are held in memory, at a few
+dozen bytes per ID. The result keeps the order of the union files, without
+repeats. One file may be - to read stdin.`,
+		Example: `  # Everyone in a.txt or b.txt who is not in c.txt
+  line audience ops --union a.txt b.txt --minus c.txt --out result.txt
+  line audience create --name "Spring campaign" --file result.txt
+
+  # Users in both exports, to stdout
+  line audience ops --union march.txt --intersect april.txt`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unions = append(unions, args...)
+			if len(unions) == 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("at least one --union file is required"))
+			}
+			stdinUsed := false
+			for _, path := range append(append(append([]string{}, unions...), intersects...), minuses...) {
+				if path != stdinName {
+					continue
+				}
+				if stdinUsed {
+					return withExitCode(ExitUsage, fmt.Errorf("only one users file can read stdin"))
+				}
+				stdinUsed = true
+			}
+			toStdout := outPath == "" || outPath == stdinName
+			if toStdout && flags.Output == "json" {
+				return withExitCode(ExitUsage, fmt.Errorf("--output json needs --out, since the user IDs are written to stdout"))
+			}
+
+			opts := userids.Options{MaxUsers: maxUsers, SkipInvalid: skipInvalid}
+			result := audienceOpsResult{Inputs: []audienceOpsInput{}, Out: outPath}
+			read := func(op, path string, fn func(id string) error) error {
+				stats, err := readUsersFile(path, opts, usersFileBatchSize, func(batch []string) error {
+					for _, id := range batch {
+						if err := fn(id); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				if errors.Is(err, userids.ErrNoUsers) {
+					err = nil
+				}
+				if err != nil {
+					return fmt.Errorf("--%s %s: %w", op, path, err)
+				}
+				warnSkippedUsers(cmd, stats, "line")
+				result.Inputs = append(result.Inputs, audienceOpsInput{Op: op, File: path, Users: stats.Unique})
+				return nil
+			}
+
+			excluded := userids.NewSet()
+			for _, path := range minuses {
+				if err := read("minus", path, func(id string) error {
+					excluded.Add(id)
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			// Each --intersect file narrows the set kept from the ones before
+			var kept *userids.Set
+			for _, path := range intersects {
+				next := userids.NewSet()
+				if err := read("intersect", path, func(id string) error {
+					if (kept == nil || kept.Has(id)) && !excluded.Has(id) {
+						next.Add(id)
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+				kept = next
+			}
+
+			out, err := createUsersOutput(cmd, outPath)
+			if err != nil {
+				return err
+			}
+			defer out.abort()
+			candidates := userids.NewSet()
+			for _, path := range unions {
+				if err := read("union", path, func(id string) error {
+					if !candidates.Add(id) || excluded.Has(id) || (kept != nil && !kept.Has(id)) {
+						return nil
+					}
+					return out.write(id)
+				}); err != nil {
+					return err
+				}
+			}
+			if err := out.close(); err != nil {
+				return err
+			}
+			result.Candidates, result.Result = candidates.Len(), out.n
+
+			if toStdout {
+				return nil
+			}
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d user IDs to %s (%d in the union, %d left out)\n",
+				result.Result, outPath, result.Candidates, result.Candidates-result.Result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&unions, "union", nil, "Users file whose users are included, or - for stdin (repeatable)")
+	cmd.Flags().StringArrayVar(&intersects, "intersect", nil, "Users file the result must be in (repeatable)")
+	cmd.Flags().StringArrayVar(&minuses, "minus", nil, "Users file whose users are left out (repeatable)")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write the resulting user IDs to this file (default: stdout)")
+	addMaxUsersFlag(cmd, &maxUsers)
+	addInvalidUsersFlags(cmd, &skipInvalid)
+
+	return cmd
+}
+
+// usersOutput writes user IDs one per line to a file, or to stdout when
+// the path is empty or -. A file is written next to its final name and
+// renamed into place by close, so a failed command leaves no partial file.
+type usersOutput struct {
+	path string
+	f    *os.File
+	w    *bufio.Writer
+	// n is the number of IDs written
+	n int
+}
+
+func createUsersOutput(cmd *cobra.Command, path string) (*usersOutput, error) {
+	if path == "" || path == stdinName {
+		return &usersOutput{w: bufio.NewWriter(cmd.OutOrStdout())}, nil
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".line-users-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return &usersOutput{path: path, f: f, w: bufio.NewWriter(f)}, nil
+}
+
+func (o *usersOutput) write(id string) error {
+	if _, err := io.WriteString(o.w, id+"\n"); err != nil {
+		return fmt.Errorf("failed to write user IDs: %w", err)
+	}
+	o.n++
+	return nil
+}
+
+// close flushes the IDs and moves a file into place.
+func (o *usersOutput) close() error {
+	err := o.w.Flush()
+	if o.f == nil {
+		return err
+	}
+	f := o.f
+	o.f = nil
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), o.path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to write %s: %w", o.path, err)
+	}
+	return nil
+}
+
+// abort removes a file that was not closed; it is a no-op after close.
+func (o *usersOutput) abort() {
+	if o.f == nil {
+		return
+	}
+	_ = o.f.Close()
+	_ = os.Remove(o.f.Name())
+	o.f = nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAudienceOpsCmd(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Output = "text"
+
+	a := writeUsersFile(t, testUserID(1), testUserID(2), testUserID(3), testUserID(2))
+	b := writeUsersFile(t, testUserID(4), testUserID(3), testUserID(5))
+	c := writeUsersFile(t, testUserID(2), testUserID(5))
+	out := filepath.Join(t.TempDir(), "result.txt")
+
+	// b is a positional argument, as in --union a.txt b.txt
+	cmd := newAudienceOpsCmd()
+	cmd.SetArgs([]string{"--union", a, b, "--minus", c, "--out", out})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Join([]string{testUserID(1), testUserID(3), testUserID(4)}, "\n") + "\n"
+	if got, _ := os.ReadFile(out); string(got) != want {
+		t.Errorf("result = %q, want %q", got, want)
+	}
+	if !strings.Contains(buf.String(), "Wrote 3 user IDs to "+out+" (5 in the union, 2 left out)") {
+		t.Errorf("unexpected summary: %s", buf.String())
+	}
+}
+
+func TestAudienceOpsCmd_IntersectToStdout(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Output = "text"
+
+	a := writeUsersFile(t, testUserID(1), testUserID(2), testUserID(3), testUserID(4))
+	b := writeUsersFile(t, testUserID(4), testUserID(2), testUserID(3))
+	c := writeUsersFile(t, testUserID(3), testUserID(4), testUserID(9))
+
+	cmd := newAudienceOpsCmd()
+	cmd.SetArgs([]string{"--union", a, "--intersect", b, "--intersect", c})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := testUserID(3) + "\n" + testUserID(4) + "\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestAudienceOpsCmd_JSON(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Output = "json"
+
+	a := writeUsersFile(t, testUserID(1), testUserID(2))
+	out := filepath.Join(t.TempDir(), "result.txt")
+
+	cmd := newAudienceOpsCmd()
+	cmd.SetArgs([]string{"--union", a, "--out", out})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result audienceOpsResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("expected valid JSON, got: %s", buf.String())
+	}
+	if result.Result != 2 || len(result.Inputs) != 1 || result.Inputs[0].Op != "union" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	t.Run("needs --out", func(t *testing.T) {
+		cmd := newAudienceOpsCmd()
+		cmd.SetArgs([]string{"--union", a})
+		cmd.SetOut(new(bytes.Buffer))
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--out") {
+			t.Errorf("expected --out error, got %v", err)
+		}
+	})
+}
+
+func TestAudienceOpsCmd_Errors(t *testing.T) {
+	a := writeUsersFile(t, testUserID(1))
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no union", []string{"--minus", a}, "at least one --union"},
+		{"two stdin", []string{"--union", "-", "--minus", "-"}, "only one users file can read stdin"},
+		{"missing file", []string{"--union", filepath.Join(t.TempDir(), "missing.txt")}, "--union"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newAudienceOpsCmd()
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
 )
 
 func TestAudienceCmd_HasSubcommands(t *testing.T) {
@@ -29,7 +31,7 @@ func TestAudienceCmd_HasSubcommands(t *testing.T) {
 
 	expectedSubcommands := []string{
 		"list", "get", "delete", "create", "add-users",
-		"create-click", "create-impression", "update-description", "shared",
+		"create-click", "create-impression", "update-description", "shared", "status", "activate", "diff", "ops",
 	}
 
 	for _, expected := range expectedSubcommands {
@@ -525,6 +527,7 @@ func TestAudienceCommands_IDValidation(t *testing.T) {
 		{"update-description with zero id", []string{"audience", "update-description", "--id", "0", "--description", "Test"}},
 		{"shared get with zero id", []string{"audience", "shared", "get", "--id", "0"}},
 		{"shared get with negative id", []string{"audience", "shared", "get", "--id", "-1"}},
+		{"status with zero id", []string{"audience", "status", "--id", "0"}},
 	}
 
 	for _, tt := range tests {
@@ -611,13 +614,19 @@ func TestAudienceListCmd_Execute(t *testing.T) {
 
 			output := out.String()
 			if tt.wantJSON {
-				var result []any
+				var result struct {
+					AudienceGroups []any  `json:"audienceGroups"`
+					Next           string `json:"next"`
+				}
 				if err := json.Unmarshal([]byte(output), &result); err != nil {
 					t.Errorf("expected valid JSON output, got: %s", output)
 				}
-				if len(result) == 0 {
+				if len(result.AudienceGroups) == 0 {
 					t.Error("expected at least one audience group")
 				}
+				if result.Next != "" {
+					t.Errorf("expected no next cursor on last page, got %q", result.Next)
+				}
 			} else {
 				if !strings.Contains(output, tt.checkText) {
 					t.Errorf("expected output to contain %q, got: %s", tt.checkText, output)
@@ -627,6 +636,58 @@ func TestAudienceListCmd_Execute(t *testing.T) {
 	}
 }
 
+func TestAudienceListCmd_StartCursor(t *testing.T) {
+	var gotPage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPage = r.URL.Query().Get("page")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"audienceGroups": []map[string]any{{"audienceGroupId": 1}},
+			"hasNextPage":    true,
+		})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newAudienceListCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--start", "2"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPage != "2" {
+		t.Errorf("expected page=2, got %q", gotPage)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result["next"] != "3" {
+		t.Errorf("expected next cursor 3, got %v", result["next"])
+	}
+}
+
+func TestAudienceListCmd_InvalidStart(t *testing.T) {
+	cmd := newAudienceListCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--start", "abc"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --cursor") {
+		t.Errorf("expected invalid --cursor error, got: %v", err)
+	}
+}
+
 func TestAudienceListCmd_EmptyList(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasPrefix(r.URL.Path, "/v2/bot/audienceGroup/list") {
@@ -862,7 +923,7 @@ func TestAudienceCreateCmd_Execute(t *testing.T) {
 			defer func() { flags.Output = oldOutput }()
 
 			cmd := newAudienceCreateCmdWithClient(client)
-			cmd.SetArgs([]string{"--name", "New Test Audience", "--users", "U123,U456,U789"})
+			cmd.SetArgs([]string{"--name", "New Test Audience", "--users", testUserID(1) + "," + testUserID(2) + "," + testUserID(3)})
 			var out bytes.Buffer
 			cmd.SetOut(&out)
 
@@ -1750,7 +1811,7 @@ func TestAudienceAddUsersCmd_Execute(t *testing.T) {
 			defer func() { flags.Output = oldOutput }()
 
 			cmd := newAudienceAddUsersCmdWithClient(client)
-			cmd.SetArgs([]string{"--id", "12345", "--users", "U123,U456,U789"})
+			cmd.SetArgs([]string{"--id", "12345", "--users", testUserID(1) + "," + testUserID(2) + "," + testUserID(3)})
 			var out bytes.Buffer
 			cmd.SetOut(&out)
 
@@ -1791,7 +1852,7 @@ func TestAudienceAddUsersCmd_APIError(t *testing.T) {
 	client.SetBaseURL(server.URL)
 
 	cmd := newAudienceAddUsersCmdWithClient(client)
-	cmd.SetArgs([]string{"--id", "12345", "--users", "U123"})
+	cmd.SetArgs([]string{"--id", "12345", "--users", testUserID(1)})
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
@@ -2064,7 +2125,7 @@ func TestAudienceCreateCmd_APIError(t *testing.T) {
 	client.SetBaseURL(server.URL)
 
 	cmd := newAudienceCreateCmdWithClient(client)
-	cmd.SetArgs([]string{"--name", "Test Audience", "--users", "U123"})
+	cmd.SetArgs([]string{"--name", "Test Audience", "--users", testUserID(1)})
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
@@ -2099,7 +2160,7 @@ func TestAudienceCreateCmd_FromFile(t *testing.T) {
 	// Create temp file with user IDs
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "users.txt")
-	if err := os.WriteFile(tmpFile, []byte("U123\nU456\nU789\n"), 0644); err != nil {
+	if err := os.WriteFile(tmpFile, []byte(testUserID(1)+"\n"+testUserID(2)+"\n"+testUserID(3)+"\n"), 0644); err != nil {
 		t.Fatalf("failed to create temp file: %v", err)
 	}
 
@@ -2210,7 +2271,7 @@ func TestAudienceCreateCmd_FromFile_APIError(t *testing.T) {
 	// Create temp file with user IDs
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "users.txt")
-	if err := os.WriteFile(tmpFile, []byte("U123\nU456\n"), 0644); err != nil {
+	if err := os.WriteFile(tmpFile, []byte(testUserID(1)+"\n"+testUserID(2)+"\n"), 0644); err != nil {
 		t.Fatalf("failed to create temp file: %v", err)
 	}
 
@@ -2244,7 +2305,7 @@ func TestAudienceAddUsersCmd_FromFile(t *testing.T) {
 	// Create temp file with user IDs
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "users.txt")
-	if err := os.WriteFile(tmpFile, []byte("U123\nU456\nU789\n"), 0644); err != nil {
+	if err := os.WriteFile(tmpFile, []byte(testUserID(1)+"\n"+testUserID(2)+"\n"+testUserID(3)+"\n"), 0644); err != nil {
 		t.Fatalf("failed to create temp file: %v", err)
 	}
 
@@ -2358,7 +2419,7 @@ func TestAudienceAddUsersCmd_FromFile_APIError(t *testing.T) {
 	// Create temp file with user IDs
 	tmpDir := t.TempDir()
 	tmpFile := filepath.Join(tmpDir, "users.txt")
-	if err := os.WriteFile(tmpFile, []byte("U123\nU456\n"), 0644); err != nil {
+	if err := os.WriteFile(tmpFile, []byte(testUserID(1)+"\n"+testUserID(2)+"\n"), 0644); err != nil {
 		t.Fatalf("failed to create temp file: %v", err)
 	}
 
@@ -2375,3 +2436,230 @@ func TestAudienceAddUsersCmd_FromFile_APIError(t *testing.T) {
 		t.Errorf("expected 'failed to add users to audience' in error, got: %v", err)
 	}
 }
+
+// audienceStatusServer returns a server that reports IN_PROGRESS for the
+// first two polls and then the given final status and job status.
+func audienceStatusServer(t *testing.T, finalStatus, finalJob string, calls *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/bot/audienceGroup/upload":
+			_ = json.NewEncoder(w).Encode(map[string]any{"audienceGroupId": 42, "type": "UPLOAD", "description": "VIP"})
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/bot/audienceGroup/upload":
+			_, _ = w.Write([]byte("{}"))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/audienceGroup/42":
+			*calls++
+			status, job := "IN_PROGRESS", "WORKING"
+			if *calls >= 3 {
+				status, job = finalStatus, finalJob
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"audienceGroup": map[string]any{"audienceGroupId": 42, "description": "VIP", "status": status, "audienceCount": 2},
+				"jobs": []map[string]any{
+					{"audienceGroupJobId": 1, "created": 100, "jobStatus": "FINISHED"},
+					{"audienceGroupJobId": 2, "created": 200, "jobStatus": job, "failedType": "INTERNAL_ERROR"},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestAudienceStatusCmd_Execute(t *testing.T) {
+	calls := 0
+	server := audienceStatusServer(t, "READY", "FINISHED", &calls)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	cmd := newAudienceStatusCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "42", "--interval", "1ms"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 polls, got %d", calls)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if result["status"] != "READY" || result["jobStatus"] != "FINISHED" {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestAudienceStatusCmd_JobFailed(t *testing.T) {
+	calls := 0
+	server := audienceStatusServer(t, "READY", "FAILED", &calls)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newAudienceStatusCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "42", "--interval", "1ms"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "INTERNAL_ERROR") {
+		t.Fatalf("expected job failure error, got %v", err)
+	}
+}
+
+func TestAudienceCreateCmd_Wait(t *testing.T) {
+	calls := 0
+	server := audienceStatusServer(t, "READY", "FINISHED", &calls)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	cmd := newAudienceCreateCmdWithClient(client)
+	cmd.SetArgs([]string{"--name", "VIP", "--users", testUserID(1) + "," + testUserID(2), "--wait", "--interval", "1ms"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 polls, got %d", calls)
+	}
+	if !strings.Contains(out.String(), "Status: READY") {
+		t.Errorf("expected final status in output, got %s", out.String())
+	}
+}
+
+func TestAudienceAddUsersCmd_WaitTimeout(t *testing.T) {
+	calls := 0
+	server := audienceStatusServer(t, "IN_PROGRESS", "WORKING", &calls)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newAudienceAddUsersCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "42", "--users", testUserID(3), "--wait", "--interval", "5ms", "--timeout", "30ms"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestAudienceGetCmd_ExpiryAndRecipients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"audienceGroup":{"audienceGroupId":12345,"description":"Ad audience","status":"READY","isIfaAudience":true,"created":1700000000,"expireTimestamp":1735689600}}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	cmd := newAudienceGetCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "12345"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Recipients:  IFAs", "Expires:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output should contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestAudienceActivateCmd_Wait(t *testing.T) {
+	var activated bool
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/bot/audienceGroup/42/activate":
+			activated = true
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/audienceGroup/42":
+			polls++
+			status := "ACTIVATING"
+			if polls >= 2 {
+				status = "READY"
+			}
+			_, _ = w.Write([]byte(`{"audienceGroup":{"audienceGroupId":42,"status":"` + status + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	cmd := newAudienceActivateCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "42", "--wait", "--interval", "1ms"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !activated {
+		t.Error("expected activate request")
+	}
+	if !strings.Contains(out.String(), `"status": "READY"`) {
+		t.Errorf("expected READY status, got %s", out.String())
+	}
+}
+
+func TestAudienceRenameAlias(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	parent := &cobra.Command{Use: "audience"}
+	parent.AddCommand(newAudienceUpdateDescriptionCmdWithClient(client))
+	parent.SetArgs([]string{"rename", "--id", "42", "--name", "Spring VIPs"})
+	parent.SetOut(&bytes.Buffer{})
+
+	if err := parent.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(body, `"description":"Spring VIPs"`) {
+		t.Errorf("expected description in request, got %s", body)
+	}
+}
@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/auth"
 	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/salmonumbrella/line-official-cli/internal/tokenhealth"
 	"github.com/spf13/cobra"
 )
 
@@ -20,6 +24,7 @@ func newAuthCmd() *cobra.Command {
 	cmd.AddCommand(newAuthLogoutCmd())
 	cmd.AddCommand(newAuthStatusCmd())
 	cmd.AddCommand(newAuthListCmd())
+	cmd.AddCommand(newAuthTokenCmd())
 
 	return cmd
 }
@@ -30,20 +35,42 @@ func newAuthLoginCmd() *cobra.Command {
 
 func newAuthLoginCmdWithStore(store secrets.Store) *cobra.Command {
 	var channelAccessToken string
+	var channelID string
+	var channelSecret string
 	var accountName string
+	var noBrowser bool
+	var listenAddr string
 
 	cmd := &cobra.Command{
 		Use:   "login",
-		Short: "Login with channel access token",
+		Short: "Login with channel access token or channel secret",
 		Long: `Authenticate with your LINE Official Account.
 
 Opens a browser to enter your channel access token from the LINE Developers Console.
-The token will be stored securely in your system keyring.`,
+The token will be stored securely in your system keyring.
+
+Instead of a long-lived token you can store the channel ID and channel
+secret. Each command then issues a stateless channel access token (valid
+for 15 minutes) on first use and issues a new one if it expires or is
+rejected, so no long-lived token is ever stored.
+
+Over SSH or on a machine without a browser, --no-browser prints the setup
+URL and a one-time code instead of opening a browser. Open the URL in a
+browser on another machine and enter the code; login completes when the
+form is submitted. The server listens on 127.0.0.1 unless --listen says
+otherwise, so forward its port with "ssh -L" or listen on a fixed port.`,
 		Example: `  # Interactive login (opens browser)
   line auth login
 
   # Login with token directly
-  line auth login --token YOUR_TOKEN --name my-account`,
+  line auth login --token YOUR_TOKEN --name my-account
+
+  # Login with channel ID and secret (stateless tokens)
+  line auth login --channel-id 1234567890 --channel-secret SECRET --name my-account
+
+  # Login over SSH from the browser on your laptop
+  ssh -L 8085:127.0.0.1:8085 server
+  line auth login --no-browser --listen 127.0.0.1:8085`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			if store == nil {
@@ -53,13 +80,22 @@ The token will be stored securely in your system keyring.`,
 				}
 			}
 
-			if channelAccessToken != "" {
+			creds, err := credentialsFromFlags(channelAccessToken, channelID, channelSecret)
+			if err != nil {
+				return err
+			}
+			if creds != nil && noBrowser {
+				return withExitCode(ExitUsage, fmt.Errorf("--no-browser does not apply when credentials are given with flags"))
+			}
+			if listenAddr != "" && !noBrowser {
+				return withExitCode(ExitUsage, fmt.Errorf("--listen requires --no-browser"))
+			}
+
+			if creds != nil {
 				if accountName == "" {
 					accountName = "default"
 				}
-				err := store.Set(accountName, secrets.Credentials{
-					ChannelAccessToken: channelAccessToken,
-				}, "") // Empty bot name for direct token login
+				err := store.Set(accountName, *creds, "") // Empty bot name for direct login
 				if err != nil {
 					return fmt.Errorf("failed to save credentials: %w", err)
 				}
@@ -68,12 +104,19 @@ The token will be stored securely in your system keyring.`,
 			}
 
 			// Browser flow
-			server, err := auth.NewSetupServer(store)
+			var server *auth.SetupServer
+			if noBrowser {
+				server, err = auth.NewHeadlessSetupServer(store, listenAddr, cmd.OutOrStdout())
+			} else {
+				server, err = auth.NewSetupServer(store)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to start auth server: %w", err)
 			}
 
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Opening browser for authentication...")
+			if !noBrowser {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Opening browser for authentication...")
+			}
 			result, err := server.Start(cmd.Context())
 			if err != nil {
 				return fmt.Errorf("authentication failed: %w", err)
@@ -85,11 +128,34 @@ The token will be stored securely in your system keyring.`,
 	}
 
 	cmd.Flags().StringVar(&channelAccessToken, "token", "", "Channel access token")
+	cmd.Flags().StringVar(&channelID, "channel-id", "", "Channel ID, to issue stateless tokens instead of storing one")
+	cmd.Flags().StringVar(&channelSecret, "channel-secret", "", "Channel secret, used with --channel-id")
 	cmd.Flags().StringVar(&accountName, "name", "", "Account name")
+	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Print the setup URL and a one-time code instead of opening a browser")
+	cmd.Flags().StringVar(&listenAddr, "listen", "", "Address for the --no-browser setup server (default 127.0.0.1 on a random port)")
 
 	return cmd
 }
 
+// credentialsFromFlags returns the credentials given with --token, or with
+// --channel-id and --channel-secret, or nil if none were given.
+func credentialsFromFlags(channelAccessToken, channelID, channelSecret string) (*secrets.Credentials, error) {
+	if channelAccessToken != "" && (channelID != "" || channelSecret != "") {
+		return nil, fmt.Errorf("use either --token or --channel-id with --channel-secret, not both")
+	}
+	if (channelID == "") != (channelSecret == "") {
+		return nil, fmt.Errorf("--channel-id and --channel-secret must be used together")
+	}
+	if channelAccessToken == "" && channelID == "" {
+		return nil, nil
+	}
+	return &secrets.Credentials{
+		ChannelAccessToken: channelAccessToken,
+		ChannelID:          channelID,
+		ChannelSecret:      channelSecret,
+	}, nil
+}
+
 func newAuthLogoutCmd() *cobra.Command {
 	return newAuthLogoutCmdWithStore(nil)
 }
@@ -132,11 +198,31 @@ func newAuthStatusCmd() *cobra.Command {
 	return newAuthStatusCmdWithStore(nil)
 }
 
+// accountStatus is an account in 'line auth status' output.
+type accountStatus struct {
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	Primary   bool      `json:"primary"`
+	Stateless bool      `json:"stateless,omitempty"`
+	BotName   string    `json:"botName,omitempty"`
+	BasicID   string    `json:"basicId,omitempty"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+	Error     string    `json:"error,omitempty"`
+}
+
 func newAuthStatusCmdWithStore(store secrets.Store) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show authentication status",
-		Long:  "Display which account is currently active and authentication status.",
+		Long: `Display which account is currently active, and check every stored
+account with LINE: its bot, whether its token is valid, and when it
+expires.
+
+Token is one of valid, invalid (rejected by LINE), or unknown (it could not
+be checked). Long-lived tokens do not expire; stateless tokens are issued
+for each command. Any command warns when the active account's token expires
+within 24 hours; its expiry is checked with LINE at most once a day.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			if store == nil {
@@ -150,7 +236,9 @@ func newAuthStatusCmdWithStore(store secrets.Store) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to list accounts: %w", err)
 			}
-			if len(accounts) == 0 {
+			_, fromEnv := envCredentials()
+			fromEnv = fromEnv && !flags.AccountSet
+			if len(accounts) == 0 && !fromEnv {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Not logged in")
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Run: line auth login")
 				return nil
@@ -160,7 +248,9 @@ func newAuthStatusCmdWithStore(store secrets.Store) *cobra.Command {
 			activeAccount := ""
 			source := ""
 
-			if flags.Account != "" {
+			if fromEnv {
+				source = "(from the environment)"
+			} else if flags.Account != "" {
 				activeAccount = flags.Account
 				source = "(from --account flag or LINE_ACCOUNT env)"
 			} else {
@@ -180,28 +270,54 @@ func newAuthStatusCmdWithStore(store secrets.Store) *cobra.Command {
 				}
 			}
 
-			if activeAccount == "" {
+			statuses, _ := enrichConcurrently(cmd.Context(), accounts, 4, func(ctx context.Context, acc secrets.AccountInfo) (accountStatus, error) {
+				return checkAccount(ctx, store, acc), nil
+			})
+			for i := range statuses {
+				statuses[i].Active = statuses[i].Name == activeAccount
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"activeAccount": activeAccount, "fromEnvironment": fromEnv, "accounts": statuses})
+			}
+
+			if fromEnv {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Active credentials: %s or %s %s\n", channelAccessTokenEnv, channelSecretEnv, source)
+				if len(accounts) == 0 {
+					return nil
+				}
+			} else if activeAccount == "" {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No active account")
 				return nil
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Active account: %s %s\n", activeAccount, source)
+			}
+			if creds, err := store.Get(activeAccount); err == nil {
+				if creds.UsesChannelSecret() {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Credentials: channel %s with secret (stateless tokens)\n", creds.ChannelID)
+				}
+				rec, _ := tokenhealth.Get(activeAccount, tokenhealth.TokenID(tokenHealthKey(creds)))
+				printTokenHealth(cmd.OutOrStdout(), activeAccount, rec, clockNow())
 			}
-
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Active account: %s %s\n", activeAccount, source)
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "")
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "All accounts:")
-			for _, acc := range accounts {
+			for _, st := range statuses {
 				marker := "  "
-				if acc.Name == activeAccount {
+				if st.Active {
 					marker = "* "
 				}
 				primary := ""
-				if acc.IsPrimary {
+				if st.Primary {
 					primary = " (primary)"
 				}
 				botInfo := ""
-				if acc.BotName != "" {
-					botInfo = fmt.Sprintf(" - %s", acc.BotName)
+				if st.BotName != "" {
+					botInfo = fmt.Sprintf(" - %s", st.BotName)
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s%s%s%s\n", marker, acc.Name, botInfo, primary)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s%s%s%s\n", marker, st.Name, botInfo, primary)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    Token: %s\n", describeAccountToken(st, clockNow()))
 			}
 			return nil
 		},
@@ -210,6 +326,64 @@ func newAuthStatusCmdWithStore(store secrets.Store) *cobra.Command {
 	return cmd
 }
 
+// checkAccount checks an account's credentials with LINE, recording the
+// expiry of a stored token for the warnings of later commands.
+func checkAccount(ctx context.Context, store secrets.Store, acc secrets.AccountInfo) accountStatus {
+	st := accountStatus{Name: acc.Name, Primary: acc.IsPrimary, BotName: acc.BotName, Token: "unknown"}
+	creds, err := store.Get(acc.Name)
+	if err != nil {
+		st.Error = err.Error()
+		return st
+	}
+	st.Stateless = creds.UsesChannelSecret()
+
+	client := auth.NewClient(*creds, flags.Debug, false)
+	applyNetwork(client)
+	client.SetLogger(logger)
+	if base := configuredAPIBaseURL(); base != "" {
+		client.SetBaseURL(base)
+	}
+	now := clockNow()
+	result := auth.Verify(ctx, client, *creds, now)
+	switch {
+	case result.Err != nil:
+		st.Error = result.Err.Error()
+		return st
+	case !result.Valid:
+		st.Token = "invalid"
+		return st
+	}
+	st.Token, st.BasicID, st.ExpiresAt = "valid", result.BasicID, result.ExpiresAt
+	if result.BotName != "" {
+		st.BotName = result.BotName
+	}
+	if !st.Stateless {
+		_ = tokenhealth.RecordExpiry(acc.Name, tokenhealth.TokenID(tokenHealthKey(creds)), result.ExpiresAt, now)
+	}
+	return st
+}
+
+// describeAccountToken describes the token of an account in text output.
+func describeAccountToken(st accountStatus, now time.Time) string {
+	switch {
+	case st.Token == "invalid":
+		return "invalid (rejected by LINE); run: line auth login"
+	case st.Token != "valid":
+		return "unknown (" + st.Error + ")"
+	case st.Stateless:
+		return "valid, stateless (issued for each command)"
+	case st.ExpiresAt.IsZero():
+		return "valid, does not expire"
+	}
+	desc := "valid, expires " + st.ExpiresAt.Local().Format("2006-01-02 15:04")
+	if left := st.ExpiresAt.Sub(now); left < tokenhealth.ExpiryWindow {
+		desc += fmt.Sprintf(" (in %s)", left.Round(time.Minute))
+	} else {
+		desc += fmt.Sprintf(" (in %d days)", int(left.Hours()/24))
+	}
+	return desc
+}
+
 func newAuthListCmd() *cobra.Command {
 	return newAuthListCmdWithStore(nil)
 }
@@ -288,3 +462,27 @@ func newAuthListCmdWithStore(store secrets.Store) *cobra.Command {
 
 	return cmd
 }
+
+// printTokenHealth prints the recorded request history for an account's token.
+func printTokenHealth(w io.Writer, account string, rec *tokenhealth.Record, now time.Time) {
+	if rec == nil {
+		_, _ = fmt.Fprintln(w, "Token health: no requests recorded yet")
+		return
+	}
+	lastSuccess := "never"
+	if !rec.LastSuccess.IsZero() {
+		lastSuccess = rec.LastSuccess.Local().Format("2006-01-02 15:04")
+	}
+	failuresToday := 0
+	if rec.FailureDay == now.Format("2006-01-02") {
+		failuresToday = rec.FailuresToday
+	}
+	_, _ = fmt.Fprintf(w, "Token health: first used %s, last success %s, auth failures today %d\n",
+		rec.FirstSeen.Local().Format("2006-01-02"), lastSuccess, failuresToday)
+	if msg := tokenhealth.Warning(account, rec, now); msg != "" {
+		_, _ = fmt.Fprintf(w, "Warning: %s\n", msg)
+	}
+	if msg := tokenhealth.ExpiryWarning(account, rec, now); msg != "" {
+		_, _ = fmt.Fprintf(w, "Warning: %s\n", msg)
+	}
+}
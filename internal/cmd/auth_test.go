@@ -2,11 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/salmonumbrella/line-official-cli/internal/tokenhealth"
 	"github.com/spf13/cobra"
 )
 
@@ -120,14 +129,14 @@ func TestAuthCmd_RequiresSubcommand(t *testing.T) {
 func TestAuthCmd_HasSubcommands(t *testing.T) {
 	cmd := newAuthCmd()
 	subcommands := cmd.Commands()
-	if len(subcommands) != 4 {
-		t.Errorf("expected 4 subcommands, got %d", len(subcommands))
+	if len(subcommands) != 5 {
+		t.Errorf("expected 5 subcommands, got %d", len(subcommands))
 	}
 	names := make(map[string]bool)
 	for _, subcmd := range subcommands {
 		names[subcmd.Name()] = true
 	}
-	expected := []string{"login", "logout", "status", "list"}
+	expected := []string{"login", "logout", "status", "list", "token"}
 	for _, name := range expected {
 		if !names[name] {
 			t.Errorf("expected '%s' subcommand", name)
@@ -342,6 +351,48 @@ func TestAuthLoginCmd_WithToken_DefaultName(t *testing.T) {
 	}
 }
 
+func TestAuthLoginCmd_WithChannelSecret(t *testing.T) {
+	store := newMockStore()
+	cmd := newAuthLoginCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--channel-id", "1234567890", "--channel-secret", "s3cret", "--name", "stateless"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creds, err := store.Get("stateless")
+	if err != nil {
+		t.Fatalf("expected credentials to be stored: %v", err)
+	}
+	if !creds.UsesChannelSecret() || creds.ChannelID != "1234567890" || creds.ChannelSecret != "s3cret" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+}
+
+func TestAuthLoginCmd_WithChannelSecret_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"id without secret", []string{"--channel-id", "1234567890"}, "must be used together"},
+		{"secret without id", []string{"--channel-secret", "s3cret"}, "must be used together"},
+		{"token and secret", []string{"--token", "t", "--channel-id", "1", "--channel-secret", "s"}, "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newAuthLoginCmdWithStore(newMockStore())
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestAuthLoginCmd_WithToken_StoreError(t *testing.T) {
 	store := newMockStore()
 	store.setErr = errors.New("keychain locked")
@@ -415,7 +466,7 @@ func TestAuthLogoutCmd_DeleteError(t *testing.T) {
 func TestAuthStatusCmd_ShowsActiveAccount(t *testing.T) {
 	store := newMockStore()
 	_ = store.Set("my-account", secrets.Credentials{ChannelAccessToken: "abcdefgh12345678xyz9"}, "My Bot")
-	cmd := newAuthStatusCmdWithStore(store)
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{})
@@ -439,7 +490,7 @@ func TestAuthStatusCmd_ShowsPrimaryAccount(t *testing.T) {
 	store := newMockStore()
 	_ = store.Set("my-account", secrets.Credentials{ChannelAccessToken: "abcdefgh12345678xyz9"}, "My Bot")
 	_ = store.SetPrimary("my-account")
-	cmd := newAuthStatusCmdWithStore(store)
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{})
@@ -456,7 +507,7 @@ func TestAuthStatusCmd_ShowsPrimaryAccount(t *testing.T) {
 func TestAuthStatusCmd_ShowsFirstAccountFallback(t *testing.T) {
 	store := newMockStore()
 	_ = store.Set("first-account", secrets.Credentials{ChannelAccessToken: "token1"}, "")
-	cmd := newAuthStatusCmdWithStore(store)
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{})
@@ -480,7 +531,7 @@ func TestAuthStatusCmd_ShowsAccountFromFlag(t *testing.T) {
 	flags.Account = "account-b"
 	defer func() { flags.Account = oldAccount }()
 
-	cmd := newAuthStatusCmdWithStore(store)
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{})
@@ -499,7 +550,7 @@ func TestAuthStatusCmd_ShowsAccountFromFlag(t *testing.T) {
 
 func TestAuthStatusCmd_NotLoggedIn(t *testing.T) {
 	store := newMockStore()
-	cmd := newAuthStatusCmdWithStore(store)
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{})
@@ -519,7 +570,7 @@ func TestAuthStatusCmd_NotLoggedIn(t *testing.T) {
 func TestAuthStatusCmd_ShowsBotName(t *testing.T) {
 	store := newMockStore()
 	_ = store.Set("my-account", secrets.Credentials{ChannelAccessToken: "token123"}, "My Super Bot")
-	cmd := newAuthStatusCmdWithStore(store)
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{})
@@ -538,7 +589,7 @@ func TestAuthStatusCmd_MultipleAccounts(t *testing.T) {
 	_ = store.Set("account-1", secrets.Credentials{ChannelAccessToken: "token1"}, "Bot 1")
 	_ = store.Set("account-2", secrets.Credentials{ChannelAccessToken: "token2"}, "Bot 2")
 	_ = store.SetPrimary("account-1")
-	cmd := newAuthStatusCmdWithStore(store)
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 	cmd.SetArgs([]string{})
@@ -558,6 +609,97 @@ func TestAuthStatusCmd_MultipleAccounts(t *testing.T) {
 	}
 }
 
+// newTestAuthStatusCmd returns auth status pointed at a fake LINE API. It
+// rejects the tokens in invalid and reports the rest as expiring per
+// expiresIn, in seconds; tokens it does not list do not expire.
+func newTestAuthStatusCmd(t *testing.T, store secrets.Store, invalid []string, expiresIn map[string]int) *cobra.Command {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/info":
+			if slices.Contains(invalid, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"userId":"U1","basicId":"@shop"}`))
+		case "/oauth2/v2.1/verify":
+			_, _ = fmt.Fprintf(w, `{"client_id":"1234","expires_in":%d}`, expiresIn[r.URL.Query().Get("access_token")])
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("LINE_API_BASE_URL", server.URL)
+	return newAuthStatusCmdWithStore(store)
+}
+
+func TestAuthStatusCmd_TokenValidityAndExpiry(t *testing.T) {
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	oldNow := clockNow
+	clockNow = func() time.Time { return now }
+	defer func() { clockNow = oldNow }()
+
+	store := newMockStore()
+	_ = store.Set("long", secrets.Credentials{ChannelAccessToken: "long-token"}, "")
+	_ = store.Set("short", secrets.Credentials{ChannelAccessToken: "short-token"}, "")
+	_ = store.Set("revoked", secrets.Credentials{ChannelAccessToken: "revoked-token"}, "")
+	_ = store.SetPrimary("short")
+	cmd := newTestAuthStatusCmd(t, store, []string{"revoked-token"}, map[string]int{"short-token": 3 * 3600})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output := out.String()
+	for _, want := range []string{
+		"Warning: token for 'short' expires in 3 hours",
+		"Token: valid, does not expire",
+		"Token: valid, expires " + now.Add(3*time.Hour).Local().Format("2006-01-02 15:04") + " (in 3h0m0s)",
+		"Token: invalid (rejected by LINE)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	// The expiry is recorded for the warnings of later commands
+	rec, err := tokenhealth.Get("short", tokenhealth.TokenID("short-token"))
+	if err != nil || rec == nil || !rec.ExpiresAt.Equal(now.Add(3*time.Hour)) {
+		t.Errorf("expected recorded expiry, got %+v, %v", rec, err)
+	}
+}
+
+func TestAuthStatusCmd_JSON(t *testing.T) {
+	store := newMockStore()
+	_ = store.Set("my-account", secrets.Credentials{ChannelAccessToken: "token1"}, "My Bot")
+	cmd := newTestAuthStatusCmd(t, store, nil, nil)
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got struct {
+		ActiveAccount string          `json:"activeAccount"`
+		Accounts      []accountStatus `json:"accounts"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if got.ActiveAccount != "my-account" || len(got.Accounts) != 1 {
+		t.Fatalf("unexpected status: %+v", got)
+	}
+	if st := got.Accounts[0]; !st.Active || st.Token != "valid" || st.BotName != "My Bot" || st.BasicID != "@shop" {
+		t.Errorf("unexpected account: %+v", st)
+	}
+}
+
 func TestAuthListCmd_NoAccounts(t *testing.T) {
 	store := newMockStore()
 	cmd := newAuthListCmdWithStore(store)
@@ -687,3 +829,47 @@ func TestAuthListCmd_ListError(t *testing.T) {
 		t.Errorf("expected 'failed to list accounts' in error, got: %v", err)
 	}
 }
+
+func TestAuthLoginCmd_NoBrowser(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cmd := newAuthLoginCmdWithStore(newMockStore())
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--no-browser"})
+	if err := cmd.ExecuteContext(ctx); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("expected the cancelled login to fail, got %v", err)
+	}
+	if !regexp.MustCompile(`http://127\.0\.0\.1:\d+\n`).MatchString(out.String()) ||
+		!regexp.MustCompile(`one-time code: [A-Z0-9]{4}-[A-Z0-9]{4}`).MatchString(out.String()) ||
+		!strings.Contains(out.String(), "ssh -L") {
+		t.Errorf("expected the URL, code, and SSH hint, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Opening browser") {
+		t.Errorf("did not expect a browser to be opened, got:\n%s", out.String())
+	}
+}
+
+func TestAuthLoginCmd_NoBrowser_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"with token", []string{"--no-browser", "--token", "tok"}, "--no-browser does not apply"},
+		{"listen without no-browser", []string{"--listen", "127.0.0.1:8085"}, "--listen requires --no-browser"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newAuthLoginCmdWithStore(newMockStore())
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) || ExitCode(err) != ExitUsage {
+				t.Errorf("expected usage error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/salmonumbrella/line-official-cli/internal/tokenhealth"
+	"github.com/spf13/cobra"
+)
+
+// channelTokenEnv is the environment variable --export-env sets.
+const channelTokenEnv = "LINE_CHANNEL_TOKEN"
+
+func newAuthTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Access stored channel access tokens",
+		Long:  "Access the channel access tokens stored in the keyring.",
+	}
+
+	cmd.AddCommand(newAuthTokenPrintCmd())
+
+	return cmd
+}
+
+func newAuthTokenPrintCmd() *cobra.Command {
+	return newAuthTokenPrintCmdWithStore(nil)
+}
+
+func newAuthTokenPrintCmdWithStore(store secrets.Store) *cobra.Command {
+	var reveal bool
+	var exportEnv bool
+
+	cmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print a stored channel access token",
+		Long: `Print the stored channel access token of an account, for tools that need
+the raw token.
+
+The token is only printed with --reveal, and every reveal is recorded in
+the audit log. --export-env prints a shell export line instead:
+
+  eval "$(line auth token print --account prod --reveal --export-env)"`,
+		Example: `  line auth token print --account prod --reveal
+  line auth token print --account prod --reveal --export-env`,
+		Annotations: map[string]string{noPagerAnnotation: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !reveal {
+				return fmt.Errorf("refusing to print a token without --reveal; reveals are recorded in the audit log")
+			}
+
+			var err error
+			if store == nil {
+				store, err = openSecretsStore()
+				if err != nil {
+					return fmt.Errorf("failed to open keyring: %w", err)
+				}
+			}
+
+			account := flags.Account
+			if account == "" {
+				account, err = defaultStoredAccount(store)
+				if err != nil {
+					return err
+				}
+			}
+
+			creds, err := store.Get(account)
+			if err != nil {
+				return fmt.Errorf("failed to get credentials for %s: %w", account, err)
+			}
+			if creds.UsesChannelSecret() {
+				return fmt.Errorf("account %s stores a channel secret, not a token; issue a short-lived token with: line token issue-stateless", account)
+			}
+
+			format := "raw"
+			if exportEnv {
+				format = "export-env"
+			}
+			if err := recordTokenReveal(cmd, account, creds.ChannelAccessToken, format); err != nil {
+				return err
+			}
+
+			switch {
+			case exportEnv:
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "export %s=%s\n", channelTokenEnv, shellQuote(creds.ChannelAccessToken))
+			case flags.Output == "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]string{"account": account, "channelAccessToken": creds.ChannelAccessToken})
+			default:
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), creds.ChannelAccessToken)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "Print the token (recorded in the audit log)")
+	cmd.Flags().BoolVar(&exportEnv, "export-env", false, "Print an export "+channelTokenEnv+"=... line for eval")
+
+	return cmd
+}
+
+// recordTokenReveal records in the audit log that a stored token was
+// printed, identified by its fingerprint. Callers must not print the token
+// if this fails.
+func recordTokenReveal(cmd *cobra.Command, account, token, format string) error {
+	if err := audit.Append(audit.Entry{
+		Time:    clockNow().UTC(),
+		Account: account,
+		Action:  "token.reveal",
+		Details: map[string]any{
+			"tokenId": tokenhealth.TokenID(token),
+			"format":  format,
+			"command": cmd.CommandPath(),
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to record token reveal: %w", err)
+	}
+	return nil
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
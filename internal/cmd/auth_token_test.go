@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+)
+
+func runAuthTokenPrint(t *testing.T, store secrets.Store, account string, args ...string) (string, error) {
+	t.Helper()
+	oldAccount, oldOutput := flags.Account, flags.Output
+	flags.Account, flags.Output = account, "text"
+	defer func() { flags.Account, flags.Output = oldAccount, oldOutput }()
+
+	cmd := newAuthTokenPrintCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestAuthTokenPrint_RequiresReveal(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store := newMockStore()
+	_ = store.Set("prod", secrets.Credentials{ChannelAccessToken: "secret-token"}, "")
+
+	out, err := runAuthTokenPrint(t, store, "prod")
+	if err == nil || !strings.Contains(err.Error(), "--reveal") {
+		t.Fatalf("expected --reveal error, got %v", err)
+	}
+	if strings.Contains(out, "secret-token") {
+		t.Error("token printed without --reveal")
+	}
+	if n, _ := audit.CountAccount("prod"); n != 0 {
+		t.Errorf("expected no audit entry, got %d", n)
+	}
+}
+
+func TestAuthTokenPrint_Reveal(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store := newMockStore()
+	_ = store.Set("prod", secrets.Credentials{ChannelAccessToken: "secret-token"}, "")
+	_ = store.Set("staging", secrets.Credentials{ChannelAccessToken: "other-token"}, "")
+
+	out, err := runAuthTokenPrint(t, store, "prod", "--reveal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "secret-token\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	path, err := audit.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected audit log to be written: %v", err)
+	}
+	log := string(data)
+	if !strings.Contains(log, `"action":"token.reveal"`) || !strings.Contains(log, `"account":"prod"`) {
+		t.Errorf("unexpected audit log: %s", log)
+	}
+	if strings.Contains(log, "secret-token") {
+		t.Error("audit log must not contain the token")
+	}
+}
+
+func TestAuthTokenPrint_ExportEnv(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store := newMockStore()
+	_ = store.Set("prod", secrets.Credentials{ChannelAccessToken: "abc+/="}, "")
+
+	out, err := runAuthTokenPrint(t, store, "prod", "--reveal", "--export-env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "export LINE_CHANNEL_TOKEN='abc+/='\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestAuthTokenPrint_DefaultAccount(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store := newMockStore()
+	_ = store.Set("prod", secrets.Credentials{ChannelAccessToken: "prod-token"}, "")
+	_ = store.Set("staging", secrets.Credentials{ChannelAccessToken: "staging-token"}, "")
+	_ = store.SetPrimary("staging")
+
+	out, err := runAuthTokenPrint(t, store, "", "--reveal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "staging-token\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestAuthTokenPrint_StatelessAccount(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	store := newMockStore()
+	_ = store.Set("prod", secrets.Credentials{ChannelID: "1234", ChannelSecret: "channel-secret"}, "")
+
+	out, err := runAuthTokenPrint(t, store, "prod", "--reveal")
+	if err == nil || !strings.Contains(err.Error(), "issue-stateless") {
+		t.Fatalf("expected stateless error, got %v", err)
+	}
+	if strings.Contains(out, "channel-secret") {
+		t.Error("channel secret printed")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+}
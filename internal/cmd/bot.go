@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -125,47 +126,75 @@ func newBotFollowersCmd() *cobra.Command {
 }
 
 func newBotFollowersCmdWithClient(client *api.Client) *cobra.Command {
-	var limit int
-	var all bool
+	var page pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "followers",
 		Short: "List follower IDs",
-		Long:  "Get a list of user IDs of users who have added your bot as a friend.",
+		Long: `Get a list of user IDs of users who have added your bot as a friend.
+
+Only the first 100 are fetched unless --all or --limit is given. When more
+followers are available, text output ends with a "Next cursor" line and
+JSON output includes the "next" cursor; pass it back with --cursor to
+resume from that point in a later invocation.
+
+With --output ndjson, each follower is written as a {"userId": ...} line as
+its page arrives, so the output can be piped before the last page is
+fetched.`,
+		Annotations: map[string]string{ndjsonStreamAnnotation: ""},
 		Example: `  # Get first 100 followers
   line bot followers
 
   # Get all followers (paginated)
-  line bot followers --all`,
+  line bot followers --all
+
+  # Get the first 5000
+  line bot followers --limit 5000
+
+  # Resume from a cursor returned by a previous call
+  line bot followers --cursor <next> --output json
+
+  # Stream every follower, one JSON line each
+  line bot followers --all --output ndjson | head -n 1000`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			pageSize := 100
+			if page.all {
+				pageSize = 1000
+			}
+			opts, err := page.options(pageSize, 1000)
+			if err != nil {
+				return err
+			}
+
 			c := client
 			if c == nil {
-				var err error
 				c, err = newAPIClient()
 				if err != nil {
 					return err
 				}
 			}
 
-			var allUserIDs []string
-			var next string
-
-			for {
-				resp, err := c.GetFollowerIDs(cmd.Context(), next, limit)
+			fetch := func(ctx context.Context, cursor string, size int) ([]string, string, error) {
+				resp, err := c.GetFollowerIDs(ctx, cursor, size)
 				if err != nil {
-					return fmt.Errorf("failed to get followers: %w", err)
+					return nil, "", fmt.Errorf("failed to get followers: %w", err)
 				}
+				return resp.UserIDs, resp.Next, nil
+			}
+			if flags.Output == "ndjson" {
+				return streamNDJSON(cmd, opts, fetch, userIDLine)
+			}
 
-				allUserIDs = append(allUserIDs, resp.UserIDs...)
-
-				if !all || resp.Next == "" {
-					break
-				}
-				next = resp.Next
+			allUserIDs, next, err := api.Paginate(cmd.Context(), opts, fetch)
+			if err != nil {
+				return err
 			}
 
 			if flags.Output == "json" {
 				result := map[string]any{"userIds": allUserIDs, "count": len(allUserIDs)}
+				if next != "" {
+					result["next"] = next
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
 				return enc.Encode(result)
@@ -175,12 +204,12 @@ func newBotFollowersCmdWithClient(client *api.Client) *cobra.Command {
 			for _, id := range allUserIDs {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), id)
 			}
+			printNextCursor(cmd.OutOrStdout(), next)
 			return nil
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 100, "Number of IDs per request (max 1000)")
-	cmd.Flags().BoolVar(&all, "all", false, "Fetch all followers (paginated)")
+	addPageFlags(cmd, &page, "follower IDs")
 
 	return cmd
 }
@@ -537,6 +537,46 @@ func TestBotFollowersCmd_WithoutAll_StopsAtFirstPage(t *testing.T) {
 	}
 }
 
+func TestBotFollowersCmd_StartCursorAndNextInJSON(t *testing.T) {
+	var gotStart string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotStart = r.URL.Query().Get("start")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"userIds": []string{"U333"},
+			"next":    "page3token",
+		})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newBotFollowersCmdWithClient(client)
+	cmd.SetArgs([]string{"--start", "page2token"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotStart != "page2token" {
+		t.Errorf("expected start=page2token, got %q", gotStart)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result["next"] != "page3token" {
+		t.Errorf("expected next=page3token, got %v", result["next"])
+	}
+}
+
 func TestBotFollowersCmd_TextOutputListsIDs(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasPrefix(r.URL.Path, "/v2/bot/followers/ids") {
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// budgetSlowestCalls is how many of the slowest API calls a budget warning
+// lists.
+const budgetSlowestCalls = 3
+
+// callLog keeps the count and the slowest of the API calls made while a
+// command runs. It is safe for concurrent use.
+type callLog struct {
+	mu      sync.Mutex
+	calls   int
+	slowest []api.CallTiming
+}
+
+// commandCalls collects the API calls of the running command; newAPIClient
+// reports to it.
+var commandCalls = &callLog{}
+
+// commandStart is when the running command started, or zero if it never
+// got past flag parsing.
+var commandStart time.Time
+
+func (l *callLog) observe(t api.CallTiming) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	i := sort.Search(len(l.slowest), func(i int) bool { return l.slowest[i].Duration < t.Duration })
+	if i == budgetSlowestCalls {
+		return
+	}
+	l.slowest = append(l.slowest, api.CallTiming{})
+	copy(l.slowest[i+1:], l.slowest[i:])
+	l.slowest[i] = t
+	if len(l.slowest) > budgetSlowestCalls {
+		l.slowest = l.slowest[:budgetSlowestCalls]
+	}
+}
+
+func (l *callLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = 0
+	l.slowest = nil
+}
+
+// startCommandTimer marks the start of a command and forgets earlier calls.
+func startCommandTimer() {
+	commandStart = clockNow()
+	commandCalls.reset()
+}
+
+// commandClass returns the budget class of cmd: "list" for list commands,
+// "bulk" for bulk and batch commands, or "" if it has none.
+func commandClass(cmd *cobra.Command) string {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "bulk" || c.Name() == "batch" {
+			return "bulk"
+		}
+	}
+	if name := cmd.Name(); name == "list" || strings.HasPrefix(name, "list-") {
+		return "list"
+	}
+	return ""
+}
+
+// commandBudget returns the configured budget for cmd, looked up by command
+// path (without the root command name) and then by class. The returned key
+// is what matched.
+func commandBudget(budgets map[string]time.Duration, cmd *cobra.Command) (key string, budget time.Duration, ok bool) {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if budget, ok := budgets[path]; ok && budget > 0 {
+		return path, budget, true
+	}
+	if class := commandClass(cmd); class != "" {
+		if budget, ok := budgets[class]; ok && budget > 0 {
+			return class, budget, true
+		}
+	}
+	return "", 0, false
+}
+
+// checkCommandBudget warns on w if cmd ran longer than its budget, listing
+// the slowest API calls it made.
+func checkCommandBudget(w io.Writer, cmd *cobra.Command) {
+	if cmd == nil || commandStart.IsZero() || cfg == nil || len(cfg.Budgets) == 0 {
+		return
+	}
+	key, budget, ok := commandBudget(cfg.Budgets, cmd)
+	if !ok {
+		return
+	}
+	elapsed := clockNow().Sub(commandStart)
+	if elapsed <= budget {
+		return
+	}
+
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	source := ""
+	if key != path {
+		source = fmt.Sprintf(" (%s)", key)
+	}
+	warnf(w, "%s took %s, over its %s budget%s", path, elapsed.Round(time.Millisecond), budget, source)
+
+	commandCalls.mu.Lock()
+	defer commandCalls.mu.Unlock()
+	if commandCalls.calls == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "  Slowest of %d API calls:\n", commandCalls.calls)
+	for _, t := range commandCalls.slowest {
+		status := "failed"
+		if t.StatusCode != 0 {
+			status = fmt.Sprintf("%d", t.StatusCode)
+		}
+		_, _ = fmt.Fprintf(w, "  %10s  %s (%s)\n", t.Duration.Round(time.Millisecond), t.Endpoint, status)
+	}
+}
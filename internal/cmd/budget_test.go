@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// findCommand returns the command at path below the root command.
+func findCommand(t *testing.T, path ...string) *cobra.Command {
+	t.Helper()
+	c, _, err := NewRootCmd().Find(path)
+	if err != nil {
+		t.Fatalf("command %v not found: %v", path, err)
+	}
+	return c
+}
+
+func TestCommandClass(t *testing.T) {
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"audience", "list"}, "list"},
+		{[]string{"token", "list-keys"}, "list"},
+		{[]string{"richmenu", "bulk", "link"}, "bulk"},
+		{[]string{"richmenu", "batch"}, "bulk"},
+		{[]string{"bot", "info"}, ""},
+	}
+	for _, tt := range tests {
+		if got := commandClass(findCommand(t, tt.path...)); got != tt.want {
+			t.Errorf("commandClass(%v) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCommandBudget_PathBeforeClass(t *testing.T) {
+	budgets := map[string]time.Duration{"list": 3 * time.Second, "audience list": 10 * time.Second}
+
+	key, budget, ok := commandBudget(budgets, findCommand(t, "audience", "list"))
+	if !ok || key != "audience list" || budget != 10*time.Second {
+		t.Errorf("got %q %v %v", key, budget, ok)
+	}
+	key, budget, ok = commandBudget(budgets, findCommand(t, "coupon", "list"))
+	if !ok || key != "list" || budget != 3*time.Second {
+		t.Errorf("got %q %v %v", key, budget, ok)
+	}
+	if _, _, ok := commandBudget(budgets, findCommand(t, "bot", "info")); ok {
+		t.Error("expected no budget for bot info")
+	}
+}
+
+func TestCheckCommandBudget(t *testing.T) {
+	oldCfg, oldNow := cfg, clockNow
+	defer func() { cfg, clockNow = oldCfg, oldNow }()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clockNow = func() time.Time { return now }
+	cmd := findCommand(t, "audience", "list")
+	cfg = &config.Config{Budgets: map[string]time.Duration{"list": 3 * time.Second}}
+
+	startCommandTimer()
+	for i, d := range []time.Duration{200, 1500, 900, 2100} {
+		commandCalls.observe(api.CallTiming{
+			Endpoint:   "GET /v1/audienceGroup/list",
+			StatusCode: 200 + i,
+			Duration:   d * time.Millisecond,
+		})
+	}
+
+	// Within budget: no warning
+	now = now.Add(2 * time.Second)
+	var out bytes.Buffer
+	checkCommandBudget(&out, cmd)
+	if out.Len() != 0 {
+		t.Errorf("expected no warning, got %q", out.String())
+	}
+
+	now = now.Add(2 * time.Second)
+	checkCommandBudget(&out, cmd)
+	got := out.String()
+	if !strings.Contains(got, "Warning: audience list took 4s, over its 3s budget (list)") {
+		t.Errorf("unexpected warning: %s", got)
+	}
+	if !strings.Contains(got, "Slowest of 4 API calls:") {
+		t.Errorf("expected call count, got: %s", got)
+	}
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	if len(lines) != 2+budgetSlowestCalls {
+		t.Fatalf("expected %d slowest calls, got:\n%s", budgetSlowestCalls, got)
+	}
+	for i, want := range []string{"2.1s", "1.5s", "900ms"} {
+		if !strings.Contains(lines[2+i], want) {
+			t.Errorf("line %d: expected %s, got %q", i, want, lines[2+i])
+		}
+	}
+}
+
+func TestCheckCommandBudget_NotStarted(t *testing.T) {
+	oldCfg, oldStart := cfg, commandStart
+	defer func() { cfg, commandStart = oldCfg, oldStart }()
+
+	cfg = &config.Config{Budgets: map[string]time.Duration{"list": time.Nanosecond}}
+	commandStart = time.Time{}
+
+	var out bytes.Buffer
+	checkCommandBudget(&out, findCommand(t, "audience", "list"))
+	if out.Len() != 0 {
+		t.Errorf("expected no warning, got %q", out.String())
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// responseCacheDir returns where --cache-ttl keeps cached API responses.
+func responseCacheDir() (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "responses"), nil
+}
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the API response cache",
+		Long: `With --cache-ttl (or LINE_CACHE_TTL), read-only API calls such as bot
+info, profiles, rich menu and alias lists, audience lists, LIFF apps, and
+the webhook endpoint are answered from a local cache until the TTL passes.
+Entries are kept per account, and any change made through the CLI clears
+that account's entries. Use --no-cache to bypass the cache for one command.`,
+		Example: `  # Cache read-only calls for five minutes
+  export LINE_CACHE_TTL=5m
+
+  # Drop all cached responses
+  line cache clear`,
+	}
+	cmd.AddCommand(newCacheClearCmd())
+	return cmd
+}
+
+func newCacheClearCmd() *cobra.Command {
+	var account string
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove cached API responses",
+		Long:  "Remove every cached API response, or only those of one account with --for.",
+		Example: `  line cache clear
+  line cache clear --for my-shop`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := responseCacheDir()
+			if err != nil {
+				return fmt.Errorf("failed to resolve cache directory: %w", err)
+			}
+			n, err := api.ClearCache(dir, account)
+			if err != nil {
+				return err
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{"removed": n}
+				if account != "" {
+					result["account"] = account
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached response(s)\n", n)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&account, "for", "", "Only clear the responses cached for this account")
+
+	return cmd
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestCacheClearCmd(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	dir, err := responseCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range []string{"shop", "staging"} {
+		client := api.NewClient("test-token", false, false)
+		client.SetBaseURL(server.URL)
+		if err := client.SetCache(api.CacheOptions{Dir: dir, Key: account, TTL: time.Hour}); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = client.Get(context.Background(), "/v2/bot/info")
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--for", "shop"}, "Removed 1 cached response(s)"},
+		{nil, "Removed 1 cached response(s)"},
+		{nil, "Removed 0 cached response(s)"},
+	} {
+		var out bytes.Buffer
+		cmd := newCacheClearCmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs(tt.args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("args %v: expected %q, got %q", tt.args, tt.want, out.String())
+		}
+	}
+}
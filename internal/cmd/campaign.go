@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/campaign"
+	"github.com/spf13/cobra"
+)
+
+func newCampaignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "campaign",
+		Short: "Run narrowcast campaigns from a YAML file",
+	}
+
+	cmd.AddCommand(newCampaignRunCmd())
+
+	return cmd
+}
+
+func newCampaignRunCmd() *cobra.Command {
+	return newCampaignRunCmdWithClient(nil)
+}
+
+func newCampaignRunCmdWithClient(client *api.Client) *cobra.Command {
+	var specPath string
+	var statePath string
+	var restart bool
+	var retrySend bool
+	var overrideFreeze string
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run a narrowcast campaign pipeline",
+		Long: `Run the narrowcast campaign described in a YAML file, step by step:
+
+  audience  create the audience from a CSV file (or use an existing one)
+  ready     wait until the audience is READY
+  validate  validate the messages for narrowcast
+  send      send the narrowcast to the audience
+  delivery  wait until delivery has finished
+  stats     export the delivery statistics to a JSON file
+
+Progress is saved after every step to a state file next to the campaign
+file (campaign.yaml keeps it in campaign.state.json). Running the same
+campaign again resumes after the last completed step, so a campaign is
+never sent twice. If a run is interrupted while sending, the next run stops
+instead of guessing; check whether the narrowcast went out and pass
+--retry-send to send it again. --restart discards the state and starts over.
+
+With --dry-run the steps still to run are listed and nothing is done.`,
+		Example: `  # campaign.yaml
+  name: spring-sale
+  audience:
+    name: Spring sale 2026
+    file: targets.csv        # CSV of user IDs, relative to this file
+    column: user_id          # header of the ID column (default: first column)
+  text: "Spring sale starts today!"
+  wait:
+    audience: 30m
+    delivery: 2h
+    interval: 10s
+  stats:
+    file: spring-sale-stats.json
+
+  # Run it, or resume an interrupted run
+  line campaign run --file campaign.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if specPath == "" {
+				return fmt.Errorf("--file is required")
+			}
+			spec, sum, err := campaign.Load(specPath)
+			if err != nil {
+				return withExitCode(ExitUsage, err)
+			}
+			if statePath == "" {
+				statePath = campaign.StatePath(specPath)
+			}
+			state := &campaign.State{Steps: map[string]campaign.StepState{}}
+			if !restart {
+				state, err = campaign.LoadState(statePath)
+				if err != nil {
+					return err
+				}
+			}
+			if state.Started() && state.SpecSHA256 != sum {
+				return fmt.Errorf("%w; use --restart to start over", campaign.ErrSpecChanged)
+			}
+
+			if flags.DryRun {
+				return printCampaignPlan(cmd, spec, specPath, state)
+			}
+
+			sentBefore := state.Done(campaign.StepSend)
+			if !sentBefore {
+				if err := enforceFreeze(cmd, "narrowcast", overrideFreeze); err != nil {
+					return err
+				}
+			}
+
+			c := client
+			account := flags.Account
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+				account, _ = requireAccount(&flags)
+			}
+
+			runner := &campaign.Runner{
+				API:       c,
+				Spec:      spec,
+				SpecPath:  specPath,
+				SpecSHA:   sum,
+				State:     state,
+				StatePath: statePath,
+				RetrySend: retrySend,
+				Now:       clockNow,
+				Uploaded: func(audienceGroupID int64, file string) {
+					recordAudienceUploadFile(account, audienceGroupID, file)
+				},
+			}
+			if flags.Output != "json" {
+				runner.Log = func(step, format string, args ...any) {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", step, fmt.Sprintf(format, args...))
+				}
+			}
+			runErr := runner.Run(cmd.Context())
+
+			if !sentBefore && state.Done(campaign.StepSend) {
+				if err := audit.Append(audit.Entry{
+					Time:    clockNow().UTC(),
+					Account: flags.Account,
+					Action:  "campaign.run",
+					Details: map[string]any{
+						"campaign":        spec.Name,
+						"sha256":          sum,
+						"audienceGroupId": state.AudienceGroupID,
+						"requestId":       state.RequestID,
+					},
+				}); err != nil && runErr == nil {
+					runErr = fmt.Errorf("failed to record campaign send: %w", err)
+				}
+			}
+			if errors.Is(runErr, campaign.ErrSendUncertain) {
+				runErr = fmt.Errorf("%w; check 'line message narrowcast-status' or the LINE Official Account Manager, then rerun with --retry-send to send again", runErr)
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{
+					"campaign":        spec.Name,
+					"audienceGroupId": state.AudienceGroupID,
+					"requestId":       state.RequestID,
+					"steps":           state.Steps,
+					"state":           statePath,
+				}
+				if state.Done(campaign.StepStats) {
+					result["stats"] = spec.StatsPath(specPath)
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			} else if runErr == nil {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Campaign %s complete\n", spec.Name)
+			}
+			return runErr
+		},
+	}
+
+	cmd.Flags().StringVarP(&specPath, "file", "f", "", "Campaign YAML file (required)")
+	cmd.Flags().StringVar(&statePath, "state", "", "State file (default <file>.state.json next to the campaign file)")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Discard saved progress and run every step again")
+	cmd.Flags().BoolVar(&retrySend, "retry-send", false, "Send again after a run was interrupted while sending")
+	addFreezeOverrideFlag(cmd, &overrideFreeze)
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// printCampaignPlan lists the steps of a campaign and whether each is done,
+// for --dry-run.
+func printCampaignPlan(cmd *cobra.Command, spec *campaign.Spec, specPath string, state *campaign.State) error {
+	if flags.Output == "json" {
+		steps := make([]map[string]any, 0, len(campaign.Steps))
+		for _, step := range campaign.Steps {
+			steps = append(steps, map[string]any{"step": step, "done": state.Done(step)})
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"campaign": spec.Name, "dryRun": true, "steps": steps})
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "Campaign %s (dry run)\n", spec.Name)
+	for _, step := range campaign.Steps {
+		status := "to run"
+		if state.Done(step) {
+			status = "done"
+		}
+		_, _ = fmt.Fprintf(out, "  %-9s %s\n", step, status)
+	}
+	if spec.Audience.File != "" && !state.Done(campaign.StepAudience) {
+		if _, err := os.Stat(spec.AudienceFile(specPath)); err != nil {
+			warnf(cmd.ErrOrStderr(), "audience file %s not found", spec.AudienceFile(specPath))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+const testCampaignRun = `name: vip-offer
+audience:
+  id: 12345
+text: "VIP offer"
+wait:
+  interval: 1ms
+`
+
+func writeCampaignRun(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "campaign.yaml")
+	if err := os.WriteFile(path, []byte(testCampaignRun), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCampaignRunCmd(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	var narrowcasts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/audienceGroup/12345":
+			_, _ = w.Write([]byte(`{"audienceGroup":{"audienceGroupId":12345,"status":"READY","audienceCount":80}}`))
+		case "/v2/bot/message/validate/narrowcast":
+			_, _ = w.Write([]byte(`{}`))
+		case "/v2/bot/message/narrowcast":
+			narrowcasts++
+			w.Header().Set("X-Line-Request-Id", "req-abc")
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{}`))
+		case "/v2/bot/message/progress/narrowcast":
+			_, _ = w.Write([]byte(`{"phase":"succeeded","successCount":80,"failureCount":0}`))
+		case "/v2/bot/insight/message/event":
+			_, _ = w.Write([]byte(`{"overview":{"requestId":"req-abc","delivered":80}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	specPath := writeCampaignRun(t)
+
+	for range 2 {
+		cmd := newCampaignRunCmdWithClient(client)
+		cmd.SetArgs([]string{"--file", specPath})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v\n%s", err, out.String())
+		}
+		if !strings.Contains(out.String(), "Campaign vip-offer complete") {
+			t.Errorf("unexpected output: %s", out.String())
+		}
+	}
+	if narrowcasts != 1 {
+		t.Errorf("expected one narrowcast across two runs, got %d", narrowcasts)
+	}
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(specPath), "campaign-stats.json"))
+	if err != nil {
+		t.Fatalf("stats not exported: %v", err)
+	}
+	var stats map[string]any
+	if err := json.Unmarshal(data, &stats); err != nil || stats["requestId"] != "req-abc" {
+		t.Errorf("unexpected stats: %s", data)
+	}
+}
+
+func TestCampaignRunCmd_DryRun(t *testing.T) {
+	oldOutput, oldDryRun := flags.Output, flags.DryRun
+	defer func() { flags.Output, flags.DryRun = oldOutput, oldDryRun }()
+	flags.Output = "text"
+	flags.DryRun = true
+
+	specPath := writeCampaignRun(t)
+	cmd := newCampaignRunCmdWithClient(api.NewClient("test-token", false, true))
+	cmd.SetArgs([]string{"--file", specPath})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "send      to run") {
+		t.Errorf("expected a plan, got %s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(specPath), "campaign.state.json")); err == nil {
+		t.Error("dry run should not write state")
+	}
+}
@@ -31,19 +31,22 @@ func newChatLoadingCmdWithClient(client *api.Client) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "loading",
 		Short: "Show loading animation",
-		Long:  "Display a loading animation in a chat. The animation indicates the bot is processing and will be shown for the specified duration (default 5 seconds, max 60 seconds).",
+		Long: `Display a loading animation in a one-on-one chat. The animation indicates
+the bot is processing and is shown for --seconds (5 to 60, in steps of 5)
+or until the bot's next message arrives.`,
 		Example: `  # Show loading animation for default 5 seconds
   line chat loading --user U1234567890abcdef
 
-  # Show loading animation for 10 seconds
-  line chat loading --user U1234567890abcdef --seconds 10`,
+  # Show loading animation for 20 seconds
+  line chat loading --user U1234567890abcdef --seconds 20`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if userID == "" {
 				return fmt.Errorf("--user is required")
 			}
 
-			if seconds < 1 || seconds > 60 {
-				return fmt.Errorf("--seconds must be between 1 and 60")
+			// LINE accepts only multiples of 5
+			if seconds < 5 || seconds > 60 || seconds%5 != 0 {
+				return fmt.Errorf("--seconds must be 5, 10, 15, ... or 60")
 			}
 
 			c := client
@@ -77,7 +80,7 @@ func newChatLoadingCmdWithClient(client *api.Client) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&userID, "user", "", "User ID / chat ID (required)")
-	cmd.Flags().IntVar(&seconds, "seconds", 5, "Loading duration in seconds (1-60)")
+	cmd.Flags().IntVar(&seconds, "seconds", 5, "Loading duration in seconds (5-60, a multiple of 5)")
 	_ = cmd.MarkFlagRequired("user")
 
 	return cmd
@@ -47,7 +47,8 @@ func TestChatLoadingCmd_ValidatesSeconds(t *testing.T) {
 	}{
 		{"too low", "0", true},
 		{"too high", "61", true},
-		{"valid low", "1", false},
+		{"not a multiple of 5", "7", true},
+		{"valid 20", "20", false},
 		{"valid high", "60", false},
 		{"valid default", "5", false},
 	}
@@ -1,26 +1,287 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/auth"
+	"github.com/salmonumbrella/line-official-cli/internal/deprecation"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/salmonumbrella/line-official-cli/internal/tokenhealth"
 )
 
+// newAPIClientForAccount is newAPIClient for a named account, for commands
+// that act for several accounts in one run. An empty name is the default
+// account.
+func newAPIClientForAccount(name string) (*api.Client, error) {
+	saved := flags.Account
+	defer func() { flags.Account = saved }()
+	flags.Account = name
+	return newAPIClient()
+}
+
+// sessionClients keeps the API clients of a 'line repl' session, by the
+// flags they were built with, so later commands reuse them; nil outside a
+// session.
+var sessionClients map[string]*api.Client
+
+// clientAccountKey is the account the last client was built for, or
+// "env-" and a token fingerprint for credentials from the environment; the
+// recorded capabilities of the account are looked up by it.
+var clientAccountKey string
+
+// newAPIClient returns a client for the account the flags select. Its
+// credentials are resolved in this order:
+//
+//  1. the stored credentials of the account named with --account
+//  2. LINE_CHANNEL_ACCESS_TOKEN, or LINE_CHANNEL_ID and LINE_CHANNEL_SECRET
+//  3. the stored credentials of LINE_ACCOUNT, the config file's account, or
+//     the primary account
+//
+// so CI jobs can pass a token in the environment without running
+// 'line auth login'.
 func newAPIClient() (*api.Client, error) {
-	accountName, err := requireAccount(&flags)
+	if sessionClients == nil {
+		return buildAPIClient()
+	}
+	key := sessionClientKey()
+	if client, ok := sessionClients[key]; ok {
+		// Each command sets up its own logger
+		client.SetLogger(logger)
+		return client, nil
+	}
+	client, err := buildAPIClient()
 	if err != nil {
 		return nil, err
 	}
+	sessionClients[key] = client
+	return client, nil
+}
+
+// sessionClientKey identifies the client the flags call for. Flags that
+// only shape the output or the logs do not change it.
+func sessionClientKey() string {
+	f := flags
+	f.Output, f.Query, f.Columns, f.MaxColWidth, f.NoTruncate = "", "", nil, 0, false
+	f.Yes, f.NoPager, f.Color, f.NoColor = false, false, "", false
+	f.LogLevel, f.LogFormat, f.LogFile = "", "", ""
+	return fmt.Sprintf("%#v", f)
+}
+
+func buildAPIClient() (*api.Client, error) {
+	if flags.Record != "" && flags.Replay != "" {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("--record and --replay cannot be used together"))
+	}
+
+	var client *api.Client
+	// expiryToken is a stored token whose expiry is checked: not a
+	// stateless one, which is issued anew, nor on dry runs or recordings
+	var accountName, healthKey, expiryToken string
+	if flags.Replay != "" {
+		// Replayed responses need no account or credentials
+		client = api.NewClient("", flags.Debug, flags.DryRun)
+		if err := client.SetReplay(flags.Replay); err != nil {
+			return nil, withExitCode(ExitUsage, err)
+		}
+	} else {
+		creds, ok := envCredentials()
+		if !ok || flags.AccountSet {
+			var err error
+			accountName, err = requireAccount(&flags)
+			if err != nil {
+				return nil, err
+			}
+
+			store, err := openSecretsStore()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open keyring: %w", err)
+			}
+
+			creds, err = store.Get(accountName)
+			if err != nil {
+				return nil, withExitCode(ExitAuth, fmt.Errorf("failed to get credentials for %s: %w", accountName, err))
+			}
+		}
+
+		client = auth.NewClient(*creds, flags.Debug, flags.DryRun)
+		applyNetwork(client)
+		if flags.Record != "" {
+			if err := client.SetRecord(flags.Record); err != nil {
+				return nil, err
+			}
+		}
+		healthKey = tokenHealthKey(creds)
+		if !creds.UsesChannelSecret() && !flags.DryRun && flags.Record == "" {
+			expiryToken = creds.ChannelAccessToken
+		}
+	}
+	client.SetLogger(logger)
+	client.SetClock(func() time.Time { return clockNow() })
+	client.SetUUIDGenerator(func() string { return newUUID() })
+	if base := configuredAPIBaseURL(); base != "" {
+		client.SetBaseURL(base)
+	}
+	client.SetRetry(api.RetryOptions{MaxRetries: flags.MaxRetries, Backoff: flags.RetryBackoff})
+	// Innermost, so every attempt is traced as sent, including retries and
+	// requests to failover hosts
+	if flags.DebugHTTP || flags.DebugHTTPBodies {
+		client.SetTrace(api.TraceOptions{Writer: os.Stderr, Bodies: flags.DebugHTTPBodies, NoRedact: flags.NoRedact})
+	}
+	if cfg != nil && (cfg.Failover.API != "" || cfg.Failover.Data != "") {
+		err := client.SetFailover(api.FailoverOptions{
+			Secondaries: map[string]string{api.BaseURL: cfg.Failover.API, api.DataBaseURL: cfg.Failover.Data},
+			Threshold:   cfg.Failover.Threshold,
+			Cooldown:    cfg.Failover.Cooldown,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid failover config: %w", err)
+		}
+	}
+	// Credentials from the environment have no account; key what is kept
+	// for them by token instead
+	clientAccountKey = accountName
+	if clientAccountKey == "" && healthKey != "" {
+		clientAccountKey = "env-" + tokenhealth.TokenID(healthKey)
+	}
+	if accountName != "" {
+		trackTokenHealth(os.Stderr, client, accountName, healthKey)
+		if expiryToken != "" {
+			warnTokenExpiry(os.Stderr, client, accountName, expiryToken)
+		}
+	}
+	trackDeprecations(os.Stderr, client)
+	// Replayed sends were never made
+	if flags.Replay == "" {
+		recordSends(os.Stderr, client, clientAccountKey)
+	}
+	client.OnTiming(commandCalls.observe)
 
-	store, err := openSecretsStore()
+	// Outermost, so time spent waiting for the limiter is not counted as
+	// API call time
+	limits, limited, err := rateLimits(cfg.RateLimits, flags.RateLimits)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open keyring: %w", err)
+		return nil, withExitCode(ExitUsage, err)
+	}
+	if limited {
+		client.SetRateLimit(limits)
+	}
+
+	// Outside everything else, so a cache hit makes no request at all: it
+	// is not traced, timed, or rate limited
+	if flags.CacheTTL > 0 && !flags.NoCache && flags.Record == "" && flags.Replay == "" && !flags.DryRun {
+		dir, err := responseCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve cache directory: %w", err)
+		}
+		if err := client.SetCache(api.CacheOptions{Dir: dir, Key: clientAccountKey, TTL: flags.CacheTTL}); err != nil {
+			return nil, withExitCode(ExitUsage, err)
+		}
 	}
+	return client, nil
+}
 
-	creds, err := store.Get(accountName)
+// tokenHealthKey returns what token health is tracked by: the stored token,
+// or the channel secret for accounts that issue stateless tokens.
+func tokenHealthKey(creds *secrets.Credentials) string {
+	if creds.UsesChannelSecret() {
+		return creds.ChannelSecret
+	}
+	return creds.ChannelAccessToken
+}
+
+// trackTokenHealth warns if the account's token has been failing and
+// records the outcome of every request the client makes. Tracking errors
+// are ignored so they never block a command.
+func trackTokenHealth(w io.Writer, client *api.Client, account, token string) {
+	tokenID := tokenhealth.TokenID(token)
+	if rec, err := tokenhealth.Get(account, tokenID); err == nil {
+		if msg := tokenhealth.Warning(account, rec, clockNow()); msg != "" {
+			warnf(w, "%s", msg)
+		}
+	}
+
+	client.OnResponse(func(statusCode int) {
+		switch {
+		case statusCode == http.StatusUnauthorized:
+			_ = tokenhealth.Observe(account, tokenID, false, clockNow())
+		case statusCode < 300:
+			_ = tokenhealth.Observe(account, tokenID, true, clockNow())
+		}
+	})
+}
+
+// warnTokenExpiry warns if the account's token expires within a day,
+// checking its expiry with LINE at most once a day. Errors are ignored so
+// they never block a command.
+func warnTokenExpiry(w io.Writer, client *api.Client, account, token string) {
+	tokenID := tokenhealth.TokenID(token)
+	now := clockNow()
+	rec, err := tokenhealth.Get(account, tokenID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get credentials for %s: %w", accountName, err)
+		return
+	}
+	if tokenhealth.NeedsExpiryCheck(rec, now) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		expiresAt, err := auth.TokenExpiry(ctx, client, token, now)
+		if err != nil && rec != nil {
+			// Keep what is known and try again after the interval
+			expiresAt = rec.ExpiresAt
+		}
+		if tokenhealth.RecordExpiry(account, tokenID, expiresAt, now) != nil {
+			return
+		}
+		if rec, err = tokenhealth.Get(account, tokenID); err != nil {
+			return
+		}
+	}
+	if msg := tokenhealth.ExpiryWarning(account, rec, now); msg != "" {
+		warnf(w, "%s", msg)
 	}
+}
+
+// trackDeprecations prints a one-line notice when a response carries
+// Deprecation, Sunset, or Warning headers, at most once per day per
+// endpoint. State errors are ignored so they never block a command.
+func trackDeprecations(w io.Writer, client *api.Client) {
+	client.OnDeprecation(func(n api.DeprecationNotice) {
+		if ok, err := deprecation.Claim(n.Endpoint, clockNow()); err != nil || !ok {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "Notice: %s\n", deprecationMessage(n))
+	})
+}
 
-	return api.NewClient(creds.ChannelAccessToken, flags.Debug, flags.DryRun), nil
+// deprecationMessage formats a notice as a single line.
+func deprecationMessage(n api.DeprecationNotice) string {
+	msg := "LINE API " + n.Endpoint
+	switch {
+	case n.Deprecated && !n.Since.IsZero() && n.Since.After(clockNow()):
+		msg += " will be deprecated on " + n.Since.Format("2006-01-02")
+	case n.Deprecated && !n.Since.IsZero():
+		msg += " is deprecated since " + n.Since.Format("2006-01-02")
+	case n.Deprecated:
+		msg += " is deprecated"
+	case n.Sunset.IsZero():
+		msg += " returned a warning"
+	}
+	if !n.Sunset.IsZero() {
+		if n.Deprecated {
+			msg += ";"
+		}
+		msg += " will be removed on " + n.Sunset.Format("2006-01-02")
+	}
+	if len(n.Warnings) > 0 {
+		msg += ": " + strings.Join(n.Warnings, "; ")
+	}
+	if n.Link != "" {
+		msg += " (" + n.Link + ")"
+	}
+	return msg
 }
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestTrackTokenHealth_WarnsAfterAuthFailures(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		var warn bytes.Buffer
+		client := api.NewClient("revoked-token", false, false)
+		client.SetBaseURL(server.URL)
+		trackTokenHealth(&warn, client, "prod", "revoked-token")
+		if i == 0 && warn.Len() != 0 {
+			t.Errorf("expected no warning before any failures, got: %s", warn.String())
+		}
+		if i == 1 && !strings.Contains(warn.String(), "token for 'prod' has failed 1 time today, no successful request recorded") {
+			t.Errorf("expected failure warning, got: %q", warn.String())
+		}
+		_, _ = client.GetBotInfo(context.Background())
+	}
+}
+
+func TestWarnTokenExpiry_ChecksOncePerInterval(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	oldNow := clockNow
+	clockNow = func() time.Time { return now }
+	defer func() { clockNow = oldNow }()
+
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks++
+		if r.URL.Path != "/oauth2/v2.1/verify" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"client_id":"1234","expires_in":7200}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("token", false, false)
+	client.SetBaseURL(server.URL)
+
+	for i := 0; i < 2; i++ {
+		var warn bytes.Buffer
+		warnTokenExpiry(&warn, client, "prod", "token")
+		if !strings.Contains(warn.String(), "token for 'prod' expires in 2 hours") {
+			t.Errorf("run %d: expected expiry warning, got %q", i, warn.String())
+		}
+	}
+	if checks != 1 {
+		t.Errorf("expected one expiry check, got %d", checks)
+	}
+}
+
+func TestTrackDeprecations_OncePerDayPerEndpoint(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	oldNow := clockNow
+	defer func() { clockNow = oldNow }()
+	clockNow = func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@1767225600") // 2026-01-01
+		w.Header().Set("Sunset", "Wed, 30 Dec 2026 00:00:00 GMT")
+		w.Header().Set("Link", `<https://developers.line.biz/en/news/>; rel="deprecation"`)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var notices bytes.Buffer
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	trackDeprecations(&notices, client)
+
+	_, _ = client.Get(context.Background(), "/v2/bot/audienceGroup/111")
+	_, _ = client.Get(context.Background(), "/v2/bot/audienceGroup/222")
+	_, _ = client.Get(context.Background(), "/v2/bot/info")
+
+	lines := strings.Split(strings.TrimSpace(notices.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one notice per endpoint, got %d: %q", len(lines), notices.String())
+	}
+	want := "Notice: LINE API GET /v2/bot/audienceGroup/{id} is deprecated since 2026-01-01; will be removed on 2026-12-30 (https://developers.line.biz/en/news/)"
+	if lines[0] != want {
+		t.Errorf("notice = %q\nwant     %q", lines[0], want)
+	}
+}
+
+func TestDeprecationMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		notice api.DeprecationNotice
+		want   string
+	}{
+		{
+			name:   "deprecated without date",
+			notice: api.DeprecationNotice{Endpoint: "GET /v2/bot/x", Deprecated: true},
+			want:   "LINE API GET /v2/bot/x is deprecated",
+		},
+		{
+			name:   "sunset only",
+			notice: api.DeprecationNotice{Endpoint: "GET /v2/bot/x", Sunset: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)},
+			want:   "LINE API GET /v2/bot/x will be removed on 2026-12-01",
+		},
+		{
+			name:   "warning only",
+			notice: api.DeprecationNotice{Endpoint: "POST /v2/bot/y", Warnings: []string{"use /v3/bot/y"}},
+			want:   "LINE API POST /v2/bot/y returned a warning: use /v3/bot/y",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deprecationMessage(tt.notice); got != tt.want {
+				t.Errorf("deprecationMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAPIClient_ReplayNeedsNoAccount(t *testing.T) {
+	writeTestConfig(t, "")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("LINE_ACCOUNT", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"userId":"Ubot","basicId":"@bot","displayName":"Replay Bot","chatMode":"bot"}`))
+	}))
+	dir := t.TempDir()
+	recorder := api.NewClient("token", false, false)
+	recorder.SetBaseURL(server.URL)
+	if err := recorder.SetRecord(dir); err != nil {
+		t.Fatalf("SetRecord: %v", err)
+	}
+	if _, err := recorder.GetBotInfo(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server.Close()
+
+	out, err := runConfigTestCmd(t, "bot", "info", "--replay", dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Display Name: Replay Bot") {
+		t.Errorf("expected replayed bot info, got:\n%s", out)
+	}
+
+	_, err = runConfigTestCmd(t, "bot", "info", "--replay", dir, "--record", t.TempDir())
+	if err == nil || ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error for --record with --replay, got %v", err)
+	}
+}
+
+func TestNewAPIClient_EnvCredentials(t *testing.T) {
+	writeTestConfig(t, "credential_store: file\n")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("LINE_ACCOUNT", "")
+	t.Setenv("LINE_CHANNEL_ACCESS_TOKEN", "env-token")
+
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"userId":"Ubot","basicId":"@bot","displayName":"CI Bot","chatMode":"bot"}`))
+	}))
+	defer server.Close()
+
+	out, err := runConfigTestCmd(t, "bot", "info", "--base-url", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer env-token" || !strings.Contains(out, "Display Name: CI Bot") {
+		t.Errorf("expected a request with the token from the environment, got %q:\n%s", auth, out)
+	}
+
+	// An explicit --account takes precedence over the environment
+	_, err = runConfigTestCmd(t, "bot", "info", "--base-url", server.URL, "--account", "prod")
+	if err == nil || !strings.Contains(err.Error(), "failed to get credentials for prod") {
+		t.Errorf("expected the stored credentials of prod to be used, got %v", err)
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	tests := []struct {
+		name               string
+		token, id, secret  string
+		wantOK, wantSecret bool
+	}{
+		{"none", "", "", "", false, false},
+		{"token", "tok", "", "", true, false},
+		{"channel secret", "", "1234", "s3cret", true, true},
+		{"secret alone", "", "", "s3cret", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LINE_CHANNEL_ACCESS_TOKEN", tt.token)
+			t.Setenv("LINE_CHANNEL_ID", tt.id)
+			t.Setenv("LINE_CHANNEL_SECRET", tt.secret)
+			creds, ok := envCredentials()
+			if ok != tt.wantOK {
+				t.Fatalf("envCredentials() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && creds.UsesChannelSecret() != tt.wantSecret {
+				t.Errorf("UsesChannelSecret() = %v, want %v", creds.UsesChannelSecret(), tt.wantSecret)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// clockNow and newUUID are the time and identifier sources for commands.
+// Tests replace them for deterministic timestamps and IDs; newAPIClient
+// hands them to the API client so both sides agree.
+var (
+	clockNow = time.Now
+	newUUID  = api.RandomUUID
+)
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/salmonumbrella/line-official-cli/internal/output"
+)
+
+// colorEnabled reports whether output written to w should be colored:
+// always with --color always, never with --color never or --no-color, and
+// otherwise only when w is a terminal (or the pager in front of one) and
+// NO_COLOR is unset.
+func colorEnabled(w io.Writer) bool {
+	if flags.Color == output.ColorAuto && activePager != nil && w == io.Writer(activePager) {
+		// The pager shows the output on the terminal
+		return os.Getenv("NO_COLOR") == ""
+	}
+	return output.ColorEnabled(flags.Color, w)
+}
+
+// palette returns the colors for output written to w.
+func palette(w io.Writer) output.Palette {
+	return output.Palette{Enabled: colorEnabled(w)}
+}
+
+// bold wraps s in bold escapes when color is enabled for w.
+func bold(w io.Writer, s string) string {
+	return palette(w).Bold(s)
+}
+
+// colorStatus colors a status for w, e.g. READY green and FAILED red.
+func colorStatus(w io.Writer, status string) string {
+	return palette(w).Status(status)
+}
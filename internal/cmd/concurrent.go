@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// runConcurrently runs independent tasks in parallel and waits for all of
+// them to finish. The tasks share a context derived from ctx that is
+// cancelled as soon as one fails, so the others can stop early. If any
+// task fails, the error from the earliest task in argument order is
+// returned, so error messages stay deterministic regardless of which call
+// finished first; errors caused by that cancellation never hide the one
+// that triggered it.
+func runConcurrently(ctx context.Context, tasks ...func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(tasks))
+	var first error
+	var once sync.Once
+
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errs[i] = task(ctx); errs[i] != nil {
+				once.Do(func() {
+					first = errs[i]
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		// A task stopped by the cancellation only echoes the first failure
+		if err == nil || (err != first && errors.Is(err, context.Canceled)) {
+			continue
+		}
+		return err
+	}
+	return first
+}
+
+// enrichConcurrently calls fn for every item with at most limit calls in
+// flight and returns the results in input order. Errors are handled as in
+// runConcurrently.
+func enrichConcurrently[T, R any](ctx context.Context, items []T, limit int, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	results := make([]R, len(items))
+	sem := make(chan struct{}, limit)
+	tasks := make([]func(ctx context.Context) error, len(items))
+	for i, item := range items {
+		tasks[i] = func(ctx context.Context) error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+
+			r, err := fn(ctx, item)
+			if err != nil {
+				return err
+			}
+			results[i] = r
+			return nil
+		}
+	}
+
+	if err := runConcurrently(ctx, tasks...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunConcurrently_ReturnsFirstErrorInOrder(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	err := runConcurrently(context.Background(),
+		func(ctx context.Context) error {
+			// Finish last so ordering is by argument position, not completion
+			time.Sleep(20 * time.Millisecond)
+			return errFirst
+		},
+		func(ctx context.Context) error { return errSecond },
+		func(ctx context.Context) error { return nil },
+	)
+	if !errors.Is(err, errFirst) {
+		t.Errorf("expected first error, got %v", err)
+	}
+}
+
+func TestRunConcurrently_CancelsOnFirstError(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	err := runConcurrently(context.Background(),
+		func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return errors.New("not cancelled")
+			}
+		},
+		func(ctx context.Context) error { return errFailed },
+	)
+	if !errors.Is(err, errFailed) {
+		t.Errorf("expected the failure rather than the cancellation, got %v", err)
+	}
+}
+
+func TestRunConcurrently_RunsInParallel(t *testing.T) {
+	start := time.Now()
+	err := runConcurrently(context.Background(),
+		func(ctx context.Context) error { time.Sleep(50 * time.Millisecond); return nil },
+		func(ctx context.Context) error { time.Sleep(50 * time.Millisecond); return nil },
+		func(ctx context.Context) error { time.Sleep(50 * time.Millisecond); return nil },
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 140*time.Millisecond {
+		t.Errorf("tasks appear to run sequentially (took %v)", elapsed)
+	}
+}
+
+func TestEnrichConcurrently_PreservesOrderAndLimit(t *testing.T) {
+	var inFlight, maxInFlight int32
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+
+	results, err := enrichConcurrently(context.Background(), items, 3, func(ctx context.Context, n int) (int, error) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		for {
+			prev := atomic.LoadInt32(&maxInFlight)
+			if cur <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, cur) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return n * 10, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, n := range items {
+		if results[i] != n*10 {
+			t.Errorf("results[%d] = %d, want %d", i, results[i], n*10)
+		}
+	}
+	if maxInFlight > 3 {
+		t.Errorf("expected at most 3 calls in flight, got %d", maxInFlight)
+	}
+}
+
+func TestEnrichConcurrently_Error(t *testing.T) {
+	_, err := enrichConcurrently(context.Background(), []string{"a", "b"}, 2, func(ctx context.Context, s string) (string, error) {
+		if s == "b" {
+			return "", errors.New("lookup failed")
+		}
+		return s, nil
+	})
+	if err == nil || err.Error() != "lookup failed" {
+		t.Errorf("expected lookup error, got %v", err)
+	}
+}
@@ -2,12 +2,53 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/salmonumbrella/line-official-cli/internal/api"
 	"github.com/salmonumbrella/line-official-cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
+// configSetting describes a settable config key: the environment variable
+// that overrides it and the value used when neither is set.
+type configSetting struct {
+	Key     string
+	Env     string
+	Default string
+}
+
+var configSettings = []configSetting{
+	{Key: "account", Env: "LINE_ACCOUNT", Default: "(primary account)"},
+	{Key: "output", Env: "LINE_OUTPUT", Default: "text"},
+	{Key: "debug", Env: "LINE_DEBUG", Default: "false"},
+	{Key: "credential_store", Default: "keychain"},
+	{Key: "timezone", Env: "LINE_TIMEZONE", Default: "(local time)"},
+	{Key: "api_base_url", Env: "LINE_API_BASE_URL", Default: api.BaseURL},
+	{Key: "color", Env: "LINE_COLOR", Default: "auto"},
+	{Key: "proxy", Env: "HTTPS_PROXY", Default: "(none)"},
+	{Key: "ca_cert", Default: "(system CAs)"},
+}
+
+// resolve returns the value of s that commands use before flags are
+// applied, and where it came from: env, file, or default.
+func (s configSetting) resolve() (value, source string) {
+	if s.Env != "" {
+		if v := os.Getenv(s.Env); v != "" {
+			return v, "env (" + s.Env + ")"
+		}
+	}
+	if v, ok, _ := cfg.Get(s.Key); ok {
+		return v, "file"
+	}
+	return s.Default, "default"
+}
+
 func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
@@ -20,18 +61,25 @@ Configuration is loaded from (in order of priority):
   3. Config file
   4. Built-in defaults (lowest)
 
+Use 'line config list' to see where each value comes from.
+
 Config file locations (first found is used):
   - $XDG_CONFIG_HOME/line-cli/config.yaml
   - ~/.config/line-cli/config.yaml
   - ~/.line-cli.yaml`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runConfig()
+			return runConfig(cmd.OutOrStdout())
 		},
 	}
 
 	cmd.AddCommand(newConfigShowCmd())
 	cmd.AddCommand(newConfigPathCmd())
 	cmd.AddCommand(newConfigExampleCmd())
+	cmd.AddCommand(newConfigGetCmd())
+	cmd.AddCommand(newConfigSetCmd())
+	cmd.AddCommand(newConfigUnsetCmd())
+	cmd.AddCommand(newConfigListCmd())
+	cmd.AddCommand(newConfigEditCmd())
 
 	return cmd
 }
@@ -41,7 +89,7 @@ func newConfigShowCmd() *cobra.Command {
 		Use:   "show",
 		Short: "Show current configuration values",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runConfig()
+			return runConfig(cmd.OutOrStdout())
 		},
 	}
 }
@@ -91,59 +139,260 @@ func newConfigExampleCmd() *cobra.Command {
 	}
 }
 
-func runConfig() error {
+func newConfigSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a value in the config file",
+		Long: `Set a value in the config file, creating the file if needed. The rest of
+the file, including comments, is kept.
+
+Settable keys: ` + strings.Join(config.SettableKeys(), ", ") + `.`,
+		Example: `  line config set credential-store file
+  line config set output json
+  line config set timezone Asia/Tokyo`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			previous := getDefault(configuredCredentialStore(), "keychain")
+			path, err := config.Set(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Set %s = %s in %s\n", args[0], args[1], path)
+			if key := strings.ReplaceAll(args[0], "-", "_"); key == "credential_store" && args[1] != previous {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Existing credentials are not moved; run: line account migrate-store --from %s --to %s\n", previous, args[1])
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a value from the config file",
+		Long: `Print the value of a key as set in the config file. Exits with an error if
+the key is not set; use 'line config list' to see effective values.
+
+Keys: ` + strings.Join(config.SettableKeys(), ", ") + `.`,
+		Example: `  line config get output
+  line config get api-base-url`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, ok, err := cfg.Get(args[0])
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("%s is not set in the config file", args[0])
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	}
+}
+
+func newConfigUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a value from the config file",
+		Long: `Remove a key from the config file so its environment variable or built-in
+default applies again. The rest of the file, including comments, is kept.`,
+		Example: `  line config unset timezone`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, removed, err := config.Unset(args[0])
+			if err != nil {
+				return err
+			}
+			if !removed {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s is not set in %s\n", args[0], path)
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Unset %s in %s\n", args[0], path)
+			return nil
+		},
+	}
+}
+
+func newConfigListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List effective config values and their sources",
+		Long: `List every config key with the value commands use and where it comes from:
+an environment variable, the config file, or the built-in default. Flags
+given on the command line override these.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			type settingOutput struct {
+				Key    string `json:"key"`
+				Value  string `json:"value"`
+				Source string `json:"source"`
+			}
+			out := make([]settingOutput, 0, len(configSettings))
+			for _, s := range configSettings {
+				value, source := s.resolve()
+				out = append(out, settingOutput{Key: s.Key, Value: value, Source: source})
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+
+			table := NewTable("KEY", "VALUE", "SOURCE")
+			for _, o := range out {
+				table.AddRow(o.Key, o.Value, o.Source)
+			}
+			table.Render(cmd.OutOrStdout())
+			return nil
+		},
+	}
+}
+
+func newConfigEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in your editor",
+		Long: `Open the config file in $VISUAL or $EDITOR (default vi), creating it from
+'line config example' if there is none yet. The file is checked after the
+editor exits.`,
+		Annotations: map[string]string{noPagerAnnotation: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.WritablePath()
+			if err != nil {
+				return fmt.Errorf("failed to resolve config path: %w", err)
+			}
+			if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					return fmt.Errorf("failed to create config directory: %w", err)
+				}
+				if err := os.WriteFile(path, []byte(config.ExampleConfig()), 0600); err != nil {
+					return fmt.Errorf("failed to create config: %w", err)
+				}
+			}
+
+			editor := getDefault(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi")
+			c := exec.Command("sh", "-c", editor+" "+shellQuote(path))
+			c.Stdin = os.Stdin
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+			if err := c.Run(); err != nil {
+				return fmt.Errorf("failed to run editor %q: %w", editor, err)
+			}
+
+			edited, err := config.LoadFile(path)
+			if err == nil {
+				err = edited.Validate()
+			}
+			if err != nil {
+				return fmt.Errorf("config file %s is invalid (run 'line config edit' again to fix it): %w", path, err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Saved %s\n", path)
+			return nil
+		},
+	}
+}
+
+func runConfig(w io.Writer) error {
 	if flags.Output == "json" {
 		type configOutput struct {
 			ConfigPath string `json:"config_path,omitempty"`
 			Account    string `json:"account,omitempty"`
 			Output     string `json:"output"`
 			Debug      bool   `json:"debug"`
+			// CredentialStore is empty when not set (keychain)
+			CredentialStore string `json:"credential_store,omitempty"`
+			Timezone        string `json:"timezone,omitempty"`
+			APIBaseURL      string `json:"api_base_url,omitempty"`
+			Color           string `json:"color"`
+			Proxy           string `json:"proxy,omitempty"`
+			CACert          string `json:"ca_cert,omitempty"`
 		}
 		out := configOutput{
-			ConfigPath: cfg.ConfigPath(),
-			Account:    cfg.Account,
-			Output:     getDefault(cfg.Output, "text"),
-			Debug:      cfg.Debug,
+			ConfigPath:      cfg.ConfigPath(),
+			Account:         cfg.Account,
+			Output:          getDefault(cfg.Output, "text"),
+			Debug:           cfg.Debug,
+			CredentialStore: cfg.CredentialStore,
+			Timezone:        cfg.Timezone,
+			APIBaseURL:      cfg.APIBaseURL,
+			Color:           getDefault(cfg.Color, "auto"),
+			Proxy:           cfg.Proxy,
+			CACert:          cfg.CACert,
 		}
-		enc := json.NewEncoder(nil)
+		enc := json.NewEncoder(w)
 		enc.SetIndent("", "  ")
-		data, _ := json.MarshalIndent(out, "", "  ")
-		fmt.Println(string(data))
-		return nil
+		return enc.Encode(out)
 	}
 
 	// Text output
-	fmt.Println("Configuration")
-	fmt.Println("=============")
+	_, _ = fmt.Fprintln(w, "Configuration")
+	_, _ = fmt.Fprintln(w, "=============")
 
 	if path := cfg.ConfigPath(); path != "" {
-		fmt.Printf("Config file: %s\n", path)
+		_, _ = fmt.Fprintf(w, "Config file: %s\n", path)
 	} else {
-		fmt.Println("Config file: (not found)")
+		_, _ = fmt.Fprintln(w, "Config file: (not found)")
 		if recommended, err := config.DefaultConfigPath(); err == nil {
-			fmt.Printf("             Create at: %s\n", recommended)
+			_, _ = fmt.Fprintf(w, "             Create at: %s\n", recommended)
 		}
 	}
 
-	fmt.Println()
-	fmt.Println("Values (from config file):")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Values (from config file):")
 
 	if cfg.Account != "" {
-		fmt.Printf("  account: %s\n", cfg.Account)
+		_, _ = fmt.Fprintf(w, "  account: %s\n", cfg.Account)
 	} else {
-		fmt.Println("  account: (not set)")
+		_, _ = fmt.Fprintln(w, "  account: (not set)")
 	}
 
 	if cfg.Output != "" {
-		fmt.Printf("  output:  %s\n", cfg.Output)
+		_, _ = fmt.Fprintf(w, "  output:  %s\n", cfg.Output)
+	} else {
+		_, _ = fmt.Fprintln(w, "  output:  (not set, default: text)")
+	}
+
+	_, _ = fmt.Fprintf(w, "  debug:   %v\n", cfg.Debug)
+
+	if cfg.CredentialStore != "" {
+		_, _ = fmt.Fprintf(w, "  credential_store: %s\n", cfg.CredentialStore)
+	} else {
+		_, _ = fmt.Fprintln(w, "  credential_store: (not set, default: keychain)")
+	}
+
+	if cfg.Timezone != "" {
+		_, _ = fmt.Fprintf(w, "  timezone: %s\n", cfg.Timezone)
 	} else {
-		fmt.Println("  output:  (not set, default: text)")
+		_, _ = fmt.Fprintln(w, "  timezone: (not set, default: local time)")
 	}
 
-	fmt.Printf("  debug:   %v\n", cfg.Debug)
+	if cfg.APIBaseURL != "" {
+		_, _ = fmt.Fprintf(w, "  api_base_url: %s\n", cfg.APIBaseURL)
+	} else {
+		_, _ = fmt.Fprintf(w, "  api_base_url: (not set, default: %s)\n", api.BaseURL)
+	}
+
+	if cfg.Color != "" {
+		_, _ = fmt.Fprintf(w, "  color:   %s\n", cfg.Color)
+	} else {
+		_, _ = fmt.Fprintln(w, "  color:   (not set, default: auto)")
+	}
+
+	if cfg.Proxy != "" {
+		_, _ = fmt.Fprintf(w, "  proxy:   %s\n", cfg.Proxy)
+	} else {
+		_, _ = fmt.Fprintln(w, "  proxy:   (not set, default: HTTPS_PROXY env)")
+	}
+
+	if cfg.CACert != "" {
+		_, _ = fmt.Fprintf(w, "  ca_cert: %s\n", cfg.CACert)
+	} else {
+		_, _ = fmt.Fprintln(w, "  ca_cert: (not set, default: system CAs)")
+	}
 
-	fmt.Println()
-	fmt.Println("Run 'line config example' to see an example config file.")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Run 'line config example' to see an example config file.")
 
 	return nil
 }
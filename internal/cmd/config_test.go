@@ -2,8 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
 )
 
 func TestConfigCmd_Execute(t *testing.T) {
@@ -14,12 +19,13 @@ func TestConfigCmd_Execute(t *testing.T) {
 	cmd.SetErr(buf)
 	cmd.SetArgs([]string{"config"})
 
-	// Note: runConfig() writes directly to stdout via fmt.Println,
-	// so we only verify the command executes without error.
 	err := cmd.Execute()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !strings.Contains(buf.String(), "Configuration") {
+		t.Errorf("expected configuration in output, got %q", buf.String())
+	}
 }
 
 func TestConfigShowCmd_Execute(t *testing.T) {
@@ -30,12 +36,13 @@ func TestConfigShowCmd_Execute(t *testing.T) {
 	cmd.SetErr(buf)
 	cmd.SetArgs([]string{"config", "show"})
 
-	// Note: runConfig() writes directly to stdout via fmt.Println,
-	// so we only verify the command executes without error.
 	err := cmd.Execute()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !strings.Contains(buf.String(), "Configuration") {
+		t.Errorf("expected configuration in output, got %q", buf.String())
+	}
 }
 
 func TestConfigPathCmd_Execute(t *testing.T) {
@@ -115,12 +122,13 @@ func TestConfigCmd_JSONOutput(t *testing.T) {
 	cmd.SetErr(buf)
 	cmd.SetArgs([]string{"--output", "json", "config"})
 
-	// Note: runConfig() writes directly to stdout via fmt.Println,
-	// so we only verify the command executes without error.
 	err := cmd.Execute()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !strings.Contains(buf.String(), `"output"`) {
+		t.Errorf("expected JSON configuration in output, got %q", buf.String())
+	}
 }
 
 func TestConfigShowCmd_JSONOutput(t *testing.T) {
@@ -131,20 +139,21 @@ func TestConfigShowCmd_JSONOutput(t *testing.T) {
 	cmd.SetErr(buf)
 	cmd.SetArgs([]string{"--output", "json", "config", "show"})
 
-	// Note: runConfig() writes directly to stdout via fmt.Println,
-	// so we only verify the command executes without error.
 	err := cmd.Execute()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !strings.Contains(buf.String(), `"output"`) {
+		t.Errorf("expected JSON configuration in output, got %q", buf.String())
+	}
 }
 
 func TestConfigCmd_HasSubcommands(t *testing.T) {
 	cmd := newConfigCmd()
 
 	subcommands := cmd.Commands()
-	if len(subcommands) != 3 {
-		t.Errorf("expected 3 subcommands, got %d", len(subcommands))
+	if len(subcommands) != 8 {
+		t.Errorf("expected 8 subcommands, got %d", len(subcommands))
 	}
 
 	names := make(map[string]bool)
@@ -158,7 +167,152 @@ func TestConfigCmd_HasSubcommands(t *testing.T) {
 	if !names["path"] {
 		t.Error("expected 'path' subcommand")
 	}
+	if !names["set"] {
+		t.Error("expected 'set' subcommand")
+	}
 	if !names["example"] {
 		t.Error("expected 'example' subcommand")
 	}
+	for _, name := range []string{"get", "unset", "list", "edit"} {
+		if !names[name] {
+			t.Errorf("expected '%s' subcommand", name)
+		}
+	}
+}
+
+// writeTestConfig points the config search path at a temporary directory
+// holding content, and returns the config file path. The loaded config and
+// flags are reset when the test ends.
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	t.Cleanup(func() {
+		cfg = &config.Config{}
+		flags = rootFlags{}
+	})
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(dir, config.AppName, "config.yaml")
+	if content != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func runConfigTestCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestConfigGetCmd(t *testing.T) {
+	writeTestConfig(t, "output: json\n")
+
+	out, err := runConfigTestCmd(t, "config", "get", "output")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "json\n" {
+		t.Errorf("got %q", out)
+	}
+
+	if _, err := runConfigTestCmd(t, "config", "get", "timezone"); err == nil || !strings.Contains(err.Error(), "not set") {
+		t.Errorf("expected not set error, got %v", err)
+	}
+	if _, err := runConfigTestCmd(t, "config", "get", "nope"); err == nil || !strings.Contains(err.Error(), "unknown config key") {
+		t.Errorf("expected unknown key error, got %v", err)
+	}
+}
+
+func TestConfigUnsetCmd(t *testing.T) {
+	path := writeTestConfig(t, "output: json\ncolor: never\n")
+
+	out, err := runConfigTestCmd(t, "config", "unset", "color")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Unset color") {
+		t.Errorf("got %q", out)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "output: json\n" {
+		t.Errorf("config = %q", data)
+	}
+
+	out, err = runConfigTestCmd(t, "config", "unset", "color")
+	if err != nil || !strings.Contains(out, "not set") {
+		t.Errorf("got %q, %v", out, err)
+	}
+}
+
+func TestConfigListCmd_Sources(t *testing.T) {
+	writeTestConfig(t, "output: table\ntimezone: Asia/Tokyo\n")
+	t.Setenv("LINE_OUTPUT", "json")
+	t.Setenv("LINE_TIMEZONE", "")
+	t.Setenv("LINE_COLOR", "")
+	t.Setenv("LINE_API_BASE_URL", "")
+
+	out, err := runConfigTestCmd(t, "config", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var settings []struct {
+		Key    string `json:"key"`
+		Value  string `json:"value"`
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal([]byte(out), &settings); err != nil {
+		t.Fatalf("expected JSON from LINE_OUTPUT=json, got %q: %v", out, err)
+	}
+	got := map[string]string{}
+	for _, s := range settings {
+		got[s.Key] = s.Value + " / " + s.Source
+	}
+	want := map[string]string{
+		"output":       "json / env (LINE_OUTPUT)",
+		"timezone":     "Asia/Tokyo / file",
+		"color":        "auto / default",
+		"api_base_url": "https://api.line.me / default",
+	}
+	for key, w := range want {
+		if got[key] != w {
+			t.Errorf("%s = %q, want %q", key, got[key], w)
+		}
+	}
+}
+
+func TestConfigEditCmd_CreatesAndValidates(t *testing.T) {
+	path := writeTestConfig(t, "")
+	t.Setenv("VISUAL", "")
+
+	// An editor that leaves the file alone keeps the example config
+	t.Setenv("EDITOR", "true")
+	out, err := runConfigTestCmd(t, "config", "edit")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Saved "+path) {
+		t.Errorf("got %q", out)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != config.ExampleConfig() {
+		t.Error("expected the example config to be written")
+	}
+
+	// An edit that breaks the config is reported
+	t.Setenv("EDITOR", "printf 'color: rainbow\\n' >")
+	if _, err := runConfigTestCmd(t, "config", "edit"); err == nil || !strings.Contains(err.Error(), "invalid color") {
+		t.Errorf("expected validation error, got %v", err)
+	}
 }
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// inputIsTerminal reports whether in is an interactive terminal. Tests
+// replace it to simulate one.
+var inputIsTerminal = func(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// askYesNo writes question to w and reports whether the answer read from in
+// is yes. Anything else, including no answer, is no.
+func askYesNo(w io.Writer, in io.Reader, question string) bool {
+	_, _ = fmt.Fprintf(w, "%s [y/N]: ", question)
+	var response string
+	_, _ = fmt.Fscanln(in, &response)
+	return response == "y" || response == "Y" || response == "yes"
+}
+
+// confirmDestructive asks on the terminal before cmd does something that
+// cannot be undone, and returns an "<action> cancelled" error unless the
+// answer is yes. It does not ask with --yes (or LINE_ASSUME_YES), with
+// --dry-run, or when input is not a terminal, so scripts run unchanged. The
+// question goes to stderr to keep it out of piped output.
+func confirmDestructive(cmd *cobra.Command, action, question string) error {
+	if flags.Yes || flags.DryRun || !inputIsTerminal(cmd.InOrStdin()) {
+		return nil
+	}
+	if !askYesNo(cmd.ErrOrStderr(), cmd.InOrStdin(), question) {
+		return fmt.Errorf("%s cancelled", action)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// simulateTerminal makes confirmDestructive treat input as a terminal.
+func simulateTerminal(t *testing.T) {
+	t.Helper()
+	old := inputIsTerminal
+	inputIsTerminal = func(io.Reader) bool { return true }
+	t.Cleanup(func() { inputIsTerminal = old })
+}
+
+func TestConfirmDestructive_Commands(t *testing.T) {
+	tests := []struct {
+		name     string
+		newCmd   func(*api.Client) *cobra.Command
+		args     []string
+		question string
+	}{
+		{"richmenu delete", newRichMenuDeleteCmdWithClient, []string{"--id", "richmenu-123"}, "Delete rich menu richmenu-123? [y/N]: "},
+		{"richmenu cancel-default", newRichMenuCancelDefaultCmdWithClient, nil, "Remove the default rich menu for all users? [y/N]: "},
+		{"audience delete", newAudienceDeleteCmdWithClient, []string{"--id", "42"}, "Delete audience group 42? [y/N]: "},
+		{"coupon close", newCouponCloseCmdWithClient, []string{"--id", "coupon-1"}, "Close coupon coupon-1? It cannot be reopened. [y/N]: "},
+	}
+
+	for _, tt := range tests {
+		for _, answer := range []string{"n\n", "\n", "y\n"} {
+			t.Run(tt.name+" "+strings.TrimSpace(answer), func(t *testing.T) {
+				simulateTerminal(t)
+				var requests atomic.Int32
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests.Add(1)
+					_, _ = w.Write([]byte(`{}`))
+				}))
+				defer server.Close()
+				client := api.NewClient("token", false, false)
+				client.SetBaseURL(server.URL)
+
+				cmd := tt.newCmd(client)
+				var out, errOut bytes.Buffer
+				cmd.SetIn(strings.NewReader(answer))
+				cmd.SetOut(&out)
+				cmd.SetErr(&errOut)
+				cmd.SetArgs(tt.args)
+				err := cmd.Execute()
+
+				if !strings.Contains(errOut.String(), tt.question) {
+					t.Errorf("expected prompt %q on stderr, got %q", tt.question, errOut.String())
+				}
+				if answer == "y\n" {
+					if err != nil || requests.Load() != 1 {
+						t.Errorf("expected one request after yes, got %d requests, err %v", requests.Load(), err)
+					}
+					return
+				}
+				if err == nil || !strings.Contains(err.Error(), "cancelled") {
+					t.Errorf("expected cancelled error, got %v", err)
+				}
+				if requests.Load() != 0 {
+					t.Errorf("expected no request after %q, got %d", answer, requests.Load())
+				}
+			})
+		}
+	}
+}
+
+func TestConfirmDestructive_Skipped(t *testing.T) {
+	tests := []struct {
+		name     string
+		terminal bool
+		yes      bool
+		dryRun   bool
+	}{
+		{"not a terminal", false, false, false},
+		{"yes", true, true, false},
+		{"dry run", true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, oldFlags := inputIsTerminal, flags
+			inputIsTerminal = func(io.Reader) bool { return tt.terminal }
+			flags.Yes, flags.DryRun = tt.yes, tt.dryRun
+			defer func() { inputIsTerminal, flags = old, oldFlags }()
+
+			cmd := &cobra.Command{}
+			var errOut bytes.Buffer
+			cmd.SetIn(strings.NewReader(""))
+			cmd.SetErr(&errOut)
+			if err := confirmDestructive(cmd, "delete", "Delete?"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if errOut.Len() != 0 {
+				t.Errorf("expected no prompt, got %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestAssumeYesEnv(t *testing.T) {
+	writeTestConfig(t, "")
+	t.Setenv("LINE_ASSUME_YES", "true")
+
+	cmd := NewRootCmd()
+	if f := cmd.PersistentFlags().Lookup("yes"); f == nil || f.DefValue != "true" {
+		t.Errorf("expected LINE_ASSUME_YES to default --yes to true, got %v", f)
+	}
+}
@@ -231,7 +231,7 @@ func newContentStatusCmdWithClient(client *api.Client) *cobra.Command {
 			}
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Message ID: %s\n", messageID)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Transcoding Status: %s\n", status.Status)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Transcoding Status: %s\n", colorStatus(cmd.OutOrStdout(), status.Status))
 			return nil
 		},
 	}
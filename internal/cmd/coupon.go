@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +24,8 @@ func newCouponCmd() *cobra.Command {
 	cmd.AddCommand(newCouponCreateCmd())
 	cmd.AddCommand(newCouponGetCmd())
 	cmd.AddCommand(newCouponCloseCmd())
+	cmd.AddCommand(newCouponReportCmd())
+	cmd.AddCommand(newCouponScheduleCmd())
 
 	return cmd
 }
@@ -31,20 +36,47 @@ func newCouponListCmd() *cobra.Command {
 
 func newCouponListCmdWithClient(client *api.Client) *cobra.Command {
 	var status string
-	var limit int
+	var expiringWithin string
+	var sortBy string
+	var filterTitle string
+	var page pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all coupons",
-		Long:  "Get a list of all coupons associated with your LINE Official Account.",
+		Long: `Get a list of all coupons associated with your LINE Official Account.
+
+Only the first page is fetched unless --all or --limit is given. When more
+coupons are available, text output ends with a "Next cursor" line and JSON
+output includes the "next" cursor; pass it back with --cursor to fetch the
+following page.
+
+--expiring-within keeps the coupons that are not closed and end within the
+given time, such as 7d or 36h; it is applied to the coupons fetched, as are
+--filter-title and --sort.
+
+Each coupon shows when it starts or ends relative to now, such as "ends in
+3 days"; --output table adds the start and end times.`,
 		Example: `  # List all coupons
   line coupon list
 
   # List only running coupons
   line coupon list --status running
 
-  # List with limit
-  line coupon list --limit 10`,
+  # List at most 10
+  line coupon list --limit 10
+
+  # List every coupon
+  line coupon list --all
+
+  # Coupons ending in the next week, to close or schedule a close for
+  line coupon list --all --expiring-within 7d
+
+  # Summer coupons in a table, soonest ending first
+  line coupon list --all --filter-title summer --sort end --output table
+
+  # Continue from a cursor returned by a previous call
+  line coupon list --cursor <next> --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Convert status to uppercase for API (do this before client creation)
 			var statusFilter []string
@@ -60,20 +92,56 @@ func newCouponListCmdWithClient(client *api.Client) *cobra.Command {
 					return fmt.Errorf("invalid status: %s (use running, draft, or closed)", status)
 				}
 			}
+			if sortBy != "" && !validCouponSort(sortBy) {
+				return fmt.Errorf("invalid sort: %s (use title, status, start, or end)", sortBy)
+			}
+			var within time.Duration
+			if expiringWithin != "" {
+				var err error
+				if within, err = parseWithin(expiringWithin); err != nil {
+					return err
+				}
+			}
+			opts, err := page.options(0, 100)
+			if err != nil {
+				return err
+			}
 
 			c := client
 			if c == nil {
-				var err error
 				c, err = newAPIClient()
 				if err != nil {
 					return err
 				}
 			}
 
-			resp, err := c.ListCoupons(cmd.Context(), statusFilter, limit, "")
+			coupons, next, err := api.Paginate(cmd.Context(), opts, func(ctx context.Context, cursor string, size int) ([]api.Coupon, string, error) {
+				resp, err := c.ListCoupons(ctx, statusFilter, size, cursor)
+				if err != nil {
+					return nil, "", fmt.Errorf("failed to list coupons: %w", err)
+				}
+				if within == 0 && filterTitle == "" {
+					return resp.Coupons, resp.Next, nil
+				}
+				now := clockNow()
+				title := strings.ToLower(filterTitle)
+				var matched []api.Coupon
+				for _, coupon := range resp.Coupons {
+					if within > 0 && !couponExpiring(coupon, now, within) {
+						continue
+					}
+					if !strings.Contains(strings.ToLower(coupon.Title), title) {
+						continue
+					}
+					matched = append(matched, coupon)
+				}
+				return matched, resp.Next, nil
+			})
 			if err != nil {
-				return fmt.Errorf("failed to list coupons: %w", err)
+				return err
 			}
+			sortCoupons(coupons, sortBy)
+			resp := api.CouponListResponse{Coupons: coupons, Next: next}
 
 			if flags.Output == "json" {
 				enc := json.NewEncoder(cmd.OutOrStdout())
@@ -86,37 +154,156 @@ func newCouponListCmdWithClient(client *api.Client) *cobra.Command {
 				return nil
 			}
 
+			now := clockNow()
+			if flags.Output == "table" {
+				table := NewTable("ID", "TITLE", "STATUS", "START", "END", "WINDOW")
+				for _, coupon := range resp.Coupons {
+					table.AddRow(coupon.CouponID, coupon.Title, coupon.Status,
+						couponTime(coupon.StartTimestamp), couponTime(coupon.EndTimestamp), couponWindow(coupon, now))
+				}
+				table.Render(cmd.OutOrStdout())
+				printNextCursor(cmd.OutOrStdout(), resp.Next)
+				return nil
+			}
+
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Coupons:")
 			for _, coupon := range resp.Coupons {
 				statusStr := ""
 				if coupon.Status != "" {
 					statusStr = fmt.Sprintf(" [%s]", coupon.Status)
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s%s\n", coupon.CouponID, coupon.Title, statusStr)
-			}
-
-			if resp.Next != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nMore coupons available. Use pagination to fetch more.\n")
+				windowStr := ""
+				if window := couponWindow(coupon, now); window != "" {
+					windowStr = " " + window
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s  %s%s%s\n", coupon.CouponID, coupon.Title, statusStr, windowStr)
 			}
 
+			printNextCursor(cmd.OutOrStdout(), resp.Next)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&status, "status", "", "Filter by status: running, draft, or closed")
-	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of coupons to return")
+	cmd.Flags().StringVar(&expiringWithin, "expiring-within", "", "Only coupons that are not closed and end within this time, e.g. 7d")
+	cmd.Flags().StringVar(&sortBy, "sort", "", "Sort by title, status, start, or end")
+	cmd.Flags().StringVar(&filterTitle, "filter-title", "", "Only coupons whose title contains this text (case-insensitive)")
+	addPageFlags(cmd, &page, "coupons")
 
 	return cmd
 }
 
+// couponStatusOrder ranks statuses for --sort status: running coupons
+// first, then drafts, then closed ones.
+var couponStatusOrder = map[string]int{"RUNNING": 0, "DRAFT": 1, "CLOSED": 2}
+
+func validCouponSort(by string) bool {
+	switch by {
+	case "title", "status", "start", "end":
+		return true
+	}
+	return false
+}
+
+// sortCoupons sorts coupons in place by title, status, start, or end,
+// keeping the API order for ties. Coupons without a start or end time
+// sort last.
+func sortCoupons(coupons []api.Coupon, by string) {
+	timeKey := func(ms int64) int64 {
+		if ms <= 0 {
+			return 1<<63 - 1
+		}
+		return ms
+	}
+	var less func(a, b api.Coupon) bool
+	switch by {
+	case "title":
+		less = func(a, b api.Coupon) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	case "status":
+		rank := func(status string) int {
+			if r, ok := couponStatusOrder[status]; ok {
+				return r
+			}
+			return len(couponStatusOrder)
+		}
+		less = func(a, b api.Coupon) bool { return rank(a.Status) < rank(b.Status) }
+	case "start":
+		less = func(a, b api.Coupon) bool { return timeKey(a.StartTimestamp) < timeKey(b.StartTimestamp) }
+	case "end":
+		less = func(a, b api.Coupon) bool { return timeKey(a.EndTimestamp) < timeKey(b.EndTimestamp) }
+	default:
+		return
+	}
+	sort.SliceStable(coupons, func(i, j int) bool { return less(coupons[i], coupons[j]) })
+}
+
+// couponTime formats a coupon timestamp in milliseconds, or "-" when unset.
+func couponTime(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).Local().Format("2006-01-02 15:04")
+}
+
+// couponWindow describes where now falls in a coupon's validity window,
+// such as "starts in 2 days", "ends in 3 days", or "ended 5 hours ago".
+// Closed coupons that have not reached their end time are "closed".
+func couponWindow(c api.Coupon, now time.Time) string {
+	if c.StartTimestamp > 0 {
+		if start := time.UnixMilli(c.StartTimestamp); now.Before(start) && c.Status != "CLOSED" {
+			return "starts " + relativeTime(start, now)
+		}
+	}
+	if c.EndTimestamp <= 0 {
+		return ""
+	}
+	end := time.UnixMilli(c.EndTimestamp)
+	if !now.Before(end) {
+		return "ended " + relativeTime(end, now)
+	}
+	if c.Status == "CLOSED" {
+		return "closed"
+	}
+	return "ends " + relativeTime(end, now)
+}
+
+// relativeTime describes t relative to now in whole minutes, hours, or
+// days, such as "in 3 days" or "5 hours ago".
+func relativeTime(t, now time.Time) string {
+	d := t.Sub(now)
+	future := d >= 0
+	if !future {
+		d = -d
+	}
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "hour"
+	default:
+		n, unit = int(d/(24*time.Hour)), "day"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	if future {
+		return fmt.Sprintf("in %d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
+
 func newCouponCreateCmd() *cobra.Command {
 	return newCouponCreateCmdWithClient(nil)
 }
 
 func newCouponCreateCmdWithClient(client *api.Client) *cobra.Command {
 	var title string
-	var startTimestamp int64
-	var endTimestamp int64
+	var start string
+	var end string
 	var description string
 	var imageURL string
 	var discount int
@@ -132,11 +319,17 @@ func newCouponCreateCmdWithClient(client *api.Client) *cobra.Command {
 
 Required fields:
   --title          Coupon title
-  --start          Start timestamp (Unix milliseconds)
-  --end            End timestamp (Unix milliseconds)
+  --start          When the coupon opens
+  --end            When the coupon ends
   --max-use        Maximum number of times a user can use this coupon
   --visibility     Visibility setting: PUBLIC or UNLISTED
-  --acquisition    How users can acquire the coupon: normal or lottery`,
+  --acquisition    How users can acquire the coupon: normal or lottery
+
+--start and --end take an RFC 3339 time, a date and time such as
+"2025-07-01 09:00", a day and time such as "tomorrow 09:00", an offset from
+now such as +7d, or a Unix timestamp in milliseconds. Times without an
+offset are in --timezone when it is an IANA name such as Asia/Tokyo, and
+the local timezone otherwise.`,
 		Example: `  # Create a basic coupon with fixed discount
   line coupon create --title "Summer Sale" \
     --start 1704067200000 --end 1735689600000 \
@@ -149,6 +342,12 @@ Required fields:
     --max-use 1 --visibility UNLISTED --acquisition lottery \
     --discount 1000
 
+  # Open a coupon tomorrow morning for one week
+  line coupon create --title "Flash Sale" \
+    --start "tomorrow 09:00" --end +8d \
+    --max-use 1 --visibility PUBLIC --acquisition normal \
+    --discount 300
+
   # Create a coupon with timezone and description
   line coupon create --title "Welcome" \
     --start 1704067200000 --end 1735689600000 \
@@ -159,11 +358,11 @@ Required fields:
 			if title == "" {
 				return fmt.Errorf("--title is required")
 			}
-			if startTimestamp == 0 {
-				return fmt.Errorf("--start is required (Unix timestamp in milliseconds)")
+			if start == "" {
+				return fmt.Errorf("--start is required (e.g. 2025-07-01T09:00+09:00 or \"tomorrow 09:00\")")
 			}
-			if endTimestamp == 0 {
-				return fmt.Errorf("--end is required (Unix timestamp in milliseconds)")
+			if end == "" {
+				return fmt.Errorf("--end is required (e.g. 2025-07-31T23:59+09:00 or +7d)")
 			}
 			if maxUse <= 0 {
 				return fmt.Errorf("--max-use is required (must be > 0)")
@@ -187,7 +386,23 @@ Required fields:
 				return fmt.Errorf("invalid --acquisition: %s (use normal or lottery)", acquisitionCondition)
 			}
 
-			// Validate timestamps
+			// Parse and validate timestamps, in the coupon's timezone when
+			// it names one
+			loc := time.Local
+			if timezone != "" {
+				if l, err := time.LoadLocation(timezone); err == nil {
+					loc = l
+				}
+			}
+			startTime, _, err := parseTime(start, clockNow(), loc)
+			if err != nil {
+				return timeFlagError("--start", start)
+			}
+			endTime, _, err := parseTime(end, clockNow(), loc)
+			if err != nil {
+				return timeFlagError("--end", end)
+			}
+			startTimestamp, endTimestamp := startTime.UnixMilli(), endTime.UnixMilli()
 			if startTimestamp >= endTimestamp {
 				return fmt.Errorf("--start must be before --end")
 			}
@@ -247,8 +462,8 @@ Required fields:
 	}
 
 	cmd.Flags().StringVar(&title, "title", "", "Coupon title (required)")
-	cmd.Flags().Int64Var(&startTimestamp, "start", 0, "Start timestamp in milliseconds (required)")
-	cmd.Flags().Int64Var(&endTimestamp, "end", 0, "End timestamp in milliseconds (required)")
+	cmd.Flags().StringVar(&start, "start", "", "When the coupon opens, e.g. \"tomorrow 09:00\" or Unix milliseconds (required)")
+	cmd.Flags().StringVar(&end, "end", "", "When the coupon ends, e.g. +7d or 2025-07-31T23:59+09:00 (required)")
 	cmd.Flags().IntVar(&maxUse, "max-use", 0, "Max times a user can use this coupon (required)")
 	cmd.Flags().StringVar(&visibility, "visibility", "", "Visibility: PUBLIC or UNLISTED (required)")
 	cmd.Flags().StringVar(&acquisitionCondition, "acquisition", "", "Acquisition type: normal or lottery (required)")
@@ -266,12 +481,21 @@ func newCouponGetCmd() *cobra.Command {
 
 func newCouponGetCmdWithClient(client *api.Client) *cobra.Command {
 	var couponID string
+	var usageLog string
 
 	cmd := &cobra.Command{
-		Use:     "get",
-		Short:   "Get coupon details",
-		Long:    "Get detailed information about a specific coupon.",
-		Example: `  line coupon get --id coupon-xxx`,
+		Use:   "get",
+		Short: "Get coupon details",
+		Long: `Get detailed information about a specific coupon.
+
+--usage-log writes what is known about the coupon's use to a CSV file.
+The Messaging API does not expose per-user redemption records for any
+account tier, so the file counts, by day, the sends of messages carrying
+the coupon from the local message history: sends, users named by pushes
+and multicasts, and broadcasts. Redemptions are shown in LINE Official
+Account Manager (Coupons > Usage).`,
+		Example: `  line coupon get --id coupon-xxx
+  line coupon get --id coupon-xxx --usage-log usage.csv`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if couponID == "" {
 				return fmt.Errorf("--id is required")
@@ -291,6 +515,22 @@ func newCouponGetCmdWithClient(client *api.Client) *cobra.Command {
 				return fmt.Errorf("failed to get coupon: %w", err)
 			}
 
+			if usageLog != "" {
+				account, err := localAccountKey()
+				if err != nil {
+					return err
+				}
+				days, err := couponSendsByDay(account, couponID, "", "")
+				if err != nil {
+					return err
+				}
+				if err := writeCouponDaysCSV(usageLog, days); err != nil {
+					return err
+				}
+				// Per-user records are not in the API; say what the file holds
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Wrote daily send counts for %s (%d day(s)) to %s; per-user redemptions are only in LINE Official Account Manager\n", couponID, len(days), usageLog)
+			}
+
 			if flags.Output == "json" {
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
@@ -303,7 +543,7 @@ func newCouponGetCmdWithClient(client *api.Client) *cobra.Command {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\n", coupon.Description)
 			}
 			if coupon.Status != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:      %s\n", coupon.Status)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:      %s\n", colorStatus(cmd.OutOrStdout(), coupon.Status))
 			}
 			if coupon.StartTimestamp > 0 {
 				startTime := time.UnixMilli(coupon.StartTimestamp)
@@ -330,6 +570,7 @@ func newCouponGetCmdWithClient(client *api.Client) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&couponID, "id", "", "Coupon ID (required)")
+	cmd.Flags().StringVar(&usageLog, "usage-log", "", "Write daily send counts of the coupon from the message history to this CSV file")
 	_ = cmd.MarkFlagRequired("id")
 
 	return cmd
@@ -351,6 +592,9 @@ func newCouponCloseCmdWithClient(client *api.Client) *cobra.Command {
 			if couponID == "" {
 				return fmt.Errorf("--id is required")
 			}
+			if err := confirmDestructive(cmd, "close", fmt.Sprintf("Close coupon %s? It cannot be reopened.", couponID)); err != nil {
+				return err
+			}
 
 			c := client
 			if c == nil {
@@ -385,3 +629,150 @@ func newCouponCloseCmdWithClient(client *api.Client) *cobra.Command {
 
 	return cmd
 }
+
+// couponReport is the JSON output of 'coupon report'.
+type couponReport struct {
+	CouponID   string      `json:"couponId"`
+	Title      string      `json:"title"`
+	Status     string      `json:"status,omitempty"`
+	Start      *time.Time  `json:"start,omitempty"`
+	End        *time.Time  `json:"end,omitempty"`
+	Window     string      `json:"window,omitempty"`
+	From       string      `json:"from,omitempty"`
+	To         string      `json:"to,omitempty"`
+	Sends      int         `json:"sends"`
+	Recipients int         `json:"recipients"`
+	Broadcasts int         `json:"broadcasts"`
+	Days       []couponDay `json:"days"`
+}
+
+func newCouponReportCmd() *cobra.Command {
+	return newCouponReportCmdWithClient(nil)
+}
+
+func newCouponReportCmdWithClient(client *api.Client) *cobra.Command {
+	var couponID string
+	var from string
+	var to string
+	var csvPath string
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report a coupon's validity window and sends by day",
+		Long: `Report a coupon's details and validity window with how often it was sent
+each day, from the local message history (see "coupon get --usage-log").
+
+The range defaults to the coupon's validity window; --from and --to narrow
+or widen it. Sends count the pushes, multicasts, broadcasts, and
+narrowcasts that carried the coupon; recipients count the users named by
+pushes and multicasts. The Messaging API does not expose acquisition or
+redemption statistics, which are shown in LINE Official Account Manager
+(Coupons > Statistics).
+
+--csv also writes the daily counts to a CSV file.`,
+		Example: `  line coupon report --id coupon-001
+  line coupon report --id coupon-001 --from 20260101 --to 20260131 --csv coupon-001.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if couponID == "" {
+				return fmt.Errorf("--id is required")
+			}
+			from, to, err := resolveDateRange(from, to)
+			if err != nil {
+				return err
+			}
+			account, err := localAccountKey()
+			if err != nil {
+				return err
+			}
+
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			coupon, err := c.GetCoupon(cmd.Context(), couponID)
+			if err != nil {
+				return fmt.Errorf("failed to get coupon: %w", err)
+			}
+
+			report := couponReport{
+				CouponID: coupon.CouponID,
+				Title:    coupon.Title,
+				Status:   coupon.Status,
+				Window:   couponWindow(*coupon, clockNow()),
+				From:     from,
+				To:       to,
+			}
+			if coupon.StartTimestamp > 0 {
+				start := time.UnixMilli(coupon.StartTimestamp)
+				report.Start = &start
+				if report.From == "" {
+					report.From = start.Local().Format("20060102")
+				}
+			}
+			if coupon.EndTimestamp > 0 {
+				end := time.UnixMilli(coupon.EndTimestamp)
+				report.End = &end
+				if report.To == "" {
+					report.To = end.Local().Format("20060102")
+				}
+			}
+			if report.Days, err = couponSendsByDay(account, couponID, report.From, report.To); err != nil {
+				return err
+			}
+			for _, d := range report.Days {
+				report.Sends += d.Sends
+				report.Recipients += d.Recipients
+				report.Broadcasts += d.Broadcasts
+			}
+
+			if csvPath != "" {
+				if err := writeCouponDaysCSV(csvPath, report.Days); err != nil {
+					return err
+				}
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+
+			w := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(w, "Coupon:     %s (%s)\n", report.Title, report.CouponID)
+			if report.Status != "" {
+				_, _ = fmt.Fprintf(w, "Status:     %s\n", colorStatus(w, report.Status))
+			}
+			_, _ = fmt.Fprintf(w, "Valid:      %s to %s", couponTime(coupon.StartTimestamp), couponTime(coupon.EndTimestamp))
+			if report.Window != "" {
+				_, _ = fmt.Fprintf(w, " (%s)", report.Window)
+			}
+			_, _ = fmt.Fprintln(w)
+			_, _ = fmt.Fprintf(w, "Range:      %s to %s\n", getDefault(report.From, "-"), getDefault(report.To, "-"))
+			_, _ = fmt.Fprintf(w, "Sends:      %d (%d recipient(s), %d broadcast(s))\n", report.Sends, report.Recipients, report.Broadcasts)
+			if len(report.Days) > 0 {
+				_, _ = fmt.Fprintln(w)
+				table := NewTable("DATE", "SENDS", "RECIPIENTS", "BROADCASTS")
+				for _, d := range report.Days {
+					table.AddRow(d.Date, strconv.Itoa(d.Sends), strconv.Itoa(d.Recipients), strconv.Itoa(d.Broadcasts))
+				}
+				table.Render(w)
+			}
+			if csvPath != "" {
+				_, _ = fmt.Fprintf(w, "Wrote %d day(s) to %s\n", len(report.Days), csvPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&couponID, "id", "", "Coupon ID (required)")
+	cmd.Flags().StringVar(&from, "from", "", "Start date as YYYYMMDD, YYYY-MM-DD, or a day like -30d (default: the coupon's start)")
+	cmd.Flags().StringVar(&to, "to", "", "End date as YYYYMMDD, YYYY-MM-DD, or a day like yesterday (default: the coupon's end)")
+	cmd.Flags().StringVar(&csvPath, "csv", "", "Also write the daily counts to this CSV file")
+	_ = cmd.MarkFlagRequired("id")
+
+	return cmd
+}
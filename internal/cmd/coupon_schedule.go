@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+// parseWithin parses an --expiring-within value: a Go duration such as
+// 36h, or a whole number of days such as 7d.
+func parseWithin(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid --expiring-within %q: use a duration like 7d or 36h", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --expiring-within %q: use a duration like 7d or 36h", s)
+	}
+	return d, nil
+}
+
+// couponExpiring reports whether a coupon that is not closed ends between
+// now and now+within. Coupons without an end time never expire.
+func couponExpiring(c api.Coupon, now time.Time, within time.Duration) bool {
+	if c.Status == "CLOSED" || c.EndTimestamp == 0 {
+		return false
+	}
+	end := time.UnixMilli(c.EndTimestamp)
+	return !end.Before(now) && !end.After(now.Add(within))
+}
+
+// pendingCouponCloses returns the pending or failed close jobs of a coupon,
+// or of every coupon if couponID is empty.
+func pendingCouponCloses(couponID string) ([]schedule.Job, error) {
+	return listScheduledJobs(func(job schedule.Job) bool {
+		return job.Type == schedule.TypeCouponClose && (couponID == "" || job.CouponID == couponID)
+	})
+}
+
+func newCouponScheduleCmd() *cobra.Command {
+	return newCouponScheduleCmdWithClient(nil)
+}
+
+func newCouponScheduleCmdWithClient(client *api.Client) *cobra.Command {
+	var couponID string
+	var closeAt string
+
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Close a coupon at a later time",
+		Long: `Queue a coupon to be closed at a later time, so a finished promotion
+does not stay open because someone forgot it.
+
+Like scheduled messages, close jobs are kept in the config directory and
+run by 'line scheduler run', which must be running (or run from cron with
+--once) when the job is due. A job runs with the account that was active
+when it was scheduled. A coupon can have one close scheduled at a time.
+
+Coupons open at the start time they were created with; the API cannot open
+one later, so only closing is scheduled. Use 'line coupon list
+--expiring-within 7d' to find coupons about to end.`,
+		Example: `  # Close a coupon at the end of the sale, Tokyo time
+  line coupon schedule --id coupon-xxx --close-at "2024-07-31T23:59+09:00"
+
+  # Close a coupon a week from now
+  line coupon schedule --id coupon-xxx --close-at +7d
+
+  # Review and cancel scheduled closes
+  line coupon schedule list
+  line coupon schedule cancel --id coupon-xxx`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if couponID == "" {
+				return fmt.Errorf("--id is required")
+			}
+			if closeAt == "" {
+				return fmt.Errorf("--close-at is required")
+			}
+			when, err := parseFlagTime("--close-at", closeAt)
+			if err != nil {
+				return err
+			}
+			if !when.After(clockNow()) {
+				return fmt.Errorf("--close-at %s is in the past", when.Format(time.RFC3339))
+			}
+
+			existing, err := pendingCouponCloses(couponID)
+			if err != nil {
+				return fmt.Errorf("failed to list scheduled closes: %w", err)
+			}
+			if len(existing) > 0 {
+				return fmt.Errorf("coupon %s already has a close scheduled for %s (job %s); cancel it first with 'line coupon schedule cancel --id %s'",
+					couponID, existing[0].At.Local().Format(time.RFC3339), existing[0].ID, couponID)
+			}
+
+			account, err := requireAccount(&flags)
+			if err != nil {
+				return err
+			}
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+			coupon, err := c.GetCoupon(cmd.Context(), couponID)
+			if err != nil {
+				return fmt.Errorf("failed to get coupon: %w", err)
+			}
+			if coupon.Status == "CLOSED" {
+				return fmt.Errorf("coupon %s is already closed", couponID)
+			}
+			if coupon.EndTimestamp > 0 && when.After(time.UnixMilli(coupon.EndTimestamp)) {
+				warnf(cmd.ErrOrStderr(), "coupon %s ends at %s, before --close-at", couponID, time.UnixMilli(coupon.EndTimestamp).Local().Format(time.RFC3339))
+			}
+
+			store, err := schedule.Open()
+			if err != nil {
+				return err
+			}
+			job := schedule.Job{
+				ID:        strings.ReplaceAll(newUUID(), "-", "")[:12],
+				Account:   account,
+				Type:      schedule.TypeCouponClose,
+				CouponID:  couponID,
+				At:        when,
+				CreatedAt: clockNow().UTC(),
+				Status:    schedule.StatusPending,
+			}
+			if err := store.Add(job); err != nil {
+				return fmt.Errorf("failed to schedule coupon close: %w", err)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(job)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Scheduled %s: close coupon %s (%s) at %s\n", job.ID, couponID, coupon.Title, when.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&couponID, "id", "", "Coupon ID (required)")
+	cmd.Flags().StringVar(&closeAt, "close-at", "", "When to close, e.g. 2024-07-31T23:59+09:00 or +7d (required)")
+
+	cmd.AddCommand(newCouponScheduleListCmd())
+	cmd.AddCommand(newCouponScheduleCancelCmd())
+
+	return cmd
+}
+
+func newCouponScheduleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled coupon closes",
+		Long: `List the coupon closes waiting for 'line scheduler run', soonest first.
+Failed closes are kept, with their error, until they are cancelled.`,
+		Example: `  line coupon schedule list --output table`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := pendingCouponCloses("")
+			if err != nil {
+				return fmt.Errorf("failed to list scheduled closes: %w", err)
+			}
+			return printScheduledJobs(cmd, jobs, "scheduled coupon close")
+		},
+	}
+}
+
+func newCouponScheduleCancelCmd() *cobra.Command {
+	var couponID string
+
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel the scheduled close of a coupon",
+		Long: `Remove the pending or failed close job of a coupon. A close that
+'line scheduler run' is already making cannot be cancelled.`,
+		Example: `  line coupon schedule cancel --id coupon-xxx`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if couponID == "" {
+				return fmt.Errorf("--id is required")
+			}
+			jobs, err := pendingCouponCloses(couponID)
+			if err != nil {
+				return fmt.Errorf("failed to list scheduled closes: %w", err)
+			}
+			store, err := schedule.Open()
+			if err != nil {
+				return err
+			}
+			var cancelled []string
+			for _, job := range jobs {
+				if job.Status == schedule.StatusSending {
+					continue
+				}
+				if _, err := store.Cancel(job.ID); err != nil {
+					return fmt.Errorf("failed to cancel scheduled close: %w", err)
+				}
+				cancelled = append(cancelled, job.ID)
+			}
+			if len(cancelled) == 0 {
+				return fmt.Errorf("no pending close for coupon %s (see 'line coupon schedule list')", couponID)
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{"couponId": couponID, "cancelled": cancelled}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancelled the scheduled close of coupon %s\n", couponID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&couponID, "id", "", "Coupon ID (required)")
+
+	return cmd
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+)
+
+func TestParseWithin(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"week", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWithin(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseWithin(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCouponListCmd_ExpiringWithin(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	oldNow, oldOutput := clockNow, flags.Output
+	defer func() { clockNow, flags.Output = oldNow, oldOutput }()
+	clockNow = func() time.Time { return now }
+	flags.Output = "text"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+			{"couponId": "soon", "title": "Ends soon", "status": "RUNNING", "endTimestamp": now.Add(3 * 24 * time.Hour).UnixMilli()},
+			{"couponId": "later", "title": "Ends later", "status": "RUNNING", "endTimestamp": now.Add(30 * 24 * time.Hour).UnixMilli()},
+			{"couponId": "closed", "title": "Closed", "status": "CLOSED", "endTimestamp": now.Add(24 * time.Hour).UnixMilli()},
+			{"couponId": "ended", "title": "Ended", "status": "RUNNING", "endTimestamp": now.Add(-time.Hour).UnixMilli()},
+		}})
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var out bytes.Buffer
+	cmd := newCouponListCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--expiring-within", "7d"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "soon  Ends soon [RUNNING] ends in 3 days") {
+		t.Errorf("expected the expiring coupon, got %q", out.String())
+	}
+	for _, id := range []string{"later", "closed", "ended"} {
+		if strings.Contains(out.String(), "  "+id+"  ") {
+			t.Errorf("did not expect coupon %s, got %q", id, out.String())
+		}
+	}
+
+	cmd = newCouponListCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--expiring-within", "soon"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --expiring-within") {
+		t.Errorf("expected invalid --expiring-within error, got %v", err)
+	}
+}
+
+func TestCouponScheduleCmd_ScheduleListAndCancel(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	store := setupSchedule(t, now)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"couponId": "coupon-001", "title": "Summer Sale", "status": "RUNNING",
+			"endTimestamp": now.Add(60 * 24 * time.Hour).UnixMilli(),
+		})
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var out bytes.Buffer
+	cmd := newCouponScheduleCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--id", "coupon-001", "--close-at", "2024-07-31T23:59+09:00"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "close coupon coupon-001 (Summer Sale) at 2024-07-31T23:59:00+09:00") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	jobs, err := store.List()
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %v, %v", jobs, err)
+	}
+	if job := jobs[0]; job.Type != schedule.TypeCouponClose || job.CouponID != "coupon-001" || job.Account != "shop" {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	cmd = newCouponScheduleCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--id", "coupon-001", "--close-at", "2024-08-01T00:00+09:00"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "already has a close scheduled") {
+		t.Errorf("expected duplicate close error, got %v", err)
+	}
+
+	out.Reset()
+	cmd = newCouponScheduleCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Target:   close coupon coupon-001") || strings.Contains(out.String(), "Messages:") {
+		t.Errorf("unexpected list output %q", out.String())
+	}
+
+	cmd = newCouponScheduleCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"cancel", "--id", "coupon-001"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jobs, _ := store.List(); len(jobs) != 0 {
+		t.Errorf("expected the close to be cancelled, got %+v", jobs)
+	}
+}
+
+func TestCouponScheduleCmd_Validation(t *testing.T) {
+	setupSchedule(t, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"missing id", []string{"--close-at", "2024-07-31T23:59+09:00"}, "--id is required"},
+		{"missing time", []string{"--id", "coupon-001"}, "--close-at is required"},
+		{"invalid time", []string{"--id", "coupon-001", "--close-at", "someday"}, "invalid --close-at"},
+		{"past", []string{"--id", "coupon-001", "--close-at", "2024-06-01T00:00Z"}, "in the past"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newCouponScheduleCmdWithClient(nil)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchedulerRunCmd_ClosesDueCoupons(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	store := setupSchedule(t, now)
+	if err := store.Add(schedule.Job{ID: "close", Type: schedule.TypeCouponClose, CouponID: "coupon-001", At: now.Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var out bytes.Buffer
+	cmd := newSchedulerRunCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--once"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method != http.MethodPut || path != "/v2/bot/coupon/coupon-001/close" {
+		t.Errorf("expected the coupon to be closed, got %s %s", method, path)
+	}
+	if !strings.Contains(out.String(), "close: done close coupon coupon-001") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	if jobs, _ := store.List(); len(jobs) != 0 {
+		t.Errorf("expected the job to be removed, got %+v", jobs)
+	}
+}
@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
 )
 
 func TestCouponCmd_RequiresSubcommand(t *testing.T) {
@@ -39,7 +43,7 @@ func TestCouponCmd_HasSubcommands(t *testing.T) {
 		names[subcmd.Name()] = true
 	}
 
-	expected := []string{"list", "create", "get", "close"}
+	expected := []string{"list", "create", "get", "close", "report"}
 	for _, name := range expected {
 		if !names[name] {
 			t.Errorf("expected '%s' subcommand", name)
@@ -362,6 +366,133 @@ func TestCouponGetCmd_APIError(t *testing.T) {
 	}
 }
 
+// seedCouponHistory records sends of coupon-001 and another coupon for
+// the "shop" account.
+func seedCouponHistory(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	day := time.Date(2026, 1, 10, 9, 0, 0, 0, time.Local)
+	coupon := json.RawMessage(`[{"type":"text","text":"Sale"},{"type":"coupon","couponId":"coupon-001"}]`)
+	for _, e := range []ledger.Entry{
+		{Time: day, Account: "shop", Type: "multicast", Recipients: 120, Messages: coupon},
+		{Time: day.Add(3 * time.Hour), Account: "shop", Type: "broadcast", Messages: coupon},
+		{Time: day.AddDate(0, 0, 2), Account: "shop", Type: "push", Recipients: 1, Messages: coupon},
+		{Time: day.AddDate(0, 1, 0), Account: "shop", Type: "push", Recipients: 1, Messages: coupon},
+		{Time: day, Account: "shop", Type: "push", Recipients: 1, Messages: json.RawMessage(`[{"type":"coupon","couponId":"coupon-002"}]`)},
+		{Time: day, Account: "other", Type: "push", Recipients: 1, Messages: coupon},
+	} {
+		if err := ledger.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newCouponTestClient(t *testing.T) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/coupon/coupon-001" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"couponId":"coupon-001","title":"Spring Sale","status":"RUNNING","startTimestamp":1767225600000,"endTimestamp":1769817600000}`))
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestCouponGetCmd_UsageLog(t *testing.T) {
+	seedCouponHistory(t)
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.Output = "shop", "text"
+
+	path := filepath.Join(t.TempDir(), "usage.csv")
+	cmd := newCouponGetCmdWithClient(newCouponTestClient(t))
+	cmd.SetArgs([]string{"--id", "coupon-001", "--usage-log", path})
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date,sends,recipients,broadcasts\n2026-01-10,2,120,1\n2026-01-12,1,1,0\n2026-02-10,1,1,0\n"
+	if string(data) != want {
+		t.Errorf("usage log = %q, want %q", data, want)
+	}
+	if !strings.Contains(errOut.String(), "3 day(s)") || !strings.Contains(out.String(), "Spring Sale") {
+		t.Errorf("unexpected output: %s / %s", out.String(), errOut.String())
+	}
+}
+
+func TestCouponReportCmd(t *testing.T) {
+	seedCouponHistory(t)
+	oldFlags := flags
+	oldNow := clockNow
+	defer func() { flags, clockNow = oldFlags, oldNow }()
+	flags.Account, flags.Output = "shop", "text"
+	clockNow = func() time.Time { return time.Date(2026, 1, 20, 12, 0, 0, 0, time.Local) }
+
+	t.Run("validity window", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.csv")
+		cmd := newCouponReportCmdWithClient(newCouponTestClient(t))
+		cmd.SetArgs([]string{"--id", "coupon-001", "--csv", path})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, want := range []string{"Spring Sale (coupon-001)", "Sends:      3 (121 recipient(s), 1 broadcast(s))", "2026-01-10", "2026-01-12", "Wrote 2 day(s)"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("expected %q in output, got: %s", want, out.String())
+			}
+		}
+		if strings.Contains(out.String(), "2026-02-10") {
+			t.Errorf("send after the coupon's end reported: %s", out.String())
+		}
+		if data, err := os.ReadFile(path); err != nil || strings.Count(string(data), "\n") != 3 {
+			t.Errorf("unexpected CSV %q, %v", data, err)
+		}
+	})
+
+	t.Run("json range", func(t *testing.T) {
+		flags.Output = "json"
+		defer func() { flags.Output = "text" }()
+		cmd := newCouponReportCmdWithClient(newCouponTestClient(t))
+		cmd.SetArgs([]string{"--id", "coupon-001", "--from", "2026-01-11", "--to", "20260301"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var report couponReport
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.From != "20260111" || report.To != "20260301" || report.Sends != 2 || len(report.Days) != 2 {
+			t.Errorf("unexpected report: %+v", report)
+		}
+	})
+}
+
+func TestCouponReportCmd_InvalidRange(t *testing.T) {
+	cmd := newCouponReportCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetArgs([]string{"--id", "coupon-001", "--from", "20260201", "--to", "20260101"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--from (20260201) must not be after --to (20260101)") {
+		t.Errorf("expected range error, got: %v", err)
+	}
+}
+
 func TestCouponCloseCmd_Execute(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if strings.Contains(r.URL.Path, "/close") && r.Method == http.MethodPut {
@@ -486,11 +617,48 @@ func TestCouponListCmd_WithPagination(t *testing.T) {
 	}
 
 	output := out.String()
-	if !strings.Contains(output, "More coupons available") {
+	if !strings.Contains(output, "Next cursor: cursor-abc123") {
 		t.Errorf("expected pagination message, got: %s", output)
 	}
 }
 
+func TestCouponListCmd_StartCursor(t *testing.T) {
+	var gotStart string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotStart = r.URL.Query().Get("start")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"items": []map[string]any{
+				{"couponId": "coupon-002", "title": "Coupon 2"},
+			},
+			"next": "cursor-def456",
+		})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newCouponListCmdWithClient(client)
+	cmd.SetArgs([]string{"--start", "cursor-abc123"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotStart != "cursor-abc123" {
+		t.Errorf("expected start=cursor-abc123, got %q", gotStart)
+	}
+	if !strings.Contains(out.String(), `"next": "cursor-def456"`) {
+		t.Errorf("expected next cursor in JSON output, got: %s", out.String())
+	}
+}
+
 // Additional status filter tests
 func TestCouponListCmd_StatusDraft(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1136,3 +1304,153 @@ func TestCouponCreateCmd_APIError(t *testing.T) {
 		t.Errorf("error should mention 'failed to create coupon', got: %v", err)
 	}
 }
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(30 * time.Second), "now"},
+		{now.Add(45 * time.Minute), "in 45 minutes"},
+		{now.Add(-time.Hour), "1 hour ago"},
+		{now.Add(3*24*time.Hour + 2*time.Hour), "in 3 days"},
+		{now.Add(-24 * time.Hour), "1 day ago"},
+	}
+	for _, tt := range tests {
+		if got := relativeTime(tt.t, now); got != tt.want {
+			t.Errorf("relativeTime(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestCouponWindow(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(d time.Duration) int64 { return now.Add(d).UnixMilli() }
+	tests := []struct {
+		coupon api.Coupon
+		want   string
+	}{
+		{api.Coupon{Status: "DRAFT", StartTimestamp: ms(48 * time.Hour), EndTimestamp: ms(96 * time.Hour)}, "starts in 2 days"},
+		{api.Coupon{Status: "RUNNING", StartTimestamp: ms(-time.Hour), EndTimestamp: ms(72 * time.Hour)}, "ends in 3 days"},
+		{api.Coupon{Status: "RUNNING", EndTimestamp: ms(-5 * time.Hour)}, "ended 5 hours ago"},
+		{api.Coupon{Status: "CLOSED", EndTimestamp: ms(24 * time.Hour)}, "closed"},
+		{api.Coupon{Status: "RUNNING"}, ""},
+	}
+	for _, tt := range tests {
+		if got := couponWindow(tt.coupon, now); got != tt.want {
+			t.Errorf("couponWindow(%+v) = %q, want %q", tt.coupon, got, tt.want)
+		}
+	}
+}
+
+func TestCouponListCmd_SortAndFilterTitle(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	oldNow, oldOutput := clockNow, flags.Output
+	defer func() { clockNow, flags.Output = oldNow, oldOutput }()
+	clockNow = func() time.Time { return now }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{
+			{"couponId": "c1", "title": "Summer Late", "status": "RUNNING", "endTimestamp": now.Add(10 * 24 * time.Hour).UnixMilli()},
+			{"couponId": "c2", "title": "Winter", "status": "RUNNING", "endTimestamp": now.Add(24 * time.Hour).UnixMilli()},
+			{"couponId": "c3", "title": "summer early", "status": "DRAFT", "startTimestamp": now.Add(48 * time.Hour).UnixMilli(), "endTimestamp": now.Add(3 * 24 * time.Hour).UnixMilli()},
+		}})
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	flags.Output = "json"
+	var out bytes.Buffer
+	cmd := newCouponListCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--filter-title", "SUMMER", "--sort", "end"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var resp api.CouponListResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	var ids []string
+	for _, c := range resp.Coupons {
+		ids = append(ids, c.CouponID)
+	}
+	if strings.Join(ids, ",") != "c3,c1" {
+		t.Errorf("expected c3,c1, got %v", ids)
+	}
+
+	flags.Output = "table"
+	out.Reset()
+	cmd = newCouponListCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--sort", "title"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"WINDOW", "starts in 2 days", "ends in 1 day", "ends in 10 days"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in table, got %q", want, out.String())
+		}
+	}
+	if strings.Index(out.String(), "summer early") > strings.Index(out.String(), "Winter") {
+		t.Errorf("expected coupons sorted by title, got %q", out.String())
+	}
+
+	cmd = newCouponListCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--sort", "price"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid sort") {
+		t.Errorf("expected invalid sort error, got %v", err)
+	}
+}
+
+func TestCouponCreateCmd_HumanTimes(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("Asia/Tokyo timezone data not available")
+	}
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, tokyo)
+	oldNow, oldOutput := clockNow, flags.Output
+	defer func() { clockNow, flags.Output = oldNow, oldOutput }()
+	clockNow = func() time.Time { return now }
+	flags.Output = "text"
+
+	var got api.CreateCouponRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(map[string]string{"couponId": "coupon-123"})
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newCouponCreateCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{
+		"--title", "Flash Sale",
+		"--start", "tomorrow 09:00",
+		"--end", "+8d",
+		"--timezone", "Asia/Tokyo",
+		"--max-use", "1",
+		"--visibility", "PUBLIC",
+		"--acquisition", "normal",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 7, 1, 9, 0, 0, 0, tokyo).UnixMilli(); got.StartTimestamp != want {
+		t.Errorf("expected start %d, got %d", want, got.StartTimestamp)
+	}
+	if want := now.AddDate(0, 0, 8).UnixMilli(); got.EndTimestamp != want {
+		t.Errorf("expected end %d, got %d", want, got.EndTimestamp)
+	}
+
+	cmd = newCouponCreateCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--title", "T", "--start", "soon", "--end", "+8d", "--max-use", "1", "--visibility", "PUBLIC", "--acquisition", "normal"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), `invalid --start "soon"`) {
+		t.Errorf("expected invalid --start error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
+)
+
+// couponDay counts one local day's sends of a coupon message, taken from
+// the message history. The Messaging API exposes no redemption records,
+// so these are the coupon counts the CLI can report.
+type couponDay struct {
+	Date  string `json:"date"`
+	Sends int    `json:"sends"`
+	// Recipients is the number of users named by pushes and multicasts
+	Recipients int `json:"recipients"`
+	// Broadcasts counts broadcasts and narrowcasts, which reach followers
+	// the history cannot count
+	Broadcasts int `json:"broadcasts"`
+}
+
+// couponSendsByDay counts the sends by account of messages carrying the
+// coupon, by local day, oldest first. from and to are YYYYMMDD days as
+// returned by resolveDateRange and may be empty.
+func couponSendsByDay(account, couponID, from, to string) ([]couponDay, error) {
+	entries, err := ledger.ForAccount(account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message history: %w", err)
+	}
+	byDay := map[string]*couponDay{}
+	for _, e := range entries {
+		day := e.Time.In(time.Local).Format("20060102")
+		if (from != "" && day < from) || (to != "" && day > to) || !carriesCoupon(e.Messages, couponID) {
+			continue
+		}
+		d := byDay[day]
+		if d == nil {
+			d = &couponDay{Date: e.Time.In(time.Local).Format(time.DateOnly)}
+			byDay[day] = d
+		}
+		d.Sends++
+		if e.Recipients == 0 {
+			d.Broadcasts++
+		}
+		d.Recipients += e.Recipients
+	}
+
+	days := make([]couponDay, 0, len(byDay))
+	for _, d := range byDay {
+		days = append(days, *d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days, nil
+}
+
+// carriesCoupon reports whether a sent message list includes a coupon
+// message for couponID.
+func carriesCoupon(messages json.RawMessage, couponID string) bool {
+	var list []struct {
+		Type     string `json:"type"`
+		CouponID string `json:"couponId"`
+	}
+	if err := json.Unmarshal(messages, &list); err != nil {
+		return false
+	}
+	for _, m := range list {
+		if m.Type == "coupon" && m.CouponID == couponID {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCouponDaysCSV writes the daily counts to path as CSV.
+func writeCouponDaysCSV(path string, days []couponDay) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	w := csv.NewWriter(file)
+	_ = w.Write([]string{"date", "sends", "recipients", "broadcasts"})
+	for _, d := range days {
+		_ = w.Write([]string{d.Date, strconv.Itoa(d.Sends), strconv.Itoa(d.Recipients), strconv.Itoa(d.Broadcasts)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// dashboardBroadcastDays is how many recent days of broadcast deliveries
+// the dashboard shows.
+const dashboardBroadcastDays = 3
+
+func newDashboardCmd() *cobra.Command {
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:         "dashboard",
+		Short:       "Interactive terminal dashboard of account status",
+		Annotations: map[string]string{noPagerAnnotation: ""},
+		Long: `Show a full-screen dashboard with quota consumption, follower counts,
+recent broadcast deliveries, the default rich menu, and the webhook
+endpoint, refreshed every --interval.
+
+Press r to refresh at once and q (or Esc, Ctrl+C) to quit. For output that
+can be piped or logged, use 'line insight overview --dashboard' instead.`,
+		Example: `  line dashboard
+  line dashboard --interval 1m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.Output == "json" {
+				return withExitCode(ExitUsage, fmt.Errorf("the dashboard has no JSON output: use 'line insight overview --dashboard --output json'"))
+			}
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			if !inputIsTerminal(os.Stdin) || !inputIsTerminal(os.Stdout) {
+				return withExitCode(ExitUsage, fmt.Errorf("the dashboard needs an interactive terminal: use 'line insight overview' instead"))
+			}
+
+			c, err := newAPIClient()
+			if err != nil {
+				return err
+			}
+
+			title := "LINE Official Account dashboard"
+			if info, err := c.GetBotInfo(cmd.Context()); err == nil && info.DisplayName != "" {
+				title = fmt.Sprintf("%s (%s)", info.DisplayName, info.BasicID)
+			}
+
+			return tui.Run(cmd.Context(), tui.Options{
+				Title:    title,
+				Interval: interval,
+				In:       os.Stdin,
+				Out:      os.Stdout,
+				Now:      clockNow,
+			}, func(ctx context.Context) []tui.Pane {
+				return fetchDashboard(ctx, c, clockNow())
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "Refresh interval")
+
+	return cmd
+}
+
+// fetchDashboard fetches every dashboard pane in parallel. A pane that
+// cannot be fetched carries its error instead of its lines.
+func fetchDashboard(ctx context.Context, c *api.Client, now time.Time) []tui.Pane {
+	panes := []tui.Pane{
+		{Title: "Message quota"},
+		{Title: "Followers"},
+		{Title: "Recent broadcasts"},
+		{Title: "Default rich menu"},
+		{Title: "Webhook"},
+	}
+
+	_ = runConcurrently(ctx,
+		func(ctx context.Context) error {
+			panes[0].Lines, panes[0].Err = dashboardQuota(ctx, c)
+			return nil
+		},
+		func(ctx context.Context) error {
+			panes[1].Lines, panes[1].Err = dashboardFollowers(ctx, c, now)
+			return nil
+		},
+		func(ctx context.Context) error {
+			panes[2].Lines, panes[2].Err = dashboardBroadcasts(ctx, c, now)
+			return nil
+		},
+		func(ctx context.Context) error {
+			panes[3].Lines, panes[3].Err = dashboardRichMenu(ctx, c)
+			return nil
+		},
+		func(ctx context.Context) error {
+			panes[4].Lines, panes[4].Err = dashboardWebhook(ctx, c)
+			return nil
+		},
+	)
+	return panes
+}
+
+func dashboardQuota(ctx context.Context, c *api.Client) ([]string, error) {
+	quota, err := fetchOverviewQuota(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if quota.Type != "limited" {
+		return []string{fmt.Sprintf("Used %d this month (unlimited)", quota.Used)}, nil
+	}
+	if quota.Limit <= 0 {
+		return []string{fmt.Sprintf("Used %d of 0 this month", quota.Used)}, nil
+	}
+	used := float64(quota.Used) / float64(quota.Limit)
+	return []string{
+		fmt.Sprintf("Used %d of %d this month (%.1f%%)", quota.Used, quota.Limit, used*100),
+		tui.Bar(used, 30),
+		fmt.Sprintf("%d remaining", max(0, quota.Limit-quota.Used)),
+	}, nil
+}
+
+func dashboardFollowers(ctx context.Context, c *api.Client, now time.Time) ([]string, error) {
+	// Insights lag a day
+	date := now.AddDate(0, 0, -1)
+	f, err := fetchOverviewFollowers(ctx, c, date.Format("20060102"))
+	if err != nil {
+		return nil, err
+	}
+	if f.Status != "ready" {
+		return []string{fmt.Sprintf("%s: not ready (%s)", date.Format("2006-01-02"), f.Status)}, nil
+	}
+	return []string{
+		fmt.Sprintf("Followers:        %d", f.Followers),
+		fmt.Sprintf("Targeted reaches: %d", f.TargetedReaches),
+		fmt.Sprintf("Blocks:           %d", f.Blocks),
+		fmt.Sprintf("As of %s", date.Format("2006-01-02")),
+	}, nil
+}
+
+func dashboardBroadcasts(ctx context.Context, c *api.Client, now time.Time) ([]string, error) {
+	lines := make([]string, 0, dashboardBroadcastDays)
+	for i := 1; i <= dashboardBroadcastDays; i++ {
+		date := now.AddDate(0, 0, -i)
+		stats, err := c.GetBroadcastMessageStats(ctx, date.Format("20060102"))
+		if err != nil {
+			return nil, err
+		}
+		line := fmt.Sprintf("%s  not ready (%s)", date.Format("2006-01-02"), stats.Status)
+		if stats.Status == "ready" {
+			line = fmt.Sprintf("%s  %d delivered", date.Format("2006-01-02"), stats.Success)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func dashboardRichMenu(ctx context.Context, c *api.Client) ([]string, error) {
+	id, err := c.GetDefaultRichMenuID(ctx)
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return []string{"None set"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	menu, err := c.GetRichMenu(ctx, id)
+	if err != nil {
+		return []string{id}, nil
+	}
+	return []string{
+		menu.Name,
+		id,
+		fmt.Sprintf("Chat bar: %q, %d areas", menu.ChatBarText, len(menu.Areas)),
+	}, nil
+}
+
+func dashboardWebhook(ctx context.Context, c *api.Client) ([]string, error) {
+	info, err := c.GetWebhookEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info.Endpoint == "" {
+		return []string{"No endpoint set"}, nil
+	}
+	state := "inactive"
+	if info.Active {
+		state = "active"
+	}
+	return []string{info.Endpoint, "Status: " + state}, nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestFetchDashboard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/message/quota":
+			_, _ = w.Write([]byte(`{"type":"limited","value":1000}`))
+		case "/v2/bot/message/quota/consumption":
+			_, _ = w.Write([]byte(`{"totalUsage":250}`))
+		case "/v2/bot/insight/followers":
+			_, _ = w.Write([]byte(`{"status":"ready","followers":1200,"targetedReaches":1000,"blocks":30}`))
+		case "/v2/bot/message/delivery/broadcast":
+			if r.URL.Query().Get("date") == "20260303" {
+				_, _ = w.Write([]byte(`{"status":"ready","success":900}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"unready"}`))
+		case "/v2/bot/user/all/richmenu":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"no default richmenu"}`))
+		case "/v2/bot/channel/webhook/endpoint":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Authentication failed"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	panes := fetchDashboard(context.Background(), client, time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC))
+	if len(panes) != 5 {
+		t.Fatalf("expected 5 panes, got %d", len(panes))
+	}
+	byTitle := map[string]string{}
+	for _, p := range panes {
+		text := strings.Join(p.Lines, "\n")
+		if p.Err != nil {
+			text = "error: " + p.Err.Error()
+		}
+		byTitle[p.Title] = text
+	}
+
+	want := map[string]string{
+		"Message quota":     "Used 250 of 1000 this month (25.0%)",
+		"Followers":         "Followers:        1200",
+		"Recent broadcasts": "2026-03-03  900 delivered\n2026-03-02  not ready (unready)",
+		"Default rich menu": "None set",
+		"Webhook":           "error:",
+	}
+	for title, s := range want {
+		if !strings.Contains(byTitle[title], s) {
+			t.Errorf("%s pane: expected %q in %q", title, s, byTitle[title])
+		}
+	}
+}
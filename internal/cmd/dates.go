@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the absolute formats accepted by time and date flags.
+// Values without an offset are in the flag's location, usually the local
+// timezone. Date-only layouts are the start of that day.
+var timeLayouts = []struct {
+	layout   string
+	local    bool
+	dateOnly bool
+}{
+	{time.RFC3339, false, false},
+	{"2006-01-02T15:04Z07:00", false, false},
+	{"2006-01-02T15:04:05", true, false},
+	{"2006-01-02T15:04", true, false},
+	{"2006-01-02 15:04:05", true, false},
+	{"2006-01-02 15:04", true, false},
+	{time.DateOnly, true, true},
+	{"20060102", true, true},
+}
+
+// dayKeywords are the days that can start a relative time, such as
+// "tomorrow 09:00", as offsets from today.
+var dayKeywords = map[string]int{"yesterday": -1, "today": 0, "tomorrow": 1}
+
+// errTimeFormat is returned by parseTime for a value in no known format.
+var errTimeFormat = errors.New("unknown time format")
+
+// parseTime parses the value of a time or date flag. It accepts:
+//
+//   - RFC 3339 and the shorter forms 2024-07-01T09:00+09:00,
+//     2024-07-01T09:00, and 2024-07-01 09:00
+//   - dates such as 2024-07-01 or 20240701
+//   - now, today, tomorrow, and yesterday, optionally with a time of day
+//     such as "tomorrow 09:00"
+//   - offsets from now such as +7d, -2w, +36h, or -90m
+//   - Unix timestamps in milliseconds
+//
+// Values without an offset are in loc. dateOnly reports a value that
+// names a day without a time, which parseTime returns as its start.
+func parseTime(s string, now time.Time, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	s = strings.TrimSpace(s)
+	now = now.In(loc)
+
+	if ms, ok := parseUnixMilli(s); ok {
+		return time.UnixMilli(ms).In(loc), false, nil
+	}
+	for _, l := range timeLayouts {
+		if l.local {
+			t, err = time.ParseInLocation(l.layout, s, loc)
+		} else {
+			t, err = time.Parse(l.layout, s)
+		}
+		if err == nil {
+			return t, l.dateOnly, nil
+		}
+	}
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		if t, ok := addOffset(now, s); ok {
+			return t, false, nil
+		}
+		return time.Time{}, false, errTimeFormat
+	}
+
+	day, clock, _ := strings.Cut(strings.ToLower(s), " ")
+	if day == "now" && clock == "" {
+		return now, false, nil
+	}
+	offset, ok := dayKeywords[day]
+	if !ok {
+		return time.Time{}, false, errTimeFormat
+	}
+	y, m, d := now.Date()
+	t = time.Date(y, m, d+offset, 0, 0, 0, 0, loc)
+	clock = strings.TrimSpace(clock)
+	if clock == "" {
+		return t, true, nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if c, err := time.Parse(layout, clock); err == nil {
+			return time.Date(y, m, d+offset, c.Hour(), c.Minute(), c.Second(), 0, loc), false, nil
+		}
+	}
+	return time.Time{}, false, errTimeFormat
+}
+
+// parseUnixMilli parses a Unix timestamp in milliseconds. It needs at
+// least 12 digits, so YYYYMMDD dates are not taken for timestamps.
+func parseUnixMilli(s string) (int64, bool) {
+	if len(s) < 12 {
+		return 0, false
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	return ms, err == nil && ms > 0
+}
+
+// addOffset adds a signed offset such as +7d, -2w, or +1h30m to now.
+// Days and weeks are calendar days, so they keep the time of day across
+// daylight saving changes.
+func addOffset(now time.Time, s string) (time.Time, bool) {
+	sign, body := 1, s[1:]
+	if s[0] == '-' {
+		sign = -1
+	}
+	days := 0
+	switch {
+	case strings.HasSuffix(body, "d"):
+		days = 1
+	case strings.HasSuffix(body, "w"):
+		days = 7
+	}
+	if days > 0 {
+		n, err := strconv.Atoi(body[:len(body)-1])
+		if err != nil || n < 0 {
+			return time.Time{}, false
+		}
+		return now.AddDate(0, 0, sign*n*days), true
+	}
+	d, err := time.ParseDuration(body)
+	if err != nil || d < 0 {
+		return time.Time{}, false
+	}
+	return now.Add(time.Duration(sign) * d), true
+}
+
+// parseFlagTime parses the value of a time flag such as --at in the local
+// timezone; see parseTime for the accepted formats.
+func parseFlagTime(flag, s string) (time.Time, error) {
+	t, _, err := parseTime(s, clockNow(), time.Local)
+	if err != nil {
+		return time.Time{}, timeFlagError(flag, s)
+	}
+	return t, nil
+}
+
+// timeFlagError is the usage error for a time flag value that does not
+// parse.
+func timeFlagError(flag, s string) error {
+	return withExitCode(ExitUsage, fmt.Errorf(`invalid %s %q: use a time like 2024-07-01T09:00+09:00, 2024-07-01 09:00, "tomorrow 09:00", or +7d`, flag, s))
+}
+
+// parseFlagDay parses the value of a date flag such as --from into the
+// YYYYMMDD form the insight APIs take. Besides YYYYMMDD it accepts the
+// formats of parseTime, such as 2025-01-01, yesterday, or -7d, taking
+// the local day they fall on.
+func parseFlagDay(flag, s string) (string, error) {
+	if len(s) == 8 && strings.Trim(s, "0123456789") == "" {
+		if _, err := time.Parse("20060102", s); err != nil {
+			return "", fmt.Errorf("invalid %s date: must be in YYYYMMDD format", flag)
+		}
+		return s, nil
+	}
+	t, _, err := parseTime(s, clockNow(), time.Local)
+	if err != nil {
+		return "", fmt.Errorf("%s must be in YYYYMMDD format (e.g., 20250101), or a day like 2025-01-01, yesterday, or -7d", flag)
+	}
+	return t.Format("20060102"), nil
+}
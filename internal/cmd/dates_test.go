@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 7, 1, 15, 30, 0, 0, tokyo)
+	tests := []struct {
+		in       string
+		want     time.Time
+		dateOnly bool
+	}{
+		{"2024-07-01T09:00:00+09:00", time.Date(2024, 7, 1, 9, 0, 0, 0, tokyo), false},
+		{"2024-07-01T09:00+09:00", time.Date(2024, 7, 1, 9, 0, 0, 0, tokyo), false},
+		{"2024-07-01T00:00Z", time.Date(2024, 7, 1, 9, 0, 0, 0, tokyo), false},
+		{"2024-07-01 09:00", time.Date(2024, 7, 1, 9, 0, 0, 0, tokyo), false},
+		{"2024-07-01", time.Date(2024, 7, 1, 0, 0, 0, 0, tokyo), true},
+		{"20240701", time.Date(2024, 7, 1, 0, 0, 0, 0, tokyo), true},
+		{"now", now, false},
+		{"today", time.Date(2024, 7, 1, 0, 0, 0, 0, tokyo), true},
+		{"tomorrow 09:00", time.Date(2024, 7, 2, 9, 0, 0, 0, tokyo), false},
+		{"Yesterday", time.Date(2024, 6, 30, 0, 0, 0, 0, tokyo), true},
+		{"+7d", now.AddDate(0, 0, 7), false},
+		{"-2w", now.AddDate(0, 0, -14), false},
+		{"+36h", now.Add(36 * time.Hour), false},
+		{"-1h30m", now.Add(-90 * time.Minute), false},
+		{"1719792000000", time.Date(2024, 7, 1, 9, 0, 0, 0, tokyo), false},
+	}
+	for _, tt := range tests {
+		got, dateOnly, err := parseTime(tt.in, now, tokyo)
+		if err != nil || !got.Equal(tt.want) || dateOnly != tt.dateOnly {
+			t.Errorf("parseTime(%q) = %v, %v, %v; want %v, %v", tt.in, got, dateOnly, err, tt.want, tt.dateOnly)
+		}
+	}
+
+	for _, in := range []string{"", "someday", "tomorrow at nine", "+7x", "7d", "2024-13-01", "2026011"} {
+		if _, _, err := parseTime(in, now, tokyo); err == nil {
+			t.Errorf("parseTime(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseFlagTime(t *testing.T) {
+	if got, err := parseFlagTime("--at", "2024-07-01 09:00"); err != nil || got.Location() != time.Local {
+		t.Errorf("expected local time, got %v, %v", got, err)
+	}
+	_, err := parseFlagTime("--at", "someday")
+	if err == nil || !strings.Contains(err.Error(), `invalid --at "someday"`) || ExitCode(err) != ExitUsage {
+		t.Errorf("expected a usage error for an unparseable time, got %v", err)
+	}
+}
+
+func TestParseFlagDay(t *testing.T) {
+	oldNow := clockNow
+	defer func() { clockNow = oldNow }()
+	clockNow = func() time.Time { return time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr string
+	}{
+		{in: "20260101", want: "20260101"},
+		{in: "2026-01-15", want: "20260115"},
+		{in: "yesterday", want: "20260309"},
+		{in: "-7d", want: "20260303"},
+		{in: "20261340", wantErr: "invalid --from date"},
+		{in: "2026011", wantErr: "--from must be in YYYYMMDD"},
+	}
+	for _, tt := range tests {
+		got, err := parseFlagDay("--from", tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseFlagDay(%q): expected error containing %q, got %v", tt.in, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseFlagDay(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
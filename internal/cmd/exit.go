@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, so scripts can branch on the class of failure.
+const (
+	ExitOK          = 0
+	ExitError       = 1 // any other failure
+	ExitUsage       = 2 // bad flags or arguments
+	ExitAuth        = 3 // missing or rejected credentials
+	ExitNotFound    = 4
+	ExitRateLimited = 5
+	ExitServer      = 6 // LINE API 5xx
+)
+
+// errorCodes names the exit codes in JSON error output.
+var errorCodes = map[int]string{
+	ExitError:       "error",
+	ExitUsage:       "usage",
+	ExitAuth:        "auth",
+	ExitNotFound:    "not_found",
+	ExitRateLimited: "rate_limited",
+	ExitServer:      "server_error",
+}
+
+// exitError attaches an exit code to an error that is not an API error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// withExitCode marks err to exit with code.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// cobraUsagePrefixes start the errors cobra returns for unknown commands
+// and missing required flags, which are not typed.
+var cobraUsagePrefixes = []string{
+	"unknown command ",
+	"required flag(s) ",
+	"if any flags in the group ",
+	"at least one of the flags in the group ",
+}
+
+// ExitCode returns the exit code for an error returned by Execute or
+// ExecuteContext.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.IsUnauthorized(), apiErr.IsForbidden():
+			return ExitAuth
+		case apiErr.IsNotFound():
+			return ExitNotFound
+		case apiErr.IsRateLimited():
+			return ExitRateLimited
+		case apiErr.IsServerError():
+			return ExitServer
+		}
+		return ExitError
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	for _, prefix := range cobraUsagePrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return ExitUsage
+		}
+	}
+	return ExitError
+}
+
+// markUsageErrors makes flag parsing and argument validation errors of cmd
+// and its subcommands exit with ExitUsage.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return withExitCode(ExitUsage, err)
+	})
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if args := c.Args; args != nil {
+			c.Args = func(c *cobra.Command, a []string) error {
+				return withExitCode(ExitUsage, args(c, a))
+			}
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(cmd)
+}
+
+// reportError writes err to w: as a JSON envelope with --output json or
+// ndjson, otherwise as "Error: ..." like cobra, with a pointer to --help
+// for usage errors.
+func reportError(w io.Writer, cmd *cobra.Command, err error) {
+	code := ExitCode(err)
+	if flags.Output == "json" || flags.Output == "ndjson" {
+		type errorBody struct {
+			Code       string `json:"code"`
+			ExitCode   int    `json:"exitCode"`
+			Message    string `json:"message"`
+			StatusCode int    `json:"statusCode,omitempty"`
+			RequestID  string `json:"requestId,omitempty"`
+			// RetriedRequestIDs are earlier attempts of the request
+			RetriedRequestIDs []string `json:"retriedRequestIds,omitempty"`
+		}
+		body := errorBody{Code: errorCodes[code], ExitCode: code, Message: err.Error()}
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) {
+			body.StatusCode = apiErr.StatusCode
+			body.RequestID = apiErr.RequestID
+			body.RetriedRequestIDs = apiErr.RetriedRequestIDs
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string]errorBody{"error": body})
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Error: %v\n", err)
+	if code == ExitUsage && cmd != nil {
+		_, _ = fmt.Fprintf(w, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+}
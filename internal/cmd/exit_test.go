@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestExitCode(t *testing.T) {
+	apiErr := func(status int) error {
+		return fmt.Errorf("failed to get bot info: %w", api.ParseAPIError(status, "GET", "/v2/bot/info", nil))
+	}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain", errors.New("boom"), ExitError},
+		{"unauthorized", apiErr(401), ExitAuth},
+		{"forbidden", apiErr(403), ExitAuth},
+		{"not found", apiErr(404), ExitNotFound},
+		{"rate limited", apiErr(429), ExitRateLimited},
+		{"server error", apiErr(503), ExitServer},
+		{"bad request", apiErr(400), ExitError},
+		{"marked", fmt.Errorf("wrapped: %w", withExitCode(ExitAuth, errors.New("no accounts"))), ExitAuth},
+		{"unknown command", errors.New(`unknown command "nope" for "line"`), ExitUsage},
+		{"required flag", errors.New(`required flag(s) "to" not set`), ExitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode_UsageErrors(t *testing.T) {
+	writeTestConfig(t, "")
+
+	for _, args := range [][]string{
+		{"config", "--nope"},
+		{"config", "get"},
+		{"config", "set", "a", "b", "c"},
+		{"nope"},
+	} {
+		_, err := runConfigTestCmd(t, args...)
+		if got := ExitCode(err); got != ExitUsage {
+			t.Errorf("%v: ExitCode(%v) = %d, want %d", args, err, got, ExitUsage)
+		}
+	}
+
+	// Errors from a command's own checks are not usage errors
+	_, err := runConfigTestCmd(t, "config", "get", "timezone")
+	if got := ExitCode(err); got != ExitError {
+		t.Errorf("ExitCode(%v) = %d, want %d", err, got, ExitError)
+	}
+}
+
+func TestReportError_JSON(t *testing.T) {
+	old := flags.Output
+	t.Cleanup(func() { flags.Output = old })
+	flags.Output = "json"
+
+	apiErr := api.ParseAPIError(429, "POST", "/v2/bot/message/push", []byte(`{"message":"Too many requests"}`))
+	apiErr.RequestID = "req-123"
+
+	var buf bytes.Buffer
+	reportError(&buf, nil, fmt.Errorf("failed to push message: %w", apiErr))
+
+	var out struct {
+		Error struct {
+			Code       string `json:"code"`
+			ExitCode   int    `json:"exitCode"`
+			Message    string `json:"message"`
+			StatusCode int    `json:"statusCode"`
+			RequestID  string `json:"requestId"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	e := out.Error
+	if e.Code != "rate_limited" || e.ExitCode != ExitRateLimited || e.StatusCode != 429 || e.RequestID != "req-123" {
+		t.Errorf("unexpected envelope: %+v", e)
+	}
+	if !strings.HasPrefix(e.Message, "failed to push message: API Error: 429") {
+		t.Errorf("message = %q", e.Message)
+	}
+}
+
+func TestReportError_Text(t *testing.T) {
+	old := flags.Output
+	t.Cleanup(func() { flags.Output = old })
+	cmd := NewRootCmd()
+	config, _, _ := cmd.Find([]string{"config"})
+	flags.Output = "text"
+
+	var buf bytes.Buffer
+	reportError(&buf, config, withExitCode(ExitUsage, errors.New("unknown flag: --nope")))
+	if want := "Error: unknown flag: --nope\nRun 'line config --help' for usage.\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	reportError(&buf, config, errors.New("boom"))
+	if buf.String() != "Error: boom\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
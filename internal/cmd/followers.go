@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/followersnapshot"
+	"github.com/spf13/cobra"
+)
+
+func newFollowersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "followers",
+		Short: "Track follower churn with snapshots",
+		Long: `Take snapshots of the account's follower IDs and report the users
+gained and lost between them.
+
+Snapshots are stored locally per account. Listing followers needs the
+follower IDs feature, which LINE offers only to verified and premium
+accounts.`,
+	}
+
+	cmd.AddCommand(newFollowersSnapshotCmd())
+	cmd.AddCommand(newFollowersSnapshotsCmd())
+	cmd.AddCommand(newFollowersChurnCmd())
+
+	return cmd
+}
+
+func newFollowersSnapshotCmd() *cobra.Command {
+	return newFollowersSnapshotCmdWithClient(nil)
+}
+
+func newFollowersSnapshotCmdWithClient(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Store the current follower IDs",
+		Long: `Fetch every follower ID and store them as a snapshot, named by the
+time it was taken. Snapshot files hold one user ID per line, so they can
+also be used as users files.`,
+		Example: `  line followers snapshot
+
+  # Take a snapshot every day, e.g. from cron
+  0 9 * * * line followers snapshot --account prod`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.DryRun {
+				return withExitCode(ExitUsage, fmt.Errorf("snapshots cannot be taken with --dry-run"))
+			}
+
+			c := client
+			account := flags.Account
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+				account, _ = requireAccount(&flags)
+			}
+			if account == "" {
+				return fmt.Errorf("snapshots are stored per account; use --account")
+			}
+
+			fetch := func(ctx context.Context, cursor string, size int) ([]string, string, error) {
+				resp, err := c.GetFollowerIDs(ctx, cursor, size)
+				if err != nil {
+					return nil, "", fmt.Errorf("failed to get followers: %w", err)
+				}
+				return resp.UserIDs, resp.Next, nil
+			}
+			ids, _, err := api.Paginate(cmd.Context(), api.PageOptions{All: true, PageSize: 1000}, fetch)
+			if err != nil {
+				return err
+			}
+
+			snap, err := followersnapshot.Save(account, clockNow(), ids)
+			if err != nil {
+				return err
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(snap)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Snapshot %s: %d followers\n", snap.ID, snap.Followers)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newFollowersSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "List stored follower snapshots",
+		Example: `  line followers snapshots
+  line followers snapshots --output json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account, err := requireAccount(&flags)
+			if err != nil {
+				return err
+			}
+			snaps, err := followersnapshot.List(account)
+			if err != nil {
+				return err
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(snaps)
+			}
+
+			if len(snaps) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No snapshots. Run: line followers snapshot")
+				return nil
+			}
+			table := NewTable("ID", "TAKEN", "FOLLOWERS")
+			for _, s := range snaps {
+				table.AddRow(s.ID, s.TakenAt.Local().Format("2006-01-02 15:04"), fmt.Sprintf("%d", s.Followers))
+			}
+			table.Render(cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// followerChurn is the change between two follower snapshots.
+type followerChurn struct {
+	From   followersnapshot.Snapshot `json:"from"`
+	To     followersnapshot.Snapshot `json:"to"`
+	Gained int                       `json:"gained"`
+	Lost   int                       `json:"lost"`
+	Net    int                       `json:"net"`
+}
+
+func newFollowersChurnCmd() *cobra.Command {
+	var since string
+	var until string
+	var gainedOut string
+	var lostOut string
+
+	cmd := &cobra.Command{
+		Use:   "churn",
+		Short: "Report followers gained and lost between snapshots",
+		Long: `Compare two follower snapshots and count the users gained and lost.
+
+--since and --until name a snapshot by ID, "latest", or a date
+(2026-03-01) or RFC 3339 time for the last snapshot taken by then. --until
+defaults to the latest snapshot. Use --gained-out and --lost-out to write
+the user IDs, one per line.`,
+		Example: `  line followers churn --since 2026-03-01
+  line followers churn --since 20260301T090000Z --until 20260308T090000Z
+
+  # Export who left, e.g. for a win-back audience
+  line followers churn --since 2026-03-01 --lost-out lost.txt`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account, err := requireAccount(&flags)
+			if err != nil {
+				return err
+			}
+			from, err := followersnapshot.Find(account, since)
+			if err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+			to, err := followersnapshot.Find(account, until)
+			if err != nil {
+				return fmt.Errorf("--until: %w", err)
+			}
+			if !to.TakenAt.After(from.TakenAt) {
+				return withExitCode(ExitUsage, fmt.Errorf("--since snapshot %s must be older than --until snapshot %s", from.ID, to.ID))
+			}
+
+			outputs := map[string]*usersOutput{}
+			for name, path := range map[string]string{"gained": gainedOut, "lost": lostOut} {
+				if path == "" {
+					continue
+				}
+				out, err := createUsersOutput(cmd, path)
+				if err != nil {
+					return err
+				}
+				defer out.abort()
+				outputs[name] = out
+			}
+
+			churn := followerChurn{From: from, To: to}
+			err = followersnapshot.Compare(from, to,
+				func(id string) error {
+					churn.Gained++
+					if out := outputs["gained"]; out != nil {
+						return out.write(id)
+					}
+					return nil
+				},
+				func(id string) error {
+					churn.Lost++
+					if out := outputs["lost"]; out != nil {
+						return out.write(id)
+					}
+					return nil
+				})
+			if err != nil {
+				return err
+			}
+			for _, out := range outputs {
+				if err := out.close(); err != nil {
+					return err
+				}
+			}
+			churn.Net = churn.Gained - churn.Lost
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(churn)
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "From:   %s (%d followers)\n", from.ID, from.Followers)
+			_, _ = fmt.Fprintf(out, "To:     %s (%d followers)\n", to.ID, to.Followers)
+			_, _ = fmt.Fprintf(out, "Gained: %d\n", churn.Gained)
+			_, _ = fmt.Fprintf(out, "Lost:   %d\n", churn.Lost)
+			_, _ = fmt.Fprintf(out, "Net:    %+d\n", churn.Net)
+			if gainedOut != "" {
+				_, _ = fmt.Fprintf(out, "Wrote %d gained IDs to %s\n", churn.Gained, gainedOut)
+			}
+			if lostOut != "" {
+				_, _ = fmt.Fprintf(out, "Wrote %d lost IDs to %s\n", churn.Lost, lostOut)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Snapshot to compare from: ID, latest, or date (required)")
+	cmd.Flags().StringVar(&until, "until", "latest", "Snapshot to compare to: ID, latest, or date")
+	cmd.Flags().StringVar(&gainedOut, "gained-out", "", "Write the gained user IDs to this file")
+	cmd.Flags().StringVar(&lostOut, "lost-out", "", "Write the lost user IDs to this file")
+	_ = cmd.MarkFlagRequired("since")
+
+	return cmd
+}
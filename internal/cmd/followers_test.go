@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/followersnapshot"
+)
+
+// followersClient returns a client whose followers are *ids, served two
+// per page.
+func followersClient(t *testing.T, ids *[]string) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/followers/ids" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		end := min(start+2, len(*ids))
+		resp := map[string]any{"userIds": (*ids)[start:end]}
+		if end < len(*ids) {
+			resp["next"] = strconv.Itoa(end)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestFollowersSnapshotAndChurn(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	oldFlags, oldNow := flags, clockNow
+	defer func() { flags, clockNow = oldFlags, oldNow }()
+	flags.Account, flags.Output = "prod", "text"
+
+	ids := []string{testUserID(3), testUserID(1), testUserID(2)}
+	client := followersClient(t, &ids)
+	snapshot := func(at time.Time) string {
+		t.Helper()
+		clockNow = func() time.Time { return at }
+		cmd := newFollowersSnapshotCmdWithClient(client)
+		cmd.SetArgs(nil)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out.String()
+	}
+
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if out := snapshot(first); !strings.Contains(out, "Snapshot 20260301T090000Z: 3 followers") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	ids = []string{testUserID(2), testUserID(4), testUserID(5), testUserID(1)}
+	snapshot(first.Add(7 * 24 * time.Hour))
+
+	if snaps, _ := followersnapshot.List("prod"); len(snaps) != 2 || snaps[1].Followers != 4 {
+		t.Fatalf("unexpected snapshots: %+v", snaps)
+	}
+
+	dir := t.TempDir()
+	gainedPath, lostPath := filepath.Join(dir, "gained.txt"), filepath.Join(dir, "lost.txt")
+	cmd := newFollowersChurnCmd()
+	cmd.SetArgs([]string{"--since", "2026-03-01", "--gained-out", gainedPath, "--lost-out", lostPath})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"From:   20260301T090000Z (3 followers)",
+		"To:     20260308T090000Z (4 followers)",
+		"Gained: 2",
+		"Lost:   1",
+		"Net:    +1",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, out.String())
+		}
+	}
+	if got, _ := os.ReadFile(gainedPath); string(got) != testUserID(4)+"\n"+testUserID(5)+"\n" {
+		t.Errorf("unexpected gained IDs: %q", got)
+	}
+	if got, _ := os.ReadFile(lostPath); string(got) != testUserID(3)+"\n" {
+		t.Errorf("unexpected lost IDs: %q", got)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		flags.Output = "json"
+		defer func() { flags.Output = "text" }()
+
+		cmd := newFollowersChurnCmd()
+		cmd.SetArgs([]string{"--since", "20260301T090000Z", "--until", "latest"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var churn followerChurn
+		if err := json.Unmarshal(out.Bytes(), &churn); err != nil {
+			t.Fatalf("expected valid JSON, got: %s", out.String())
+		}
+		if churn.Gained != 2 || churn.Lost != 1 || churn.Net != 1 || churn.To.ID != "20260308T090000Z" {
+			t.Errorf("unexpected churn: %+v", churn)
+		}
+	})
+
+	t.Run("same snapshot", func(t *testing.T) {
+		cmd := newFollowersChurnCmd()
+		cmd.SetArgs([]string{"--since", "latest"})
+		cmd.SetOut(new(bytes.Buffer))
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "must be older") {
+			t.Errorf("expected an ordering error, got %v", err)
+		}
+	})
+}
+
+func TestFollowersSnapshotCmd_RefusesDryRun(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.DryRun = "prod", true
+
+	cmd := newFollowersSnapshotCmdWithClient(api.NewClient("test-token", false, true))
+	cmd.SetArgs(nil)
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--dry-run") {
+		t.Errorf("expected a --dry-run error, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// addFreezeOverrideFlag registers --override-freeze on a send command.
+func addFreezeOverrideFlag(cmd *cobra.Command, reason *string) {
+	cmd.Flags().StringVar(reason, "override-freeze", "", "Send during an active freeze window; requires a reason (recorded in the audit log)")
+}
+
+// enforceFreeze blocks a send of the given type while a configured freeze
+// window is active. When overrideReason is set the send is allowed and the
+// override is recorded in the audit log.
+func enforceFreeze(cmd *cobra.Command, sendType, overrideReason string) error {
+	if cmd.Flags().Changed("override-freeze") && strings.TrimSpace(overrideReason) == "" {
+		return fmt.Errorf("--override-freeze requires a reason")
+	}
+	if cfg == nil || cfg.Freeze.IsZero() {
+		return nil
+	}
+
+	active, err := activeFreeze(cfg.Freeze, sendType, clockNow())
+	if err != nil {
+		return fmt.Errorf("failed to evaluate freeze windows: %w", err)
+	}
+	if active == "" {
+		return nil
+	}
+
+	if overrideReason == "" {
+		return fmt.Errorf("%s is blocked by freeze window (%s); use --override-freeze \"reason\" to send anyway", sendType, active)
+	}
+
+	if err := audit.Append(audit.Entry{
+		Time:    clockNow().UTC(),
+		Account: flags.Account,
+		Action:  "freeze.override",
+		Reason:  strings.TrimSpace(overrideReason),
+		Details: map[string]any{
+			"send":    sendType,
+			"window":  active,
+			"command": cmd.CommandPath(),
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to record freeze override: %w", err)
+	}
+	warnf(cmd.ErrOrStderr(), "overriding freeze window (%s): %s", active, overrideReason)
+	return nil
+}
+
+// activeFreeze returns a description of the freeze rule that applies to
+// sendType at now, or an empty string if sending is allowed.
+func activeFreeze(fc config.FreezeConfig, sendType string, now time.Time) (string, error) {
+	loc := time.Local
+	if fc.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(fc.Timezone)
+		if err != nil {
+			return "", fmt.Errorf("invalid timezone %q: %w", fc.Timezone, err)
+		}
+	}
+	now = now.In(loc)
+
+	if fc.HolidaysFile != "" {
+		holidays, err := readHolidays(fc.HolidaysFile)
+		if err != nil {
+			return "", err
+		}
+		today := now.Format("2006-01-02")
+		if holidays[today] {
+			return "holiday " + today, nil
+		}
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	weekday := shortWeekday(now.Weekday())
+	yesterday := shortWeekday((now.Weekday() + 6) % 7)
+
+	for _, w := range fc.Windows {
+		if len(w.Sends) > 0 && !containsFold(w.Sends, sendType) {
+			continue
+		}
+
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return "", err
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return "", err
+		}
+
+		// Days name the day a window starts on, so the part of a window
+		// that wraps past midnight belongs to the day before
+		startsOn := func(day string) bool { return len(w.Days) == 0 || containsFold(w.Days, day) }
+		var inWindow bool
+		if start <= end {
+			inWindow = minute >= start && minute < end && startsOn(weekday)
+		} else {
+			inWindow = (minute >= start && startsOn(weekday)) || (minute < end && startsOn(yesterday))
+		}
+		if inWindow {
+			return fmt.Sprintf("%s-%s %s", w.Start, w.End, loc), nil
+		}
+	}
+
+	return "", nil
+}
+
+// shortWeekday returns the three-letter lowercase name of d, as used in
+// freeze window days.
+func shortWeekday(d time.Weekday) string {
+	return strings.ToLower(d.String()[:3])
+}
+
+// parseClock parses an HH:MM time of day into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid freeze time %q: use HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// readHolidays reads a holiday file with one YYYY-MM-DD date per line.
+// Blank lines and lines starting with # are ignored.
+func readHolidays(path string) (map[string]bool, error) {
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	holidays := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Allow trailing comments such as "2026-01-01 New Year's Day"
+		date := strings.Fields(line)[0]
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q: use YYYY-MM-DD", date)
+		}
+		holidays[date] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), s) {
+			return true
+		}
+	}
+	return false
+}
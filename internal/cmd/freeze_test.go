@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+func TestActiveFreeze_Windows(t *testing.T) {
+	fc := config.FreezeConfig{
+		Timezone: "Asia/Tokyo",
+		Windows: []config.FreezeWindow{
+			{Start: "22:00", End: "08:00", Sends: []string{"broadcast"}},
+			{Start: "12:00", End: "13:00", Days: []string{"sat"}},
+			{Start: "22:00", End: "08:00", Sends: []string{"multicast"}, Days: []string{"fri"}},
+		},
+	}
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+
+	tests := []struct {
+		name     string
+		sendType string
+		now      time.Time
+		frozen   bool
+	}{
+		{"late night broadcast", "broadcast", time.Date(2026, 3, 4, 23, 30, 0, 0, jst), true},
+		{"early morning broadcast", "broadcast", time.Date(2026, 3, 4, 7, 59, 0, 0, jst), true},
+		{"window end is exclusive", "broadcast", time.Date(2026, 3, 4, 8, 0, 0, 0, jst), false},
+		{"late night push not restricted", "push", time.Date(2026, 3, 4, 23, 30, 0, 0, jst), false},
+		{"saturday lunch", "push", time.Date(2026, 3, 7, 12, 15, 0, 0, jst), true},
+		{"wednesday lunch", "push", time.Date(2026, 3, 4, 12, 15, 0, 0, jst), false},
+		{"friday night multicast", "multicast", time.Date(2026, 3, 6, 23, 0, 0, 0, jst), true},
+		{"friday night wraps into saturday", "multicast", time.Date(2026, 3, 7, 3, 0, 0, 0, jst), true},
+		{"thursday night not restricted", "multicast", time.Date(2026, 3, 6, 3, 0, 0, 0, jst), false},
+		{"saturday night not restricted", "multicast", time.Date(2026, 3, 7, 23, 0, 0, 0, jst), false},
+		{"evaluated in configured timezone", "broadcast", time.Date(2026, 3, 4, 14, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, err := activeFreeze(fc, tt.sendType, tt.now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (active != "") != tt.frozen {
+				t.Errorf("activeFreeze() = %q, want frozen=%v", active, tt.frozen)
+			}
+		})
+	}
+}
+
+func TestActiveFreeze_Holidays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.txt")
+	content := "# Public holidays\n2026-01-01 New Year's Day\n\n2026-05-05\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fc := config.FreezeConfig{Timezone: "UTC", HolidaysFile: path}
+
+	active, err := activeFreeze(fc, "push", time.Date(2026, 5, 5, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active != "holiday 2026-05-05" {
+		t.Errorf("expected holiday freeze, got %q", active)
+	}
+
+	active, err = activeFreeze(fc, "push", time.Date(2026, 5, 6, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active != "" {
+		t.Errorf("expected no freeze, got %q", active)
+	}
+}
+
+func TestActiveFreeze_InvalidConfig(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := activeFreeze(config.FreezeConfig{Timezone: "Nowhere/City"}, "push", now); err == nil {
+		t.Error("expected error for invalid timezone")
+	}
+	bad := config.FreezeConfig{Windows: []config.FreezeWindow{{Start: "25:00", End: "08:00"}}}
+	if _, err := activeFreeze(bad, "push", now); err == nil {
+		t.Error("expected error for invalid window time")
+	}
+}
+
+func TestMessagePushCmd_BlockedByFreeze(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldCfg, oldNow := cfg, clockNow
+	defer func() { cfg, clockNow = oldCfg, oldNow }()
+	cfg = &config.Config{Freeze: config.FreezeConfig{
+		Timezone: "UTC",
+		Windows:  []config.FreezeWindow{{Start: "22:00", End: "08:00"}},
+	}}
+	clockNow = func() time.Time { return time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC) }
+
+	cmd := newMessagePushCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--to", "U123", "--text", "hi"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "blocked by freeze window") {
+		t.Fatalf("expected freeze error, got: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no API requests, got %d", requests)
+	}
+}
+
+func TestMessagePushCmd_OverrideFreezeRecordsAudit(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldCfg, oldNow, oldOutput := cfg, clockNow, flags.Output
+	defer func() { cfg, clockNow, flags.Output = oldCfg, oldNow, oldOutput }()
+	cfg = &config.Config{Freeze: config.FreezeConfig{
+		Timezone: "UTC",
+		Windows:  []config.FreezeWindow{{Start: "22:00", End: "08:00"}},
+	}}
+	clockNow = func() time.Time { return time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC) }
+	flags.Output = "text"
+
+	cmd := newMessagePushCmdWithClient(client)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"--to", "U123", "--text", "hi", "--override-freeze", "incident notice"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "overriding freeze window") {
+		t.Errorf("expected override warning, got: %s", errOut.String())
+	}
+
+	path, err := audit.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected audit log to be written: %v", err)
+	}
+	if !strings.Contains(string(data), `"reason":"incident notice"`) {
+		t.Errorf("audit log missing reason: %s", data)
+	}
+	if !strings.Contains(string(data), `"time":"2026-03-04T23:00:00Z"`) {
+		t.Errorf("audit entry should use the injected clock: %s", data)
+	}
+}
+
+func TestEnforceFreeze_EmptyOverrideReason(t *testing.T) {
+	oldCfg := cfg
+	defer func() { cfg = oldCfg }()
+	cfg = &config.Config{}
+
+	cmd := newMessagePushCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--to", "U123", "--text", "hi", "--override-freeze", " "})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "requires a reason") {
+		t.Errorf("expected reason error, got: %v", err)
+	}
+}
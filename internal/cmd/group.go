@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -76,25 +77,46 @@ func newGroupMembersCmd() *cobra.Command {
 
 func newGroupMembersCmdWithClient(client *api.Client) *cobra.Command {
 	var groupID string
-	var all bool
+	var page pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "members",
 		Short: "List group members",
-		Long:  "Get member count and list of user IDs in a group.",
+		Long: `Get the member count of a group, and with --all, --limit, or --cursor
+the user IDs of its members. When more member IDs are available, text
+output ends with a "Next cursor" line and JSON output includes the "next"
+cursor; pass it back with --cursor to continue.
+
+With --output ndjson, the member IDs (the first page unless --all or
+--limit is given) are written as {"userId": ...} lines as each page arrives,
+without the count.`,
+		Annotations: map[string]string{ndjsonStreamAnnotation: ""},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if groupID == "" {
 				return fmt.Errorf("--id is required")
 			}
+			opts, err := page.options(0, 0)
+			if err != nil {
+				return err
+			}
 
 			c := client
 			if c == nil {
-				var err error
 				c, err = newAPIClient()
 				if err != nil {
 					return err
 				}
 			}
+			fetch := func(ctx context.Context, cursor string, _ int) ([]string, string, error) {
+				resp, err := c.GetGroupMemberIDs(ctx, groupID, cursor)
+				if err != nil {
+					return nil, "", fmt.Errorf("failed to get member IDs: %w", err)
+				}
+				return resp.MemberIDs, resp.Next, nil
+			}
+			if flags.Output == "ndjson" {
+				return streamNDJSON(cmd, opts, fetch, userIDLine)
+			}
 
 			count, err := c.GetGroupMemberCount(cmd.Context(), groupID)
 			if err != nil {
@@ -102,26 +124,22 @@ func newGroupMembersCmdWithClient(client *api.Client) *cobra.Command {
 			}
 
 			var allMemberIDs []string
-			if all {
-				var next string
-				for {
-					resp, err := c.GetGroupMemberIDs(cmd.Context(), groupID, next)
-					if err != nil {
-						return fmt.Errorf("failed to get member IDs: %w", err)
-					}
-					allMemberIDs = append(allMemberIDs, resp.MemberIDs...)
-					if resp.Next == "" {
-						break
-					}
-					next = resp.Next
+			var next string
+			if page.listing() {
+				allMemberIDs, next, err = api.Paginate(cmd.Context(), opts, fetch)
+				if err != nil {
+					return err
 				}
 			}
 
 			if flags.Output == "json" {
 				result := map[string]any{"groupId": groupID, "count": count}
-				if all {
+				if page.listing() {
 					result["memberIds"] = allMemberIDs
 				}
+				if next != "" {
+					result["next"] = next
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
 				return enc.Encode(result)
@@ -129,17 +147,16 @@ func newGroupMembersCmdWithClient(client *api.Client) *cobra.Command {
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Group ID: %s\n", groupID)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Members:  %d\n", count)
-			if all {
-				for _, id := range allMemberIDs {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), id)
-				}
+			for _, id := range allMemberIDs {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), id)
 			}
+			printNextCursor(cmd.OutOrStdout(), next)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&groupID, "id", "", "Group ID (required)")
-	cmd.Flags().BoolVar(&all, "all", false, "List all member IDs")
+	addPageFlags(cmd, &page, "member IDs")
 	_ = cmd.MarkFlagRequired("id")
 
 	return cmd
@@ -1,6 +1,8 @@
 package cmd
 
-import "fmt"
+import (
+	"fmt"
+)
 
 // FlagCheck represents a named boolean condition for flag validation.
 type FlagCheck struct {
@@ -33,3 +35,23 @@ func requireExactlyOneFlag(checks []FlagCheck) error {
 		return fmt.Errorf("specify only one message type, got: %v", setFlags)
 	}
 }
+
+// resolveDateRange parses --from and --to, when set, into YYYYMMDD dates
+// (see parseFlagDay) and checks that from is not after to.
+func resolveDateRange(from, to string) (string, string, error) {
+	var err error
+	if from != "" {
+		if from, err = parseFlagDay("--from", from); err != nil {
+			return "", "", err
+		}
+	}
+	if to != "" {
+		if to, err = parseFlagDay("--to", to); err != nil {
+			return "", "", err
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return "", "", fmt.Errorf("--from (%s) must not be after --to (%s)", from, to)
+	}
+	return from, to, nil
+}
@@ -102,3 +102,35 @@ func TestRequireExactlyOneFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveDateRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr string
+	}{
+		{name: "both empty"},
+		{name: "valid range", from: "20260101", to: "20260131"},
+		{name: "same day", from: "20260101", to: "20260101"},
+		{name: "only from", from: "20260101"},
+		{name: "short from", from: "2026011", wantErr: "--from must be in YYYYMMDD"},
+		{name: "bad to", to: "20261340", wantErr: "invalid --to date"},
+		{name: "reversed", from: "20260201", to: "20260101", wantErr: "must not be after --to"},
+		{name: "reversed dashes", from: "2026-02-01", to: "2026-01-01", wantErr: "--from (20260201) must not be after --to (20260101)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := resolveDateRange(tt.from, tt.to)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
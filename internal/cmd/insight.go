@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
@@ -21,7 +23,10 @@ func newInsightCmd() *cobra.Command {
 	cmd.AddCommand(newInsightMessagesCmd())
 	cmd.AddCommand(newInsightDemographicsCmd())
 	cmd.AddCommand(newInsightEventsCmd())
+	cmd.AddCommand(newInsightMessageDeliveryCmd())
 	cmd.AddCommand(newInsightUnitStatsCmd())
+	cmd.AddCommand(newInsightAggregationCmd())
+	cmd.AddCommand(newInsightOverviewCmd())
 
 	return cmd
 }
@@ -32,6 +37,9 @@ func newInsightFollowersCmd() *cobra.Command {
 
 func newInsightFollowersCmdWithClient(client *api.Client) *cobra.Command {
 	var date string
+	var watch bool
+	var interval time.Duration
+	var count int
 
 	cmd := &cobra.Command{
 		Use:   "followers",
@@ -41,19 +49,16 @@ func newInsightFollowersCmdWithClient(client *api.Client) *cobra.Command {
   line insight followers
 
   # Get stats for a specific date
-  line insight followers --date 20250101`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if date == "" {
-				// Default to yesterday (insight data has 1-day delay)
-				date = time.Now().AddDate(0, 0, -1).Format("20060102")
-			}
+  line insight followers --date 20250101
 
-			// Validate date format
-			if len(date) != 8 {
-				return fmt.Errorf("date must be in YYYYMMDD format (e.g., 20250101)")
-			}
-			if _, err := time.Parse("20060102", date); err != nil {
-				return fmt.Errorf("invalid date: must be in YYYYMMDD format (e.g., 20250101)")
+  # Refresh every 30 seconds until interrupted
+  line insight followers --watch --interval 30s`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if date != "" {
+				var err error
+				if date, err = parseFlagDay("--date", date); err != nil {
+					return err
+				}
 			}
 
 			c := client
@@ -65,40 +70,56 @@ func newInsightFollowersCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
-			stats, err := c.GetFollowerStats(cmd.Context(), date)
-			if err != nil {
-				return fmt.Errorf("failed to get follower stats: %w", err)
-			}
-
-			if flags.Output == "json" {
-				enc := json.NewEncoder(cmd.OutOrStdout())
-				enc.SetIndent("", "  ")
-				return enc.Encode(stats)
-			}
-
-			if stats.Status != nil && *stats.Status == generated.GetNumberOfFollowersResponseStatusReady {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Follower Stats (%s):\n", date)
-				if stats.Followers != nil {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Followers:        %d\n", *stats.Followers)
+			render := func(ctx context.Context, w io.Writer) error {
+				day := date
+				if day == "" {
+					// Default to yesterday (insight data has 1-day delay)
+					day = clockNow().AddDate(0, 0, -1).Format("20060102")
 				}
-				if stats.TargetedReaches != nil {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Targeted Reaches: %d\n", *stats.TargetedReaches)
+
+				stats, err := c.GetFollowerStats(ctx, day)
+				if err != nil {
+					return fmt.Errorf("failed to get follower stats: %w", err)
 				}
-				if stats.Blocks != nil {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Blocks:           %d\n", *stats.Blocks)
+
+				if flags.Output == "json" {
+					enc := json.NewEncoder(w)
+					enc.SetIndent("", "  ")
+					return enc.Encode(stats)
 				}
-			} else {
-				status := "unknown"
-				if stats.Status != nil {
-					status = string(*stats.Status)
+
+				if stats.Status != nil && *stats.Status == generated.GetNumberOfFollowersResponseStatusReady {
+					_, _ = fmt.Fprintf(w, "Follower Stats (%s):\n", day)
+					if stats.Followers != nil {
+						_, _ = fmt.Fprintf(w, "  Followers:        %d\n", *stats.Followers)
+					}
+					if stats.TargetedReaches != nil {
+						_, _ = fmt.Fprintf(w, "  Targeted Reaches: %d\n", *stats.TargetedReaches)
+					}
+					if stats.Blocks != nil {
+						_, _ = fmt.Fprintf(w, "  Blocks:           %d\n", *stats.Blocks)
+					}
+				} else {
+					status := "unknown"
+					if stats.Status != nil {
+						status = string(*stats.Status)
+					}
+					_, _ = fmt.Fprintf(w, "Stats not ready for %s (status: %s)\n", day, status)
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Stats not ready for %s (status: %s)\n", date, status)
+				return nil
 			}
-			return nil
+
+			if watch {
+				return watchOutput(cmd, interval, count, render)
+			}
+			return render(cmd.Context(), cmd.OutOrStdout())
 		},
 	}
 
-	cmd.Flags().StringVar(&date, "date", "", "Date in YYYYMMDD format (default: yesterday)")
+	cmd.Flags().StringVar(&date, "date", "", "Date as YYYYMMDD, YYYY-MM-DD, or a day like -7d (default: yesterday)")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Refresh the stats every --interval until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "Refresh interval with --watch")
+	cmd.Flags().IntVar(&count, "count", 0, "Stop after this many refreshes with --watch (0 = until interrupted)")
 
 	return cmd
 }
@@ -121,15 +142,12 @@ func newInsightMessagesCmdWithClient(client *api.Client) *cobra.Command {
   line insight messages --date 20250101`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if date == "" {
-				date = time.Now().AddDate(0, 0, -1).Format("20060102")
+				date = clockNow().AddDate(0, 0, -1).Format("20060102")
 			}
 
-			// Validate date format
-			if len(date) != 8 {
-				return fmt.Errorf("date must be in YYYYMMDD format (e.g., 20250101)")
-			}
-			if _, err := time.Parse("20060102", date); err != nil {
-				return fmt.Errorf("invalid date: must be in YYYYMMDD format (e.g., 20250101)")
+			var err error
+			if date, err = parseFlagDay("--date", date); err != nil {
+				return err
 			}
 
 			c := client
@@ -209,7 +227,7 @@ func newInsightMessagesCmdWithClient(client *api.Client) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&date, "date", "", "Date in YYYYMMDD format (default: yesterday)")
+	cmd.Flags().StringVar(&date, "date", "", "Date as YYYYMMDD, YYYY-MM-DD, or a day like -7d (default: yesterday)")
 
 	return cmd
 }
@@ -396,63 +414,60 @@ func newInsightUnitStatsCmdWithClient(client *api.Client) *cobra.Command {
 				return fmt.Errorf("--to is required (format: YYYYMMDD)")
 			}
 
-			// Validate date formats
-			if len(from) != 8 {
-				return fmt.Errorf("--from must be in YYYYMMDD format (e.g., 20250101)")
-			}
-			if _, err := time.Parse("20060102", from); err != nil {
-				return fmt.Errorf("invalid --from date: must be in YYYYMMDD format")
-			}
-			if len(to) != 8 {
-				return fmt.Errorf("--to must be in YYYYMMDD format (e.g., 20250101)")
-			}
-			if _, err := time.Parse("20060102", to); err != nil {
-				return fmt.Errorf("invalid --to date: must be in YYYYMMDD format")
-			}
-
-			c := client
-			if c == nil {
-				var err error
-				c, err = newAPIClient()
-				if err != nil {
-					return err
-				}
-			}
-
-			stats, err := c.GetStatisticsPerUnit(cmd.Context(), unit, from, to)
-			if err != nil {
-				return fmt.Errorf("failed to get unit statistics: %w", err)
+			var err error
+			if from, to, err = resolveDateRange(from, to); err != nil {
+				return err
 			}
 
-			if flags.Output == "json" {
-				enc := json.NewEncoder(cmd.OutOrStdout())
-				enc.SetIndent("", "  ")
-				return enc.Encode(stats)
-			}
-
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Statistics for unit '%s' (%s to %s):\n", unit, from, to)
-			if stats.Overview != nil {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Unique Impressions:  %d\n", stats.Overview.UniqueImpression)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Unique Clicks:       %d\n", stats.Overview.UniqueClick)
-				if stats.Overview.UniqueMediaPlayed > 0 {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Unique Media Played: %d\n", stats.Overview.UniqueMediaPlayed)
-				}
-				if stats.Overview.UniqueMediaPlayedComplete > 0 {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Media Played 100%%:   %d\n", stats.Overview.UniqueMediaPlayedComplete)
-				}
-			} else {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  No statistics available for this unit and date range.")
-			}
-			return nil
+			return printUnitStats(cmd, client, unit, from, to)
 		},
 	}
 
 	cmd.Flags().StringVar(&unit, "unit", "", "Custom aggregation unit name (required)")
-	cmd.Flags().StringVar(&from, "from", "", "Start date in YYYYMMDD format (required)")
-	cmd.Flags().StringVar(&to, "to", "", "End date in YYYYMMDD format (required)")
+	cmd.Flags().StringVar(&from, "from", "", "Start date as YYYYMMDD, YYYY-MM-DD, or a day like -7d (required)")
+	cmd.Flags().StringVar(&to, "to", "", "End date as YYYYMMDD, YYYY-MM-DD, or a day like yesterday (required)")
 	_ = cmd.MarkFlagRequired("unit")
 	_ = cmd.MarkFlagRequired("from")
 	_ = cmd.MarkFlagRequired("to")
 
 	return cmd
 }
+
+// printUnitStats prints the insights of the custom aggregation unit unit
+// from from to to (YYYYMMDD).
+func printUnitStats(cmd *cobra.Command, client *api.Client, unit, from, to string) error {
+	c := client
+	if c == nil {
+		var err error
+		c, err = newAPIClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	stats, err := c.GetStatisticsPerUnit(cmd.Context(), unit, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get unit statistics: %w", err)
+	}
+
+	if flags.Output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Statistics for unit '%s' (%s to %s):\n", unit, from, to)
+	if stats.Overview != nil {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Unique Impressions:  %d\n", stats.Overview.UniqueImpression)
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Unique Clicks:       %d\n", stats.Overview.UniqueClick)
+		if stats.Overview.UniqueMediaPlayed > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Unique Media Played: %d\n", stats.Overview.UniqueMediaPlayed)
+		}
+		if stats.Overview.UniqueMediaPlayedComplete > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Media Played 100%%:   %d\n", stats.Overview.UniqueMediaPlayedComplete)
+		}
+	} else {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "  No statistics available for this unit and date range.")
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// aggregationStatsDays is the default span of "insight aggregation stats",
+// the longest LINE allows.
+const aggregationStatsDays = 30
+
+func newInsightAggregationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "aggregation",
+		Short: "Insights per custom aggregation unit",
+		Long: `Segment message insights by custom aggregation unit, such as a campaign
+code. Messages are counted under a unit when sent with
+--aggregation-unit (push and multicast; LINE does not support units for
+broadcast or narrowcast).`,
+	}
+
+	cmd.AddCommand(newInsightAggregationListUnitsCmd())
+	cmd.AddCommand(newInsightAggregationStatsCmd())
+
+	return cmd
+}
+
+func newInsightAggregationListUnitsCmd() *cobra.Command {
+	return newInsightAggregationListUnitsCmdWithClient(nil)
+}
+
+func newInsightAggregationListUnitsCmdWithClient(client *api.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-units",
+		Short: "List the custom aggregation units used this month",
+		Long: `List every custom aggregation unit name used this month, following
+pagination, along with the number used.`,
+		Example: `  line insight aggregation list-units`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			var units []string
+			start := ""
+			for {
+				resp, err := c.GetAggregationUnitNameList(cmd.Context(), 0, start)
+				if err != nil {
+					return fmt.Errorf("failed to get aggregation unit list: %w", err)
+				}
+				units = append(units, resp.CustomAggregationUnits...)
+				if resp.Next == "" {
+					break
+				}
+				start = resp.Next
+			}
+			if units == nil {
+				units = []string{}
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"customAggregationUnits": units, "count": len(units)})
+			}
+
+			if len(units) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No aggregation units used this month")
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Aggregation Units (%d):\n", len(units))
+			for _, unit := range units {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", unit)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newInsightAggregationStatsCmd() *cobra.Command {
+	return newInsightAggregationStatsCmdWithClient(nil)
+}
+
+func newInsightAggregationStatsCmdWithClient(client *api.Client) *cobra.Command {
+	var unit string
+	var from string
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Get statistics for a custom aggregation unit",
+		Long: `Get the impressions, clicks, and media plays of the messages sent under a
+custom aggregation unit. The date range defaults to the last 30 days, the
+longest LINE allows.`,
+		Example: `  # Stats for a campaign over the last 30 days
+  line insight aggregation stats --unit spring_sale
+
+  # Stats for a given range
+  line insight aggregation stats --unit spring_sale --from 20260301 --to 20260331`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if unit == "" {
+				return fmt.Errorf("--unit is required")
+			}
+			if err := validateAggregationUnit(unit); err != nil {
+				return err
+			}
+
+			today := clockNow()
+			if to == "" {
+				to = today.Format("20060102")
+			}
+			var err error
+			if to, err = parseFlagDay("--to", to); err != nil {
+				return err
+			}
+			if from == "" {
+				end := today
+				if t, err := time.ParseInLocation("20060102", to, today.Location()); err == nil {
+					end = t
+				}
+				from = end.AddDate(0, 0, -(aggregationStatsDays - 1)).Format("20060102")
+			}
+			if from, to, err = resolveDateRange(from, to); err != nil {
+				return err
+			}
+
+			return printUnitStats(cmd, client, unit, from, to)
+		},
+	}
+
+	cmd.Flags().StringVar(&unit, "unit", "", "Custom aggregation unit name (required)")
+	cmd.Flags().StringVar(&from, "from", "", "Start date as YYYYMMDD, YYYY-MM-DD, or a day like -7d (default: the 30 days ending --to)")
+	cmd.Flags().StringVar(&to, "to", "", "End date as YYYYMMDD, YYYY-MM-DD, or a day like yesterday (default today)")
+	_ = cmd.MarkFlagRequired("unit")
+
+	return cmd
+}
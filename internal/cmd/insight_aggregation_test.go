@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestInsightAggregationListUnitsCmd_FollowsPages(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := r.URL.Query().Get("start")
+		starts = append(starts, start)
+		resp := map[string]any{"customAggregationUnits": []string{"spring_sale", "summer_sale"}, "next": "page2"}
+		if start == "page2" {
+			resp = map[string]any{"customAggregationUnits": []string{"winter_sale"}}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newInsightAggregationListUnitsCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(starts, ",") != ",page2" {
+		t.Errorf("expected two pages, got starts %q", starts)
+	}
+	var result struct {
+		Units []string `json:"customAggregationUnits"`
+		Count int      `json:"count"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result.Count != 3 || strings.Join(result.Units, ",") != "spring_sale,summer_sale,winter_sale" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestInsightAggregationStatsCmd_DefaultRange(t *testing.T) {
+	oldOutput, oldNow := flags.Output, clockNow
+	defer func() { flags.Output, clockNow = oldOutput, oldNow }()
+	flags.Output = "text"
+	clockNow = func() time.Time { return time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC) }
+
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"overview": map[string]any{"uniqueImpression": 120, "uniqueClick": 30},
+		})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newInsightAggregationStatsCmdWithClient(client)
+	cmd.SetArgs([]string{"--unit", "spring_sale"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if query != "customAggregationUnit=spring_sale&from=20260302&to=20260331" {
+		t.Errorf("unexpected query: %s", query)
+	}
+	if !strings.Contains(out.String(), "Unique Impressions:  120") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestInsightAggregationStatsCmd_InvalidUnit(t *testing.T) {
+	cmd := newInsightAggregationStatsCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetArgs([]string{"--unit", "bad unit"})
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid aggregation unit") {
+		t.Fatalf("expected invalid unit error, got %v", err)
+	}
+}
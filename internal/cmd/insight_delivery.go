@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+// deliveryResult is the exported delivery result of one send.
+type deliveryResult struct {
+	RequestID string `json:"requestId"`
+	// Type, Label, SentAt, and Summary come from the ledger, for sends
+	// recorded there
+	Type    string `json:"type,omitempty"`
+	Label   string `json:"label,omitempty"`
+	SentAt  string `json:"sentAt,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	// Progress is the delivery progress of a narrowcast
+	Progress map[string]any            `json:"progress,omitempty"`
+	Events   *api.MessageEventResponse `json:"events,omitempty"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+func newInsightMessageDeliveryCmd() *cobra.Command {
+	return newInsightMessageDeliveryCmdWithClient(nil)
+}
+
+func newInsightMessageDeliveryCmdWithClient(client *api.Client) *cobra.Command {
+	var requestIDs []string
+	var tracked bool
+	var label string
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "message-delivery",
+		Short: "Export the delivery results of broadcasts and narrowcasts",
+		Long: `Export the delivery results of sent messages: delivered, impression, and
+click counts, and for narrowcasts the delivery progress and success and
+failure counts.
+
+Name sends with --request-id, or export every send recorded with --track
+on "message broadcast" and "message narrowcast" with --tracked (--label
+narrows it to the sends of one --track-label). Tracked sends also carry
+their type, label, and send time into the export.
+
+The results are written as JSON to --out, or printed. LINE publishes event
+statistics a while after a send, and only for sends that reached enough
+users; a send without statistics yet is exported with its error.`,
+		Example: `  # Export one send
+  line insight message-delivery --request-id xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx --out results.json
+
+  # Export every tracked send of a campaign
+  line message broadcast --text "Spring sale!" --track-label spring-sale --yes
+  line insight message-delivery --tracked --label spring-sale --out spring-sale.json
+
+  # Summary of every tracked send
+  line insight message-delivery --tracked`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(requestIDs) > 0) == tracked {
+				return withExitCode(ExitUsage, fmt.Errorf("specify --request-id or --tracked"))
+			}
+			if label != "" && !tracked {
+				return withExitCode(ExitUsage, fmt.Errorf("--label requires --tracked"))
+			}
+
+			c := client
+			account := flags.Account
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+				account, _ = localAccountKey()
+			}
+
+			entries, err := ledger.ForAccount(account)
+			if err != nil {
+				return err
+			}
+			var results []deliveryResult
+			if tracked {
+				for _, e := range entries {
+					if e.Tracked && (label == "" || e.Label == label) {
+						results = append(results, deliveryResultFromEntry(e))
+					}
+				}
+				if len(results) == 0 {
+					if label != "" {
+						return fmt.Errorf("no tracked sends labeled %q for this account", label)
+					}
+					return fmt.Errorf("no tracked sends for this account; send with --track first")
+				}
+			} else {
+				byID := map[string]ledger.Entry{}
+				for _, e := range entries {
+					byID[e.RequestID] = e
+				}
+				for _, id := range requestIDs {
+					if e, ok := byID[id]; ok {
+						results = append(results, deliveryResultFromEntry(e))
+					} else {
+						results = append(results, deliveryResult{RequestID: id})
+					}
+				}
+			}
+
+			failed := 0
+			for i := range results {
+				r := &results[i]
+				if r.Type == "narrowcast" {
+					progress, err := c.GetNarrowcastProgress(cmd.Context(), r.RequestID)
+					if err != nil {
+						r.Error = fmt.Sprintf("failed to get narrowcast progress: %v", err)
+						failed++
+						continue
+					}
+					r.Progress = progress
+				}
+				events, err := c.GetMessageEventStats(cmd.Context(), r.RequestID)
+				if err != nil {
+					r.Error = fmt.Sprintf("failed to get event stats: %v", err)
+					failed++
+					continue
+				}
+				r.Events = events
+			}
+			// A single named send that failed is an error, not an export
+			if !tracked && len(results) == 1 && results[0].Error != "" {
+				return fmt.Errorf("%s", results[0].Error)
+			}
+
+			if outPath != "" {
+				data, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to encode results: %w", err)
+				}
+				if err := os.WriteFile(outPath, append(data, '\n'), 0644); err != nil {
+					return fmt.Errorf("failed to write results: %w", err)
+				}
+			}
+			if err := printDeliveryResults(cmd, results, outPath); err != nil {
+				return err
+			}
+			if failed > 0 {
+				warnf(cmd.ErrOrStderr(), "no results for %d of %d sends", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&requestIDs, "request-id", nil, "Request ID of a send (repeatable)")
+	cmd.Flags().BoolVar(&tracked, "tracked", false, "Export every send recorded with --track for this account")
+	cmd.Flags().StringVar(&label, "label", "", "Only tracked sends with this --track-label")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write the results as JSON to this file")
+
+	return cmd
+}
+
+func deliveryResultFromEntry(e ledger.Entry) deliveryResult {
+	return deliveryResult{
+		RequestID: e.RequestID,
+		Type:      e.Type,
+		Label:     e.Label,
+		SentAt:    e.Time.Format(time.RFC3339),
+		Summary:   e.Summary,
+	}
+}
+
+// printDeliveryResults prints the results, or where they were written.
+func printDeliveryResults(cmd *cobra.Command, results []deliveryResult, outPath string) error {
+	out := cmd.OutOrStdout()
+	if flags.Output == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if outPath != "" {
+			return enc.Encode(map[string]any{"count": len(results), "out": outPath})
+		}
+		return enc.Encode(results)
+	}
+	if outPath != "" {
+		_, _ = fmt.Fprintf(out, "Wrote the delivery results of %d sends to %s\n", len(results), outPath)
+		return nil
+	}
+
+	table := NewTable("REQUEST ID", "TYPE", "LABEL", "SENT", "DELIVERED", "IMPRESSIONS", "CLICKS")
+	for _, r := range results {
+		sent := ""
+		if r.SentAt != "" {
+			sent = r.SentAt[:10]
+		}
+		delivered, impressions, clicks := "-", "-", "-"
+		if r.Events != nil && r.Events.Overview != nil {
+			o := r.Events.Overview
+			delivered = strconv.FormatInt(o.Delivered, 10)
+			impressions = strconv.FormatInt(o.UniqueImpression, 10)
+			clicks = strconv.FormatInt(o.UniqueClick, 10)
+		}
+		table.AddRow(r.RequestID, r.Type, r.Label, sent, delivered, impressions, clicks)
+	}
+	table.Render(out)
+	return nil
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
+)
+
+func TestMessageBroadcastCmd_Track(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	oldYes, oldAccount, oldOutput := flags.Yes, flags.Account, flags.Output
+	defer func() { flags.Yes, flags.Account, flags.Output = oldYes, oldAccount, oldOutput }()
+	flags.Yes, flags.Account, flags.Output = true, "shop", "json"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Line-Request-Id", "req-broadcast")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	recordSends(new(bytes.Buffer), client, "shop")
+
+	cmd := newMessageBroadcastCmdWithClient(client)
+	cmd.SetArgs([]string{"--text", "Spring sale starts today!", "--track-label", "spring"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil || result["requestId"] != "req-broadcast" {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	e, err := ledger.Find("shop", "req-broadcast")
+	if err != nil || e == nil {
+		t.Fatalf("broadcast not tracked: %v", err)
+	}
+	if e.Type != "broadcast" || !e.Tracked || e.Label != "spring" || e.Summary != "Spring sale starts today!" || e.PayloadHash == "" {
+		t.Errorf("unexpected ledger entry: %+v", e)
+	}
+	// The recorded send is marked, not recorded a second time
+	if n, _ := ledger.CountAccount("shop"); n != 1 {
+		t.Errorf("expected 1 ledger entry, got %d", n)
+	}
+}
+
+func TestInsightMessageDeliveryCmd(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	oldAccount, oldOutput := flags.Account, flags.Output
+	defer func() { flags.Account, flags.Output = oldAccount, oldOutput }()
+	flags.Account, flags.Output = "shop", "text"
+
+	for _, e := range []ledger.Entry{
+		{Account: "shop", Type: "broadcast", RequestID: "req-1", Tracked: true, Label: "spring"},
+		{Account: "shop", Type: "narrowcast", RequestID: "req-2", Tracked: true, Label: "spring"},
+		{Account: "shop", Type: "broadcast", RequestID: "req-3", Tracked: true, Label: "summer"},
+		{Account: "shop", Type: "broadcast", RequestID: "req-4"},
+	} {
+		if err := ledger.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/v2/bot/message/progress/narrowcast":
+			_, _ = w.Write([]byte(`{"phase":"succeeded","successCount":90,"failureCount":10}`))
+		case "/v2/bot/insight/message/event":
+			id := r.URL.Query().Get("requestId")
+			_, _ = w.Write([]byte(`{"overview":{"requestId":"` + id + `","delivered":100,"uniqueImpression":80,"uniqueClick":20}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	outPath := filepath.Join(t.TempDir(), "results.json")
+	cmd := newInsightMessageDeliveryCmdWithClient(client)
+	cmd.SetArgs([]string{"--tracked", "--label", "spring", "--out", outPath})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Wrote the delivery results of 2 sends") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	want := "/v2/bot/insight/message/event?requestId=req-1," +
+		"/v2/bot/message/progress/narrowcast?requestId=req-2," +
+		"/v2/bot/insight/message/event?requestId=req-2"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("requests = %s, want %s", got, want)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []deliveryResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatalf("invalid results file: %v", err)
+	}
+	if len(results) != 2 || results[1].Type != "narrowcast" || results[1].Progress["phase"] != "succeeded" || results[1].Events.Overview.Delivered != 100 {
+		t.Errorf("unexpected results: %s", data)
+	}
+
+	// An untracked request ID is looked up as given
+	paths = nil
+	cmd = newInsightMessageDeliveryCmdWithClient(client)
+	cmd.SetArgs([]string{"--request-id", "req-9"})
+	out.Reset()
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 1 || !strings.Contains(out.String(), "req-9") || !strings.Contains(out.String(), "100") {
+		t.Errorf("unexpected lookup: %v\n%s", paths, out.String())
+	}
+}
+
+func TestInsightMessageDeliveryCmd_Flags(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	for _, args := range [][]string{
+		{},
+		{"--request-id", "req-1", "--tracked"},
+		{"--request-id", "req-1", "--label", "spring"},
+	} {
+		cmd := newInsightMessageDeliveryCmdWithClient(api.NewClient("test-token", false, false))
+		cmd.SetArgs(args)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
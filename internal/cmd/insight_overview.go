@@ -0,0 +1,428 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// overviewSnapshot is one refresh of the insight overview. Sections that
+// could not be fetched are nil and their errors are listed in Errors.
+type overviewSnapshot struct {
+	Time       time.Time           `json:"time"`
+	Followers  *overviewFollowers  `json:"followers,omitempty"`
+	Deliveries *overviewDeliveries `json:"deliveries,omitempty"`
+	Quota      *overviewQuota      `json:"quota,omitempty"`
+	Webhook    *webhookStats       `json:"webhook,omitempty"`
+	// Rates derived from earlier snapshots in dashboard mode
+	QuotaBurnPerHour       *float64 `json:"quotaBurnPerHour,omitempty"`
+	WebhookEventsPerMinute *float64 `json:"webhookEventsPerMinute,omitempty"`
+	Errors                 []string `json:"errors,omitempty"`
+}
+
+type overviewFollowers struct {
+	Date            string `json:"date"`
+	Status          string `json:"status"`
+	Followers       int64  `json:"followers"`
+	TargetedReaches int64  `json:"targetedReaches"`
+	Blocks          int64  `json:"blocks"`
+}
+
+type overviewDeliveries struct {
+	Date   string `json:"date"`
+	Status string `json:"status"`
+	Total  int64  `json:"total"`
+}
+
+type overviewQuota struct {
+	// Type is "limited" or "none" (unlimited)
+	Type  string `json:"type"`
+	Limit int    `json:"limit,omitempty"`
+	Used  int    `json:"used"`
+}
+
+func newInsightOverviewCmd() *cobra.Command {
+	return newInsightOverviewCmdWithClient(nil)
+}
+
+func newInsightOverviewCmdWithClient(client *api.Client) *cobra.Command {
+	var dashboard bool
+	var interval time.Duration
+	var webhookStatsURL string
+	var history int
+	var count int
+
+	cmd := &cobra.Command{
+		Use:         "overview",
+		Short:       "Show followers, deliveries, quota, and webhook activity at a glance",
+		Annotations: map[string]string{noPagerAnnotation: ""},
+		Long: `Show follower counts (yesterday, since insights lag a day), today's
+deliveries, monthly quota usage, and optionally the webhook event rate
+reported by a running "line webhook serve".
+
+With --dashboard the view refreshes every --interval and keeps a sparkline
+history of each metric, along with the quota burn rate and webhook events
+per minute. In JSON mode, the dashboard prints one snapshot per line.`,
+		Example: `  # One-off snapshot
+  line insight overview
+
+  # Launch-day wall monitor with webhook event rate
+  line webhook serve --quiet &
+  line insight overview --dashboard --interval 15s --webhook-stats http://localhost:8080/stats`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			if history < 1 {
+				return fmt.Errorf("--history must be at least 1")
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if !dashboard {
+				snap := fetchOverview(cmd.Context(), c, webhookStatsURL, clockNow())
+				if flags.Output == "json" {
+					enc := json.NewEncoder(out)
+					enc.SetIndent("", "  ")
+					return enc.Encode(snap)
+				}
+				renderOverview(out, []overviewSnapshot{snap}, 0)
+				return nil
+			}
+
+			ctx := cmd.Context()
+			var snaps []overviewSnapshot
+			for i := 0; count == 0 || i < count; i++ {
+				if i > 0 {
+					select {
+					case <-ctx.Done():
+						return nil
+					case <-time.After(interval):
+					}
+				}
+
+				snap := fetchOverview(ctx, c, webhookStatsURL, clockNow())
+				if ctx.Err() != nil {
+					return nil
+				}
+				deriveOverviewRates(snaps, &snap)
+				snaps = append(snaps, snap)
+				if len(snaps) > history {
+					snaps = snaps[len(snaps)-history:]
+				}
+
+				if flags.Output == "json" {
+					if err := json.NewEncoder(out).Encode(snap); err != nil {
+						return err
+					}
+					continue
+				}
+				// Clear the screen and redraw from the top-left corner
+				_, _ = fmt.Fprint(out, "\033[H\033[2J")
+				renderOverview(out, snaps, interval)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dashboard, "dashboard", false, "Refresh continuously with sparkline history")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "Refresh interval with --dashboard")
+	cmd.Flags().StringVar(&webhookStatsURL, "webhook-stats", "", "Stats URL of a running 'line webhook serve' (e.g. http://localhost:8080/stats)")
+	cmd.Flags().IntVar(&history, "history", 30, "Number of refreshes kept for sparklines")
+	cmd.Flags().IntVar(&count, "count", 0, "Stop after this many refreshes (0 = until interrupted)")
+
+	return cmd
+}
+
+// fetchOverview collects one snapshot, fetching all sections in parallel.
+// A failing section is recorded in Errors rather than aborting the rest.
+func fetchOverview(ctx context.Context, c *api.Client, webhookStatsURL string, now time.Time) overviewSnapshot {
+	snap := overviewSnapshot{Time: now}
+	yesterday := now.AddDate(0, 0, -1).Format("20060102")
+	today := now.Format("20060102")
+
+	errs := make([]error, 4)
+
+	_ = runConcurrently(ctx,
+		func(ctx context.Context) error {
+			var err error
+			if snap.Followers, err = fetchOverviewFollowers(ctx, c, yesterday); err != nil {
+				errs[0] = fmt.Errorf("followers: %w", err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			stats, err := c.GetMessageDeliveryStats(ctx, today)
+			if err != nil {
+				errs[1] = fmt.Errorf("deliveries: %w", err)
+				return nil
+			}
+			d := &overviewDeliveries{Date: today, Status: "unknown"}
+			if stats.Status != nil {
+				d.Status = string(*stats.Status)
+			}
+			for _, n := range []*int64{
+				stats.Broadcast, stats.Targeting, stats.AutoResponse, stats.WelcomeResponse, stats.Chat,
+				stats.ApiBroadcast, stats.ApiPush, stats.ApiMulticast, stats.ApiNarrowcast, stats.ApiReply,
+			} {
+				d.Total += derefInt64(n)
+			}
+			snap.Deliveries = d
+			return nil
+		},
+		func(ctx context.Context) error {
+			var err error
+			if snap.Quota, err = fetchOverviewQuota(ctx, c); err != nil {
+				errs[2] = err
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			if webhookStatsURL == "" {
+				return nil
+			}
+			stats, err := fetchWebhookStats(ctx, webhookStatsURL)
+			if err != nil {
+				errs[3] = fmt.Errorf("webhook: %w", err)
+				return nil
+			}
+			snap.Webhook = stats
+			return nil
+		},
+	)
+
+	for _, err := range errs {
+		if err != nil {
+			snap.Errors = append(snap.Errors, err.Error())
+		}
+	}
+	return snap
+}
+
+// fetchOverviewFollowers fetches the follower counts for date (YYYYMMDD).
+// The overview and the dashboard both show them.
+func fetchOverviewFollowers(ctx context.Context, c *api.Client, date string) (*overviewFollowers, error) {
+	stats, err := c.GetFollowerStats(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	f := &overviewFollowers{Date: date, Status: "unknown"}
+	if stats.Status != nil {
+		f.Status = string(*stats.Status)
+	}
+	f.Followers = derefInt64(stats.Followers)
+	f.TargetedReaches = derefInt64(stats.TargetedReaches)
+	f.Blocks = derefInt64(stats.Blocks)
+	return f, nil
+}
+
+// fetchOverviewQuota fetches the monthly quota and its consumption in
+// parallel. The overview and the dashboard both show them. Both requests
+// run to the end, so a failure of each is reported.
+func fetchOverviewQuota(ctx context.Context, c *api.Client) (*overviewQuota, error) {
+	var quota *api.QuotaResponse
+	var consumption *api.ConsumptionResponse
+	var quotaErr, consumptionErr error
+	_ = runConcurrently(ctx,
+		func(ctx context.Context) error {
+			if quota, quotaErr = c.GetMessageQuota(ctx); quotaErr != nil {
+				quotaErr = fmt.Errorf("quota: %w", quotaErr)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			if consumption, consumptionErr = c.GetMessageConsumption(ctx); consumptionErr != nil {
+				consumptionErr = fmt.Errorf("quota consumption: %w", consumptionErr)
+			}
+			return nil
+		},
+	)
+	if err := errors.Join(quotaErr, consumptionErr); err != nil {
+		return nil, err
+	}
+	return &overviewQuota{Type: quota.Type, Limit: quota.Value, Used: consumption.TotalUsage}, nil
+}
+
+// fetchWebhookStats reads the /stats endpoint of "line webhook serve".
+func fetchWebhookStats(ctx context.Context, url string) (*webhookStats, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var stats webhookStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("invalid stats response: %w", err)
+	}
+	return &stats, nil
+}
+
+// deriveOverviewRates fills in rates for cur from earlier snapshots: quota
+// burn averaged over the whole history, and webhook events per minute since
+// the previous snapshot.
+func deriveOverviewRates(earlier []overviewSnapshot, cur *overviewSnapshot) {
+	if len(earlier) == 0 {
+		return
+	}
+
+	first := earlier[0]
+	if hours := cur.Time.Sub(first.Time).Hours(); hours > 0 && first.Quota != nil && cur.Quota != nil {
+		burn := float64(cur.Quota.Used-first.Quota.Used) / hours
+		cur.QuotaBurnPerHour = &burn
+	}
+
+	prev := earlier[len(earlier)-1]
+	// A changed start time means the webhook server restarted and reset its counts
+	if minutes := cur.Time.Sub(prev.Time).Minutes(); minutes > 0 && prev.Webhook != nil && cur.Webhook != nil && prev.Webhook.Since.Equal(cur.Webhook.Since) {
+		rate := float64(cur.Webhook.Events-prev.Webhook.Events) / minutes
+		cur.WebhookEventsPerMinute = &rate
+	}
+}
+
+// renderOverview draws the latest snapshot with sparklines over all snaps.
+// A zero interval omits the refresh hint.
+func renderOverview(w io.Writer, snaps []overviewSnapshot, interval time.Duration) {
+	cur := snaps[len(snaps)-1]
+
+	_, _ = fmt.Fprintf(w, "LINE Official Account overview   %s\n", cur.Time.Format("2006-01-02 15:04:05"))
+	if interval > 0 {
+		_, _ = fmt.Fprintf(w, "Refreshing every %s (Ctrl+C to quit)\n", interval)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	series := func(value func(s overviewSnapshot) (float64, bool)) string {
+		if len(snaps) < 2 {
+			return ""
+		}
+		values := make([]*float64, len(snaps))
+		for i, s := range snaps {
+			if v, ok := value(s); ok {
+				values[i] = &v
+			}
+		}
+		return "  " + sparkline(values)
+	}
+
+	if f := cur.Followers; f != nil {
+		line := fmt.Sprintf("not ready (%s)", f.Status)
+		if f.Status == "ready" {
+			line = fmt.Sprintf("%d  reach %d  blocks %d", f.Followers, f.TargetedReaches, f.Blocks)
+		}
+		_, _ = fmt.Fprintf(w, "Followers (%s)   %s%s\n", f.Date, line, series(func(s overviewSnapshot) (float64, bool) {
+			if s.Followers == nil || s.Followers.Status != "ready" {
+				return 0, false
+			}
+			return float64(s.Followers.Followers), true
+		}))
+	}
+
+	if d := cur.Deliveries; d != nil {
+		line := fmt.Sprintf("not ready (%s)", d.Status)
+		if d.Status == "ready" {
+			line = fmt.Sprintf("%d", d.Total)
+		}
+		_, _ = fmt.Fprintf(w, "Deliveries today      %s%s\n", line, series(func(s overviewSnapshot) (float64, bool) {
+			if s.Deliveries == nil || s.Deliveries.Status != "ready" {
+				return 0, false
+			}
+			return float64(s.Deliveries.Total), true
+		}))
+	}
+
+	if q := cur.Quota; q != nil {
+		line := fmt.Sprintf("%d used (unlimited)", q.Used)
+		if q.Type == "limited" && q.Limit > 0 {
+			line = fmt.Sprintf("%d / %d (%.1f%%)", q.Used, q.Limit, float64(q.Used)/float64(q.Limit)*100)
+		}
+		if cur.QuotaBurnPerHour != nil {
+			line += fmt.Sprintf("  %+.0f/h", *cur.QuotaBurnPerHour)
+		}
+		_, _ = fmt.Fprintf(w, "Quota this month      %s%s\n", line, series(func(s overviewSnapshot) (float64, bool) {
+			if s.Quota == nil {
+				return 0, false
+			}
+			return float64(s.Quota.Used), true
+		}))
+	}
+
+	if wh := cur.Webhook; wh != nil {
+		line := fmt.Sprintf("%d total", wh.Events)
+		if cur.WebhookEventsPerMinute != nil {
+			line = fmt.Sprintf("%.1f/min  (%d total)", *cur.WebhookEventsPerMinute, wh.Events)
+		}
+		_, _ = fmt.Fprintf(w, "Webhook events        %s%s\n", line, series(func(s overviewSnapshot) (float64, bool) {
+			if s.WebhookEventsPerMinute == nil {
+				return 0, false
+			}
+			return *s.WebhookEventsPerMinute, true
+		}))
+	}
+
+	if len(cur.Errors) > 0 {
+		_, _ = fmt.Fprintln(w)
+		for _, e := range cur.Errors {
+			_, _ = fmt.Fprintf(w, "Error: %s\n", e)
+		}
+	}
+}
+
+// sparkBlocks are the sparkline levels from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values scaled between their minimum and maximum. Nil
+// values (missing samples) render as spaces.
+func sparkline(values []*float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if v != nil {
+			lo = math.Min(lo, *v)
+			hi = math.Max(hi, *v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case v == nil:
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			level := int(math.Round((*v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+			b.WriteRune(sparkBlocks[level])
+		}
+	}
+	return b.String()
+}
+
+func derefInt64(n *int64) int64 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
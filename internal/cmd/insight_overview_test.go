@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestSparkline(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	if got := sparkline([]*float64{f(0), f(7), nil, f(3.5)}); got != "▁█ ▅" {
+		t.Errorf("sparkline = %q", got)
+	}
+	if got := sparkline([]*float64{f(5), f(5)}); got != "▁▁" {
+		t.Errorf("flat sparkline = %q", got)
+	}
+}
+
+func TestDeriveOverviewRates(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := start.Add(-time.Hour)
+	earlier := []overviewSnapshot{
+		{Time: start, Quota: &overviewQuota{Used: 1000}, Webhook: &webhookStats{Since: since, Events: 10}},
+		{Time: start.Add(30 * time.Minute), Quota: &overviewQuota{Used: 1200}, Webhook: &webhookStats{Since: since, Events: 40}},
+	}
+	cur := overviewSnapshot{
+		Time:    start.Add(time.Hour),
+		Quota:   &overviewQuota{Used: 1500},
+		Webhook: &webhookStats{Since: since, Events: 100},
+	}
+
+	deriveOverviewRates(earlier, &cur)
+
+	if cur.QuotaBurnPerHour == nil || *cur.QuotaBurnPerHour != 500 {
+		t.Errorf("expected quota burn 500/h, got %v", cur.QuotaBurnPerHour)
+	}
+	if cur.WebhookEventsPerMinute == nil || *cur.WebhookEventsPerMinute != 2 {
+		t.Errorf("expected 2 events/min, got %v", cur.WebhookEventsPerMinute)
+	}
+
+	// A restarted webhook server resets its counters, so no rate is derived
+	cur.WebhookEventsPerMinute = nil
+	cur.Webhook.Since = start
+	deriveOverviewRates(earlier, &cur)
+	if cur.WebhookEventsPerMinute != nil {
+		t.Errorf("expected no rate after restart, got %v", *cur.WebhookEventsPerMinute)
+	}
+}
+
+func newOverviewTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	consumption := 0
+	webhookEvents := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/insight/followers":
+			_, _ = w.Write([]byte(`{"status":"ready","followers":1200,"targetedReaches":1000,"blocks":30}`))
+		case "/v2/bot/insight/message/delivery":
+			_, _ = w.Write([]byte(`{"status":"unready"}`))
+		case "/v2/bot/message/quota":
+			_, _ = w.Write([]byte(`{"type":"limited","value":10000}`))
+		case "/v2/bot/message/quota/consumption":
+			consumption += 100
+			_ = json.NewEncoder(w).Encode(map[string]int{"totalUsage": consumption})
+		case "/stats":
+			webhookEvents += 30
+			_ = json.NewEncoder(w).Encode(webhookStats{Since: time.Unix(0, 0).UTC(), Events: int64(webhookEvents)})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestInsightOverviewCmd_DashboardJSON(t *testing.T) {
+	server := newOverviewTestServer(t)
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput, oldNow := flags.Output, clockNow
+	defer func() { flags.Output, clockNow = oldOutput, oldNow }()
+	flags.Output = "json"
+	tick := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clockNow = func() time.Time {
+		tick = tick.Add(time.Minute)
+		return tick
+	}
+
+	cmd := newInsightOverviewCmdWithClient(client)
+	cmd.SetArgs([]string{"--dashboard", "--count", "3", "--interval", "1ms", "--webhook-stats", server.URL + "/stats"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var snaps []overviewSnapshot
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var s overviewSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		snaps = append(snaps, s)
+	}
+	if len(snaps) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(snaps))
+	}
+
+	last := snaps[2]
+	if last.Followers == nil || last.Followers.Followers != 1200 {
+		t.Errorf("unexpected followers: %+v", last.Followers)
+	}
+	if last.Quota == nil || last.Quota.Used != 300 || last.Quota.Limit != 10000 {
+		t.Errorf("unexpected quota: %+v", last.Quota)
+	}
+	// 200 messages over 2 minutes
+	if last.QuotaBurnPerHour == nil || *last.QuotaBurnPerHour != 6000 {
+		t.Errorf("expected quota burn 6000/h, got %v", last.QuotaBurnPerHour)
+	}
+	if last.WebhookEventsPerMinute == nil || *last.WebhookEventsPerMinute != 30 {
+		t.Errorf("expected 30 events/min, got %v", last.WebhookEventsPerMinute)
+	}
+	if len(last.Errors) != 0 {
+		t.Errorf("unexpected errors: %v", last.Errors)
+	}
+}
+
+func TestInsightOverviewCmd_TextPartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/bot/insight/followers" {
+			_, _ = w.Write([]byte(`{"status":"ready","followers":1200,"targetedReaches":1000,"blocks":30}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message":"boom"}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	cmd := newInsightOverviewCmdWithClient(client)
+	cmd.SetArgs([]string{})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"1200  reach 1000  blocks 30", "Error: deliveries:", "Error: quota:"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, output)
+		}
+	}
+}
@@ -39,7 +39,7 @@ func TestInsightCmd_HasSubcommands(t *testing.T) {
 		names[subcmd.Name()] = true
 	}
 
-	expected := []string{"followers", "messages", "demographics", "events", "unit-stats"}
+	expected := []string{"followers", "messages", "demographics", "events", "unit-stats", "overview"}
 	for _, name := range expected {
 		if !names[name] {
 			t.Errorf("expected '%s' subcommand", name)
@@ -666,7 +666,7 @@ func TestInsightUnitStatsCmd_InvalidDateFormat(t *testing.T) {
 	}{
 		{
 			name:    "invalid from date",
-			args:    []string{"--unit", "test", "--from", "2025-12-24", "--to", "20251231"},
+			args:    []string{"--unit", "test", "--from", "2025/12/24", "--to", "20251231"},
 			wantErr: "--from must be in YYYYMMDD",
 		},
 		{
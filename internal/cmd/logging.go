@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/salmonumbrella/line-official-cli/internal/logging"
+)
+
+// logger is where diagnostics go: warnings, and with --log-level info or
+// debug, retries and HTTP traffic. By default it prints warnings to stderr
+// for a person to read.
+var logger = logging.NewTerminal(os.Stderr, slog.LevelWarn)
+
+// logForTerminal is true while logs go to stderr in the terminal format, so
+// warnings can be printed to the command's own stderr.
+var logForTerminal = true
+
+// logFile is the open --log-file, closed by closeLogging.
+var logFile *os.File
+
+// setupLogging builds the logger from --log-level, --log-format, and
+// --log-file. --debug and --dry-run lower the default level to debug.
+func setupLogging(stderr io.Writer) error {
+	level := slog.LevelWarn
+	if flags.Debug || flags.DryRun {
+		level = slog.LevelDebug
+	}
+	if flags.LogLevel != "" {
+		var err error
+		if level, err = logging.ParseLevel(flags.LogLevel); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+	if flags.LogFormat != logging.FormatText && flags.LogFormat != logging.FormatJSON {
+		return withExitCode(ExitUsage, fmt.Errorf("invalid --log-format %q: must be text or json", flags.LogFormat))
+	}
+
+	if err := closeLogging(); err != nil {
+		return err
+	}
+	w := stderr
+	if flags.LogFile != "" {
+		f, err := logging.OpenFile(expandHome(flags.LogFile))
+		if err != nil {
+			return err
+		}
+		logFile, w = f, f
+	} else if flags.LogFormat == logging.FormatText {
+		logger, logForTerminal = logging.NewTerminal(stderr, level), true
+		return nil
+	}
+	l, err := logging.New(w, level, flags.LogFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	logger, logForTerminal = l, false
+	return nil
+}
+
+// closeLogging closes the --log-file, if one is open, and restores the
+// default logger.
+func closeLogging() error {
+	logger, logForTerminal = logging.NewTerminal(os.Stderr, slog.LevelWarn), true
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	if err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	return nil
+}
+
+// warnf reports a warning: as "Warning: ..." on w, the command's stderr, or
+// as a log record when --log-file or --log-format json send logs elsewhere.
+func warnf(w io.Writer, format string, args ...any) {
+	if !logger.Enabled(context.Background(), slog.LevelWarn) {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if logForTerminal {
+		_, _ = fmt.Fprintf(w, "Warning: %s\n", msg)
+		return
+	}
+	logger.Warn(msg)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupLogging_Validates(t *testing.T) {
+	t.Cleanup(func() { flags = rootFlags{}; _ = closeLogging() })
+
+	flags = rootFlags{LogLevel: "loud", LogFormat: "text"}
+	if err := setupLogging(&bytes.Buffer{}); err == nil || ExitCode(err) != ExitUsage {
+		t.Errorf("expected a usage error for an invalid level, got %v", err)
+	}
+	flags = rootFlags{LogFormat: "xml"}
+	if err := setupLogging(&bytes.Buffer{}); err == nil || ExitCode(err) != ExitUsage {
+		t.Errorf("expected a usage error for an invalid format, got %v", err)
+	}
+}
+
+func TestWarnf_Terminal(t *testing.T) {
+	t.Cleanup(func() { flags = rootFlags{}; _ = closeLogging() })
+
+	var stderr, w bytes.Buffer
+	flags = rootFlags{LogFormat: "text"}
+	if err := setupLogging(&stderr); err != nil {
+		t.Fatal(err)
+	}
+	warnf(&w, "token expires in %d days", 3)
+	if w.String() != "Warning: token expires in 3 days\n" {
+		t.Errorf("warning = %q", w.String())
+	}
+
+	w.Reset()
+	flags = rootFlags{LogFormat: "text", LogLevel: "error"}
+	if err := setupLogging(&stderr); err != nil {
+		t.Fatal(err)
+	}
+	warnf(&w, "hidden")
+	if w.Len() != 0 || stderr.Len() != 0 {
+		t.Errorf("expected no warning at --log-level error, got %q %q", w.String(), stderr.String())
+	}
+}
+
+func TestWarnf_JSONToStderr(t *testing.T) {
+	t.Cleanup(func() { flags = rootFlags{}; _ = closeLogging() })
+
+	var stderr, w bytes.Buffer
+	flags = rootFlags{LogFormat: "json"}
+	if err := setupLogging(&stderr); err != nil {
+		t.Fatal(err)
+	}
+	warnf(&w, "no column %q", "nope")
+	if w.Len() != 0 {
+		t.Errorf("expected no plain warning, got %q", w.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &record); err != nil {
+		t.Fatalf("expected a JSON record on stderr, got %q", stderr.String())
+	}
+	if record["level"] != "WARN" || record["msg"] != `no column "nope"` {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
+
+func TestExecute_LogFileRecordsFailure(t *testing.T) {
+	writeTestConfig(t, "")
+	path := filepath.Join(t.TempDir(), "logs", "line.log")
+
+	err := Execute([]string{"--log-file", path, "--log-format", "json", "audience", "get", "--id", "0"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if logFile != nil || !logForTerminal {
+		t.Error("expected the log file to be closed and the default logger restored")
+	}
+
+	data, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &record); err != nil {
+		t.Fatalf("expected one JSON record, got %q", data)
+	}
+	if record["level"] != "ERROR" || record["msg"] != "command failed" || record["command"] != "line audience get" || record["exitCode"] != float64(ExitCode(err)) {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/pflag"
+)
+
+// Limits LINE sets on media and location messages. File size limits (10 MB
+// for images, 1 MB for previews, 200 MB for video and audio) are only in
+// the flag help: the files are fetched by LINE, not the CLI.
+const (
+	maxMediaURLLen     = 2000
+	maxLocationTextLen = 100
+)
+
+// mediaFlagAliases maps the media flag names of earlier versions to the
+// current ones, so scripts using --image or --video keep working.
+var mediaFlagAliases = map[string]string{
+	"image":   "image-url",
+	"preview": "preview-url",
+	"video":   "video-url",
+	"audio":   "audio-url",
+}
+
+// normalizeMediaFlags is a pflag normalize func applying mediaFlagAliases.
+func normalizeMediaFlags(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if alias, ok := mediaFlagAliases[name]; ok {
+		return pflag.NormalizedName(alias)
+	}
+	return pflag.NormalizedName(name)
+}
+
+// validateMediaURL checks that a media URL is one LINE accepts: HTTPS and at
+// most 2000 characters.
+func validateMediaURL(flag, raw string) error {
+	if len(raw) > maxMediaURLLen {
+		return fmt.Errorf("%s is %d characters, at most %d are allowed", flag, len(raw), maxMediaURLLen)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute URL, got %q", flag, raw)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%s must use HTTPS, got %q", flag, raw)
+	}
+	return nil
+}
+
+// parseLocation parses --location "lat,lng,title,address". The address may
+// contain commas; the title may not.
+func parseLocation(s string) (lat, lng float64, title, address string, err error) {
+	parts := strings.SplitN(s, ",", 4)
+	if len(parts) != 4 {
+		return 0, 0, "", "", fmt.Errorf("invalid --location %q: expected \"lat,lng,title,address\"", s)
+	}
+	if lat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err != nil {
+		return 0, 0, "", "", fmt.Errorf("invalid --location latitude %q", parts[0])
+	}
+	if lng, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil {
+		return 0, 0, "", "", fmt.Errorf("invalid --location longitude %q", parts[1])
+	}
+	return lat, lng, strings.TrimSpace(parts[2]), strings.TrimSpace(parts[3]), nil
+}
+
+// applyLocationFlag fills the location fields from --location, which
+// cannot be combined with the separate --location-* flags.
+func applyLocationFlag(location string, lat, lng *float64, title, address *string) error {
+	if location == "" {
+		return nil
+	}
+	if *title != "" || *address != "" || *lat != 0 || *lng != 0 {
+		return fmt.Errorf("use --location or --location-title, --location-address, --lat, and --lng, not both")
+	}
+	var err error
+	*lat, *lng, *title, *address, err = parseLocation(location)
+	return err
+}
+
+// validateLocation checks a location message's fields against LINE's limits.
+func validateLocation(title, address string, lat, lng float64) error {
+	if utf8.RuneCountInString(title) > maxLocationTextLen {
+		return fmt.Errorf("location title is over %d characters", maxLocationTextLen)
+	}
+	if utf8.RuneCountInString(address) > maxLocationTextLen {
+		return fmt.Errorf("location address is over %d characters", maxLocationTextLen)
+	}
+	if lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %g is out of range (-90 to 90)", lat)
+	}
+	if lng < -180 || lng > 180 {
+		return fmt.Errorf("longitude %g is out of range (-180 to 180)", lng)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidateMediaURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"https://example.com/image.jpg", ""},
+		{"http://example.com/image.jpg", "must use HTTPS"},
+		{"example.com/image.jpg", "must be an absolute URL"},
+		{"https://example.com/" + strings.Repeat("a", maxMediaURLLen), "at most 2000"},
+	}
+	for _, tt := range tests {
+		err := validateMediaURL("--image-url", tt.url)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("validateMediaURL(%q): unexpected error %v", tt.url, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("validateMediaURL(%q): expected error containing %q, got %v", tt.url, tt.wantErr, err)
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	lat, lng, title, address, err := parseLocation("35.6586, 139.7454,Tokyo Tower,4-2-8 Shiba-koen, Minato-ku, Tokyo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lat != 35.6586 || lng != 139.7454 || title != "Tokyo Tower" || address != "4-2-8 Shiba-koen, Minato-ku, Tokyo" {
+		t.Errorf("got %v, %v, %q, %q", lat, lng, title, address)
+	}
+
+	for _, bad := range []string{"35.6,139.7,Tokyo Tower", "north,139.7,Tower,Tokyo", "35.6,east,Tower,Tokyo"} {
+		if _, _, _, _, err := parseLocation(bad); err == nil {
+			t.Errorf("parseLocation(%q): expected error", bad)
+		}
+	}
+}
+
+func TestValidateLocation(t *testing.T) {
+	if err := validateLocation("Tower", "Tokyo", 35.6, 139.7); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateLocation(strings.Repeat("塔", maxLocationTextLen+1), "Tokyo", 35.6, 139.7); err == nil {
+		t.Error("expected error for long title")
+	}
+	if err := validateLocation("Tower", "Tokyo", 91, 139.7); err == nil {
+		t.Error("expected error for latitude out of range")
+	}
+	if err := validateLocation("Tower", "Tokyo", 35.6, -181); err == nil {
+		t.Error("expected error for longitude out of range")
+	}
+}
+
+func TestMessagePushCmd_MediaFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			"location shorthand",
+			[]string{"--location", "35.6586,139.7454,Tokyo Tower,Minato-ku, Tokyo"},
+			map[string]any{"type": "location", "title": "Tokyo Tower", "address": "Minato-ku, Tokyo", "latitude": 35.6586, "longitude": 139.7454},
+			"",
+		},
+		{
+			"image with preview",
+			[]string{"--image-url", "https://example.com/a.jpg", "--preview-url", "https://example.com/a-small.jpg"},
+			map[string]any{"type": "image", "originalContentUrl": "https://example.com/a.jpg", "previewImageUrl": "https://example.com/a-small.jpg"},
+			"",
+		},
+		{
+			"earlier flag names",
+			[]string{"--video", "https://example.com/v.mp4", "--preview", "https://example.com/v.jpg"},
+			map[string]any{"type": "video", "originalContentUrl": "https://example.com/v.mp4", "previewImageUrl": "https://example.com/v.jpg"},
+			"",
+		},
+		{"plain HTTP", []string{"--audio-url", "http://example.com/a.m4a", "--duration", "1000"}, nil, "must use HTTPS"},
+		{"location both forms", []string{"--location", "1,2,a,b", "--lat", "3"}, nil, "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			cmd := newMessagePushCmdWithClient(captureRequest(t, &body))
+			cmd.SetArgs(append([]string{"--to", "U123"}, tt.args...))
+			cmd.SetOut(new(bytes.Buffer))
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				if body != nil {
+					t.Errorf("expected no request, got %v", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			msg := body["messages"].([]any)[0].(map[string]any)
+			for k, v := range tt.want {
+				if msg[k] != v {
+					t.Errorf("message %s = %v, want %v", k, msg[k], v)
+				}
+			}
+		})
+	}
+}
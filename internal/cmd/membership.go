@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
@@ -13,25 +15,27 @@ func newMembershipCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "membership",
 		Short: "Manage memberships (Japan-only)",
-		Long: `Manage LINE Official Account memberships.
+		Long: `Manage LINE Official Account memberships (paid subscriptions).
 Note: This feature is only available for accounts in Japan.`,
 	}
 
-	cmd.AddCommand(newMembershipPlansCmd())
-	cmd.AddCommand(newMembershipStatusCmd())
+	cmd.AddCommand(newMembershipListCmd())
+	cmd.AddCommand(newMembershipGetCmd())
+	cmd.AddCommand(newMembershipUserStatusCmd())
 	cmd.AddCommand(newMembershipUsersCmd())
 	return cmd
 }
 
-func newMembershipPlansCmd() *cobra.Command {
-	return newMembershipPlansCmdWithClient(nil)
+func newMembershipListCmd() *cobra.Command {
+	return newMembershipListCmdWithClient(nil)
 }
 
-func newMembershipPlansCmdWithClient(client *api.Client) *cobra.Command {
+func newMembershipListCmdWithClient(client *api.Client) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "plans",
-		Short: "List membership plans",
-		Long:  "Get a list of membership plans offered by your LINE Official Account.",
+		Use:     "list",
+		Aliases: []string{"plans"},
+		Short:   "List membership plans",
+		Long:    "Get a list of membership plans offered by your LINE Official Account.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := client
 			if c == nil {
@@ -58,35 +62,117 @@ func newMembershipPlansCmdWithClient(client *api.Client) *cobra.Command {
 				return nil
 			}
 
+			if flags.Output == "table" {
+				table := NewTable("ID", "TITLE", "PRICE", "MEMBERS", "PUBLISHED")
+				for _, plan := range plans {
+					table.AddRow(strconv.FormatInt(plan.MembershipID, 10), plan.Title,
+						formatMembershipPrice(plan), formatMembershipMembers(plan), strconv.FormatBool(plan.IsPublished))
+				}
+				table.Render(cmd.OutOrStdout())
+				return nil
+			}
+
 			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Membership Plans:")
 			for _, plan := range plans {
-				status := ""
-				if plan.IsPublished && plan.IsInSale {
-					status = " (active)"
-				} else if plan.IsPublished {
-					status = " (published, not for sale)"
+				status := " (unpublished)"
+				if plan.IsPublished {
+					status = " (published)"
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %d: %s - %s, %s members%s\n",
+					plan.MembershipID, plan.Title, formatMembershipPrice(plan), formatMembershipMembers(plan), status)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newMembershipGetCmd() *cobra.Command {
+	return newMembershipGetCmdWithClient(nil)
+}
+
+func newMembershipGetCmdWithClient(client *api.Client) *cobra.Command {
+	var membershipID int64
+
+	cmd := &cobra.Command{
+		Use:     "get",
+		Short:   "Get membership plan details",
+		Long:    "Get the details of one membership plan, including its benefits and subscriber count.",
+		Example: `  line membership get --id 3189`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if membershipID <= 0 {
+				return fmt.Errorf("--id is required")
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			plans, err := c.GetMembershipPlans(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get membership plans: %w", err)
+			}
+
+			var plan *api.MembershipPlan
+			for i := range plans {
+				if plans[i].MembershipID == membershipID {
+					plan = &plans[i]
+					break
+				}
+			}
+			if plan == nil {
+				return fmt.Errorf("membership plan %d not found", membershipID)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(plan)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID:          %d\n", plan.MembershipID)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Title:       %s\n", plan.Title)
+			if plan.Description != "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\n", plan.Description)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Price:       %s\n", formatMembershipPrice(*plan))
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Members:     %s\n", formatMembershipMembers(*plan))
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Published:   %v\n", plan.IsPublished)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "In-app:      %v\n", plan.IsInApp)
+			if len(plan.Benefits) > 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Benefits:")
+				for _, b := range plan.Benefits {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s\n", b)
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %d: %s - %d %s%s\n",
-					plan.MembershipID, plan.Title, plan.Price, plan.Currency, status)
 			}
 			return nil
 		},
 	}
 
+	cmd.Flags().Int64Var(&membershipID, "id", 0, "Membership plan ID (required)")
+	_ = cmd.MarkFlagRequired("id")
+
 	return cmd
 }
 
-func newMembershipStatusCmd() *cobra.Command {
-	return newMembershipStatusCmdWithClient(nil)
+func newMembershipUserStatusCmd() *cobra.Command {
+	return newMembershipUserStatusCmdWithClient(nil)
 }
 
-func newMembershipStatusCmdWithClient(client *api.Client) *cobra.Command {
+func newMembershipUserStatusCmdWithClient(client *api.Client) *cobra.Command {
 	var userID string
 
 	cmd := &cobra.Command{
-		Use:   "status",
-		Short: "Get user's membership status",
-		Long:  "Check a user's membership subscription status.",
+		Use:     "user-status",
+		Aliases: []string{"status"},
+		Short:   "Get a user's membership subscriptions",
+		Long:    "Check which memberships a user is subscribed to, with join and payment history.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if userID == "" {
 				return fmt.Errorf("--user is required")
@@ -101,7 +187,7 @@ func newMembershipStatusCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
-			memberships, err := c.GetUserMembershipStatus(cmd.Context(), userID)
+			subscriptions, err := c.GetMembershipSubscriptions(cmd.Context(), userID)
 			if err != nil {
 				return fmt.Errorf("failed to get membership status: %w", err)
 			}
@@ -109,22 +195,27 @@ func newMembershipStatusCmdWithClient(client *api.Client) *cobra.Command {
 			if flags.Output == "json" {
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				return enc.Encode(map[string]any{"userId": userID, "memberships": memberships})
+				return enc.Encode(map[string]any{"userId": userID, "subscriptions": subscriptions})
 			}
 
-			if len(memberships) == 0 {
+			if len(subscriptions) == 0 {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "User %s has no memberships\n", userID)
 				return nil
 			}
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "User: %s\n", userID)
-			for _, m := range memberships {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Membership %d: %s\n", m.MembershipID, m.SubscriptionState)
-				if m.StartTime > 0 {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    Started: %s\n", time.Unix(m.StartTime/1000, 0).Format(time.RFC3339))
+			for _, s := range subscriptions {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  Membership %d: %s (member #%d)\n",
+					s.Membership.MembershipID, s.Membership.Title, s.User.MembershipNo)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    Months:    %d\n", s.User.TotalSubscriptionMonths)
+				if s.User.JoinedTime > 0 {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    Joined:    %s\n", time.Unix(s.User.JoinedTime, 0).Format(time.RFC3339))
 				}
-				if m.EndTime > 0 {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    Ends: %s\n", time.Unix(m.EndTime/1000, 0).Format(time.RFC3339))
+				if s.User.LastJoinedTime > 0 && s.User.LastJoinedTime != s.User.JoinedTime {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    Rejoined:  %s\n", time.Unix(s.User.LastJoinedTime, 0).Format(time.RFC3339))
+				}
+				if s.User.LastPaidTime > 0 {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "    Last paid: %s\n", time.Unix(s.User.LastPaidTime, 0).Format(time.RFC3339))
 				}
 			}
 			return nil
@@ -142,53 +233,86 @@ func newMembershipUsersCmd() *cobra.Command {
 }
 
 func newMembershipUsersCmdWithClient(client *api.Client) *cobra.Command {
-	var all bool
+	var membershipID int64
+	var page pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "users",
 		Short: "List membership subscribers",
-		Long:  "Get a list of users who have joined memberships.",
+		Long: `Get the user IDs subscribed to a membership plan.
+
+Only the first 300 are fetched unless --all or --limit is given. When more
+subscribers are available, text output ends with a "Next cursor" line and
+JSON output includes the "next" cursor; pass it back with --cursor to
+continue.`,
+		Example: `  line membership users --id 3189 --all`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if membershipID <= 0 {
+				return fmt.Errorf("--id is required")
+			}
+			pageSize := 300
+			if page.all {
+				pageSize = 1000
+			}
+			opts, err := page.options(pageSize, 1000)
+			if err != nil {
+				return err
+			}
+
 			c := client
 			if c == nil {
-				var err error
 				c, err = newAPIClient()
 				if err != nil {
 					return err
 				}
 			}
 
-			var allUserIDs []string
-			var next string
-			for {
-				resp, err := c.GetMembershipUsers(cmd.Context(), next)
+			allUserIDs, next, err := api.Paginate(cmd.Context(), opts, func(ctx context.Context, cursor string, size int) ([]string, string, error) {
+				resp, err := c.GetMembershipUsers(ctx, membershipID, cursor, size)
 				if err != nil {
-					return fmt.Errorf("failed to get membership users: %w", err)
-				}
-				allUserIDs = append(allUserIDs, resp.MemberIDs...)
-				if resp.Next == "" || !all {
-					break
+					return nil, "", fmt.Errorf("failed to get membership users: %w", err)
 				}
-				next = resp.Next
+				return resp.UserIDs, resp.Next, nil
+			})
+			if err != nil {
+				return err
 			}
 
 			if flags.Output == "json" {
+				result := map[string]any{"membershipId": membershipID, "count": len(allUserIDs), "userIds": allUserIDs}
+				if next != "" {
+					result["next"] = next
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				return enc.Encode(map[string]any{"count": len(allUserIDs), "userIds": allUserIDs})
+				return enc.Encode(result)
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Membership Subscribers: %d\n", len(allUserIDs))
-			if all {
-				for _, id := range allUserIDs {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), id)
-				}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Membership %d Subscribers: %d\n", membershipID, len(allUserIDs))
+			for _, id := range allUserIDs {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), id)
 			}
+			printNextCursor(cmd.OutOrStdout(), next)
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&all, "all", false, "Fetch and list all user IDs (paginated)")
+	cmd.Flags().Int64Var(&membershipID, "id", 0, "Membership plan ID (required)")
+	addPageFlags(cmd, &page, "user IDs")
+	_ = cmd.MarkFlagRequired("id")
 
 	return cmd
 }
+
+// formatMembershipPrice formats a plan's monthly price, e.g. "500 JPY".
+func formatMembershipPrice(plan api.MembershipPlan) string {
+	return strconv.FormatFloat(plan.Price, 'f', -1, 64) + " " + plan.Currency
+}
+
+// formatMembershipMembers formats a plan's member count and cap, e.g. "12/100".
+func formatMembershipMembers(plan api.MembershipPlan) string {
+	if plan.MemberLimit == nil {
+		return strconv.FormatInt(plan.MemberCount, 10)
+	}
+	return fmt.Sprintf("%d/%d", plan.MemberCount, *plan.MemberLimit)
+}
@@ -29,17 +29,12 @@ func TestMembershipCmd_RequiresSubcommand(t *testing.T) {
 func TestMembershipCmd_HasSubcommands(t *testing.T) {
 	cmd := newMembershipCmd()
 
-	subcommands := cmd.Commands()
-	if len(subcommands) < 3 {
-		t.Errorf("expected at least 3 subcommands (plans, status, users), got %d", len(subcommands))
-	}
-
 	names := make(map[string]bool)
-	for _, subcmd := range subcommands {
+	for _, subcmd := range cmd.Commands() {
 		names[subcmd.Name()] = true
 	}
 
-	expected := []string{"plans", "status", "users"}
+	expected := []string{"list", "get", "users", "user-status"}
 	for _, name := range expected {
 		if !names[name] {
 			t.Errorf("expected '%s' subcommand", name)
@@ -47,13 +42,27 @@ func TestMembershipCmd_HasSubcommands(t *testing.T) {
 	}
 }
 
-func TestMembershipStatusCmd_RequiresUserID(t *testing.T) {
+func TestMembershipCmd_Aliases(t *testing.T) {
+	cmd := newMembershipCmd()
+
+	for alias, want := range map[string]string{"plans": "list", "status": "user-status"} {
+		found, _, err := cmd.Find([]string{alias})
+		if err != nil {
+			t.Fatalf("unexpected error finding %q: %v", alias, err)
+		}
+		if found.Name() != want {
+			t.Errorf("expected %q to resolve to %q, got %q", alias, want, found.Name())
+		}
+	}
+}
+
+func TestMembershipUserStatusCmd_RequiresUserID(t *testing.T) {
 	cmd := NewRootCmd()
 
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
 	cmd.SetErr(buf)
-	cmd.SetArgs([]string{"membership", "status"})
+	cmd.SetArgs([]string{"membership", "user-status"})
 
 	err := cmd.Execute()
 	if err == nil {
@@ -61,29 +70,64 @@ func TestMembershipStatusCmd_RequiresUserID(t *testing.T) {
 	}
 }
 
+func TestMembershipGetCmd_RequiresID(t *testing.T) {
+	cmd := NewRootCmd()
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"membership", "get"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Error("expected error for missing --id flag")
+	}
+}
+
+func TestMembershipUsersCmd_RequiresID(t *testing.T) {
+	cmd := NewRootCmd()
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"membership", "users"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Error("expected error for missing --id flag")
+	}
+}
+
 // Execution tests using mock servers
 
-func TestMembershipPlansCmd_Execute(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/v2/bot/membership/plans" {
+func newMembershipPlansServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/bot/membership/list" {
 			w.Header().Set("Content-Type", "application/json")
 			_ = json.NewEncoder(w).Encode(map[string]any{
 				"memberships": []map[string]any{
 					{
 						"membershipId": 123,
 						"title":        "Gold Plan",
+						"description":  "All benefits",
+						"benefits":     []string{"Monthly coupon", "Members-only posts"},
 						"price":        1000,
 						"currency":     "JPY",
+						"memberCount":  12,
+						"memberLimit":  100,
+						"isInApp":      false,
 						"isPublished":  true,
-						"isInSale":     true,
 					},
 					{
 						"membershipId": 456,
 						"title":        "Silver Plan",
 						"price":        500,
 						"currency":     "JPY",
-						"isPublished":  true,
-						"isInSale":     false,
+						"memberCount":  3,
+						"memberLimit":  nil,
+						"isInApp":      true,
+						"isPublished":  false,
 					},
 				},
 			})
@@ -91,6 +135,10 @@ func TestMembershipPlansCmd_Execute(t *testing.T) {
 		}
 		w.WriteHeader(http.StatusNotFound)
 	}))
+}
+
+func TestMembershipListCmd_Execute(t *testing.T) {
+	server := newMembershipPlansServer(t)
 	defer server.Close()
 
 	client := api.NewClient("test-token", false, false)
@@ -100,13 +148,17 @@ func TestMembershipPlansCmd_Execute(t *testing.T) {
 		name      string
 		output    string
 		wantJSON  bool
-		checkText string
+		checkText []string
 	}{
 		{
 			name:      "text output",
 			output:    "text",
-			wantJSON:  false,
-			checkText: "Gold Plan",
+			checkText: []string{"123: Gold Plan - 1000 JPY, 12/100 members (published)", "456: Silver Plan - 500 JPY, 3 members (unpublished)"},
+		},
+		{
+			name:      "table output",
+			output:    "table",
+			checkText: []string{"PUBLISHED", "Gold Plan", "12/100"},
 		},
 		{
 			name:     "json output",
@@ -121,7 +173,7 @@ func TestMembershipPlansCmd_Execute(t *testing.T) {
 			flags.Output = tt.output
 			defer func() { flags.Output = oldOutput }()
 
-			cmd := newMembershipPlansCmdWithClient(client)
+			cmd := newMembershipListCmdWithClient(client)
 			var out bytes.Buffer
 			cmd.SetOut(&out)
 
@@ -134,25 +186,24 @@ func TestMembershipPlansCmd_Execute(t *testing.T) {
 			if tt.wantJSON {
 				var result map[string]any
 				if err := json.Unmarshal([]byte(output), &result); err != nil {
-					t.Errorf("expected valid JSON output, got: %s", output)
+					t.Fatalf("expected valid JSON output, got: %s", output)
 				}
 				plans := result["plans"].([]any)
 				if len(plans) != 2 {
 					t.Errorf("expected 2 plans, got: %d", len(plans))
 				}
-			} else {
-				if !strings.Contains(output, tt.checkText) {
-					t.Errorf("expected output to contain %q, got: %s", tt.checkText, output)
-				}
-				if !strings.Contains(output, "(active)") {
-					t.Errorf("expected output to contain '(active)', got: %s", output)
+				return
+			}
+			for _, want := range tt.checkText {
+				if !strings.Contains(output, want) {
+					t.Errorf("expected output to contain %q, got: %s", want, output)
 				}
 			}
 		})
 	}
 }
 
-func TestMembershipPlansCmd_EmptyPlans(t *testing.T) {
+func TestMembershipListCmd_EmptyPlans(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(map[string]any{
@@ -168,7 +219,7 @@ func TestMembershipPlansCmd_EmptyPlans(t *testing.T) {
 	flags.Output = "text"
 	defer func() { flags.Output = oldOutput }()
 
-	cmd := newMembershipPlansCmdWithClient(client)
+	cmd := newMembershipListCmdWithClient(client)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
@@ -182,7 +233,7 @@ func TestMembershipPlansCmd_EmptyPlans(t *testing.T) {
 	}
 }
 
-func TestMembershipPlansCmd_APIError(t *testing.T) {
+func TestMembershipListCmd_APIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		_ = json.NewEncoder(w).Encode(map[string]string{"message": "API error"})
@@ -192,156 +243,117 @@ func TestMembershipPlansCmd_APIError(t *testing.T) {
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
-	cmd := newMembershipPlansCmdWithClient(client)
+	cmd := newMembershipListCmdWithClient(client)
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
 	err := cmd.Execute()
 	if err == nil {
-		t.Error("expected error for API failure")
+		t.Fatal("expected error for API failure")
 	}
 	if !strings.Contains(err.Error(), "failed to get membership plans") {
 		t.Errorf("expected 'failed to get membership plans' in error, got: %v", err)
 	}
 }
 
-func TestMembershipStatusCmd_Execute(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/membership/subscription") {
-			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(map[string]any{
-				"memberships": []map[string]any{
-					{
-						"membershipId":      123,
-						"subscriptionState": "ACTIVE",
-						"startTime":         1704067200000,
-						"endTime":           1735689600000,
-					},
-				},
-			})
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
+func TestMembershipGetCmd_Execute(t *testing.T) {
+	server := newMembershipPlansServer(t)
 	defer server.Close()
 
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
-	tests := []struct {
-		name      string
-		output    string
-		wantJSON  bool
-		checkText string
-	}{
-		{
-			name:      "text output",
-			output:    "text",
-			wantJSON:  false,
-			checkText: "ACTIVE",
-		},
-		{
-			name:     "json output",
-			output:   "json",
-			wantJSON: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			oldOutput := flags.Output
-			flags.Output = tt.output
-			defer func() { flags.Output = oldOutput }()
+	t.Run("text output", func(t *testing.T) {
+		oldOutput := flags.Output
+		flags.Output = "text"
+		defer func() { flags.Output = oldOutput }()
 
-			cmd := newMembershipStatusCmdWithClient(client)
-			cmd.SetArgs([]string{"--user", "U123456789"})
-			var out bytes.Buffer
-			cmd.SetOut(&out)
+		cmd := newMembershipGetCmdWithClient(client)
+		cmd.SetArgs([]string{"--id", "123"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
 
-			err := cmd.Execute()
-			if err != nil {
-				t.Fatalf("unexpected error: %v", err)
-			}
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-			output := out.String()
-			if tt.wantJSON {
-				var result map[string]any
-				if err := json.Unmarshal([]byte(output), &result); err != nil {
-					t.Errorf("expected valid JSON output, got: %s", output)
-				}
-				if result["userId"] != "U123456789" {
-					t.Errorf("expected userId 'U123456789', got: %v", result["userId"])
-				}
-			} else {
-				if !strings.Contains(output, tt.checkText) {
-					t.Errorf("expected output to contain %q, got: %s", tt.checkText, output)
-				}
+		output := out.String()
+		for _, want := range []string{"Gold Plan", "All benefits", "1000 JPY", "12/100", "- Monthly coupon"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, got: %s", want, output)
 			}
-		})
-	}
-}
-
-func TestMembershipStatusCmd_NoMemberships(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(map[string]any{
-			"memberships": []map[string]any{},
-		})
-	}))
-	defer server.Close()
+		}
+		if strings.Contains(output, "Silver Plan") {
+			t.Errorf("expected only the requested plan, got: %s", output)
+		}
+	})
 
-	client := api.NewClient("test-token", false, false)
-	client.SetBaseURL(server.URL)
+	t.Run("json output", func(t *testing.T) {
+		oldOutput := flags.Output
+		flags.Output = "json"
+		defer func() { flags.Output = oldOutput }()
 
-	oldOutput := flags.Output
-	flags.Output = "text"
-	defer func() { flags.Output = oldOutput }()
+		cmd := newMembershipGetCmdWithClient(client)
+		cmd.SetArgs([]string{"--id", "456"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
 
-	cmd := newMembershipStatusCmdWithClient(client)
-	cmd.SetArgs([]string{"--user", "U123456789"})
-	var out bytes.Buffer
-	cmd.SetOut(&out)
-
-	err := cmd.Execute()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-	if !strings.Contains(out.String(), "has no memberships") {
-		t.Errorf("expected 'has no memberships', got: %s", out.String())
-	}
+		var plan api.MembershipPlan
+		if err := json.Unmarshal(out.Bytes(), &plan); err != nil {
+			t.Fatalf("expected valid JSON output, got: %s", out.String())
+		}
+		if plan.MembershipID != 456 || plan.Title != "Silver Plan" {
+			t.Errorf("unexpected plan: %+v", plan)
+		}
+	})
 }
 
-func TestMembershipStatusCmd_APIError(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(map[string]string{"message": "User not found"})
-	}))
+func TestMembershipGetCmd_NotFound(t *testing.T) {
+	server := newMembershipPlansServer(t)
 	defer server.Close()
 
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
-	cmd := newMembershipStatusCmdWithClient(client)
-	cmd.SetArgs([]string{"--user", "U999999999"})
+	cmd := newMembershipGetCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "999"})
 	var out bytes.Buffer
 	cmd.SetOut(&out)
+	cmd.SetErr(&out)
 
 	err := cmd.Execute()
 	if err == nil {
-		t.Error("expected error for API failure")
+		t.Fatal("expected error for unknown plan")
 	}
-	if !strings.Contains(err.Error(), "failed to get membership status") {
-		t.Errorf("expected 'failed to get membership status' in error, got: %v", err)
+	if !strings.Contains(err.Error(), "membership plan 999 not found") {
+		t.Errorf("unexpected error: %v", err)
 	}
 }
 
-func TestMembershipUsersCmd_Execute(t *testing.T) {
+func TestMembershipUserStatusCmd_Execute(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/v2/bot/membership/users" {
+		if r.URL.Path == "/v2/bot/membership/subscription/U123456789" {
 			w.Header().Set("Content-Type", "application/json")
 			_ = json.NewEncoder(w).Encode(map[string]any{
-				"memberIds": []string{"U001", "U002", "U003"},
+				"subscriptions": []map[string]any{
+					{
+						"membership": map[string]any{
+							"membershipId": 123,
+							"title":        "Gold Plan",
+						},
+						"user": map[string]any{
+							"membershipNo":            7,
+							"joinedTime":              1704067200,
+							"lastJoinedTime":          1706745600,
+							"lastPaidTime":            1709251200,
+							"totalSubscriptionMonths": 3,
+						},
+					},
+				},
 			})
 			return
 		}
@@ -352,88 +364,61 @@ func TestMembershipUsersCmd_Execute(t *testing.T) {
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
-	tests := []struct {
-		name      string
-		output    string
-		all       bool
-		wantJSON  bool
-		checkText string
-	}{
-		{
-			name:      "text output",
-			output:    "text",
-			all:       false,
-			wantJSON:  false,
-			checkText: "Membership Subscribers: 3",
-		},
-		{
-			name:      "text output with all",
-			output:    "text",
-			all:       true,
-			wantJSON:  false,
-			checkText: "U001",
-		},
-		{
-			name:     "json output",
-			output:   "json",
-			all:      false,
-			wantJSON: true,
-		},
-	}
+	t.Run("text output", func(t *testing.T) {
+		oldOutput := flags.Output
+		flags.Output = "text"
+		defer func() { flags.Output = oldOutput }()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			oldOutput := flags.Output
-			flags.Output = tt.output
-			defer func() { flags.Output = oldOutput }()
+		cmd := newMembershipUserStatusCmdWithClient(client)
+		cmd.SetArgs([]string{"--user", "U123456789"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
 
-			cmd := newMembershipUsersCmdWithClient(client)
-			args := []string{}
-			if tt.all {
-				args = append(args, "--all")
-			}
-			cmd.SetArgs(args)
-			var out bytes.Buffer
-			cmd.SetOut(&out)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-			err := cmd.Execute()
-			if err != nil {
-				t.Fatalf("unexpected error: %v", err)
+		output := out.String()
+		for _, want := range []string{"Membership 123: Gold Plan (member #7)", "Months:    3", "Joined:", "Rejoined:", "Last paid:"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, got: %s", want, output)
 			}
+		}
+	})
 
-			output := out.String()
-			if tt.wantJSON {
-				var result map[string]any
-				if err := json.Unmarshal([]byte(output), &result); err != nil {
-					t.Errorf("expected valid JSON output, got: %s", output)
-				}
-				if result["count"].(float64) != 3 {
-					t.Errorf("expected count 3, got: %v", result["count"])
-				}
-			} else {
-				if !strings.Contains(output, tt.checkText) {
-					t.Errorf("expected output to contain %q, got: %s", tt.checkText, output)
-				}
-			}
-		})
-	}
+	t.Run("json output", func(t *testing.T) {
+		oldOutput := flags.Output
+		flags.Output = "json"
+		defer func() { flags.Output = oldOutput }()
+
+		cmd := newMembershipUserStatusCmdWithClient(client)
+		cmd.SetArgs([]string{"--user", "U123456789"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var result map[string]any
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Fatalf("expected valid JSON output, got: %s", out.String())
+		}
+		if result["userId"] != "U123456789" {
+			t.Errorf("expected userId 'U123456789', got: %v", result["userId"])
+		}
+		if subs, ok := result["subscriptions"].([]any); !ok || len(subs) != 1 {
+			t.Errorf("expected 1 subscription, got: %v", result["subscriptions"])
+		}
+	})
 }
 
-func TestMembershipUsersCmd_Pagination(t *testing.T) {
-	callCount := 0
+func TestMembershipUserStatusCmd_NoMemberships(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		callCount++
 		w.Header().Set("Content-Type", "application/json")
-		if callCount == 1 {
-			_ = json.NewEncoder(w).Encode(map[string]any{
-				"memberIds": []string{"U001", "U002"},
-				"next":      "cursor123",
-			})
-		} else {
-			_ = json.NewEncoder(w).Encode(map[string]any{
-				"memberIds": []string{"U003"},
-			})
-		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"subscriptions": []map[string]any{},
+		})
 	}))
 	defer server.Close()
 
@@ -441,11 +426,11 @@ func TestMembershipUsersCmd_Pagination(t *testing.T) {
 	client.SetBaseURL(server.URL)
 
 	oldOutput := flags.Output
-	flags.Output = "json"
+	flags.Output = "text"
 	defer func() { flags.Output = oldOutput }()
 
-	cmd := newMembershipUsersCmdWithClient(client)
-	cmd.SetArgs([]string{"--all"})
+	cmd := newMembershipUserStatusCmdWithClient(client)
+	cmd.SetArgs([]string{"--user", "U123456789"})
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
@@ -454,176 +439,191 @@ func TestMembershipUsersCmd_Pagination(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	var result map[string]any
-	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
-		t.Fatalf("failed to parse JSON: %v", err)
-	}
-
-	if result["count"].(float64) != 3 {
-		t.Errorf("expected count 3 after pagination, got: %v", result["count"])
-	}
-
-	if callCount != 2 {
-		t.Errorf("expected 2 API calls for pagination, got: %d", callCount)
+	if !strings.Contains(out.String(), "has no memberships") {
+		t.Errorf("expected 'has no memberships', got: %s", out.String())
 	}
 }
 
-func TestMembershipUsersCmd_APIError(t *testing.T) {
+func TestMembershipUserStatusCmd_APIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(map[string]string{"message": "API error"})
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": "User not found"})
 	}))
 	defer server.Close()
 
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
-	cmd := newMembershipUsersCmdWithClient(client)
+	cmd := newMembershipUserStatusCmdWithClient(client)
+	cmd.SetArgs([]string{"--user", "U999999999"})
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
 	err := cmd.Execute()
 	if err == nil {
-		t.Error("expected error for API failure")
+		t.Fatal("expected error for API failure")
 	}
-	if !strings.Contains(err.Error(), "failed to get membership users") {
-		t.Errorf("expected 'failed to get membership users' in error, got: %v", err)
+	if !strings.Contains(err.Error(), "failed to get membership status") {
+		t.Errorf("expected 'failed to get membership status' in error, got: %v", err)
 	}
 }
 
-func TestMembershipPlansCmd_PublishedNotForSale(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/v2/bot/membership/plans" {
-			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(map[string]any{
-				"memberships": []map[string]any{
-					{
-						"membershipId": 123,
-						"title":        "Limited Plan",
-						"price":        2000,
-						"currency":     "JPY",
-						"isPublished":  true,
-						"isInSale":     false,
-					},
-				},
-			})
+func newMembershipUsersServer(t *testing.T, requests *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/membership/123/users/ids" {
+			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		w.WriteHeader(http.StatusNotFound)
+		*requests = append(*requests, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("start") == "page2" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"userIds": []string{"U3"}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"userIds": []string{"U1", "U2"}, "next": "page2"})
 	}))
+}
+
+func TestMembershipUsersCmd_Execute(t *testing.T) {
+	var requests []string
+	server := newMembershipUsersServer(t, &requests)
 	defer server.Close()
 
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
-	oldOutput := flags.Output
-	flags.Output = "text"
-	defer func() { flags.Output = oldOutput }()
+	t.Run("text output shows next-page hint", func(t *testing.T) {
+		requests = nil
+		oldOutput := flags.Output
+		flags.Output = "text"
+		defer func() { flags.Output = oldOutput }()
 
-	cmd := newMembershipPlansCmdWithClient(client)
-	var out bytes.Buffer
-	cmd.SetOut(&out)
+		cmd := newMembershipUsersCmdWithClient(client)
+		cmd.SetArgs([]string{"--id", "123"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
 
-	err := cmd.Execute()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-	output := out.String()
-	if !strings.Contains(output, "(published, not for sale)") {
-		t.Errorf("expected '(published, not for sale)' status, got: %s", output)
-	}
-}
+		output := out.String()
+		for _, want := range []string{"Membership 123 Subscribers: 2", "U1", "U2", "Next cursor: page2"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, got: %s", want, output)
+			}
+		}
+		if len(requests) != 1 || requests[0] != "limit=300" {
+			t.Errorf("expected one request with limit=300, got: %v", requests)
+		}
+	})
 
-func TestMembershipStatusCmd_WithTimes(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/membership/subscription") {
-			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(map[string]any{
-				"memberships": []map[string]any{
-					{
-						"membershipId":      123,
-						"subscriptionState": "ACTIVE",
-						"startTime":         1704067200000,
-						"endTime":           1735689600000,
-					},
-				},
-			})
-			return
+	t.Run("json output includes next cursor", func(t *testing.T) {
+		requests = nil
+		oldOutput := flags.Output
+		flags.Output = "json"
+		defer func() { flags.Output = oldOutput }()
+
+		cmd := newMembershipUsersCmdWithClient(client)
+		cmd.SetArgs([]string{"--id", "123", "--start", "page2", "--limit", "1000"})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
 		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
+
+		var result map[string]any
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Fatalf("expected valid JSON output, got: %s", out.String())
+		}
+		if result["count"] != float64(1) {
+			t.Errorf("expected count 1, got: %v", result["count"])
+		}
+		if _, ok := result["next"]; ok {
+			t.Errorf("expected no next cursor on the last page, got: %v", result["next"])
+		}
+		if len(requests) != 1 || requests[0] != "limit=1000&start=page2" {
+			t.Errorf("unexpected requests: %v", requests)
+		}
+	})
+}
+
+func TestMembershipUsersCmd_All(t *testing.T) {
+	var requests []string
+	server := newMembershipUsersServer(t, &requests)
 	defer server.Close()
 
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
 	oldOutput := flags.Output
-	flags.Output = "text"
+	flags.Output = "json"
 	defer func() { flags.Output = oldOutput }()
 
-	cmd := newMembershipStatusCmdWithClient(client)
-	cmd.SetArgs([]string{"--user", "U123456789"})
+	cmd := newMembershipUsersCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "123", "--all"})
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
-	err := cmd.Execute()
-	if err != nil {
+	if err := cmd.Execute(); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	output := out.String()
-	if !strings.Contains(output, "Started:") {
-		t.Errorf("expected 'Started:' in output, got: %s", output)
+	var result struct {
+		MembershipID int64    `json:"membershipId"`
+		Count        int      `json:"count"`
+		UserIDs      []string `json:"userIds"`
+		Next         string   `json:"next"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("expected valid JSON output, got: %s", out.String())
+	}
+	if result.MembershipID != 123 || result.Count != 3 || strings.Join(result.UserIDs, ",") != "U1,U2,U3" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.Next != "" {
+		t.Errorf("expected no next cursor, got: %s", result.Next)
 	}
-	if !strings.Contains(output, "Ends:") {
-		t.Errorf("expected 'Ends:' in output, got: %s", output)
+	if len(requests) != 2 {
+		t.Errorf("expected 2 requests, got: %v", requests)
 	}
 }
 
-func TestMembershipStatusCmd_NoTimes(t *testing.T) {
+func TestMembershipUsersCmd_InvalidLimit(t *testing.T) {
+	cmd := newMembershipUsersCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetArgs([]string{"--id", "123", "--limit", "-1"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--limit cannot be negative") {
+		t.Errorf("expected limit error, got: %v", err)
+	}
+}
+
+func TestMembershipUsersCmd_APIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if strings.Contains(r.URL.Path, "/membership/subscription") {
-			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(map[string]any{
-				"memberships": []map[string]any{
-					{
-						"membershipId":      123,
-						"subscriptionState": "INACTIVE",
-					},
-				},
-			})
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": "Forbidden"})
 	}))
 	defer server.Close()
 
 	client := api.NewClient("test-token", false, false)
 	client.SetBaseURL(server.URL)
 
-	oldOutput := flags.Output
-	flags.Output = "text"
-	defer func() { flags.Output = oldOutput }()
-
-	cmd := newMembershipStatusCmdWithClient(client)
-	cmd.SetArgs([]string{"--user", "U123456789"})
+	cmd := newMembershipUsersCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "123"})
 	var out bytes.Buffer
 	cmd.SetOut(&out)
 
 	err := cmd.Execute()
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	output := out.String()
-	if strings.Contains(output, "Started:") {
-		t.Error("did not expect 'Started:' when startTime is 0")
-	}
-	if strings.Contains(output, "Ends:") {
-		t.Error("did not expect 'Ends:' when endTime is 0")
+	if err == nil {
+		t.Fatal("expected error for API failure")
 	}
-	if !strings.Contains(output, "INACTIVE") {
-		t.Errorf("expected 'INACTIVE' in output, got: %s", output)
+	if !strings.Contains(err.Error(), "failed to get membership users") {
+		t.Errorf("expected 'failed to get membership users' in error, got: %v", err)
 	}
 }
@@ -13,6 +13,13 @@ type messageTarget struct {
 	Type    string   // "push", "broadcast", "multicast"
 	UserID  string   // for push
 	UserIDs []string // for multicast
+	// QuickReply holds quick reply items to attach to the message, if any
+	QuickReply json.RawMessage
+	// AggregationUnit is the custom aggregation unit the message's insights
+	// are counted under (push and multicast only)
+	AggregationUnit string
+	// Track records a broadcast's request ID in the ledger
+	Track *trackFlags
 }
 
 // sendMessage is the generic message sending helper for the command layer.
@@ -27,7 +34,21 @@ func sendMessage(cmd *cobra.Command, client *api.Client, target messageTarget, m
 		}
 	}
 
-	if err := client.SendMessage(cmd.Context(), target.Type, target.UserID, target.UserIDs, message); err != nil {
+	message, err := attachQuickReply(message, target.QuickReply)
+	if err != nil {
+		return err
+	}
+	if target.Type == "broadcast" && target.Track.enabled() {
+		resp, err := client.Broadcast(cmd.Context(), api.BroadcastMessageRequest{Messages: []any{message}})
+		if err != nil {
+			return fmt.Errorf("failed to send %s: %w", msgType, err)
+		}
+		trackSend(cmd, target.Track, "broadcast", resp.RequestID, message)
+		if extraFields == nil {
+			extraFields = map[string]any{}
+		}
+		extraFields["requestId"] = resp.RequestID
+	} else if err := client.SendMessagesInUnit(cmd.Context(), target.Type, target.UserID, target.UserIDs, []any{message}, target.AggregationUnit); err != nil {
 		return fmt.Errorf("failed to send %s: %w", msgType, err)
 	}
 
@@ -48,6 +69,9 @@ func formatMessageOutput(cmd *cobra.Command, target messageTarget, msgType strin
 			result["status"] = "sent"
 			result["recipients"] = len(target.UserIDs)
 		}
+		if target.AggregationUnit != "" {
+			result["aggregationUnit"] = target.AggregationUnit
+		}
 		for k, v := range extraFields {
 			result[k] = v
 		}
@@ -92,7 +116,10 @@ func capitalize(s string) string {
 // If client is nil, a new client is created using newAPIClient().
 func dispatchMessage(cmd *cobra.Command, client *api.Client, target messageTarget, text, flexJSON, altText, imageURL, previewURL, videoURL, audioURL string, duration int, locationTitle, locationAddress string, lat, lng float64, packageID, stickerID string) error {
 	if text != "" {
-		msg := api.TextMessage{Type: "text", Text: text}
+		msg, err := newTextMessage(text)
+		if err != nil {
+			return err
+		}
 		return sendMessage(cmd, client, target, msg, "text", nil)
 	}
 	if flexJSON != "" {
@@ -103,12 +130,24 @@ func dispatchMessage(cmd *cobra.Command, client *api.Client, target messageTarge
 		if previewURL == "" {
 			previewURL = imageURL
 		}
+		if err := validateMediaURL("--image-url", imageURL); err != nil {
+			return err
+		}
+		if err := validateMediaURL("--preview-url", previewURL); err != nil {
+			return err
+		}
 		msg := api.ImageMessage{Type: "image", OriginalContentURL: imageURL, PreviewImageURL: previewURL}
 		return sendMessage(cmd, client, target, msg, "image", nil)
 	}
 	if videoURL != "" {
 		if previewURL == "" {
-			return fmt.Errorf("--preview is required for video messages")
+			return fmt.Errorf("--preview-url is required for video messages")
+		}
+		if err := validateMediaURL("--video-url", videoURL); err != nil {
+			return err
+		}
+		if err := validateMediaURL("--preview-url", previewURL); err != nil {
+			return err
 		}
 		msg := api.VideoMessage{Type: "video", OriginalContentURL: videoURL, PreviewImageURL: previewURL}
 		return sendMessage(cmd, client, target, msg, "video", nil)
@@ -117,6 +156,9 @@ func dispatchMessage(cmd *cobra.Command, client *api.Client, target messageTarge
 		if duration <= 0 {
 			return fmt.Errorf("--duration is required for audio messages (in milliseconds)")
 		}
+		if err := validateMediaURL("--audio-url", audioURL); err != nil {
+			return err
+		}
 		msg := api.AudioMessage{Type: "audio", OriginalContentURL: audioURL, Duration: duration}
 		return sendMessage(cmd, client, target, msg, "audio", map[string]any{"duration": duration})
 	}
@@ -130,6 +172,9 @@ func dispatchMessage(cmd *cobra.Command, client *api.Client, target messageTarge
 		if lat == 0 && lng == 0 {
 			return fmt.Errorf("--lat and --lng are required for location messages")
 		}
+		if err := validateLocation(locationTitle, locationAddress, lat, lng); err != nil {
+			return err
+		}
 		msg := api.LocationMessage{Type: "location", Title: locationTitle, Address: locationAddress, Latitude: lat, Longitude: lng}
 		return sendMessage(cmd, client, target, msg, "location", map[string]any{"title": locationTitle, "address": locationAddress, "lat": lat, "lng": lng})
 	}
@@ -149,13 +194,19 @@ func newMessageCmd() *cobra.Command {
 	cmd.AddCommand(newMessagePushCmd())
 	cmd.AddCommand(newMessageBroadcastCmd())
 	cmd.AddCommand(newMessageMulticastCmd())
+	cmd.AddCommand(newMessageSendBatchCmd())
 	cmd.AddCommand(newMessageReplyCmd())
 	cmd.AddCommand(newMessageQuotaCmd())
+	cmd.AddCommand(newMessageEstimateCmd())
 	cmd.AddCommand(newMessageNarrowcastCmd())
 	cmd.AddCommand(newMessageNarrowcastStatusCmd())
 	cmd.AddCommand(newMessageDeliveryStatsCmd())
 	cmd.AddCommand(newMessageValidateCmd())
+	cmd.AddCommand(newMessageLintCmd())
 	cmd.AddCommand(newMessageAggregationCmd())
+	cmd.AddCommand(newMessageTemplateCmd())
+	cmd.AddCommand(newMessageScheduleCmd())
+	cmd.AddCommand(newMessageHistoryCmd())
 
 	return cmd
 }
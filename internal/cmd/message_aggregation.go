@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
 	"github.com/spf13/cobra"
@@ -21,6 +22,24 @@ func newMessageAggregationCmd() *cobra.Command {
 	return cmd
 }
 
+// aggregationUnitPattern matches the custom aggregation unit names LINE
+// accepts: up to 30 ASCII letters, digits, and underscores.
+var aggregationUnitPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,30}$`)
+
+// addAggregationUnitFlag adds --aggregation-unit to a send command.
+func addAggregationUnitFlag(cmd *cobra.Command, unit *string) {
+	cmd.Flags().StringVar(unit, "aggregation-unit", "", "Custom aggregation unit to count the message's insights under, e.g. a campaign code")
+}
+
+// validateAggregationUnit checks a custom aggregation unit name; empty means
+// no unit.
+func validateAggregationUnit(unit string) error {
+	if unit != "" && !aggregationUnitPattern.MatchString(unit) {
+		return withExitCode(ExitUsage, fmt.Errorf("invalid aggregation unit %q: use up to 30 letters, digits, and underscores", unit))
+	}
+	return nil
+}
+
 func newMessageAggregationUsageCmd() *cobra.Command {
 	return newMessageAggregationUsageCmdWithClient(nil)
 }
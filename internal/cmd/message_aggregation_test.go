@@ -281,3 +281,59 @@ func TestMessageAggregationListCmd_Execute_APIError(t *testing.T) {
 		t.Errorf("expected error to contain 'failed to get aggregation unit list', got %v", err)
 	}
 }
+
+func TestMessagePushCmd_AggregationUnit(t *testing.T) {
+	var body map[string]any
+	cmd := newMessagePushCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--to", "U123", "--text", "Sale!", "--aggregation-unit", "spring_sale"})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	units, _ := body["customAggregationUnits"].([]any)
+	if len(units) != 1 || units[0] != "spring_sale" {
+		t.Errorf("expected customAggregationUnits [spring_sale], got %v", body["customAggregationUnits"])
+	}
+}
+
+func TestMessageMulticastCmd_AggregationUnit(t *testing.T) {
+	var body map[string]any
+	cmd := newMessageMulticastCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--to", "U123,U456", "--text", "Sale!", "--aggregation-unit", "spring_sale"})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	units, _ := body["customAggregationUnits"].([]any)
+	if len(units) != 1 || units[0] != "spring_sale" {
+		t.Errorf("expected customAggregationUnits [spring_sale], got %v", body["customAggregationUnits"])
+	}
+}
+
+func TestMessagePushCmd_NoAggregationUnit(t *testing.T) {
+	var body map[string]any
+	cmd := newMessagePushCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--to", "U123", "--text", "Hi"})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := body["customAggregationUnits"]; ok {
+		t.Errorf("customAggregationUnits should be omitted, got %v", body)
+	}
+}
+
+func TestValidateAggregationUnit(t *testing.T) {
+	for _, unit := range []string{"", "spring_sale", "A1", strings.Repeat("x", 30)} {
+		if err := validateAggregationUnit(unit); err != nil {
+			t.Errorf("validateAggregationUnit(%q) = %v, want nil", unit, err)
+		}
+	}
+	for _, unit := range []string{"spring-sale", "has space", "日本", strings.Repeat("x", 31)} {
+		if err := validateAggregationUnit(unit); err == nil {
+			t.Errorf("validateAggregationUnit(%q) = nil, want error", unit)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// loadMessageBundle reads a directory of per-language message variants.
+// Each file is named after a language tag (ja.json, en.json, zh-TW.json)
+// and contains a single Messaging API message object.
+func loadMessageBundle(dir string) (map[string]json.RawMessage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no message variants (*.json) found in %s", dir)
+	}
+
+	bundle := make(map[string]json.RawMessage, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var msg struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			return nil, fmt.Errorf("%s must contain a message object with a \"type\" field", path)
+		}
+		lang := strings.ToLower(strings.TrimSuffix(filepath.Base(path), ".json"))
+		bundle[lang] = json.RawMessage(data)
+	}
+	return bundle, nil
+}
+
+// selectBundleVariant picks the variant for lang, trying the exact tag
+// (zh-tw), then its base language (zh), then fallback. It returns the
+// language of the chosen variant.
+func selectBundleVariant(bundle map[string]json.RawMessage, lang, fallback string) (string, json.RawMessage, error) {
+	var candidates []string
+	if lang = strings.ToLower(lang); lang != "" {
+		candidates = append(candidates, lang)
+		if base, _, ok := strings.Cut(lang, "-"); ok {
+			candidates = append(candidates, base)
+		}
+	}
+	candidates = append(candidates, strings.ToLower(fallback))
+
+	for _, c := range candidates {
+		if msg, ok := bundle[c]; ok {
+			return c, msg, nil
+		}
+	}
+
+	available := make([]string, 0, len(bundle))
+	for l := range bundle {
+		available = append(available, l)
+	}
+	sort.Strings(available)
+	return "", nil, fmt.Errorf("no variant for language %q or fallback %q (available: %s)", lang, fallback, strings.Join(available, ", "))
+}
+
+// bundleMessageType returns the "type" field of a bundle variant.
+func bundleMessageType(msg json.RawMessage) string {
+	var m struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(msg, &m)
+	return m.Type
+}
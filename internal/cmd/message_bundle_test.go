@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func writeTestBundle(t *testing.T, variants map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for lang, text := range variants {
+		msg := `{"type":"text","text":"` + text + `"}`
+		if err := os.WriteFile(filepath.Join(dir, lang+".json"), []byte(msg), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSelectBundleVariant(t *testing.T) {
+	bundle := map[string]json.RawMessage{
+		"ja":    json.RawMessage(`{"type":"text","text":"こんにちは"}`),
+		"en":    json.RawMessage(`{"type":"text","text":"Hello"}`),
+		"zh-tw": json.RawMessage(`{"type":"text","text":"你好"}`),
+	}
+
+	tests := []struct {
+		lang, fallback, want string
+	}{
+		{"ja", "en", "ja"},
+		{"JA", "en", "ja"},
+		{"zh-TW", "en", "zh-tw"},
+		{"en-US", "ja", "en"},
+		{"th", "en", "en"},
+		{"", "ja", "ja"},
+	}
+	for _, tt := range tests {
+		got, _, err := selectBundleVariant(bundle, tt.lang, tt.fallback)
+		if err != nil {
+			t.Errorf("selectBundleVariant(%q, %q): %v", tt.lang, tt.fallback, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("selectBundleVariant(%q, %q) = %q, want %q", tt.lang, tt.fallback, got, tt.want)
+		}
+	}
+
+	_, _, err := selectBundleVariant(bundle, "th", "ko")
+	if err == nil || !strings.Contains(err.Error(), "available: en, ja, zh-tw") {
+		t.Errorf("expected error listing available variants, got %v", err)
+	}
+}
+
+func TestLoadMessageBundle_Invalid(t *testing.T) {
+	if _, err := loadMessageBundle(t.TempDir()); err == nil {
+		t.Error("expected error for empty directory")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"text":"no type"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadMessageBundle(dir); err == nil || !strings.Contains(err.Error(), `"type"`) {
+		t.Errorf("expected missing type error, got %v", err)
+	}
+}
+
+func TestMessagePushCmd_Execute_BundleLangFromProfile(t *testing.T) {
+	dir := writeTestBundle(t, map[string]string{"ja": "こんにちは", "en": "Hello"})
+
+	var capturedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/bot/profile/U123" {
+			_, _ = w.Write([]byte(`{"userId":"U123","displayName":"Taro","language":"ja"}`))
+			return
+		}
+		capturedBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	origOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = origOutput }()
+
+	cmd := newMessagePushCmdWithClient(client)
+	cmd.SetArgs([]string{"--to", "U123", "--bundle", dir, "--lang-from-profile"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var req api.PushMessageRequest
+	if err := json.Unmarshal(capturedBody, &req); err != nil {
+		t.Fatalf("failed to parse request body: %v", err)
+	}
+	msg := req.Messages[0].(map[string]any)
+	if msg["text"] != "こんにちは" {
+		t.Errorf("expected ja variant, got %v", msg["text"])
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if result["lang"] != "ja" || result["type"] != "text" {
+		t.Errorf("unexpected output: %v", result)
+	}
+}
+
+func TestMessagePushCmd_Execute_BundleFallback(t *testing.T) {
+	dir := writeTestBundle(t, map[string]string{"ja": "こんにちは", "en": "Hello"})
+
+	var capturedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/bot/profile/") {
+			// No language: the user hasn't consented to sharing it
+			_, _ = w.Write([]byte(`{"userId":"U123","displayName":"Somchai"}`))
+			return
+		}
+		capturedBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newMessagePushCmdWithClient(client)
+	cmd.SetArgs([]string{"--to", "U123", "--bundle", dir, "--lang-from-profile", "--fallback-lang", "en"})
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(capturedBody), "Hello") {
+		t.Errorf("expected en variant, got %s", capturedBody)
+	}
+	if !strings.Contains(errOut.String(), `sending "en" variant`) {
+		t.Errorf("expected fallback note, got %q", errOut.String())
+	}
+}
+
+func TestMessagePushCmd_Execute_LangRequiresBundle(t *testing.T) {
+	cmd := newMessagePushCmdWithClient(nil)
+	cmd.SetArgs([]string{"--to", "U123", "--text", "hi", "--lang", "ja"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "require --bundle") {
+		t.Errorf("expected --bundle error, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+	"github.com/spf13/cobra"
+)
+
+// sendEstimate is the result of 'line message estimate'.
+type sendEstimate struct {
+	Type       string `json:"type"`
+	Recipients int64  `json:"recipients"`
+	// Source says where Recipients came from; UpperBound is set when it
+	// counts more users than the send may reach
+	Source     string `json:"source"`
+	UpperBound bool   `json:"upperBound,omitempty"`
+	QuotaType  string `json:"quotaType"`
+	Limit      int64  `json:"limit,omitempty"`
+	Used       int64  `json:"used"`
+	Remaining  int64  `json:"remaining,omitempty"`
+	Fits       bool   `json:"fits"`
+	Overage    int64  `json:"overage,omitempty"`
+}
+
+func newMessageEstimateCmd() *cobra.Command {
+	return newMessageEstimateCmdWithClient(nil)
+}
+
+func newMessageEstimateCmdWithClient(client *api.Client) *cobra.Command {
+	var sendType string
+	var usersFile string
+	var to []string
+	var audienceID int64
+	var maxUsers int
+
+	cmd := &cobra.Command{
+		Use:   "estimate",
+		Short: "Estimate whether a send fits in the remaining monthly quota",
+		Long: `Estimate how many messages a send will use and whether they fit in the
+remaining monthly quota, without sending anything.
+
+Every recipient of a send counts as one message, however many message
+objects the send has. Recipients are counted from:
+
+  broadcast   the targeted reaches of the latest follower statistics
+  multicast   the user IDs of --to or --users
+  narrowcast  the audience of --audience, or the user IDs of --users;
+              without either, the targeted reaches, as an upper bound
+              for demographic filters
+
+A warning is printed when the send would exceed the quota. With --output
+json the result has "fits" and "overage", for gating a send in a script.`,
+		Example: `  # Will tonight's broadcast fit?
+  line message estimate --type broadcast
+
+  # A multicast to a list of users
+  line message estimate --type multicast --users users.txt
+
+  # A narrowcast to an audience
+  line message estimate --type narrowcast --audience 12345 --output json | jq -e .fits`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch sendType {
+			case "broadcast":
+				if usersFile != "" || len(to) > 0 || audienceID != 0 {
+					return fmt.Errorf("broadcasts go to every follower: --users, --to, and --audience do not apply")
+				}
+			case "multicast":
+				if usersFile == "" && len(to) == 0 {
+					return fmt.Errorf("multicast needs --to or --users")
+				}
+				if usersFile != "" && len(to) > 0 {
+					return fmt.Errorf("specify either --to or --users, not both")
+				}
+				if audienceID != 0 {
+					return fmt.Errorf("--audience only applies to narrowcast")
+				}
+			case "narrowcast":
+				if usersFile != "" && audienceID != 0 {
+					return fmt.Errorf("specify either --audience or --users, not both")
+				}
+				if len(to) > 0 {
+					return fmt.Errorf("--to only applies to multicast")
+				}
+			case "":
+				return fmt.Errorf("--type is required (broadcast|multicast|narrowcast)")
+			default:
+				return fmt.Errorf("--type must be one of: broadcast, multicast, narrowcast")
+			}
+
+			est := sendEstimate{Type: sendType}
+
+			// Count local user IDs before calling the API, so a bad file
+			// fails fast
+			switch {
+			case len(to) > 0:
+				cleaned, stats, err := userids.Clean(to, userids.Options{SkipInvalid: true})
+				if err != nil {
+					return fmt.Errorf("invalid --to: %w", err)
+				}
+				warnSkippedUsers(cmd, stats, "--to item")
+				est.Recipients, est.Source = int64(len(cleaned)), "--to"
+			case usersFile != "":
+				stats, err := readUsersFile(usersFile, userids.Options{MaxUsers: maxUsers, SkipInvalid: true}, usersFileBatchSize, func([]string) error { return nil })
+				if errors.Is(err, userids.ErrNoUsers) {
+					return fmt.Errorf("no user IDs found in file")
+				}
+				if err != nil {
+					return fmt.Errorf("failed to read users file: %w", err)
+				}
+				warnSkippedUsers(cmd, stats, "line")
+				est.Recipients, est.Source = int64(stats.Unique), usersFile
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			var quota *api.QuotaResponse
+			var consumption *api.ConsumptionResponse
+			err := runConcurrently(cmd.Context(),
+				func(ctx context.Context) error {
+					var err error
+					quota, err = c.GetMessageQuota(ctx)
+					if err != nil {
+						return fmt.Errorf("failed to get quota: %w", err)
+					}
+					return nil
+				},
+				func(ctx context.Context) error {
+					var err error
+					consumption, err = c.GetMessageConsumption(ctx)
+					if err != nil {
+						return fmt.Errorf("failed to get consumption: %w", err)
+					}
+					return nil
+				},
+				func(ctx context.Context) error {
+					switch {
+					case est.Source != "":
+						return nil
+					case audienceID != 0:
+						resp, err := c.GetAudienceGroup(ctx, audienceID)
+						if err != nil {
+							return fmt.Errorf("failed to get audience: %w", err)
+						}
+						if g := resp.AudienceGroup; g != nil && g.AudienceCount != nil {
+							est.Recipients = *g.AudienceCount
+						}
+						est.Source = fmt.Sprintf("audience %d", audienceID)
+						return nil
+					}
+					reaches, date, err := latestTargetedReaches(ctx, c)
+					if err != nil {
+						return err
+					}
+					est.Recipients, est.Source = reaches, "targeted reaches on "+date
+					est.UpperBound = sendType == "narrowcast"
+					return nil
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			est.QuotaType, est.Used = quota.Type, int64(consumption.TotalUsage)
+			est.Fits = true
+			if quota.Type == "limited" {
+				est.Limit = int64(quota.Value)
+				est.Remaining = max(est.Limit-est.Used, 0)
+				est.Overage = max(est.Recipients-est.Remaining, 0)
+				est.Fits = est.Overage == 0
+			}
+			if !est.Fits {
+				warnf(cmd.ErrOrStderr(), "the send needs %d messages but only %d remain this month: %d over quota", est.Recipients, est.Remaining, est.Overage)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(est)
+			}
+
+			w := cmd.OutOrStdout()
+			recipients := fmt.Sprintf("%d (%s)", est.Recipients, est.Source)
+			if est.UpperBound {
+				recipients = fmt.Sprintf("up to %d (%s)", est.Recipients, est.Source)
+			}
+			_, _ = fmt.Fprintf(w, "Type:        %s\n", est.Type)
+			_, _ = fmt.Fprintf(w, "Recipients:  %s\n", recipients)
+			if quota.Type != "limited" {
+				_, _ = fmt.Fprintf(w, "Quota:       Unlimited (%d used)\n", est.Used)
+				_, _ = fmt.Fprintf(w, "Result:      %s\n", palette(w).Green("fits"))
+				return nil
+			}
+			_, _ = fmt.Fprintf(w, "Quota:       %d/month, %d used, %d remaining\n", est.Limit, est.Used, est.Remaining)
+			if est.Fits {
+				_, _ = fmt.Fprintf(w, "After send:  %d remaining\n", est.Remaining-est.Recipients)
+				_, _ = fmt.Fprintf(w, "Result:      %s\n", palette(w).Green("fits"))
+			} else {
+				_, _ = fmt.Fprintf(w, "Result:      %s by %d\n", palette(w).Red("exceeds quota"), est.Overage)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sendType, "type", "", "Send type: broadcast|multicast|narrowcast (required)")
+	cmd.Flags().StringVar(&usersFile, "users", "", "File of user IDs the send goes to, one per line, or - for stdin")
+	cmd.Flags().StringSliceVar(&to, "to", nil, "Comma-separated user IDs of a multicast")
+	cmd.Flags().Int64Var(&audienceID, "audience", 0, "Audience group ID of a narrowcast")
+	addMaxUsersFlag(cmd, &maxUsers)
+
+	return cmd
+}
+
+// latestTargetedReaches returns the number of followers a broadcast reaches
+// from the most recent follower statistics that are ready, and their date.
+// Statistics for a day are ready some hours after it ends.
+func latestTargetedReaches(ctx context.Context, c *api.Client) (int64, string, error) {
+	now := clockNow()
+	for days := 1; days <= 3; days++ {
+		date := now.AddDate(0, 0, -days).Format("20060102")
+		stats, err := c.GetFollowerStats(ctx, date)
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to get follower statistics: %w", err)
+		}
+		if stats.Status != nil && string(*stats.Status) != "ready" {
+			continue
+		}
+		if stats.TargetedReaches != nil {
+			return *stats.TargetedReaches, date, nil
+		}
+		return max(derefInt64(stats.Followers)-derefInt64(stats.Blocks), 0), date, nil
+	}
+	return 0, "", fmt.Errorf("no follower statistics are ready for the last 3 days")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// newEstimateServer serves a limited quota of 1000 with 900 used, follower
+// statistics ready from the day before yesterday, and an audience of 50.
+func newEstimateServer(t *testing.T) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/message/quota":
+			_, _ = w.Write([]byte(`{"type":"limited","value":1000}`))
+		case "/v2/bot/message/quota/consumption":
+			_, _ = w.Write([]byte(`{"totalUsage":900}`))
+		case "/v2/bot/insight/followers":
+			if r.URL.Query().Get("date") == "20240630" {
+				_, _ = w.Write([]byte(`{"status":"unready"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"ready","followers":300,"targetedReaches":250,"blocks":50}`))
+		case "/v2/bot/audienceGroup/12345":
+			_, _ = w.Write([]byte(`{"audienceGroup":{"audienceGroupId":12345,"audienceCount":50}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func runEstimate(t *testing.T, client *api.Client, output string, args ...string) (string, string, error) {
+	t.Helper()
+	oldNow, oldOutput := clockNow, flags.Output
+	t.Cleanup(func() { clockNow, flags.Output = oldNow, oldOutput })
+	clockNow = func() time.Time { return time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC) }
+	flags.Output = output
+
+	var out, errOut bytes.Buffer
+	cmd := newMessageEstimateCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestMessageEstimateCmd_BroadcastOverQuota(t *testing.T) {
+	out, errOut, err := runEstimate(t, newEstimateServer(t), "text", "--type", "broadcast")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"Recipients:  250 (targeted reaches on 20240629)",
+		"Quota:       1000/month, 900 used, 100 remaining",
+		"exceeds quota by 150",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got %q", want, out)
+		}
+	}
+	if !strings.Contains(errOut, "150 over quota") {
+		t.Errorf("expected an overage warning, got %q", errOut)
+	}
+}
+
+func TestMessageEstimateCmd_NarrowcastAudienceJSON(t *testing.T) {
+	out, _, err := runEstimate(t, newEstimateServer(t), "json", "--type", "narrowcast", "--audience", "12345")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var est sendEstimate
+	if err := json.Unmarshal([]byte(out), &est); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if est.Recipients != 50 || !est.Fits || est.Remaining != 100 || est.UpperBound || est.Source != "audience 12345" {
+		t.Errorf("unexpected estimate %+v", est)
+	}
+
+	out, _, err = runEstimate(t, newEstimateServer(t), "json", "--type", "narrowcast")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = json.Unmarshal([]byte(out), &est)
+	if est.Recipients != 250 || !est.UpperBound {
+		t.Errorf("expected targeted reaches as an upper bound, got %+v", est)
+	}
+}
+
+func TestMessageEstimateCmd_MulticastUsersFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "users.txt")
+	ids := "U" + strings.Repeat("a", 32) + "\nU" + strings.Repeat("b", 32) + "\nU" + strings.Repeat("a", 32) + "\nnot-an-id\n"
+	if err := os.WriteFile(file, []byte(ids), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, errOut, err := runEstimate(t, newEstimateServer(t), "text", "--type", "multicast", "--users", file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Recipients:  2 ("+file+")") || !strings.Contains(out, "After send:  98 remaining") {
+		t.Errorf("unexpected output %q", out)
+	}
+	if !strings.Contains(errOut, "skipped 1 invalid user ID") {
+		t.Errorf("expected the invalid line to be reported, got %q", errOut)
+	}
+}
+
+func TestMessageEstimateCmd_Validation(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{}, "--type is required"},
+		{[]string{"--type", "push"}, "--type must be one of"},
+		{[]string{"--type", "multicast"}, "multicast needs --to or --users"},
+		{[]string{"--type", "broadcast", "--audience", "1"}, "do not apply"},
+		{[]string{"--type", "narrowcast", "--to", "U1"}, "--to only applies to multicast"},
+	}
+	for _, tt := range tests {
+		_, _, err := runEstimate(t, nil, "text", tt.args...)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v: expected error containing %q, got %v", tt.args, tt.wantErr, err)
+		}
+	}
+}
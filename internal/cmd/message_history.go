@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
+	"github.com/salmonumbrella/line-official-cli/internal/tokenhealth"
+	"github.com/spf13/cobra"
+)
+
+// recordSends adds every send the client makes to the ledger, the local
+// message history. The send has already happened, so a failure to record
+// it is a warning, not an error.
+func recordSends(w io.Writer, client *api.Client, account string) {
+	client.OnSend(func(s api.SentMessage) {
+		err := ledger.Append(ledger.Entry{
+			Time:        clockNow().UTC(),
+			Account:     account,
+			Type:        s.Type,
+			RequestID:   s.RequestID,
+			Recipients:  s.Recipients,
+			PayloadHash: s.PayloadHash,
+			Summary:     historySummary(s.Messages),
+			Messages:    s.Messages,
+		})
+		if err != nil {
+			warnf(w, "the %s was sent but not recorded in the message history: %v", s.Type, err)
+		}
+	})
+}
+
+// historySummary describes the first of the sent messages, as trackSummary
+// does, noting how many more were sent with it.
+func historySummary(messages json.RawMessage) string {
+	var list []json.RawMessage
+	if err := json.Unmarshal(messages, &list); err != nil || len(list) == 0 {
+		return ""
+	}
+	summary := trackSummary(list[0])
+	if len(list) > 1 {
+		summary += fmt.Sprintf(" (+%d more)", len(list)-1)
+	}
+	return summary
+}
+
+// localAccountKey returns the account that local records such as the
+// message history are kept under, without building a client: the key
+// newAPIClient sets as clientAccountKey.
+func localAccountKey() (string, error) {
+	if creds, ok := envCredentials(); ok && !flags.AccountSet {
+		return "env-" + tokenhealth.TokenID(tokenHealthKey(creds)), nil
+	}
+	return requireAccount(&flags)
+}
+
+// parseHistoryDate parses a --since, --until, or --before date. A date
+// without a time is the start of that day, or with endOfDay its end.
+func parseHistoryDate(flag, s string, endOfDay bool) (time.Time, error) {
+	t, dateOnly, err := parseTime(s, clockNow(), time.Local)
+	if err != nil {
+		return time.Time{}, timeFlagError(flag, s)
+	}
+	if dateOnly && endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+func newMessageHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Look up messages sent from this CLI",
+		Long: `Every message the CLI sends (push, multicast, broadcast, narrowcast,
+reply, and PNP) is recorded in a local history: when it was sent, its type,
+how many recipients the request named, LINE's request ID, a SHA-256 hash of
+the messages, and the messages themselves.
+
+The history is kept per account on this machine only; sends made from
+LINE's console or other tools are not in it. Broadcasts and narrowcasts
+sent with --track are marked in the same history for 'line insight
+message-delivery'.`,
+	}
+
+	cmd.AddCommand(newMessageHistoryListCmd())
+	cmd.AddCommand(newMessageHistoryShowCmd())
+	cmd.AddCommand(newMessageHistoryPurgeCmd())
+
+	return cmd
+}
+
+func newMessageHistoryListCmd() *cobra.Command {
+	var since string
+	var until string
+	var sendType string
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List sent messages",
+		Long: `List the messages sent from this CLI, oldest first. --since and --until
+take a date (YYYY-MM-DD or a day such as yesterday, covering the whole
+day), an RFC 3339 time, or an offset from now such as -7d. --limit keeps
+the most recent sends.`,
+		Example: `  # What did we send last Tuesday?
+  line message history list --since 2026-10-13 --until 2026-10-13
+
+  # Everything sent in the last 24 hours
+  line message history list --since -24h
+
+  # The last 10 broadcasts
+  line message history list --type broadcast --limit 10`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var from, to time.Time
+			var err error
+			if since != "" {
+				if from, err = parseHistoryDate("--since", since, false); err != nil {
+					return err
+				}
+			}
+			if until != "" {
+				if to, err = parseHistoryDate("--until", until, true); err != nil {
+					return err
+				}
+			}
+			if limit < 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--limit must not be negative"))
+			}
+
+			account, err := localAccountKey()
+			if err != nil {
+				return err
+			}
+			all, err := ledger.ForAccount(account)
+			if err != nil {
+				return err
+			}
+			entries := []ledger.Entry{}
+			for _, e := range all {
+				if (!from.IsZero() && e.Time.Before(from)) || (!to.IsZero() && e.Time.After(to)) {
+					continue
+				}
+				if sendType != "" && e.Type != sendType {
+					continue
+				}
+				entries = append(entries, e)
+			}
+			if limit > 0 && len(entries) > limit {
+				entries = entries[len(entries)-limit:]
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+
+			if len(entries) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No sent messages found")
+				return nil
+			}
+			table := NewTable("SENT", "TYPE", "RECIPIENTS", "REQUEST ID", "SUMMARY")
+			for _, e := range entries {
+				table.AddRow(e.Time.Local().Format("2006-01-02 15:04"), e.Type, historyRecipients(e), e.RequestID, e.Summary)
+			}
+			table.Render(cmd.OutOrStdout())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Only sends on or after this date or time")
+	cmd.Flags().StringVar(&until, "until", "", "Only sends on or before this date or time")
+	cmd.Flags().StringVar(&sendType, "type", "", "Only sends of this type: push, multicast, broadcast, narrowcast, reply, or pnp")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Show only the most recent sends (0 for all)")
+
+	return cmd
+}
+
+// historyRecipients describes who a send went to; LINE chooses the
+// recipients of broadcasts and narrowcasts.
+func historyRecipients(e ledger.Entry) string {
+	switch e.Type {
+	case "broadcast":
+		return "all followers"
+	case "narrowcast":
+		return "targeted"
+	}
+	return strconv.Itoa(e.Recipients)
+}
+
+func newMessageHistoryShowCmd() *cobra.Command {
+	var requestID string
+
+	cmd := &cobra.Command{
+		Use:     "show",
+		Short:   "Show a sent message",
+		Example: `  line message history show --request-id xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account, err := localAccountKey()
+			if err != nil {
+				return err
+			}
+			e, err := ledger.Find(account, requestID)
+			if err != nil {
+				return err
+			}
+			if e == nil {
+				return fmt.Errorf("no sent message with request ID %s in this account's history", requestID)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(e)
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Request ID:   %s\n", e.RequestID)
+			_, _ = fmt.Fprintf(out, "Sent:         %s\n", e.Time.Local().Format(time.RFC3339))
+			_, _ = fmt.Fprintf(out, "Type:         %s\n", e.Type)
+			_, _ = fmt.Fprintf(out, "Recipients:   %s\n", historyRecipients(*e))
+			_, _ = fmt.Fprintf(out, "Payload hash: %s\n", e.PayloadHash)
+			if len(e.Messages) > 0 {
+				var messages any
+				if err := json.Unmarshal(e.Messages, &messages); err == nil {
+					data, _ := json.MarshalIndent(messages, "", "  ")
+					_, _ = fmt.Fprintf(out, "Messages:\n%s\n", data)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&requestID, "request-id", "", "Request ID of the send (required)")
+	_ = cmd.MarkFlagRequired("request-id")
+
+	return cmd
+}
+
+func newMessageHistoryPurgeCmd() *cobra.Command {
+	var before string
+
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Remove sent messages from the history",
+		Long: `Remove the account's sent messages from the local history: all of them,
+or with --before only those sent before a date (YYYY-MM-DD), an RFC 3339
+time, or an offset from now such as -90d. Nothing on the LINE platform is changed.`,
+		Example: `  # Keep only this year's sends
+  line message history purge --before 2026-01-01
+
+  line message history purge --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cutoff time.Time
+			if before != "" {
+				var err error
+				if cutoff, err = parseHistoryDate("--before", before, false); err != nil {
+					return err
+				}
+			}
+			account, err := localAccountKey()
+			if err != nil {
+				return err
+			}
+
+			if flags.DryRun {
+				n := 0
+				entries, err := ledger.ForAccount(account)
+				if err != nil {
+					return err
+				}
+				for _, e := range entries {
+					if cutoff.IsZero() || e.Time.Before(cutoff) {
+						n++
+					}
+				}
+				return printHistoryPurge(cmd, n, false)
+			}
+
+			question := "Remove every sent message from the history?"
+			if !cutoff.IsZero() {
+				question = "Remove the sent messages before " + before + " from the history?"
+			}
+			if err := confirmDestructive(cmd, "purge", question); err != nil {
+				return err
+			}
+			var n int
+			if cutoff.IsZero() {
+				n, err = ledger.RemoveAccount(account)
+			} else {
+				n, err = ledger.RemoveBefore(account, cutoff)
+			}
+			if err != nil {
+				return err
+			}
+			return printHistoryPurge(cmd, n, true)
+		},
+	}
+
+	cmd.Flags().StringVar(&before, "before", "", "Only remove sends before this date or time")
+
+	return cmd
+}
+
+func printHistoryPurge(cmd *cobra.Command, n int, removed bool) error {
+	if flags.Output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"count": n, "removed": removed})
+	}
+	verb := "Removed"
+	if !removed {
+		verb = "Would remove"
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %d sent message(s) from the history\n", verb, n)
+	return nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+func TestRecordSends(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	oldNow := clockNow
+	defer func() { clockNow = oldNow }()
+	now := time.Date(2026, 10, 13, 9, 30, 0, 0, time.UTC)
+	clockNow = func() time.Time { return now }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Line-Request-Id", "req-1")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	recordSends(new(bytes.Buffer), client, "shop")
+
+	messages := []any{
+		api.TextMessage{Type: "text", Text: "Spring sale starts today"},
+		map[string]any{"type": "sticker", "packageId": "1", "stickerId": "1"},
+	}
+	if err := client.SendMessages(context.Background(), "multicast", "", []string{testUserID(1), testUserID(2)}, messages); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ledger.ForAccount("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Type != "multicast" || e.RequestID != "req-1" || e.Recipients != 2 || !e.Time.Equal(now) || len(e.PayloadHash) != 64 {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Summary != "Spring sale starts today (+1 more)" {
+		t.Errorf("Summary = %q", e.Summary)
+	}
+}
+
+func TestMessageHistoryCmds(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.Output, flags.Yes = "shop", "text", true
+
+	tuesday := time.Date(2026, 10, 13, 9, 30, 0, 0, time.Local)
+	for _, e := range []ledger.Entry{
+		{Time: tuesday.AddDate(0, 0, -1), Account: "shop", Type: "push", RequestID: "req-mon", Recipients: 1, Summary: "Hi"},
+		{Time: tuesday, Account: "shop", Type: "broadcast", RequestID: "req-tue", Summary: "Sale", Messages: json.RawMessage(`[{"type":"text","text":"Sale"}]`)},
+		{Time: tuesday.Add(2 * time.Hour), Account: "shop", Type: "multicast", RequestID: "req-tue-2", Recipients: 300, Summary: "VIP"},
+		{Time: tuesday, Account: "other", Type: "push", RequestID: "req-other"},
+	} {
+		if err := ledger.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(t *testing.T, cmd *cobra.Command, args ...string) string {
+		t.Helper()
+		cmd.SetArgs(args)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(new(bytes.Buffer))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out.String()
+	}
+
+	t.Run("list", func(t *testing.T) {
+		out := run(t, newMessageHistoryListCmd(), "--since", "2026-10-13", "--until", "2026-10-13")
+		if !strings.Contains(out, "req-tue") || !strings.Contains(out, "all followers") || !strings.Contains(out, "300") {
+			t.Errorf("expected Tuesday's sends, got: %s", out)
+		}
+		if strings.Contains(out, "req-mon") || strings.Contains(out, "req-other") {
+			t.Errorf("unexpected sends listed: %s", out)
+		}
+	})
+
+	t.Run("list json", func(t *testing.T) {
+		flags.Output = "json"
+		defer func() { flags.Output = "text" }()
+		var entries []ledger.Entry
+		if err := json.Unmarshal([]byte(run(t, newMessageHistoryListCmd(), "--type", "push")), &entries); err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].RequestID != "req-mon" {
+			t.Errorf("unexpected entries: %+v", entries)
+		}
+	})
+
+	t.Run("show", func(t *testing.T) {
+		out := run(t, newMessageHistoryShowCmd(), "--request-id", "req-tue")
+		if !strings.Contains(out, "Type:         broadcast") || !strings.Contains(out, `"text": "Sale"`) {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		cmd := newMessageHistoryShowCmd()
+		cmd.SetArgs([]string{"--request-id", "req-other"})
+		cmd.SetOut(new(bytes.Buffer))
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected another account's send not to be found")
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		if out := run(t, newMessageHistoryPurgeCmd(), "--before", "2026-10-13"); !strings.Contains(out, "Removed 1 sent message(s)") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if n, _ := ledger.CountAccount("shop"); n != 2 {
+			t.Errorf("expected 2 sends left, got %d", n)
+		}
+		run(t, newMessageHistoryPurgeCmd())
+		if n, _ := ledger.CountAccount("shop"); n != 0 {
+			t.Errorf("expected no sends left, got %d", n)
+		}
+		if n, _ := ledger.CountAccount("other"); n != 1 {
+			t.Errorf("expected the other account's send to be kept, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/messagespec"
+	"github.com/spf13/cobra"
+)
+
+// lintFinding is a finding of 'line message lint' located in its file.
+type lintFinding struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	messagespec.Finding
+}
+
+func newMessageLintCmd() *cobra.Command {
+	var files []string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check message payloads against LINE's limits offline",
+		Long: `Check message payloads against the Messaging API's limits without calling
+the API, so they can be checked in CI without credentials.
+
+Each file may be a messages array, a single message object, or a whole
+request body with a "messages" field. Every problem is reported with its
+rule, file line, and property path:
+
+  message-count  a request has 1 to 5 messages
+  message-type   supported message, template, and action types and their
+                 required fields
+  text-length    texts, labels, and postback data within their limits
+  alt-text       template, flex, and imagemap messages have an altText
+  url-scheme     https image URLs; http, https, line, or tel URI actions
+  action-count   actions and columns per template, carousel, and imagemap
+  flex-depth     flex boxes nested too deeply (a warning)
+  quick-reply    1 to 13 quick reply items of supported action types
+
+The command fails when any error is found. --format sarif writes a SARIF
+2.1.0 log for code scanning annotations; otherwise --output applies.
+'line message validate' asks LINE itself, which also catches what cannot be
+checked offline.`,
+		Example: `  # Lint a payload
+  line message lint --file payload.json
+
+  # Lint every payload of a campaign and annotate the pull request
+  line message lint --file payloads/welcome.json --file payloads/sale.json --format sarif > lint.sarif`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(files) == 0 {
+				return fmt.Errorf("--file is required")
+			}
+			if format != "" && format != "sarif" {
+				return withExitCode(ExitUsage, fmt.Errorf("invalid --format %q: use sarif", format))
+			}
+
+			var findings []lintFinding
+			for _, file := range files {
+				data, err := readFileOrStdin(file)
+				if err != nil {
+					return fmt.Errorf("failed to read file: %w", err)
+				}
+				found, err := lintPayload(file, data)
+				if err != nil {
+					return fmt.Errorf("%s: %w", file, err)
+				}
+				findings = append(findings, found...)
+			}
+
+			errorCount := 0
+			for _, f := range findings {
+				if f.Level == messagespec.LevelError {
+					errorCount++
+				}
+			}
+
+			switch {
+			case format == "sarif":
+				if err := writeSARIF(cmd.OutOrStdout(), findings); err != nil {
+					return err
+				}
+			case flags.Output == "json":
+				if findings == nil {
+					findings = []lintFinding{}
+				}
+				result := map[string]any{"valid": errorCount == 0, "files": len(files), "findings": findings}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			default:
+				for _, f := range findings {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s:%d: %s: %s: %s [%s]\n", f.File, f.Line, f.Level, f.Path, f.Message, f.Rule)
+				}
+				if len(findings) == 0 {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No problems found in %d file(s)\n", len(files))
+				}
+			}
+
+			if errorCount > 0 {
+				return fmt.Errorf("%d problem(s) found", errorCount)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil, "Payload JSON file to lint, or - for stdin (repeatable, required)")
+	cmd.Flags().StringVar(&format, "format", "", "Report format: sarif (default: --output)")
+
+	return cmd
+}
+
+// lintPayload lints the messages in data and locates each finding on a
+// line of file.
+func lintPayload(file string, data []byte) ([]lintFinding, error) {
+	messages, err := parseValidateMessages(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Findings use paths from the messages array; map them onto the shape
+	// of the file.
+	root := ""
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		root = "messages"
+	} else {
+		var body struct {
+			Messages json.RawMessage `json:"messages"`
+		}
+		if json.Unmarshal(trimmed, &body) == nil && body.Messages == nil {
+			root = "messages[0]"
+		}
+	}
+	lines := jsonLines(data, root)
+
+	var findings []lintFinding
+	for _, f := range messagespec.Lint(messages) {
+		findings = append(findings, lintFinding{File: file, Line: lineOf(lines, f.Path), Finding: f})
+	}
+	return findings, nil
+}
+
+// jsonLines maps the property path of each value in data, like
+// messages[0].text, to the line it starts on. root is the path of the
+// top-level value.
+func jsonLines(data []byte, root string) map[string]int {
+	lines := map[string]int{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	lineAt := func(off int64) int {
+		for off < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[off]) >= 0 {
+			off++
+		}
+		return bytes.Count(data[:off], []byte("\n")) + 1
+	}
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		lines[path] = lineAt(dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child, _ := key.(string)
+				if path != "" {
+					child = path + "." + child
+				}
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	_ = walk(root)
+	return lines
+}
+
+// lineOf returns the line of path, or of its nearest located parent when
+// the property is missing.
+func lineOf(lines map[string]int, path string) int {
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return line
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return 1
+}
+
+// writeSARIF writes findings as a SARIF 2.1.0 log.
+func writeSARIF(w io.Writer, findings []lintFinding) error {
+	rules := make([]map[string]any, 0, len(messagespec.Rules))
+	for _, r := range messagespec.Rules {
+		rules = append(rules, map[string]any{
+			"id":               r.ID,
+			"shortDescription": map[string]any{"text": r.Description},
+		})
+	}
+	results := make([]map[string]any, 0, len(findings))
+	for _, f := range findings {
+		results = append(results, map[string]any{
+			"ruleId":  f.Rule,
+			"level":   f.Level,
+			"message": map[string]any{"text": f.Path + ": " + f.Message},
+			"locations": []map[string]any{{
+				"physicalLocation": map[string]any{
+					"artifactLocation": map[string]any{"uri": filepath.ToSlash(f.File)},
+					"region":           map[string]any{"startLine": f.Line},
+				},
+				"logicalLocations": []map[string]any{{"fullyQualifiedName": f.Path}},
+			}},
+		})
+	}
+	log := map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []map[string]any{{
+			"tool": map[string]any{"driver": map[string]any{
+				"name":           "line message lint",
+				"version":        version,
+				"informationUri": "https://github.com/salmonumbrella/line-official-cli",
+				"rules":          rules,
+			}},
+			"results": results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
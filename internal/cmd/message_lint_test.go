@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLintFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "payload.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const lintBadPayload = `{
+  "to": "U123",
+  "messages": [
+    {"type": "text", "text": "Hello"},
+    {
+      "type": "template",
+      "template": {
+        "type": "buttons",
+        "text": "Pick",
+        "actions": [
+          {"type": "uri", "label": "Go", "uri": "ftp://example.com"}
+        ]
+      }
+    }
+  ]
+}`
+
+func TestJSONLines(t *testing.T) {
+	lines := jsonLines([]byte(lintBadPayload), "")
+	for path, want := range map[string]int{
+		"to":                                  2,
+		"messages[0]":                         4,
+		"messages[1]":                         5,
+		"messages[1].template.actions[0].uri": 11,
+	} {
+		if lines[path] != want {
+			t.Errorf("line of %s = %d, want %d", path, lines[path], want)
+		}
+	}
+	if got := lineOf(lines, "messages[1].altText"); got != 5 {
+		t.Errorf("expected a missing property on its parent's line, got %d", got)
+	}
+
+	lines = jsonLines([]byte("[\n  {\"type\": \"text\"}\n]"), "messages")
+	if lines["messages[0].type"] != 2 {
+		t.Errorf("expected array elements under messages, got %v", lines)
+	}
+}
+
+func TestMessageLintCmd_Text(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+	file := writeLintFile(t, lintBadPayload)
+
+	var out bytes.Buffer
+	cmd := newMessageLintCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--file", file})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "2 problem(s) found") {
+		t.Fatalf("expected 2 problems, got %v", err)
+	}
+	for _, want := range []string{
+		file + ":5: error: messages[1].altText: altText is required [alt-text]",
+		file + ":11: error: messages[1].template.actions[0].uri: uri must use http, https, line, or tel",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got %q", want, out.String())
+		}
+	}
+
+	out.Reset()
+	cmd = newMessageLintCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--file", writeLintFile(t, `{"type":"text","text":"Hi"}`)})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "No problems found in 1 file(s)") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
+
+func TestMessageLintCmd_SARIF(t *testing.T) {
+	file := writeLintFile(t, lintBadPayload)
+
+	var out bytes.Buffer
+	cmd := newMessageLintCmd()
+	cmd.SetOut(&out)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs([]string{"--file", file, "--format", "sarif"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected the lint to fail")
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, out.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) == 0 {
+		t.Fatalf("unexpected SARIF log %s", out.String())
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 || results[0].RuleID != "alt-text" || results[0].Level != "error" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	loc := results[1].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != filepath.ToSlash(file) || loc.Region.StartLine != 11 {
+		t.Errorf("unexpected location %+v", loc)
+	}
+}
+
+func TestMessageLintCmd_Validation(t *testing.T) {
+	cmd := newMessageLintCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--file is required") {
+		t.Errorf("expected --file error, got %v", err)
+	}
+
+	cmd = newMessageLintCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--file", writeLintFile(t, `[]`), "--format", "xml"})
+	if err := cmd.Execute(); err == nil || ExitCode(err) != ExitUsage {
+		t.Errorf("expected a usage error for --format xml, got %v", err)
+	}
+}
@@ -15,22 +15,76 @@ func newMessageNarrowcastCmd() *cobra.Command {
 func newMessageNarrowcastCmdWithClient(client *api.Client) *cobra.Command {
 	var text string
 	var audienceID int64
+	var filterGenders []string
+	var filterAges []string
+	var filterRegions []string
+	var filterOS []string
+	var filterFile string
+	var overrideFreeze string
+	var quickReply quickReplyFlags
+	var track trackFlags
 
 	cmd := &cobra.Command{
 		Use:   "narrowcast",
 		Short: "Send message to targeted users",
 		Long: `Send a message to users matching specific criteria.
-Can target an audience group or use demographic filters.`,
+Can target an audience group or use demographic filters.
+
+Demographic filter flags build the filter object for you. Values within a
+flag are alternatives (any of them matches); different flags must all match.
+Age ranges are inclusive and follow LINE's 5-year buckets (15-19, 20-24, ...
+70+). Regions are Japanese prefecture names or LINE area codes (jp_13).
+
+For conditions the flags can't express (e.g. "not"), use --filter-file with
+a JSON filter object.`,
 		Example: `  # Send to an audience group
   line message narrowcast --text "Special offer!" --audience 12345678
 
+  # Women aged 20-34 in Tokyo or Osaka on iOS
+  line message narrowcast --text "Hi!" --filter-gender female --filter-age 20-34 \
+    --filter-region tokyo,osaka --filter-os ios
+
+  # Advanced filter from a file
+  line message narrowcast --text "Hi!" --filter-file filter.json
+
   # Check narrowcast progress
-  line message narrowcast-status --request-id <id>`,
+  line message narrowcast-status --request-id <id>
+
+  # Record the request ID to fetch delivery results later
+  line message narrowcast --text "Hi!" --audience 12345678 --track`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if text == "" {
 				return fmt.Errorf("--text is required")
 			}
 
+			var demographic *api.DemographicFilter
+			builderSet := len(filterGenders) > 0 || len(filterAges) > 0 || len(filterRegions) > 0 || len(filterOS) > 0
+			if filterFile != "" {
+				if builderSet {
+					return fmt.Errorf("--filter-file cannot be combined with --filter-gender, --filter-age, --filter-region, or --filter-os")
+				}
+				var err error
+				demographic, err = readDemographicFilterFile(filterFile)
+				if err != nil {
+					return err
+				}
+			} else {
+				var err error
+				demographic, err = buildDemographicFilter(filterGenders, filterAges, filterRegions, filterOS)
+				if err != nil {
+					return err
+				}
+			}
+
+			message, err := attachQuickReplyFlags(api.TextMessage{Type: "text", Text: text}, &quickReply)
+			if err != nil {
+				return err
+			}
+
+			if err := enforceFreeze(cmd, "narrowcast", overrideFreeze); err != nil {
+				return err
+			}
+
 			c := client
 			if c == nil {
 				var err error
@@ -40,10 +94,21 @@ Can target an audience group or use demographic filters.`,
 				}
 			}
 
-			resp, err := c.NarrowcastTextMessage(cmd.Context(), text, audienceID)
+			req := api.NarrowcastMessageRequest{
+				Messages: []any{message},
+			}
+			if audienceID > 0 {
+				req.Recipient = &api.NarrowcastRecipient{Type: "audience", AudienceGroupID: audienceID}
+			}
+			if demographic != nil {
+				req.Filter = &api.NarrowcastFilter{Demographic: demographic}
+			}
+
+			resp, err := c.Narrowcast(cmd.Context(), req)
 			if err != nil {
 				return fmt.Errorf("failed to narrowcast: %w", err)
 			}
+			trackSend(cmd, &track, "narrowcast", resp.RequestID, message)
 
 			if flags.Output == "json" {
 				enc := json.NewEncoder(cmd.OutOrStdout())
@@ -58,6 +123,14 @@ Can target an audience group or use demographic filters.`,
 
 	cmd.Flags().StringVar(&text, "text", "", "Text message content (required)")
 	cmd.Flags().Int64Var(&audienceID, "audience", 0, "Audience group ID to target")
+	cmd.Flags().StringSliceVar(&filterGenders, "filter-gender", nil, "Only users of these genders (male, female)")
+	cmd.Flags().StringSliceVar(&filterAges, "filter-age", nil, "Only users in these inclusive age ranges (e.g. 20-34, 50-, -19)")
+	cmd.Flags().StringSliceVar(&filterRegions, "filter-region", nil, "Only users in these regions (prefecture names like tokyo, or area codes like jp_13)")
+	cmd.Flags().StringSliceVar(&filterOS, "filter-os", nil, "Only users on these OSes (ios, android)")
+	cmd.Flags().StringVar(&filterFile, "filter-file", "", "JSON file with a demographic filter object (for advanced and/or/not nesting), or - for stdin")
+	addFreezeOverrideFlag(cmd, &overrideFreeze)
+	quickReply.addFlags(cmd)
+	track.addFlags(cmd)
 	_ = cmd.MarkFlagRequired("text")
 
 	return cmd
@@ -99,7 +172,7 @@ func newMessageNarrowcastStatusCmdWithClient(client *api.Client) *cobra.Command
 				return enc.Encode(progress)
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Phase: %v\n", progress["phase"])
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Phase: %s\n", colorStatus(cmd.OutOrStdout(), fmt.Sprint(progress["phase"])))
 			if count, ok := progress["successCount"]; ok {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Success: %v\n", count)
 			}
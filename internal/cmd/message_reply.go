@@ -17,25 +17,62 @@ func newMessageReplyCmdWithClient(client *api.Client) *cobra.Command {
 	var text string
 	var flexJSON string
 	var altText string
+	var sticker string
+	var quickReply quickReplyFlags
 
 	cmd := &cobra.Command{
 		Use:   "reply",
 		Short: "Reply to a webhook event",
-		Long:  "Send a reply message using a reply token from a webhook event. Reply tokens expire after 1 minute.",
+		Long:  "Send a text, flex, or sticker reply using a reply token from a webhook event. Reply tokens expire after 1 minute.",
 		Example: `  # Reply with text
   line message reply --token <replyToken> --text "Thanks for your message!"
 
+  # Reply with text and a LINE emoji
+  line message reply --token <replyToken> --text "Thanks {emoji:5ac1bfd5040ab15980c9b435:001}"
+
   # Reply with flex message
-  line message reply --token <replyToken> --flex '{"type":"bubble",...}'`,
+  line message reply --token <replyToken> --flex '{"type":"bubble",...}'
+
+  # Reply with a sticker
+  line message reply --token <replyToken> --sticker 446:1988`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if replyToken == "" {
 				return fmt.Errorf("--token is required")
 			}
-			if text == "" && flexJSON == "" {
-				return fmt.Errorf("specify --text or --flex")
+			set := 0
+			for _, v := range []string{text, flexJSON, sticker} {
+				if v != "" {
+					set++
+				}
+			}
+			if set == 0 {
+				return fmt.Errorf("specify --text, --flex, or --sticker")
+			}
+			if set > 1 {
+				return fmt.Errorf("specify only one of --text, --flex, or --sticker")
+			}
+
+			var message any
+			switch {
+			case text != "":
+				msg, err := newTextMessage(text)
+				if err != nil {
+					return err
+				}
+				message = msg
+			case flexJSON != "":
+				message = api.FlexMessage{Type: "flex", AltText: altText, Contents: json.RawMessage(flexJSON)}
+			default:
+				packageID, stickerID, err := parseStickerFlags(sticker, "", "")
+				if err != nil {
+					return err
+				}
+				message = api.StickerMessage{Type: "sticker", PackageID: packageID, StickerID: stickerID}
 			}
-			if text != "" && flexJSON != "" {
-				return fmt.Errorf("specify either --text or --flex, not both")
+
+			message, err := attachQuickReplyFlags(message, &quickReply)
+			if err != nil {
+				return err
 			}
 
 			c := client
@@ -47,14 +84,8 @@ func newMessageReplyCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
-			if text != "" {
-				if err := c.ReplyTextMessage(cmd.Context(), replyToken, text); err != nil {
-					return fmt.Errorf("failed to reply: %w", err)
-				}
-			} else {
-				if err := c.ReplyFlexMessage(cmd.Context(), replyToken, altText, json.RawMessage(flexJSON)); err != nil {
-					return fmt.Errorf("failed to reply: %w", err)
-				}
+			if err := c.ReplyMessage(cmd.Context(), replyToken, message); err != nil {
+				return fmt.Errorf("failed to reply: %w", err)
 			}
 
 			if flags.Output == "json" {
@@ -72,6 +103,8 @@ func newMessageReplyCmdWithClient(client *api.Client) *cobra.Command {
 	cmd.Flags().StringVar(&text, "text", "", "Text message content")
 	cmd.Flags().StringVar(&flexJSON, "flex", "", "Flex message JSON")
 	cmd.Flags().StringVar(&altText, "alt-text", "Flex message", "Alt text for flex messages")
+	cmd.Flags().StringVar(&sticker, "sticker", "", "Sticker as <packageId>:<stickerId>, e.g. 446:1988")
+	quickReply.addFlags(cmd)
 	_ = cmd.MarkFlagRequired("token")
 
 	return cmd
@@ -171,8 +171,8 @@ func TestMessageReplyCmd_MissingMessage(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for missing message content")
 	}
-	if !strings.Contains(err.Error(), "specify --text or --flex") {
-		t.Errorf("expected error to contain 'specify --text or --flex', got %v", err)
+	if !strings.Contains(err.Error(), "specify --text, --flex, or --sticker") {
+		t.Errorf("expected error to contain 'specify --text, --flex, or --sticker', got %v", err)
 	}
 }
 
@@ -190,8 +190,8 @@ func TestMessageReplyCmd_BothTextAndFlex(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for specifying both --text and --flex")
 	}
-	if !strings.Contains(err.Error(), "not both") {
-		t.Errorf("expected error to contain 'not both', got %v", err)
+	if !strings.Contains(err.Error(), "specify only one of") {
+		t.Errorf("expected error to contain 'specify only one of', got %v", err)
 	}
 }
 
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
 	"github.com/spf13/cobra"
@@ -14,7 +15,7 @@ func newMessagePushCmd() *cobra.Command {
 
 // newMessagePushCmdWithClient creates a push message command with an optional API client for testing.
 func newMessagePushCmdWithClient(client *api.Client) *cobra.Command {
-	var userID string
+	var to []string
 	var text string
 	var flexJSON string
 	var altText string
@@ -22,86 +23,191 @@ func newMessagePushCmdWithClient(client *api.Client) *cobra.Command {
 	var previewURL string
 	var packageID string
 	var stickerID string
+	var sticker string
 	var videoURL string
 	var audioURL string
 	var duration int
+	var location string
 	var locationTitle string
 	var locationAddress string
 	var lat float64
 	var lng float64
+	var bundleDir string
+	var lang string
+	var langFromProfile bool
+	var fallbackLang string
+	var overrideFreeze string
+	var quickReply quickReplyFlags
+	var aggregationUnit string
 
 	cmd := &cobra.Command{
 		Use:   "push",
 		Short: "Push a message to a user",
-		Long:  "Send a text, flex, image, video, audio, location, or sticker message directly to a specific user.",
+		Long: `Send a text, flex, image, video, audio, location, or sticker message directly to a specific user.
+
+--to may be repeated or comma-separated, and @file reads user IDs from a
+file, one per line. With more than one recipient the message is sent as a
+multicast (max 500 users).`,
 		Example: `  # Send a text message
   line message push --to U1234567890abcdef --text "Hello!"
 
+  # Send to several users, or to every user in a file, as a multicast
+  line message push --to U1234567890abcdef --to U0987654321fedcba --text "Hello!"
+  line message push --to @users.txt --text "Hello!"
+
   # Send a flex message from JSON
   line message push --to U1234567890abcdef --flex '{"type":"bubble",...}'
 
   # Send an image message
-  line message push --to U1234567890abcdef --image https://example.com/image.jpg
+  line message push --to U1234567890abcdef --image-url https://example.com/image.jpg
 
   # Send a video message
-  line message push --to U1234567890abcdef --video https://example.com/video.mp4 --preview https://example.com/preview.jpg
+  line message push --to U1234567890abcdef --video-url https://example.com/video.mp4 --preview-url https://example.com/preview.jpg
 
   # Send an audio message
-  line message push --to U1234567890abcdef --audio https://example.com/audio.m4a --duration 60000
+  line message push --to U1234567890abcdef --audio-url https://example.com/audio.m4a --duration 60000
 
   # Send a location message
-  line message push --to U1234567890abcdef --location-title "Tokyo Tower" --location-address "4-2-8 Shiba-koen, Minato-ku, Tokyo" --lat 35.6586 --lng 139.7454
+  line message push --to U1234567890abcdef --location "35.6586,139.7454,Tokyo Tower,4-2-8 Shiba-koen, Minato-ku, Tokyo"
 
   # Send a sticker
-  line message push --to U1234567890abcdef --sticker-package 446 --sticker-id 1988`,
+  line message push --to U1234567890abcdef --sticker 446:1988
+
+  # Send the variant matching the user's profile language (messages/ja.json, en.json, ...)
+  line message push --to U1234567890abcdef --bundle ./messages --lang-from-profile
+
+  # Count the message's insights under a campaign code
+  line message push --to U1234567890abcdef --text "Sale!" --aggregation-unit spring_sale`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if userID == "" {
-				return fmt.Errorf("--to is required: specify a user ID")
+			recipients, err := expandRecipients(to)
+			if err != nil {
+				return err
+			}
+			target := messageTarget{AggregationUnit: aggregationUnit}
+			if err := setRecipients(&target, recipients); err != nil {
+				return err
+			}
+
+			if err := applyLocationFlag(location, &lat, &lng, &locationTitle, &locationAddress); err != nil {
+				return err
 			}
 
 			// Validate exactly one message type is specified
 			if err := requireExactlyOneFlag([]FlagCheck{
 				{Name: "--text", Set: text != ""},
 				{Name: "--flex", Set: flexJSON != ""},
-				{Name: "--image", Set: imageURL != ""},
-				{Name: "--video", Set: videoURL != ""},
-				{Name: "--audio", Set: audioURL != ""},
-				{Name: "--location-*", Set: locationTitle != "" || locationAddress != "" || lat != 0 || lng != 0},
-				{Name: "--sticker-*", Set: packageID != "" || stickerID != ""},
+				{Name: "--image-url", Set: imageURL != ""},
+				{Name: "--video-url", Set: videoURL != ""},
+				{Name: "--audio-url", Set: audioURL != ""},
+				{Name: "--location", Set: locationTitle != "" || locationAddress != "" || lat != 0 || lng != 0},
+				{Name: "--sticker", Set: sticker != "" || packageID != "" || stickerID != ""},
+				{Name: "--bundle", Set: bundleDir != ""},
 			}); err != nil {
 				return err
 			}
 
-			// Validate sticker flags are used together
-			if (packageID != "" && stickerID == "") || (packageID == "" && stickerID != "") {
-				return fmt.Errorf("--sticker-package and --sticker-id must be used together")
+			packageID, stickerID, err := parseStickerFlags(sticker, packageID, stickerID)
+			if err != nil {
+				return err
+			}
+			quickReplyItems, err := quickReply.load()
+			if err != nil {
+				return err
+			}
+			if bundleDir == "" && (lang != "" || langFromProfile) {
+				return fmt.Errorf("--lang and --lang-from-profile require --bundle")
+			}
+			if lang != "" && langFromProfile {
+				return fmt.Errorf("--lang and --lang-from-profile cannot be used together")
+			}
+			if bundleDir != "" && target.Type != "push" {
+				return fmt.Errorf("--bundle sends to one user: give a single --to")
+			}
+			if err := validateAggregationUnit(aggregationUnit); err != nil {
+				return err
+			}
+
+			if err := enforceFreeze(cmd, target.Type, overrideFreeze); err != nil {
+				return err
 			}
 
-			target := messageTarget{Type: "push", UserID: userID}
+			target.QuickReply = quickReplyItems
+			if bundleDir != "" {
+				return pushBundleVariant(cmd, client, target, bundleDir, lang, langFromProfile, fallbackLang)
+			}
 			return dispatchMessage(cmd, client, target, text, flexJSON, altText, imageURL, previewURL, videoURL, audioURL, duration, locationTitle, locationAddress, lat, lng, packageID, stickerID)
 		},
 	}
 
-	cmd.Flags().StringVar(&userID, "to", "", "User ID to send message to (required)")
+	cmd.Flags().StringSliceVar(&to, "to", nil, "User ID to send to, or @file of IDs; repeat for a multicast (required, max 500)")
 	cmd.Flags().StringVar(&text, "text", "", "Text message content")
 	cmd.Flags().StringVar(&flexJSON, "flex", "", "Flex message JSON")
 	cmd.Flags().StringVar(&altText, "alt-text", "Flex message", "Alt text for flex messages (shown in notifications)")
-	cmd.Flags().StringVar(&imageURL, "image", "", "Image URL to send")
-	cmd.Flags().StringVar(&videoURL, "video", "", "Video URL to send")
-	cmd.Flags().StringVar(&audioURL, "audio", "", "Audio URL to send")
-	cmd.Flags().IntVar(&duration, "duration", 0, "Audio duration in milliseconds (required for --audio)")
-	cmd.Flags().StringVar(&previewURL, "preview", "", "Preview image URL (required for --video, defaults to --image for images)")
+	cmd.Flags().StringVar(&imageURL, "image-url", "", "HTTPS URL of a JPEG or PNG image to send (max 10 MB)")
+	cmd.Flags().StringVar(&videoURL, "video-url", "", "HTTPS URL of an MP4 video to send (max 200 MB)")
+	cmd.Flags().StringVar(&audioURL, "audio-url", "", "HTTPS URL of an M4A audio file to send (max 200 MB)")
+	cmd.Flags().IntVar(&duration, "duration", 0, "Audio duration in milliseconds (required for --audio-url)")
+	cmd.Flags().StringVar(&previewURL, "preview-url", "", "HTTPS URL of a JPEG or PNG preview image, max 1 MB (required for --video-url, defaults to --image-url)")
+	cmd.Flags().StringVar(&location, "location", "", "Location as \"lat,lng,title,address\"")
 	cmd.Flags().StringVar(&locationTitle, "location-title", "", "Location title")
 	cmd.Flags().StringVar(&locationAddress, "location-address", "", "Location address")
 	cmd.Flags().Float64Var(&lat, "lat", 0, "Latitude for location message")
 	cmd.Flags().Float64Var(&lng, "lng", 0, "Longitude for location message")
+	cmd.Flags().StringVar(&sticker, "sticker", "", "Sticker as <packageId>:<stickerId>, e.g. 446:1988 (see: line sticker list-packages)")
 	cmd.Flags().StringVar(&packageID, "sticker-package", "", "Sticker package ID")
 	cmd.Flags().StringVar(&stickerID, "sticker-id", "", "Sticker ID")
+	cmd.Flags().StringVar(&bundleDir, "bundle", "", "Directory of per-language message files (ja.json, en.json, ...)")
+	cmd.Flags().StringVar(&lang, "lang", "", "Language variant to send from --bundle")
+	cmd.Flags().BoolVar(&langFromProfile, "lang-from-profile", false, "Pick the --bundle variant from the recipient's profile language")
+	cmd.Flags().StringVar(&fallbackLang, "fallback-lang", "en", "Variant to send when no variant matches the language")
+	addFreezeOverrideFlag(cmd, &overrideFreeze)
+	quickReply.addFlags(cmd)
+	addAggregationUnitFlag(cmd, &aggregationUnit)
+	cmd.Flags().SetNormalizeFunc(normalizeMediaFlags)
 	_ = cmd.MarkFlagRequired("to")
 
 	return cmd
 }
 
+// pushBundleVariant sends the message bundle variant that matches the
+// requested (or recipient's profile) language.
+func pushBundleVariant(cmd *cobra.Command, client *api.Client, target messageTarget, bundleDir, lang string, langFromProfile bool, fallbackLang string) error {
+	bundle, err := loadMessageBundle(bundleDir)
+	if err != nil {
+		return err
+	}
+
+	if client == nil {
+		client, err = newAPIClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	if langFromProfile {
+		profile, err := client.GetUserProfile(cmd.Context(), target.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get profile language: %w", err)
+		}
+		// Language is empty when the user hasn't consented to sharing it
+		lang = profile.Language
+	}
+
+	chosen, msg, err := selectBundleVariant(bundle, lang, fallbackLang)
+	if err != nil {
+		return err
+	}
+	if flags.Output != "json" && chosen != strings.ToLower(lang) {
+		if lang == "" {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Recipient language unknown; sending %q variant\n", chosen)
+		} else {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "No %q variant; sending %q variant\n", lang, chosen)
+		}
+	}
+
+	return sendMessage(cmd, client, target, msg, bundleMessageType(msg), map[string]any{"lang": chosen})
+}
+
 // newMessageBroadcastCmd creates a broadcast message command.
 func newMessageBroadcastCmd() *cobra.Command {
 	return newMessageBroadcastCmdWithClient(nil)
@@ -116,13 +222,18 @@ func newMessageBroadcastCmdWithClient(client *api.Client) *cobra.Command {
 	var previewURL string
 	var packageID string
 	var stickerID string
+	var sticker string
 	var videoURL string
 	var audioURL string
 	var duration int
+	var location string
 	var locationTitle string
 	var locationAddress string
 	var lat float64
 	var lng float64
+	var overrideFreeze string
+	var quickReply quickReplyFlags
+	var track trackFlags
 
 	cmd := &cobra.Command{
 		Use:   "broadcast",
@@ -135,49 +246,61 @@ func newMessageBroadcastCmdWithClient(client *api.Client) *cobra.Command {
   line message broadcast --flex '{"type":"bubble",...}'
 
   # Broadcast an image
-  line message broadcast --image https://example.com/image.jpg
+  line message broadcast --image-url https://example.com/image.jpg
 
   # Broadcast a video
-  line message broadcast --video https://example.com/video.mp4 --preview https://example.com/preview.jpg
+  line message broadcast --video-url https://example.com/video.mp4 --preview-url https://example.com/preview.jpg
 
   # Broadcast an audio message
-  line message broadcast --audio https://example.com/audio.m4a --duration 60000
+  line message broadcast --audio-url https://example.com/audio.m4a --duration 60000
 
   # Broadcast a location
-  line message broadcast --location-title "Tokyo Tower" --location-address "4-2-8 Shiba-koen, Minato-ku, Tokyo" --lat 35.6586 --lng 139.7454
+  line message broadcast --location "35.6586,139.7454,Tokyo Tower,4-2-8 Shiba-koen, Minato-ku, Tokyo"
 
   # Broadcast a sticker
-  line message broadcast --sticker-package 446 --sticker-id 1988`,
+  line message broadcast --sticker 446:1988
+
+  # Record the request ID to fetch delivery results later
+  line message broadcast --text "Spring sale!" --track-label spring-sale`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyLocationFlag(location, &lat, &lng, &locationTitle, &locationAddress); err != nil {
+				return err
+			}
+
 			// Validate exactly one message type is specified
 			if err := requireExactlyOneFlag([]FlagCheck{
 				{Name: "--text", Set: text != ""},
 				{Name: "--flex", Set: flexJSON != ""},
-				{Name: "--image", Set: imageURL != ""},
-				{Name: "--video", Set: videoURL != ""},
-				{Name: "--audio", Set: audioURL != ""},
-				{Name: "--location-*", Set: locationTitle != "" || locationAddress != "" || lat != 0 || lng != 0},
-				{Name: "--sticker-*", Set: packageID != "" || stickerID != ""},
+				{Name: "--image-url", Set: imageURL != ""},
+				{Name: "--video-url", Set: videoURL != ""},
+				{Name: "--audio-url", Set: audioURL != ""},
+				{Name: "--location", Set: locationTitle != "" || locationAddress != "" || lat != 0 || lng != 0},
+				{Name: "--sticker", Set: sticker != "" || packageID != "" || stickerID != ""},
 			}); err != nil {
 				return err
 			}
 
-			// Validate sticker flags are used together
-			if (packageID != "" && stickerID == "") || (packageID == "" && stickerID != "") {
-				return fmt.Errorf("--sticker-package and --sticker-id must be used together")
+			packageID, stickerID, err := parseStickerFlags(sticker, packageID, stickerID)
+			if err != nil {
+				return err
+			}
+			quickReplyItems, err := quickReply.load()
+			if err != nil {
+				return err
+			}
+
+			if err := enforceFreeze(cmd, "broadcast", overrideFreeze); err != nil {
+				return err
 			}
 
 			// Require confirmation for broadcast unless --yes is set
 			if !flags.Yes {
-				_, _ = fmt.Fprint(cmd.OutOrStdout(), "This will broadcast to ALL followers. Continue? [y/N]: ")
-				var response string
-				_, _ = fmt.Fscanln(cmd.InOrStdin(), &response)
-				if response != "y" && response != "Y" && response != "yes" {
+				if !askYesNo(cmd.OutOrStdout(), cmd.InOrStdin(), "This will broadcast to ALL followers. Continue?") {
 					return fmt.Errorf("broadcast cancelled")
 				}
 			}
 
-			target := messageTarget{Type: "broadcast"}
+			target := messageTarget{Type: "broadcast", QuickReply: quickReplyItems, Track: &track}
 			return dispatchMessage(cmd, client, target, text, flexJSON, altText, imageURL, previewURL, videoURL, audioURL, duration, locationTitle, locationAddress, lat, lng, packageID, stickerID)
 		},
 	}
@@ -185,17 +308,23 @@ func newMessageBroadcastCmdWithClient(client *api.Client) *cobra.Command {
 	cmd.Flags().StringVar(&text, "text", "", "Text message content")
 	cmd.Flags().StringVar(&flexJSON, "flex", "", "Flex message JSON")
 	cmd.Flags().StringVar(&altText, "alt-text", "Flex message", "Alt text for flex messages (shown in notifications)")
-	cmd.Flags().StringVar(&imageURL, "image", "", "Image URL to broadcast")
-	cmd.Flags().StringVar(&videoURL, "video", "", "Video URL to broadcast")
-	cmd.Flags().StringVar(&audioURL, "audio", "", "Audio URL to broadcast")
-	cmd.Flags().IntVar(&duration, "duration", 0, "Audio duration in milliseconds (required for --audio)")
-	cmd.Flags().StringVar(&previewURL, "preview", "", "Preview image URL (required for --video, defaults to --image for images)")
+	cmd.Flags().StringVar(&imageURL, "image-url", "", "HTTPS URL of a JPEG or PNG image to broadcast (max 10 MB)")
+	cmd.Flags().StringVar(&videoURL, "video-url", "", "HTTPS URL of an MP4 video to broadcast (max 200 MB)")
+	cmd.Flags().StringVar(&audioURL, "audio-url", "", "HTTPS URL of an M4A audio file to broadcast (max 200 MB)")
+	cmd.Flags().IntVar(&duration, "duration", 0, "Audio duration in milliseconds (required for --audio-url)")
+	cmd.Flags().StringVar(&previewURL, "preview-url", "", "HTTPS URL of a JPEG or PNG preview image, max 1 MB (required for --video-url, defaults to --image-url)")
+	cmd.Flags().StringVar(&location, "location", "", "Location as \"lat,lng,title,address\"")
 	cmd.Flags().StringVar(&locationTitle, "location-title", "", "Location title")
 	cmd.Flags().StringVar(&locationAddress, "location-address", "", "Location address")
 	cmd.Flags().Float64Var(&lat, "lat", 0, "Latitude for location message")
 	cmd.Flags().Float64Var(&lng, "lng", 0, "Longitude for location message")
+	cmd.Flags().StringVar(&sticker, "sticker", "", "Sticker as <packageId>:<stickerId>, e.g. 446:1988 (see: line sticker list-packages)")
 	cmd.Flags().StringVar(&packageID, "sticker-package", "", "Sticker package ID")
 	cmd.Flags().StringVar(&stickerID, "sticker-id", "", "Sticker ID")
+	addFreezeOverrideFlag(cmd, &overrideFreeze)
+	quickReply.addFlags(cmd)
+	track.addFlags(cmd)
+	cmd.Flags().SetNormalizeFunc(normalizeMediaFlags)
 
 	return cmd
 }
@@ -215,84 +344,124 @@ func newMessageMulticastCmdWithClient(client *api.Client) *cobra.Command {
 	var previewURL string
 	var packageID string
 	var stickerID string
+	var sticker string
 	var videoURL string
 	var audioURL string
 	var duration int
+	var location string
 	var locationTitle string
 	var locationAddress string
 	var lat float64
 	var lng float64
+	var overrideFreeze string
+	var quickReply quickReplyFlags
+	var aggregationUnit string
 
 	cmd := &cobra.Command{
 		Use:   "multicast",
 		Short: "Send message to multiple users",
-		Long:  "Send a text, flex, image, video, audio, location, or sticker message to multiple users (max 500 per request).",
+		Long: `Send a text, flex, image, video, audio, location, or sticker message to multiple users (max 500 per request).
+
+--to may be repeated or comma-separated, and @file reads user IDs from a
+file, one per line.`,
 		Example: `  # Send text to multiple users
   line message multicast --to U123,U456,U789 --text "Hello!"
 
+  # Send to the users listed in a file
+  line message multicast --to @users.txt --text "Hello!"
+
   # Send flex message
   line message multicast --to U123,U456 --flex '{"type":"bubble",...}'
 
   # Send an image
-  line message multicast --to U123,U456 --image https://example.com/image.jpg
+  line message multicast --to U123,U456 --image-url https://example.com/image.jpg
 
   # Send a video
-  line message multicast --to U123,U456 --video https://example.com/video.mp4 --preview https://example.com/preview.jpg
+  line message multicast --to U123,U456 --video-url https://example.com/video.mp4 --preview-url https://example.com/preview.jpg
 
   # Send an audio message
-  line message multicast --to U123,U456 --audio https://example.com/audio.m4a --duration 60000
+  line message multicast --to U123,U456 --audio-url https://example.com/audio.m4a --duration 60000
 
   # Send a location
-  line message multicast --to U123,U456 --location-title "Tokyo Tower" --location-address "4-2-8 Shiba-koen, Minato-ku, Tokyo" --lat 35.6586 --lng 139.7454
+  line message multicast --to U123,U456 --location "35.6586,139.7454,Tokyo Tower,4-2-8 Shiba-koen, Minato-ku, Tokyo"
 
   # Send a sticker
-  line message multicast --to U123,U456 --sticker-package 446 --sticker-id 1988`,
+  line message multicast --to U123,U456 --sticker 446:1988
+
+  # Count the message's insights under a campaign code
+  line message multicast --to U123,U456 --text "Sale!" --aggregation-unit spring_sale`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(userIDs) == 0 {
+			recipients, err := expandRecipients(userIDs)
+			if err != nil {
+				return err
+			}
+			if len(recipients) == 0 {
 				return fmt.Errorf("--to is required: specify comma-separated user IDs")
 			}
-			if len(userIDs) > 500 {
-				return fmt.Errorf("too many users: max 500 per request, got %d", len(userIDs))
+			if len(recipients) > maxMulticastRecipients {
+				return fmt.Errorf("too many users: max %d per request, got %d", maxMulticastRecipients, len(recipients))
+			}
+
+			if err := applyLocationFlag(location, &lat, &lng, &locationTitle, &locationAddress); err != nil {
+				return err
 			}
 
 			// Validate exactly one message type is specified
 			if err := requireExactlyOneFlag([]FlagCheck{
 				{Name: "--text", Set: text != ""},
 				{Name: "--flex", Set: flexJSON != ""},
-				{Name: "--image", Set: imageURL != ""},
-				{Name: "--video", Set: videoURL != ""},
-				{Name: "--audio", Set: audioURL != ""},
-				{Name: "--location-*", Set: locationTitle != "" || locationAddress != "" || lat != 0 || lng != 0},
-				{Name: "--sticker-*", Set: packageID != "" || stickerID != ""},
+				{Name: "--image-url", Set: imageURL != ""},
+				{Name: "--video-url", Set: videoURL != ""},
+				{Name: "--audio-url", Set: audioURL != ""},
+				{Name: "--location", Set: locationTitle != "" || locationAddress != "" || lat != 0 || lng != 0},
+				{Name: "--sticker", Set: sticker != "" || packageID != "" || stickerID != ""},
 			}); err != nil {
 				return err
 			}
 
-			// Validate sticker flags are used together
-			if (packageID != "" && stickerID == "") || (packageID == "" && stickerID != "") {
-				return fmt.Errorf("--sticker-package and --sticker-id must be used together")
+			packageID, stickerID, err := parseStickerFlags(sticker, packageID, stickerID)
+			if err != nil {
+				return err
+			}
+			quickReplyItems, err := quickReply.load()
+			if err != nil {
+				return err
+			}
+
+			if err := validateAggregationUnit(aggregationUnit); err != nil {
+				return err
+			}
+
+			if err := enforceFreeze(cmd, "multicast", overrideFreeze); err != nil {
+				return err
 			}
 
-			target := messageTarget{Type: "multicast", UserIDs: userIDs}
+			target := messageTarget{Type: "multicast", UserIDs: recipients, QuickReply: quickReplyItems, AggregationUnit: aggregationUnit}
 			return dispatchMessage(cmd, client, target, text, flexJSON, altText, imageURL, previewURL, videoURL, audioURL, duration, locationTitle, locationAddress, lat, lng, packageID, stickerID)
 		},
 	}
 
-	cmd.Flags().StringSliceVar(&userIDs, "to", nil, "Comma-separated user IDs (required, max 500)")
+	cmd.Flags().StringSliceVar(&userIDs, "to", nil, "Comma-separated user IDs, or @file of IDs (required, max 500)")
 	cmd.Flags().StringVar(&text, "text", "", "Text message content")
 	cmd.Flags().StringVar(&flexJSON, "flex", "", "Flex message JSON")
 	cmd.Flags().StringVar(&altText, "alt-text", "Flex message", "Alt text for flex messages")
-	cmd.Flags().StringVar(&imageURL, "image", "", "Image URL to send")
-	cmd.Flags().StringVar(&videoURL, "video", "", "Video URL to send")
-	cmd.Flags().StringVar(&audioURL, "audio", "", "Audio URL to send")
-	cmd.Flags().IntVar(&duration, "duration", 0, "Audio duration in milliseconds (required for --audio)")
-	cmd.Flags().StringVar(&previewURL, "preview", "", "Preview image URL (required for --video, defaults to --image for images)")
+	cmd.Flags().StringVar(&imageURL, "image-url", "", "HTTPS URL of a JPEG or PNG image to send (max 10 MB)")
+	cmd.Flags().StringVar(&videoURL, "video-url", "", "HTTPS URL of an MP4 video to send (max 200 MB)")
+	cmd.Flags().StringVar(&audioURL, "audio-url", "", "HTTPS URL of an M4A audio file to send (max 200 MB)")
+	cmd.Flags().IntVar(&duration, "duration", 0, "Audio duration in milliseconds (required for --audio-url)")
+	cmd.Flags().StringVar(&previewURL, "preview-url", "", "HTTPS URL of a JPEG or PNG preview image, max 1 MB (required for --video-url, defaults to --image-url)")
+	cmd.Flags().StringVar(&location, "location", "", "Location as \"lat,lng,title,address\"")
 	cmd.Flags().StringVar(&locationTitle, "location-title", "", "Location title")
 	cmd.Flags().StringVar(&locationAddress, "location-address", "", "Location address")
 	cmd.Flags().Float64Var(&lat, "lat", 0, "Latitude for location message")
 	cmd.Flags().Float64Var(&lng, "lng", 0, "Longitude for location message")
+	cmd.Flags().StringVar(&sticker, "sticker", "", "Sticker as <packageId>:<stickerId>, e.g. 446:1988 (see: line sticker list-packages)")
 	cmd.Flags().StringVar(&packageID, "sticker-package", "", "Sticker package ID")
 	cmd.Flags().StringVar(&stickerID, "sticker-id", "", "Sticker ID")
+	addFreezeOverrideFlag(cmd, &overrideFreeze)
+	quickReply.addFlags(cmd)
+	addAggregationUnitFlag(cmd, &aggregationUnit)
+	cmd.Flags().SetNormalizeFunc(normalizeMediaFlags)
 	_ = cmd.MarkFlagRequired("to")
 
 	return cmd
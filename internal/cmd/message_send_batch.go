@@ -0,0 +1,443 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+	"github.com/spf13/cobra"
+)
+
+// maxTextMessageLength is the most characters LINE accepts in a text
+// message.
+const maxTextMessageLength = 5000
+
+// batchRecipient is one row of a send-batch CSV with its rendered message.
+type batchRecipient struct {
+	Line    int
+	UserID  string
+	Message api.TextMessage
+}
+
+// batchResult is the outcome of one row, as written to the results file.
+type batchResult struct {
+	Line      int
+	UserID    string
+	Status    string // sent, failed, or skipped
+	RequestID string
+	Error     string
+}
+
+func newMessageSendBatchCmd() *cobra.Command {
+	return newMessageSendBatchCmdWithClient(nil)
+}
+
+func newMessageSendBatchCmdWithClient(client *api.Client) *cobra.Command {
+	var templateText string
+	var templateFile string
+	var csvPath string
+	var idColumn string
+	var concurrency int
+	var retries int
+	var resultsPath string
+	var aggregationUnit string
+	var overrideFreeze string
+
+	cmd := &cobra.Command{
+		Use:   "send-batch",
+		Short: "Push a personalized message to every row of a CSV file",
+		Long: `Render a Go template for every row of a CSV file and push the result to
+the user of that row, one request per user.
+
+The first row of the CSV is the header. Every column is available to the
+template by its header, as {{.name}}; a template that refers to a column
+the file does not have is an error. {emoji:productId:emojiId} in the
+rendered text becomes a LINE emoji, as with "message push".
+
+Every row is rendered and checked before anything is sent: an invalid user
+ID, a template error, or a message that is empty or longer than 5000
+characters stops the command and lists the rows at fault. A user ID that
+appears again is skipped.
+
+Messages are pushed --concurrency at a time. A push that fails with 429 is
+retried up to --retries times, waiting longer each time; other failures are
+recorded and do not stop the other rows. The outcome of every row, with the
+request ID of each sent message, is written to the --results CSV as it
+completes. Pushes count against the monthly message quota. With --dry-run
+the rows are checked and the first message is shown, and nothing is sent.`,
+		Example: `  # recipients.csv
+  userId,name,code
+  U1234567890abcdef1234567890abcdef,Aiko,SPRING-1
+  Ufedcba0987654321fedcba0987654321,Ken,SPRING-2
+
+  # Send each user their code
+  line message send-batch --csv recipients.csv --id-column userId \
+    --template 'Hello {{.name}}, your code is {{.code}}'
+
+  # Check the file and preview the first message
+  line message send-batch --csv recipients.csv --template-file welcome.tmpl --dry-run
+
+  # Slower, with the results in a known place
+  line message send-batch --csv recipients.csv --template-file welcome.tmpl \
+    --concurrency 2 --results sent.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (templateText == "") == (templateFile == "") {
+				return withExitCode(ExitUsage, fmt.Errorf("specify exactly one of --template or --template-file"))
+			}
+			if concurrency < 1 {
+				return withExitCode(ExitUsage, fmt.Errorf("--concurrency must be at least 1"))
+			}
+			if retries < 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--retries must not be negative"))
+			}
+			if err := validateAggregationUnit(aggregationUnit); err != nil {
+				return err
+			}
+			if templateFile != "" {
+				data, err := readFileOrStdin(templateFile)
+				if err != nil {
+					return fmt.Errorf("failed to read template file: %w", err)
+				}
+				templateText = strings.TrimRight(string(data), "\r\n")
+			}
+			tmpl, err := template.New("message").Option("missingkey=error").Parse(templateText)
+			if err != nil {
+				return withExitCode(ExitUsage, fmt.Errorf("invalid template: %w", err))
+			}
+
+			recipients, skipped, err := readBatchRecipients(csvPath, idColumn, tmpl)
+			if err != nil {
+				return withExitCode(ExitUsage, err)
+			}
+			if len(recipients) == 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("no recipients in %s", csvPath))
+			}
+
+			if flags.DryRun {
+				return printBatchPreview(cmd, recipients, skipped)
+			}
+			if err := enforceFreeze(cmd, "push", overrideFreeze); err != nil {
+				return err
+			}
+			if err := confirmDestructive(cmd, "send-batch", fmt.Sprintf("Push %d messages?", len(recipients))); err != nil {
+				return err
+			}
+
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			if resultsPath == "" {
+				resultsPath = fmt.Sprintf("message-send-batch-%s.csv", clockNow().Format("20060102T150405"))
+			}
+			sender, err := newBatchSender(cmd, resultsPath, len(recipients))
+			if err != nil {
+				return err
+			}
+			for _, r := range skipped {
+				sender.record(r)
+			}
+			sender.run(cmd.Context(), recipients, concurrency, func(ctx context.Context, r batchRecipient) (string, error) {
+				var requestID string
+				err := callRetrying429N(ctx, retries, func(ctx context.Context) error {
+					req := api.PushMessageRequest{To: r.UserID, Messages: []any{r.Message}}
+					if aggregationUnit != "" {
+						req.CustomAggregationUnits = []string{aggregationUnit}
+					}
+					resp, err := c.PostWithHeaders(ctx, "/v2/bot/message/push", req)
+					if err != nil {
+						return err
+					}
+					requestID = resp.Headers.Get("X-Line-Request-Id")
+					return nil
+				})
+				return requestID, err
+			})
+			if err := sender.finish(); err != nil {
+				return err
+			}
+			if err := cmd.Context().Err(); err != nil {
+				return fmt.Errorf("send-batch interrupted after %d of %d messages; see %s", sender.sent+sender.failed, len(recipients), resultsPath)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(map[string]any{
+					"total":   len(recipients),
+					"sent":    sender.sent,
+					"failed":  sender.failed,
+					"skipped": len(skipped),
+					"results": resultsPath,
+				}); err != nil {
+					return err
+				}
+			} else {
+				out := cmd.OutOrStdout()
+				_, _ = fmt.Fprintf(out, "Sent %d of %d messages\n", sender.sent, len(recipients))
+				if sender.failed > 0 {
+					_, _ = fmt.Fprintf(out, "Failed: %d\n", sender.failed)
+				}
+				if len(skipped) > 0 {
+					_, _ = fmt.Fprintf(out, "Skipped: %d duplicate users\n", len(skipped))
+				}
+				_, _ = fmt.Fprintf(out, "Results: %s\n", resultsPath)
+			}
+			if sender.failed > 0 {
+				return fmt.Errorf("failed to send %d messages; see %s", sender.failed, resultsPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&templateText, "template", "", "Go template of the message text, with the CSV columns as {{.column}}")
+	cmd.Flags().StringVar(&templateFile, "template-file", "", "File containing the template, or - for stdin")
+	cmd.Flags().StringVar(&csvPath, "csv", "", "CSV file of recipients with a header row, or - for stdin (required)")
+	cmd.Flags().StringVar(&idColumn, "id-column", "userId", "Header of the column holding the user IDs")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultBulkConcurrency, "Pushes in flight at a time")
+	cmd.Flags().IntVar(&retries, "retries", bulkUserRetries, "Times to retry a push that is rate limited (429)")
+	cmd.Flags().StringVar(&resultsPath, "results", "", "Results file (CSV) (default message-send-batch-<time>.csv)")
+	addAggregationUnitFlag(cmd, &aggregationUnit)
+	addFreezeOverrideFlag(cmd, &overrideFreeze)
+	_ = cmd.MarkFlagRequired("csv")
+
+	return cmd
+}
+
+// readBatchRecipients reads a send-batch CSV and renders the message of
+// every row. Rows whose user ID was already seen are returned as skipped
+// results. Every row at fault is reported in the error, up to
+// userids.MaxReportedInvalid of them.
+func readBatchRecipients(path, idColumn string, tmpl *template.Template) ([]batchRecipient, []batchResult, error) {
+	f, err := openFileOrStdin(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	cr := csv.NewReader(bufio.NewReader(f))
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%s is empty", path)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	idIndex := -1
+	for i, h := range header {
+		header[i] = strings.TrimSpace(h)
+		if header[i] == idColumn {
+			idIndex = i
+		}
+	}
+	if idIndex < 0 {
+		return nil, nil, fmt.Errorf("%s has no column %q (columns: %s)", path, idColumn, strings.Join(header, ", "))
+	}
+
+	var recipients []batchRecipient
+	var skipped []batchResult
+	var problems []string
+	problemCount := 0
+	problem := func(line int, format string, args ...any) {
+		problemCount++
+		if len(problems) < userids.MaxReportedInvalid {
+			problems = append(problems, fmt.Sprintf("  line %d: %s", line, fmt.Sprintf(format, args...)))
+		}
+	}
+	seen := map[string]bool{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV file: %w", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		row := make(map[string]string, len(header))
+		for i, h := range header {
+			if i < len(record) {
+				row[h] = record[i]
+			} else {
+				row[h] = ""
+			}
+		}
+		userID := userids.Normalize(row[idColumn])
+		if !userids.Valid(userID) {
+			problem(line, "invalid user ID %q", row[idColumn])
+			continue
+		}
+		if seen[userID] {
+			skipped = append(skipped, batchResult{Line: line, UserID: userID, Status: "skipped", Error: "duplicate user"})
+			continue
+		}
+		seen[userID] = true
+
+		var text strings.Builder
+		if err := tmpl.Execute(&text, row); err != nil {
+			problem(line, "%v", templateErrorText(err))
+			continue
+		}
+		rendered := strings.TrimSpace(text.String())
+		switch n := len([]rune(rendered)); {
+		case n == 0:
+			problem(line, "message is empty")
+			continue
+		case n > maxTextMessageLength:
+			problem(line, "message is %d characters, at most %d are allowed", n, maxTextMessageLength)
+			continue
+		}
+		msg, err := newTextMessage(rendered)
+		if err != nil {
+			problem(line, "%v", err)
+			continue
+		}
+		recipients = append(recipients, batchRecipient{Line: line, UserID: userID, Message: msg})
+	}
+
+	if problemCount > 0 {
+		msg := fmt.Sprintf("%s has %d invalid rows:\n%s", path, problemCount, strings.Join(problems, "\n"))
+		if problemCount > len(problems) {
+			msg += fmt.Sprintf("\n  ... and %d more", problemCount-len(problems))
+		}
+		return nil, nil, errors.New(msg)
+	}
+	return recipients, skipped, nil
+}
+
+// templateErrorText shortens a template execution error to its cause, such
+// as `map has no entry for key "name"`.
+func templateErrorText(err error) string {
+	msg := err.Error()
+	if i := strings.LastIndex(msg, ": "); i >= 0 && strings.HasPrefix(msg, "template: ") {
+		return msg[i+2:]
+	}
+	return msg
+}
+
+// printBatchPreview shows what send-batch would send, for --dry-run.
+func printBatchPreview(cmd *cobra.Command, recipients []batchRecipient, skipped []batchResult) error {
+	if flags.Output == "json" {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"dryRun":  true,
+			"total":   len(recipients),
+			"skipped": len(skipped),
+			"first":   map[string]any{"userId": recipients[0].UserID, "message": recipients[0].Message},
+		})
+	}
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "Would push %d messages", len(recipients))
+	if len(skipped) > 0 {
+		_, _ = fmt.Fprintf(out, " (%d duplicate users skipped)", len(skipped))
+	}
+	_, _ = fmt.Fprintf(out, "\nFirst message, to %s:\n%s\n", recipients[0].UserID, recipients[0].Message.Text)
+	return nil
+}
+
+// batchSender pushes the messages of a send-batch and appends the outcome
+// of every row to the results CSV as it completes.
+type batchSender struct {
+	progress *progressMeter
+
+	mu      sync.Mutex
+	file    *os.File
+	results *csv.Writer
+	sent    int
+	failed  int
+}
+
+func newBatchSender(cmd *cobra.Command, path string, total int) (*batchSender, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create results file: %w", err)
+	}
+	s := &batchSender{
+		progress: newProgressMeter(cmd, total),
+		file:     file,
+		results:  csv.NewWriter(file),
+	}
+	_ = s.results.Write([]string{"line", "userId", "status", "requestId", "error"})
+	return s, nil
+}
+
+// run calls push for every recipient with at most concurrency calls in
+// flight.
+func (s *batchSender) run(ctx context.Context, recipients []batchRecipient, concurrency int, push func(ctx context.Context, r batchRecipient) (string, error)) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, r := range recipients {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			requestID, err := push(ctx, r)
+			if ctx.Err() != nil && err != nil {
+				// Interrupted: the row is neither sent nor failed
+				return
+			}
+			result := batchResult{Line: r.Line, UserID: r.UserID, Status: "sent", RequestID: requestID}
+			if err != nil {
+				result.Status, result.Error = "failed", csvError(err)
+			}
+			s.record(result)
+			s.progress.add(err != nil)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *batchSender) record(r batchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Status {
+	case "sent":
+		s.sent++
+	case "failed":
+		s.failed++
+	}
+	_ = s.results.Write([]string{strconv.Itoa(r.Line), r.UserID, r.Status, r.RequestID, r.Error})
+}
+
+// finish clears the progress bar and closes the results file.
+func (s *batchSender) finish() error {
+	s.progress.clear()
+	s.results.Flush()
+	err := s.results.Error()
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
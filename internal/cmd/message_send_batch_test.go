@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func runSendBatch(t *testing.T, client *api.Client, args ...string) (string, error) {
+	t.Helper()
+	cmd := newMessageSendBatchCmdWithClient(client)
+	cmd.SetArgs(args)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func writeRecipientsCSV(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recipients.csv")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMessageSendBatchCmd(t *testing.T) {
+	oldDelay, oldOutput := bulkRetryDelay, flags.Output
+	defer func() { bulkRetryDelay, flags.Output = oldDelay, oldOutput }()
+	bulkRetryDelay = time.Millisecond
+	flags.Output = "json"
+
+	var mu sync.Mutex
+	sent := map[string]string{}
+	var throttled atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/message/push" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req struct {
+			To       string `json:"to"`
+			Messages []struct {
+				Text string `json:"text"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.To {
+		case testUserID(2):
+			// Rate limited once, then accepted
+			if throttled.CompareAndSwap(false, true) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"message":"The API rate limit has been exceeded"}`))
+				return
+			}
+		case testUserID(3):
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"The user hasn't added the LINE Official Account as a friend"}`))
+			return
+		}
+		mu.Lock()
+		sent[req.To] = req.Messages[0].Text
+		mu.Unlock()
+		w.Header().Set("X-Line-Request-Id", "req-"+req.To[len(req.To)-1:])
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	csvPath := writeRecipientsCSV(t, "name,userId,code\n"+
+		"Aiko,"+testUserID(1)+",A-1\n"+
+		"Ken,"+testUserID(2)+",B-2\n"+
+		"Mai,"+testUserID(3)+",C-3\n"+
+		"Aiko again,"+testUserID(1)+",A-9\n")
+	results := filepath.Join(t.TempDir(), "results.csv")
+
+	out, err := runSendBatch(t, client, "--csv", csvPath, "--template", "Hello {{.name}}, your code is {{.code}}", "--concurrency", "2", "--results", results)
+	if err == nil || !strings.Contains(err.Error(), "failed to send 1 messages") {
+		t.Fatalf("expected one failure, got %v", err)
+	}
+	if sent[testUserID(1)] != "Hello Aiko, your code is A-1" || sent[testUserID(2)] != "Hello Ken, your code is B-2" || len(sent) != 2 {
+		t.Errorf("unexpected messages: %v", sent)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if result["sent"] != float64(2) || result["failed"] != float64(1) || result["skipped"] != float64(1) || result["results"] != results {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	f, err := os.Open(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(rows[0], ",") != "line,userId,status,requestId,error" || len(rows) != 5 {
+		t.Fatalf("unexpected results: %v", rows)
+	}
+	byLine := map[string][]string{}
+	for _, row := range rows[1:] {
+		byLine[row[0]] = row
+	}
+	if row := byLine["2"]; row[2] != "sent" || row[3] != "req-1" {
+		t.Errorf("unexpected row for line 2: %v", row)
+	}
+	if row := byLine["3"]; row[2] != "sent" || row[3] != "req-2" {
+		t.Errorf("unexpected row for line 3: %v", row)
+	}
+	if row := byLine["4"]; row[2] != "failed" || !strings.HasPrefix(row[4], "400 ") {
+		t.Errorf("unexpected row for line 4: %v", row)
+	}
+	if row := byLine["5"]; row[2] != "skipped" || row[4] != "duplicate user" {
+		t.Errorf("unexpected row for line 5: %v", row)
+	}
+}
+
+func TestMessageSendBatchCmd_InvalidRows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("nothing should be sent, got %s", r.URL.Path)
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	csvPath := writeRecipientsCSV(t, "userId,name\n"+
+		testUserID(1)+",Aiko\n"+
+		"U123,Ken\n"+
+		testUserID(3)+"\n")
+	_, err := runSendBatch(t, client, "--csv", csvPath, "--template", "Hi {{.name}}{{.missing}}")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"has 3 invalid rows", `line 2: map has no entry for key "missing"`, `line 3: invalid user ID "U123"`, "line 4:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	_, err = runSendBatch(t, client, "--csv", csvPath, "--template", "Hi", "--id-column", "user")
+	if err == nil || !strings.Contains(err.Error(), `no column "user"`) {
+		t.Errorf("expected missing column error, got %v", err)
+	}
+	_, err = runSendBatch(t, client, "--csv", csvPath)
+	if err == nil || !strings.Contains(err.Error(), "exactly one of --template or --template-file") {
+		t.Errorf("expected template flag error, got %v", err)
+	}
+}
+
+func TestMessageSendBatchCmd_DryRun(t *testing.T) {
+	oldDryRun, oldOutput := flags.DryRun, flags.Output
+	defer func() { flags.DryRun, flags.Output = oldDryRun, oldOutput }()
+	flags.DryRun = true
+	flags.Output = "text"
+
+	csvPath := writeRecipientsCSV(t, "\ufeffuserId,name\n"+testUserID(1)+",Aiko\n"+testUserID(2)+",Ken\n")
+	tmplPath := filepath.Join(t.TempDir(), "welcome.tmpl")
+	if err := os.WriteFile(tmplPath, []byte("Welcome, {{.name}}!\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runSendBatch(t, nil, "--csv", csvPath, "--template-file", tmplPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Would push 2 messages") || !strings.Contains(out, "Welcome, Aiko!") {
+		t.Errorf("unexpected preview: %q", out)
+	}
+}
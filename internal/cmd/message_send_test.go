@@ -433,7 +433,7 @@ func TestMessagePushCmd_Execute_VideoWithoutPreview(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for video without preview")
 	}
-	if !strings.Contains(err.Error(), "--preview is required") {
+	if !strings.Contains(err.Error(), "--preview-url is required") {
 		t.Errorf("expected error about preview required, got: %v", err)
 	}
 }
@@ -732,10 +732,10 @@ func TestMessageMulticastCmd_Execute_TooManyUsers(t *testing.T) {
 
 	cmd := newMessageMulticastCmdWithClient(client)
 
-	// Create 501 user IDs
+	// Create 501 distinct user IDs
 	userIDs := make([]string, 501)
 	for i := 0; i < 501; i++ {
-		userIDs[i] = "U" + strings.Repeat("0", 32)
+		userIDs[i] = testUserID(i)
 	}
 	cmd.SetArgs([]string{"--to", strings.Join(userIDs, ","), "--text", "Hello!"})
 
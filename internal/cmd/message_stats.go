@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
 	"github.com/spf13/cobra"
@@ -13,10 +16,21 @@ func newMessageQuotaCmd() *cobra.Command {
 }
 
 func newMessageQuotaCmdWithClient(client *api.Client) *cobra.Command {
+	var watch bool
+	var interval time.Duration
+	var count int
+
 	cmd := &cobra.Command{
 		Use:   "quota",
 		Short: "Get message quota and usage",
-		Long:  "Show the monthly message limit and current usage for your LINE Official Account.",
+		Long: `Show the monthly message limit and current usage for your LINE Official Account.
+
+With --watch the usage is refreshed every --interval, which is handy for
+following consumption while a campaign goes out.`,
+		Example: `  line message quota
+
+  # Follow consumption live
+  line message quota --watch --interval 30s`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := client
 			if c == nil {
@@ -27,42 +41,67 @@ func newMessageQuotaCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
-			quota, err := c.GetMessageQuota(cmd.Context())
-			if err != nil {
-				return fmt.Errorf("failed to get quota: %w", err)
-			}
+			render := func(ctx context.Context, w io.Writer) error {
+				var quota *api.QuotaResponse
+				var consumption *api.ConsumptionResponse
+				err := runConcurrently(ctx,
+					func(ctx context.Context) error {
+						var err error
+						quota, err = c.GetMessageQuota(ctx)
+						if err != nil {
+							return fmt.Errorf("failed to get quota: %w", err)
+						}
+						return nil
+					},
+					func(ctx context.Context) error {
+						var err error
+						consumption, err = c.GetMessageConsumption(ctx)
+						if err != nil {
+							return fmt.Errorf("failed to get consumption: %w", err)
+						}
+						return nil
+					},
+				)
+				if err != nil {
+					return err
+				}
 
-			consumption, err := c.GetMessageConsumption(cmd.Context())
-			if err != nil {
-				return fmt.Errorf("failed to get consumption: %w", err)
-			}
+				if flags.Output == "json" {
+					result := map[string]any{
+						"type":  quota.Type,
+						"limit": quota.Value,
+						"used":  consumption.TotalUsage,
+					}
+					enc := json.NewEncoder(w)
+					enc.SetIndent("", "  ")
+					return enc.Encode(result)
+				}
 
-			if flags.Output == "json" {
-				result := map[string]any{
-					"type":  quota.Type,
-					"limit": quota.Value,
-					"used":  consumption.TotalUsage,
+				if quota.Type == "limited" && quota.Value > 0 {
+					pct := float64(consumption.TotalUsage) / float64(quota.Value) * 100
+					_, _ = fmt.Fprintf(w, "Message Quota: %d/month\n", quota.Value)
+					_, _ = fmt.Fprintf(w, "Used: %d (%.1f%%)\n", consumption.TotalUsage, pct)
+				} else if quota.Type == "limited" {
+					_, _ = fmt.Fprintf(w, "Message Quota: 0/month\n")
+					_, _ = fmt.Fprintf(w, "Used: %d\n", consumption.TotalUsage)
+				} else {
+					_, _ = fmt.Fprintf(w, "Message Quota: Unlimited\n")
+					_, _ = fmt.Fprintf(w, "Used: %d\n", consumption.TotalUsage)
 				}
-				enc := json.NewEncoder(cmd.OutOrStdout())
-				enc.SetIndent("", "  ")
-				return enc.Encode(result)
+				return nil
 			}
 
-			if quota.Type == "limited" && quota.Value > 0 {
-				pct := float64(consumption.TotalUsage) / float64(quota.Value) * 100
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Message Quota: %d/month\n", quota.Value)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Used: %d (%.1f%%)\n", consumption.TotalUsage, pct)
-			} else if quota.Type == "limited" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Message Quota: 0/month\n")
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Used: %d\n", consumption.TotalUsage)
-			} else {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Message Quota: Unlimited\n")
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Used: %d\n", consumption.TotalUsage)
+			if watch {
+				return watchOutput(cmd, interval, count, render)
 			}
-			return nil
+			return render(cmd.Context(), cmd.OutOrStdout())
 		},
 	}
 
+	cmd.Flags().BoolVar(&watch, "watch", false, "Refresh quota usage every --interval until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "Refresh interval with --watch")
+	cmd.Flags().IntVar(&count, "count", 0, "Stop after this many refreshes with --watch (0 = until interrupted)")
+
 	return cmd
 }
 
@@ -137,7 +176,7 @@ Supported message types:
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Type:          %s\n", messageType)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Date:          %s\n", date)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:        %s\n", stats.Status)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:        %s\n", colorStatus(cmd.OutOrStdout(), stats.Status))
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Success:       %d\n", stats.Success)
 			if stats.Failure > 0 {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Failure:       %d\n", stats.Failure)
@@ -333,6 +333,12 @@ func TestMessageQuotaCmd_Execute_LimitedZeroQuota(t *testing.T) {
 
 func TestMessageQuotaCmd_Execute_QuotaAPIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only the quota fails, so the consumption request cannot fail
+		// first and cancel it
+		if r.URL.Path != "/v2/bot/message/quota" {
+			_, _ = w.Write([]byte(`{"totalUsage":0}`))
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"message": "Server error",
@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/messagespec"
+	"github.com/spf13/cobra"
+)
+
+func newMessageTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Send confirm, buttons, and carousel template messages",
+		Long: `Build template messages from flags instead of hand-written JSON, check
+them against LINE's limits, and send them.
+
+Actions are written as <type>:<label>:<value>:
+  message:Yes:yes                    sends "yes" as the user
+  uri:Open:https://example.com       opens a URL (http, https, line, tel)
+  postback:Buy:action=buy&item=1     sends data to your webhook`,
+	}
+	cmd.AddCommand(newMessageTemplateConfirmCmd())
+	cmd.AddCommand(newMessageTemplateButtonsCmd())
+	cmd.AddCommand(newMessageTemplateCarouselCmd())
+	return cmd
+}
+
+// templateSend holds the flags shared by the template subcommands: who to
+// send to and where the template comes from.
+type templateSend struct {
+	to             []string
+	replyToken     string
+	broadcast      bool
+	altText        string
+	file           string
+	overrideFreeze string
+	quickReply     quickReplyFlags
+}
+
+func (s *templateSend) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&s.to, "to", nil, "User ID to push to, or comma-separated IDs or @file to multicast to (max 500)")
+	cmd.Flags().StringVar(&s.replyToken, "reply-token", "", "Reply to a webhook event with this reply token")
+	cmd.Flags().BoolVar(&s.broadcast, "broadcast", false, "Broadcast to all followers (requires confirmation)")
+	cmd.Flags().StringVar(&s.altText, "alt-text", "", "Notification text (defaults to the template text)")
+	cmd.Flags().StringVar(&s.file, "file", "", "Read the template, or the whole template message, from a JSON file, or - for stdin")
+	addFreezeOverrideFlag(cmd, &s.overrideFreeze)
+	s.quickReply.addFlags(cmd)
+}
+
+// message returns the template message from --file, or else from build,
+// checked to be a valid template of type templateType.
+func (s *templateSend) message(cmd *cobra.Command, templateType string, build func() (messagespec.Template, error)) (messagespec.TemplateMessage, error) {
+	var msg messagespec.TemplateMessage
+	if s.file != "" {
+		for _, name := range []string{"text", "title", "image-url", "action", "column"} {
+			if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+				return msg, fmt.Errorf("--file and --%s cannot be used together", name)
+			}
+		}
+		data, err := readFileOrStdin(s.file)
+		if err != nil {
+			return msg, fmt.Errorf("failed to read template file: %w", err)
+		}
+		if msg, err = messagespec.ParseTemplateMessage(data); err != nil {
+			return msg, err
+		}
+		if s.altText != "" {
+			msg.AltText = s.altText
+		}
+		if msg.Template.Type != templateType {
+			return msg, fmt.Errorf("%s is a %s template, not %s", s.file, msg.Template.Type, templateType)
+		}
+	} else {
+		t, err := build()
+		if err != nil {
+			return msg, err
+		}
+		msg = messagespec.NewTemplateMessage(s.altText, t)
+	}
+
+	if err := msg.Validate(); err != nil {
+		return msg, fmt.Errorf("invalid %s template: %w", templateType, err)
+	}
+	return msg, nil
+}
+
+// send delivers msg to the target chosen by --to, --reply-token, or
+// --broadcast.
+func (s *templateSend) send(cmd *cobra.Command, client *api.Client, msg messagespec.TemplateMessage) error {
+	targets := 0
+	for _, set := range []bool{len(s.to) > 0, s.replyToken != "", s.broadcast} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return fmt.Errorf("specify exactly one of --to, --reply-token, or --broadcast")
+	}
+	items, err := s.quickReply.load()
+	if err != nil {
+		return err
+	}
+
+	if s.replyToken != "" {
+		c := client
+		if c == nil {
+			var err error
+			c, err = newAPIClient()
+			if err != nil {
+				return err
+			}
+		}
+		message, err := attachQuickReply(msg, items)
+		if err != nil {
+			return err
+		}
+		if err := c.ReplyMessage(cmd.Context(), s.replyToken, message); err != nil {
+			return fmt.Errorf("failed to reply: %w", err)
+		}
+		if flags.Output == "json" {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]any{"status": "sent", "type": "template"})
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Template reply sent")
+		return nil
+	}
+
+	target := messageTarget{QuickReply: items}
+	if s.broadcast {
+		target.Type = "broadcast"
+	} else {
+		recipients, err := expandRecipients(s.to)
+		if err != nil {
+			return err
+		}
+		if err := setRecipients(&target, recipients); err != nil {
+			return err
+		}
+	}
+	if err := enforceFreeze(cmd, target.Type, s.overrideFreeze); err != nil {
+		return err
+	}
+	if s.broadcast && !flags.Yes {
+		if !askYesNo(cmd.OutOrStdout(), cmd.InOrStdin(), "This will broadcast to ALL followers. Continue?") {
+			return fmt.Errorf("broadcast cancelled")
+		}
+	}
+	return sendMessage(cmd, client, target, msg, "template", nil)
+}
+
+// parseActions parses the --action flags.
+func parseActions(values []string) ([]messagespec.Action, error) {
+	actions := make([]messagespec.Action, 0, len(values))
+	for _, v := range values {
+		a, err := messagespec.ParseAction(v)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+// parseColumn parses a --column flag: semicolon-separated key=value pairs
+// with the keys title, text, image, and action (repeatable).
+func parseColumn(s string) (messagespec.Column, error) {
+	var c messagespec.Column
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return c, fmt.Errorf("invalid --column part %q: expected key=value", part)
+		}
+		switch strings.TrimSpace(key) {
+		case "title":
+			c.Title = value
+		case "text":
+			c.Text = value
+		case "image":
+			c.ThumbnailImageURL = value
+		case "action":
+			a, err := messagespec.ParseAction(value)
+			if err != nil {
+				return c, err
+			}
+			c.Actions = append(c.Actions, a)
+		default:
+			return c, fmt.Errorf("invalid --column key %q: use title, text, image, or action", key)
+		}
+	}
+	return c, nil
+}
+
+func newMessageTemplateConfirmCmd() *cobra.Command {
+	return newMessageTemplateConfirmCmdWithClient(nil)
+}
+
+func newMessageTemplateConfirmCmdWithClient(client *api.Client) *cobra.Command {
+	var send templateSend
+	var text string
+	var actions []string
+
+	cmd := &cobra.Command{
+		Use:   "confirm",
+		Short: "Send a yes/no confirm template",
+		Long:  "Send a confirm template: a question with exactly two action buttons.",
+		Example: `  line message template confirm --to U1234567890abcdef --text "Book a table for 7pm?" \
+    --action message:Yes:yes --action message:No:no
+
+  line message template confirm --reply-token <replyToken> --file confirm.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			msg, err := send.message(cmd, messagespec.TypeConfirm, func() (messagespec.Template, error) {
+				parsed, err := parseActions(actions)
+				return messagespec.Template{Type: messagespec.TypeConfirm, Text: text, Actions: parsed}, err
+			})
+			if err != nil {
+				return err
+			}
+			return send.send(cmd, client, msg)
+		},
+	}
+
+	cmd.Flags().StringVar(&text, "text", "", "Question text (max 240 characters)")
+	cmd.Flags().StringArrayVar(&actions, "action", nil, "Action as <type>:<label>:<value> (exactly 2)")
+	send.addFlags(cmd)
+	return cmd
+}
+
+func newMessageTemplateButtonsCmd() *cobra.Command {
+	return newMessageTemplateButtonsCmdWithClient(nil)
+}
+
+func newMessageTemplateButtonsCmdWithClient(client *api.Client) *cobra.Command {
+	var send templateSend
+	var text, title, imageURL string
+	var actions []string
+
+	cmd := &cobra.Command{
+		Use:   "buttons",
+		Short: "Send a buttons template",
+		Long:  "Send a buttons template: text with an optional title and image, and up to 4 action buttons.",
+		Example: `  line message template buttons --to U1234567890abcdef --title "Menu" --text "What would you like?" \
+    --image-url https://example.com/menu.jpg \
+    --action message:Coffee:coffee --action uri:Website:https://example.com`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			msg, err := send.message(cmd, messagespec.TypeButtons, func() (messagespec.Template, error) {
+				parsed, err := parseActions(actions)
+				return messagespec.Template{Type: messagespec.TypeButtons, Title: title, Text: text, ThumbnailImageURL: imageURL, Actions: parsed}, err
+			})
+			if err != nil {
+				return err
+			}
+			return send.send(cmd, client, msg)
+		},
+	}
+
+	cmd.Flags().StringVar(&text, "text", "", "Message text (max 160 characters, 60 with a title or image)")
+	cmd.Flags().StringVar(&title, "title", "", "Title (max 40 characters)")
+	cmd.Flags().StringVar(&imageURL, "image-url", "", "HTTPS URL of a JPEG or PNG image shown above the text")
+	cmd.Flags().StringArrayVar(&actions, "action", nil, "Action as <type>:<label>:<value> (1 to 4)")
+	send.addFlags(cmd)
+	return cmd
+}
+
+func newMessageTemplateCarouselCmd() *cobra.Command {
+	return newMessageTemplateCarouselCmdWithClient(nil)
+}
+
+func newMessageTemplateCarouselCmdWithClient(client *api.Client) *cobra.Command {
+	var send templateSend
+	var columns []string
+
+	cmd := &cobra.Command{
+		Use:   "carousel",
+		Short: "Send a carousel template",
+		Long: `Send a carousel template: up to 10 columns the user can scroll through.
+
+Each --column is semicolon-separated key=value pairs: text, and optionally
+title and image, plus 1 to 3 actions. Every column needs the same number of
+actions, and either all or none have a title (likewise an image).`,
+		Example: `  line message template carousel --to U1234567890abcdef \
+    --column 'title=Coffee;text=Hot drip;action=postback:Buy:item=coffee' \
+    --column 'title=Tea;text=Green tea;action=postback:Buy:item=tea'
+
+  line message template carousel --broadcast --file carousel.json --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			msg, err := send.message(cmd, messagespec.TypeCarousel, func() (messagespec.Template, error) {
+				t := messagespec.Template{Type: messagespec.TypeCarousel}
+				for _, v := range columns {
+					c, err := parseColumn(v)
+					if err != nil {
+						return t, err
+					}
+					t.Columns = append(t.Columns, c)
+				}
+				return t, nil
+			})
+			if err != nil {
+				return err
+			}
+			return send.send(cmd, client, msg)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&columns, "column", nil, "Column as 'title=...;text=...;image=...;action=...' (repeatable, max 10)")
+	send.addFlags(cmd)
+	return cmd
+}
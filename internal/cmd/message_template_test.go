@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func TestMessageTemplateConfirmCmd_Push(t *testing.T) {
+	var body map[string]any
+	cmd := newMessageTemplateConfirmCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--to", "U123", "--text", "Book for 7pm?", "--action", "message:Yes:yes", "--action", "postback:No:answer=no"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if body["to"] != "U123" {
+		t.Errorf("expected push to U123, got %v", body["to"])
+	}
+	msg := body["messages"].([]any)[0].(map[string]any)
+	template := msg["template"].(map[string]any)
+	actions := template["actions"].([]any)
+	if msg["type"] != "template" || msg["altText"] != "Book for 7pm?" || template["type"] != "confirm" || len(actions) != 2 {
+		t.Errorf("unexpected message %v", msg)
+	}
+	if a := actions[1].(map[string]any); a["type"] != "postback" || a["data"] != "answer=no" {
+		t.Errorf("unexpected postback action %v", a)
+	}
+	if !strings.Contains(out.String(), "Template sent to U123") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
+
+func TestMessageTemplateCarouselCmd_Columns(t *testing.T) {
+	var body map[string]any
+	cmd := newMessageTemplateCarouselCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--to", "U1,U2",
+		"--column", "title=Coffee;text=Hot drip;image=https://example.com/c.jpg;action=postback:Buy:item=coffee",
+		"--column", "title=Tea;text=Green tea;image=https://example.com/t.jpg;action=postback:Buy:item=tea",
+	})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if to, _ := body["to"].([]any); len(to) != 2 {
+		t.Errorf("expected multicast to 2 users, got %v", body["to"])
+	}
+	columns := body["messages"].([]any)[0].(map[string]any)["template"].(map[string]any)["columns"].([]any)
+	second := columns[1].(map[string]any)
+	if len(columns) != 2 || second["title"] != "Tea" || second["thumbnailImageUrl"] != "https://example.com/t.jpg" {
+		t.Errorf("unexpected columns %v", columns)
+	}
+}
+
+func TestMessageTemplateCmd_Invalid(t *testing.T) {
+	buttonsFile := filepath.Join(t.TempDir(), "buttons.json")
+	if err := os.WriteFile(buttonsFile, []byte(`{"type":"buttons","text":"Menu","actions":[{"type":"message","label":"A","text":"a"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	confirm, buttons, carousel := newMessageTemplateConfirmCmdWithClient, newMessageTemplateButtonsCmdWithClient, newMessageTemplateCarouselCmdWithClient
+	tests := []struct {
+		name    string
+		newCmd  func(*api.Client) *cobra.Command
+		args    []string
+		wantErr string
+	}{
+		{"confirm with three actions", confirm, []string{"--to", "U1", "--text", "Sure?", "--action", "message:A:a", "--action", "message:B:b", "--action", "message:C:c"}, "exactly 2 actions"},
+		{"uneven carousel", carousel, []string{"--to", "U1", "--column", "text=A;action=message:A:a;action=message:B:b", "--column", "text=B;action=message:A:a"}, "same number"},
+		{"bad column key", carousel, []string{"--to", "U1", "--column", "label=A"}, "invalid --column key"},
+		{"no recipient", buttons, []string{"--text", "Menu", "--action", "message:A:a"}, "exactly one of --to, --reply-token, or --broadcast"},
+		{"wrong file type", confirm, []string{"--to", "U1", "--file", buttonsFile}, "is a buttons template, not confirm"},
+		{"file and flags", buttons, []string{"--to", "U1", "--file", buttonsFile, "--text", "Menu"}, "cannot be used together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			cmd := tt.newCmd(captureRequest(t, &body))
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if body != nil {
+				t.Errorf("expected no request, got %v", body)
+			}
+		})
+	}
+}
+
+func TestMessageTemplateButtonsCmd_ReplyFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buttons.json")
+	if err := os.WriteFile(path, []byte(`{"type":"buttons","text":"Menu","actions":[{"type":"uri","label":"Site","uri":"https://example.com"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var body map[string]any
+	cmd := newMessageTemplateButtonsCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--reply-token", "reply-1", "--file", path, "--alt-text", "Our menu"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg := body["messages"].([]any)[0].(map[string]any)
+	if body["replyToken"] != "reply-1" || msg["altText"] != "Our menu" {
+		t.Errorf("unexpected reply %v", body)
+	}
+	if !strings.Contains(out.String(), "Template reply sent") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -1,9 +1,10 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
+	"net/http"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
 	"github.com/spf13/cobra"
@@ -23,7 +24,15 @@ func newMessageValidateCmdWithClient(client *api.Client) *cobra.Command {
 		Short: "Validate message objects",
 		Long: `Validate message objects before sending.
 Catches formatting errors without actually sending messages.
-Provide messages via --messages flag or --file flag (not both).`,
+Provide messages via --messages flag or --file flag (not both).
+
+The input may be a messages array, a single message object, or a whole
+request body with a "messages" field, such as a push payload, so the
+payloads a campaign will send can be linted as they are.
+
+When LINE rejects the messages, each field error is listed with its
+property path. With --output json the result has "valid": false and an
+"errors" array of {property, message} objects.`,
 		Example: `  # Validate a text message for push
   line message validate --type push --messages '[{"type":"text","text":"Hello"}]'
 
@@ -31,7 +40,10 @@ Provide messages via --messages flag or --file flag (not both).`,
   line message validate --type broadcast --messages '[{"type":"flex","altText":"Menu",...}]'
 
   # Validate from a JSON file
-  line message validate --type push --file messages.json`,
+  line message validate --type push --file messages.json
+
+  # Lint a narrowcast request body in CI
+  line message validate --type narrowcast --file payload.json --output json | jq -e .valid`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if messageType == "" {
 				return fmt.Errorf("--type is required (reply|push|multicast|narrowcast|broadcast)")
@@ -54,7 +66,7 @@ Provide messages via --messages flag or --file flag (not both).`,
 			// Get messages from file or flag
 			var messagesData []byte
 			if filePath != "" {
-				data, err := os.ReadFile(filePath)
+				data, err := readFileOrStdin(filePath)
 				if err != nil {
 					return fmt.Errorf("failed to read file: %w", err)
 				}
@@ -63,9 +75,9 @@ Provide messages via --messages flag or --file flag (not both).`,
 				messagesData = []byte(messagesJSON)
 			}
 
-			var messages []json.RawMessage
-			if err := json.Unmarshal(messagesData, &messages); err != nil {
-				return fmt.Errorf("invalid messages JSON: %w", err)
+			messages, err := parseValidateMessages(messagesData)
+			if err != nil {
+				return err
 			}
 
 			c := client
@@ -78,8 +90,22 @@ Provide messages via --messages flag or --file flag (not both).`,
 			}
 
 			if err := c.ValidateMessage(cmd.Context(), messageType, messages); err != nil {
+				apiErr := api.AsAPIError(err)
+				if apiErr == nil || apiErr.StatusCode != http.StatusBadRequest {
+					return fmt.Errorf("failed to validate messages: %w", err)
+				}
 				if flags.Output == "json" {
-					result := map[string]any{"valid": false, "error": err.Error()}
+					fieldErrors := make([]map[string]string, 0, len(apiErr.Details))
+					for _, d := range apiErr.Details {
+						fieldErrors = append(fieldErrors, map[string]string{"property": d.Property, "message": d.Message})
+					}
+					result := map[string]any{
+						"valid":        false,
+						"type":         messageType,
+						"messageCount": len(messages),
+						"error":        apiErr.Message,
+						"errors":       fieldErrors,
+					}
 					enc := json.NewEncoder(cmd.OutOrStdout())
 					enc.SetIndent("", "  ")
 					return enc.Encode(result)
@@ -100,8 +126,36 @@ Provide messages via --messages flag or --file flag (not both).`,
 
 	cmd.Flags().StringVar(&messageType, "type", "", "Message type: reply|push|multicast|narrowcast|broadcast (required)")
 	cmd.Flags().StringVar(&messagesJSON, "messages", "", "Messages JSON array")
-	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to JSON file containing messages array")
+	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to JSON file containing messages array, or - for stdin")
 	_ = cmd.MarkFlagRequired("type")
 
 	return cmd
 }
+
+// parseValidateMessages returns the messages in data, which may be a
+// messages array, a single message object, or a request body with a
+// "messages" field.
+func parseValidateMessages(data []byte) ([]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var body struct {
+			Type     string            `json:"type"`
+			Messages []json.RawMessage `json:"messages"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, fmt.Errorf("invalid messages JSON: %w", err)
+		}
+		if body.Messages != nil {
+			return body.Messages, nil
+		}
+		if body.Type != "" {
+			return []json.RawMessage{json.RawMessage(data)}, nil
+		}
+		return nil, fmt.Errorf("invalid messages JSON: expected a messages array, a message object, or an object with \"messages\"")
+	}
+	var messages []json.RawMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("invalid messages JSON: %w", err)
+	}
+	return messages, nil
+}
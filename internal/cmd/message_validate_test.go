@@ -294,3 +294,97 @@ func TestMessageValidateCmd_Execute_ValidationError_JSONOutput(t *testing.T) {
 		t.Error("expected error field in JSON output")
 	}
 }
+
+func TestMessageValidateCmd_FieldErrors_JSONOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"message": "The request body has 1 error(s)",
+			"details": []map[string]string{{"property": "messages[0].text", "message": "Length must be between 0 and 5000"}},
+		})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newMessageValidateCmdWithClient(client)
+	cmd.SetArgs([]string{"--type", "push", "--messages", `[{"type":"text","text":"x"}]`})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result struct {
+		Valid  bool                `json:"valid"`
+		Error  string              `json:"error"`
+		Errors []map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("expected valid JSON output: %v", err)
+	}
+	if result.Valid || result.Error != "The request body has 1 error(s)" {
+		t.Errorf("unexpected result: %s", out.String())
+	}
+	if len(result.Errors) != 1 || result.Errors[0]["property"] != "messages[0].text" {
+		t.Errorf("expected field errors, got %v", result.Errors)
+	}
+}
+
+func TestMessageValidateCmd_NonValidationErrorFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]any{"message": "Authentication failed"})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newMessageValidateCmdWithClient(client)
+	cmd.SetArgs([]string{"--type", "push", "--messages", `[{"type":"text","text":"x"}]`})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	if err == nil || ExitCode(err) != ExitAuth {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+}
+
+func TestParseValidateMessages(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"array", `[{"type":"text","text":"a"},{"type":"text","text":"b"}]`, 2},
+		{"single message", `{"type":"text","text":"a"}`, 1},
+		{"push payload", `{"to":"U123","messages":[{"type":"text","text":"a"}]}`, 1},
+		{"narrowcast payload", ` {"messages":[{"type":"text","text":"a"}],"recipient":{"type":"audience","audienceGroupId":1}}`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages, err := parseValidateMessages([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(messages) != tt.want {
+				t.Errorf("got %d messages, want %d", len(messages), tt.want)
+			}
+		})
+	}
+
+	if _, err := parseValidateMessages([]byte(`{"to":"U123"}`)); err == nil || !strings.Contains(err.Error(), "invalid messages JSON") {
+		t.Errorf("expected error for an object without messages, got %v", err)
+	}
+}
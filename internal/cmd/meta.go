@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// metaSchemaVersion is bumped when fields of the command metadata change
+// meaning or are removed; new fields may be added without a bump.
+const metaSchemaVersion = 1
+
+// Annotations cobra sets on flags in a group (MarkFlagsMutuallyExclusive,
+// MarkFlagsOneRequired, MarkFlagsRequiredTogether).
+const (
+	flagGroupMutuallyExclusive = "cobra_annotation_mutually_exclusive"
+	flagGroupOneRequired       = "cobra_annotation_one_required"
+	flagGroupRequiredTogether  = "cobra_annotation_required_if_others_set"
+)
+
+// metaCommands is the metadata of a command tree.
+type metaCommands struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	Version       string        `json:"version"`
+	GlobalFlags   []metaFlag    `json:"globalFlags"`
+	Commands      []metaCommand `json:"commands"`
+}
+
+// metaCommand describes one command. Flags are its own flags; every command
+// also accepts the global flags.
+type metaCommand struct {
+	Path       string          `json:"path"`
+	Parent     string          `json:"parent,omitempty"`
+	Name       string          `json:"name"`
+	Usage      string          `json:"usage"`
+	Aliases    []string        `json:"aliases,omitempty"`
+	Short      string          `json:"short"`
+	Long       string          `json:"long,omitempty"`
+	Example    string          `json:"example,omitempty"`
+	Runnable   bool            `json:"runnable"`
+	Hidden     bool            `json:"hidden,omitempty"`
+	Deprecated string          `json:"deprecated,omitempty"`
+	Flags      []metaFlag      `json:"flags"`
+	FlagGroups []metaFlagGroup `json:"flagGroups,omitempty"`
+	// Subcommands are the paths of the direct subcommands
+	Subcommands []string `json:"subcommands,omitempty"`
+}
+
+// metaFlag describes one flag. Type is pflag's type name: string, bool,
+// int, int64, float64, duration, stringSlice, stringArray, ...
+type metaFlag struct {
+	Name        string `json:"name"`
+	Shorthand   string `json:"shorthand,omitempty"`
+	Type        string `json:"type"`
+	Default     string `json:"default"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Repeatable  bool   `json:"repeatable,omitempty"`
+	Hidden      bool   `json:"hidden,omitempty"`
+	Deprecated  string `json:"deprecated,omitempty"`
+}
+
+// metaFlagGroup is a constraint on several flags: mutuallyExclusive (at
+// most one), oneRequired (at least one), or requiredTogether (all or none).
+type metaFlagGroup struct {
+	Kind  string   `json:"kind"`
+	Flags []string `json:"flags"`
+}
+
+func newMetaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "meta",
+		Short: "Describe the CLI for tools that drive it",
+	}
+	cmd.AddCommand(newMetaCommandsCmd())
+	return cmd
+}
+
+func newMetaCommandsCmd() *cobra.Command {
+	var includeHidden bool
+
+	cmd := &cobra.Command{
+		Use:   "commands [command...]",
+		Short: "List every command with its flags, types, and required flags",
+		Long: `List the command tree as metadata, so tools such as TUI wrappers, form
+generators, and AI agents can drive the CLI without parsing help text.
+
+With --output json, every command is described by its path, usage, help
+text, and flags: name, shorthand, type (string, bool, int, duration,
+stringSlice, ...), default, whether it is required, and groups of flags that
+are mutually exclusive, of which one is required, or that go together.
+Global flags, accepted by every command, are listed once. Name a command to
+describe only it and its subcommands.
+
+The JSON carries a schemaVersion, bumped only when existing fields change
+meaning or are removed.`,
+		Example: `  # The whole tree, for a tool to load
+  line meta commands --output json > line-commands.json
+
+  # One command
+  line meta commands message push --output json
+
+  # Runnable commands and their required flags, as a table
+  line meta commands`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			start := root
+			if len(args) > 0 {
+				found, rest, err := root.Find(args)
+				if err != nil || len(rest) > 0 || found == root {
+					return withExitCode(ExitUsage, fmt.Errorf("unknown command %q", strings.Join(args, " ")))
+				}
+				start = found
+			}
+
+			meta := metaCommands{
+				SchemaVersion: metaSchemaVersion,
+				Version:       version,
+				GlobalFlags:   describeFlags(root.PersistentFlags(), includeHidden),
+			}
+			var walk func(c *cobra.Command)
+			walk = func(c *cobra.Command) {
+				if !includeMetaCommand(c, includeHidden) {
+					return
+				}
+				meta.Commands = append(meta.Commands, describeCommand(c, includeHidden))
+				for _, sub := range c.Commands() {
+					walk(sub)
+				}
+			}
+			walk(start)
+
+			out := cmd.OutOrStdout()
+			if flags.Output == "json" {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(meta)
+			}
+
+			table := NewTable("COMMAND", "REQUIRED FLAGS", "DESCRIPTION")
+			for _, c := range meta.Commands {
+				if !c.Runnable {
+					continue
+				}
+				var required []string
+				for _, f := range c.Flags {
+					if f.Required {
+						required = append(required, "--"+f.Name)
+					}
+				}
+				table.AddRow(c.Path, strings.Join(required, " "), c.Short)
+			}
+			table.Render(out)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&includeHidden, "include-hidden", false, "Also list hidden commands and flags")
+
+	return cmd
+}
+
+// includeMetaCommand reports whether c belongs in the metadata. cobra's
+// generated help command is left out, as are hidden commands unless asked
+// for.
+func includeMetaCommand(c *cobra.Command, includeHidden bool) bool {
+	if c.Name() == "help" && c.Parent() != nil && c.Parent().Parent() == nil {
+		return false
+	}
+	return includeHidden || !c.Hidden
+}
+
+func describeCommand(c *cobra.Command, includeHidden bool) metaCommand {
+	m := metaCommand{
+		Path:       c.CommandPath(),
+		Name:       c.Name(),
+		Usage:      c.UseLine(),
+		Aliases:    c.Aliases,
+		Short:      c.Short,
+		Long:       c.Long,
+		Example:    c.Example,
+		Runnable:   c.Runnable(),
+		Hidden:     c.Hidden,
+		Deprecated: c.Deprecated,
+		Flags:      []metaFlag{},
+	}
+	if c.HasParent() {
+		m.Parent = c.Parent().CommandPath()
+	}
+	// The root's persistent flags are the global flags
+	local := c.LocalFlags()
+	if !c.HasParent() {
+		local = c.LocalNonPersistentFlags()
+	}
+	m.Flags = describeFlags(local, includeHidden)
+	m.FlagGroups = describeFlagGroups(local)
+	for _, sub := range c.Commands() {
+		if includeMetaCommand(sub, includeHidden) {
+			m.Subcommands = append(m.Subcommands, sub.CommandPath())
+		}
+	}
+	return m
+}
+
+func describeFlags(fs *pflag.FlagSet, includeHidden bool) []metaFlag {
+	result := []metaFlag{}
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" || (f.Hidden && !includeHidden) {
+			return
+		}
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		typ := f.Value.Type()
+		result = append(result, metaFlag{
+			Name:        f.Name,
+			Shorthand:   f.Shorthand,
+			Type:        typ,
+			Default:     f.DefValue,
+			Description: f.Usage,
+			Required:    required,
+			Repeatable:  strings.HasSuffix(typ, "Slice") || strings.HasSuffix(typ, "Array") || typ == "count",
+			Hidden:      f.Hidden,
+			Deprecated:  f.Deprecated,
+		})
+	})
+	return result
+}
+
+// describeFlagGroups collects the flag groups of a flag set. cobra records
+// a group on each of its flags as a space-separated list of the group's
+// flags.
+func describeFlagGroups(fs *pflag.FlagSet) []metaFlagGroup {
+	kinds := []struct {
+		annotation, kind string
+	}{
+		{flagGroupMutuallyExclusive, "mutuallyExclusive"},
+		{flagGroupOneRequired, "oneRequired"},
+		{flagGroupRequiredTogether, "requiredTogether"},
+	}
+	var groups []metaFlagGroup
+	seen := map[string]bool{}
+	for _, k := range kinds {
+		fs.VisitAll(func(f *pflag.Flag) {
+			for _, group := range f.Annotations[k.annotation] {
+				names := strings.Fields(group)
+				sort.Strings(names)
+				key := k.kind + ":" + strings.Join(names, " ")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				groups = append(groups, metaFlagGroup{Kind: k.kind, Flags: names})
+			}
+		})
+	}
+	return groups
+}
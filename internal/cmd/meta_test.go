@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func runMetaCommands(t *testing.T, args ...string) (metaCommands, error) {
+	t.Helper()
+	writeTestConfig(t, "")
+	cmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(append([]string{"meta", "commands", "--output", "json"}, args...))
+	var meta metaCommands
+	if err := cmd.Execute(); err != nil {
+		return meta, err
+	}
+	if err := json.Unmarshal(buf.Bytes(), &meta); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	return meta, nil
+}
+
+func findMetaCommand(meta metaCommands, path string) *metaCommand {
+	for i := range meta.Commands {
+		if meta.Commands[i].Path == path {
+			return &meta.Commands[i]
+		}
+	}
+	return nil
+}
+
+func findMetaFlag(fs []metaFlag, name string) *metaFlag {
+	for i := range fs {
+		if fs[i].Name == name {
+			return &fs[i]
+		}
+	}
+	return nil
+}
+
+func TestMetaCommands_Tree(t *testing.T) {
+	meta, err := runMetaCommands(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.SchemaVersion != metaSchemaVersion {
+		t.Errorf("schemaVersion = %d", meta.SchemaVersion)
+	}
+	if f := findMetaFlag(meta.GlobalFlags, "output"); f == nil || f.Type != "string" || f.Default != "text" {
+		t.Errorf("unexpected global --output: %+v", f)
+	}
+	if f := findMetaFlag(meta.GlobalFlags, "yes"); f == nil || f.Shorthand != "y" || f.Type != "bool" {
+		t.Errorf("unexpected global --yes: %+v", f)
+	}
+
+	root := findMetaCommand(meta, "line")
+	if root == nil || findMetaFlag(root.Flags, "output") != nil {
+		t.Errorf("global flags should not repeat on the root: %+v", root)
+	}
+	if findMetaCommand(meta, "line help") != nil {
+		t.Error("cobra's help command should be left out")
+	}
+
+	get := findMetaCommand(meta, "line audience get")
+	if get == nil || !get.Runnable || get.Parent != "line audience" {
+		t.Fatalf("unexpected audience get: %+v", get)
+	}
+	if f := findMetaFlag(get.Flags, "id"); f == nil || f.Required || f.Type != "int64" {
+		t.Errorf("unexpected audience get --id: %+v", f)
+	}
+	oneRequired := false
+	for _, g := range get.FlagGroups {
+		oneRequired = oneRequired || (g.Kind == "oneRequired" && strings.Join(g.Flags, ",") == "id,name")
+	}
+	if !oneRequired {
+		t.Errorf("expected --id or --name to be required: %+v", get.FlagGroups)
+	}
+	if findMetaFlag(get.Flags, "account") != nil {
+		t.Error("inherited global flags should not be listed per command")
+	}
+
+	audience := findMetaCommand(meta, "line audience")
+	if audience == nil || audience.Runnable || !strings.Contains(strings.Join(audience.Subcommands, ","), "line audience get") {
+		t.Errorf("unexpected audience group: %+v", audience)
+	}
+}
+
+func TestMetaCommands_Subtree(t *testing.T) {
+	meta, err := runMetaCommands(t, "message", "push")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Commands) != 1 || meta.Commands[0].Path != "line message push" {
+		t.Fatalf("unexpected commands: %+v", meta.Commands)
+	}
+	if f := findMetaFlag(meta.Commands[0].Flags, "to"); f == nil {
+		t.Error("expected --to on message push")
+	}
+
+	if _, err := runMetaCommands(t, "nope"); err == nil || ExitCode(err) != ExitUsage {
+		t.Errorf("expected a usage error for an unknown command, got %v", err)
+	}
+}
+
+func TestMetaCommands_FlagGroups(t *testing.T) {
+	meta, err := runMetaCommands(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range meta.Commands {
+		for _, g := range c.FlagGroups {
+			if g.Kind == "mutuallyExclusive" && strings.Join(g.Flags, " ") == "skip-invalid strict" {
+				return
+			}
+		}
+	}
+	t.Error("expected the --skip-invalid/--strict mutually exclusive group")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/mock"
+	"github.com/spf13/cobra"
+)
+
+func newMockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Run an in-memory stand-in for the LINE API",
+	}
+	cmd.AddCommand(newMockServeCmd())
+	return cmd
+}
+
+func newMockServeCmd() *cobra.Command {
+	var port int
+	var quota int
+	var followers int
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a mock Messaging API for rehearsing scripts",
+		Long: `Serve an in-memory mock of the Messaging API endpoints the CLI uses for
+messages, rich menus, and audiences, so scripts can be rehearsed end to end
+without touching a real channel.
+
+Point commands at it with --base-url (or LINE_API_BASE_URL). Any token is
+accepted, so a throwaway account is enough:
+
+  line auth login --token sandbox --name sandbox
+
+The mock checks requests the way LINE does where it matters to a rehearsal:
+unknown rich menus and audiences, malformed messages, user ID format, and
+the monthly --quota. Broadcasts reach --followers users. Endpoints it does
+not implement answer 404 saying so.
+
+State lives only in memory. GET /mock/state shows what was sent and created,
+and POST /mock/reset clears it.`,
+		Example: `  # Start the mock
+  line mock serve --port 9000
+
+  # In another terminal, rehearse against it
+  line auth login --token sandbox --name sandbox
+  line --account sandbox --base-url http://localhost:9000 richmenu create --file menu.json
+  line --account sandbox --base-url http://localhost:9000 message broadcast --text "Hello" --yes
+
+  # See what the script did
+  curl localhost:9000/mock/state`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if port < 0 || port > 65535 {
+				return withExitCode(ExitUsage, fmt.Errorf("--port must be between 0 and 65535"))
+			}
+			if quota < 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--quota must not be negative"))
+			}
+			if followers < 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--followers must not be negative"))
+			}
+			opts := mock.Options{Quota: quota, Followers: followers, Now: clockNow}
+			if !quiet {
+				opts.Log = cmd.ErrOrStderr()
+			}
+			return runMockServe(cmd, fmt.Sprintf("127.0.0.1:%d", port), mock.NewServer(opts), quota, followers)
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", 9000, "Port to listen on (localhost only)")
+	cmd.Flags().IntVar(&quota, "quota", 1000, "Monthly message quota (0 for unlimited)")
+	cmd.Flags().IntVar(&followers, "followers", 100, "Number of followers a broadcast reaches")
+	cmd.Flags().BoolVar(&quiet, "quiet", false, "Do not log requests")
+
+	return cmd
+}
+
+func runMockServe(cmd *cobra.Command, listen string, handler http.Handler, quota, followers int) error {
+	out := cmd.OutOrStdout()
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	printMockBanner(out, "http://localhost:"+port, quota, followers)
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("server error: %w", err)
+	case <-cmd.Context().Done():
+		_, _ = fmt.Fprintf(out, "\nShutting down...\n")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown error: %w", err)
+	}
+
+	return nil
+}
+
+func printMockBanner(out io.Writer, baseURL string, quota, followers int) {
+	quotaText := "unlimited"
+	if quota > 0 {
+		quotaText = fmt.Sprintf("%d messages", quota)
+	}
+	_, _ = fmt.Fprintf(out, "Mock LINE API listening on %s\n", baseURL)
+	_, _ = fmt.Fprintf(out, "Quota: %s, followers: %d\n", quotaText, followers)
+	_, _ = fmt.Fprintf(out, "\nRehearse against it with:\n")
+	_, _ = fmt.Fprintf(out, "  line --base-url %s ...\n", baseURL)
+	_, _ = fmt.Fprintf(out, "\nInspect state: GET %s/mock/state\n", baseURL)
+	_, _ = fmt.Fprintf(out, "Reset state:   POST %s/mock/reset\n", baseURL)
+	_, _ = fmt.Fprintf(out, "Press Ctrl+C to stop\n")
+}
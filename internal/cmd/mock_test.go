@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/mock"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+)
+
+func TestBaseURLFlag_SendsToMock(t *testing.T) {
+	server := httptest.NewServer(mock.NewServer(mock.Options{Quota: 100}))
+	defer server.Close()
+
+	writeTestConfig(t, "credential_store: file\n")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("LINE_API_BASE_URL", "")
+	t.Setenv("LINE_CREDENTIALS_PASSPHRASE", "")
+	cfg = &config.Config{CredentialStore: "file"}
+	store, err := openSecretsStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("sandbox", secrets.Credentials{ChannelAccessToken: "sandbox"}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--account", "sandbox", "--base-url", server.URL, "bot", "info"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("bot info against the mock: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Mock Bot") {
+		t.Errorf("expected the mock's bot info, got:\n%s", buf.String())
+	}
+}
+
+func TestBaseURLFlag_RejectsInvalidURL(t *testing.T) {
+	writeTestConfig(t, "")
+	cmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--base-url", "localhost:9000", "bot", "info"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --base-url") {
+		t.Fatalf("expected an invalid --base-url error, got %v", err)
+	}
+	if code := ExitCode(err); code != ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, ExitUsage)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe to write from the server goroutine
+// while the test reads it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestMockServeCmd_ServesUntilCancelled(t *testing.T) {
+	out := &lockedBuffer{}
+	cmd := newMockServeCmd()
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs([]string{"--port", "0", "--quota", "5"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.ExecuteContext(ctx) }()
+
+	listening := regexp.MustCompile(`listening on (http://localhost:\d+)`)
+	var baseURL string
+	for deadline := time.Now().Add(5 * time.Second); baseURL == "" && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if m := listening.FindStringSubmatch(out.String()); m != nil {
+			baseURL = m[1]
+		}
+	}
+	if baseURL == "" {
+		t.Fatalf("the mock did not start:\n%s", out.String())
+	}
+
+	// A client of its own, so its keep-alive connections can be closed
+	// before shutdown, which otherwise waits on them
+	client := &http.Client{Transport: &http.Transport{}}
+	resp, err := client.Get(baseURL + "/v2/bot/message/quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without a token = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, baseURL+"/v2/bot/message/quota", nil)
+	req.Header.Set("Authorization", "Bearer sandbox")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	var quota struct {
+		Type  string `json:"type"`
+		Value int    `json:"value"`
+	}
+	if err := json.Unmarshal(body, &quota); err != nil || quota.Type != "limited" || quota.Value != 5 {
+		t.Errorf("quota = %s, want limited 5", body)
+	}
+
+	client.CloseIdleConnections()
+	cancel()
+	// Longer than the 5s shutdown timeout, so a slow shutdown reports its
+	// error rather than timing out here
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("mock serve returned %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("mock serve did not stop")
+	}
+	if !strings.Contains(out.String(), "GET /v2/bot/message/quota -> 200") {
+		t.Errorf("expected the request log, got:\n%s", out.String())
+	}
+}
+
+func TestMockServeCmd_ValidatesFlags(t *testing.T) {
+	for _, args := range [][]string{{"--port", "70000"}, {"--quota", "-1"}, {"--followers", "-1"}} {
+		cmd := newMockServeCmd()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -55,10 +55,7 @@ explicit confirmation.`,
 
 			// Require confirmation for detach unless --yes is set
 			if !flags.Yes {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "This will detach the module from bot %s. Continue? [y/N]: ", botID)
-				var response string
-				_, _ = fmt.Fscanln(cmd.InOrStdin(), &response)
-				if response != "y" && response != "Y" && response != "yes" {
+				if !askYesNo(cmd.OutOrStdout(), cmd.InOrStdin(), fmt.Sprintf("This will detach the module from bot %s. Continue?", botID)) {
 					return fmt.Errorf("detach cancelled")
 				}
 			}
@@ -256,7 +253,7 @@ it is used to obtain one.`,
 			c := client
 			if c == nil {
 				// Create a minimal client (no auth token needed for this endpoint)
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			resp, err := c.ExchangeModuleToken(cmd.Context(), code, redirectURI, clientID, clientSecret)
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// japanPrefectures lists prefecture names in JIS order; jp_NN area codes
+// use the 1-based index.
+var japanPrefectures = []string{
+	"hokkaido", "aomori", "iwate", "miyagi", "akita", "yamagata", "fukushima",
+	"ibaraki", "tochigi", "gunma", "saitama", "chiba", "tokyo", "kanagawa",
+	"niigata", "toyama", "ishikawa", "fukui", "yamanashi", "nagano", "gifu",
+	"shizuoka", "aichi", "mie", "shiga", "kyoto", "osaka", "hyogo", "nara",
+	"wakayama", "tottori", "shimane", "okayama", "hiroshima", "yamaguchi",
+	"tokushima", "kagawa", "ehime", "kochi", "fukuoka", "saga", "nagasaki",
+	"kumamoto", "oita", "miyazaki", "kagoshima", "okinawa",
+}
+
+// areaCodePattern matches raw LINE area codes such as jp_13 or tw_01.
+var areaCodePattern = regexp.MustCompile(`^[a-z]{2}_\d{2}$`)
+
+// buildDemographicFilter assembles a demographic filter from the builder
+// flags. Values within one flag are alternatives (oneOf, or an "or" of age
+// ranges); different flags are combined with "and". Returns nil if no
+// filter flags are set.
+func buildDemographicFilter(genders, ages, regions, oses []string) (*api.DemographicFilter, error) {
+	var conditions []*api.DemographicFilter
+
+	if len(genders) > 0 {
+		values, err := normalizeChoices("--filter-gender", genders, "male", "female")
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, &api.DemographicFilter{Type: "gender", OneOf: values})
+	}
+
+	if len(ages) > 0 {
+		var ranges []*api.DemographicFilter
+		for _, a := range ages {
+			r, err := parseAgeRange(a)
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, r)
+		}
+		if len(ranges) == 1 {
+			conditions = append(conditions, ranges[0])
+		} else {
+			conditions = append(conditions, &api.DemographicFilter{Type: "operator", Or: ranges})
+		}
+	}
+
+	if len(regions) > 0 {
+		var codes []any
+		for _, r := range regions {
+			code, err := resolveAreaCode(r)
+			if err != nil {
+				return nil, err
+			}
+			codes = append(codes, code)
+		}
+		conditions = append(conditions, &api.DemographicFilter{Type: "area", OneOf: codes})
+	}
+
+	if len(oses) > 0 {
+		values, err := normalizeChoices("--filter-os", oses, "ios", "android")
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, &api.DemographicFilter{Type: "appType", OneOf: values})
+	}
+
+	switch len(conditions) {
+	case 0:
+		return nil, nil
+	case 1:
+		return conditions[0], nil
+	default:
+		return &api.DemographicFilter{Type: "operator", And: conditions}, nil
+	}
+}
+
+// normalizeChoices lowercases values and checks each is one of allowed.
+func normalizeChoices(flag string, values []string, allowed ...string) ([]any, error) {
+	out := make([]any, 0, len(values))
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !containsFold(allowed, v) {
+			return nil, fmt.Errorf("invalid %s value %q: use %s", flag, v, strings.Join(allowed, ", "))
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// parseAgeRange converts an inclusive age range such as "20-34", "50-", or
+// "-19" into an age condition. LINE age buckets are 5 years wide from 15 to
+// 70, so the lower bound must be a multiple of 5 and the upper bound must
+// end in 4 or 9.
+func parseAgeRange(s string) (*api.DemographicFilter, error) {
+	s = strings.TrimSpace(s)
+	lo, hi, ok := strings.Cut(s, "-")
+	if !ok || (lo == "" && hi == "") {
+		return nil, fmt.Errorf("invalid --filter-age %q: use a range like 20-34, 50-, or -19", s)
+	}
+
+	f := &api.DemographicFilter{Type: "age"}
+	if lo != "" {
+		n, err := strconv.Atoi(lo)
+		if err != nil || n%5 != 0 || n < 15 || n > 70 {
+			return nil, fmt.Errorf("invalid --filter-age %q: lower bound must be 15, 20, ... 70", s)
+		}
+		f.Gte = fmt.Sprintf("age_%d", n)
+	}
+	if hi != "" {
+		n, err := strconv.Atoi(hi)
+		if err != nil || (n+1)%5 != 0 || n+1 < 20 || n+1 > 70 {
+			return nil, fmt.Errorf("invalid --filter-age %q: upper bound must be 19, 24, ... 69", s)
+		}
+		f.Lt = fmt.Sprintf("age_%d", n+1)
+	}
+	if f.Gte != "" && f.Lt != "" && f.Gte >= f.Lt {
+		return nil, fmt.Errorf("invalid --filter-age %q: lower bound must be below upper bound", s)
+	}
+	return f, nil
+}
+
+// resolveAreaCode maps a Japanese prefecture name (e.g. tokyo) or a raw
+// LINE area code (e.g. jp_13, tw_01) to an area code.
+func resolveAreaCode(region string) (string, error) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if areaCodePattern.MatchString(region) {
+		return region, nil
+	}
+	for i, name := range japanPrefectures {
+		if region == name {
+			return fmt.Sprintf("jp_%02d", i+1), nil
+		}
+	}
+	return "", fmt.Errorf("unknown --filter-region %q: use a Japanese prefecture name (e.g. tokyo) or an area code (e.g. jp_13)", region)
+}
+
+// readDemographicFilterFile reads a filter from a JSON file. The file may
+// contain the full filter object ({"demographic": {...}}) or just the
+// demographic condition. Unknown fields are rejected to catch typos.
+func readDemographicFilterFile(path string) (*api.DemographicFilter, error) {
+	data, err := readFileOrStdin(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read filter file: %w", err)
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid filter file: %w", err)
+	}
+	if inner, ok := probe["demographic"]; ok {
+		data = inner
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var f api.DemographicFilter
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid filter file: %w", err)
+	}
+	if err := validateDemographicFilter(&f, "demographic"); err != nil {
+		return nil, fmt.Errorf("invalid filter file: %w", err)
+	}
+	return &f, nil
+}
+
+// validateDemographicFilter checks operator nesting in a filter tree.
+func validateDemographicFilter(f *api.DemographicFilter, path string) error {
+	if f == nil {
+		return fmt.Errorf("%s: empty condition", path)
+	}
+	if f.Type == "" {
+		return fmt.Errorf("%s: missing type", path)
+	}
+
+	if f.Type != "operator" {
+		if len(f.And) > 0 || len(f.Or) > 0 || f.Not != nil {
+			return fmt.Errorf("%s: and/or/not require type \"operator\"", path)
+		}
+		return nil
+	}
+
+	set := 0
+	for _, present := range []bool{len(f.And) > 0, len(f.Or) > 0, f.Not != nil} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%s: operator must have exactly one of and, or, not", path)
+	}
+	for i, c := range f.And {
+		if err := validateDemographicFilter(c, fmt.Sprintf("%s.and[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	for i, c := range f.Or {
+		if err := validateDemographicFilter(c, fmt.Sprintf("%s.or[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	if f.Not != nil {
+		return validateDemographicFilter(f.Not, path+".not")
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestBuildDemographicFilter(t *testing.T) {
+	f, err := buildDemographicFilter([]string{"female"}, []string{"20-34", "50-"}, []string{"tokyo", "jp_27"}, []string{"iOS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := json.Marshal(f)
+	want := `{"type":"operator","and":[` +
+		`{"type":"gender","oneOf":["female"]},` +
+		`{"type":"operator","or":[{"type":"age","gte":"age_20","lt":"age_35"},{"type":"age","gte":"age_50"}]},` +
+		`{"type":"area","oneOf":["jp_13","jp_27"]},` +
+		`{"type":"appType","oneOf":["ios"]}]}`
+	if string(got) != want {
+		t.Errorf("unexpected filter:\n got: %s\nwant: %s", got, want)
+	}
+
+	// A single condition is not wrapped in an operator
+	f, err = buildDemographicFilter([]string{"male"}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Type != "gender" {
+		t.Errorf("expected bare gender condition, got %+v", f)
+	}
+
+	if f, _ := buildDemographicFilter(nil, nil, nil, nil); f != nil {
+		t.Errorf("expected nil filter, got %+v", f)
+	}
+}
+
+func TestBuildDemographicFilter_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		genders []string
+		ages    []string
+		regions []string
+		oses    []string
+		wantErr string
+	}{
+		{"bad gender", []string{"other"}, nil, nil, nil, "--filter-gender"},
+		{"unaligned lower bound", nil, []string{"22-34"}, nil, nil, "lower bound"},
+		{"unaligned upper bound", nil, []string{"20-30"}, nil, nil, "upper bound"},
+		{"inverted range", nil, []string{"40-24"}, nil, nil, "below upper bound"},
+		{"not a range", nil, []string{"20"}, nil, nil, "use a range"},
+		{"unknown region", nil, nil, []string{"atlantis"}, nil, "--filter-region"},
+		{"bad os", nil, nil, nil, []string{"windows"}, "--filter-os"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildDemographicFilter(tt.genders, tt.ages, tt.regions, tt.oses)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReadDemographicFilterFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	f, err := readDemographicFilterFile(write("full.json", `{"demographic":{"type":"operator","not":{"type":"gender","oneOf":["male"]}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Not == nil || f.Not.Type != "gender" {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown field", `{"type":"gender","oneOff":["male"]}`, "unknown field"},
+		{"operator without children", `{"type":"operator"}`, "exactly one of and, or, not"},
+		{"nested missing type", `{"type":"operator","and":[{"oneOf":["male"]}]}`, "demographic.and[0]: missing type"},
+		{"children without operator", `{"type":"age","and":[{"type":"gender"}]}`, "require type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readDemographicFilterFile(write(tt.name+".json", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMessageNarrowcastCmd_FilterFlags(t *testing.T) {
+	var capturedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("X-Line-Request-Id", "req-1")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newMessageNarrowcastCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--text", "Hi", "--filter-gender", "female", "--filter-region", "osaka"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var req struct {
+		Filter struct {
+			Demographic struct {
+				Type string `json:"type"`
+				And  []struct {
+					Type  string   `json:"type"`
+					OneOf []string `json:"oneOf"`
+				} `json:"and"`
+			} `json:"demographic"`
+		} `json:"filter"`
+	}
+	if err := json.Unmarshal(capturedBody, &req); err != nil {
+		t.Fatalf("invalid request body: %v", err)
+	}
+	d := req.Filter.Demographic
+	if d.Type != "operator" || len(d.And) != 2 || d.And[1].OneOf[0] != "jp_27" {
+		t.Errorf("unexpected filter in request: %s", capturedBody)
+	}
+}
+
+func TestMessageNarrowcastCmd_FilterFileConflictsWithFlags(t *testing.T) {
+	cmd := newMessageNarrowcastCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--text", "Hi", "--filter-file", "f.json", "--filter-os", "ios"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("expected conflict error, got: %v", err)
+	}
+}
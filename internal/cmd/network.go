@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// networkTransport is the transport API clients send requests with, set up
+// from --proxy, --ca-cert, --insecure-skip-verify, and --connect-timeout.
+// nil uses http.DefaultTransport, which already honors HTTPS_PROXY and
+// NO_PROXY.
+var networkTransport http.RoundTripper
+
+// setupNetwork builds networkTransport from the flags, warning on stderr
+// when certificate verification is turned off.
+func setupNetwork(stderr io.Writer) error {
+	networkTransport = nil
+	opts := api.NetworkOptions{
+		Proxy:              flags.Proxy,
+		CACertFile:         expandHome(flags.CACert),
+		InsecureSkipVerify: flags.InsecureSkipVerify,
+		ConnectTimeout:     flags.ConnectTimeout,
+	}
+	if opts.IsZero() {
+		return nil
+	}
+	transport, err := api.NewTransport(opts)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if opts.InsecureSkipVerify {
+		warnf(stderr, "TLS certificate verification is disabled (--insecure-skip-verify). Anyone on the network path can read and change API requests, including your channel credentials. Use --ca-cert instead where you can.")
+	}
+	networkTransport = transport
+	return nil
+}
+
+// applyNetwork makes c send its requests with networkTransport, bounded by
+// --timeout. It must be called before the transport is wrapped (SetRecord,
+// SetTrace, ...).
+func applyNetwork(c *api.Client) {
+	c.SetTimeout(flags.Timeout)
+	if networkTransport != nil {
+		c.SetTransport(networkTransport)
+	}
+}
+
+// newPlainAPIClient returns a client without an access token, for the
+// token endpoints that authenticate with channel credentials instead.
+func newPlainAPIClient() *api.Client {
+	c := api.NewClient("", flags.Debug, flags.DryRun)
+	applyNetwork(c)
+	c.SetLogger(logger)
+	return c
+}
+
+// expandHome replaces a leading ~/ in path with the home directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
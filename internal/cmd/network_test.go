@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestSetupNetwork(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags; networkTransport = nil }()
+
+	flags.Proxy, flags.CACert, flags.InsecureSkipVerify, flags.ConnectTimeout = "", "", false, 0
+	var stderr bytes.Buffer
+	if err := setupNetwork(&stderr); err != nil || networkTransport != nil {
+		t.Fatalf("expected the default transport, got %v, %v", networkTransport, err)
+	}
+
+	flags.Proxy = "http://proxy.example.com:8080"
+	flags.InsecureSkipVerify = true
+	if err := setupNetwork(&stderr); err != nil || networkTransport == nil {
+		t.Fatalf("expected a custom transport, got %v, %v", networkTransport, err)
+	}
+	if !strings.Contains(stderr.String(), "TLS certificate verification is disabled") {
+		t.Errorf("expected an --insecure-skip-verify warning, got %q", stderr.String())
+	}
+
+	flags.Proxy = "ftp://proxy.example.com"
+	err := setupNetwork(&stderr)
+	if err == nil || ExitCode(err) != ExitUsage {
+		t.Fatalf("expected a usage error for an ftp proxy, got %v", err)
+	}
+}
+
+func TestApplyNetwork_Timeout(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags; networkTransport = nil }()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	flags.Proxy, flags.CACert, flags.InsecureSkipVerify = "", "", false
+	flags.ConnectTimeout = time.Second
+	flags.Timeout = 50 * time.Millisecond
+	if err := setupNetwork(&bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := api.NewClient("test-token", false, false)
+	applyNetwork(client)
+	client.SetBaseURL(server.URL)
+
+	start := time.Now()
+	_, err := client.GetBotInfo(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %s despite --timeout", elapsed)
+	}
+}
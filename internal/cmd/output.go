@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/salmonumbrella/line-official-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// activeFormat converts the running command's JSON output to yaml or csv,
+// or filters it with --query, if requested.
+var activeFormat *output.Writer
+
+// ndjsonStreamAnnotation marks commands that write --output ndjson
+// themselves, a line per item as pages arrive. The JSON output of other
+// commands is converted to NDJSON when they end.
+const ndjsonStreamAnnotation = "ndjson-stream"
+
+// sessionAnnotation marks commands that run other commands, such as 'line
+// repl', which set up --output and --query for themselves.
+const sessionAnnotation = "session"
+
+// installOutputFormat checks --output and --query. For yaml, csv, or a
+// query, it has the command print JSON into a writer that converts it when
+// the command ends, so commands only need to support json to support them.
+func installOutputFormat(cmd *cobra.Command) error {
+	format := flags.Output
+	switch format {
+	case "text", "json", "table":
+		format = output.JSON
+	case output.YAML, output.CSV:
+	case output.NDJSON:
+		if _, ok := cmd.Annotations[ndjsonStreamAnnotation]; ok && flags.Query == "" {
+			return nil
+		}
+	default:
+		return fmt.Errorf("invalid --output %q: must be text, json, table, yaml, csv, or ndjson", flags.Output)
+	}
+	if _, ok := cmd.Annotations[sessionAnnotation]; ok {
+		return nil
+	}
+
+	root := cmd.Root()
+	switch {
+	case flags.Query != "":
+		query, err := output.ParseQuery(flags.Query)
+		if err != nil {
+			return err
+		}
+		activeFormat = output.NewQueryWriter(root.OutOrStdout(), format, query)
+	case format != output.JSON:
+		activeFormat = output.NewWriter(root.OutOrStdout(), format)
+	default:
+		return nil
+	}
+	flags.Output = "json"
+	root.SetOut(activeFormat)
+	return nil
+}
+
+// closeOutputFormat writes the converted output of the running command, if
+// any.
+func closeOutputFormat() error {
+	if activeFormat == nil {
+		return nil
+	}
+	err := activeFormat.Close()
+	activeFormat = nil
+	return err
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputFormat_YAML(t *testing.T) {
+	writeTestConfig(t, "output: table\n")
+	t.Setenv("LINE_OUTPUT", "")
+	t.Setenv("LINE_COLOR", "")
+
+	out, err := runConfigTestCmd(t, "--output", "yaml", "config", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "- key: account\n") || !strings.Contains(out, "- key: output\n  value: table\n  source: file\n") {
+		t.Errorf("unexpected YAML:\n%s", out)
+	}
+}
+
+func TestOutputFormat_CSV(t *testing.T) {
+	writeTestConfig(t, "color: never\n")
+	t.Setenv("LINE_COLOR", "")
+
+	out, err := runConfigTestCmd(t, "--output", "csv", "config", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "key,value,source\n") || !strings.Contains(out, "\ncolor,never,file\n") {
+		t.Errorf("unexpected CSV:\n%s", out)
+	}
+}
+
+func TestOutputFormat_NDJSON(t *testing.T) {
+	writeTestConfig(t, "color: never\n")
+	t.Setenv("LINE_COLOR", "")
+
+	out, err := runConfigTestCmd(t, "--output", "ndjson", "config", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, `{"key":"account",`) || !strings.Contains(out, "\n"+`{"key":"color","value":"never","source":"file"}`+"\n") {
+		t.Errorf("unexpected NDJSON:\n%s", out)
+	}
+}
+
+func TestOutputFormat_NonJSONPassesThrough(t *testing.T) {
+	writeTestConfig(t, "")
+
+	out, err := runConfigTestCmd(t, "--output", "csv", "config", "set", "color", "never")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "Set color = never") {
+		t.Errorf("got %q", out)
+	}
+}
+
+func TestOutputFormat_Invalid(t *testing.T) {
+	writeTestConfig(t, "")
+
+	_, err := runConfigTestCmd(t, "--output", "xml", "config", "list")
+	if err == nil || !strings.Contains(err.Error(), "invalid --output") {
+		t.Errorf("expected output error, got %v", err)
+	}
+}
+
+func TestOutputQuery(t *testing.T) {
+	writeTestConfig(t, "timezone: Asia/Tokyo\n")
+	t.Setenv("LINE_TIMEZONE", "")
+
+	_, err := runConfigTestCmd(t, "--query", ".[] | select", "config", "list")
+	if err == nil || !strings.Contains(err.Error(), "invalid query") {
+		t.Errorf("expected query error, got %v", err)
+	}
+
+	out, err := runConfigTestCmd(t, "--query", ".[4].value", "config", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "Asia/Tokyo\n" {
+		t.Errorf("got %q", out)
+	}
+
+	out, err = runConfigTestCmd(t, "--output", "csv", "--query", ".[4]", "config", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "key,value,source\ntimezone,Asia/Tokyo,file\n" {
+		t.Errorf("got %q", out)
+	}
+}
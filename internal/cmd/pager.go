@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// noPagerAnnotation marks commands that stream output or stay running
+// (servers, dashboards) and must never be paged.
+const noPagerAnnotation = "line.no-pager"
+
+// activePager is the pager installed for the running command, if any.
+var activePager *pagerWriter
+
+// pagerWriter buffers command output until it exceeds the terminal height,
+// then starts the pager and streams everything through it. Output that fits
+// on one screen is written straight to the terminal when the command ends.
+type pagerWriter struct {
+	out    io.Writer
+	height int
+	// start launches the pager. header is the number of leading lines to
+	// keep pinned at the top (0 for none).
+	start func(header int) (io.WriteCloser, func() error, error)
+
+	buf         bytes.Buffer
+	lines       int
+	passthrough bool
+	pipe        io.WriteCloser
+	wait        func() error
+}
+
+func (p *pagerWriter) Write(b []byte) (int, error) {
+	switch {
+	case p.pipe != nil:
+		return p.pipe.Write(b)
+	case p.passthrough:
+		return p.out.Write(b)
+	}
+
+	p.buf.Write(b)
+	p.lines += bytes.Count(b, []byte("\n"))
+	if p.lines < p.height {
+		return len(b), nil
+	}
+
+	pipe, wait, err := p.start(tableHeaderLines(p.buf.Bytes()))
+	if err != nil {
+		// No usable pager; fall back to plain output
+		p.Flush()
+		return len(b), nil
+	}
+	p.pipe, p.wait = pipe, wait
+	_, _ = p.pipe.Write(p.buf.Bytes())
+	p.buf.Reset()
+	return len(b), nil
+}
+
+// Flush writes buffered output to the terminal and stops paging. It is
+// called before reading from stdin so prompts are visible.
+func (p *pagerWriter) Flush() {
+	if p.pipe != nil || p.passthrough {
+		return
+	}
+	_, _ = p.out.Write(p.buf.Bytes())
+	p.buf.Reset()
+	p.passthrough = true
+}
+
+// Close flushes short output or waits for the user to quit the pager.
+func (p *pagerWriter) Close() error {
+	if p.pipe == nil {
+		p.Flush()
+		return nil
+	}
+	_ = p.pipe.Close()
+	return p.wait()
+}
+
+// flushingReader flushes the pager before each read so a prompt written to
+// stdout is shown before the command waits for input.
+type flushingReader struct {
+	r io.Reader
+	p *pagerWriter
+}
+
+func (f *flushingReader) Read(b []byte) (int, error) {
+	f.p.Flush()
+	return f.r.Read(b)
+}
+
+// tableSeparator matches the separator row printed by Table.Render.
+var tableSeparator = regexp.MustCompile(`^[─ ]+$`)
+
+// tableHeaderLines returns 2 if out begins with a rendered table (header
+// and separator rows), so the pager can keep them on screen while
+// scrolling, and 0 otherwise.
+func tableHeaderLines(out []byte) int {
+	lines := strings.SplitN(string(out), "\n", 3)
+	if len(lines) == 3 && lines[0] != "" && tableSeparator.MatchString(lines[1]) {
+		return 2
+	}
+	return 0
+}
+
+// installPager routes the command's stdout through a pager when it is
+// writing text or table output to a terminal. LINE_PAGER overrides PAGER;
+// setting either to "cat" or "" disables paging, as does --no-pager.
+func installPager(cmd *cobra.Command) {
+	if flags.NoPager || flags.Output == "json" || flags.Output == "ndjson" || activePager != nil {
+		return
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[noPagerAnnotation]; ok {
+			return
+		}
+	}
+	// --watch redraws the terminal until interrupted
+	if f := cmd.Flags().Lookup("watch"); f != nil && f.Changed {
+		return
+	}
+	if cmd.OutOrStdout() != os.Stdout || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return
+	}
+	pager, ok := pagerCommand()
+	if !ok {
+		return
+	}
+
+	activePager = &pagerWriter{
+		out:    os.Stdout,
+		height: terminalHeight(),
+		start: func(header int) (io.WriteCloser, func() error, error) {
+			return startPager(pager, header)
+		},
+	}
+	root := cmd.Root()
+	root.SetIn(&flushingReader{r: cmd.InOrStdin(), p: activePager})
+	root.SetOut(activePager)
+}
+
+// closePager waits for the pager started by installPager, if any.
+func closePager() {
+	if activePager == nil {
+		return
+	}
+	_ = activePager.Close()
+	activePager = nil
+}
+
+// pagerCommand returns the pager to run, or false if paging is disabled.
+func pagerCommand() (string, bool) {
+	for _, name := range []string{"LINE_PAGER", "PAGER"} {
+		if v, ok := os.LookupEnv(name); ok {
+			v = strings.TrimSpace(v)
+			return v, v != "" && v != "cat"
+		}
+	}
+	return "less", true
+}
+
+// terminalHeight returns the number of rows on stdout's terminal, falling
+// back to $LINES and then 24.
+func terminalHeight() int {
+	if _, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && h > 0 {
+		return h
+	}
+	if h, err := strconv.Atoi(os.Getenv("LINES")); err == nil && h > 0 {
+		return h
+	}
+	return 24
+}
+
+// startPager runs pager through the shell with stdin connected to the
+// returned writer. Like git, it sets LESS=FRX when LESS is unset.
+func startPager(pager string, header int) (io.WriteCloser, func() error, error) {
+	if header > 0 && isLess(pager) && lessSupportsHeader() {
+		pager += " --header=" + strconv.Itoa(header)
+	}
+	c := exec.Command("sh", "-c", pager)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		c.Env = append(c.Env, "LESS=FRX")
+	}
+	pipe, err := c.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Start(); err != nil {
+		return nil, nil, err
+	}
+	return pipe, c.Wait, nil
+}
+
+func isLess(pager string) bool {
+	fields := strings.Fields(pager)
+	return len(fields) > 0 && filepath.Base(fields[0]) == "less"
+}
+
+var lessVersion = regexp.MustCompile(`less (\d+)`)
+
+// lessSupportsHeader reports whether the installed less has --header,
+// which was added in version 600.
+func lessSupportsHeader() bool {
+	out, err := exec.Command("less", "--version").Output()
+	if err != nil {
+		return false
+	}
+	m := lessVersion.FindSubmatch(out)
+	if m == nil {
+		return false
+	}
+	v, _ := strconv.Atoi(string(m[1]))
+	return v >= 600
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+type fakePager struct {
+	buf    bytes.Buffer
+	header int
+	closed bool
+}
+
+func (f *fakePager) Write(b []byte) (int, error) { return f.buf.Write(b) }
+func (f *fakePager) Close() error                { f.closed = true; return nil }
+
+func newTestPager(out io.Writer, height int) (*pagerWriter, *fakePager, *int) {
+	fake := &fakePager{}
+	starts := 0
+	p := &pagerWriter{
+		out:    out,
+		height: height,
+		start: func(header int) (io.WriteCloser, func() error, error) {
+			starts++
+			fake.header = header
+			return fake, func() error { return nil }, nil
+		},
+	}
+	return p, fake, &starts
+}
+
+func TestPagerWriter_ShortOutputSkipsPager(t *testing.T) {
+	var out bytes.Buffer
+	p, _, starts := newTestPager(&out, 10)
+
+	_, _ = fmt.Fprintln(p, "line 1")
+	_, _ = fmt.Fprintln(p, "line 2")
+	if out.Len() != 0 {
+		t.Errorf("expected output to be buffered until close, got %q", out.String())
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *starts != 0 {
+		t.Errorf("expected pager not to start, started %d times", *starts)
+	}
+	if out.String() != "line 1\nline 2\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestPagerWriter_LongOutputStartsPager(t *testing.T) {
+	var out bytes.Buffer
+	p, fake, starts := newTestPager(&out, 5)
+
+	for i := 1; i <= 8; i++ {
+		_, _ = fmt.Fprintf(p, "line %d\n", i)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *starts != 1 {
+		t.Errorf("expected pager to start once, started %d times", *starts)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected nothing written directly, got %q", out.String())
+	}
+	if !strings.HasPrefix(fake.buf.String(), "line 1\n") || !strings.HasSuffix(fake.buf.String(), "line 8\n") {
+		t.Errorf("expected all lines in pager, got %q", fake.buf.String())
+	}
+	if !fake.closed {
+		t.Error("expected pager input to be closed")
+	}
+	if fake.header != 0 {
+		t.Errorf("expected no sticky header for plain text, got %d", fake.header)
+	}
+}
+
+func TestPagerWriter_TableKeepsHeader(t *testing.T) {
+	var out bytes.Buffer
+	p, fake, _ := newTestPager(&out, 5)
+
+	table := NewTable("ID", "NAME")
+	for i := 0; i < 10; i++ {
+		table.AddRow(fmt.Sprint(i), "item")
+	}
+	table.Render(p)
+	_ = p.Close()
+
+	if fake.header != 2 {
+		t.Errorf("expected header and separator to be pinned, got %d", fake.header)
+	}
+}
+
+func TestPagerWriter_FallsBackWhenPagerFails(t *testing.T) {
+	var out bytes.Buffer
+	p := &pagerWriter{
+		out:    &out,
+		height: 2,
+		start: func(int) (io.WriteCloser, func() error, error) {
+			return nil, nil, errors.New("no pager")
+		},
+	}
+
+	for i := 1; i <= 4; i++ {
+		_, _ = fmt.Fprintf(p, "line %d\n", i)
+	}
+	_ = p.Close()
+
+	if out.String() != "line 1\nline 2\nline 3\nline 4\n" {
+		t.Errorf("expected plain output, got %q", out.String())
+	}
+}
+
+func TestFlushingReader_ShowsPromptBeforeRead(t *testing.T) {
+	var out bytes.Buffer
+	p, _, starts := newTestPager(&out, 10)
+	in := &flushingReader{r: strings.NewReader("y\n"), p: p}
+
+	_, _ = fmt.Fprint(p, "Continue? [y/N]: ")
+	var response string
+	_, _ = fmt.Fscanln(in, &response)
+
+	if out.String() != "Continue? [y/N]: " {
+		t.Errorf("expected prompt to be flushed before reading, got %q", out.String())
+	}
+	if response != "y" {
+		t.Errorf("expected response y, got %q", response)
+	}
+
+	// Output after the prompt is no longer paged
+	for i := 0; i < 20; i++ {
+		_, _ = fmt.Fprintln(p, "row")
+	}
+	if *starts != 0 {
+		t.Errorf("expected pager not to start after flush, started %d times", *starts)
+	}
+}
+
+func TestTableHeaderLines(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want int
+	}{
+		{"table", "ID  NAME\n──  ────\n1   a\n", 2},
+		{"text", "Name: a\nID: 1\nStatus: ok\n", 0},
+		{"short", "ID\n", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tableHeaderLines([]byte(tt.out)); got != tt.want {
+				t.Errorf("tableHeaderLines() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		linePager string
+		pager     string
+		want      string
+		wantOK    bool
+	}{
+		{"default", "", "", "less", true},
+		{"PAGER", "", "more", "more", true},
+		{"LINE_PAGER wins", "less -S", "more", "less -S", true},
+		{"cat disables", "", "cat", "cat", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LINE_PAGER", tt.linePager)
+			t.Setenv("PAGER", tt.pager)
+			// t.Setenv restores the variables; unset the empty ones
+			if tt.linePager == "" {
+				_ = os.Unsetenv("LINE_PAGER")
+			}
+			if tt.pager == "" {
+				_ = os.Unsetenv("PAGER")
+			}
+			got, ok := pagerCommand()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("pagerCommand() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestInstallPager_SkipsNonTerminal(t *testing.T) {
+	cmd := NewRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"version"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if activePager != nil {
+		t.Error("expected no pager when stdout is not a terminal")
+	}
+}
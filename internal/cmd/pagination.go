@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// pageFlags are the pagination flags shared by list commands: --all,
+// --limit, and --cursor. The older --start still works as a hidden alias of
+// --cursor, without a deprecation notice that would corrupt JSON output.
+type pageFlags struct {
+	all    bool
+	limit  int
+	cursor string
+}
+
+// addPageFlags adds the pagination flags to cmd. noun names the listed
+// items in the flag help, e.g. "follower IDs".
+func addPageFlags(cmd *cobra.Command, p *pageFlags, noun string) {
+	cmd.Flags().BoolVar(&p.all, "all", false, "Fetch every page of "+noun)
+	cmd.Flags().IntVar(&p.limit, "limit", 0, "Return at most this many "+noun+", fetching as many pages as needed")
+	cmd.Flags().StringVar(&p.cursor, "cursor", "", "Continue from the \"next\" cursor of a previous call")
+	cmd.Flags().StringVar(&p.cursor, "start", "", "Continue from the \"next\" cursor of a previous call")
+	_ = cmd.Flags().MarkHidden("start")
+}
+
+// options returns the api.PageOptions for the flags, with the command's
+// page size and the API's largest one (0 if it cannot be chosen).
+func (p pageFlags) options(pageSize, maxPageSize int) (api.PageOptions, error) {
+	if p.limit < 0 {
+		return api.PageOptions{}, withExitCode(ExitUsage, fmt.Errorf("--limit cannot be negative"))
+	}
+	return api.PageOptions{
+		Cursor:      p.cursor,
+		Limit:       p.limit,
+		All:         p.all,
+		PageSize:    pageSize,
+		MaxPageSize: maxPageSize,
+	}, nil
+}
+
+// listing reports whether any pagination flag was given, for commands that
+// list items only on request.
+func (p pageFlags) listing() bool {
+	return p.all || p.limit > 0 || p.cursor != ""
+}
+
+// printNextCursor writes the "Next cursor" line of text output when there
+// are more items to fetch.
+func printNextCursor(w io.Writer, next string) {
+	if next != "" {
+		_, _ = fmt.Fprintf(w, "\nNext cursor: %s (continue with --cursor %s)\n", next, next)
+	}
+}
+
+// streamNDJSON writes what fetch returns as --output ndjson, one line per
+// item as each page arrives, with line giving the JSON value of an item.
+// The next cursor, if any, goes to stderr so stdout stays pure NDJSON.
+func streamNDJSON[T any](cmd *cobra.Command, opts api.PageOptions, fetch api.PageFunc[T], line func(T) any) error {
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	next, err := api.PaginateEach(cmd.Context(), opts, fetch, func(items []T) error {
+		for _, item := range items {
+			if err := enc.Encode(line(item)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	printNextCursor(cmd.ErrOrStderr(), next)
+	return nil
+}
+
+// userIDLine is the NDJSON line of a user ID.
+func userIDLine(id string) any {
+	return map[string]string{"userId": id}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// idPages serves IDs U1..U<total> from path, as many per page as the limit
+// query asks (defaultSize without one), with the next ID number as the
+// cursor, and total from any members/count endpoint. Each page request is
+// recorded as "start/limit".
+func idPages(t *testing.T, path, field string, total, defaultSize int, requests *[]string) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/members/count") {
+			_ = json.NewEncoder(w).Encode(map[string]int{"count": total})
+			return
+		}
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		*requests = append(*requests, q.Get("start")+"/"+q.Get("limit"))
+		first, size := 1, defaultSize
+		if s := q.Get("start"); s != "" {
+			first, _ = strconv.Atoi(s)
+		}
+		if l := q.Get("limit"); l != "" {
+			size, _ = strconv.Atoi(l)
+		}
+		ids := []string{}
+		for n := first; n <= total && len(ids) < size; n++ {
+			ids = append(ids, fmt.Sprintf("U%d", n))
+		}
+		resp := map[string]any{field: ids}
+		if first+len(ids) <= total {
+			resp["next"] = strconv.Itoa(first + len(ids))
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestBotFollowersCmd_Limit(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	var requests []string
+	client := idPages(t, "/v2/bot/followers/ids", "userIds", 5000, 1000, &requests)
+	cmd := newBotFollowersCmdWithClient(client)
+	cmd.SetArgs([]string{"--limit", "2500"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(requests, ","); got != "/1000,1001/1000,2001/500" {
+		t.Errorf("requests = %s", got)
+	}
+	if !strings.Contains(out.String(), "Followers: 2500") || !strings.Contains(out.String(), "Next cursor: 2501") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestGroupMembersCmd_CursorAndLimit(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	var requests []string
+	client := idPages(t, "/v2/bot/group/C1/members/ids", "memberIds", 250, 100, &requests)
+	cmd := newGroupMembersCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "C1", "--cursor", "101", "--limit", "150"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(requests, ","); got != "101/,201/" {
+		t.Errorf("requests = %s", got)
+	}
+	var result struct {
+		Count     int      `json:"count"`
+		MemberIDs []string `json:"memberIds"`
+		Next      string   `json:"next"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	// Pages of 100 and 50 IDs reach the limit exactly at the end of the group
+	if result.Count != 250 || len(result.MemberIDs) != 150 || result.MemberIDs[0] != "U101" || result.Next != "" {
+		t.Errorf("unexpected result: count %d, %d IDs from %v, next %q", result.Count, len(result.MemberIDs), result.MemberIDs[:1], result.Next)
+	}
+}
+
+// firstWriteCalls records how many requests had been made when output was
+// first written.
+type firstWriteCalls struct {
+	bytes.Buffer
+	requests *[]string
+	calls    int
+}
+
+func (w *firstWriteCalls) Write(p []byte) (int, error) {
+	if w.Len() == 0 {
+		w.calls = len(*w.requests)
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestBotFollowersCmd_NDJSONStreams(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "ndjson"
+
+	var requests []string
+	client := idPages(t, "/v2/bot/followers/ids", "userIds", 2500, 1000, &requests)
+	cmd := newBotFollowersCmdWithClient(client)
+	cmd.SetArgs([]string{"--all"})
+	out := &firstWriteCalls{requests: &requests}
+	var errOut bytes.Buffer
+	cmd.SetOut(out)
+	cmd.SetErr(&errOut)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.calls != 1 || len(requests) != 3 {
+		t.Errorf("expected output after the first of 3 requests, got after %d of %d", out.calls, len(requests))
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2500 || lines[0] != `{"userId":"U1"}` || lines[2499] != `{"userId":"U2500"}` {
+		t.Errorf("unexpected NDJSON: %d lines, first %s", len(lines), lines[0])
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("expected nothing on stderr after the last page, got %q", errOut.String())
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// spinnerFrames are drawn on stderr while waiting in text mode.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// pollUntil calls poll every interval until it reports done, drawing a
+// spinner on stderr in text mode. poll returns the current state, which is
+// shown in the spinner and in the timeout error. It returns the number of
+// polls made.
+func pollUntil(cmd *cobra.Command, label string, timeout, interval time.Duration, poll func(ctx context.Context) (done bool, state string, err error)) (int, error) {
+	if timeout <= 0 || interval <= 0 {
+		return 0, fmt.Errorf("--timeout and --interval must be positive")
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	start := clockNow()
+	errOut := cmd.ErrOrStderr()
+	showSpinner := flags.Output != "json"
+	clearSpinner := func() {
+		if showSpinner {
+			_, _ = fmt.Fprint(errOut, "\r\033[K")
+		}
+	}
+
+	var state string
+	for polls := 1; ; polls++ {
+		done, s, err := poll(ctx)
+		if err != nil {
+			clearSpinner()
+			if ctx.Err() == context.DeadlineExceeded {
+				return polls, fmt.Errorf("timed out after %s waiting for %s (last status: %s)", timeout, label, state)
+			}
+			return polls, err
+		}
+		state = s
+		if done {
+			if polls > 1 {
+				clearSpinner()
+			}
+			return polls, nil
+		}
+
+		if showSpinner {
+			elapsed := clockNow().Sub(start).Round(time.Second)
+			_, _ = fmt.Fprintf(errOut, "\r%s Waiting for %s: %s (%s)", spinnerFrames[polls%len(spinnerFrames)], label, state, elapsed)
+		}
+
+		select {
+		case <-ctx.Done():
+			clearSpinner()
+			if ctx.Err() == context.DeadlineExceeded {
+				return polls, fmt.Errorf("timed out after %s waiting for %s (last status: %s)", timeout, label, state)
+			}
+			return polls, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audiencemanifest"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/capabilities"
+	"github.com/salmonumbrella/line-official-cli/internal/followersnapshot"
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/salmonumbrella/line-official-cli/internal/tokenhealth"
+	"github.com/spf13/cobra"
+)
+
+// accountData is one kind of locally stored data held for an account.
+type accountData struct {
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+	// remove deletes the data
+	remove func() error
+}
+
+// findAccountData lists everything stored locally for account.
+func findAccountData(store secrets.Store, account string) ([]accountData, error) {
+	var found []accountData
+
+	if creds, err := store.Get(account); err == nil {
+		found = append(found, accountData{
+			Kind:   "credentials",
+			Detail: credentialsDetail(store, creds),
+			remove: func() error { return store.Delete(account) },
+		})
+	}
+
+	records, err := tokenhealth.Load()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := records[account]; ok {
+		found = append(found, accountData{
+			Kind:   "token-health",
+			Detail: "token usage and auth failure record",
+			remove: func() error {
+				_, err := tokenhealth.Forget(account)
+				return err
+			},
+		})
+	}
+
+	caps, err := capabilities.Load()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := caps[account]; ok {
+		found = append(found, accountData{
+			Kind:   "capabilities",
+			Detail: "plan features found by 'line account capabilities'",
+			remove: func() error {
+				_, err := capabilities.Forget(account)
+				return err
+			},
+		})
+	}
+
+	n, err := audit.CountAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		found = append(found, accountData{
+			Kind:   "audit-log",
+			Detail: fmt.Sprintf("%d send/override log entries", n),
+			remove: func() error {
+				_, err := audit.RemoveAccount(account)
+				return err
+			},
+		})
+	}
+
+	n, err = audiencemanifest.CountAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		found = append(found, accountData{
+			Kind:   "audience-manifests",
+			Detail: fmt.Sprintf("uploaded user IDs for %d audience groups", n),
+			remove: func() error {
+				_, err := audiencemanifest.RemoveAccount(account)
+				return err
+			},
+		})
+	}
+
+	n, err = followersnapshot.CountAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		found = append(found, accountData{
+			Kind:   "follower-snapshots",
+			Detail: fmt.Sprintf("%d follower ID snapshots", n),
+			remove: func() error {
+				_, err := followersnapshot.RemoveAccount(account)
+				return err
+			},
+		})
+	}
+
+	jobs, err := schedule.Open()
+	if err != nil {
+		return nil, err
+	}
+	n, err = jobs.CountAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		found = append(found, accountData{
+			Kind:   "scheduled-jobs",
+			Detail: fmt.Sprintf("%d scheduled message or coupon close job(s)", n),
+			remove: func() error {
+				_, err := jobs.RemoveAccount(account)
+				return err
+			},
+		})
+	}
+
+	cacheDir, err := responseCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	n, err = api.CountCache(cacheDir, account)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		found = append(found, accountData{
+			Kind:   "response-cache",
+			Detail: fmt.Sprintf("%d cached API response(s)", n),
+			remove: func() error {
+				_, err := api.ClearCache(cacheDir, account)
+				return err
+			},
+		})
+	}
+
+	n, err = ledger.CountAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		found = append(found, accountData{
+			Kind:   "ledger",
+			Detail: fmt.Sprintf("%d sent messages in the message history", n),
+			remove: func() error {
+				_, err := ledger.RemoveAccount(account)
+				return err
+			},
+		})
+	}
+
+	return found, nil
+}
+
+// credentialsDetail describes stored credentials and the store they are
+// kept in.
+func credentialsDetail(store secrets.Store, creds *secrets.Credentials) string {
+	what := "channel access token"
+	if creds.UsesChannelSecret() {
+		what = "channel ID and secret"
+	}
+	where := "the credential store"
+	if s, ok := store.(interface{ Kind() string }); ok {
+		switch s.Kind() {
+		case secrets.StoreKeychain:
+			where = "the OS keychain"
+		case secrets.StoreFile:
+			where = "the credentials file"
+		case secrets.StoreEncryptedFile:
+			where = "the encrypted credentials file"
+		}
+	}
+	return what + " in " + where
+}
+
+func newPurgeCmd() *cobra.Command {
+	return newPurgeCmdWithStore(nil)
+}
+
+func newPurgeCmdWithStore(store secrets.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Remove all local data for an account",
+		Long: `Remove everything this CLI stores locally for one account: its stored
+credentials (in the keychain or credentials file, whichever is configured),
+the token health record and plan capabilities, the account's entries in the
+send/override audit log, its audience upload manifests and follower
+snapshots, its scheduled messages and coupon closes (so 'line scheduler
+run' no longer runs them), its cached API responses, and its message
+history in the ledger, tracked sends included.
+
+Use --dry-run to list what would be removed. Nothing on the LINE platform
+is changed.`,
+		Example: `  # See what would be removed
+  line purge --account old-client --dry-run
+
+  # Remove it without prompting
+  line purge --account old-client --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Never fall back to the primary account: the target must be named
+			account := flags.Account
+			if account == "" {
+				return fmt.Errorf("--account is required")
+			}
+
+			var err error
+			if store == nil {
+				store, err = openSecretsStore()
+				if err != nil {
+					return fmt.Errorf("failed to open keyring: %w", err)
+				}
+			}
+
+			found, err := findAccountData(store, account)
+			if err != nil {
+				return fmt.Errorf("failed to inspect local data: %w", err)
+			}
+
+			if flags.DryRun || len(found) == 0 {
+				return printPurgeResult(cmd, account, found, false)
+			}
+
+			if !flags.Yes {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "This will remove all local data for account %q:\n", account)
+				for _, d := range found {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  - %s: %s\n", d.Kind, d.Detail)
+				}
+				if !askYesNo(cmd.OutOrStdout(), cmd.InOrStdin(), "Continue?") {
+					return fmt.Errorf("purge cancelled")
+				}
+			}
+
+			for _, d := range found {
+				if err := d.remove(); err != nil {
+					return fmt.Errorf("failed to remove %s: %w", d.Kind, err)
+				}
+			}
+			return printPurgeResult(cmd, account, found, true)
+		},
+	}
+
+	return cmd
+}
+
+// printPurgeResult reports removed (or, for a dry run, removable) data.
+func printPurgeResult(cmd *cobra.Command, account string, found []accountData, removed bool) error {
+	if flags.Output == "json" {
+		if found == nil {
+			found = []accountData{}
+		}
+		result := map[string]any{"account": account, "removed": removed, "data": found}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(found) == 0 {
+		_, _ = fmt.Fprintf(out, "No local data found for account %q\n", account)
+		return nil
+	}
+	if removed {
+		_, _ = fmt.Fprintf(out, "Removed local data for account %q:\n", account)
+	} else {
+		_, _ = fmt.Fprintf(out, "Would remove local data for account %q:\n", account)
+	}
+	for _, d := range found {
+		_, _ = fmt.Fprintf(out, "  - %s: %s\n", d.Kind, d.Detail)
+	}
+	return nil
+}
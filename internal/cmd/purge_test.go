@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audiencemanifest"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/salmonumbrella/line-official-cli/internal/tokenhealth"
+)
+
+// seedPurgeData stores credentials, token health, audit entries, an
+// audience manifest, a scheduled job, and a cached response for
+// "old-client" plus an audit entry, a scheduled job, and a cached response
+// for another account.
+func seedPurgeData(t *testing.T) *mockSecretsStore {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	store := newMockStore()
+	_ = store.Set("old-client", secrets.Credentials{ChannelAccessToken: "tok"}, "")
+	_ = store.Set("prod", secrets.Credentials{ChannelAccessToken: "tok2"}, "")
+	if err := tokenhealth.Observe("old-client", tokenhealth.TokenID("tok"), true, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range []string{"old-client", "prod", "old-client"} {
+		if err := audit.Append(audit.Entry{Account: account, Action: "campaign.send"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := audiencemanifest.Record("old-client", 42, []string{"U1"}); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := schedule.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, job := range []schedule.Job{
+		{ID: "old1", Account: "old-client", Type: schedule.TypeCouponClose, CouponID: "c1", At: time.Now().Add(time.Hour)},
+		{ID: "prod1", Account: "prod", Type: schedule.TypeCouponClose, CouponID: "c2", At: time.Now().Add(time.Hour)},
+	} {
+		if err := jobs.Add(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	dir, err := responseCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range []string{"old-client", "prod"} {
+		client := api.NewClient("test-token", false, false)
+		client.SetBaseURL(server.URL)
+		if err := client.SetCache(api.CacheOptions{Dir: dir, Key: account, TTL: time.Hour}); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = client.Get(context.Background(), "/v2/bot/info")
+	}
+	return store
+}
+
+func TestPurgeCmd_DryRunListsData(t *testing.T) {
+	store := seedPurgeData(t)
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.DryRun, flags.Output = "old-client", true, "text"
+
+	cmd := newPurgeCmdWithStore(store)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"Would remove", "credentials", "token-health", "2 send/override log entries", "audience groups", "1 scheduled message or coupon close job(s)", "1 cached API response(s)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, out.String())
+		}
+	}
+	if _, err := store.Get("old-client"); err != nil {
+		t.Error("dry run must not remove credentials")
+	}
+}
+
+func TestPurgeCmd_RemovesOnlyTargetAccount(t *testing.T) {
+	store := seedPurgeData(t)
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.Yes, flags.Output = "old-client", true, "text"
+
+	cmd := newPurgeCmdWithStore(store)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := store.Get("old-client"); err == nil {
+		t.Error("expected credentials to be removed")
+	}
+	if _, err := store.Get("prod"); err != nil {
+		t.Error("other account's credentials must be kept")
+	}
+	if rec, _ := tokenhealth.Get("old-client", tokenhealth.TokenID("tok")); rec != nil {
+		t.Error("expected token health record to be removed")
+	}
+	if n, _ := audit.CountAccount("old-client"); n != 0 {
+		t.Errorf("expected audit entries to be removed, %d remain", n)
+	}
+	if n, _ := audit.CountAccount("prod"); n != 1 {
+		t.Errorf("expected other account's audit entry to be kept, got %d", n)
+	}
+	if n, _ := audiencemanifest.CountAccount("old-client"); n != 0 {
+		t.Errorf("expected audience manifests to be removed, %d remain", n)
+	}
+	jobs, err := schedule.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := jobs.CountAccount("old-client"); n != 0 {
+		t.Errorf("expected scheduled jobs to be removed, %d remain", n)
+	}
+	if n, _ := jobs.CountAccount("prod"); n != 1 {
+		t.Errorf("expected other account's scheduled job to be kept, got %d", n)
+	}
+	dir, err := responseCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := api.CountCache(dir, "old-client"); n != 0 {
+		t.Errorf("expected cached responses to be removed, %d remain", n)
+	}
+	if n, _ := api.CountCache(dir, "prod"); n != 1 {
+		t.Errorf("expected other account's cached response to be kept, got %d", n)
+	}
+}
+
+func TestPurgeCmd_RequiresAccountAndConfirmation(t *testing.T) {
+	store := seedPurgeData(t)
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags = rootFlags{Output: "text"}
+
+	cmd := newPurgeCmdWithStore(store)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--account is required") {
+		t.Errorf("expected account error, got: %v", err)
+	}
+
+	flags.Account = "old-client"
+	cmd = newPurgeCmdWithStore(store)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader("n\n"))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "purge cancelled") {
+		t.Errorf("expected cancellation, got: %v", err)
+	}
+	if _, err := store.Get("old-client"); err != nil {
+		t.Error("cancelled purge must not remove credentials")
+	}
+}
+
+func TestCredentialsDetail(t *testing.T) {
+	creds := &secrets.Credentials{ChannelAccessToken: "tok"}
+	if got := credentialsDetail(secrets.NewFileStore(t.TempDir()+"/credentials"), creds); got != "channel access token in the credentials file" {
+		t.Errorf("file store: got %q", got)
+	}
+	if got := credentialsDetail(newMockStore(), &secrets.Credentials{ChannelID: "1", ChannelSecret: "s"}); got != "channel ID and secret in the credential store" {
+		t.Errorf("mock store: got %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/salmonumbrella/line-official-cli/internal/messagespec"
+	"github.com/spf13/cobra"
+)
+
+// quickReplyFlags are the --quick-reply flags of the message commands.
+type quickReplyFlags struct {
+	inline string
+	file   string
+}
+
+func (q *quickReplyFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&q.inline, "quick-reply", "", `Quick reply items as JSON, e.g. '[{"type":"action","action":{"type":"message","label":"Yes","text":"yes"}}]'`)
+	cmd.Flags().StringVar(&q.file, "quick-reply-file", "", "Read quick reply items from a JSON file, or - for stdin")
+}
+
+// load returns the validated quick reply items, or nil if none were given.
+func (q *quickReplyFlags) load() (json.RawMessage, error) {
+	data := []byte(q.inline)
+	switch {
+	case q.inline != "" && q.file != "":
+		return nil, fmt.Errorf("--quick-reply and --quick-reply-file cannot be used together")
+	case q.file != "":
+		var err error
+		if data, err = readFileOrStdin(q.file); err != nil {
+			return nil, fmt.Errorf("failed to read quick reply file: %w", err)
+		}
+	case q.inline == "":
+		return nil, nil
+	}
+	return messagespec.ParseQuickReply(data)
+}
+
+// attachQuickReplyFlags returns message with the quick reply from q
+// attached.
+func attachQuickReplyFlags(message any, q *quickReplyFlags) (any, error) {
+	items, err := q.load()
+	if err != nil {
+		return nil, err
+	}
+	return attachQuickReply(message, items)
+}
+
+// attachQuickReply returns message with the quick reply items attached, or message
+// itself if there are none.
+func attachQuickReply(message any, items json.RawMessage) (any, error) {
+	if len(items) == 0 {
+		return message, nil
+	}
+	return messagespec.WithQuickReply(message, items)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+const testQuickReply = `[{"type":"action","action":{"type":"message","label":"Yes","text":"yes"}},{"type":"action","action":{"type":"camera","label":"Camera"}}]`
+
+func TestMessagePushCmd_QuickReply(t *testing.T) {
+	var body map[string]any
+	cmd := newMessagePushCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--to", "U123", "--text", "Pick one", "--quick-reply", testQuickReply})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := body["messages"].([]any)[0].(map[string]any)
+	if msg["type"] != "text" || msg["text"] != "Pick one" {
+		t.Errorf("unexpected message %v", msg)
+	}
+	qr, ok := msg["quickReply"].(map[string]any)
+	if !ok || len(qr["items"].([]any)) != 2 {
+		t.Errorf("expected 2 quick reply items, got %v", msg["quickReply"])
+	}
+}
+
+func TestMessageReplyCmd_QuickReplyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qr.json")
+	if err := os.WriteFile(path, []byte(`{"items":`+testQuickReply+`}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var body map[string]any
+	cmd := newMessageReplyCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--token", "reply-token", "--text", "Pick one", "--quick-reply-file", path})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := body["messages"].([]any)[0].(map[string]any)
+	if qr, ok := msg["quickReply"].(map[string]any); !ok || len(qr["items"].([]any)) != 2 {
+		t.Errorf("expected 2 quick reply items, got %v", msg["quickReply"])
+	}
+}
+
+func TestMessagePushCmd_InvalidQuickReplyNotSent(t *testing.T) {
+	items := strings.TrimSuffix(strings.Repeat(`{"type":"action","action":{"type":"location","label":"Here"}},`, 14), ",")
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"too many items", []string{"--quick-reply", "[" + items + "]"}, "1 to 13 items"},
+		{"unsupported action", []string{"--quick-reply", `[{"type":"action","action":{"type":"richmenuswitch","label":"Menu","data":"d"}}]`}, "not supported in quick replies"},
+		{"both flags", []string{"--quick-reply", testQuickReply, "--quick-reply-file", "qr.json"}, "cannot be used together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+			client := api.NewClient("test-token", false, false)
+			client.SetBaseURL(server.URL)
+
+			cmd := newMessagePushCmdWithClient(client)
+			cmd.SetArgs(append([]string{"--to", "U123", "--text", "Pick one"}, tt.args...))
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if requests != 0 {
+				t.Errorf("expected no requests, got %d", requests)
+			}
+		})
+	}
+}
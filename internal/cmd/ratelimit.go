@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// rateLimitOff disables the client-side rate limiter.
+const rateLimitOff = "off"
+
+// rateLimits returns the per-class rate limits from the config file and
+// --rate-limit, where later entries win. A bare rate applies to every
+// class and "off" disables limiting, in which case ok is false.
+func rateLimits(fromConfig map[string]string, overrides []string) (limits map[string]api.Rate, ok bool, err error) {
+	limits = make(map[string]api.Rate)
+	ok = true
+	set := func(source, class, value string) error {
+		if strings.TrimSpace(value) == rateLimitOff {
+			ok = false
+			return nil
+		}
+		rate, err := api.ParseRate(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", source, err)
+		}
+		if class == "" {
+			for c := range api.DefaultRateLimits {
+				limits[c] = rate
+			}
+			return nil
+		}
+		if _, known := api.DefaultRateLimits[class]; !known {
+			return fmt.Errorf("invalid %s: unknown endpoint class %q (classes: %s)", source, class, strings.Join(rateLimitClasses(), ", "))
+		}
+		limits[class] = rate
+		return nil
+	}
+
+	classes := make([]string, 0, len(fromConfig))
+	for class := range fromConfig {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		if err := set("rate_limits."+class, class, fromConfig[class]); err != nil {
+			return nil, false, err
+		}
+	}
+	for _, o := range overrides {
+		class, value, hasClass := strings.Cut(o, "=")
+		if !hasClass {
+			class, value = "", o
+		}
+		if err := set("--rate-limit "+o, strings.TrimSpace(class), value); err != nil {
+			return nil, false, err
+		}
+	}
+	return limits, ok, nil
+}
+
+// rateLimitClasses returns the endpoint classes in alphabetical order.
+func rateLimitClasses() []string {
+	classes := make([]string, 0, len(api.DefaultRateLimits))
+	for class := range api.DefaultRateLimits {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	return classes
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestRateLimits(t *testing.T) {
+	limits, ok, err := rateLimits(map[string]string{"multicast": "100/s", "profile": "500/s"}, []string{"multicast=50/s"})
+	if err != nil || !ok {
+		t.Fatalf("rateLimits = %v, %v", ok, err)
+	}
+	if limits["multicast"] != (api.Rate{Requests: 50, Per: time.Second}) {
+		t.Errorf("multicast = %v, want the flag to win", limits["multicast"])
+	}
+	if limits["profile"] != (api.Rate{Requests: 500, Per: time.Second}) {
+		t.Errorf("profile = %v", limits["profile"])
+	}
+	if _, set := limits["broadcast"]; set {
+		t.Error("expected broadcast to keep its default")
+	}
+
+	// A bare rate applies to every class
+	limits, _, _ = rateLimits(nil, []string{"10/s"})
+	if len(limits) != len(api.DefaultRateLimits) || limits["broadcast"] != (api.Rate{Requests: 10, Per: time.Second}) {
+		t.Errorf("limits = %v", limits)
+	}
+
+	if _, ok, err := rateLimits(map[string]string{"default": "100/s"}, []string{"off"}); err != nil || ok {
+		t.Errorf("expected off to disable limiting, got %v, %v", ok, err)
+	}
+}
+
+func TestRateLimits_Invalid(t *testing.T) {
+	if _, _, err := rateLimits(map[string]string{"pushes": "10/s"}, nil); err == nil || !strings.Contains(err.Error(), "unknown endpoint class") {
+		t.Errorf("expected unknown class error, got %v", err)
+	}
+	if _, _, err := rateLimits(nil, []string{"multicast=fast"}); err == nil || !strings.Contains(err.Error(), "--rate-limit multicast=fast") {
+		t.Errorf("expected flag error, got %v", err)
+	}
+}
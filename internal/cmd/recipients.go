@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+)
+
+// maxMulticastRecipients is how many users one multicast can go to.
+const maxMulticastRecipients = 500
+
+// expandRecipients returns the user IDs of --to values in order, each
+// once. A value starting with @ names a file of user IDs, one per line, so
+// "--to @users.txt" sends to every user in the file once, even users also
+// named by another --to.
+func expandRecipients(values []string) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		path, ok := strings.CutPrefix(v, "@")
+		if !ok {
+			if v != "" {
+				add(v)
+			}
+			continue
+		}
+		_, err := readUsersFile(path, userids.Options{}, usersFileBatchSize, func(batch []string) error {
+			for _, id := range batch {
+				add(id)
+			}
+			return nil
+		})
+		if errors.Is(err, userids.ErrNoUsers) {
+			return nil, fmt.Errorf("--to %s: no user IDs found in file", v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("--to %s: %w", v, err)
+		}
+	}
+	return ids, nil
+}
+
+// setRecipients addresses target to ids: a push for one user, or a
+// multicast for several.
+func setRecipients(target *messageTarget, ids []string) error {
+	switch {
+	case len(ids) == 0:
+		return fmt.Errorf("--to is required: specify a user ID")
+	case len(ids) == 1:
+		target.Type, target.UserID = "push", ids[0]
+	case len(ids) > maxMulticastRecipients:
+		return fmt.Errorf("too many users: max %d per request, got %d", maxMulticastRecipients, len(ids))
+	default:
+		target.Type, target.UserIDs = "multicast", ids
+	}
+	return nil
+}
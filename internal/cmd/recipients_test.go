@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func writeRecipientsFile(t *testing.T, ids ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "users.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(ids, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExpandRecipients(t *testing.T) {
+	a, b := "U"+strings.Repeat("a", 32), "U"+strings.Repeat("b", 32)
+	file := writeRecipientsFile(t, a, b, a)
+
+	got, err := expandRecipients([]string{"U1", "@" + file, " U2 ", "U1", b})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(got, ",") != "U1,"+a+","+b+",U2" {
+		t.Errorf("unexpected recipients %v", got)
+	}
+
+	if _, err := expandRecipients([]string{"@" + writeRecipientsFile(t, a, "not-an-id")}); err == nil || !strings.Contains(err.Error(), "--to @") {
+		t.Errorf("expected an invalid ID error naming the file, got %v", err)
+	}
+	if _, err := expandRecipients([]string{"@" + filepath.Join(t.TempDir(), "missing.txt")}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestSetRecipients(t *testing.T) {
+	var target messageTarget
+	if err := setRecipients(&target, []string{"U1"}); err != nil || target.Type != "push" || target.UserID != "U1" {
+		t.Errorf("expected a push, got %+v, %v", target, err)
+	}
+	target = messageTarget{}
+	if err := setRecipients(&target, []string{"U1", "U2"}); err != nil || target.Type != "multicast" || len(target.UserIDs) != 2 {
+		t.Errorf("expected a multicast, got %+v, %v", target, err)
+	}
+	if err := setRecipients(&messageTarget{}, nil); err == nil || !strings.Contains(err.Error(), "--to is required") {
+		t.Errorf("expected --to is required, got %v", err)
+	}
+	if err := setRecipients(&messageTarget{}, make([]string, maxMulticastRecipients+1)); err == nil || !strings.Contains(err.Error(), "max 500") {
+		t.Errorf("expected too many users, got %v", err)
+	}
+}
+
+func TestMessagePushCmd_MultipleRecipientsMulticast(t *testing.T) {
+	a, b, c := "U"+strings.Repeat("a", 32), "U"+strings.Repeat("b", 32), "U"+strings.Repeat("c", 32)
+	file := writeRecipientsFile(t, b, c)
+
+	var path string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var out bytes.Buffer
+	cmd := newMessagePushCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--to", a, "--to", "@" + file, "--text", "Hello!"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/v2/bot/message/multicast" {
+		t.Errorf("expected a multicast, got %s", path)
+	}
+	if to, _ := body["to"].([]any); len(to) != 3 {
+		t.Errorf("expected 3 recipients, got %v", body["to"])
+	}
+	if !strings.Contains(out.String(), "Message sent to 3 users") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	// A user named again, directly or in a file, is sent to once
+	cmd = newMessagePushCmdWithClient(client)
+	out.Reset()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--to", a, "--to", a, "--to", "@" + writeRecipientsFile(t, a), "--text", "Hello!"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/v2/bot/message/push" || body["to"] != a {
+		t.Errorf("expected a push to %s, got %s to %v", a, path, body["to"])
+	}
+
+	cmd = newMessagePushCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--to", a + "," + b, "--bundle", t.TempDir()})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--bundle sends to one user") {
+		t.Errorf("expected a --bundle error, got %v", err)
+	}
+}
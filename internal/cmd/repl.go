@@ -0,0 +1,465 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/repl"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// replBuiltins are the session commands of 'line repl', offered by Tab
+// completion next to the CLI's commands.
+var replBuiltins = []string{"exit", "quit", "use", "history", "refresh"}
+
+// replIDCompleters complete the values of flags that take a resource ID, by
+// the commands and flag they apply to. The first match wins; an empty kind
+// completes nothing.
+var replIDCompleters = []struct {
+	commands, flag, kind string
+}{
+	{"line audience shared", "id", ""},
+	{"line audience", "id", "audience"},
+	{"line message narrowcast", "audience", "audience"},
+	{"line richmenu", "alias", "alias"},
+	{"line richmenu", "id", "richmenu"},
+	{"line liff", "id", "liff"},
+}
+
+func newReplCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "repl",
+		Short:       "Run commands interactively with a warm API client",
+		Annotations: map[string]string{noPagerAnnotation: "", sessionAnnotation: ""},
+		Long: `Start an interactive session that reads commands, without the leading
+'line', and runs them one after another. The account's credentials are read
+and its API client set up once, then reused by every command, so there is
+no start-up cost between commands.
+
+Global flags given to 'line repl' apply to every command, and a command can
+add its own. On a terminal, the up and down arrows recall earlier commands,
+kept across sessions (lines starting with a space are not kept), and Tab
+completes commands, flags, and the IDs of rich menus, rich menu aliases,
+audiences, and LIFF apps, fetched on first use.
+
+Session commands:
+  use [NAME]   Show or switch the account
+  history      List earlier commands
+  refresh      Re-read credentials and forget fetched IDs
+  exit, quit   End the session (or Ctrl+D)
+
+Ctrl+C stops the running command and returns to the prompt.`,
+		Example: `  line repl
+  line repl --account my-shop --output json
+
+  # In the session
+  line:my-shop> richmenu list
+  line:my-shop> richmenu delete --id <Tab>
+  line:my-shop> use staging`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s := newReplSession(cmd)
+			history, _ := loadReplHistory()
+			reader := repl.NewReader(repl.Options{
+				In:       cmd.InOrStdin(),
+				Out:      cmd.OutOrStdout(),
+				History:  history,
+				Complete: s.complete,
+			})
+			err := s.run(cmd.Context(), reader)
+			if reader.Interactive() {
+				if herr := saveReplHistory(reader.History()); herr != nil {
+					warnf(cmd.ErrOrStderr(), "%v", herr)
+				}
+			}
+			return err
+		},
+	}
+	return cmd
+}
+
+// replSession is the state of a 'line repl' session.
+type replSession struct {
+	// tree is the command tree, used for completion
+	tree *cobra.Command
+	// globalArgs are the global flags 'line repl' was given, except
+	// --account, which is account
+	globalArgs []string
+	account    string
+
+	in          io.Reader
+	out, errOut io.Writer
+
+	clients map[string]*api.Client
+	// ids are the resource IDs fetched for completion, by kind
+	ids map[string][]string
+}
+
+func newReplSession(cmd *cobra.Command) *replSession {
+	s := &replSession{
+		tree:    cmd.Root(),
+		account: flags.Account,
+		in:      cmd.InOrStdin(),
+		out:     cmd.OutOrStdout(),
+		errOut:  cmd.ErrOrStderr(),
+		clients: map[string]*api.Client{},
+		ids:     map[string][]string{},
+	}
+	if s.account == "" && flags.Replay == "" {
+		s.account, _ = requireAccount(&flags)
+	}
+	cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed || f.Name == "account" {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				s.globalArgs = append(s.globalArgs, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		s.globalArgs = append(s.globalArgs, "--"+f.Name+"="+f.Value.String())
+	})
+	return s
+}
+
+// args returns the global flags every command of the session gets.
+func (s *replSession) args() []string {
+	args := append([]string(nil), s.globalArgs...)
+	if s.account != "" {
+		args = append(args, "--account", s.account)
+	}
+	return args
+}
+
+func (s *replSession) prompt() string {
+	if s.account == "" {
+		return "line> "
+	}
+	return "line:" + s.account + "> "
+}
+
+// run reads and runs commands until exit or the end of input. A session
+// fed through a pipe fails if any of its commands did.
+func (s *replSession) run(ctx context.Context, reader *repl.Reader) error {
+	sessionClients = s.clients
+	defer func() { sessionClients = nil }()
+	// Ctrl+C is for the running command, not the session
+	ctx = context.WithoutCancel(ctx)
+
+	var failed, total int
+	var lastErr error
+	for {
+		line, err := reader.ReadLine(s.prompt())
+		if errors.Is(err, repl.ErrInterrupted) {
+			continue
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		args, err := repl.Split(line)
+		if err != nil {
+			_, _ = fmt.Fprintf(s.errOut, "Error: %v\n", err)
+			continue
+		}
+		if len(args) > 0 && args[0] == "line" {
+			args = args[1:]
+		}
+		if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+			continue
+		}
+
+		switch args[0] {
+		case "exit", "quit":
+			return sessionResult(reader, failed, total, lastErr)
+		case "use":
+			err = s.use(args[1:])
+		case "history":
+			for i, h := range reader.History() {
+				_, _ = fmt.Fprintf(s.out, "%4d  %s\n", i+1, h)
+			}
+		case "refresh":
+			clear(s.clients)
+			clear(s.ids)
+		case "repl":
+			err = withExitCode(ExitUsage, fmt.Errorf("already in a 'line repl' session"))
+		default:
+			total++
+			if err = s.runCommand(ctx, args); err != nil {
+				failed++
+				lastErr = err
+			}
+			continue
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(s.errOut, "Error: %v\n", err)
+		}
+	}
+	if reader.Interactive() {
+		_, _ = fmt.Fprintln(s.out)
+	}
+	return sessionResult(reader, failed, total, lastErr)
+}
+
+// sessionResult is what a session returns: nil on a terminal, where each
+// failure was reported as it happened, and otherwise an error with the
+// exit code of the last failure.
+func sessionResult(reader *repl.Reader, failed, total int, lastErr error) error {
+	if failed == 0 || reader.Interactive() {
+		return nil
+	}
+	return withExitCode(ExitCode(lastErr), fmt.Errorf("%d of %d commands failed", failed, total))
+}
+
+// runCommand runs one command line the way 'line' would, with the
+// session's global flags. Ctrl+C cancels it.
+func (s *replSession) runCommand(ctx context.Context, args []string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	root := NewRootCmd()
+	root.SetArgs(append(s.args(), args...))
+	root.SetIn(s.in)
+	root.SetOut(s.out)
+	root.SetErr(s.errOut)
+	c, err := root.ExecuteContextC(ctx)
+	closePager()
+	if ferr := closeOutputFormat(); err == nil {
+		err = ferr
+	}
+	if c != nil {
+		s.forgetIDs(c.CommandPath())
+	}
+	return finishCommand(s.errOut, c, err)
+}
+
+// use shows the session's account or switches to another.
+func (s *replSession) use(args []string) error {
+	if len(args) == 0 {
+		if s.account == "" {
+			_, _ = fmt.Fprintln(s.out, "No account selected")
+		} else {
+			_, _ = fmt.Fprintln(s.out, s.account)
+		}
+		return nil
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("usage: use [NAME]")
+	}
+	names, err := s.accountNames()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if name == args[0] {
+			s.account = name
+			clear(s.ids)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown account %q: run 'auth list' to see the accounts", args[0])
+}
+
+func (s *replSession) accountNames() ([]string, error) {
+	store, err := openSecretsStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyring: %w", err)
+	}
+	accounts, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		names = append(names, a.Name)
+	}
+	return names, nil
+}
+
+// complete returns the candidates for the last of words: a command, a
+// flag, a resource ID for a flag that takes one, or an account for 'use'.
+// Failures to fetch candidates complete nothing.
+func (s *replSession) complete(words []string) []string {
+	if len(words) > 1 && words[0] == "line" {
+		words = words[1:]
+	}
+	partial, before := words[len(words)-1], words[:len(words)-1]
+
+	if len(before) == 1 && before[0] == "use" {
+		names, _ := s.accountNames()
+		return names
+	}
+	c, _, err := s.tree.Find(before)
+	if err != nil {
+		return nil
+	}
+
+	if n := len(before); n > 0 && strings.HasPrefix(before[n-1], "--") && !strings.Contains(before[n-1], "=") {
+		name := strings.TrimPrefix(before[n-1], "--")
+		if f := c.Flags().Lookup(name); f != nil && f.Value.Type() != "bool" {
+			return s.completeID(c.CommandPath(), name)
+		}
+	}
+
+	var candidates []string
+	if strings.HasPrefix(partial, "-") {
+		add := func(f *pflag.Flag) {
+			if !f.Hidden {
+				candidates = append(candidates, "--"+f.Name)
+			}
+		}
+		c.LocalFlags().VisitAll(add)
+		c.InheritedFlags().VisitAll(add)
+		return candidates
+	}
+	for _, sub := range c.Commands() {
+		if includeMetaCommand(sub, false) && sub.Name() != "repl" {
+			candidates = append(candidates, sub.Name())
+		}
+	}
+	if c == s.tree {
+		candidates = append(candidates, replBuiltins...)
+	}
+	return candidates
+}
+
+// completeID returns the IDs that the flag of the command at path takes,
+// fetching them the first time they are needed.
+func (s *replSession) completeID(path, flag string) []string {
+	kind := ""
+	for _, ic := range replIDCompleters {
+		if ic.flag == flag && (path == ic.commands || strings.HasPrefix(path, ic.commands+" ")) {
+			kind = ic.kind
+			break
+		}
+	}
+	if kind == "" {
+		return nil
+	}
+	if ids, ok := s.ids[kind]; ok {
+		return ids
+	}
+	ids, err := s.fetchIDs(kind)
+	if err != nil {
+		return nil
+	}
+	s.ids[kind] = ids
+	return ids
+}
+
+func (s *replSession) fetchIDs(kind string) ([]string, error) {
+	// Parse the session's global flags as a command would, for the client
+	root := NewRootCmd()
+	if err := root.PersistentFlags().Parse(s.args()); err != nil {
+		return nil, err
+	}
+	client, err := newAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var ids []string
+	switch kind {
+	case "richmenu":
+		menus, err := client.GetRichMenuList(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range menus {
+			ids = append(ids, m.RichMenuID)
+		}
+	case "alias":
+		aliases, err := client.ListRichMenuAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range aliases {
+			ids = append(ids, a.RichMenuAliasID)
+		}
+	case "audience":
+		groups, err := client.GetAudienceGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			if g.AudienceGroupId != nil {
+				ids = append(ids, strconv.FormatInt(*g.AudienceGroupId, 10))
+			}
+		}
+	case "liff":
+		apps, err := client.GetAllLIFFApps(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range apps {
+			ids = append(ids, a.LIFFID)
+		}
+	}
+	return ids, nil
+}
+
+// forgetIDs drops the fetched IDs, and the names --name looks up, that a
+// command at path may have changed.
+func (s *replSession) forgetIDs(path string) {
+	for _, ic := range replIDCompleters {
+		if ic.kind != "" && (path == ic.commands || strings.HasPrefix(path, ic.commands+" ")) {
+			delete(s.ids, ic.kind)
+			forgetNames(ic.kind)
+		}
+	}
+}
+
+// replHistoryPath returns the file 'line repl' keeps its history in.
+func replHistoryPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "repl_history"), nil
+}
+
+func loadReplHistory() ([]string, error) {
+	path, err := replHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n"), nil
+}
+
+// saveReplHistory replaces the history file with lines. Commands can carry
+// user IDs, so the file is readable only by its owner.
+func saveReplHistory(lines []string) error {
+	path, err := replHistoryPath()
+	if err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	data := strings.Join(lines, "\n")
+	if data != "" {
+		data += "\n"
+	}
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	return nil
+}
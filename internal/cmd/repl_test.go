@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/mock"
+	"github.com/salmonumbrella/line-official-cli/internal/repl"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+)
+
+// setupReplAccounts stores the accounts sandbox and staging and points the
+// API at a mock server.
+func setupReplAccounts(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(mock.NewServer(mock.Options{Quota: 100}))
+	t.Cleanup(server.Close)
+
+	writeTestConfig(t, "credential_store: file\n")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("LINE_API_BASE_URL", server.URL)
+	t.Setenv("LINE_CREDENTIALS_PASSPHRASE", "")
+	cfg = &config.Config{CredentialStore: "file"}
+	store, err := openSecretsStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"sandbox", "staging"} {
+		if err := store.Set(name, secrets.Credentials{ChannelAccessToken: name}, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return server
+}
+
+func runRepl(t *testing.T, input string, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := NewRootCmd()
+	var out, errOut bytes.Buffer
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs(append([]string{"repl"}, args...))
+	err := cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestRepl_RunsCommands(t *testing.T) {
+	setupReplAccounts(t)
+
+	input := `bot info
+line bot info --output json
+# a comment
+
+use
+use staging
+use nope
+repl
+exit
+bot info
+`
+	out, errOut, err := runRepl(t, input, "--account", "sandbox")
+	if err != nil {
+		t.Fatalf("repl: %v\n%s", err, errOut)
+	}
+	if strings.Count(out, "Mock Bot") != 2 || !strings.Contains(out, `"displayName": "Mock Bot"`) {
+		t.Errorf("expected bot info as text and JSON, got:\n%s", out)
+	}
+	if !strings.Contains(out, "sandbox\n") {
+		t.Errorf("expected 'use' to show the account, got:\n%s", out)
+	}
+	if !strings.Contains(errOut, `unknown account "nope"`) || !strings.Contains(errOut, "already in a 'line repl' session") {
+		t.Errorf("unexpected errors:\n%s", errOut)
+	}
+	if sessionClients != nil {
+		t.Error("the session's clients should be dropped when it ends")
+	}
+}
+
+func TestRepl_ReusesClients(t *testing.T) {
+	setupReplAccounts(t)
+	cmd, _, err := NewRootCmd().Find([]string{"repl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ParseFlags([]string{"--account", "sandbox", "--no-color"}); err != nil {
+		t.Fatal(err)
+	}
+	s := newReplSession(cmd)
+	if want := []string{"--no-color=true"}; !reflect.DeepEqual(s.globalArgs, want) {
+		t.Errorf("globalArgs = %q, want %q", s.globalArgs, want)
+	}
+	var out bytes.Buffer
+	s.out, s.errOut = &out, &out
+
+	input := "bot info\nbot info --output json\nuse staging\nbot info\nbot info\n"
+	reader := repl.NewReader(repl.Options{In: strings.NewReader(input), Out: &out})
+	if err := s.run(t.Context(), reader); err != nil {
+		t.Fatalf("repl: %v\n%s", err, out.String())
+	}
+	if len(s.clients) != 2 {
+		t.Errorf("expected a client per account, got %d", len(s.clients))
+	}
+}
+
+func TestRepl_PipedFailures(t *testing.T) {
+	setupReplAccounts(t)
+	_, _, err := runRepl(t, "bot info\naudience get --id 999\n", "--account", "sandbox")
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 commands failed") {
+		t.Fatalf("expected the failure to be reported, got %v", err)
+	}
+	if ExitCode(err) == ExitOK {
+		t.Error("expected a failing exit code")
+	}
+}
+
+func TestRepl_Complete(t *testing.T) {
+	server := setupReplAccounts(t)
+	client := api.NewClient("sandbox", false, false)
+	client.SetBaseURL(server.URL)
+	menuID, err := client.CreateRichMenu(t.Context(), api.CreateRichMenuRequest{
+		Size: api.RichMenuSize{Width: 2500, Height: 843}, Name: "Main", ChatBarText: "Menu",
+		Areas: []api.RichMenuArea{{
+			Bounds: api.RichMenuBounds{Width: 2500, Height: 843},
+			Action: []byte(`{"type":"message","text":"hi"}`),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := NewRootCmd()
+	s := &replSession{tree: root, account: "sandbox", ids: map[string][]string{}}
+	sessionClients = map[string]*api.Client{}
+	t.Cleanup(func() { sessionClients = nil })
+
+	has := func(words []string, want string) bool {
+		for _, c := range s.complete(words) {
+			if c == want {
+				return true
+			}
+		}
+		return false
+	}
+	if !has([]string{""}, "richmenu") || !has([]string{""}, "use") || has([]string{""}, "repl") {
+		t.Errorf("unexpected top-level candidates: %q", s.complete([]string{""}))
+	}
+	if !has([]string{"richmenu", "d"}, "delete") {
+		t.Errorf("expected richmenu subcommands: %q", s.complete([]string{"richmenu", "d"}))
+	}
+	if !has([]string{"richmenu", "delete", "--"}, "--id") || !has([]string{"richmenu", "delete", "--"}, "--account") {
+		t.Errorf("expected flags: %q", s.complete([]string{"richmenu", "delete", "--"}))
+	}
+	if !has([]string{"line", "richmenu", "delete", "--id", ""}, menuID) {
+		t.Errorf("expected rich menu %s, got %q", menuID, s.complete([]string{"richmenu", "delete", "--id", ""}))
+	}
+	if got := s.complete([]string{"message", "push", "--to", ""}); got != nil {
+		t.Errorf("--to takes no resource ID, got %q", got)
+	}
+	if !has([]string{"use", ""}, "staging") {
+		t.Errorf("expected accounts: %q", s.complete([]string{"use", ""}))
+	}
+
+	s.forgetIDs("line richmenu delete")
+	if _, ok := s.ids["richmenu"]; ok {
+		t.Error("rich menu IDs should be forgotten after a rich menu command")
+	}
+}
+
+func TestReplHistory_SaveAndLoad(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if _, err := loadReplHistory(); err == nil {
+		t.Error("expected an error without a history file")
+	}
+	lines := []string{"bot info", "richmenu list"}
+	if err := saveReplHistory(lines); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadReplHistory()
+	if err != nil || !reflect.DeepEqual(got, lines) {
+		t.Errorf("history = %q, %v; want %q", got, err, lines)
+	}
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// nameLookupTTL is how long the names of a kind of resource, once listed,
+// are reused by later lookups with the same client.
+const nameLookupTTL = time.Minute
+
+// namedResource is a resource that --name can address.
+type namedResource struct {
+	ID   string
+	Name string
+}
+
+// resourceKind is a kind of resource that --name can address. kind matches
+// the kinds of replIDCompleters, so a session forgets both together.
+type resourceKind struct {
+	kind string
+	noun string
+	list func(ctx context.Context, c *api.Client) ([]namedResource, error)
+}
+
+var richMenuKind = resourceKind{
+	kind: "richmenu",
+	noun: "rich menu",
+	list: func(ctx context.Context, c *api.Client) ([]namedResource, error) {
+		menus, err := c.GetRichMenuList(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rich menus: %w", err)
+		}
+		resources := make([]namedResource, 0, len(menus))
+		for _, m := range menus {
+			resources = append(resources, namedResource{ID: m.RichMenuID, Name: m.Name})
+		}
+		return resources, nil
+	},
+}
+
+// audienceKind names audience groups by their description, the only name
+// they have.
+var audienceKind = resourceKind{
+	kind: "audience",
+	noun: "audience group",
+	list: func(ctx context.Context, c *api.Client) ([]namedResource, error) {
+		var resources []namedResource
+		for page := int64(1); ; page++ {
+			resp, err := c.GetAudienceGroupsPage(ctx, page, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list audience groups: %w", err)
+			}
+			if resp.AudienceGroups != nil {
+				for _, g := range *resp.AudienceGroups {
+					if g.AudienceGroupId == nil || g.Description == nil {
+						continue
+					}
+					resources = append(resources, namedResource{ID: strconv.FormatInt(*g.AudienceGroupId, 10), Name: *g.Description})
+				}
+			}
+			if resp.HasNextPage == nil || !*resp.HasNextPage {
+				return resources, nil
+			}
+		}
+	},
+}
+
+type nameCacheKey struct {
+	client *api.Client
+	kind   string
+}
+
+type nameCacheEntry struct {
+	resources []namedResource
+	listed    time.Time
+}
+
+var (
+	nameCacheMu sync.Mutex
+	nameCache   = map[nameCacheKey]nameCacheEntry{}
+)
+
+// forgetNames drops the listed names of a kind, for every client.
+func forgetNames(kind string) {
+	nameCacheMu.Lock()
+	defer nameCacheMu.Unlock()
+	for key := range nameCache {
+		if key.kind == kind {
+			delete(nameCache, key)
+		}
+	}
+}
+
+// namedResources lists the resources of a kind, reusing a listing by the
+// same client younger than nameLookupTTL.
+func namedResources(ctx context.Context, c *api.Client, k resourceKind) ([]namedResource, error) {
+	key := nameCacheKey{client: c, kind: k.kind}
+	nameCacheMu.Lock()
+	entry, ok := nameCache[key]
+	nameCacheMu.Unlock()
+	if ok && clockNow().Sub(entry.listed) < nameLookupTTL {
+		return entry.resources, nil
+	}
+
+	resources, err := k.list(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	nameCacheMu.Lock()
+	nameCache[key] = nameCacheEntry{resources: resources, listed: clockNow()}
+	nameCacheMu.Unlock()
+	return resources, nil
+}
+
+// resolveName returns the ID of the resource of a kind named name. An exact
+// match wins over one that differs only in case. A name shared by several
+// resources is a usage error that lists them; an unknown name suggests
+// similar ones.
+func resolveName(ctx context.Context, c *api.Client, k resourceKind, name string) (string, error) {
+	resources, err := namedResources(ctx, c, k)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []namedResource
+	for _, r := range resources {
+		if r.Name == name {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) == 0 {
+		for _, r := range resources {
+			if strings.EqualFold(r.Name, name) {
+				matches = append(matches, r)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0].ID, nil
+	case 0:
+		msg := fmt.Sprintf("no %s is named %q", k.noun, name)
+		if similar := similarNames(resources, name, 5); len(similar) > 0 {
+			msg += "; similar names: " + strings.Join(similar, ", ")
+		}
+		return "", withExitCode(ExitNotFound, fmt.Errorf("%s", msg))
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "%d %ss are named %q; use --id with one of:", len(matches), k.noun, name)
+	for _, m := range matches {
+		_, _ = fmt.Fprintf(&b, "\n  %s  %s", m.ID, m.Name)
+	}
+	return "", withExitCode(ExitUsage, fmt.Errorf("%s", b.String()))
+}
+
+// similarNames returns up to max quoted names of resources that contain
+// name, or are contained in it, ignoring case.
+func similarNames(resources []namedResource, name string, max int) []string {
+	lower := strings.ToLower(name)
+	seen := map[string]bool{}
+	var similar []string
+	for _, r := range resources {
+		candidate := strings.ToLower(r.Name)
+		if r.Name == "" || seen[r.Name] || !(strings.Contains(candidate, lower) || strings.Contains(lower, candidate)) {
+			continue
+		}
+		seen[r.Name] = true
+		similar = append(similar, strconv.Quote(r.Name))
+	}
+	sort.Strings(similar)
+	if len(similar) > max {
+		similar = similar[:max]
+	}
+	return similar
+}
+
+// addNameFlag registers --name as the alternative to a command's --id flag:
+// exactly one of the two is required.
+func addNameFlag(cmd *cobra.Command, name *string, noun string) {
+	cmd.Flags().StringVar(name, "name", "", fmt.Sprintf("Name of the %s, instead of --id", noun))
+	cmd.MarkFlagsOneRequired("id", "name")
+	cmd.MarkFlagsMutuallyExclusive("id", "name")
+}
+
+// resolveRichMenuID returns id, or the ID of the rich menu named name.
+func resolveRichMenuID(ctx context.Context, c *api.Client, id, name string) (string, error) {
+	if name == "" {
+		return id, nil
+	}
+	return resolveName(ctx, c, richMenuKind, name)
+}
+
+// resolveAudienceID returns id, or the ID of the audience group whose
+// description is name.
+func resolveAudienceID(ctx context.Context, c *api.Client, id int64, name string) (int64, error) {
+	if name == "" {
+		return id, nil
+	}
+	resolved, err := resolveName(ctx, c, audienceKind, name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(resolved, 10, 64)
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// richMenuNameServer serves a list of rich menus with the given IDs and
+// names, counting the listings.
+func richMenuNameServer(t *testing.T, menus map[string]string, lists *int) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/bot/richmenu/list":
+			*lists++
+			var list []map[string]any
+			for id, name := range menus {
+				list = append(list, map[string]any{"richMenuId": id, "name": name})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"richmenus": list})
+		case strings.HasPrefix(r.URL.Path, "/v2/bot/richmenu/"):
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { forgetNames("richmenu") })
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestResolveName(t *testing.T) {
+	var lists int
+	client := richMenuNameServer(t, map[string]string{
+		"rm-1": "Main Menu",
+		"rm-2": "Promo",
+		"rm-3": "promo",
+		"rm-4": "Sale",
+		"rm-5": "Sale",
+	}, &lists)
+
+	tests := []struct {
+		name    string
+		want    string
+		code    int
+		errPart string
+	}{
+		{"Main Menu", "rm-1", ExitOK, ""},
+		{"main menu", "rm-1", ExitOK, ""},
+		{"Promo", "rm-2", ExitOK, ""},
+		{"PROMO", "", ExitUsage, "rm-2  Promo\n  rm-3  promo"},
+		{"Sale", "", ExitUsage, `2 rich menus are named "Sale"; use --id`},
+		{"Main", "", ExitNotFound, `similar names: "Main Menu"`},
+		{"Spring", "", ExitNotFound, `no rich menu is named "Spring"`},
+	}
+	for _, tt := range tests {
+		got, err := resolveName(t.Context(), client, richMenuKind, tt.name)
+		if got != tt.want || ExitCode(err) != tt.code {
+			t.Errorf("resolveName(%q) = %q, %v; want %q with exit code %d", tt.name, got, err, tt.want, tt.code)
+		}
+		if tt.errPart != "" && (err == nil || !strings.Contains(err.Error(), tt.errPart)) {
+			t.Errorf("resolveName(%q): expected %q in %v", tt.name, tt.errPart, err)
+		}
+	}
+	if lists != 1 {
+		t.Errorf("expected the menus to be listed once, got %d", lists)
+	}
+}
+
+func TestResolveName_CacheExpires(t *testing.T) {
+	oldNow := clockNow
+	defer func() { clockNow = oldNow }()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	clockNow = func() time.Time { return now }
+
+	var lists int
+	client := richMenuNameServer(t, map[string]string{"rm-1": "Main Menu"}, &lists)
+	for _, step := range []time.Duration{0, 30 * time.Second, time.Minute} {
+		now = now.Add(step)
+		if _, err := resolveName(t.Context(), client, richMenuKind, "Main Menu"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lists != 2 {
+		t.Errorf("expected a second listing after the TTL, got %d listings", lists)
+	}
+	forgetNames("richmenu")
+	if _, err := resolveName(t.Context(), client, richMenuKind, "Main Menu"); err != nil {
+		t.Fatal(err)
+	}
+	if lists != 3 {
+		t.Errorf("expected a listing after forgetNames, got %d listings", lists)
+	}
+}
+
+func TestResolveAudienceID_Pages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/audienceGroup/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resp := map[string]any{
+			"audienceGroups": []map[string]any{{"audienceGroupId": 1, "description": "Spring"}},
+			"hasNextPage":    true,
+		}
+		if r.URL.Query().Get("page") == "2" {
+			resp = map[string]any{
+				"audienceGroups": []map[string]any{{"audienceGroupId": 2, "description": "VIP 2024"}},
+				"hasNextPage":    false,
+			}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	defer forgetNames("audience")
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	id, err := resolveAudienceID(t.Context(), client, 0, "VIP 2024")
+	if err != nil || id != 2 {
+		t.Errorf("resolveAudienceID = %d, %v; want 2", id, err)
+	}
+	if id, err := resolveAudienceID(t.Context(), client, 7, ""); err != nil || id != 7 {
+		t.Errorf("without --name the ID should be kept, got %d, %v", id, err)
+	}
+}
+
+func TestRichMenuDeleteCmd_ByName(t *testing.T) {
+	var lists int
+	client := richMenuNameServer(t, map[string]string{"rm-1": "Main Menu", "rm-2": "Promo"}, &lists)
+	oldOutput, oldYes := flags.Output, flags.Yes
+	defer func() { flags.Output, flags.Yes = oldOutput, oldYes }()
+	flags.Output, flags.Yes = "text", true
+
+	cmd := newRichMenuDeleteCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--name", "Main Menu"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Deleted rich menu: rm-1") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	cmd = newRichMenuDeleteCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--name", "Main Menu", "--id", "rm-1"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected --id and --name to be mutually exclusive")
+	}
+}
@@ -1,14 +1,22 @@
 package cmd
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG decoder for image.DecodeConfig
+	_ "image/png"  // register PNG decoder for image.DecodeConfig
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/imaging"
+	"github.com/salmonumbrella/line-official-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -26,14 +34,20 @@ func newRichMenuCmd() *cobra.Command {
 	cmd.AddCommand(newRichMenuSetDefaultCmd())
 	cmd.AddCommand(newRichMenuCancelDefaultCmd())
 	cmd.AddCommand(newRichMenuUploadImageCmd())
+	cmd.AddCommand(newRichMenuGenerateImageCmd())
 	cmd.AddCommand(newRichMenuGetCmd())
+	cmd.AddCommand(newRichMenuTestTapCmd())
 	cmd.AddCommand(newRichMenuLinkCmd())
 	cmd.AddCommand(newRichMenuUnlinkCmd())
+	cmd.AddCommand(newRichMenuLinkedCmd())
+	cmd.AddCommand(newRichMenuLinksCmd())
 	cmd.AddCommand(newRichMenuAliasCmd())
 	cmd.AddCommand(newRichMenuBulkCmd())
 	cmd.AddCommand(newRichMenuBatchCmd())
 	cmd.AddCommand(newRichMenuValidateCmd())
 	cmd.AddCommand(newRichMenuDownloadImageCmd())
+	cmd.AddCommand(newRichMenuDeployCmd())
+	cmd.AddCommand(newRichMenuApplyCmd())
 
 	return cmd
 }
@@ -71,65 +85,90 @@ func newRichMenuCreateCmdWithClient(client *api.Client) *cobra.Command {
 	var chatBarText string
 	var actionsJSON string
 	var size string
+	var menuFile string
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new rich menu",
-		Long:  "Create a rich menu with the specified actions and chat bar text.",
+		Long: `Create a rich menu with the specified actions and chat bar text.
+
+--actions builds a simple single-row layout with one area per action. For
+custom layouts (multi-row grids, explicit bounds, selected flag), pass the
+complete rich menu definition with --file instead.`,
 		Example: `  # Create a full-size rich menu
   line richmenu create --name "Main Menu" --actions '[{"type":"message","label":"Help","text":"help"}]'
 
   # Create a compact rich menu
-  line richmenu create --name "Menu" --size compact --actions '[...]'`,
+  line richmenu create --name "Menu" --size compact --actions '[...]'
+
+  # Create from a full JSON definition
+  line richmenu create --file menu.json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if chatBarText == "" {
-				return fmt.Errorf("--name is required")
-			}
-			if actionsJSON == "" {
-				return fmt.Errorf("--actions is required")
-			}
-			if size != "full" && size != "compact" {
-				return fmt.Errorf("--size must be 'full' or 'compact'")
-			}
+			var req api.CreateRichMenuRequest
 
-			// Parse actions JSON
-			var actions []json.RawMessage
-			if err := json.Unmarshal([]byte(actionsJSON), &actions); err != nil {
-				return fmt.Errorf("invalid actions JSON: %w", err)
-			}
+			if menuFile != "" {
+				if actionsJSON != "" {
+					return fmt.Errorf("--file and --actions cannot be used together")
+				}
+				menu, err := readRichMenuDefinitionFromFile(menuFile)
+				if err != nil {
+					return fmt.Errorf("failed to read menu file: %w", err)
+				}
+				if chatBarText != "" {
+					menu.Name = chatBarText
+					menu.ChatBarText = chatBarText
+				}
+				req = *menu
+			} else {
+				if chatBarText == "" {
+					return fmt.Errorf("--name is required")
+				}
+				if actionsJSON == "" {
+					return fmt.Errorf("--actions is required")
+				}
+				if size != "full" && size != "compact" {
+					return fmt.Errorf("--size must be 'full' or 'compact'")
+				}
 
-			// Determine dimensions based on size
-			height := 1686
-			if size == "compact" {
-				height = 843
-			}
+				// Parse actions JSON
+				var actions []json.RawMessage
+				if err := json.Unmarshal([]byte(actionsJSON), &actions); err != nil {
+					return fmt.Errorf("invalid actions JSON: %w", err)
+				}
 
-			// Build areas from actions (simplified: single area covering the whole menu)
-			areas := make([]api.RichMenuArea, len(actions))
-			areaWidth := 2500 / len(actions)
-			for i, action := range actions {
-				areas[i] = api.RichMenuArea{
-					Bounds: api.RichMenuBounds{
-						X:      i * areaWidth,
-						Y:      0,
-						Width:  areaWidth,
+				// Determine dimensions based on size
+				height := 1686
+				if size == "compact" {
+					height = 843
+				}
+
+				// Build areas from actions (simplified: single area covering the whole menu)
+				areas := make([]api.RichMenuArea, len(actions))
+				areaWidth := 2500 / len(actions)
+				for i, action := range actions {
+					areas[i] = api.RichMenuArea{
+						Bounds: api.RichMenuBounds{
+							X:      i * areaWidth,
+							Y:      0,
+							Width:  areaWidth,
+							Height: height,
+						},
+						Action: action,
+					}
+				}
+
+				req = api.CreateRichMenuRequest{
+					Size: api.RichMenuSize{
+						Width:  2500,
 						Height: height,
 					},
-					Action: action,
+					Selected:    false,
+					Name:        chatBarText,
+					ChatBarText: chatBarText,
+					Areas:       areas,
 				}
 			}
 
-			req := api.CreateRichMenuRequest{
-				Size: api.RichMenuSize{
-					Width:  2500,
-					Height: height,
-				},
-				Selected:    false,
-				Name:        chatBarText,
-				ChatBarText: chatBarText,
-				Areas:       areas,
-			}
-
 			c := client
 			if c == nil {
 				var err error
@@ -147,38 +186,65 @@ func newRichMenuCreateCmdWithClient(client *api.Client) *cobra.Command {
 			if flags.Output == "json" {
 				result := map[string]any{
 					"richMenuId": richMenuID,
-					"name":       chatBarText,
+					"name":       req.Name,
 				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
 				return enc.Encode(result)
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created rich menu: %s (ID: %s)\n", chatBarText, richMenuID)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created rich menu: %s (ID: %s)\n", req.Name, richMenuID)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&chatBarText, "name", "", "Chat bar text / menu name (required)")
-	cmd.Flags().StringVar(&actionsJSON, "actions", "", "Actions JSON array (required)")
+	cmd.Flags().StringVar(&chatBarText, "name", "", "Chat bar text / menu name (required unless --file is used)")
+	cmd.Flags().StringVar(&actionsJSON, "actions", "", "Actions JSON array (required unless --file is used)")
 	cmd.Flags().StringVar(&size, "size", "full", "Menu size: full (2500x1686) or compact (2500x843)")
+	cmd.Flags().StringVar(&menuFile, "file", "", "JSON file containing a complete rich menu definition, or - for stdin")
 
 	return cmd
 }
 
+// readRichMenuDefinitionFromFile reads a complete rich menu definition from a JSON file
+func readRichMenuDefinitionFromFile(path string) (*api.CreateRichMenuRequest, error) {
+	data, err := readFileOrStdin(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var menu api.CreateRichMenuRequest
+	if err := json.Unmarshal(data, &menu); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if len(menu.Areas) == 0 {
+		return nil, fmt.Errorf("menu definition must contain at least one area")
+	}
+	if menu.Size.Width == 0 || menu.Size.Height == 0 {
+		return nil, fmt.Errorf("menu definition must specify size.width and size.height")
+	}
+	if menu.ChatBarText == "" {
+		menu.ChatBarText = menu.Name
+	}
+	return &menu, nil
+}
+
 func newRichMenuDeleteCmd() *cobra.Command {
 	return newRichMenuDeleteCmdWithClient(nil)
 }
 
 func newRichMenuDeleteCmdWithClient(client *api.Client) *cobra.Command {
 	var richMenuID string
+	var name string
 
 	cmd := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete a rich menu",
-		Long:  "Delete a rich menu by its ID.",
+		Long:  "Delete a rich menu by its ID, or by its name if no other menu has it.",
+		Example: `  line richmenu delete --id richmenu-xxx
+  line richmenu delete --name "Main Menu"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if richMenuID == "" {
-				return fmt.Errorf("--id is required")
+			if richMenuID == "" && name == "" {
+				return fmt.Errorf("--id or --name is required")
 			}
 
 			c := client
@@ -190,6 +256,14 @@ func newRichMenuDeleteCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
+			richMenuID, err := resolveRichMenuID(cmd.Context(), c, richMenuID, name)
+			if err != nil {
+				return err
+			}
+			if err := confirmDestructive(cmd, "delete", fmt.Sprintf("Delete rich menu %s?", richMenuID)); err != nil {
+				return err
+			}
+
 			if err := c.DeleteRichMenu(cmd.Context(), richMenuID); err != nil {
 				return fmt.Errorf("failed to delete rich menu: %w", err)
 			}
@@ -205,8 +279,8 @@ func newRichMenuDeleteCmdWithClient(client *api.Client) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID to delete (required)")
-	_ = cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID to delete")
+	addNameFlag(cmd, &name, "rich menu")
 
 	return cmd
 }
@@ -217,14 +291,15 @@ func newRichMenuSetDefaultCmd() *cobra.Command {
 
 func newRichMenuSetDefaultCmdWithClient(client *api.Client) *cobra.Command {
 	var richMenuID string
+	var name string
 
 	cmd := &cobra.Command{
 		Use:   "set-default",
 		Short: "Set the default rich menu",
-		Long:  "Set a rich menu as the default for all users.",
+		Long:  "Set a rich menu, given by its ID or name, as the default for all users.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if richMenuID == "" {
-				return fmt.Errorf("--id is required")
+			if richMenuID == "" && name == "" {
+				return fmt.Errorf("--id or --name is required")
 			}
 
 			c := client
@@ -236,6 +311,11 @@ func newRichMenuSetDefaultCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
+			richMenuID, err := resolveRichMenuID(cmd.Context(), c, richMenuID, name)
+			if err != nil {
+				return err
+			}
+
 			if err := c.SetDefaultRichMenu(cmd.Context(), richMenuID); err != nil {
 				return fmt.Errorf("failed to set default rich menu: %w", err)
 			}
@@ -251,8 +331,8 @@ func newRichMenuSetDefaultCmdWithClient(client *api.Client) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID to set as default (required)")
-	_ = cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID to set as default")
+	addNameFlag(cmd, &name, "rich menu")
 
 	return cmd
 }
@@ -267,6 +347,10 @@ func newRichMenuCancelDefaultCmdWithClient(client *api.Client) *cobra.Command {
 		Short: "Cancel the default rich menu",
 		Long:  "Remove the default rich menu setting.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := confirmDestructive(cmd, "cancel-default", "Remove the default rich menu for all users?"); err != nil {
+				return err
+			}
+
 			c := client
 			if c == nil {
 				var err error
@@ -295,14 +379,28 @@ func newRichMenuCancelDefaultCmdWithClient(client *api.Client) *cobra.Command {
 }
 
 func listRichMenusWithClient(cmd *cobra.Command, client *api.Client) error {
-	menus, err := client.GetRichMenuList(cmd.Context())
+	var menus []api.RichMenu
+	var defaultID string
+
+	err := runConcurrently(cmd.Context(),
+		func(ctx context.Context) error {
+			var err error
+			menus, err = client.GetRichMenuList(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list rich menus: %w", err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			// Get default rich menu to mark it (absence of a default is not an error)
+			defaultID, _ = client.GetDefaultRichMenuID(ctx)
+			return nil
+		},
+	)
 	if err != nil {
-		return fmt.Errorf("failed to list rich menus: %w", err)
+		return err
 	}
 
-	// Get default rich menu to mark it
-	defaultID, _ := client.GetDefaultRichMenuID(cmd.Context())
-
 	if flags.Output == "json" {
 		result := map[string]any{
 			"richmenus":       menus,
@@ -328,18 +426,20 @@ func listRichMenusWithClient(cmd *cobra.Command, client *api.Client) error {
 			}
 			table.AddRow(menu.RichMenuID, menu.ChatBarText, size, isDefault)
 		}
+		table.ColorColumn("DEFAULT", output.Palette.Green)
 		table.Render(cmd.OutOrStdout())
 		return nil
 	}
 
 	// Default text output
 	_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Rich Menus:")
+	p := palette(cmd.OutOrStdout())
 	for _, menu := range menus {
 		prefix := "  "
 		suffix := ""
 		if menu.RichMenuID == defaultID {
-			prefix = "* "
-			suffix = " (default)"
+			prefix = p.Green("*") + " "
+			suffix = " " + p.Green("(default)")
 		}
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s%s  %s%s\n", prefix, menu.RichMenuID, menu.ChatBarText, suffix)
 	}
@@ -353,16 +453,31 @@ func newRichMenuUploadImageCmd() *cobra.Command {
 func newRichMenuUploadImageCmdWithClient(client *api.Client, imageDataOverride []byte) *cobra.Command {
 	var richMenuID string
 	var imagePath string
+	var autoFit bool
 
 	cmd := &cobra.Command{
 		Use:   "upload-image",
 		Short: "Upload an image for a rich menu",
 		Long: `Upload an image file for a rich menu. The image must be:
 - PNG or JPEG format
-- 2500x1686 pixels (full) or 2500x843 pixels (compact)
-- Maximum 1MB file size`,
+- Exactly the size declared by the rich menu (e.g. 2500x1686 full or 2500x843 compact)
+- 800-2500 pixels wide, at least 250 pixels tall, aspect ratio (width/height) of at least 1.45
+- Maximum 1MB file size
+
+The image header is checked locally before uploading, so mismatched
+images fail fast with a clear message. Under --dry-run the menu is not
+fetched, so only the local checks run and --auto-fit does not resize.
+
+With --auto-fit, an image of another size is resized to the menu's size
+(cropping the edges if the aspect ratio differs), and an image over 1MB is
+re-encoded until it fits: PNG images are recompressed and then reduced to
+256 colors, and JPEG images (or PNG images still too large) are encoded as
+JPEG at decreasing quality. Each transformation applied is printed.`,
 		Example: `  # Upload an image to a rich menu
-  line richmenu upload-image --id richmenu-xxx --image menu.png`,
+  line richmenu upload-image --id richmenu-xxx --image menu.png
+
+  # Resize and compress a large design export to fit the menu
+  line richmenu upload-image --id richmenu-xxx --image design.png --auto-fit`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if richMenuID == "" {
 				return fmt.Errorf("--id is required")
@@ -380,25 +495,17 @@ func newRichMenuUploadImageCmdWithClient(client *api.Client, imageDataOverride [
 					return fmt.Errorf("--image is required")
 				}
 
-				// Read image file
 				var err error
-				data, err = os.ReadFile(imagePath)
-				if err != nil {
-					return fmt.Errorf("failed to read image: %w", err)
-				}
-
-				// Check file size (max 1MB)
-				if len(data) > 1024*1024 {
-					return fmt.Errorf("image file too large: max 1MB, got %d bytes", len(data))
-				}
-
-				// Determine content type
-				contentType = "image/png"
-				ext := strings.ToLower(filepath.Ext(imagePath))
-				if ext == ".jpg" || ext == ".jpeg" {
-					contentType = "image/jpeg"
-				} else if ext != ".png" {
-					return fmt.Errorf("unsupported image format: use PNG or JPEG")
+				if autoFit {
+					data, err = os.ReadFile(imagePath)
+					if err != nil {
+						return fmt.Errorf("failed to read image: %w", err)
+					}
+				} else {
+					data, contentType, err = readRichMenuImage(imagePath)
+					if err != nil {
+						return err
+					}
 				}
 			}
 
@@ -411,16 +518,71 @@ func newRichMenuUploadImageCmdWithClient(client *api.Client, imageDataOverride [
 				}
 			}
 
+			// The declared size is unknown under --dry-run or for a menu
+			// without one; the image header is still checked locally
+			var declared api.RichMenuSize
+			var known bool
+			if autoFit || imageDataOverride == nil {
+				var err error
+				if declared, known, err = declaredRichMenuSize(cmd.Context(), c, richMenuID); err != nil {
+					return err
+				}
+			}
+			var fitSteps []string
+			switch {
+			case autoFit && known:
+				fitted, err := imaging.Fit(data, imaging.Options{
+					Width:    declared.Width,
+					Height:   declared.Height,
+					MaxBytes: maxRichMenuImageBytes,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to fit image: %w", err)
+				}
+				data, contentType, fitSteps = fitted.Data, fitted.ContentType, fitted.Steps
+				if err := checkRichMenuImageSize(data, contentType, declared); err != nil {
+					return err
+				}
+			case autoFit:
+				// Nothing to fit to, so only check the header
+				_, format, err := image.DecodeConfig(bytes.NewReader(data))
+				if err != nil {
+					return fmt.Errorf("invalid image: could not decode PNG or JPEG header: %w", err)
+				}
+				contentType = "image/" + format
+				if _, err := richMenuImageSize(data, contentType); err != nil {
+					return err
+				}
+			case known:
+				// Verify the image matches the menu's declared size before uploading
+				if err := checkRichMenuImageSize(data, contentType, declared); err != nil {
+					return err
+				}
+			}
+
 			if err := c.UploadRichMenuImage(cmd.Context(), richMenuID, contentType, data); err != nil {
 				return fmt.Errorf("failed to upload image: %w", err)
 			}
 
 			if flags.Output == "json" {
 				result := map[string]any{"richMenuId": richMenuID, "status": "uploaded"}
+				if autoFit {
+					result["contentType"] = contentType
+					result["bytes"] = len(data)
+					result["autoFit"] = append([]string{}, fitSteps...)
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
 				return enc.Encode(result)
 			}
+			if autoFit {
+				if len(fitSteps) == 0 {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Auto-fit: image already fits, uploaded unchanged")
+				}
+				for _, step := range fitSteps {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Auto-fit: %s\n", step)
+				}
+			}
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Image uploaded to rich menu: %s\n", richMenuID)
 			return nil
 		},
@@ -428,26 +590,117 @@ func newRichMenuUploadImageCmdWithClient(client *api.Client, imageDataOverride [
 
 	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID (required)")
 	cmd.Flags().StringVar(&imagePath, "image", "", "Path to image file (required)")
+	cmd.Flags().BoolVar(&autoFit, "auto-fit", false, "Resize the image to the menu's size and compress it under 1MB")
 	_ = cmd.MarkFlagRequired("id")
 	// Note: --image is not marked required since imageDataOverride can be used in tests
 
 	return cmd
 }
 
+// maxRichMenuImageBytes is the largest rich menu image LINE accepts.
+const maxRichMenuImageBytes = 1024 * 1024
+
+// readRichMenuImage reads a rich menu image file and determines its content type.
+// The file must be PNG or JPEG and at most 1MB.
+func readRichMenuImage(path string) ([]byte, string, error) {
+	contentType := "image/png"
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".jpg" || ext == ".jpeg" {
+		contentType = "image/jpeg"
+	} else if ext != ".png" {
+		return nil, "", fmt.Errorf("unsupported image format: use PNG or JPEG")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// Check file size (max 1MB)
+	if len(data) > maxRichMenuImageBytes {
+		return nil, "", fmt.Errorf("image file too large: max 1MB, got %d bytes", len(data))
+	}
+
+	if _, err := richMenuImageSize(data, contentType); err != nil {
+		return nil, "", err
+	}
+
+	return data, contentType, nil
+}
+
+// richMenuImageSize decodes the image header and checks the dimensions
+// against LINE's rich menu constraints: width 800-2500px, height at least
+// 250px, and an aspect ratio (width/height) of at least 1.45.
+func richMenuImageSize(data []byte, contentType string) (api.RichMenuSize, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return api.RichMenuSize{}, fmt.Errorf("invalid image: could not decode PNG or JPEG header: %w", err)
+	}
+	if "image/"+format != contentType {
+		return api.RichMenuSize{}, fmt.Errorf("image content is %s but file extension indicates %s", format, strings.TrimPrefix(contentType, "image/"))
+	}
+
+	size := api.RichMenuSize{Width: config.Width, Height: config.Height}
+	if size.Width < 800 || size.Width > 2500 {
+		return size, fmt.Errorf("image width must be between 800 and 2500 pixels, got %dx%d", size.Width, size.Height)
+	}
+	if size.Height < 250 {
+		return size, fmt.Errorf("image height must be at least 250 pixels, got %dx%d", size.Width, size.Height)
+	}
+	if float64(size.Width)/float64(size.Height) < 1.45 {
+		return size, fmt.Errorf("image aspect ratio (width/height) must be at least 1.45, got %dx%d", size.Width, size.Height)
+	}
+	return size, nil
+}
+
+// declaredRichMenuSize returns the size declared by a rich menu, to check
+// an image against before uploading. It reports false under --dry-run,
+// where the menu is not fetched, or when the menu declares no size.
+func declaredRichMenuSize(ctx context.Context, c *api.Client, richMenuID string) (api.RichMenuSize, bool, error) {
+	if flags.DryRun {
+		return api.RichMenuSize{}, false, nil
+	}
+	menu, err := c.GetRichMenu(ctx, richMenuID)
+	if err != nil {
+		return api.RichMenuSize{}, false, fmt.Errorf("failed to get rich menu: %w", err)
+	}
+	if menu.Size == (api.RichMenuSize{}) {
+		return api.RichMenuSize{}, false, nil
+	}
+	return menu.Size, true, nil
+}
+
+// checkRichMenuImageSize verifies that the image dimensions exactly match
+// the size declared in the rich menu definition.
+func checkRichMenuImageSize(data []byte, contentType string, declared api.RichMenuSize) error {
+	size, err := richMenuImageSize(data, contentType)
+	if err != nil {
+		return err
+	}
+	if size != declared {
+		return fmt.Errorf("image is %dx%d but the rich menu size is %dx%d (common sizes: 2500x1686, 2500x843, 1200x810, 1200x405, 800x540, 800x270)",
+			size.Width, size.Height, declared.Width, declared.Height)
+	}
+	return nil
+}
+
 func newRichMenuGetCmd() *cobra.Command {
 	return newRichMenuGetCmdWithClient(nil)
 }
 
 func newRichMenuGetCmdWithClient(client *api.Client) *cobra.Command {
 	var richMenuID string
+	var name string
 
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Get rich menu details",
 		Long:  "Get detailed information about a specific rich menu.",
+		Example: `  line richmenu get --id richmenu-xxx
+  line richmenu get --name "Main Menu"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if richMenuID == "" {
-				return fmt.Errorf("--id is required")
+			if richMenuID == "" && name == "" {
+				return fmt.Errorf("--id or --name is required")
 			}
 
 			c := client
@@ -459,6 +712,10 @@ func newRichMenuGetCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
+			richMenuID, err := resolveRichMenuID(cmd.Context(), c, richMenuID, name)
+			if err != nil {
+				return err
+			}
 			menu, err := c.GetRichMenu(cmd.Context(), richMenuID)
 			if err != nil {
 				return fmt.Errorf("failed to get rich menu: %w", err)
@@ -479,8 +736,8 @@ func newRichMenuGetCmdWithClient(client *api.Client) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID (required)")
-	_ = cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID")
+	addNameFlag(cmd, &name, "rich menu")
 
 	return cmd
 }
@@ -492,18 +749,20 @@ func newRichMenuLinkCmd() *cobra.Command {
 func newRichMenuLinkCmdWithClient(client *api.Client) *cobra.Command {
 	var userID string
 	var richMenuID string
+	var name string
 
 	cmd := &cobra.Command{
-		Use:     "link",
-		Short:   "Link rich menu to a user",
-		Long:    "Assign a specific rich menu to a user (overrides default).",
-		Example: `  line richmenu link --user U123... --id richmenu-xxx`,
+		Use:   "link",
+		Short: "Link rich menu to a user",
+		Long:  "Assign a specific rich menu to a user (overrides default).",
+		Example: `  line richmenu link --user U123... --id richmenu-xxx
+  line richmenu link --user U123... --name "VIP Menu"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if userID == "" {
 				return fmt.Errorf("--user is required")
 			}
-			if richMenuID == "" {
-				return fmt.Errorf("--id is required")
+			if richMenuID == "" && name == "" {
+				return fmt.Errorf("--id or --name is required")
 			}
 
 			c := client
@@ -515,6 +774,11 @@ func newRichMenuLinkCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
+			richMenuID, err := resolveRichMenuID(cmd.Context(), c, richMenuID, name)
+			if err != nil {
+				return err
+			}
+
 			if err := c.LinkRichMenuToUser(cmd.Context(), userID, richMenuID); err != nil {
 				return fmt.Errorf("failed to link rich menu: %w", err)
 			}
@@ -531,9 +795,9 @@ func newRichMenuLinkCmdWithClient(client *api.Client) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&userID, "user", "", "User ID (required)")
-	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID (required)")
+	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID")
+	addNameFlag(cmd, &name, "rich menu")
 	_ = cmd.MarkFlagRequired("user")
-	_ = cmd.MarkFlagRequired("id")
 
 	return cmd
 }
@@ -596,6 +860,7 @@ func newRichMenuAliasCmd() *cobra.Command {
 	cmd.AddCommand(newRichMenuAliasUpdateCmd())
 	cmd.AddCommand(newRichMenuAliasDeleteCmd())
 	cmd.AddCommand(newRichMenuAliasListCmd())
+	cmd.AddCommand(newRichMenuAliasSyncCmd())
 	return cmd
 }
 
@@ -834,9 +1099,21 @@ func newRichMenuAliasListCmdWithClient(client *api.Client) *cobra.Command {
 			}
 
 			if flags.Output == "table" {
-				table := NewTable("ALIAS", "RICH MENU ID")
-				for _, alias := range aliases {
-					table.AddRow(alias.RichMenuAliasID, alias.RichMenuID)
+				// Look up each target menu's name concurrently; a missing menu leaves the column blank
+				names, err := enrichConcurrently(cmd.Context(), aliases, 5, func(ctx context.Context, alias api.RichMenuAlias) (string, error) {
+					menu, err := c.GetRichMenu(ctx, alias.RichMenuID)
+					if err != nil {
+						return "", nil
+					}
+					return menu.Name, nil
+				})
+				if err != nil {
+					return err
+				}
+
+				table := NewTable("ALIAS", "RICH MENU ID", "MENU NAME")
+				for i, alias := range aliases {
+					table.AddRow(alias.RichMenuAliasID, alias.RichMenuID, names[i])
 				}
 				table.Render(cmd.OutOrStdout())
 				return nil
@@ -876,35 +1153,44 @@ func newRichMenuBulkLinkCmd() *cobra.Command {
 func newRichMenuBulkLinkCmdWithClient(client *api.Client, userIDsOverride []string) *cobra.Command {
 	var richMenuID string
 	var usersFile string
+	var maxUsers int
+	var concurrency int
+	var reportPath string
 
 	cmd := &cobra.Command{
 		Use:   "link",
 		Short: "Link rich menu to multiple users",
-		Long:  "Link a rich menu to multiple users at once. User IDs are read from a file (one per line).",
+		Long: `Link a rich menu to multiple users at once. User IDs are read from a file (one per line).
+
+The file is streamed, so exports with millions of lines are fine: every line
+must be a valid user ID (blank lines and # comments are skipped), repeated IDs
+are skipped, and users are linked in requests of 500.
+
+If the bulk endpoint is not available on the channel's plan (403), users are
+linked one request at a time instead, --concurrency at a time, with a
+progress bar on stderr. Requests rejected with 429 are retried, and every
+user's outcome is written to the CSV --report.`,
 		Example: `  # Link a menu to users from a file
-  line richmenu bulk link --menu richmenu-xxx --users users.txt`,
+  line richmenu bulk link --menu richmenu-xxx --users users.txt
+
+  # On plans without bulk endpoints, control the per-user fallback
+  line richmenu bulk link --menu richmenu-xxx --users users.txt --concurrency 20 --report link.csv`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if richMenuID == "" {
 				return fmt.Errorf("--menu is required")
 			}
-
-			var userIDs []string
-			if userIDsOverride != nil {
-				userIDs = userIDsOverride
-			} else {
-				if usersFile == "" {
-					return fmt.Errorf("--users is required")
-				}
-				var err error
-				userIDs, err = readUserIDsFromFile(usersFile)
-				if err != nil {
-					return fmt.Errorf("failed to read users file: %w", err)
-				}
+			if userIDsOverride == nil && usersFile == "" {
+				return fmt.Errorf("--users is required")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
 			}
 
-			if len(userIDs) == 0 {
-				return fmt.Errorf("no user IDs found in file")
+			usersFile, cleanup, err := spoolStdin(usersFile)
+			if err != nil {
+				return err
 			}
+			defer cleanup()
 
 			c := client
 			if c == nil {
@@ -915,27 +1201,74 @@ func newRichMenuBulkLinkCmdWithClient(client *api.Client, userIDsOverride []stri
 				}
 			}
 
-			if err := c.LinkRichMenuToUsers(cmd.Context(), richMenuID, userIDs); err != nil {
-				return fmt.Errorf("failed to bulk link: %w", err)
+			linked := 0
+			var fallback *perUserFallback
+			_, err = forEachUserBatch(usersFile, userIDsOverride, maxUsers, api.MaxBulkUserIDs, func(batch []string) error {
+				if fallback == nil {
+					err := c.LinkRichMenuToUsers(cmd.Context(), richMenuID, batch)
+					if err == nil {
+						linked += len(batch)
+						return nil
+					}
+					if !bulkPlanUnavailable(err) {
+						return fmt.Errorf("failed to bulk link after %d users: %w", linked, err)
+					}
+					total := countUsers(usersFile, userIDsOverride, maxUsers) - linked
+					if fallback, err = startPerUserFallback(cmd, "link", concurrency, reportPath, total); err != nil {
+						return err
+					}
+				}
+				fallback.run(cmd.Context(), batch, func(ctx context.Context, userID string) error {
+					return c.LinkRichMenuToUser(ctx, userID, richMenuID)
+				})
+				return cmd.Context().Err()
+			})
+			failed := 0
+			if fallback != nil {
+				n, ferr := fallback.finish()
+				linked += n
+				failed = fallback.failed
+				if err == nil {
+					err = ferr
+				}
+			}
+			if err != nil {
+				return err
 			}
 
 			if flags.Output == "json" {
 				result := map[string]any{
 					"richMenuId": richMenuID,
-					"userCount":  len(userIDs),
+					"userCount":  linked,
 					"status":     "linked",
 				}
+				if fallback != nil {
+					result["mode"] = "per-user"
+					result["failedCount"] = failed
+					result["report"] = fallback.reportPath
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				return enc.Encode(result)
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Linked rich menu %s to %d users\n", richMenuID, linked)
+				if fallback != nil {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Failed: %d users (report: %s)\n", failed, fallback.reportPath)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to link %d users; see %s", failed, fallback.reportPath)
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Linked rich menu %s to %d users\n", richMenuID, len(userIDs))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&richMenuID, "menu", "", "Rich menu ID (required)")
-	cmd.Flags().StringVar(&usersFile, "users", "", "File containing user IDs, one per line (required)")
+	cmd.Flags().StringVar(&usersFile, "users", "", "File containing user IDs, one per line, or - for stdin (required)")
+	addMaxUsersFlag(cmd, &maxUsers)
+	addBulkFallbackFlags(cmd, &concurrency, &reportPath)
 	_ = cmd.MarkFlagRequired("menu")
 	// Note: --users is not marked required since userIDsOverride can be used in tests
 
@@ -948,31 +1281,34 @@ func newRichMenuBulkUnlinkCmd() *cobra.Command {
 
 func newRichMenuBulkUnlinkCmdWithClient(client *api.Client, userIDsOverride []string) *cobra.Command {
 	var usersFile string
+	var maxUsers int
+	var concurrency int
+	var reportPath string
 
 	cmd := &cobra.Command{
 		Use:   "unlink",
 		Short: "Unlink rich menus from multiple users",
-		Long:  "Unlink rich menus from multiple users at once. User IDs are read from a file (one per line).",
+		Long: `Unlink rich menus from multiple users at once. User IDs are read from a file (one per line).
+
+The file is streamed like "line richmenu bulk link": every line must be a
+valid user ID, repeated IDs are skipped, and users are unlinked in requests
+of 500. On plans without the bulk endpoint it falls back to per-user
+requests the same way, with --concurrency and --report.`,
 		Example: `  # Unlink menus from users in a file
   line richmenu bulk unlink --users users.txt`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var userIDs []string
-			if userIDsOverride != nil {
-				userIDs = userIDsOverride
-			} else {
-				if usersFile == "" {
-					return fmt.Errorf("--users is required")
-				}
-				var err error
-				userIDs, err = readUserIDsFromFile(usersFile)
-				if err != nil {
-					return fmt.Errorf("failed to read users file: %w", err)
-				}
+			if userIDsOverride == nil && usersFile == "" {
+				return fmt.Errorf("--users is required")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
 			}
 
-			if len(userIDs) == 0 {
-				return fmt.Errorf("no user IDs found in file")
+			usersFile, cleanup, err := spoolStdin(usersFile)
+			if err != nil {
+				return err
 			}
+			defer cleanup()
 
 			c := client
 			if c == nil {
@@ -983,52 +1319,75 @@ func newRichMenuBulkUnlinkCmdWithClient(client *api.Client, userIDsOverride []st
 				}
 			}
 
-			if err := c.UnlinkRichMenuFromUsers(cmd.Context(), userIDs); err != nil {
-				return fmt.Errorf("failed to bulk unlink: %w", err)
+			unlinked := 0
+			var fallback *perUserFallback
+			_, err = forEachUserBatch(usersFile, userIDsOverride, maxUsers, api.MaxBulkUserIDs, func(batch []string) error {
+				if fallback == nil {
+					err := c.UnlinkRichMenuFromUsers(cmd.Context(), batch)
+					if err == nil {
+						unlinked += len(batch)
+						return nil
+					}
+					if !bulkPlanUnavailable(err) {
+						return fmt.Errorf("failed to bulk unlink after %d users: %w", unlinked, err)
+					}
+					total := countUsers(usersFile, userIDsOverride, maxUsers) - unlinked
+					if fallback, err = startPerUserFallback(cmd, "unlink", concurrency, reportPath, total); err != nil {
+						return err
+					}
+				}
+				fallback.run(cmd.Context(), batch, c.UnlinkRichMenuFromUser)
+				return cmd.Context().Err()
+			})
+			failed := 0
+			if fallback != nil {
+				n, ferr := fallback.finish()
+				unlinked += n
+				failed = fallback.failed
+				if err == nil {
+					err = ferr
+				}
+			}
+			if err != nil {
+				return err
 			}
 
 			if flags.Output == "json" {
 				result := map[string]any{
-					"userCount": len(userIDs),
+					"userCount": unlinked,
 					"status":    "unlinked",
 				}
+				if fallback != nil {
+					result["mode"] = "per-user"
+					result["failedCount"] = failed
+					result["report"] = fallback.reportPath
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
-				return enc.Encode(result)
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Unlinked rich menus from %d users\n", unlinked)
+				if fallback != nil {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Failed: %d users (report: %s)\n", failed, fallback.reportPath)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to unlink %d users; see %s", failed, fallback.reportPath)
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Unlinked rich menus from %d users\n", len(userIDs))
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&usersFile, "users", "", "File containing user IDs, one per line (required)")
+	cmd.Flags().StringVar(&usersFile, "users", "", "File containing user IDs, one per line, or - for stdin (required)")
+	addMaxUsersFlag(cmd, &maxUsers)
+	addBulkFallbackFlags(cmd, &concurrency, &reportPath)
 	// Note: --users is not marked required since userIDsOverride can be used in tests
 
 	return cmd
 }
 
-// readUserIDsFromFile reads user IDs from a file, one per line
-func readUserIDsFromFile(path string) ([]string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = file.Close() }()
-
-	var userIDs []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !strings.HasPrefix(line, "#") {
-			userIDs = append(userIDs, line)
-		}
-	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-	return userIDs, nil
-}
-
 // Batch operations commands
 
 func newRichMenuBatchCmd() *cobra.Command {
@@ -1038,6 +1397,10 @@ func newRichMenuBatchCmd() *cobra.Command {
 func newRichMenuBatchCmdWithClient(client *api.Client, operationsOverride []api.RichMenuBatchOperation) *cobra.Command {
 	var operationsFile string
 	var resumeRequestID string
+	var skip int
+	var wait bool
+	var timeout time.Duration
+	var interval time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "batch",
@@ -1046,12 +1409,21 @@ func newRichMenuBatchCmdWithClient(client *api.Client, operationsOverride []api.
 [
   {"type": "link", "richMenuId": "richmenu-xxx", "userIds": ["U1", "U2"]},
   {"type": "unlink", "userIds": ["U3", "U4"]}
-]`,
+]
+
+A request holds at most 1000 operations of at most 500 users each. Larger
+files are split into several requests, submitted one after another; each
+request is atomic, but the set as a whole is not. With --wait, each request
+must succeed before the next is submitted. To continue after request N
+failed, rerun with --resume <its ID> --skip <N-1>.`,
 		Example: `  # Execute batch operations from a file
   line richmenu batch --operations ops.json
 
   # Resume a failed batch
-  line richmenu batch --operations ops.json --resume abc123`,
+  line richmenu batch --operations ops.json --resume abc123
+
+  # Submit a large file one request at a time
+  line richmenu batch --operations ops.json --wait --timeout 30m`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var operations []api.RichMenuBatchOperation
 			if operationsOverride != nil {
@@ -1072,6 +1444,10 @@ func newRichMenuBatchCmdWithClient(client *api.Client, operationsOverride []api.
 			if len(operations) == 0 {
 				return fmt.Errorf("no operations found in file")
 			}
+			total := len(api.SplitRichMenuBatch(operations))
+			if skip < 0 || skip >= total {
+				return withExitCode(ExitUsage, fmt.Errorf("--skip must be between 0 and %d for %d requests", total-1, total))
+			}
 
 			c := client
 			if c == nil {
@@ -1082,32 +1458,71 @@ func newRichMenuBatchCmdWithClient(client *api.Client, operationsOverride []api.
 				}
 			}
 
-			requestID, err := c.RichMenuBatch(cmd.Context(), operations, resumeRequestID)
+			ctx := cmd.Context()
+			if wait {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			submitted := 0
+			requestIDs, err := c.RichMenuBatchAll(ctx, operations, api.RichMenuBatchOptions{
+				ResumeRequestID: resumeRequestID,
+				Skip:            skip,
+				Wait:            wait,
+				PollInterval:    interval,
+				OnSubmit: func(index int, requestID string, ops []api.RichMenuBatchOperation) {
+					submitted += len(ops)
+					if flags.Output != "json" {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Batch submitted: %s (%d operations)\n", requestID, len(ops))
+					} else if total > 1 {
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Submitted request %d of %d: %s\n", index+1, total, requestID)
+					}
+				},
+			})
 			if err != nil {
+				if len(requestIDs) > 0 {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Submitted before the error: %s\n", strings.Join(requestIDs, ", "))
+				}
 				return fmt.Errorf("failed to execute batch: %w", err)
 			}
 
+			status := "submitted"
+			if wait {
+				status = "succeeded"
+			}
 			if flags.Output == "json" {
 				result := map[string]any{
-					"requestId":      requestID,
-					"operationCount": len(operations),
-					"status":         "submitted",
+					"requestId":      requestIDs[0],
+					"requestIds":     requestIDs,
+					"operationCount": submitted,
+					"status":         status,
 				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
 				return enc.Encode(result)
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Batch submitted: %s (%d operations)\n", requestID, len(operations))
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Check progress with: line richmenu batch status --request %s\n", requestID)
+			if wait {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "All %d requests succeeded\n", len(requestIDs))
+				return nil
+			}
+			for _, requestID := range requestIDs {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Check progress with: line richmenu batch status --request %s\n", requestID)
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&operationsFile, "operations", "", "JSON file containing batch operations")
+	cmd.Flags().StringVar(&operationsFile, "operations", "", "JSON file containing batch operations, or - for stdin")
 	cmd.Flags().StringVar(&resumeRequestID, "resume", "", "Resume a previous batch request")
+	cmd.Flags().IntVar(&skip, "skip", 0, "Requests of a split batch that already succeeded, to continue after a failure")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait for each request to succeed before submitting the next")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "Maximum time to wait with --wait")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval with --wait")
 
 	cmd.AddCommand(newRichMenuBatchValidateCmd())
 	cmd.AddCommand(newRichMenuBatchStatusCmd())
+	cmd.AddCommand(newRichMenuBatchPlanCmd())
+	cmd.AddCommand(newRichMenuBatchGenerateCmd())
 
 	return cmd
 }
@@ -1154,8 +1569,15 @@ func newRichMenuBatchValidateCmdWithClient(client *api.Client, operationsOverrid
 				}
 			}
 
-			if err := c.ValidateRichMenuBatch(cmd.Context(), operations); err != nil {
-				return fmt.Errorf("validation failed: %w", err)
+			// Validate each request a split batch would make
+			sets := api.SplitRichMenuBatch(operations)
+			for i, set := range sets {
+				if err := c.ValidateRichMenuBatch(cmd.Context(), set); err != nil {
+					if len(sets) > 1 {
+						return fmt.Errorf("validation failed for request %d of %d: %w", i+1, len(sets), err)
+					}
+					return fmt.Errorf("validation failed: %w", err)
+				}
 			}
 
 			if flags.Output == "json" {
@@ -1172,7 +1594,7 @@ func newRichMenuBatchValidateCmdWithClient(client *api.Client, operationsOverrid
 		},
 	}
 
-	cmd.Flags().StringVar(&operationsFile, "operations", "", "JSON file containing batch operations (required)")
+	cmd.Flags().StringVar(&operationsFile, "operations", "", "JSON file containing batch operations, or - for stdin (required)")
 	// Note: --operations is not marked required since operationsOverride can be used in tests
 
 	return cmd
@@ -1184,17 +1606,35 @@ func newRichMenuBatchStatusCmd() *cobra.Command {
 
 func newRichMenuBatchStatusCmdWithClient(client *api.Client) *cobra.Command {
 	var requestID string
+	var wait bool
+	var watch bool
+	var count int
+	var timeout time.Duration
+	var interval time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Get batch operation status",
-		Long:  "Get the progress of a batch operation.",
+		Long: `Get the progress of a batch operation.
+
+With --wait, poll until the operation succeeds or fails. The command exits
+non-zero if the operation fails or --timeout elapses first. With --watch,
+redraw the status every --interval until interrupted.`,
 		Example: `  # Check batch status
-  line richmenu batch status --request abc123`,
+  line richmenu batch status --request abc123
+
+  # Wait for the batch to finish
+  line richmenu batch status --request abc123 --wait --timeout 10m
+
+  # Monitor the batch live
+  line richmenu batch status --request abc123 --watch --interval 30s`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if requestID == "" {
 				return fmt.Errorf("--request is required")
 			}
+			if wait && watch {
+				return fmt.Errorf("--wait and --watch cannot be used together")
+			}
 
 			c := client
 			if c == nil {
@@ -1205,42 +1645,105 @@ func newRichMenuBatchStatusCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
-			progress, err := c.GetRichMenuBatchProgress(cmd.Context(), requestID)
-			if err != nil {
-				return fmt.Errorf("failed to get batch status: %w", err)
+			if wait {
+				return waitForRichMenuBatch(cmd, c, requestID, timeout, interval)
 			}
 
-			if flags.Output == "json" {
-				result := map[string]any{
-					"requestId":     requestID,
-					"phase":         progress.Phase,
-					"acceptedTime":  progress.AcceptedTime,
-					"completedTime": progress.CompletedTime,
+			render := func(ctx context.Context, w io.Writer) error {
+				progress, err := c.GetRichMenuBatchProgress(ctx, requestID)
+				if err != nil {
+					return fmt.Errorf("failed to get batch status: %w", err)
 				}
-				enc := json.NewEncoder(cmd.OutOrStdout())
-				enc.SetIndent("", "  ")
-				return enc.Encode(result)
+
+				if flags.Output == "json" {
+					result := map[string]any{
+						"requestId":     requestID,
+						"phase":         progress.Phase,
+						"acceptedTime":  progress.AcceptedTime,
+						"completedTime": progress.CompletedTime,
+					}
+					enc := json.NewEncoder(w)
+					enc.SetIndent("", "  ")
+					return enc.Encode(result)
+				}
+
+				printBatchProgress(w, requestID, progress)
+				return nil
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Request ID:     %s\n", requestID)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Phase:          %s\n", progress.Phase)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Accepted Time:  %s\n", progress.AcceptedTime)
-			if progress.CompletedTime != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Completed Time: %s\n", progress.CompletedTime)
+			if watch {
+				return watchOutput(cmd, interval, count, render)
 			}
-			return nil
+			return render(cmd.Context(), cmd.OutOrStdout())
 		},
 	}
 
 	cmd.Flags().StringVar(&requestID, "request", "", "Batch request ID (required)")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Poll until the batch succeeds or fails")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "Maximum time to wait with --wait")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval with --wait or --watch")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Refresh the status every --interval until interrupted")
+	cmd.Flags().IntVar(&count, "count", 0, "Stop after this many refreshes with --watch (0 = until interrupted)")
 	_ = cmd.MarkFlagRequired("request")
 
 	return cmd
 }
 
+// printBatchProgress prints batch progress in text form.
+func printBatchProgress(w io.Writer, requestID string, progress *api.BatchProgress) {
+	_, _ = fmt.Fprintf(w, "Request ID:     %s\n", requestID)
+	_, _ = fmt.Fprintf(w, "Phase:          %s\n", colorStatus(w, progress.Phase))
+	_, _ = fmt.Fprintf(w, "Accepted Time:  %s\n", progress.AcceptedTime)
+	if progress.CompletedTime != "" {
+		_, _ = fmt.Fprintf(w, "Completed Time: %s\n", progress.CompletedTime)
+	}
+}
+
+// waitForRichMenuBatch polls a batch operation until it leaves the ongoing
+// phase, then prints a summary. It returns an error if the batch failed or
+// the timeout elapsed.
+func waitForRichMenuBatch(cmd *cobra.Command, c *api.Client, requestID string, timeout, interval time.Duration) error {
+	start := clockNow()
+	var progress *api.BatchProgress
+	polls, err := pollUntil(cmd, "batch "+requestID, timeout, interval, func(ctx context.Context) (bool, string, error) {
+		p, err := c.GetRichMenuBatchProgress(ctx, requestID)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get batch status: %w", err)
+		}
+		progress = p
+		return p.Phase != "ongoing", p.Phase, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if flags.Output == "json" {
+		result := map[string]any{
+			"requestId":      requestID,
+			"phase":          progress.Phase,
+			"acceptedTime":   progress.AcceptedTime,
+			"completedTime":  progress.CompletedTime,
+			"polls":          polls,
+			"elapsedSeconds": clockNow().Sub(start).Seconds(),
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printBatchProgress(cmd.OutOrStdout(), requestID, progress)
+	}
+
+	if progress.Phase == "failed" {
+		return fmt.Errorf("batch operation %s failed", requestID)
+	}
+	return nil
+}
+
 // readBatchOperationsFromFile reads batch operations from a JSON file
 func readBatchOperationsFromFile(path string) ([]api.RichMenuBatchOperation, error) {
-	data, err := os.ReadFile(path)
+	data, err := readFileOrStdin(path)
 	if err != nil {
 		return nil, err
 	}
@@ -1260,44 +1763,79 @@ func newRichMenuValidateCmd() *cobra.Command {
 
 func newRichMenuValidateCmdWithClient(client *api.Client, menuOverride *api.CreateRichMenuRequest) *cobra.Command {
 	var menuFile string
+	var offline bool
 
 	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate a rich menu definition",
-		Long:  "Validate a rich menu JSON definition without creating it.",
+		Long: `Validate a rich menu JSON definition without creating it.
+
+The definition is first checked locally, and every problem found is
+reported at once with a JSON pointer to it:
+- the size is 800-2500 wide and at least 250 tall
+- every area lies inside the size, and there are at most 20 areas
+- every action has the fields its type requires
+- chatBarText is at most 14 characters
+
+Only a definition that passes is sent to LINE's validate endpoint, unless
+--offline is given.`,
 		Example: `  # Validate a rich menu definition
-  line richmenu validate --file menu.json`,
+  line richmenu validate --file menu.json
+
+  # Check it locally only
+  line richmenu validate --file menu.json --offline`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var menu *api.CreateRichMenuRequest
+			var data []byte
 			if menuOverride != nil {
-				menu = menuOverride
+				var err error
+				if data, err = json.Marshal(menuOverride); err != nil {
+					return fmt.Errorf("failed to encode menu: %w", err)
+				}
 			} else {
 				if menuFile == "" {
 					return fmt.Errorf("--file is required")
 				}
 
-				data, err := os.ReadFile(menuFile)
+				var err error
+				data, err = readFileOrStdin(menuFile)
 				if err != nil {
 					return fmt.Errorf("failed to read file: %w", err)
 				}
+			}
 
-				menu = &api.CreateRichMenuRequest{}
-				if err := json.Unmarshal(data, menu); err != nil {
-					return fmt.Errorf("invalid JSON: %w", err)
+			if problems := checkRichMenu(data); len(problems) > 0 {
+				if flags.Output == "json" {
+					enc := json.NewEncoder(cmd.OutOrStdout())
+					enc.SetIndent("", "  ")
+					if err := enc.Encode(map[string]any{"valid": false, "problems": problems}); err != nil {
+						return err
+					}
+				} else {
+					for _, p := range problems {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", getDefault(p.Path, "/"), p.Message)
+					}
 				}
+				return fmt.Errorf("%d problem(s) found", len(problems))
 			}
 
-			c := client
-			if c == nil {
-				var err error
-				c, err = newAPIClient()
-				if err != nil {
-					return err
-				}
+			menu := &api.CreateRichMenuRequest{}
+			if err := json.Unmarshal(data, menu); err != nil {
+				return fmt.Errorf("invalid JSON: %w", err)
 			}
 
-			if err := c.ValidateRichMenu(cmd.Context(), menu); err != nil {
-				return fmt.Errorf("validation failed: %w", err)
+			if !offline {
+				c := client
+				if c == nil {
+					var err error
+					c, err = newAPIClient()
+					if err != nil {
+						return err
+					}
+				}
+
+				if err := c.ValidateRichMenu(cmd.Context(), menu); err != nil {
+					return fmt.Errorf("validation failed: %w", err)
+				}
 			}
 
 			if flags.Output == "json" {
@@ -1314,7 +1852,8 @@ func newRichMenuValidateCmdWithClient(client *api.Client, menuOverride *api.Crea
 		},
 	}
 
-	cmd.Flags().StringVar(&menuFile, "file", "", "JSON file containing rich menu definition (required)")
+	cmd.Flags().StringVar(&menuFile, "file", "", "JSON file containing rich menu definition, or - for stdin (required)")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Only run the local checks, without calling the API")
 	// Note: --file is not marked required since menuOverride can be used in tests
 
 	return cmd
@@ -1328,6 +1867,7 @@ func newRichMenuDownloadImageCmd() *cobra.Command {
 
 func newRichMenuDownloadImageCmdWithClient(client *api.Client) *cobra.Command {
 	var richMenuID string
+	var name string
 	var outputPath string
 
 	cmd := &cobra.Command{
@@ -1338,10 +1878,10 @@ func newRichMenuDownloadImageCmdWithClient(client *api.Client) *cobra.Command {
   line richmenu download-image --id richmenu-xxx
 
   # Download to specific path
-  line richmenu download-image --id richmenu-xxx --output menu.png`,
+  line richmenu download-image --name "Main Menu" --output menu.png`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if richMenuID == "" {
-				return fmt.Errorf("--id is required")
+			if richMenuID == "" && name == "" {
+				return fmt.Errorf("--id or --name is required")
 			}
 
 			c := client
@@ -1353,6 +1893,11 @@ func newRichMenuDownloadImageCmdWithClient(client *api.Client) *cobra.Command {
 				}
 			}
 
+			richMenuID, err := resolveRichMenuID(cmd.Context(), c, richMenuID, name)
+			if err != nil {
+				return err
+			}
+
 			data, contentType, err := c.DownloadRichMenuImage(cmd.Context(), richMenuID)
 			if err != nil {
 				return fmt.Errorf("failed to download image: %w", err)
@@ -1388,9 +1933,353 @@ func newRichMenuDownloadImageCmdWithClient(client *api.Client) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID (required)")
+	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID")
+	addNameFlag(cmd, &name, "rich menu")
 	cmd.Flags().StringVar(&outputPath, "output", "", "Output file path (default: richmenu-{id}.{ext})")
-	_ = cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+// Deploy command
+
+// richMenuDeployStep records the outcome of one step of a deploy.
+type richMenuDeployStep struct {
+	Step   string `json:"step"`
+	Status string `json:"status"` // "ok", "failed", "rolled-back", "rollback-failed"
+	Detail string `json:"detail,omitempty"`
+}
+
+// richMenuDeployer runs deploy steps in order, reporting progress and
+// remembering how to undo each completed step so a failure can be rolled back.
+type richMenuDeployer struct {
+	progress io.Writer
+	steps    []richMenuDeployStep
+	undo     []richMenuDeployUndo
+}
+
+type richMenuDeployUndo struct {
+	index int // position of the step in steps
+	fn    func(ctx context.Context) error
+}
+
+// run executes a single step. On success the optional undo function is
+// pushed onto the rollback stack.
+func (d *richMenuDeployer) run(ctx context.Context, step, description string, fn func(ctx context.Context) (undo func(ctx context.Context) error, detail string, err error)) error {
+	_, _ = fmt.Fprintf(d.progress, "==> %s\n", description)
+
+	undo, detail, err := fn(ctx)
+	if err != nil {
+		d.steps = append(d.steps, richMenuDeployStep{Step: step, Status: "failed", Detail: err.Error()})
+		_, _ = fmt.Fprintf(d.progress, "    failed: %v\n", err)
+		return err
+	}
+
+	d.steps = append(d.steps, richMenuDeployStep{Step: step, Status: "ok", Detail: detail})
+	if undo != nil {
+		d.undo = append(d.undo, richMenuDeployUndo{index: len(d.steps) - 1, fn: undo})
+	}
+	if detail != "" {
+		_, _ = fmt.Fprintf(d.progress, "    ok: %s\n", detail)
+	} else {
+		_, _ = fmt.Fprintln(d.progress, "    ok")
+	}
+	return nil
+}
+
+// rollback undoes completed steps in reverse order. Errors are recorded but
+// do not stop the remaining undo steps. It returns the steps that could not
+// be undone, each with its error.
+func (d *richMenuDeployer) rollback(ctx context.Context) []string {
+	var failed []string
+	for i := len(d.undo) - 1; i >= 0; i-- {
+		u := d.undo[i]
+		step := &d.steps[u.index]
+		_, _ = fmt.Fprintf(d.progress, "==> Rolling back %s\n", step.Step)
+		if err := u.fn(ctx); err != nil {
+			step.Status = "rollback-failed"
+			step.Detail = err.Error()
+			failed = append(failed, fmt.Sprintf("%s: %v", step.Step, err))
+			_, _ = fmt.Fprintf(d.progress, "    failed: %v\n", err)
+		} else {
+			step.Status = "rolled-back"
+			_, _ = fmt.Fprintln(d.progress, "    ok")
+		}
+	}
+	d.undo = nil
+	return failed
+}
+
+func newRichMenuDeployCmd() *cobra.Command {
+	return newRichMenuDeployCmdWithClient(nil)
+}
+
+func newRichMenuDeployCmdWithClient(client *api.Client) *cobra.Command {
+	var menuFile string
+	var imagePath string
+	var aliasID string
+	var setDefault bool
+
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Create, upload, alias, and set default in one step",
+		Long: `Deploy a rich menu in one step: create it from a JSON definition, upload
+its image, optionally point an alias at it, and optionally make it the
+default menu.
+
+If any step fails, completed steps are rolled back: the alias is restored
+to its previous menu (or deleted if it was created), and the new menu is
+deleted. If a step cannot be undone, the error names it so it can be
+cleaned up by hand. Progress is reported on stderr.`,
+		Example: `  # Create and upload a menu
+  line richmenu deploy --file menu.json --image menu.png
+
+  # Deploy, point the "main" alias at it, and make it the default
+  line richmenu deploy --file menu.json --image menu.png --alias main --default`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if menuFile == "" {
+				return fmt.Errorf("--file is required")
+			}
+			if imagePath == "" {
+				return fmt.Errorf("--image is required")
+			}
+
+			menu, err := readRichMenuDefinitionFromFile(menuFile)
+			if err != nil {
+				return fmt.Errorf("failed to read menu file: %w", err)
+			}
+			imageData, contentType, err := readRichMenuImage(imagePath)
+			if err != nil {
+				return err
+			}
+			if err := checkRichMenuImageSize(imageData, contentType, menu.Size); err != nil {
+				return err
+			}
+
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			return deployRichMenu(cmd, c, menu, imageData, contentType, aliasID, setDefault)
+		},
+	}
+
+	cmd.Flags().StringVar(&menuFile, "file", "", "JSON file containing the rich menu definition, or - for stdin (required)")
+	cmd.Flags().StringVar(&imagePath, "image", "", "Path to PNG or JPEG image (required)")
+	cmd.Flags().StringVar(&aliasID, "alias", "", "Create or update this alias to point at the new menu")
+	cmd.Flags().BoolVar(&setDefault, "default", false, "Set the new menu as the default for all users")
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagRequired("image")
+
+	return cmd
+}
+
+// deployRichMenu runs the deploy steps and rolls back on failure.
+func deployRichMenu(cmd *cobra.Command, c *api.Client, menu *api.CreateRichMenuRequest, imageData []byte, contentType, aliasID string, setDefault bool) error {
+	ctx := cmd.Context()
+	d := &richMenuDeployer{progress: cmd.ErrOrStderr()}
+
+	var richMenuID string
+	deployErr := d.run(ctx, "create", fmt.Sprintf("Creating rich menu %q", menu.Name), func(ctx context.Context) (func(context.Context) error, string, error) {
+		id, err := c.CreateRichMenu(ctx, *menu)
+		if err != nil {
+			return nil, "", err
+		}
+		richMenuID = id
+		return func(ctx context.Context) error { return c.DeleteRichMenu(ctx, id) }, id, nil
+	})
+
+	if deployErr == nil {
+		deployErr = d.run(ctx, "upload-image", fmt.Sprintf("Uploading image (%d bytes)", len(imageData)), func(ctx context.Context) (func(context.Context) error, string, error) {
+			return nil, "", c.UploadRichMenuImage(ctx, richMenuID, contentType, imageData)
+		})
+	}
+
+	if deployErr == nil && aliasID != "" {
+		deployErr = d.run(ctx, "alias", fmt.Sprintf("Pointing alias %q at %s", aliasID, richMenuID), func(ctx context.Context) (func(context.Context) error, string, error) {
+			existing, err := c.GetRichMenuAlias(ctx, aliasID)
+			if err != nil {
+				if apiErr := api.AsAPIError(err); apiErr == nil || !apiErr.IsNotFound() {
+					return nil, "", err
+				}
+				if err := c.CreateRichMenuAlias(ctx, aliasID, richMenuID); err != nil {
+					return nil, "", err
+				}
+				return func(ctx context.Context) error { return c.DeleteRichMenuAlias(ctx, aliasID) }, "created", nil
+			}
+
+			previousID := existing.RichMenuID
+			if err := c.UpdateRichMenuAlias(ctx, aliasID, richMenuID); err != nil {
+				return nil, "", err
+			}
+			return func(ctx context.Context) error { return c.UpdateRichMenuAlias(ctx, aliasID, previousID) }, "updated from " + previousID, nil
+		})
+	}
+
+	if deployErr == nil && setDefault {
+		deployErr = d.run(ctx, "set-default", "Setting as default rich menu", func(ctx context.Context) (func(context.Context) error, string, error) {
+			return nil, "", c.SetDefaultRichMenu(ctx, richMenuID)
+		})
+	}
+
+	var rollbackFailed []string
+	if deployErr != nil {
+		rollbackFailed = d.rollback(ctx)
+	}
+
+	if flags.Output == "json" {
+		result := map[string]any{
+			"richMenuId": richMenuID,
+			"name":       menu.Name,
+			"steps":      d.steps,
+			"deployed":   deployErr == nil,
+		}
+		if aliasID != "" {
+			result["alias"] = aliasID
+		}
+		if deployErr == nil {
+			result["default"] = setDefault
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	}
+
+	if len(rollbackFailed) > 0 {
+		return fmt.Errorf("deploy failed and these steps could not be rolled back; undo them manually (%s): %w", strings.Join(rollbackFailed, "; "), deployErr)
+	}
+	if deployErr != nil {
+		return fmt.Errorf("deploy failed and was rolled back: %w", deployErr)
+	}
+
+	if flags.Output != "json" {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Deployed rich menu: %s (ID: %s)\n", menu.Name, richMenuID)
+	}
+	return nil
+}
+
+func newRichMenuLinkedCmd() *cobra.Command {
+	return newRichMenuLinkedCmdWithClient(nil)
+}
+
+func newRichMenuLinkedCmdWithClient(client *api.Client) *cobra.Command {
+	var userID string
+
+	cmd := &cobra.Command{
+		Use:   "linked",
+		Short: "Show which rich menu a user sees",
+		Long: `Show the rich menu a user currently sees, and any aliases pointing at it.
+
+If no menu is linked to the user specifically, the default menu (if any)
+is reported instead. Useful for verifying per-user links after bulk or
+batch operations.`,
+		Example: `  line richmenu linked --user U1234567890abcdef`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if userID == "" {
+				return fmt.Errorf("--user is required")
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			var linkedID, defaultID string
+			var aliases []api.RichMenuAlias
+			err := runConcurrently(cmd.Context(),
+				func(ctx context.Context) error {
+					var err error
+					linkedID, err = c.GetUserRichMenu(ctx, userID)
+					if err != nil {
+						// 404 means no per-user link; the user sees the default menu
+						if apiErr := api.AsAPIError(err); apiErr != nil && apiErr.IsNotFound() {
+							return nil
+						}
+						return fmt.Errorf("failed to get user rich menu: %w", err)
+					}
+					return nil
+				},
+				func(ctx context.Context) error {
+					defaultID, _ = c.GetDefaultRichMenuID(ctx)
+					return nil
+				},
+				func(ctx context.Context) error {
+					// Alias lookup is best-effort
+					aliases, _ = c.ListRichMenuAliases(ctx)
+					return nil
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			richMenuID, source := linkedID, "user"
+			if richMenuID == "" {
+				richMenuID, source = defaultID, "default"
+			}
+			if richMenuID == "" {
+				source = "none"
+			}
+
+			var name string
+			var aliasIDs []string
+			if richMenuID != "" {
+				if menu, err := c.GetRichMenu(cmd.Context(), richMenuID); err == nil {
+					name = menu.Name
+				}
+				for _, a := range aliases {
+					if a.RichMenuID == richMenuID {
+						aliasIDs = append(aliasIDs, a.RichMenuAliasID)
+					}
+				}
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{
+					"userId":     userID,
+					"richMenuId": richMenuID,
+					"source":     source,
+					"name":       name,
+					"aliases":    aliasIDs,
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+
+			out := cmd.OutOrStdout()
+			if source == "none" {
+				_, _ = fmt.Fprintf(out, "User %s has no linked rich menu and no default is set\n", userID)
+				return nil
+			}
+			if name != "" {
+				_, _ = fmt.Fprintf(out, "User %s sees rich menu %s (%s)\n", userID, richMenuID, name)
+			} else {
+				_, _ = fmt.Fprintf(out, "User %s sees rich menu %s\n", userID, richMenuID)
+			}
+			if source == "user" {
+				_, _ = fmt.Fprintln(out, "Source: linked to user")
+			} else {
+				_, _ = fmt.Fprintln(out, "Source: default menu (no per-user link)")
+			}
+			if len(aliasIDs) > 0 {
+				_, _ = fmt.Fprintf(out, "Aliases: %s\n", strings.Join(aliasIDs, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&userID, "user", "", "User ID (required)")
+	_ = cmd.MarkFlagRequired("user")
 
 	return cmd
 }
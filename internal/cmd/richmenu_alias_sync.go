@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// readAliasSyncFile reads the aliases of "richmenu alias sync": a YAML (or
+// JSON) mapping of alias IDs to the ID or name of the menu each points at.
+func readAliasSyncFile(path string) (map[string]string, error) {
+	data, err := readFileOrStdin(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alias file: %w", err)
+	}
+	var aliases map[string]string
+	if err := yaml.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("invalid alias file: expected a mapping of alias IDs to menu IDs or names: %w", err)
+	}
+	if len(aliases) == 0 {
+		return nil, fmt.Errorf("invalid alias file: no aliases")
+	}
+	for alias, target := range aliases {
+		if alias == "" || target == "" {
+			return nil, fmt.Errorf("invalid alias file: alias %q needs a menu ID or name", alias)
+		}
+	}
+	return aliases, nil
+}
+
+// aliasSyncPlan is the set of changes that makes the live aliases match an
+// alias file.
+type aliasSyncPlan struct {
+	changes []richMenuChange
+	// unmanaged are live aliases missing from the file, left as they are
+	// without --prune
+	unmanaged []string
+}
+
+// planAliasSync compares the aliases of a file with the live ones. A target
+// is the ID of a live menu, or otherwise the name of exactly one.
+func planAliasSync(desired map[string]string, menus []api.RichMenu, aliases []api.RichMenuAlias, prune bool) (aliasSyncPlan, error) {
+	ids := map[string]bool{}
+	byName := map[string][]string{}
+	for _, m := range menus {
+		ids[m.RichMenuID] = true
+		byName[m.Name] = append(byName[m.Name], m.RichMenuID)
+	}
+	resolve := func(alias, target string) (string, error) {
+		if ids[target] {
+			return target, nil
+		}
+		switch named := byName[target]; len(named) {
+		case 0:
+			return "", fmt.Errorf("alias %q: no rich menu has the ID or name %q", alias, target)
+		case 1:
+			return named[0], nil
+		default:
+			return "", fmt.Errorf("alias %q: %d rich menus are named %q; use a menu ID instead", alias, len(named), target)
+		}
+	}
+
+	live := map[string]string{}
+	for _, a := range aliases {
+		live[a.RichMenuAliasID] = a.RichMenuID
+	}
+	names := make([]string, 0, len(desired))
+	for alias := range desired {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+
+	var plan aliasSyncPlan
+	for _, alias := range names {
+		target := desired[alias]
+		menuID, err := resolve(alias, target)
+		if err != nil {
+			return aliasSyncPlan{}, err
+		}
+		current, exists := live[alias]
+		switch {
+		case !exists:
+			plan.changes = append(plan.changes, richMenuChange{Action: "create", Kind: "alias", Name: alias, Target: target, TargetID: menuID})
+		case current != menuID:
+			plan.changes = append(plan.changes, richMenuChange{Action: "update", Kind: "alias", Name: alias, ID: current, Target: target, TargetID: menuID, Reason: "points at " + current})
+		}
+	}
+	for _, a := range aliases {
+		if _, ok := desired[a.RichMenuAliasID]; ok {
+			continue
+		}
+		if prune {
+			plan.changes = append(plan.changes, richMenuChange{Action: "delete", Kind: "alias", Name: a.RichMenuAliasID, ID: a.RichMenuID, Reason: "not in alias file"})
+		} else {
+			plan.unmanaged = append(plan.unmanaged, a.RichMenuAliasID)
+		}
+	}
+	return plan, nil
+}
+
+func newRichMenuAliasSyncCmd() *cobra.Command {
+	return newRichMenuAliasSyncCmdWithClient(nil)
+}
+
+func newRichMenuAliasSyncCmdWithClient(client *api.Client) *cobra.Command {
+	var file string
+	var prune, planOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Make rich menu aliases match a file",
+		Long: `Create and update rich menu aliases so they match a YAML or JSON file
+that maps each alias ID to the ID or name of its menu. A name must belong
+to exactly one rich menu on the account.
+
+Aliases that are missing are created and aliases that point at another
+menu are updated. Aliases on the account that are not in the file are left
+alone, unless --prune is given, which deletes them after asking on a
+terminal (--yes skips the question).
+
+The plan of changes is printed first; --plan-only, or --dry-run, stops
+there. If a change fails, sync stops; run it again to finish. To manage
+menus and the default menu as well, use 'line richmenu apply'.`,
+		Example: `  # aliases.yaml
+  main-tab: main                 # a menu name
+  promo-tab: promo
+  coupon-tab: richmenu-88c05ef6  # or a menu ID
+
+  line richmenu alias sync --file aliases.yaml --plan-only
+  line richmenu alias sync --file aliases.yaml --prune --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return fmt.Errorf("--file is required")
+			}
+			desired, err := readAliasSyncFile(file)
+			if err != nil {
+				return withExitCode(ExitUsage, err)
+			}
+
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			var menus []api.RichMenu
+			var aliases []api.RichMenuAlias
+			err = runConcurrently(cmd.Context(),
+				func(ctx context.Context) error {
+					var err error
+					menus, err = c.GetRichMenuList(ctx)
+					if err != nil {
+						return fmt.Errorf("failed to list rich menus: %w", err)
+					}
+					return nil
+				},
+				func(ctx context.Context) error {
+					var err error
+					aliases, err = c.ListRichMenuAliases(ctx)
+					if err != nil {
+						return fmt.Errorf("failed to list rich menu aliases: %w", err)
+					}
+					return nil
+				},
+			)
+			if err != nil {
+				return err
+			}
+			plan, err := planAliasSync(desired, menus, aliases, prune)
+			if err != nil {
+				return err
+			}
+
+			if planOnly || flags.DryRun || len(plan.changes) == 0 {
+				return printAliasSyncPlan(cmd, plan, false)
+			}
+			if flags.Output != "json" {
+				_ = printAliasSyncPlan(cmd, plan, false)
+			}
+			deletes := 0
+			for _, ch := range plan.changes {
+				if ch.Action == "delete" {
+					deletes++
+				}
+			}
+			if deletes > 0 {
+				if err := confirmDestructive(cmd, "sync", fmt.Sprintf("Delete %d aliases?", deletes)); err != nil {
+					return err
+				}
+			}
+
+			syncErr := applyAliasSyncPlan(cmd, c, plan)
+			if flags.Output == "json" {
+				if err := printAliasSyncPlan(cmd, plan, syncErr == nil); err != nil {
+					return err
+				}
+			}
+			if syncErr != nil {
+				return fmt.Errorf("sync stopped: %w; run it again to finish", syncErr)
+			}
+			if flags.Output != "json" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Sync complete: %d changes\n", len(plan.changes))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "YAML or JSON file mapping alias IDs to menu IDs or names, or - for stdin (required)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete aliases that are not in the file")
+	cmd.Flags().BoolVar(&planOnly, "plan-only", false, "Print the plan without changing anything")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// printAliasSyncPlan prints the changes of a plan and the aliases it leaves
+// alone.
+func printAliasSyncPlan(cmd *cobra.Command, plan aliasSyncPlan, applied bool) error {
+	if flags.Output == "json" {
+		changes, unmanaged := plan.changes, plan.unmanaged
+		if changes == nil {
+			changes = []richMenuChange{}
+		}
+		if unmanaged == nil {
+			unmanaged = []string{}
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"changes": changes, "unmanaged": unmanaged, "applied": applied})
+	}
+
+	out := cmd.OutOrStdout()
+	if len(plan.changes) == 0 {
+		_, _ = fmt.Fprintln(out, "No changes: aliases match the file")
+	}
+	counts := map[string]int{}
+	for _, ch := range plan.changes {
+		counts[ch.Action]++
+		symbol := map[string]string{"create": "+", "update": "~", "delete": "-"}[ch.Action]
+		line := ch.String()
+		if ch.TargetID != "" && ch.TargetID != ch.Target {
+			line += " (" + ch.TargetID + ")"
+		}
+		if ch.Reason != "" {
+			line += ": " + ch.Reason
+		}
+		_, _ = fmt.Fprintf(out, "  %s %s\n", symbol, line)
+	}
+	if len(plan.changes) > 0 {
+		_, _ = fmt.Fprintf(out, "Plan: %d to create, %d to update, %d to delete\n", counts["create"], counts["update"], counts["delete"])
+	}
+	if len(plan.unmanaged) > 0 {
+		_, _ = fmt.Fprintf(out, "%d aliases not in the file are left as they are (--prune deletes them)\n", len(plan.unmanaged))
+	}
+	return nil
+}
+
+// applyAliasSyncPlan makes the changes of a plan, deleting last.
+func applyAliasSyncPlan(cmd *cobra.Command, c *api.Client, plan aliasSyncPlan) error {
+	ctx := cmd.Context()
+	for _, deleting := range []bool{false, true} {
+		for _, ch := range plan.changes {
+			if (ch.Action == "delete") != deleting {
+				continue
+			}
+			var err error
+			switch ch.Action {
+			case "create":
+				err = c.CreateRichMenuAlias(ctx, ch.Name, ch.TargetID)
+			case "update":
+				err = c.UpdateRichMenuAlias(ctx, ch.Name, ch.TargetID)
+			case "delete":
+				err = c.DeleteRichMenuAlias(ctx, ch.Name)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to %s: %w", ch, err)
+			}
+			if flags.Output != "json" {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Done: %s\n", ch)
+			}
+		}
+	}
+	return nil
+}
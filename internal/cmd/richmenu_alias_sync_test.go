@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestPlanAliasSync(t *testing.T) {
+	menus := []api.RichMenu{
+		{RichMenuID: "rm-main", Name: "main"},
+		{RichMenuID: "rm-promo", Name: "promo"},
+		{RichMenuID: "rm-dup-1", Name: "dup"},
+		{RichMenuID: "rm-dup-2", Name: "dup"},
+	}
+	aliases := []api.RichMenuAlias{
+		{RichMenuAliasID: "main-tab", RichMenuID: "rm-main"},
+		{RichMenuAliasID: "promo-tab", RichMenuID: "rm-main"},
+		{RichMenuAliasID: "legacy", RichMenuID: "rm-dup-1"},
+	}
+	desired := map[string]string{"main-tab": "main", "promo-tab": "promo", "dup-tab": "rm-dup-2"}
+
+	plan, err := planAliasSync(desired, menus, aliases, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ch := range plan.changes {
+		got = append(got, ch.String()+" "+ch.TargetID)
+	}
+	want := []string{
+		`create alias "dup-tab" -> "rm-dup-2" rm-dup-2`,
+		`update alias "promo-tab" -> "promo" rm-promo`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected plan:\n%s", strings.Join(got, "\n"))
+	}
+	if len(plan.unmanaged) != 1 || plan.unmanaged[0] != "legacy" {
+		t.Errorf("unmanaged = %v, want [legacy]", plan.unmanaged)
+	}
+
+	plan, err = planAliasSync(desired, menus, aliases, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := plan.changes[len(plan.changes)-1]; last.String() != `delete alias "legacy"` || len(plan.unmanaged) != 0 {
+		t.Errorf("expected legacy to be pruned, got %v", plan.changes)
+	}
+
+	for target, wantErr := range map[string]string{"dup": "2 rich menus are named", "gone": "no rich menu has the ID or name"} {
+		_, err := planAliasSync(map[string]string{"x": target}, menus, aliases, false)
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("target %q: expected %q, got %v", target, wantErr, err)
+		}
+	}
+}
+
+func TestReadAliasSyncFile(t *testing.T) {
+	dir := t.TempDir()
+	for content, wantErr := range map[string]string{
+		"main-tab: main\n":         "",
+		`{"main-tab": "rm-1"}`:     "",
+		"- main\n":                 "expected a mapping",
+		"":                         "no aliases",
+		"main-tab:\n":              `alias "main-tab" needs a menu ID or name`,
+		"main-tab: {name: main}\n": "expected a mapping",
+	} {
+		path := filepath.Join(dir, "aliases.yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := readAliasSyncFile(path)
+		if wantErr == "" && err != nil || wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)) {
+			t.Errorf("%q: got %v, want %q", content, err, wantErr)
+		}
+	}
+}
+
+func TestRichMenuAliasSyncCmd(t *testing.T) {
+	oldOutput, oldYes := flags.Output, flags.Yes
+	defer func() { flags.Output, flags.Yes = oldOutput, oldYes }()
+	flags.Output, flags.Yes = "text", true
+
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/richmenu/list":
+			_, _ = w.Write([]byte(`{"richmenus":[{"richMenuId":"rm-main","name":"main"},{"richMenuId":"rm-promo","name":"promo"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/richmenu/alias/list":
+			_, _ = w.Write([]byte(`{"aliases":[{"richMenuAliasId":"promo-tab","richMenuId":"rm-main"},{"richMenuAliasId":"legacy","richMenuId":"rm-main"}]}`))
+		default:
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	path := filepath.Join(t.TempDir(), "aliases.yaml")
+	if err := os.WriteFile(path, []byte("main-tab: main\npromo-tab: rm-promo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newRichMenuAliasSyncCmdWithClient(client)
+		cmd.SetArgs(append([]string{"--file", path}, args...))
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(new(bytes.Buffer))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out.String()
+	}
+
+	out := run("--plan-only")
+	if len(calls) != 0 {
+		t.Errorf("--plan-only made changes: %v", calls)
+	}
+	for _, want := range []string{
+		`+ create alias "main-tab" -> "main" (rm-main)`,
+		`~ update alias "promo-tab" -> "rm-promo": points at rm-main`,
+		"1 aliases not in the file are left as they are",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	out = run("--prune")
+	want := []string{
+		"POST /v2/bot/richmenu/alias",
+		"POST /v2/bot/richmenu/alias/promo-tab",
+		"DELETE /v2/bot/richmenu/alias/legacy",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+	if !strings.Contains(out, "Plan: 1 to create, 1 to update, 1 to delete") || !strings.Contains(out, "Sync complete: 3 changes") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,563 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// richMenuStateFile is the desired rich menu setup of an account, as read
+// by "richmenu apply".
+type richMenuStateFile struct {
+	Menus []richMenuStateMenu `yaml:"menus"`
+	// Aliases maps alias IDs to the names of the menus they point at
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+	// Default is the name of the default menu; empty means none
+	Default string `yaml:"default,omitempty"`
+}
+
+// richMenuStateMenu is one menu of a state file. Name identifies the menu
+// on the account; File and Image are relative to the state file.
+type richMenuStateMenu struct {
+	Name  string `yaml:"name"`
+	File  string `yaml:"file"`
+	Image string `yaml:"image"`
+}
+
+// desiredRichMenu is a menu of a state file with its definition and image
+// loaded.
+type desiredRichMenu struct {
+	name        string
+	menu        *api.CreateRichMenuRequest
+	image       []byte
+	contentType string
+	imageSHA    string
+}
+
+// richMenuApplyState is the desired state with every file loaded.
+type richMenuApplyState struct {
+	menus       []desiredRichMenu
+	aliases     map[string]string
+	defaultMenu string
+}
+
+// readRichMenuStateFile reads and checks a state file and the menu
+// definitions and images it names.
+func readRichMenuStateFile(path string) (*richMenuApplyState, error) {
+	data, err := readFileOrStdin(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	var file richMenuStateFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid state file: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	resolve := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+
+	state := &richMenuApplyState{aliases: file.Aliases, defaultMenu: file.Default}
+	names := map[string]bool{}
+	for i, m := range file.Menus {
+		if m.Name == "" || m.File == "" || m.Image == "" {
+			return nil, fmt.Errorf("invalid state file: menu %d needs name, file, and image", i+1)
+		}
+		if names[m.Name] {
+			return nil, fmt.Errorf("invalid state file: menu %q is listed twice", m.Name)
+		}
+		names[m.Name] = true
+
+		menu, err := readRichMenuDefinitionFromFile(resolve(m.File))
+		if err != nil {
+			return nil, fmt.Errorf("menu %q: failed to read %s: %w", m.Name, m.File, err)
+		}
+		menu.Name = m.Name
+		image, contentType, err := readRichMenuImage(resolve(m.Image))
+		if err != nil {
+			return nil, fmt.Errorf("menu %q: %w", m.Name, err)
+		}
+		if err := checkRichMenuImageSize(image, contentType, menu.Size); err != nil {
+			return nil, fmt.Errorf("menu %q: %w", m.Name, err)
+		}
+		sum := sha256.Sum256(image)
+		state.menus = append(state.menus, desiredRichMenu{
+			name:        m.Name,
+			menu:        menu,
+			image:       image,
+			contentType: contentType,
+			imageSHA:    hex.EncodeToString(sum[:]),
+		})
+	}
+	for alias, target := range file.Aliases {
+		if !names[target] {
+			return nil, fmt.Errorf("invalid state file: alias %q points at %q, which is not a menu in the file", alias, target)
+		}
+	}
+	if file.Default != "" && !names[file.Default] {
+		return nil, fmt.Errorf("invalid state file: default %q is not a menu in the file", file.Default)
+	}
+	return state, nil
+}
+
+// liveRichMenus is the rich menu setup of the account.
+type liveRichMenus struct {
+	menus   []api.RichMenu
+	aliases []api.RichMenuAlias
+	// imageSHA holds the image digest of the menus whose definition matches
+	// the state file; menus without an image map to ""
+	imageSHA  map[string]string
+	defaultID string
+}
+
+// richMenuChange is one step of an apply plan.
+type richMenuChange struct {
+	// Action is create, replace, delete, update (alias), set, or cancel
+	// (default)
+	Action string `json:"action"`
+	// Kind is menu, alias, or default
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// ID is the live menu replaced or deleted
+	ID string `json:"id,omitempty"`
+	// Target is the menu an alias or the default points at
+	Target string `json:"target,omitempty"`
+	// TargetID is the ID of the Target menu, for "richmenu alias sync"
+	TargetID string `json:"targetId,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// String describes the change, such as `replace menu "main" (richmenu-1)`.
+func (ch richMenuChange) String() string {
+	switch ch.Kind {
+	case "menu":
+		if ch.ID != "" {
+			return fmt.Sprintf("%s menu %q (%s)", ch.Action, ch.Name, ch.ID)
+		}
+		return fmt.Sprintf("%s menu %q", ch.Action, ch.Name)
+	case "alias":
+		if ch.Target != "" {
+			return fmt.Sprintf("%s alias %q -> %q", ch.Action, ch.Name, ch.Target)
+		}
+		return fmt.Sprintf("%s alias %q", ch.Action, ch.Name)
+	}
+	if ch.Action == "cancel" {
+		return fmt.Sprintf("cancel default (%s)", ch.ID)
+	}
+	return fmt.Sprintf("set default -> %q", ch.Target)
+}
+
+// richMenuPlan is the set of changes that converges an account on a state
+// file.
+type richMenuPlan struct {
+	changes []richMenuChange
+	// kept maps the names of menus left as they are to their IDs
+	kept map[string]string
+}
+
+// planRichMenuApply compares the desired and live setups. Menus are matched
+// by name; LINE cannot edit a rich menu, so a menu whose definition or
+// image differs is replaced by a new one.
+func planRichMenuApply(desired *richMenuApplyState, live *liveRichMenus) richMenuPlan {
+	plan := richMenuPlan{kept: map[string]string{}}
+	byName := map[string][]api.RichMenu{}
+	for _, m := range live.menus {
+		byName[m.Name] = append(byName[m.Name], m)
+	}
+
+	wanted := map[string]bool{}
+	var deletes []richMenuChange
+	for _, d := range desired.menus {
+		wanted[d.name] = true
+		candidates := byName[d.name]
+		keep := ""
+		for _, c := range candidates {
+			if keep == "" && sameRichMenuDefinition(c, d.menu) && live.imageSHA[c.RichMenuID] == d.imageSHA {
+				keep = c.RichMenuID
+			}
+		}
+		switch {
+		case keep != "":
+			plan.kept[d.name] = keep
+		case len(candidates) > 0:
+			reason := "image changed"
+			if !sameRichMenuDefinition(candidates[0], d.menu) {
+				reason = "definition changed"
+			} else if live.imageSHA[candidates[0].RichMenuID] == "" {
+				reason = "image missing"
+			}
+			plan.changes = append(plan.changes, richMenuChange{Action: "replace", Kind: "menu", Name: d.name, ID: candidates[0].RichMenuID, Reason: reason})
+		default:
+			plan.changes = append(plan.changes, richMenuChange{Action: "create", Kind: "menu", Name: d.name})
+		}
+		for i, c := range candidates {
+			if c.RichMenuID == keep || (keep == "" && i == 0) {
+				continue
+			}
+			deletes = append(deletes, richMenuChange{Action: "delete", Kind: "menu", Name: c.Name, ID: c.RichMenuID, Reason: "duplicate name"})
+		}
+	}
+	for _, m := range live.menus {
+		if !wanted[m.Name] {
+			deletes = append(deletes, richMenuChange{Action: "delete", Kind: "menu", Name: m.Name, ID: m.RichMenuID, Reason: "not in state file"})
+		}
+	}
+
+	liveAliases := map[string]string{}
+	for _, a := range live.aliases {
+		liveAliases[a.RichMenuAliasID] = a.RichMenuID
+	}
+	aliasIDs := make([]string, 0, len(desired.aliases))
+	for id := range desired.aliases {
+		aliasIDs = append(aliasIDs, id)
+	}
+	sort.Strings(aliasIDs)
+	for _, id := range aliasIDs {
+		target := desired.aliases[id]
+		current, exists := liveAliases[id]
+		switch {
+		case !exists:
+			plan.changes = append(plan.changes, richMenuChange{Action: "create", Kind: "alias", Name: id, Target: target})
+		case current != plan.kept[target]:
+			plan.changes = append(plan.changes, richMenuChange{Action: "update", Kind: "alias", Name: id, ID: current, Target: target})
+		}
+	}
+
+	switch {
+	case desired.defaultMenu == "" && live.defaultID != "":
+		plan.changes = append(plan.changes, richMenuChange{Action: "cancel", Kind: "default", ID: live.defaultID})
+	case desired.defaultMenu != "" && live.defaultID != plan.kept[desired.defaultMenu]:
+		plan.changes = append(plan.changes, richMenuChange{Action: "set", Kind: "default", ID: live.defaultID, Target: desired.defaultMenu})
+	}
+
+	for _, a := range live.aliases {
+		if _, ok := desired.aliases[a.RichMenuAliasID]; !ok {
+			plan.changes = append(plan.changes, richMenuChange{Action: "delete", Kind: "alias", Name: a.RichMenuAliasID, ID: a.RichMenuID, Reason: "not in state file"})
+		}
+	}
+	plan.changes = append(plan.changes, deletes...)
+	return plan
+}
+
+// sameRichMenuDefinition reports whether a live menu has the definition of
+// a desired one.
+func sameRichMenuDefinition(live api.RichMenu, desired *api.CreateRichMenuRequest) bool {
+	return richMenuFingerprint(live.Size, live.Selected, live.ChatBarText, live.Areas) ==
+		richMenuFingerprint(desired.Size, desired.Selected, desired.ChatBarText, desired.Areas)
+}
+
+// richMenuFingerprint returns a canonical encoding of a menu definition,
+// with the keys of area actions sorted and null fields dropped.
+func richMenuFingerprint(size api.RichMenuSize, selected bool, chatBarText string, areas []api.RichMenuArea) string {
+	type area struct {
+		Bounds api.RichMenuBounds `json:"bounds"`
+		Action map[string]any     `json:"action"`
+	}
+	canonical := make([]area, len(areas))
+	for i, a := range areas {
+		var action map[string]any
+		_ = json.Unmarshal(a.Action, &action)
+		for k, v := range action {
+			if v == nil {
+				delete(action, k)
+			}
+		}
+		canonical[i] = area{Bounds: a.Bounds, Action: action}
+	}
+	data, _ := json.Marshal(struct {
+		Size        api.RichMenuSize `json:"size"`
+		Selected    bool             `json:"selected"`
+		ChatBarText string           `json:"chatBarText"`
+		Areas       []area           `json:"areas"`
+	}{size, selected, chatBarText, canonical})
+	return string(data)
+}
+
+// fetchLiveRichMenus reads the account's menus, aliases, and default, and
+// the images of the menus that may be kept.
+func fetchLiveRichMenus(ctx context.Context, c *api.Client, desired *richMenuApplyState) (*liveRichMenus, error) {
+	live := &liveRichMenus{imageSHA: map[string]string{}}
+	err := runConcurrently(ctx,
+		func(ctx context.Context) error {
+			var err error
+			live.menus, err = c.GetRichMenuList(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list rich menus: %w", err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			var err error
+			live.aliases, err = c.ListRichMenuAliases(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list rich menu aliases: %w", err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			id, err := c.GetDefaultRichMenuID(ctx)
+			if err != nil {
+				// 404 means no default menu is set
+				if apiErr := api.AsAPIError(err); apiErr != nil && apiErr.IsNotFound() {
+					return nil
+				}
+				return fmt.Errorf("failed to get default rich menu: %w", err)
+			}
+			live.defaultID = id
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := map[string]*api.CreateRichMenuRequest{}
+	for _, d := range desired.menus {
+		definitions[d.name] = d.menu
+	}
+	for _, m := range live.menus {
+		if d := definitions[m.Name]; d == nil || !sameRichMenuDefinition(m, d) {
+			continue
+		}
+		image, _, err := c.DownloadRichMenuImage(ctx, m.RichMenuID)
+		if err != nil {
+			if apiErr := api.AsAPIError(err); apiErr != nil && apiErr.IsNotFound() {
+				live.imageSHA[m.RichMenuID] = ""
+				continue
+			}
+			return nil, fmt.Errorf("failed to download image of rich menu %s: %w", m.RichMenuID, err)
+		}
+		sum := sha256.Sum256(image)
+		live.imageSHA[m.RichMenuID] = hex.EncodeToString(sum[:])
+	}
+	return live, nil
+}
+
+func newRichMenuApplyCmd() *cobra.Command {
+	return newRichMenuApplyCmdWithClient(nil)
+}
+
+func newRichMenuApplyCmdWithClient(client *api.Client) *cobra.Command {
+	var stateFile string
+	var planOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Converge rich menus on a declarative state file",
+		Long: `Make the account's rich menus, aliases, and default menu match a YAML
+state file, like Terraform: the live account is compared with the file, a
+plan of the changes is printed, and the changes are applied.
+
+Menus are identified by name. LINE cannot edit a rich menu, so a menu whose
+definition or image has changed is replaced: a new menu is created, aliases
+and the default are pointed at it, and the old menu is deleted. Menus and
+aliases that are not in the file are deleted, and the default menu is
+cancelled if the file names none. Images are compared byte for byte with the
+ones on LINE.
+
+The plan is confirmed on a terminal before anything changes (--yes skips
+the question). --plan-only, or --dry-run, prints the plan and stops. If a
+change fails, apply stops; run it again to finish converging. Use --account
+to apply the same file to several channels.`,
+		Example: `  # state.yaml
+  menus:
+    - name: main
+      file: menus/main.json
+      image: menus/main.png
+    - name: promo
+      file: menus/promo.json
+      image: menus/promo.png
+  aliases:
+    main-tab: main
+    promo-tab: promo
+  default: main
+
+  # Show what would change
+  line richmenu apply --file state.yaml --plan-only
+
+  # Apply to two channels
+  line richmenu apply --file state.yaml --account shop-jp --yes
+  line richmenu apply --file state.yaml --account shop-tw --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if stateFile == "" {
+				return fmt.Errorf("--file is required")
+			}
+			desired, err := readRichMenuStateFile(stateFile)
+			if err != nil {
+				return withExitCode(ExitUsage, err)
+			}
+
+			c := client
+			if c == nil {
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			live, err := fetchLiveRichMenus(cmd.Context(), c, desired)
+			if err != nil {
+				return err
+			}
+			plan := planRichMenuApply(desired, live)
+
+			if planOnly || flags.DryRun || len(plan.changes) == 0 {
+				return printRichMenuPlan(cmd, plan, false)
+			}
+			if flags.Output != "json" {
+				_ = printRichMenuPlan(cmd, plan, false)
+			}
+			if err := confirmDestructive(cmd, "apply", fmt.Sprintf("Apply %d changes?", len(plan.changes))); err != nil {
+				return err
+			}
+
+			applyErr := applyRichMenuPlan(cmd, c, desired, plan)
+			if flags.Output == "json" {
+				if err := printRichMenuPlan(cmd, plan, applyErr == nil); err != nil {
+					return err
+				}
+			}
+			if applyErr != nil {
+				return fmt.Errorf("apply stopped: %w; run it again to finish converging", applyErr)
+			}
+			if flags.Output != "json" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Apply complete: %d changes\n", len(plan.changes))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&stateFile, "file", "f", "", "YAML state file describing the desired menus, or - for stdin (required)")
+	cmd.Flags().BoolVar(&planOnly, "plan-only", false, "Print the plan without changing anything")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// printRichMenuPlan prints the changes of a plan.
+func printRichMenuPlan(cmd *cobra.Command, plan richMenuPlan, applied bool) error {
+	if flags.Output == "json" {
+		changes := plan.changes
+		if changes == nil {
+			changes = []richMenuChange{}
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"changes": changes, "applied": applied})
+	}
+
+	out := cmd.OutOrStdout()
+	if len(plan.changes) == 0 {
+		_, _ = fmt.Fprintln(out, "No changes: rich menus match the state file")
+		return nil
+	}
+	counts := map[string]int{}
+	for _, ch := range plan.changes {
+		counts[ch.Action]++
+		symbol := "~"
+		switch ch.Action {
+		case "create", "set":
+			symbol = "+"
+		case "delete", "cancel":
+			symbol = "-"
+		}
+		line := ch.String()
+		if ch.Reason != "" {
+			line += ": " + ch.Reason
+		}
+		_, _ = fmt.Fprintf(out, "  %s %s\n", symbol, line)
+	}
+	_, _ = fmt.Fprintf(out, "Plan: %d to create, %d to replace, %d to update, %d to delete\n",
+		counts["create"], counts["replace"], counts["update"]+counts["set"], counts["delete"]+counts["cancel"])
+	return nil
+}
+
+// applyRichMenuPlan makes the changes of a plan: new menus first, then
+// aliases and the default, and deletions last, so nothing points at a
+// deleted menu along the way.
+func applyRichMenuPlan(cmd *cobra.Command, c *api.Client, desired *richMenuApplyState, plan richMenuPlan) error {
+	ctx := cmd.Context()
+	progress := func(format string, args ...any) {
+		if flags.Output != "json" {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), format+"\n", args...)
+		}
+	}
+
+	ids := map[string]string{}
+	for name, id := range plan.kept {
+		ids[name] = id
+	}
+	menus := map[string]desiredRichMenu{}
+	for _, d := range desired.menus {
+		menus[d.name] = d
+	}
+
+	for _, ch := range plan.changes {
+		if ch.Kind != "menu" || (ch.Action != "create" && ch.Action != "replace") {
+			continue
+		}
+		d := menus[ch.Name]
+		id, err := c.CreateRichMenu(ctx, *d.menu)
+		if err != nil {
+			return fmt.Errorf("failed to create menu %q: %w", ch.Name, err)
+		}
+		if err := c.UploadRichMenuImage(ctx, id, d.contentType, d.image); err != nil {
+			_ = c.DeleteRichMenu(ctx, id)
+			return fmt.Errorf("failed to upload image of menu %q: %w", ch.Name, err)
+		}
+		ids[ch.Name] = id
+		progress("Done: create menu %q as %s", ch.Name, id)
+	}
+
+	for _, ch := range plan.changes {
+		var err error
+		switch {
+		case ch.Kind == "alias" && ch.Action == "create":
+			err = c.CreateRichMenuAlias(ctx, ch.Name, ids[ch.Target])
+		case ch.Kind == "alias" && ch.Action == "update":
+			err = c.UpdateRichMenuAlias(ctx, ch.Name, ids[ch.Target])
+		case ch.Kind == "default" && ch.Action == "set":
+			err = c.SetDefaultRichMenu(ctx, ids[ch.Target])
+		case ch.Kind == "default" && ch.Action == "cancel":
+			err = c.CancelDefaultRichMenu(ctx)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", ch, err)
+		}
+		progress("Done: %s", ch)
+	}
+
+	for _, ch := range plan.changes {
+		var err error
+		switch {
+		case ch.Kind == "alias" && ch.Action == "delete":
+			err = c.DeleteRichMenuAlias(ctx, ch.Name)
+		case ch.Kind == "menu" && (ch.Action == "delete" || ch.Action == "replace"):
+			err = c.DeleteRichMenu(ctx, ch.ID)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", ch, err)
+		}
+		progress("Done: %s", ch)
+	}
+	return nil
+}
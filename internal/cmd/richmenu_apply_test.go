@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+const applyMenuJSON = `{"size":{"width":2500,"height":843},"name":"ignored","chatBarText":"Menu","areas":[{"bounds":{"x":0,"y":0,"width":2500,"height":843},"action":{"type":"message","text":"hi"}}]}`
+
+// writeApplyFixtures writes a state file with menus "main" and "promo",
+// alias main-tab, and default main, and returns its path and the image.
+func writeApplyFixtures(t *testing.T) (statePath string, image []byte) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"main", "promo"} {
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(applyMenuJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+		writeTestPNG(t, filepath.Join(dir, name+".png"), 2500, 843)
+	}
+	state := `menus:
+  - {name: main, file: main.json, image: main.png}
+  - {name: promo, file: promo.json, image: promo.png}
+aliases:
+  main-tab: main
+default: main
+`
+	statePath = filepath.Join(dir, "state.yaml")
+	if err := os.WriteFile(statePath, []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+	image, err := os.ReadFile(filepath.Join(dir, "main.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return statePath, image
+}
+
+// applyTestServer serves a live account with menu rm-main (matching the
+// fixtures), rm-old, alias legacy, and default rm-old.
+func applyTestServer(t *testing.T, image []byte, calls *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			*calls = append(*calls, r.Method+" "+r.URL.Path)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/richmenu/list":
+			_, _ = w.Write([]byte(`{"richmenus":[
+				{"richMenuId":"rm-main","name":"main","size":{"width":2500,"height":843},"chatBarText":"Menu","selected":false,
+				 "areas":[{"bounds":{"x":0,"y":0,"width":2500,"height":843},"action":{"text":"hi","type":"message","label":null}}]},
+				{"richMenuId":"rm-old","name":"old","size":{"width":2500,"height":843},"chatBarText":"Old","areas":[]}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/richmenu/alias/list":
+			_, _ = w.Write([]byte(`{"aliases":[{"richMenuAliasId":"legacy","richMenuId":"rm-old"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/user/all/richmenu":
+			_, _ = w.Write([]byte(`{"richMenuId":"rm-old"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/richmenu/rm-main/content":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(image)
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/bot/richmenu":
+			_, _ = w.Write([]byte(`{"richMenuId":"rm-promo"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRichMenuApplyCmd_PlanOnly(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	statePath, image := writeApplyFixtures(t)
+	var calls []string
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(applyTestServer(t, image, &calls).URL)
+
+	cmd := newRichMenuApplyCmdWithClient(client)
+	cmd.SetArgs([]string{"--file", statePath, "--plan-only"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("plan-only made changes: %v", calls)
+	}
+
+	var result struct {
+		Changes []richMenuChange `json:"changes"`
+		Applied bool             `json:"applied"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	var got []string
+	for _, ch := range result.Changes {
+		got = append(got, ch.String())
+	}
+	want := []string{
+		`create menu "promo"`,
+		`create alias "main-tab" -> "main"`,
+		`set default -> "main"`,
+		`delete alias "legacy"`,
+		`delete menu "old" (rm-old)`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") || result.Applied {
+		t.Errorf("unexpected plan:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestRichMenuApplyCmd_Apply(t *testing.T) {
+	oldOutput, oldYes := flags.Output, flags.Yes
+	defer func() { flags.Output, flags.Yes = oldOutput, oldYes }()
+	flags.Output = "text"
+	flags.Yes = true
+
+	statePath, image := writeApplyFixtures(t)
+	var calls []string
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(applyTestServer(t, image, &calls).URL)
+
+	cmd := newRichMenuApplyCmdWithClient(client)
+	cmd.SetArgs([]string{"--file", statePath})
+	var out, progress bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&progress)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"POST /v2/bot/richmenu",
+		"POST /v2/bot/richmenu/rm-promo/content",
+		"POST /v2/bot/richmenu/alias",
+		"POST /v2/bot/user/all/richmenu/rm-main",
+		"DELETE /v2/bot/richmenu/alias/legacy",
+		"DELETE /v2/bot/richmenu/rm-old",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+	if !strings.Contains(out.String(), "Plan: 2 to create, 0 to replace, 1 to update, 2 to delete") || !strings.Contains(out.String(), "Apply complete: 5 changes") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestPlanRichMenuApply_Replace(t *testing.T) {
+	menu, err := readRichMenuDefinitionFromFile(writeApplyMenu(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	menu.Name = "main"
+	desired := &richMenuApplyState{
+		menus:       []desiredRichMenu{{name: "main", menu: menu, imageSHA: "new"}},
+		aliases:     map[string]string{"main-tab": "main"},
+		defaultMenu: "main",
+	}
+	live := &liveRichMenus{
+		menus: []api.RichMenu{
+			{RichMenuID: "rm-1", Name: "main", Size: menu.Size, ChatBarText: menu.ChatBarText, Areas: menu.Areas},
+			{RichMenuID: "rm-2", Name: "main", Size: menu.Size, ChatBarText: "Other"},
+		},
+		aliases:   []api.RichMenuAlias{{RichMenuAliasID: "main-tab", RichMenuID: "rm-1"}},
+		imageSHA:  map[string]string{"rm-1": "old"},
+		defaultID: "rm-1",
+	}
+
+	var got []string
+	for _, ch := range planRichMenuApply(desired, live).changes {
+		got = append(got, ch.String()+": "+ch.Reason)
+	}
+	want := []string{
+		`replace menu "main" (rm-1): image changed`,
+		`update alias "main-tab" -> "main": `,
+		`set default -> "main": `,
+		`delete menu "main" (rm-2): duplicate name`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected plan:\n%s", strings.Join(got, "\n"))
+	}
+
+	live.imageSHA["rm-1"] = "new"
+	live.menus = live.menus[:1]
+	if plan := planRichMenuApply(desired, live); len(plan.changes) != 0 || plan.kept["main"] != "rm-1" {
+		t.Errorf("expected no changes, got %v", plan.changes)
+	}
+}
+
+func writeApplyMenu(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "menu.json")
+	if err := os.WriteFile(path, []byte(applyMenuJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+	"github.com/spf13/cobra"
+)
+
+// defaultBulkConcurrency is the default --concurrency of the per-user
+// fallback of the bulk link and unlink commands.
+const defaultBulkConcurrency = 10
+
+// bulkUserRetries is how many more times the per-user fallback tries a user
+// whose request still fails with 429 after the client's own retries.
+const bulkUserRetries = 3
+
+// bulkRetryDelay is the pause before the first of those retries. It doubles
+// with each retry. Tests shorten it.
+var bulkRetryDelay = 5 * time.Second
+
+// bulkPlanUnavailable reports whether err is the 403 the bulk link and
+// unlink endpoints return on plans that do not include them.
+func bulkPlanUnavailable(err error) bool {
+	var apiErr *api.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
+}
+
+// addBulkFallbackFlags adds the flags of the per-user fallback.
+func addBulkFallbackFlags(cmd *cobra.Command, concurrency *int, report *string) {
+	cmd.Flags().IntVar(concurrency, "concurrency", defaultBulkConcurrency, "Per-user requests in flight if the bulk endpoint is not available on this plan")
+	cmd.Flags().StringVar(report, "report", "", "Per-user results file (CSV) written if the bulk endpoint is not available (default richmenu-bulk-<op>-<time>.csv)")
+}
+
+// perUserFallback links or unlinks users one request at a time, for plans
+// without the bulk endpoints. Every user's outcome is appended to a CSV
+// report as it completes, so the report stays complete even for very large
+// users files and when the command is interrupted.
+type perUserFallback struct {
+	verb        string // "linked" or "unlinked"
+	concurrency int
+	reportPath  string
+	progress    *progressMeter
+
+	mu     sync.Mutex
+	file   *os.File
+	report *csv.Writer
+	done   int
+	failed int
+}
+
+// startPerUserFallback opens the report and announces the fallback on
+// stderr. total is the number of users still to go, for the progress bar.
+func startPerUserFallback(cmd *cobra.Command, op string, concurrency int, reportPath string, total int) (*perUserFallback, error) {
+	if reportPath == "" {
+		reportPath = fmt.Sprintf("richmenu-bulk-%s-%s.csv", op, clockNow().Format("20060102T150405"))
+	}
+	file, err := os.OpenFile(reportPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+	f := &perUserFallback{
+		verb:        op + "ed",
+		concurrency: concurrency,
+		reportPath:  reportPath,
+		progress:    newProgressMeter(cmd, total),
+		file:        file,
+		report:      csv.NewWriter(file),
+	}
+	_ = f.report.Write([]string{"userId", "status", "error"})
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Bulk %s is not available on this plan; falling back to per-user requests (%d at a time)\n", op, concurrency)
+	return f, nil
+}
+
+// run calls call for every user in userIDs with at most concurrency calls
+// in flight. A user whose call fails is recorded in the report and does not
+// stop the others.
+func (f *perUserFallback) run(ctx context.Context, userIDs []string, call func(ctx context.Context, userID string) error) {
+	sem := make(chan struct{}, f.concurrency)
+	var wg sync.WaitGroup
+	for _, userID := range userIDs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			err := callRetrying429(ctx, func(ctx context.Context) error { return call(ctx, userID) })
+			if ctx.Err() != nil && err != nil {
+				// Interrupted: the user is neither done nor failed
+				return
+			}
+			f.record(userID, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// callRetrying429 calls call, repeating it up to bulkUserRetries times
+// while it fails with 429.
+func callRetrying429(ctx context.Context, call func(ctx context.Context) error) error {
+	return callRetrying429N(ctx, bulkUserRetries, call)
+}
+
+// callRetrying429N is callRetrying429 with the number of retries given.
+func callRetrying429N(ctx context.Context, retries int, call func(ctx context.Context) error) error {
+	delay := bulkRetryDelay
+	for attempt := 0; ; attempt++ {
+		err := call(ctx)
+		var apiErr *api.APIError
+		if err == nil || attempt >= retries || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (f *perUserFallback) record(userID string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	row := []string{userID, f.verb, ""}
+	if err != nil {
+		row = []string{userID, "failed", csvError(err)}
+		f.failed++
+	}
+	_ = f.report.Write(row)
+	f.done++
+	f.progress.add(err != nil)
+}
+
+// finish clears the progress bar and closes the report. It returns the
+// number of users that succeeded.
+func (f *perUserFallback) finish() (int, error) {
+	f.progress.clear()
+	f.report.Flush()
+	err := f.report.Error()
+	if cerr := f.file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return f.done - f.failed, fmt.Errorf("failed to write report: %w", err)
+	}
+	return f.done - f.failed, nil
+}
+
+// csvError formats err for one CSV cell: the status and message of an
+// API error, or the error text on a single line.
+func csvError(err error) string {
+	if apiErr := api.AsAPIError(err); apiErr != nil {
+		return fmt.Sprintf("%d %s", apiErr.StatusCode, apiErr.Message)
+	}
+	return strings.Join(strings.Fields(err.Error()), " ")
+}
+
+// progressMeter draws the progress of a per-user operation on stderr as
+// "[###---] done/total users (n failed)". It draws nothing in JSON mode.
+type progressMeter struct {
+	w         io.Writer
+	total     int
+	mu        sync.Mutex
+	done      int
+	failed    int
+	lastDrawn time.Time
+}
+
+func newProgressMeter(cmd *cobra.Command, total int) *progressMeter {
+	p := &progressMeter{total: total}
+	if flags.Output != "json" {
+		p.w = cmd.ErrOrStderr()
+	}
+	return p
+}
+
+// add counts one more user, redrawing at most every 100ms and at the end.
+func (p *progressMeter) add(failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	if failed {
+		p.failed++
+	}
+	if p.w != nil && (p.done == p.total || time.Since(p.lastDrawn) >= 100*time.Millisecond) {
+		p.lastDrawn = time.Now()
+		_, _ = fmt.Fprintf(p.w, "\r%s %d/%d users (%d failed)", progressBar(p.done, p.total, 30), p.done, p.total, p.failed)
+	}
+}
+
+// clear erases the progress line.
+func (p *progressMeter) clear() {
+	if p.w != nil {
+		_, _ = fmt.Fprint(p.w, "\r\033[K")
+	}
+}
+
+// progressBar draws done out of total as a bar width cells wide.
+func progressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = min(width, done*width/total)
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// countUsers returns how many unique user IDs forEachUserBatch will pass
+// on, for sizing the progress bar.
+func countUsers(usersFile string, override []string, maxUsers int) int {
+	if override != nil {
+		return len(override)
+	}
+	stats, _ := readUsersFile(usersFile, userids.Options{MaxUsers: maxUsers}, usersFileBatchSize, func([]string) error { return nil })
+	return stats.Unique
+}
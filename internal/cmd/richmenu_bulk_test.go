@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// bulkForbiddenServer answers the bulk endpoints with 403 and per-user
+// requests with 200, except 500 for failUser and one 429 for throttleUser.
+func bulkForbiddenServer(t *testing.T, failUser, throttleUser string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+	var throttled atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/bot/richmenu/bulk/") {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "Access to this API is not available for your account"})
+			return
+		}
+		parts := strings.Split(r.URL.Path, "/")
+		if len(parts) < 5 || parts[3] != "user" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		userID := parts[4]
+		mu.Lock()
+		calls = append(calls, r.Method+" "+userID)
+		mu.Unlock()
+		switch {
+		case userID == failUser:
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "boom"})
+		case userID == throttleUser && !throttled.Swap(true):
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "slow down"})
+		default:
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func readReport(t *testing.T, path string) map[string][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open report: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	if len(rows) == 0 || strings.Join(rows[0], ",") != "userId,status,error" {
+		t.Fatalf("unexpected report header: %v", rows)
+	}
+	byUser := map[string][]string{}
+	for _, row := range rows[1:] {
+		byUser[row[0]] = row
+	}
+	return byUser
+}
+
+func TestRichMenuBulkLinkCmd_PerUserFallback(t *testing.T) {
+	oldDelay, oldOutput := bulkRetryDelay, flags.Output
+	defer func() { bulkRetryDelay, flags.Output = oldDelay, oldOutput }()
+	bulkRetryDelay = time.Millisecond
+	flags.Output = "json"
+
+	server, calls := bulkForbiddenServer(t, "U003", "U002")
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	report := filepath.Join(t.TempDir(), "report.csv")
+	cmd := newRichMenuBulkLinkCmdWithClient(client, []string{"U001", "U002", "U003", "U004"})
+	cmd.SetArgs([]string{"--menu", "rm-1", "--concurrency", "2", "--report", report})
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "failed to link 1 users") {
+		t.Fatalf("expected failure for one user, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "falling back to per-user requests (2 at a time)") {
+		t.Errorf("expected fallback notice, got %q", errOut.String())
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if result["userCount"] != float64(3) || result["failedCount"] != float64(1) || result["mode"] != "per-user" || result["report"] != report {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	sort.Strings(*calls)
+	want := "POST U001,POST U002,POST U002,POST U003,POST U004"
+	if got := strings.Join(*calls, ","); got != want {
+		t.Errorf("per-user calls = %s, want %s", got, want)
+	}
+
+	rows := readReport(t, report)
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 report rows, got %v", rows)
+	}
+	for _, u := range []string{"U001", "U002", "U004"} {
+		if rows[u][1] != "linked" {
+			t.Errorf("%s: expected linked, got %v", u, rows[u])
+		}
+	}
+	if rows["U003"][1] != "failed" || !strings.Contains(rows["U003"][2], "boom") {
+		t.Errorf("U003: expected failure, got %v", rows["U003"])
+	}
+}
+
+func TestRichMenuBulkUnlinkCmd_PerUserFallback(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	server, calls := bulkForbiddenServer(t, "", "")
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	report := filepath.Join(t.TempDir(), "report.csv")
+	cmd := newRichMenuBulkUnlinkCmdWithClient(client, []string{"U001", "U002"})
+	cmd.SetArgs([]string{"--report", report})
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Unlinked rich menus from 2 users") || !strings.Contains(out.String(), "Failed: 0 users") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "2/2 users (0 failed)") {
+		t.Errorf("expected progress on stderr, got %q", errOut.String())
+	}
+	sort.Strings(*calls)
+	if got := strings.Join(*calls, ","); got != "DELETE U001,DELETE U002" {
+		t.Errorf("per-user calls = %s", got)
+	}
+	rows := readReport(t, report)
+	if rows["U001"][1] != "unlinked" || rows["U002"][1] != "unlinked" {
+		t.Errorf("unexpected report: %v", rows)
+	}
+}
+
+func TestRichMenuBulkLinkCmd_InvalidConcurrency(t *testing.T) {
+	cmd := newRichMenuBulkLinkCmdWithClient(api.NewClient("test-token", false, false), []string{"U001"})
+	cmd.SetArgs([]string{"--menu", "rm-1", "--concurrency", "0"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--concurrency") {
+		t.Fatalf("expected --concurrency error, got %v", err)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		done, total int
+		want        string
+	}{
+		{0, 4, "[----]"},
+		{2, 4, "[##--]"},
+		{4, 4, "[####]"},
+		{0, 0, "[####]"},
+	}
+	for _, tt := range tests {
+		if got := progressBar(tt.done, tt.total, 4); got != tt.want {
+			t.Errorf("progressBar(%d, %d) = %s, want %s", tt.done, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/salmonumbrella/line-official-cli/internal/messagespec"
+)
+
+// Limits of rich menu definitions checked before calling the API.
+const (
+	maxRichMenuAreas       = 20
+	maxRichMenuChatBarText = 14
+	maxRichMenuName        = 300
+	maxRichMenuAliasID     = 32
+)
+
+// richMenuProblem is a problem checkRichMenu found. Path is a JSON pointer
+// to it, e.g. /areas/3/bounds/width.
+type richMenuProblem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// checkRichMenu checks a rich menu definition against LINE's structural
+// limits without calling the API, reporting every problem rather than
+// stopping at the first.
+func checkRichMenu(data []byte) []richMenuProblem {
+	c := &richMenuChecker{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		c.errorf("", "invalid JSON: %v", err)
+		return c.problems
+	}
+	menu, ok := v.(map[string]any)
+	if !ok {
+		c.errorf("", "expected a rich menu object")
+		return c.problems
+	}
+
+	width, height := c.size(menu["size"])
+	c.text("/name", menu["name"], maxRichMenuName)
+	c.text("/chatBarText", menu["chatBarText"], maxRichMenuChatBarText)
+	if _, ok := menu["selected"].(bool); !ok {
+		c.errorf("/selected", "selected is required and must be true or false")
+	}
+
+	areas, ok := menu["areas"].([]any)
+	if !ok {
+		c.errorf("/areas", "areas is required and must be an array")
+		return c.problems
+	}
+	if len(areas) > maxRichMenuAreas {
+		c.errorf("/areas", "a rich menu has at most %d areas, got %d", maxRichMenuAreas, len(areas))
+	}
+	for i, a := range areas {
+		path := fmt.Sprintf("/areas/%d", i)
+		area, ok := a.(map[string]any)
+		if !ok {
+			c.errorf(path, "expected an area object")
+			continue
+		}
+		c.bounds(path+"/bounds", area["bounds"], width, height)
+		c.action(path+"/action", area["action"])
+	}
+	return c.problems
+}
+
+type richMenuChecker struct {
+	problems []richMenuProblem
+}
+
+func (c *richMenuChecker) errorf(path, format string, args ...any) {
+	c.problems = append(c.problems, richMenuProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// size checks the declared size and returns it, or zeros if it is invalid
+// so that area bounds are not checked against it.
+func (c *richMenuChecker) size(v any) (width, height int) {
+	size, ok := v.(map[string]any)
+	if !ok {
+		c.errorf("/size", "size is required")
+		return 0, 0
+	}
+	width, wok := c.integer("/size/width", size["width"])
+	height, hok := c.integer("/size/height", size["height"])
+	if !wok || !hok {
+		return 0, 0
+	}
+	valid := true
+	if width < 800 || width > 2500 {
+		c.errorf("/size/width", "width must be between 800 and 2500, got %d", width)
+		valid = false
+	}
+	if height < 250 {
+		c.errorf("/size/height", "height must be at least 250, got %d", height)
+		valid = false
+	}
+	if !valid {
+		return 0, 0
+	}
+	return width, height
+}
+
+// bounds checks that an area lies inside a menu of the given size; a zero
+// size skips that check.
+func (c *richMenuChecker) bounds(path string, v any, width, height int) {
+	b, ok := v.(map[string]any)
+	if !ok {
+		c.errorf(path, "bounds is required")
+		return
+	}
+	x, xok := c.integer(path+"/x", b["x"])
+	y, yok := c.integer(path+"/y", b["y"])
+	w, wok := c.integer(path+"/width", b["width"])
+	h, hok := c.integer(path+"/height", b["height"])
+	if xok && x < 0 {
+		c.errorf(path+"/x", "x must not be negative, got %d", x)
+	}
+	if yok && y < 0 {
+		c.errorf(path+"/y", "y must not be negative, got %d", y)
+	}
+	if wok && w <= 0 {
+		c.errorf(path+"/width", "width must be positive, got %d", w)
+	}
+	if hok && h <= 0 {
+		c.errorf(path+"/height", "height must be positive, got %d", h)
+	}
+	if width == 0 || height == 0 {
+		return
+	}
+	if xok && wok && x+w > width {
+		c.errorf(path, "area ends at x=%d, past the menu width %d", x+w, width)
+	}
+	if yok && hok && y+h > height {
+		c.errorf(path, "area ends at y=%d, past the menu height %d", y+h, height)
+	}
+}
+
+// action checks that an area's action has the fields its type requires.
+func (c *richMenuChecker) action(path string, v any) {
+	a, ok := v.(map[string]any)
+	if !ok {
+		c.errorf(path, "action is required")
+		return
+	}
+	c.optionalText(path+"/label", a["label"], messagespec.MaxLabel)
+	switch typ, _ := a["type"].(string); typ {
+	case "postback":
+		c.text(path+"/data", a["data"], messagespec.MaxPostbackData)
+		c.optionalText(path+"/displayText", a["displayText"], messagespec.MaxActionText)
+	case "message":
+		c.text(path+"/text", a["text"], messagespec.MaxActionText)
+	case "uri":
+		c.text(path+"/uri", a["uri"], messagespec.MaxURI)
+	case "datetimepicker":
+		c.text(path+"/data", a["data"], messagespec.MaxPostbackData)
+		switch mode, _ := a["mode"].(string); mode {
+		case "date", "time", "datetime":
+		default:
+			c.errorf(path+"/mode", "mode must be date, time, or datetime, got %q", mode)
+		}
+	case "richmenuswitch":
+		c.text(path+"/richMenuAliasId", a["richMenuAliasId"], maxRichMenuAliasID)
+		c.text(path+"/data", a["data"], messagespec.MaxPostbackData)
+	case "clipboard":
+		c.text(path+"/clipboardText", a["clipboardText"], messagespec.MaxClipboardText)
+	case "":
+		c.errorf(path+"/type", "action type is required")
+	default:
+		c.errorf(path+"/type", "action type %q is not supported in rich menus", typ)
+	}
+}
+
+// integer reports a value that is not a whole number.
+func (c *richMenuChecker) integer(path string, v any) (int, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		c.errorf(path, "%s is required and must be a number", lastPointerToken(path))
+		return 0, false
+	}
+	i, err := strconv.Atoi(n.String())
+	if err != nil {
+		c.errorf(path, "%s must be a whole number, got %s", lastPointerToken(path), n)
+		return 0, false
+	}
+	return i, true
+}
+
+// text reports a missing string or one over limit characters.
+func (c *richMenuChecker) text(path string, v any, limit int) {
+	if s, _ := v.(string); s == "" {
+		c.errorf(path, "%s is required", lastPointerToken(path))
+		return
+	}
+	c.optionalText(path, v, limit)
+}
+
+func (c *richMenuChecker) optionalText(path string, v any, limit int) {
+	s, _ := v.(string)
+	if n := utf8.RuneCountInString(s); n > limit {
+		c.errorf(path, "%s is %d characters, at most %d are allowed", lastPointerToken(path), n, limit)
+	}
+}
+
+// lastPointerToken returns the last reference token of a JSON pointer.
+func lastPointerToken(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const validRichMenu = `{
+  "size": {"width": 2500, "height": 843},
+  "selected": false,
+  "name": "Main",
+  "chatBarText": "Menu",
+  "areas": [
+    {"bounds": {"x": 0, "y": 0, "width": 1250, "height": 843}, "action": {"type": "uri", "uri": "https://example.com"}},
+    {"bounds": {"x": 1250, "y": 0, "width": 1250, "height": 843}, "action": {"type": "richmenuswitch", "richMenuAliasId": "page-2", "data": "page=2"}}
+  ]
+}`
+
+func TestCheckRichMenu(t *testing.T) {
+	if problems := checkRichMenu([]byte(validRichMenu)); len(problems) != 0 {
+		t.Fatalf("expected a valid menu, got %v", problems)
+	}
+
+	areas := make([]string, 21)
+	for i := range areas {
+		areas[i] = `{"bounds": {"x": 0, "y": 0, "width": 100, "height": 100}, "action": {"type": "message", "text": "hi"}}`
+	}
+	tooMany := fmt.Sprintf(`{"size": {"width": 2500, "height": 843}, "selected": true, "name": "Main", "chatBarText": "Menu", "areas": [%s]}`, strings.Join(areas, ","))
+
+	tests := []struct {
+		name string
+		menu string
+		want []richMenuProblem
+	}{
+		{
+			name: "area outside the size",
+			menu: `{"size": {"width": 2500, "height": 843}, "selected": true, "name": "Main", "chatBarText": "Menu",
+				"areas": [{"bounds": {"x": 2000, "y": -1, "width": 600, "height": 843}, "action": {"type": "message", "text": "hi"}}]}`,
+			want: []richMenuProblem{
+				{"/areas/0/bounds/y", "y must not be negative, got -1"},
+				{"/areas/0/bounds", "area ends at x=2600, past the menu width 2500"},
+			},
+		},
+		{
+			name: "too many areas",
+			menu: tooMany,
+			want: []richMenuProblem{{"/areas", "a rich menu has at most 20 areas, got 21"}},
+		},
+		{
+			name: "actions missing fields",
+			menu: `{"size": {"width": 2500, "height": 843}, "selected": true, "name": "Main", "chatBarText": "Menu", "areas": [
+				{"bounds": {"x": 0, "y": 0, "width": 100, "height": 100}, "action": {"type": "postback"}},
+				{"bounds": {"x": 0, "y": 0, "width": 100, "height": 100}, "action": {"type": "datetimepicker", "data": "d"}},
+				{"bounds": {"x": 0, "y": 0, "width": 100, "height": 100}, "action": {"type": "camera"}},
+				{"bounds": {"x": 0, "y": 0, "width": 100, "height": 100}}]}`,
+			want: []richMenuProblem{
+				{"/areas/0/action/data", "data is required"},
+				{"/areas/1/action/mode", `mode must be date, time, or datetime, got ""`},
+				{"/areas/2/action/type", `action type "camera" is not supported in rich menus`},
+				{"/areas/3/action", "action is required"},
+			},
+		},
+		{
+			name: "chat bar text too long and bad size",
+			menu: `{"size": {"width": 3000, "height": 843}, "selected": true, "name": "Main", "chatBarText": "Tap here to open the menu", "areas": []}`,
+			want: []richMenuProblem{
+				{"/size/width", "width must be between 800 and 2500, got 3000"},
+				{"/chatBarText", "chatBarText is 25 characters, at most 14 are allowed"},
+			},
+		},
+		{
+			name: "not an object",
+			menu: `[]`,
+			want: []richMenuProblem{{"", "expected a rich menu object"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkRichMenu([]byte(tt.menu))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("checkRichMenu() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
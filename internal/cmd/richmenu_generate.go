@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+	"github.com/spf13/cobra"
+)
+
+func newRichMenuBatchGenerateCmd() *cobra.Command {
+	var links []string
+	var unlinks []string
+	var outputPath string
+	var maxUsers int
+	var skipInvalid bool
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate batch operations from users files",
+		Long: `Generate a batch operations file from users files: every user in a
+--link file is linked to its rich menu, and every user in an --unlink file
+is unlinked. Users files have one user ID per line.
+
+Each operation holds at most 500 user IDs, so large files become several
+operations. A user may appear in only one file. The resulting file can be
+passed to 'line richmenu batch --operations'.`,
+		Example: `  # Link two segments to their menus and unlink a third
+  line richmenu batch generate --link richmenu-a:vip.txt --link richmenu-b:new.txt \
+    --unlink lapsed.txt --out ops.json
+  line richmenu batch --operations ops.json
+
+  # Pipe the operations straight into the batch
+  line richmenu batch generate --link richmenu-a:vip.txt | line richmenu batch --operations -`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(links) == 0 && len(unlinks) == 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("at least one --link or --unlink is required"))
+			}
+
+			type source struct {
+				flag, menuID, path string
+			}
+			var sources []source
+			for _, v := range links {
+				menuID, path, ok := strings.Cut(v, ":")
+				if !ok || menuID == "" || path == "" {
+					return withExitCode(ExitUsage, fmt.Errorf("invalid --link %q: use RICH_MENU_ID:USERS_FILE", v))
+				}
+				sources = append(sources, source{"--link " + v, menuID, path})
+			}
+			for _, path := range unlinks {
+				sources = append(sources, source{"--unlink " + path, "", path})
+			}
+			stdinUsed := false
+			for _, s := range sources {
+				if s.path != stdinName {
+					continue
+				}
+				if stdinUsed {
+					return withExitCode(ExitUsage, fmt.Errorf("only one users file can read stdin"))
+				}
+				stdinUsed = true
+			}
+
+			// seen maps every user to the file it came from, so a user in
+			// two files is caught rather than linked twice
+			seen := map[string]string{}
+			operations := []api.RichMenuBatchOperation{}
+			for _, s := range sources {
+				var users []string
+				_, err := readUsersFile(s.path, userids.Options{MaxUsers: maxUsers, SkipInvalid: skipInvalid}, usersFileBatchSize, func(batch []string) error {
+					for _, u := range batch {
+						if prev, ok := seen[u]; ok {
+							return fmt.Errorf("user %s is in both %s and %s", u, prev, s.flag)
+						}
+						seen[u] = s.flag
+					}
+					users = append(users, batch...)
+					return nil
+				})
+				if errors.Is(err, userids.ErrNoUsers) {
+					return fmt.Errorf("%s: no user IDs found in file", s.flag)
+				}
+				if err != nil {
+					return fmt.Errorf("%s: %w", s.flag, err)
+				}
+				for _, chunk := range chunkStrings(users, maxBatchOperationUsers) {
+					op := api.RichMenuBatchOperation{Type: "unlink", UserIDs: chunk}
+					if s.menuID != "" {
+						op.Type, op.RichMenuID = "link", s.menuID
+					}
+					operations = append(operations, op)
+				}
+			}
+
+			data, err := json.MarshalIndent(operations, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode operations: %w", err)
+			}
+
+			if outputPath == "" {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(outputPath, append(data, '\n'), 0644); err != nil {
+				return fmt.Errorf("failed to write operations file: %w", err)
+			}
+
+			linkOps, unlinkOps, users := summarizeBatchOperations(operations)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Generated %d operations (%d link, %d unlink) for %d users\n",
+				len(operations), linkOps, unlinkOps, users)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&links, "link", nil, "RICH_MENU_ID:USERS_FILE to link the users in the file to the menu, or - for stdin (repeatable)")
+	cmd.Flags().StringArrayVar(&unlinks, "unlink", nil, "Users file whose users are unlinked, or - for stdin (repeatable)")
+	cmd.Flags().StringVar(&outputPath, "out", "", "Write operations to this file (default: stdout)")
+	addMaxUsersFlag(cmd, &maxUsers)
+	addInvalidUsersFlags(cmd, &skipInvalid)
+
+	return cmd
+}
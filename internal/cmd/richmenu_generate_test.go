@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeUsers writes n user IDs, numbered from start, to a users file.
+func writeUsers(t *testing.T, dir, name string, start, n int) string {
+	t.Helper()
+	var b strings.Builder
+	for i := start; i < start+n; i++ {
+		fmt.Fprintf(&b, "U%032x\n", i)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRichMenuBatchGenerateCmd_ChunksOperations(t *testing.T) {
+	dir := t.TempDir()
+	listA := writeUsers(t, dir, "a.txt", 0, 1200)
+	listB := writeUsers(t, dir, "b.txt", 2000, 3)
+	listC := writeUsers(t, dir, "c.txt", 3000, 501)
+	outPath := filepath.Join(dir, "ops.json")
+
+	cmd := newRichMenuBatchGenerateCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--link", "richmenu-a:" + listA, "--link", "richmenu-b:" + listB, "--unlink", listC, "--out", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Generated 6 operations (4 link, 2 unlink) for 1704 users") {
+		t.Errorf("unexpected summary: %s", out.String())
+	}
+
+	ops, err := readBatchOperationsFromFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read generated ops: %v", err)
+	}
+	want := []struct {
+		typ, menu string
+		users     int
+	}{
+		{"link", "richmenu-a", 500}, {"link", "richmenu-a", 500}, {"link", "richmenu-a", 200},
+		{"link", "richmenu-b", 3},
+		{"unlink", "", 500}, {"unlink", "", 1},
+	}
+	if len(ops) != len(want) {
+		t.Fatalf("expected %d operations, got %d", len(want), len(ops))
+	}
+	for i, w := range want {
+		if ops[i].Type != w.typ || ops[i].RichMenuID != w.menu || len(ops[i].UserIDs) != w.users {
+			t.Errorf("operation %d: got %s %s with %d users, want %+v", i, ops[i].Type, ops[i].RichMenuID, len(ops[i].UserIDs), w)
+		}
+	}
+}
+
+func TestRichMenuBatchGenerateCmd_Errors(t *testing.T) {
+	dir := t.TempDir()
+	listA := writeUsers(t, dir, "a.txt", 0, 3)
+	overlap := writeUsers(t, dir, "overlap.txt", 2, 3)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"nothing to do", nil, "at least one --link or --unlink is required"},
+		{"bad link", []string{"--link", listA}, "use RICH_MENU_ID:USERS_FILE"},
+		{"user in two files", []string{"--link", "richmenu-a:" + listA, "--unlink", overlap}, "is in both --link richmenu-a:" + listA + " and --unlink " + overlap},
+		{"stdin twice", []string{"--link", "richmenu-a:-", "--unlink", "-"}, "only one users file can read stdin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRichMenuBatchGenerateCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"os"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/imagegen"
+	"github.com/spf13/cobra"
+)
+
+func newRichMenuGenerateImageCmd() *cobra.Command {
+	var menuFile string
+	var outPath string
+
+	cmd := &cobra.Command{
+		Use:   "generate-image",
+		Short: "Render a placeholder image for a menu definition",
+		Long: `Render a placeholder PNG for a rich menu definition with one labeled cell
+per area, so layouts can be tested before final artwork is ready.
+
+Each cell is drawn at the area's bounds and labeled with the action's label
+(falling back to its text, alias, URI, or data) and the action type. The
+image matches the menu's size, so it can be uploaded with upload-image or
+deploy.`,
+		Example: `  # Generate a placeholder and deploy it
+  line richmenu generate-image --file menu.json --out menu.png
+  line richmenu deploy --file menu.json --image menu.png`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if menuFile == "" {
+				return fmt.Errorf("--file is required")
+			}
+			if outPath == "" {
+				return fmt.Errorf("--out is required")
+			}
+
+			menu, err := readRichMenuDefinitionFromFile(menuFile)
+			if err != nil {
+				return fmt.Errorf("failed to read menu file: %w", err)
+			}
+
+			cells := make([]imagegen.Cell, 0, len(menu.Areas))
+			for _, area := range menu.Areas {
+				label, actionType := richMenuAreaLabel(area)
+				cells = append(cells, imagegen.Cell{
+					X:      area.Bounds.X,
+					Y:      area.Bounds.Y,
+					Width:  area.Bounds.Width,
+					Height: area.Bounds.Height,
+					Label:  label,
+					Detail: actionType,
+				})
+			}
+
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, imagegen.Grid(menu.Size.Width, menu.Size.Height, cells)); err != nil {
+				return fmt.Errorf("failed to encode image: %w", err)
+			}
+			if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to write image: %w", err)
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{
+					"file":   outPath,
+					"width":  menu.Size.Width,
+					"height": menu.Size.Height,
+					"areas":  len(cells),
+					"bytes":  buf.Len(),
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Generated %dx%d placeholder with %d areas: %s\n", menu.Size.Width, menu.Size.Height, len(cells), outPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&menuFile, "file", "", "JSON file containing the rich menu definition, or - for stdin (required)")
+	cmd.Flags().StringVar(&outPath, "out", "", "PNG file to write (required)")
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+// richMenuAreaLabel returns a human-readable label and the action type for
+// a rich menu area.
+func richMenuAreaLabel(area api.RichMenuArea) (label, actionType string) {
+	var action struct {
+		Type            string `json:"type"`
+		Label           string `json:"label"`
+		Text            string `json:"text"`
+		RichMenuAliasID string `json:"richMenuAliasId"`
+		URI             string `json:"uri"`
+		Data            string `json:"data"`
+	}
+	_ = json.Unmarshal(area.Action, &action)
+
+	for _, candidate := range []string{action.Label, action.Text, action.RichMenuAliasID, action.URI, action.Data, action.Type} {
+		if candidate != "" {
+			return candidate, action.Type
+		}
+	}
+	return "?", action.Type
+}
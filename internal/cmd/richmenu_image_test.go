@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestRichMenuGenerateImageCmd(t *testing.T) {
+	dir := t.TempDir()
+	menuPath := filepath.Join(dir, "menu.json")
+	menuJSON := `{"size":{"width":2500,"height":843},"name":"Grid","areas":[
+		{"bounds":{"x":0,"y":0,"width":1250,"height":843},"action":{"type":"message","label":"Help","text":"help"}},
+		{"bounds":{"x":1250,"y":0,"width":1250,"height":843},"action":{"type":"uri","uri":"https://example.com"}}]}`
+	if err := os.WriteFile(menuPath, []byte(menuJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "menu.png")
+
+	cmd := newRichMenuGenerateImageCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--file", menuPath, "--out", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Generated 2500x843 placeholder with 2 areas") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	// The placeholder must be accepted by upload-image/deploy validation
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkRichMenuImageSize(data, "image/png", api.RichMenuSize{Width: 2500, Height: 843}); err != nil {
+		t.Errorf("generated image failed validation: %v", err)
+	}
+}
+
+func TestRichMenuAreaLabel(t *testing.T) {
+	tests := []struct {
+		action    string
+		wantLabel string
+		wantType  string
+	}{
+		{`{"type":"message","label":"Help","text":"help"}`, "Help", "message"},
+		{`{"type":"message","text":"hello"}`, "hello", "message"},
+		{`{"type":"richmenuswitch","richMenuAliasId":"page-2","data":"x"}`, "page-2", "richmenuswitch"},
+		{`{"type":"postback","data":"action=buy"}`, "action=buy", "postback"},
+		{`{"type":"camera"}`, "camera", "camera"},
+	}
+	for _, tt := range tests {
+		label, actionType := richMenuAreaLabel(api.RichMenuArea{Action: json.RawMessage(tt.action)})
+		if label != tt.wantLabel || actionType != tt.wantType {
+			t.Errorf("richMenuAreaLabel(%s) = %q, %q; want %q, %q", tt.action, label, actionType, tt.wantLabel, tt.wantType)
+		}
+	}
+}
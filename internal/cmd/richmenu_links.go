@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func newRichMenuLinksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "links",
+		Short: "Audit per-user rich menu links",
+	}
+
+	cmd.AddCommand(newRichMenuLinksExportCmd())
+
+	return cmd
+}
+
+func newRichMenuLinksExportCmd() *cobra.Command {
+	return newRichMenuLinksExportCmdWithClient(nil, nil)
+}
+
+// userLink is the rich menu linked to one user, as found by links export.
+type userLink struct {
+	userID     string
+	richMenuID string
+	err        error
+}
+
+func newRichMenuLinksExportCmdWithClient(client *api.Client, userIDsOverride []string) *cobra.Command {
+	var richMenuID string
+	var usersFile string
+	var outPath string
+	var maxUsers int
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the rich menu linked to each user as CSV",
+		Long: `Check the rich menu linked to each user in a users file and write a CSV
+report with the columns userId, linkedMenuId, matches, and error.
+
+matches is true when the user is linked to --menu. A user with no per-user
+link has an empty linkedMenuId (they see the default menu, if any). A user
+whose lookup failed has matches "error" and the reason in the error column.
+
+Users are checked --concurrency at a time, with a progress bar on stderr.
+Use it to audit the result of a large "line richmenu bulk link".`,
+		Example: `  line richmenu links export --menu richmenu-xxx --users users.txt --out report.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if richMenuID == "" {
+				return fmt.Errorf("--menu is required")
+			}
+			if userIDsOverride == nil && usersFile == "" {
+				return fmt.Errorf("--users is required")
+			}
+			if outPath == "" {
+				return fmt.Errorf("--out is required")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+
+			usersFile, cleanup, err := spoolStdin(usersFile)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			total := countUsers(usersFile, userIDsOverride, maxUsers)
+			file, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to create report: %w", err)
+			}
+			defer func() { _ = file.Close() }()
+			report := csv.NewWriter(file)
+			_ = report.Write([]string{"userId", "linkedMenuId", "matches", "error"})
+
+			progress := newProgressMeter(cmd, total)
+			var matched, other, unlinked, failed int
+			_, err = forEachUserBatch(usersFile, userIDsOverride, maxUsers, usersFileBatchSize, func(batch []string) error {
+				links, _ := enrichConcurrently(cmd.Context(), batch, concurrency, func(ctx context.Context, userID string) (userLink, error) {
+					link := userLink{userID: userID}
+					link.richMenuID, link.err = lookupUserRichMenu(ctx, c, userID)
+					progress.add(link.err != nil)
+					return link, nil
+				})
+				if err := cmd.Context().Err(); err != nil {
+					return err
+				}
+				for _, link := range links {
+					row := []string{link.userID, link.richMenuID, strconv.FormatBool(link.richMenuID == richMenuID), ""}
+					switch {
+					case link.err != nil:
+						row[2], row[3] = "error", csvError(link.err)
+						failed++
+					case link.richMenuID == richMenuID:
+						matched++
+					case link.richMenuID == "":
+						unlinked++
+					default:
+						other++
+					}
+					if err := report.Write(row); err != nil {
+						return fmt.Errorf("failed to write report: %w", err)
+					}
+				}
+				return nil
+			})
+			progress.clear()
+			report.Flush()
+			if err == nil {
+				err = report.Error()
+			}
+			if cerr := file.Close(); err == nil && cerr != nil {
+				err = fmt.Errorf("failed to write report: %w", cerr)
+			}
+			if err != nil {
+				return err
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{
+					"richMenuId": richMenuID,
+					"userCount":  matched + other + unlinked + failed,
+					"matched":    matched,
+					"otherMenu":  other,
+					"unlinked":   unlinked,
+					"errors":     failed,
+					"report":     outPath,
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(result); err != nil {
+					return err
+				}
+			} else {
+				out := cmd.OutOrStdout()
+				_, _ = fmt.Fprintf(out, "Checked %d users against %s\n", matched+other+unlinked+failed, richMenuID)
+				_, _ = fmt.Fprintf(out, "  Linked to it:      %d\n", matched)
+				_, _ = fmt.Fprintf(out, "  Linked elsewhere:  %d\n", other)
+				_, _ = fmt.Fprintf(out, "  No per-user link:  %d\n", unlinked)
+				_, _ = fmt.Fprintf(out, "  Lookup errors:     %d\n", failed)
+				_, _ = fmt.Fprintf(out, "Report written to %s\n", outPath)
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to check %d users; see %s", failed, outPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&richMenuID, "menu", "", "Rich menu ID the users should be linked to (required)")
+	cmd.Flags().StringVar(&usersFile, "users", "", "File containing user IDs, one per line, or - for stdin (required)")
+	cmd.Flags().StringVar(&outPath, "out", "", "CSV report file to write (required)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultBulkConcurrency, "Users checked at a time")
+	addMaxUsersFlag(cmd, &maxUsers)
+	_ = cmd.MarkFlagRequired("menu")
+	_ = cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+// lookupUserRichMenu returns the rich menu linked to userID, or "" if the
+// user has no per-user link. A 429 is retried like the bulk fallback does.
+func lookupUserRichMenu(ctx context.Context, c *api.Client, userID string) (string, error) {
+	var id string
+	err := callRetrying429(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = c.GetUserRichMenu(ctx, userID)
+		return err
+	})
+	if apiErr := api.AsAPIError(err); apiErr != nil && apiErr.IsNotFound() {
+		return "", nil
+	}
+	return id, err
+}
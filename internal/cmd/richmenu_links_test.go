@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestRichMenuLinksExportCmd(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/user/U001/richmenu":
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": "rm-1"})
+		case "/v2/bot/user/U002/richmenu":
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": "rm-2"})
+		case "/v2/bot/user/U003/richmenu":
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "the user has no richmenu"})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "boom"})
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	report := filepath.Join(t.TempDir(), "report.csv")
+	cmd := newRichMenuLinksExportCmdWithClient(client, []string{"U001", "U002", "U003", "U004"})
+	cmd.SetArgs([]string{"--menu", "rm-1", "--out", report, "--concurrency", "3"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "failed to check 1 users") {
+		t.Fatalf("expected lookup failure for one user, got %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	for key, want := range map[string]float64{"userCount": 4, "matched": 1, "otherMenu": 1, "unlinked": 1, "errors": 1} {
+		if result[key] != want {
+			t.Errorf("%s = %v, want %v", key, result[key], want)
+		}
+	}
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{
+		"userId,linkedMenuId,matches,error",
+		"U001,rm-1,true,",
+		"U002,rm-2,false,",
+		"U003,,false,",
+	}
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 report lines, got:\n%s", data)
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d = %q, want %q", i, lines[i], w)
+		}
+	}
+	if lines[4] != "U004,,error,500 boom" {
+		t.Errorf("unexpected error line: %q", lines[4])
+	}
+}
+
+func TestRichMenuLinksExportCmd_RequiresOut(t *testing.T) {
+	cmd := newRichMenuLinksExportCmdWithClient(api.NewClient("test-token", false, false), []string{"U001"})
+	cmd.SetArgs([]string{"--menu", "rm-1"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "out") {
+		t.Fatalf("expected --out error, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// maxBatchOperationUsers is the maximum number of user IDs the LINE API
+// accepts in a single batch operation.
+const maxBatchOperationUsers = api.MaxRichMenuBatchUsers
+
+// richMenuLink is a single user-to-menu linkage record.
+type richMenuLink struct {
+	UserID     string `json:"userId"`
+	RichMenuID string `json:"richMenuId"`
+}
+
+func newRichMenuBatchPlanCmd() *cobra.Command {
+	var fromFile string
+	var toFile string
+	var outputPath string
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Generate batch operations from two linkage states",
+		Long: `Compute the minimal set of link/unlink operations needed to move users
+from the current rich menu linkage to the desired one.
+
+Both files describe per-user linkage, either as an object mapping user IDs
+to rich menu IDs:
+  {"U1": "richmenu-a", "U2": "richmenu-b"}
+
+or as an array of records:
+  [{"userId": "U1", "richMenuId": "richmenu-a"}]
+
+Users whose menu is unchanged are skipped. Users present in --from but
+missing (or mapped to an empty ID) in --to are unlinked. The resulting file
+can be passed to 'line richmenu batch --operations'.`,
+		Example: `  # Write the plan to a file
+  line richmenu batch plan --from current.json --to desired.json --output ops.json
+
+  # Print the plan to stdout
+  line richmenu batch plan --from current.json --to desired.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromFile == "" {
+				return fmt.Errorf("--from is required")
+			}
+			if toFile == "" {
+				return fmt.Errorf("--to is required")
+			}
+			if fromFile == stdinName && toFile == stdinName {
+				return fmt.Errorf("only one of --from and --to can read stdin")
+			}
+
+			current, err := readRichMenuLinkage(fromFile)
+			if err != nil {
+				return fmt.Errorf("failed to read --from file: %w", err)
+			}
+			desired, err := readRichMenuLinkage(toFile)
+			if err != nil {
+				return fmt.Errorf("failed to read --to file: %w", err)
+			}
+
+			operations := planRichMenuBatchOperations(current, desired)
+
+			data, err := json.MarshalIndent(operations, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode operations: %w", err)
+			}
+
+			if outputPath == "" {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(outputPath, append(data, '\n'), 0644); err != nil {
+				return fmt.Errorf("failed to write operations file: %w", err)
+			}
+
+			links, unlinks, users := summarizeBatchOperations(operations)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Planned %d operations (%d link, %d unlink) for %d users\n",
+				len(operations), links, unlinks, users)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fromFile, "from", "", "JSON file with the current user-to-menu linkage, or - for stdin (required)")
+	cmd.Flags().StringVar(&toFile, "to", "", "JSON file with the desired user-to-menu linkage, or - for stdin (required)")
+	cmd.Flags().StringVar(&outputPath, "output", "", "Write operations to this file (default: stdout)")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// readRichMenuLinkage reads a user-to-menu linkage file in either object or
+// array form and returns it as a map of user ID to rich menu ID.
+func readRichMenuLinkage(path string) (map[string]string, error) {
+	data, err := readFileOrStdin(path)
+	if err != nil {
+		return nil, err
+	}
+
+	linkage := make(map[string]string)
+	if err := json.Unmarshal(data, &linkage); err == nil {
+		return linkage, nil
+	}
+
+	var records []richMenuLink
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid JSON: expected an object of userId to richMenuId or an array of {userId, richMenuId}")
+	}
+	for _, r := range records {
+		if r.UserID == "" {
+			return nil, fmt.Errorf("record missing userId")
+		}
+		linkage[r.UserID] = r.RichMenuID
+	}
+	return linkage, nil
+}
+
+// planRichMenuBatchOperations returns the link/unlink operations that move
+// users from the current linkage to the desired one. Link operations are
+// grouped by rich menu and every operation is capped at
+// maxBatchOperationUsers users. Output is sorted for stable diffs.
+func planRichMenuBatchOperations(current, desired map[string]string) []api.RichMenuBatchOperation {
+	linkUsers := make(map[string][]string)
+	var unlinkUsers []string
+
+	for userID, menuID := range desired {
+		if menuID == "" {
+			if current[userID] != "" {
+				unlinkUsers = append(unlinkUsers, userID)
+			}
+			continue
+		}
+		if current[userID] != menuID {
+			linkUsers[menuID] = append(linkUsers[menuID], userID)
+		}
+	}
+	for userID, menuID := range current {
+		if _, ok := desired[userID]; !ok && menuID != "" {
+			unlinkUsers = append(unlinkUsers, userID)
+		}
+	}
+
+	menuIDs := make([]string, 0, len(linkUsers))
+	for menuID := range linkUsers {
+		menuIDs = append(menuIDs, menuID)
+	}
+	sort.Strings(menuIDs)
+
+	operations := []api.RichMenuBatchOperation{}
+	for _, menuID := range menuIDs {
+		users := linkUsers[menuID]
+		sort.Strings(users)
+		for _, chunk := range chunkStrings(users, maxBatchOperationUsers) {
+			operations = append(operations, api.RichMenuBatchOperation{
+				Type:       "link",
+				RichMenuID: menuID,
+				UserIDs:    chunk,
+			})
+		}
+	}
+
+	sort.Strings(unlinkUsers)
+	for _, chunk := range chunkStrings(unlinkUsers, maxBatchOperationUsers) {
+		operations = append(operations, api.RichMenuBatchOperation{
+			Type:    "unlink",
+			UserIDs: chunk,
+		})
+	}
+
+	return operations
+}
+
+// summarizeBatchOperations counts link and unlink operations and the total
+// number of users they touch.
+func summarizeBatchOperations(operations []api.RichMenuBatchOperation) (links, unlinks, users int) {
+	for _, op := range operations {
+		switch op.Type {
+		case "link":
+			links++
+		case "unlink":
+			unlinks++
+		}
+		users += len(op.UserIDs)
+	}
+	return links, unlinks, users
+}
+
+// chunkStrings splits values into consecutive slices of at most size elements.
+func chunkStrings(values []string, size int) [][]string {
+	var chunks [][]string
+	for len(values) > size {
+		chunks = append(chunks, values[:size])
+		values = values[size:]
+	}
+	if len(values) > 0 {
+		chunks = append(chunks, values)
+	}
+	return chunks
+}
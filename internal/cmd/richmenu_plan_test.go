@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestPlanRichMenuBatchOperations(t *testing.T) {
+	current := map[string]string{
+		"U1": "richmenu-a", // unchanged
+		"U2": "richmenu-a", // moves to b
+		"U3": "richmenu-b", // removed from desired -> unlink
+		"U4": "richmenu-b", // explicitly cleared -> unlink
+	}
+	desired := map[string]string{
+		"U1": "richmenu-a",
+		"U2": "richmenu-b",
+		"U4": "",
+		"U5": "richmenu-a", // new user
+		"U6": "",           // nothing to unlink
+	}
+
+	got := planRichMenuBatchOperations(current, desired)
+	want := []api.RichMenuBatchOperation{
+		{Type: "link", RichMenuID: "richmenu-a", UserIDs: []string{"U5"}},
+		{Type: "link", RichMenuID: "richmenu-b", UserIDs: []string{"U2"}},
+		{Type: "unlink", UserIDs: []string{"U3", "U4"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planRichMenuBatchOperations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPlanRichMenuBatchOperations_NoChanges(t *testing.T) {
+	state := map[string]string{"U1": "richmenu-a"}
+
+	got := planRichMenuBatchOperations(state, state)
+	if len(got) != 0 {
+		t.Errorf("expected no operations, got %+v", got)
+	}
+}
+
+func TestPlanRichMenuBatchOperations_ChunksLargeGroups(t *testing.T) {
+	desired := make(map[string]string)
+	for i := 0; i < maxBatchOperationUsers+1; i++ {
+		desired[fmt.Sprintf("U%04d", i)] = "richmenu-a"
+	}
+
+	got := planRichMenuBatchOperations(map[string]string{}, desired)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(got))
+	}
+	if len(got[0].UserIDs) != maxBatchOperationUsers || len(got[1].UserIDs) != 1 {
+		t.Errorf("unexpected chunk sizes: %d, %d", len(got[0].UserIDs), len(got[1].UserIDs))
+	}
+}
+
+func TestReadRichMenuLinkage_Formats(t *testing.T) {
+	dir := t.TempDir()
+
+	objPath := filepath.Join(dir, "obj.json")
+	if err := os.WriteFile(objPath, []byte(`{"U1":"richmenu-a"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	arrPath := filepath.Join(dir, "arr.json")
+	if err := os.WriteFile(arrPath, []byte(`[{"userId":"U1","richMenuId":"richmenu-a"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{objPath, arrPath} {
+		linkage, err := readRichMenuLinkage(path)
+		if err != nil {
+			t.Fatalf("readRichMenuLinkage(%s) error: %v", path, err)
+		}
+		if linkage["U1"] != "richmenu-a" {
+			t.Errorf("readRichMenuLinkage(%s) = %v", path, linkage)
+		}
+	}
+}
+
+func TestReadRichMenuLinkage_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte(`"nope"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readRichMenuLinkage(path); err == nil {
+		t.Error("expected error for invalid linkage file")
+	}
+}
+
+func TestRichMenuBatchPlanCmd_WritesFile(t *testing.T) {
+	dir := t.TempDir()
+	fromPath := filepath.Join(dir, "current.json")
+	toPath := filepath.Join(dir, "desired.json")
+	outPath := filepath.Join(dir, "ops.json")
+
+	if err := os.WriteFile(fromPath, []byte(`{"U1":"richmenu-a"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(toPath, []byte(`{"U1":"richmenu-b"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newRichMenuBatchPlanCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--from", fromPath, "--to", toPath, "--output", outPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Planned 1 operations (1 link, 0 unlink) for 1 users") {
+		t.Errorf("unexpected summary: %s", out.String())
+	}
+
+	ops, err := readBatchOperationsFromFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read generated ops: %v", err)
+	}
+	if len(ops) != 1 || ops[0].RichMenuID != "richmenu-b" {
+		t.Errorf("unexpected operations: %+v", ops)
+	}
+}
+
+func TestRichMenuBatchPlanCmd_Stdout(t *testing.T) {
+	dir := t.TempDir()
+	fromPath := filepath.Join(dir, "current.json")
+	toPath := filepath.Join(dir, "desired.json")
+
+	if err := os.WriteFile(fromPath, []byte(`{"U1":"richmenu-a"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(toPath, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newRichMenuBatchPlanCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--from", fromPath, "--to", toPath})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ops []api.RichMenuBatchOperation
+	if err := json.Unmarshal(out.Bytes(), &ops); err != nil {
+		t.Fatalf("expected JSON operations on stdout, got: %s", out.String())
+	}
+	if len(ops) != 1 || ops[0].Type != "unlink" {
+		t.Errorf("unexpected operations: %+v", ops)
+	}
+}
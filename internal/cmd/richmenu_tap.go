@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// richMenuTapResult describes which area of a rich menu a tap lands in.
+type richMenuTapResult struct {
+	RichMenuID string              `json:"richMenuId"`
+	X          int                 `json:"x"`
+	Y          int                 `json:"y"`
+	AreaIndex  *int                `json:"areaIndex"`
+	Bounds     *api.RichMenuBounds `json:"bounds,omitempty"`
+	Action     json.RawMessage     `json:"action,omitempty"`
+	// Shadowed lists other areas that also contain the point; the first
+	// matching area in the menu definition wins
+	Shadowed []int `json:"shadowed,omitempty"`
+}
+
+func newRichMenuTestTapCmd() *cobra.Command {
+	return newRichMenuTestTapCmdWithClient(nil)
+}
+
+func newRichMenuTestTapCmdWithClient(client *api.Client) *cobra.Command {
+	var richMenuID string
+	var name string
+	var x, y int
+
+	cmd := &cobra.Command{
+		Use:   "test-tap",
+		Short: "Show which area and action a tap coordinate hits",
+		Long: `Resolve which area of a rich menu contains a tap coordinate and print the
+action that would fire. Coordinates are in the menu's pixel space (for
+example 0-2499 x 0-1685 for a full-size menu).
+
+Useful for debugging "the button does nothing" reports against complex
+area layouts. Area indexes are zero-based, in definition order.`,
+		Example: `  line richmenu test-tap --id richmenu-xxx --x 1200 --y 800`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if richMenuID == "" && name == "" {
+				return fmt.Errorf("--id or --name is required")
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			richMenuID, err := resolveRichMenuID(cmd.Context(), c, richMenuID, name)
+			if err != nil {
+				return err
+			}
+			menu, err := c.GetRichMenu(cmd.Context(), richMenuID)
+			if err != nil {
+				return fmt.Errorf("failed to get rich menu: %w", err)
+			}
+
+			if x < 0 || y < 0 || x >= menu.Size.Width || y >= menu.Size.Height {
+				return fmt.Errorf("(%d, %d) is outside the %dx%d menu", x, y, menu.Size.Width, menu.Size.Height)
+			}
+
+			result := richMenuTapResult{RichMenuID: menu.RichMenuID, X: x, Y: y}
+			if hits := richMenuAreasAt(menu.Areas, x, y); len(hits) > 0 {
+				area := menu.Areas[hits[0]]
+				result.AreaIndex = &hits[0]
+				result.Bounds = &area.Bounds
+				result.Action = area.Action
+				result.Shadowed = hits[1:]
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+
+			out := cmd.OutOrStdout()
+			if result.AreaIndex == nil {
+				_, _ = fmt.Fprintf(out, "Tap at (%d, %d) hits no area; nothing happens\n", x, y)
+				return nil
+			}
+
+			b := result.Bounds
+			label, actionType := richMenuAreaLabel(api.RichMenuArea{Action: result.Action})
+			_, _ = fmt.Fprintf(out, "Tap at (%d, %d) hits area %d (of %d areas)\n", x, y, *result.AreaIndex, len(menu.Areas))
+			_, _ = fmt.Fprintf(out, "Bounds:  x=%d y=%d width=%d height=%d\n", b.X, b.Y, b.Width, b.Height)
+			_, _ = fmt.Fprintf(out, "Action:  %s (%s)\n", actionType, label)
+			_, _ = fmt.Fprintf(out, "Details: %s\n", compactJSON(result.Action))
+			for _, i := range result.Shadowed {
+				_, _ = fmt.Fprintf(out, "Note:    area %d also contains this point but is shadowed by area %d\n", i, *result.AreaIndex)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&richMenuID, "id", "", "Rich menu ID")
+	addNameFlag(cmd, &name, "rich menu")
+	cmd.Flags().IntVar(&x, "x", 0, "Tap X coordinate in menu pixels")
+	cmd.Flags().IntVar(&y, "y", 0, "Tap Y coordinate in menu pixels")
+	_ = cmd.MarkFlagRequired("x")
+	_ = cmd.MarkFlagRequired("y")
+
+	return cmd
+}
+
+// richMenuAreasAt returns the indexes of all areas containing (x, y), in
+// definition order. Bounds include their top-left edge and exclude the
+// bottom-right one, so adjacent areas never both match.
+func richMenuAreasAt(areas []api.RichMenuArea, x, y int) []int {
+	var hits []int
+	for i, a := range areas {
+		b := a.Bounds
+		if x >= b.X && x < b.X+b.Width && y >= b.Y && y < b.Y+b.Height {
+			hits = append(hits, i)
+		}
+	}
+	return hits
+}
+
+// compactJSON returns raw on a single line, or as-is if it is not valid JSON.
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+const tapTestMenu = `{"richMenuId":"richmenu-abc","size":{"width":2500,"height":1686},"areas":[
+	{"bounds":{"x":0,"y":0,"width":1250,"height":843},"action":{"type":"message","label":"Help","text":"help"}},
+	{"bounds":{"x":1250,"y":0,"width":1250,"height":843},"action":{"type":"uri","uri":"https://example.com"}},
+	{"bounds":{"x":0,"y":843,"width":2500,"height":843},"action":{"type":"postback","data":"action=wide"}},
+	{"bounds":{"x":1000,"y":1000,"width":500,"height":500},"action":{"type":"postback","data":"action=hidden"}}]}`
+
+func newTapTestClient(t *testing.T) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/richmenu/richmenu-abc" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(tapTestMenu))
+	}))
+	t.Cleanup(server.Close)
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestRichMenuTestTapCmd_Text(t *testing.T) {
+	oldOutput := flags.Output
+	flags.Output = "text"
+	defer func() { flags.Output = oldOutput }()
+
+	tests := []struct {
+		name string
+		x, y string
+		want []string
+	}{
+		{"first area", "10", "10", []string{"hits area 0 (of 4 areas)", "Action:  message (Help)", `"text":"help"`}},
+		{"boundary belongs to right area", "1250", "0", []string{"hits area 1", "uri (https://example.com)"}},
+		{"overlap is shadowed", "1200", "1200", []string{"hits area 2", "area 3 also contains this point but is shadowed by area 2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRichMenuTestTapCmdWithClient(newTapTestClient(t))
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetArgs([]string{"--id", "richmenu-abc", "--x", tt.x, "--y", tt.y})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRichMenuTestTapCmd_JSON(t *testing.T) {
+	oldOutput := flags.Output
+	flags.Output = "json"
+	defer func() { flags.Output = oldOutput }()
+
+	cmd := newRichMenuTestTapCmdWithClient(newTapTestClient(t))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--id", "richmenu-abc", "--x", "1200", "--y", "1200"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result richMenuTapResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result.AreaIndex == nil || *result.AreaIndex != 2 {
+		t.Errorf("expected area 2, got %v", result.AreaIndex)
+	}
+	if len(result.Shadowed) != 1 || result.Shadowed[0] != 3 {
+		t.Errorf("expected area 3 shadowed, got %v", result.Shadowed)
+	}
+	if !strings.Contains(string(result.Action), "action=wide") {
+		t.Errorf("unexpected action: %s", result.Action)
+	}
+}
+
+func TestRichMenuTestTapCmd_OutsideMenu(t *testing.T) {
+	cmd := newRichMenuTestTapCmdWithClient(newTapTestClient(t))
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--id", "richmenu-abc", "--x", "2500", "--y", "0"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "outside the 2500x1686 menu") {
+		t.Errorf("expected outside-menu error, got: %v", err)
+	}
+}
+
+func TestRichMenuAreasAt_NoArea(t *testing.T) {
+	areas := []api.RichMenuArea{{Bounds: api.RichMenuBounds{X: 0, Y: 0, Width: 100, Height: 100}}}
+	if hits := richMenuAreasAt(areas, 150, 50); len(hits) != 0 {
+		t.Errorf("expected no hits, got %v", hits)
+	}
+}
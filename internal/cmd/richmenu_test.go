@@ -3,9 +3,14 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -29,7 +34,7 @@ func TestRichMenuCmd_HasSubcommands(t *testing.T) {
 	expectedSubcommands := []string{
 		"list", "create", "delete", "set-default", "cancel-default",
 		"upload-image", "get", "link", "unlink", "alias", "bulk", "batch",
-		"validate", "download-image",
+		"validate", "download-image", "deploy", "generate-image", "linked", "test-tap",
 	}
 
 	for _, expected := range expectedSubcommands {
@@ -268,8 +273,8 @@ func TestRichMenuAliasCmd_HasSubcommands(t *testing.T) {
 	cmd := newRichMenuAliasCmd()
 
 	subcommands := cmd.Commands()
-	if len(subcommands) != 5 {
-		t.Errorf("expected 5 alias subcommands, got %d", len(subcommands))
+	if len(subcommands) != 6 {
+		t.Errorf("expected 6 alias subcommands, got %d", len(subcommands))
 	}
 
 	names := make(map[string]bool)
@@ -277,7 +282,7 @@ func TestRichMenuAliasCmd_HasSubcommands(t *testing.T) {
 		names[subcmd.Name()] = true
 	}
 
-	expected := []string{"create", "get", "update", "delete", "list"}
+	expected := []string{"create", "get", "update", "delete", "list", "sync"}
 	for _, name := range expected {
 		if !names[name] {
 			t.Errorf("expected '%s' subcommand", name)
@@ -379,8 +384,8 @@ func TestRichMenuBatchCmd_HasSubcommands(t *testing.T) {
 	cmd := newRichMenuBatchCmd()
 
 	subcommands := cmd.Commands()
-	if len(subcommands) != 2 {
-		t.Errorf("expected 2 batch subcommands, got %d", len(subcommands))
+	if len(subcommands) != 4 {
+		t.Errorf("expected 4 batch subcommands, got %d", len(subcommands))
 	}
 
 	names := make(map[string]bool)
@@ -394,6 +399,12 @@ func TestRichMenuBatchCmd_HasSubcommands(t *testing.T) {
 	if !names["status"] {
 		t.Error("expected 'status' subcommand")
 	}
+	if !names["plan"] {
+		t.Error("expected 'plan' subcommand")
+	}
+	if !names["generate"] {
+		t.Error("expected 'generate' subcommand")
+	}
 }
 
 func TestRichMenuBatchCmd_Flags(t *testing.T) {
@@ -1313,6 +1324,60 @@ func TestRichMenuValidateCmd_Error(t *testing.T) {
 	}
 }
 
+func TestRichMenuValidateCmd_LocalProblems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	path := filepath.Join(t.TempDir(), "menu.json")
+	menu := `{"size": {"width": 2500, "height": 843}, "selected": false, "name": "Main", "chatBarText": "Open the main menu",
+	  "areas": [{"bounds": {"x": 0, "y": 0, "width": 2600, "height": 843}, "action": {"type": "message"}}]}`
+	if err := os.WriteFile(path, []byte(menu), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newRichMenuValidateCmdWithClient(client, nil)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--file", path})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "3 problem(s) found" {
+		t.Fatalf("expected 3 problems, got %v", err)
+	}
+	for _, want := range []string{
+		"/chatBarText: chatBarText is 18 characters, at most 14 are allowed",
+		"/areas/0/bounds: area ends at x=2600, past the menu width 2500",
+		"/areas/0/action/text: text is required",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, out.String())
+		}
+	}
+}
+
+func TestRichMenuValidateCmd_Offline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "menu.json")
+	if err := os.WriteFile(path, []byte(validRichMenu), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// No client: --offline must not build one
+	cmd := newRichMenuValidateCmdWithClient(nil, nil)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--file", path, "--offline"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Rich menu definition valid: Main") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
 // Download image command execution test
 
 func TestRichMenuDownloadImageCmd_Execute(t *testing.T) {
@@ -1742,66 +1807,6 @@ func TestRichMenuListCmd_Error(t *testing.T) {
 	}
 }
 
-// Tests for readUserIDsFromFile
-
-func TestReadUserIDsFromFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	filePath := tmpDir + "/users.txt"
-
-	// Test with valid content including comments and blank lines
-	content := `# This is a comment
-U001
-U002
-
-# Another comment
-U003
-`
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		t.Fatalf("failed to create temp file: %v", err)
-	}
-
-	userIDs, err := readUserIDsFromFile(filePath)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	if len(userIDs) != 3 {
-		t.Errorf("expected 3 user IDs, got %d", len(userIDs))
-	}
-
-	expected := []string{"U001", "U002", "U003"}
-	for i, id := range expected {
-		if userIDs[i] != id {
-			t.Errorf("expected userIDs[%d] to be %s, got %s", i, id, userIDs[i])
-		}
-	}
-}
-
-func TestReadUserIDsFromFile_NonExistent(t *testing.T) {
-	_, err := readUserIDsFromFile("/nonexistent/path/users.txt")
-	if err == nil {
-		t.Fatal("expected error for non-existent file")
-	}
-}
-
-func TestReadUserIDsFromFile_Empty(t *testing.T) {
-	tmpDir := t.TempDir()
-	filePath := tmpDir + "/empty.txt"
-
-	if err := os.WriteFile(filePath, []byte("# Only comments\n"), 0644); err != nil {
-		t.Fatalf("failed to create temp file: %v", err)
-	}
-
-	userIDs, err := readUserIDsFromFile(filePath)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-
-	if len(userIDs) != 0 {
-		t.Errorf("expected 0 user IDs, got %d", len(userIDs))
-	}
-}
-
 // Tests for readBatchOperationsFromFile
 
 func TestReadBatchOperationsFromFile(t *testing.T) {
@@ -2422,6 +2427,85 @@ func TestRichMenuBatchStatusCmd_OngoingPhase(t *testing.T) {
 	}
 }
 
+func TestRichMenuBatchStatusCmd_Wait(t *testing.T) {
+	tests := []struct {
+		name      string
+		final     string
+		output    string
+		wantErr   bool
+		checkText string
+	}{
+		{"succeeded text", "succeeded", "text", false, "Phase:          succeeded"},
+		{"succeeded json", "succeeded", "json", false, `"polls": 3`},
+		{"failed json", "failed", "json", true, `"phase": "failed"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				phase := "ongoing"
+				if calls >= 3 {
+					phase = tt.final
+				}
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"phase":        phase,
+					"acceptedTime": "2024-01-01T00:00:00Z",
+				})
+			}))
+			defer server.Close()
+
+			client := api.NewClient("test-token", false, false)
+			client.SetBaseURL(server.URL)
+
+			oldOutput := flags.Output
+			defer func() { flags.Output = oldOutput }()
+			flags.Output = tt.output
+
+			cmd := newRichMenuBatchStatusCmdWithClient(client)
+			cmd.SetArgs([]string{"--request", "req-123", "--wait", "--interval", "1ms"})
+			var out, errOut bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&errOut)
+
+			err := cmd.Execute()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v, got %v", tt.wantErr, err)
+			}
+			if calls != 3 {
+				t.Errorf("expected 3 polls, got %d", calls)
+			}
+			if !strings.Contains(out.String(), tt.checkText) {
+				t.Errorf("output should contain %q, got: %s", tt.checkText, out.String())
+			}
+			if tt.output == "json" && strings.Contains(errOut.String(), "Waiting") {
+				t.Errorf("expected no spinner in json mode, got %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestRichMenuBatchStatusCmd_WaitTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"phase": "ongoing"})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newRichMenuBatchStatusCmdWithClient(client)
+	cmd.SetArgs([]string{"--request", "req-123", "--wait", "--interval", "5ms", "--timeout", "30ms"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
 // Tests for batch validate command
 
 func TestRichMenuBatchValidateCmd_Execute(t *testing.T) {
@@ -2618,6 +2702,103 @@ func TestRichMenuCreateCmd_CompactSize(t *testing.T) {
 	}
 }
 
+func TestRichMenuCreateCmd_FromFile(t *testing.T) {
+	var received api.CreateRichMenuRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v2/bot/richmenu" {
+			_ = json.NewDecoder(r.Body).Decode(&received)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": "rm-file-123"})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	menuPath := filepath.Join(t.TempDir(), "menu.json")
+	menuJSON := `{
+  "size": {"width": 2500, "height": 1686},
+  "selected": true,
+  "name": "Grid Menu",
+  "chatBarText": "Tap here",
+  "areas": [
+    {"bounds": {"x": 0, "y": 0, "width": 1250, "height": 843}, "action": {"type": "message", "text": "a"}},
+    {"bounds": {"x": 1250, "y": 0, "width": 1250, "height": 843}, "action": {"type": "message", "text": "b"}},
+    {"bounds": {"x": 0, "y": 843, "width": 2500, "height": 843}, "action": {"type": "message", "text": "c"}}
+  ]
+}`
+	if err := os.WriteFile(menuPath, []byte(menuJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	cmd := newRichMenuCreateCmdWithClient(client)
+	cmd.SetArgs([]string{"--file", menuPath})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Created rich menu: Grid Menu (ID: rm-file-123)") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	if len(received.Areas) != 3 || !received.Selected || received.ChatBarText != "Tap here" {
+		t.Errorf("request did not preserve the file definition: %+v", received)
+	}
+	if received.Areas[2].Bounds.Y != 843 {
+		t.Errorf("expected second-row area at y=843, got %d", received.Areas[2].Bounds.Y)
+	}
+}
+
+func TestRichMenuCreateCmd_FileAndActionsConflict(t *testing.T) {
+	menuPath := filepath.Join(t.TempDir(), "menu.json")
+	if err := os.WriteFile(menuPath, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newRichMenuCreateCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetArgs([]string{"--file", menuPath, "--actions", `[{"type":"message"}]`})
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("expected conflict error, got: %v", err)
+	}
+}
+
+func TestReadRichMenuDefinitionFromFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid JSON", content: `{`},
+		{name: "no areas", content: `{"size":{"width":2500,"height":1686},"name":"x","areas":[]}`},
+		{name: "no size", content: `{"name":"x","areas":[{"bounds":{},"action":{}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := readRichMenuDefinitionFromFile(path); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
 func TestRichMenuCreateCmd_InvalidActions(t *testing.T) {
 	client := api.NewClient("test-token", false, false)
 
@@ -2799,6 +2980,53 @@ func TestRichMenuBatchCmd_Execute(t *testing.T) {
 	}
 }
 
+func TestRichMenuBatchCmd_SplitsLargeBatches(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Operations []api.RichMenuBatchOperation `json:"operations"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		sizes = append(sizes, len(req.Operations))
+		_ = json.NewEncoder(w).Encode(map[string]string{"requestId": fmt.Sprintf("batch-req-%d", len(sizes))})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	users := make([]string, 1000*api.MaxRichMenuBatchUsers+1)
+	for i := range users {
+		users[i] = fmt.Sprintf("U%d", i)
+	}
+	operations := []api.RichMenuBatchOperation{{Type: "link", RichMenuID: "rm-123", UserIDs: users}}
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	cmd := newRichMenuBatchCmdWithClient(client, operations)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(sizes) != "[1000 1]" {
+		t.Errorf("expected requests of 1000 and 1 operations, got %v", sizes)
+	}
+	var result struct {
+		RequestIDs     []string `json:"requestIds"`
+		OperationCount int      `json:"operationCount"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if fmt.Sprint(result.RequestIDs) != "[batch-req-1 batch-req-2]" || result.OperationCount != 1001 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
 func TestRichMenuBatchCmd_Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -2842,3 +3070,458 @@ func TestRichMenuBatchCmd_EmptyOperations(t *testing.T) {
 		t.Errorf("expected 'no operations' error, got: %v", err)
 	}
 }
+
+// Deploy command tests
+
+func writeDeployFixtures(t *testing.T) (menuPath, imagePath string) {
+	t.Helper()
+	dir := t.TempDir()
+	menuPath = filepath.Join(dir, "menu.json")
+	menuJSON := `{"size":{"width":2500,"height":843},"name":"Deploy Menu","chatBarText":"Menu","areas":[{"bounds":{"x":0,"y":0,"width":2500,"height":843},"action":{"type":"message","text":"hi"}}]}`
+	if err := os.WriteFile(menuPath, []byte(menuJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	imagePath = filepath.Join(dir, "menu.png")
+	writeTestPNG(t, imagePath, 2500, 843)
+	return menuPath, imagePath
+}
+
+// writeTestPNG writes a blank PNG image with the given dimensions.
+func writeTestPNG(t *testing.T, path string, width, height int) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRichMenuDeployCmd_Success(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/bot/richmenu":
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": "rm-new"})
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/richmenu/alias/main":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		default:
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	menuPath, imagePath := writeDeployFixtures(t)
+	cmd := newRichMenuDeployCmdWithClient(client)
+	var out, progress bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&progress)
+	cmd.SetArgs([]string{"--file", menuPath, "--image", imagePath, "--alias", "main", "--default"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"POST /v2/bot/richmenu",
+		"POST /v2/bot/richmenu/rm-new/content",
+		"GET /v2/bot/richmenu/alias/main",
+		"POST /v2/bot/richmenu/alias",
+		"POST /v2/bot/user/all/richmenu/rm-new",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected call sequence:\n%s", strings.Join(calls, "\n"))
+	}
+
+	var result struct {
+		RichMenuID string               `json:"richMenuId"`
+		Deployed   bool                 `json:"deployed"`
+		Steps      []richMenuDeployStep `json:"steps"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if !result.Deployed || result.RichMenuID != "rm-new" || len(result.Steps) != 4 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if !strings.Contains(progress.String(), "==> Uploading image") {
+		t.Errorf("expected progress on stderr, got: %s", progress.String())
+	}
+}
+
+func TestRichMenuDeployCmd_RollsBackOnFailure(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/bot/richmenu":
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": "rm-new"})
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/bot/richmenu/alias/main":
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuAliasId": "main", "richMenuId": "rm-old"})
+		case r.URL.Path == "/v2/bot/user/all/richmenu/rm-new":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"boom"}`))
+		default:
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	menuPath, imagePath := writeDeployFixtures(t)
+	cmd := newRichMenuDeployCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--file", menuPath, "--image", imagePath, "--alias", "main", "--default"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected rollback error, got: %v", err)
+	}
+
+	tail := calls[len(calls)-2:]
+	if tail[0] != "POST /v2/bot/richmenu/alias/main" || tail[1] != "DELETE /v2/bot/richmenu/rm-new" {
+		t.Errorf("expected alias restore then menu delete, got: %v", calls)
+	}
+}
+
+func TestRichMenuDeployCmd_ReportsFailedRollback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/bot/richmenu":
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": "rm-new"})
+		case r.URL.Path == "/v2/bot/user/all/richmenu/rm-new", r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"boom"}`))
+		default:
+			_, _ = w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	menuPath, imagePath := writeDeployFixtures(t)
+	cmd := newRichMenuDeployCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--file", menuPath, "--image", imagePath, "--default"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "could not be rolled back") || !strings.Contains(err.Error(), "create:") {
+		t.Fatalf("expected the failed rollback step in the error, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "was rolled back") {
+		t.Errorf("error claims a rollback that failed: %v", err)
+	}
+}
+
+func TestRichMenuDeployCmd_RequiresFlags(t *testing.T) {
+	cmd := newRichMenuDeployCmd()
+	for _, name := range []string{"file", "image", "alias", "default"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected --%s flag", name)
+		}
+	}
+}
+
+// Image validation tests
+
+func TestReadRichMenuImage_Validation(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		width   int
+		height  int
+		wantErr string
+	}{
+		{"full size", 2500, 1686, ""},
+		{"small compact", 800, 270, ""},
+		{"too wide", 3000, 2000, "width must be between 800 and 2500"},
+		{"too narrow", 600, 300, "width must be between 800 and 2500"},
+		{"too short", 2500, 200, "height must be at least 250"},
+		{"too square", 1200, 1000, "aspect ratio"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".png")
+			writeTestPNG(t, path, tt.width, tt.height)
+
+			_, _, err := readRichMenuImage(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReadRichMenuImage_ContentMismatch(t *testing.T) {
+	dir := t.TempDir()
+
+	notImage := filepath.Join(dir, "fake.png")
+	if err := os.WriteFile(notImage, []byte("\x89PNG fake"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readRichMenuImage(notImage); err == nil || !strings.Contains(err.Error(), "could not decode") {
+		t.Errorf("expected decode error, got: %v", err)
+	}
+
+	// PNG data behind a .jpg extension
+	mislabeled := filepath.Join(dir, "menu.jpg")
+	writeTestPNG(t, mislabeled, 2500, 843)
+	if _, _, err := readRichMenuImage(mislabeled); err == nil || !strings.Contains(err.Error(), "image content is png") {
+		t.Errorf("expected content mismatch error, got: %v", err)
+	}
+}
+
+func TestRichMenuUploadImageCmd_SizeMismatch(t *testing.T) {
+	uploads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(api.RichMenu{RichMenuID: "rm-1", Size: api.RichMenuSize{Width: 2500, Height: 1686}})
+			return
+		}
+		uploads++
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	imagePath := filepath.Join(t.TempDir(), "menu.png")
+	writeTestPNG(t, imagePath, 2500, 843)
+
+	cmd := newRichMenuUploadImageCmdWithClient(client, nil)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--id", "rm-1", "--image", imagePath})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "image is 2500x843 but the rich menu size is 2500x1686") {
+		t.Fatalf("expected size mismatch error, got: %v", err)
+	}
+	if uploads != 0 {
+		t.Errorf("expected no upload, got %d", uploads)
+	}
+}
+
+func TestRichMenuUploadImageCmd_AutoFit(t *testing.T) {
+	var uploaded []byte
+	var uploadedType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(api.RichMenu{RichMenuID: "rm-1", Size: api.RichMenuSize{Width: 1200, Height: 810}})
+			return
+		}
+		uploadedType = r.Header.Get("Content-Type")
+		uploaded, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	imagePath := filepath.Join(t.TempDir(), "design.png")
+	writeTestPNG(t, imagePath, 1600, 1600)
+
+	cmd := newRichMenuUploadImageCmdWithClient(client, nil)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--id", "rm-1", "--image", imagePath, "--auto-fit"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Auto-fit: resized from 1600x1600 to 1200x810, cropping the edges") {
+		t.Errorf("expected the resize to be reported, got: %s", out.String())
+	}
+	if uploadedType != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", uploadedType)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(uploaded))
+	if err != nil || cfg.Width != 1200 || cfg.Height != 810 {
+		t.Errorf("uploaded image is %dx%d (%v), want 1200x810", cfg.Width, cfg.Height, err)
+	}
+}
+
+func TestRichMenuUploadImageCmd_DryRunSkipsSizeCheck(t *testing.T) {
+	oldDryRun := flags.DryRun
+	defer func() { flags.DryRun = oldDryRun }()
+	flags.DryRun = true
+
+	client := api.NewClient("test-token", false, true)
+	imagePath := filepath.Join(t.TempDir(), "menu.png")
+	writeTestPNG(t, imagePath, 2500, 843)
+
+	for _, args := range [][]string{
+		{"--id", "richmenu-abc", "--image", imagePath},
+		{"--id", "richmenu-abc", "--image", imagePath, "--auto-fit"},
+	} {
+		cmd := newRichMenuUploadImageCmdWithClient(client, nil)
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Errorf("%v: unexpected error: %v", args, err)
+		}
+	}
+
+	// The local header checks still apply
+	small := filepath.Join(t.TempDir(), "small.png")
+	writeTestPNG(t, small, 400, 200)
+	cmd := newRichMenuUploadImageCmdWithClient(client, nil)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--id", "richmenu-abc", "--image", small})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "image width must be between 800 and 2500") {
+		t.Errorf("expected a width error, got %v", err)
+	}
+}
+
+func TestRichMenuDeployCmd_SizeMismatchFailsBeforeAPI(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	menuPath, imagePath := writeDeployFixtures(t)
+	writeTestPNG(t, imagePath, 1200, 810)
+
+	cmd := newRichMenuDeployCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--file", menuPath, "--image", imagePath})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "image is 1200x810") {
+		t.Fatalf("expected size mismatch error, got: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no API requests, got %d", requests)
+	}
+}
+
+// Linked command tests
+
+func newLinkedTestServer(t *testing.T, userMenu string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/bot/user/U123/richmenu":
+			if userMenu == "" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"the user has no richmenu"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": userMenu})
+		case "/v2/bot/user/all/richmenu":
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": "rm-default"})
+		case "/v2/bot/richmenu/alias/list":
+			_ = json.NewEncoder(w).Encode(map[string]any{"aliases": []map[string]string{
+				{"richMenuAliasId": "vip", "richMenuId": "rm-vip"},
+				{"richMenuAliasId": "main", "richMenuId": "rm-default"},
+			}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]string{"richMenuId": strings.TrimPrefix(r.URL.Path, "/v2/bot/richmenu/"), "name": "Menu " + strings.TrimPrefix(r.URL.Path, "/v2/bot/richmenu/")})
+		}
+	}))
+}
+
+func TestRichMenuLinkedCmd_UserLink(t *testing.T) {
+	server := newLinkedTestServer(t, "rm-vip")
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	cmd := newRichMenuLinkedCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--user", "U123"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"User U123 sees rich menu rm-vip (Menu rm-vip)", "Source: linked to user", "Aliases: vip"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestRichMenuLinkedCmd_FallsBackToDefault(t *testing.T) {
+	server := newLinkedTestServer(t, "")
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	cmd := newRichMenuLinkedCmdWithClient(client)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--user", "U123"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		RichMenuID string   `json:"richMenuId"`
+		Source     string   `json:"source"`
+		Aliases    []string `json:"aliases"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if result.RichMenuID != "rm-default" || result.Source != "default" || len(result.Aliases) != 1 || result.Aliases[0] != "main" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -27,20 +28,26 @@ func newRoomMembersCmd() *cobra.Command {
 
 func newRoomMembersCmdWithClient(client *api.Client) *cobra.Command {
 	var roomID string
-	var all bool
+	var page pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "members",
 		Short: "List room members",
-		Long:  "Get member count and list of user IDs in a room.",
+		Long: `Get the member count of a room, and with --all, --limit, or --cursor
+the user IDs of its members. When more member IDs are available, text
+output ends with a "Next cursor" line and JSON output includes the "next"
+cursor; pass it back with --cursor to continue.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if roomID == "" {
 				return fmt.Errorf("--id is required")
 			}
+			opts, err := page.options(0, 0)
+			if err != nil {
+				return err
+			}
 
 			c := client
 			if c == nil {
-				var err error
 				c, err = newAPIClient()
 				if err != nil {
 					return err
@@ -53,26 +60,28 @@ func newRoomMembersCmdWithClient(client *api.Client) *cobra.Command {
 			}
 
 			var allMemberIDs []string
-			if all {
-				var next string
-				for {
-					resp, err := c.GetRoomMemberIDs(cmd.Context(), roomID, next)
+			var next string
+			if page.listing() {
+				allMemberIDs, next, err = api.Paginate(cmd.Context(), opts, func(ctx context.Context, cursor string, _ int) ([]string, string, error) {
+					resp, err := c.GetRoomMemberIDs(ctx, roomID, cursor)
 					if err != nil {
-						return fmt.Errorf("failed to get member IDs: %w", err)
-					}
-					allMemberIDs = append(allMemberIDs, resp.MemberIDs...)
-					if resp.Next == "" {
-						break
+						return nil, "", fmt.Errorf("failed to get member IDs: %w", err)
 					}
-					next = resp.Next
+					return resp.MemberIDs, resp.Next, nil
+				})
+				if err != nil {
+					return err
 				}
 			}
 
 			if flags.Output == "json" {
 				result := map[string]any{"roomId": roomID, "count": count}
-				if all {
+				if page.listing() {
 					result["memberIds"] = allMemberIDs
 				}
+				if next != "" {
+					result["next"] = next
+				}
 				enc := json.NewEncoder(cmd.OutOrStdout())
 				enc.SetIndent("", "  ")
 				return enc.Encode(result)
@@ -80,17 +89,16 @@ func newRoomMembersCmdWithClient(client *api.Client) *cobra.Command {
 
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Room ID:  %s\n", roomID)
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Members:  %d\n", count)
-			if all {
-				for _, id := range allMemberIDs {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), id)
-				}
+			for _, id := range allMemberIDs {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), id)
 			}
+			printNextCursor(cmd.OutOrStdout(), next)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&roomID, "id", "", "Room ID (required)")
-	cmd.Flags().BoolVar(&all, "all", false, "List all member IDs")
+	addPageFlags(cmd, &page, "member IDs")
 	_ = cmd.MarkFlagRequired("id")
 
 	return cmd
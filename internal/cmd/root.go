@@ -3,19 +3,77 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 type rootFlags struct {
 	Account string
-	Output  string
-	Debug   bool
-	DryRun  bool // show what would be sent without actually sending
+	// AccountSet is true when --account was given on the command line,
+	// rather than taken from LINE_ACCOUNT or the config file
+	AccountSet bool
+	Output     string
+	Debug      bool
+	DryRun     bool // show what would be sent without actually sending
 	// Agent-friendly flags
 	Yes bool // skip confirmation prompts
+	// NoPager disables paging of long text and table output
+	NoPager bool
+	// Color is when to color output: auto, always, or never; NoColor is
+	// short for never
+	Color   string
+	NoColor bool
+	// Columns selects table columns, MaxColWidth caps their width, and
+	// NoTruncate shows long values in full
+	Columns     []string
+	MaxColWidth int
+	NoTruncate  bool
+	// Query selects fields from the JSON output, e.g. .richmenus[].richMenuId
+	Query string
+	// MaxRetries is how many times API requests failing with 429 or 5xx are
+	// repeated, and RetryBackoff the delay before the first retry
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// RateLimits override the client-side rate limits: "200/s" for every
+	// endpoint class, "multicast=50/s" for one, or "off"
+	RateLimits []string
+	// DebugHTTP traces every HTTP request, DebugHTTPBodies adds bodies to
+	// the trace, and NoRedact shows credentials and user IDs in it
+	DebugHTTP       bool
+	DebugHTTPBodies bool
+	NoRedact        bool
+	// Record saves API requests and responses as fixtures in this
+	// directory, and Replay answers requests from them without network
+	Record string
+	Replay string
+	// CacheTTL serves read-only API calls from an on-disk cache for this
+	// long (0 disables), and NoCache bypasses it for one command
+	CacheTTL time.Duration
+	NoCache  bool
+	// Proxy and CACert route API requests through a proxy and trust its CA,
+	// and InsecureSkipVerify turns off TLS certificate verification
+	Proxy              string
+	CACert             string
+	InsecureSkipVerify bool
+	// Timeout bounds each API request and ConnectTimeout each connection
+	// to the API (0 disables either)
+	Timeout        time.Duration
+	ConnectTimeout time.Duration
+	// BaseURL replaces https://api.line.me, e.g. for "line mock serve"
+	BaseURL string
+	// LogLevel, LogFormat, and LogFile set which diagnostics are logged,
+	// as text or JSON records, and the file they are appended to
+	LogLevel  string
+	LogFormat string
+	LogFile   string
 }
 
 var flags rootFlags
@@ -36,25 +94,98 @@ func NewRootCmd() *cobra.Command {
 Manage messaging, rich menus, audiences, and insights for your
 LINE Official Account - built for both humans and AI agents.`,
 		SilenceUsage: true,
+		// Execute reports errors, as JSON with --output json
+		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			startCommandTimer()
+			stdin = cmd.InOrStdin()
+			flags.AccountSet = cmd.Flags().Changed("account")
+			if flags.MaxRetries < 0 || flags.RetryBackoff < 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--max-retries and --retry-backoff cannot be negative"))
+			}
+			if flags.CacheTTL < 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--cache-ttl cannot be negative"))
+			}
+			if flags.Timeout < 0 || flags.ConnectTimeout < 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--timeout and --connect-timeout cannot be negative"))
+			}
+			if flags.BaseURL != "" {
+				if u, err := url.Parse(flags.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return withExitCode(ExitUsage, fmt.Errorf("invalid --base-url %q: must be an http or https URL", flags.BaseURL))
+				}
+			}
+			switch flags.Color {
+			case output.ColorAuto, output.ColorAlways, output.ColorNever:
+			default:
+				return fmt.Errorf("invalid --color %q: must be auto, always, or never", flags.Color)
+			}
+			if flags.NoColor {
+				flags.Color = output.ColorNever
+			}
+			if err := setupLogging(cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			if err := applyTimezone(configuredTimezone()); err != nil {
+				return err
+			}
+			if err := setupNetwork(cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			if err := installOutputFormat(cmd); err != nil {
+				return err
+			}
+			installPager(cmd)
 			return nil
 		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return closeOutputFormat()
+		},
 	}
 
 	// Priority: flags > env vars > config file > defaults
 	cmd.PersistentFlags().StringVar(&flags.Account, "account", getDefault(os.Getenv("LINE_ACCOUNT"), cfg.Account, ""), "Account name (or LINE_ACCOUNT env)")
-	cmd.PersistentFlags().StringVar(&flags.Output, "output", getDefault(os.Getenv("LINE_OUTPUT"), cfg.Output, "text"), "Output format: text|json|table")
-	cmd.PersistentFlags().BoolVar(&flags.Debug, "debug", getDefaultBool(cfg.Debug, false), "Enable debug output")
+	cmd.PersistentFlags().StringVar(&flags.Output, "output", getDefault(os.Getenv("LINE_OUTPUT"), cfg.Output, "text"), "Output format: text|json|table|yaml|csv|ndjson")
+	cmd.PersistentFlags().BoolVar(&flags.Debug, "debug", envBool("LINE_DEBUG", cfg.Debug), "Enable debug output (or LINE_DEBUG env)")
+	cmd.PersistentFlags().StringVar(&flags.LogLevel, "log-level", os.Getenv("LINE_LOG_LEVEL"), "Log debug, info, warn, or error records and above (default warn, or debug with --debug; or LINE_LOG_LEVEL env)")
+	cmd.PersistentFlags().StringVar(&flags.LogFormat, "log-format", getDefault(os.Getenv("LINE_LOG_FORMAT"), "text"), "Log record format: text|json (or LINE_LOG_FORMAT env)")
+	cmd.PersistentFlags().StringVar(&flags.LogFile, "log-file", os.Getenv("LINE_LOG_FILE"), "Append log records to this file instead of stderr (or LINE_LOG_FILE env)")
+	cmd.PersistentFlags().BoolVar(&flags.DebugHTTP, "debug-http", false, "Trace every HTTP request to stderr: method, URL, status, latency, and request ID")
+	cmd.PersistentFlags().BoolVar(&flags.DebugHTTPBodies, "debug-http-bodies", false, "Include request and response bodies in the --debug-http trace")
+	cmd.PersistentFlags().BoolVar(&flags.NoRedact, "no-redact", false, "Show Authorization headers, tokens, and user IDs in the --debug-http trace")
+	cmd.PersistentFlags().StringVar(&flags.Record, "record", "", "Save API requests and responses as fixtures in this directory")
+	cmd.PersistentFlags().StringVar(&flags.Replay, "replay", "", "Answer API requests from fixtures in this directory, without network or credentials")
+	cmd.PersistentFlags().DurationVar(&flags.CacheTTL, "cache-ttl", envDuration("LINE_CACHE_TTL", 0), "Serve read-only API calls (bot info, rich menu and audience lists, ...) from a local cache this long (or LINE_CACHE_TTL env)")
+	cmd.PersistentFlags().BoolVar(&flags.NoCache, "no-cache", false, "Bypass the --cache-ttl response cache")
 	cmd.PersistentFlags().BoolVar(&flags.DryRun, "dry-run", false, "Show what would be sent without actually sending")
-	cmd.PersistentFlags().BoolVarP(&flags.Yes, "yes", "y", false, "Skip confirmation prompts")
+	cmd.PersistentFlags().BoolVarP(&flags.Yes, "yes", "y", envBool("LINE_ASSUME_YES", false), "Skip confirmation prompts (or LINE_ASSUME_YES env)")
+	cmd.PersistentFlags().BoolVar(&flags.NoPager, "no-pager", false, "Do not pipe long output into a pager")
+	cmd.PersistentFlags().IntVar(&flags.MaxRetries, "max-retries", 3, "Retry API requests failing with 429 or 5xx this many times (0 disables)")
+	cmd.PersistentFlags().DurationVar(&flags.RetryBackoff, "retry-backoff", 500*time.Millisecond, "Delay before the first retry, doubled for each further retry (Retry-After takes precedence)")
+	cmd.PersistentFlags().StringArrayVar(&flags.RateLimits, "rate-limit", nil, "Client-side request rate, e.g. 100/s for all endpoints, multicast=50/s for one class, or off (repeatable)")
+	cmd.PersistentFlags().DurationVar(&flags.Timeout, "timeout", envDuration("LINE_TIMEOUT", 30*time.Second), "Give up on an API request without a full response after this long, per attempt (0 disables; or LINE_TIMEOUT env)")
+	cmd.PersistentFlags().DurationVar(&flags.ConnectTimeout, "connect-timeout", envDuration("LINE_CONNECT_TIMEOUT", 10*time.Second), "Give up connecting to the API after this long, including the TLS handshake (0 disables; or LINE_CONNECT_TIMEOUT env)")
+	cmd.PersistentFlags().StringVar(&flags.BaseURL, "base-url", getDefault(os.Getenv("LINE_API_BASE_URL"), cfg.APIBaseURL, ""), "Send API requests to this base URL instead of https://api.line.me, e.g. 'line mock serve' (or LINE_API_BASE_URL env)")
+	cmd.PersistentFlags().StringVar(&flags.Proxy, "proxy", cfg.Proxy, "Send API requests through this proxy URL (default HTTPS_PROXY and NO_PROXY env)")
+	cmd.PersistentFlags().StringVar(&flags.CACert, "ca-cert", cfg.CACert, "Also trust the CA certificates in this PEM file, e.g. a TLS-intercepting proxy's")
+	cmd.PersistentFlags().BoolVar(&flags.InsecureSkipVerify, "insecure-skip-verify", false, "Do not verify TLS certificates (unsafe; prefer --ca-cert)")
+	cmd.PersistentFlags().StringVar(&flags.Query, "query", "", "Print only these fields of the JSON output (e.g. '.richmenus[].richMenuId')")
+	cmd.PersistentFlags().StringSliceVar(&flags.Columns, "columns", nil, "Show only these table columns, in this order (e.g. id,name)")
+	cmd.PersistentFlags().IntVar(&flags.MaxColWidth, "max-col-width", defaultMaxColumnWidth, "Truncate table columns wider than this many characters")
+	cmd.PersistentFlags().BoolVar(&flags.NoTruncate, "no-truncate", false, "Show long table values in full")
+	cmd.PersistentFlags().StringVar(&flags.Color, "color", getDefault(os.Getenv("LINE_COLOR"), cfg.Color, "auto"), "Color output: auto|always|never (or LINE_COLOR env)")
+	cmd.PersistentFlags().BoolVar(&flags.NoColor, "no-color", false, "Disable colored output (same as --color never)")
 
 	// Add subcommands
 	cmd.AddCommand(newMessageCmd())
 	cmd.AddCommand(newRichMenuCmd())
 	cmd.AddCommand(newAudienceCmd())
+	cmd.AddCommand(newFollowersCmd())
 	cmd.AddCommand(newInsightCmd())
+	cmd.AddCommand(newDashboardCmd())
 	cmd.AddCommand(newAuthCmd())
+	cmd.AddCommand(newAccountCmd())
 	cmd.AddCommand(newBotCmd())
+	cmd.AddCommand(newSettingsCmd())
 	cmd.AddCommand(newWebhookCmd())
 	cmd.AddCommand(newContentCmd())
 	cmd.AddCommand(newGroupCmd())
@@ -63,13 +194,27 @@ LINE Official Account - built for both humans and AI agents.`,
 	cmd.AddCommand(newCouponCmd())
 	cmd.AddCommand(newTokenCmd())
 	cmd.AddCommand(newChatCmd())
+	cmd.AddCommand(newStickerCmd())
 	cmd.AddCommand(newLIFFCmd())
 	cmd.AddCommand(newModuleCmd())
 	cmd.AddCommand(newShopCmd())
 	cmd.AddCommand(newPNPCmd())
+	cmd.AddCommand(newApproveCmd())
+	cmd.AddCommand(newSendCmd())
+	cmd.AddCommand(newCampaignCmd())
+	cmd.AddCommand(newSchedulerCmd())
+	cmd.AddCommand(newPurgeCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newMockCmd())
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newCompletionCmd())
 	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newCacheCmd())
+	cmd.AddCommand(newAPICmd())
+	cmd.AddCommand(newMetaCmd())
+	cmd.AddCommand(newReplCmd())
+
+	markUsageErrors(cmd)
 
 	return cmd
 }
@@ -93,6 +238,54 @@ func getDefaultBool(cfgVal, fallback bool) bool {
 	return fallback
 }
 
+// envBool returns the boolean value of the environment variable name if it
+// is set to one, otherwise cfgVal.
+func envBool(name string, cfgVal bool) bool {
+	if b, err := strconv.ParseBool(os.Getenv(name)); err == nil {
+		return b
+	}
+	return cfgVal
+}
+
+// envDuration returns the duration in the environment variable name if it
+// is set to one, otherwise fallback.
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return d
+	}
+	return fallback
+}
+
+// configuredTimezone returns the time zone from LINE_TIMEZONE or the config
+// file, or "" for local time.
+func configuredTimezone() string {
+	return getDefault(os.Getenv("LINE_TIMEZONE"), cfg.Timezone)
+}
+
+// configuredAPIBaseURL returns the API base URL from --base-url,
+// LINE_API_BASE_URL, or the config file, or "" for the LINE API.
+func configuredAPIBaseURL() string {
+	var fromConfig string
+	if cfg != nil {
+		fromConfig = cfg.APIBaseURL
+	}
+	return strings.TrimRight(getDefault(flags.BaseURL, os.Getenv("LINE_API_BASE_URL"), fromConfig), "/")
+}
+
+// applyTimezone makes name the local time zone, so times are shown and
+// parsed in it. An empty name keeps the system zone.
+func applyTimezone(name string) error {
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	time.Local = loc
+	return nil
+}
+
 func requireAccount(f *rootFlags) (string, error) {
 	// 1. Check explicit flag (already includes env var from flag default)
 	if f.Account != "" {
@@ -111,17 +304,44 @@ func requireAccount(f *rootFlags) (string, error) {
 	}
 
 	// 3. No accounts configured
-	return "", fmt.Errorf("no accounts configured. Run: line auth login")
+	return "", withExitCode(ExitAuth, fmt.Errorf("no accounts configured. Run: line auth login"))
 }
 
 func Execute(args []string) error {
 	cmd := NewRootCmd()
 	cmd.SetArgs(args)
-	return cmd.Execute()
+	defer closePager()
+	c, err := cmd.ExecuteC()
+	if ferr := closeOutputFormat(); err == nil {
+		err = ferr
+	}
+	return finishCommand(os.Stderr, c, err)
+}
+
+// finishCommand reports the outcome of a command run: its timing budget,
+// its error, and for logs sent elsewhere than the terminal, an error record.
+func finishCommand(stderr io.Writer, c *cobra.Command, err error) error {
+	checkCommandBudget(stderr, c)
+	if err != nil {
+		err = planError(err)
+		reportError(stderr, c, err)
+		if !logForTerminal && c != nil {
+			logger.Error("command failed", "command", c.CommandPath(), "exitCode", ExitCode(err), "error", err.Error())
+		}
+	}
+	if lerr := closeLogging(); err == nil {
+		err = lerr
+	}
+	return err
 }
 
 func ExecuteContext(ctx context.Context, args []string) error {
 	cmd := NewRootCmd()
 	cmd.SetArgs(args)
-	return cmd.ExecuteContext(ctx)
+	defer closePager()
+	c, err := cmd.ExecuteContextC(ctx)
+	if ferr := closeOutputFormat(); err == nil {
+		err = ferr
+	}
+	return finishCommand(os.Stderr, c, err)
 }
@@ -3,7 +3,14 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
 )
 
 func TestGetDefault(t *testing.T) {
@@ -160,6 +167,18 @@ func TestNewRootCmd_FlagsExist(t *testing.T) {
 	if yesFlag.Shorthand != "y" {
 		t.Errorf("expected --yes shorthand to be 'y', got %q", yesFlag.Shorthand)
 	}
+
+	if cmd.PersistentFlags().Lookup("no-pager") == nil {
+		t.Error("expected --no-pager flag")
+	}
+	if cmd.PersistentFlags().Lookup("color") == nil {
+		t.Error("expected --color flag")
+	}
+	for _, name := range []string{"max-retries", "retry-backoff"} {
+		if cmd.PersistentFlags().Lookup(name) == nil {
+			t.Errorf("expected --%s flag", name)
+		}
+	}
 }
 
 func TestExecute_HelpCommand(t *testing.T) {
@@ -208,3 +227,105 @@ func TestNewRootCmd_Execute(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestNewRootCmd_Precedence(t *testing.T) {
+	writeTestConfig(t, "output: table\ndebug: true\ncolor: never\n")
+	t.Setenv("LINE_OUTPUT", "")
+	t.Setenv("LINE_COLOR", "always")
+	t.Setenv("LINE_DEBUG", "false")
+
+	cmd := NewRootCmd()
+	if err := cmd.ParseFlags([]string{"--color", "auto"}); err != nil {
+		t.Fatal(err)
+	}
+	if flags.Output != "table" {
+		t.Errorf("Output = %q, want the config file value", flags.Output)
+	}
+	if flags.Debug {
+		t.Error("expected LINE_DEBUG=false to override the config file")
+	}
+	if flags.Color != "auto" {
+		t.Errorf("Color = %q, want the flag value over LINE_COLOR", flags.Color)
+	}
+}
+
+func TestNewRootCmd_InvalidColor(t *testing.T) {
+	_, err := runConfigTestCmd(t, "--color", "rainbow", "version")
+	if err == nil || !strings.Contains(err.Error(), "invalid --color") {
+		t.Errorf("expected color error, got %v", err)
+	}
+}
+
+func TestNewRootCmd_NoColor(t *testing.T) {
+	writeTestConfig(t, "")
+	t.Setenv("LINE_COLOR", "always")
+	if _, err := runConfigTestCmd(t, "--no-color", "version"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flags.Color != "never" {
+		t.Errorf("Color = %q, want never with --no-color", flags.Color)
+	}
+}
+
+func TestApplyTimezone(t *testing.T) {
+	old := time.Local
+	t.Cleanup(func() { time.Local = old })
+
+	if err := applyTimezone("Asia/Tokyo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := time.Unix(0, 0).Format("15:04 MST"); got != "09:00 JST" {
+		t.Errorf("got %q", got)
+	}
+	if err := applyTimezone("Mars/Olympus"); err == nil {
+		t.Error("expected error for unknown zone")
+	}
+}
+
+func TestNewRootCmd_NegativeRetries(t *testing.T) {
+	writeTestConfig(t, "")
+	_, err := runConfigTestCmd(t, "--max-retries", "-1", "version")
+	if ExitCode(err) != ExitUsage {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
+
+func TestNewAPIClient_UsesConfiguredBaseURLAndRetries(t *testing.T) {
+	var gotPath string
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"userId":"U1","basicId":"@shop","displayName":"Shop","chatMode":"bot","markAsReadMode":"auto"}`))
+	}))
+	defer server.Close()
+
+	writeTestConfig(t, "")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("LINE_API_BASE_URL", server.URL+"/")
+	t.Setenv("LINE_CREDENTIALS_PASSPHRASE", "")
+	cfg = &config.Config{CredentialStore: "file"}
+
+	store, err := openSecretsStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("shop", secrets.Credentials{ChannelAccessToken: "token"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	flags = rootFlags{Account: "shop", MaxRetries: 1, RetryBackoff: time.Millisecond}
+
+	client, err := newAPIClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetBotInfo(context.Background()); err != nil {
+		t.Fatalf("GetBotInfo: %v", err)
+	}
+	if gotPath != "/v2/bot/info" || calls != 2 {
+		t.Errorf("path = %q after %d calls, want a retry of the 503", gotPath, calls)
+	}
+}
@@ -0,0 +1,548 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+// parseScheduledMessages reads the messages of a scheduled send from a
+// single message object, an array of messages, or a request body with a
+// "messages" array.
+func parseScheduledMessages(data []byte) ([]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	var messages []json.RawMessage
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("invalid messages JSON: %w", err)
+		}
+	} else {
+		var obj struct {
+			Type     string            `json:"type"`
+			Messages []json.RawMessage `json:"messages"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("invalid messages JSON: %w", err)
+		}
+		messages = obj.Messages
+		if obj.Type != "" {
+			messages = []json.RawMessage{data}
+		}
+	}
+
+	if len(messages) == 0 || len(messages) > 5 {
+		return nil, fmt.Errorf("a scheduled send takes 1 to 5 messages, got %d", len(messages))
+	}
+	for i, m := range messages {
+		var msg struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(m, &msg); err != nil || msg.Type == "" {
+			return nil, fmt.Errorf("message %d: expected an object with a \"type\"", i+1)
+		}
+	}
+	return messages, nil
+}
+
+// describeScheduledJob returns a one-line summary of the target of a job.
+func describeScheduledJob(job schedule.Job) string {
+	switch job.Type {
+	case "push":
+		return fmt.Sprintf("push to %s", job.To[0])
+	case "multicast":
+		return fmt.Sprintf("multicast to %d users", len(job.To))
+	case schedule.TypeCouponClose:
+		return fmt.Sprintf("close coupon %s", job.CouponID)
+	default:
+		return job.Type
+	}
+}
+
+func newMessageScheduleCmd() *cobra.Command {
+	var at string
+	var filePath string
+	var text string
+	var to []string
+	var broadcast bool
+
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Queue a message to send later",
+		Long: `Queue a push, multicast, or broadcast to be sent at a later time.
+
+The Messaging API cannot schedule messages, so jobs are kept in the config
+directory and sent by 'line scheduler run', which must be running (or run
+from cron with --once) when the job is due. A job is sent with the account
+that was active when it was scheduled.
+
+--at takes an RFC 3339 time, a local date and time, a day and time such as
+"tomorrow 09:00", or an offset from now such as +2h or +7d.
+
+--file holds a message object, an array of up to 5 messages, or a request
+body with a "messages" array.`,
+		Example: `  # Push a prepared message at 9am Tokyo time
+  line message schedule --at "2024-07-01T09:00+09:00" --file payload.json --to U1234567890abcdef
+
+  # Multicast a text message (times without an offset are local)
+  line message schedule --at "2024-07-01 09:00" --text "Sale starts now" --to U123,U456
+
+  # Schedule a push for tomorrow morning, local time
+  line message schedule --at "tomorrow 09:00" --text "Good morning" --to U1234567890abcdef
+
+  # Broadcast to all followers
+  line message schedule --at "2024-07-01T09:00+09:00" --file payload.json --broadcast --yes
+
+  # Review and cancel jobs
+  line message schedule list
+  line message schedule cancel --id 3f2a9c1e04b7`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if at == "" {
+				return fmt.Errorf("--at is required")
+			}
+			if err := requireExactlyOneFlag([]FlagCheck{
+				{"--file", filePath != ""},
+				{"--text", text != ""},
+			}); err != nil {
+				return err
+			}
+			if broadcast == (len(to) > 0) {
+				return fmt.Errorf("specify either --to or --broadcast")
+			}
+			to, err := expandRecipients(to)
+			if err != nil {
+				return err
+			}
+			if !broadcast && len(to) == 0 {
+				return fmt.Errorf("--to is required: specify a user ID")
+			}
+			if len(to) > maxMulticastRecipients {
+				return fmt.Errorf("maximum %d recipients allowed, got %d", maxMulticastRecipients, len(to))
+			}
+
+			when, err := parseFlagTime("--at", at)
+			if err != nil {
+				return err
+			}
+			if !when.After(clockNow()) {
+				return fmt.Errorf("--at %s is in the past", when.Format(time.RFC3339))
+			}
+
+			var messages []json.RawMessage
+			if filePath != "" {
+				data, err := readFileOrStdin(filePath)
+				if err != nil {
+					return fmt.Errorf("failed to read file: %w", err)
+				}
+				if messages, err = parseScheduledMessages(data); err != nil {
+					return err
+				}
+			} else {
+				msg, err := newTextMessage(text)
+				if err != nil {
+					return err
+				}
+				data, err := json.Marshal(msg)
+				if err != nil {
+					return fmt.Errorf("failed to marshal message: %w", err)
+				}
+				messages = []json.RawMessage{data}
+			}
+
+			sendType := "broadcast"
+			switch {
+			case len(to) == 1:
+				sendType = "push"
+			case len(to) > 1:
+				sendType = "multicast"
+			}
+			if sendType == "broadcast" && !flags.Yes {
+				if !askYesNo(cmd.OutOrStdout(), cmd.InOrStdin(), "This will broadcast to ALL followers when due. Continue?") {
+					return fmt.Errorf("schedule cancelled")
+				}
+			}
+
+			account, err := requireAccount(&flags)
+			if err != nil {
+				return err
+			}
+
+			store, err := schedule.Open()
+			if err != nil {
+				return err
+			}
+			job := schedule.Job{
+				ID:        strings.ReplaceAll(newUUID(), "-", "")[:12],
+				Account:   account,
+				Type:      sendType,
+				To:        to,
+				Messages:  messages,
+				At:        when,
+				CreatedAt: clockNow().UTC(),
+				Status:    schedule.StatusPending,
+			}
+			if err := store.Add(job); err != nil {
+				return fmt.Errorf("failed to schedule message: %w", err)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(job)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Scheduled %s for %s (%s)\n", job.ID, when.Format(time.RFC3339), describeScheduledJob(job))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&at, "at", "", "When to send, e.g. 2024-07-01T09:00+09:00, \"tomorrow 09:00\", or +2h (required)")
+	cmd.Flags().StringVarP(&filePath, "file", "f", "", "JSON file with the message(s) to send, or - for stdin")
+	cmd.Flags().StringVar(&text, "text", "", "Text message to send")
+	cmd.Flags().StringSliceVar(&to, "to", nil, "Recipient user ID(s), or @file of IDs; several make a multicast (max 500)")
+	cmd.Flags().BoolVar(&broadcast, "broadcast", false, "Broadcast to all followers")
+
+	cmd.AddCommand(newMessageScheduleListCmd())
+	cmd.AddCommand(newMessageScheduleCancelCmd())
+
+	return cmd
+}
+
+func newMessageScheduleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List scheduled messages",
+		Long: `List the jobs waiting to be sent by 'line scheduler run', soonest first.
+Failed jobs are kept, with their error, until they are cancelled. Coupon
+closes are listed by 'line coupon schedule list'.`,
+		Example: `  line message schedule list
+  line message schedule list --output table`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jobs, err := listScheduledJobs(func(job schedule.Job) bool { return job.Type != schedule.TypeCouponClose })
+			if err != nil {
+				return fmt.Errorf("failed to list scheduled messages: %w", err)
+			}
+			return printScheduledJobs(cmd, jobs, "scheduled message")
+		},
+	}
+}
+
+// listScheduledJobs returns the jobs of the schedule store that keep
+// accepts.
+func listScheduledJobs(keep func(schedule.Job) bool) ([]schedule.Job, error) {
+	store, err := schedule.Open()
+	if err != nil {
+		return nil, err
+	}
+	all, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	var jobs []schedule.Job
+	for _, job := range all {
+		if keep(job) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// printScheduledJobs writes jobs in the output format; noun names one of
+// them in text output.
+func printScheduledJobs(cmd *cobra.Command, jobs []schedule.Job, noun string) error {
+	if flags.Output == "json" {
+		if jobs == nil {
+			jobs = []schedule.Job{}
+		}
+		result := map[string]any{"jobs": jobs}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if len(jobs) == 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No %ss\n", noun)
+		return nil
+	}
+
+	if flags.Output == "table" {
+		table := NewTable("ID", "AT", "TARGET", "STATUS", "ACCOUNT")
+		for _, job := range jobs {
+			table.AddRow(job.ID, job.At.Local().Format("2006-01-02 15:04"), describeScheduledJob(job), job.Status, job.Account)
+		}
+		table.Render(cmd.OutOrStdout())
+		return nil
+	}
+
+	// Default text output
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Found %d %s(s):\n\n", len(jobs), noun)
+	for _, job := range jobs {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID:       %s\n", job.ID)
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "At:       %s\n", job.At.Local().Format(time.RFC3339))
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Target:   %s\n", describeScheduledJob(job))
+		if len(job.Messages) > 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Messages: %d\n", len(job.Messages))
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Status:   %s\n", colorStatus(cmd.OutOrStdout(), job.Status))
+		if job.Error != "" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Error:    %s\n", job.Error)
+		}
+		if job.Account != "" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Account:  %s\n", job.Account)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout())
+	}
+	return nil
+}
+
+func newMessageScheduleCancelCmd() *cobra.Command {
+	var id string
+
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a scheduled message",
+		Long: `Remove a pending or failed job. A job that 'line scheduler run' is
+already sending cannot be cancelled.`,
+		Example: `  line message schedule cancel --id 3f2a9c1e04b7`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if id == "" {
+				return fmt.Errorf("--id is required")
+			}
+			store, err := schedule.Open()
+			if err != nil {
+				return err
+			}
+			job, err := store.Cancel(id)
+			if errors.Is(err, schedule.ErrNotFound) {
+				return fmt.Errorf("no pending scheduled message %s (see 'line message schedule list')", id)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to cancel scheduled message: %w", err)
+			}
+
+			if flags.Output == "json" {
+				result := map[string]any{"id": job.ID, "status": "cancelled"}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancelled %s (%s at %s)\n", job.ID, describeScheduledJob(job), job.At.Local().Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&id, "id", "", "ID of the scheduled message (required)")
+
+	return cmd
+}
+
+func newSchedulerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scheduler",
+		Short: "Run jobs queued with 'line message schedule' and 'line coupon schedule'",
+	}
+	cmd.AddCommand(newSchedulerRunCmd())
+	cmd.AddCommand(newSchedulerExportCmd())
+	cmd.AddCommand(newSchedulerImportCmd())
+	return cmd
+}
+
+func newSchedulerRunCmd() *cobra.Command {
+	return newSchedulerRunCmdWithClient(nil)
+}
+
+func newSchedulerRunCmdWithClient(client *api.Client) *cobra.Command {
+	var interval time.Duration
+	var once bool
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Send scheduled messages and close scheduled coupons as they fall due",
+		Long: `Run until interrupted, sending each scheduled message, and closing each
+scheduled coupon, once its time has come. Each job runs with the account it
+was scheduled under.
+
+Messages that fall due during a freeze window are held until the window
+ends; coupon closes are not. A job that fails is kept with its error and
+not retried; cancel it and schedule it again. Finished jobs are removed and
+recorded in the audit log.
+
+With --once, due jobs are sent and the command exits, for running from
+cron or a systemd timer instead of as a daemon.`,
+		Example: `  # Run as a daemon, checking every 30 seconds
+  line scheduler run
+
+  # Send whatever is due, then exit
+  line scheduler run --once`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			store, err := schedule.Open()
+			if err != nil {
+				return err
+			}
+
+			r := &schedulerRun{store: store, client: client, out: cmd.OutOrStdout(), held: map[string]bool{}}
+			for {
+				if err := r.sendDue(cmd); err != nil {
+					return err
+				}
+				if once {
+					return nil
+				}
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 30*time.Second, "How often to check for due messages")
+	cmd.Flags().BoolVar(&once, "once", false, "Send due messages and exit")
+
+	return cmd
+}
+
+// schedulerRun sends due jobs from a store.
+type schedulerRun struct {
+	store  *schedule.Store
+	client *api.Client // used for every job if set
+	out    io.Writer
+	// held are the jobs already reported as held by a freeze window
+	held map[string]bool
+}
+
+// sendDue sends every job that is due now. A failed send is recorded on
+// the job; only errors with the store itself are returned.
+func (r *schedulerRun) sendDue(cmd *cobra.Command) error {
+	jobs, err := r.store.List()
+	if err != nil {
+		return err
+	}
+	now := clockNow()
+	clients := map[string]*api.Client{}
+	for _, job := range jobs {
+		if !job.Due(now) {
+			continue
+		}
+		if cfg != nil && !cfg.Freeze.IsZero() && job.Type != schedule.TypeCouponClose {
+			active, err := activeFreeze(cfg.Freeze, job.Type, now)
+			if err != nil {
+				return fmt.Errorf("failed to evaluate freeze windows: %w", err)
+			}
+			if active != "" {
+				if !r.held[job.ID] {
+					r.held[job.ID] = true
+					r.report(job, "held", fmt.Sprintf("freeze window (%s)", active))
+				}
+				continue
+			}
+		}
+
+		job, err := r.store.Claim(job.ID)
+		if errors.Is(err, schedule.ErrNotFound) {
+			continue // cancelled or claimed by another scheduler
+		}
+		if err != nil {
+			return err
+		}
+		delete(r.held, job.ID)
+
+		sendErr := r.send(cmd, clients, job)
+		if err := r.store.Finish(job, sendErr); err != nil {
+			return err
+		}
+		if sendErr != nil {
+			r.report(job, "failed", sendErr.Error())
+			continue
+		}
+
+		entry := audit.Entry{
+			Time:    clockNow().UTC(),
+			Account: job.Account,
+			Action:  "schedule.send",
+			Details: map[string]any{
+				"id":         job.ID,
+				"type":       job.Type,
+				"recipients": len(job.To),
+				"messages":   len(job.Messages),
+				"at":         job.At,
+			},
+		}
+		status := "sent"
+		if job.Type == schedule.TypeCouponClose {
+			entry.Action = "schedule.coupon-close"
+			entry.Details = map[string]any{"id": job.ID, "couponId": job.CouponID, "at": job.At}
+			status = "done"
+		}
+		if err := audit.Append(entry); err != nil {
+			return fmt.Errorf("failed to record scheduled job: %w", err)
+		}
+		r.report(job, status, "")
+	}
+	return nil
+}
+
+// send runs a claimed job, creating a client for its account on first
+// use.
+func (r *schedulerRun) send(cmd *cobra.Command, clients map[string]*api.Client, job schedule.Job) error {
+	c := r.client
+	if c == nil {
+		c = clients[job.Account]
+	}
+	if c == nil {
+		var err error
+		if c, err = newAPIClientForAccount(job.Account); err != nil {
+			return err
+		}
+		clients[job.Account] = c
+	}
+
+	if job.Type == schedule.TypeCouponClose {
+		if err := c.CloseCoupon(cmd.Context(), job.CouponID); err != nil {
+			return fmt.Errorf("failed to close coupon: %w", err)
+		}
+		return nil
+	}
+
+	messages := make([]any, len(job.Messages))
+	for i, m := range job.Messages {
+		messages[i] = m
+	}
+	var userID string
+	if job.Type == "push" {
+		userID = job.To[0]
+	}
+	if err := c.SendMessages(cmd.Context(), job.Type, userID, job.To, messages); err != nil {
+		return fmt.Errorf("failed to send: %w", err)
+	}
+	return nil
+}
+
+// report writes one line about a job: text, or a JSON object per line in
+// JSON output mode.
+func (r *schedulerRun) report(job schedule.Job, status, detail string) {
+	if flags.Output == "json" {
+		result := map[string]any{"id": job.ID, "status": status, "type": job.Type, "at": job.At}
+		if detail != "" {
+			result["detail"] = detail
+		}
+		_ = json.NewEncoder(r.out).Encode(result)
+		return
+	}
+	line := fmt.Sprintf("%s %s: %s %s", clockNow().Format(time.RFC3339), job.ID, status, describeScheduledJob(job))
+	if detail != "" {
+		line += ": " + detail
+	}
+	_, _ = fmt.Fprintln(r.out, line)
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+)
+
+// setupSchedule isolates the schedule store and audit log, fixes the clock,
+// and selects an account.
+func setupSchedule(t *testing.T, now time.Time) *schedule.Store {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	oldNow, oldAccount, oldOutput, oldCfg := clockNow, flags.Account, flags.Output, cfg
+	t.Cleanup(func() { clockNow, flags.Account, flags.Output, cfg = oldNow, oldAccount, oldOutput, oldCfg })
+	clockNow = func() time.Time { return now }
+	flags.Account, flags.Output, cfg = "shop", "text", nil
+
+	store, err := schedule.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestParseScheduledMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr string
+	}{
+		{"object", `{"type":"text","text":"Hi"}`, 1, ""},
+		{"array", `[{"type":"text","text":"Hi"},{"type":"sticker","packageId":"446","stickerId":"1988"}]`, 2, ""},
+		{"request body", `{"to":"U123","messages":[{"type":"text","text":"Hi"}]}`, 1, ""},
+		{"too many", `[` + strings.TrimSuffix(strings.Repeat(`{"type":"text","text":"Hi"},`, 6), ",") + `]`, 0, "1 to 5 messages"},
+		{"no type", `[{"text":"Hi"}]`, 0, `message 1: expected an object with a "type"`},
+		{"invalid", `{`, 0, "invalid messages JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseScheduledMessages([]byte(tt.in))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || len(got) != tt.want {
+				t.Errorf("got %d messages, %v; want %d", len(got), err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageScheduleCmd_ListAndCancel(t *testing.T) {
+	store := setupSchedule(t, time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC))
+	payload := filepath.Join(t.TempDir(), "payload.json")
+	if err := os.WriteFile(payload, []byte(`{"type":"text","text":"Morning"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := newMessageScheduleCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--at", "2024-07-01T09:00+09:00", "--file", payload, "--to", "U123"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "for 2024-07-01T09:00:00+09:00 (push to U123)") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	jobs, err := store.List()
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %v, %v", jobs, err)
+	}
+	job := jobs[0]
+	var msg bytes.Buffer
+	_ = json.Compact(&msg, job.Messages[0])
+	if job.Account != "shop" || job.Type != "push" || job.Status != schedule.StatusPending || msg.String() != `{"type":"text","text":"Morning"}` {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	out.Reset()
+	cmd = newMessageScheduleCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list"})
+	flags.Output = "table"
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), job.ID) || !strings.Contains(out.String(), "pending") {
+		t.Errorf("expected job in list, got %q", out.String())
+	}
+	flags.Output = "text"
+
+	out.Reset()
+	cmd = newMessageScheduleCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"cancel", "--id", job.ID})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jobs, _ := store.List(); len(jobs) != 0 {
+		t.Errorf("expected job to be cancelled, got %+v", jobs)
+	}
+
+	cmd = newMessageScheduleCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"cancel", "--id", job.ID})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "no pending scheduled message") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestMessageScheduleCmd_Validation(t *testing.T) {
+	setupSchedule(t, time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no at", []string{"--text", "hi", "--to", "U1"}, "--at is required"},
+		{"past", []string{"--at", "2024-07-01T09:00+09:00", "--text", "hi", "--to", "U1"}, "is in the past"},
+		{"no target", []string{"--at", "2024-07-03T09:00+09:00", "--text", "hi"}, "specify either --to or --broadcast"},
+		{"both targets", []string{"--at", "2024-07-03T09:00+09:00", "--text", "hi", "--to", "U1", "--broadcast"}, "specify either --to or --broadcast"},
+		{"no message", []string{"--at", "2024-07-03T09:00+09:00", "--to", "U1"}, "--file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newMessageScheduleCmd()
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchedulerRunCmd_SendsDueJobs(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	store := setupSchedule(t, now)
+	for _, job := range []schedule.Job{
+		{ID: "due", Type: "multicast", To: []string{"U1", "U2"}, At: now.Add(-time.Minute),
+			Messages: []json.RawMessage{json.RawMessage(`{"type":"text","text":"One"}`), json.RawMessage(`{"type":"text","text":"Two"}`)}},
+		{ID: "later", Type: "push", To: []string{"U1"}, At: now.Add(time.Hour),
+			Messages: []json.RawMessage{json.RawMessage(`{"type":"text","text":"Later"}`)}},
+	} {
+		if err := store.Add(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var paths []string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var out bytes.Buffer
+	cmd := newSchedulerRunCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--once"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(paths) != 1 || paths[0] != "/v2/bot/message/multicast" {
+		t.Fatalf("expected one multicast, got %v", paths)
+	}
+	if len(body["to"].([]any)) != 2 || len(body["messages"].([]any)) != 2 {
+		t.Errorf("unexpected request body %v", body)
+	}
+	if !strings.Contains(out.String(), "due: sent multicast to 2 users") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	jobs, _ := store.List()
+	if len(jobs) != 1 || jobs[0].ID != "later" {
+		t.Errorf("expected only the later job to remain, got %+v", jobs)
+	}
+}
+
+func TestSchedulerRunCmd_FailedAndFrozenJobs(t *testing.T) {
+	now := time.Date(2024, 7, 1, 23, 0, 0, 0, time.UTC)
+	store := setupSchedule(t, now)
+	for _, job := range []schedule.Job{
+		{ID: "push", Type: "push", To: []string{"U1"}, At: now, Messages: []json.RawMessage{json.RawMessage(`{"type":"text","text":"Hi"}`)}},
+		{ID: "broadcast", Type: "broadcast", At: now, Messages: []json.RawMessage{json.RawMessage(`{"type":"text","text":"All"}`)}},
+	} {
+		if err := store.Add(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg = &config.Config{Freeze: config.FreezeConfig{
+		Timezone: "UTC",
+		Windows:  []config.FreezeWindow{{Start: "22:00", End: "08:00", Sends: []string{"broadcast"}}},
+	}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"The property, 'to', in the request body is invalid"}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var out bytes.Buffer
+	cmd := newSchedulerRunCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--once"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jobs, _ := store.List()
+	status := map[string]schedule.Job{}
+	for _, j := range jobs {
+		status[j.ID] = j
+	}
+	if j := status["broadcast"]; j.Status != schedule.StatusPending {
+		t.Errorf("expected frozen broadcast to stay pending, got %+v", j)
+	}
+	if j := status["push"]; j.Status != schedule.StatusFailed || !strings.Contains(j.Error, "invalid") {
+		t.Errorf("expected push to be failed with its error, got %+v", j)
+	}
+	if !strings.Contains(out.String(), "broadcast: held broadcast: freeze window") {
+		t.Errorf("expected held report, got %q", out.String())
+	}
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// scheduleManifest is the YAML form of the pending scheduled jobs, written
+// by 'scheduler export' and read by 'scheduler import'.
+type scheduleManifest struct {
+	Jobs []manifestJob `yaml:"jobs"`
+}
+
+// manifestJob is a scheduled job in a manifest. Messages are kept as YAML
+// so they read like the rest of the file in a review.
+type manifestJob struct {
+	ID       string      `yaml:"id"`
+	Account  string      `yaml:"account,omitempty"`
+	Type     string      `yaml:"type"`
+	At       time.Time   `yaml:"at"`
+	To       []string    `yaml:"to,omitempty"`
+	CouponID string      `yaml:"couponId,omitempty"`
+	Messages []yaml.Node `yaml:"messages,omitempty"`
+}
+
+// newManifestJob converts a job for a manifest.
+func newManifestJob(job schedule.Job) (manifestJob, error) {
+	m := manifestJob{
+		ID:       job.ID,
+		Account:  job.Account,
+		Type:     job.Type,
+		At:       job.At,
+		To:       job.To,
+		CouponID: job.CouponID,
+	}
+	for _, raw := range job.Messages {
+		// JSON is YAML, so the message parses as is; dropping the flow
+		// style writes it as block YAML
+		var doc yaml.Node
+		if err := yaml.Unmarshal(raw, &doc); err != nil || len(doc.Content) != 1 {
+			return manifestJob{}, fmt.Errorf("job %s has an invalid message", job.ID)
+		}
+		blockStyle(doc.Content[0])
+		m.Messages = append(m.Messages, *doc.Content[0])
+	}
+	return m, nil
+}
+
+func blockStyle(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode || n.Kind == yaml.SequenceNode || n.Style == yaml.DoubleQuotedStyle {
+		n.Style = 0
+	}
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// job converts a manifest job back, checking it is one the scheduler can
+// run.
+func (m manifestJob) job() (schedule.Job, error) {
+	job := schedule.Job{
+		ID:       m.ID,
+		Account:  m.Account,
+		Type:     m.Type,
+		At:       m.At,
+		To:       m.To,
+		CouponID: m.CouponID,
+		Status:   schedule.StatusPending,
+	}
+	for _, n := range m.Messages {
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return job, fmt.Errorf("job %s: invalid message: %w", m.ID, err)
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return job, fmt.Errorf("job %s: invalid message: %w", m.ID, err)
+		}
+		job.Messages = append(job.Messages, raw)
+	}
+
+	if m.ID == "" {
+		return job, fmt.Errorf("a job has no id")
+	}
+	if m.At.IsZero() {
+		return job, fmt.Errorf("job %s: at is required", m.ID)
+	}
+	switch m.Type {
+	case "push", "multicast", "broadcast":
+		if n := len(job.Messages); n == 0 || n > 5 {
+			return job, fmt.Errorf("job %s: a %s needs 1 to 5 messages, got %d", m.ID, m.Type, n)
+		}
+		switch {
+		case m.Type == "push" && len(m.To) != 1:
+			return job, fmt.Errorf("job %s: a push needs exactly one recipient in to", m.ID)
+		case m.Type == "multicast" && (len(m.To) == 0 || len(m.To) > maxMulticastRecipients):
+			return job, fmt.Errorf("job %s: a multicast needs 1 to %d recipients in to", m.ID, maxMulticastRecipients)
+		case m.Type == "broadcast" && len(m.To) > 0:
+			return job, fmt.Errorf("job %s: a broadcast has no recipients", m.ID)
+		}
+	case schedule.TypeCouponClose:
+		if m.CouponID == "" {
+			return job, fmt.Errorf("job %s: couponId is required", m.ID)
+		}
+	default:
+		return job, fmt.Errorf("job %s: unknown type %q (use push, multicast, broadcast, or %s)", m.ID, m.Type, schedule.TypeCouponClose)
+	}
+	return job, nil
+}
+
+// writeCrontab writes one crontab entry per job, firing 'line scheduler
+// run --once' at the job's time in the local timezone. Cron has no year,
+// so each entry fires yearly until it is removed.
+func writeCrontab(w io.Writer, jobs []schedule.Job) {
+	_, _ = fmt.Fprintf(w, "# line scheduler export: %d pending job(s), times in %s\n", len(jobs), time.Local)
+	_, _ = fmt.Fprintln(w, "# The jobs themselves stay in the scheduler store; import the YAML export")
+	_, _ = fmt.Fprintln(w, "# on another machine before installing these entries there.")
+	for _, job := range jobs {
+		at := job.At.In(time.Local)
+		_, _ = fmt.Fprintf(w, "\n# %s: %s as %s at %s\n", job.ID, describeScheduledJob(job), job.Account, at.Format(time.RFC3339))
+		_, _ = fmt.Fprintf(w, "%d %d %d %d * line scheduler run --once\n", at.Minute(), at.Hour(), at.Day(), int(at.Month()))
+	}
+}
+
+func newSchedulerExportCmd() *cobra.Command {
+	var format string
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export pending scheduled jobs as YAML or crontab entries",
+		Long: `Export the pending scheduled messages and coupon closes, so they can be
+reviewed in a pull request or mirrored to a backup machine with 'line
+scheduler import'. Failed jobs and jobs being sent are left out.
+
+--format yaml (the default) writes a manifest with every job and its
+messages. --format crontab writes one entry per job that runs 'line
+scheduler run --once' at the job's time in the local timezone, for
+machines that run the scheduler from cron; cron has no year, so remove
+an entry once its job has been sent.`,
+		Example: `  # Review the schedule in a pull request
+  line scheduler export > schedule.yaml
+
+  # Cron entries for the jobs
+  line scheduler export --format crontab`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "yaml" && format != "crontab" {
+				return withExitCode(ExitUsage, fmt.Errorf("invalid --format %q (use yaml or crontab)", format))
+			}
+			jobs, err := listScheduledJobs(func(job schedule.Job) bool { return job.Status == schedule.StatusPending })
+			if err != nil {
+				return fmt.Errorf("failed to list scheduled jobs: %w", err)
+			}
+
+			w := cmd.OutOrStdout()
+			if out != "" && out != "-" {
+				f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", out, err)
+				}
+				defer func() { _ = f.Close() }()
+				w = f
+			}
+
+			if format == "crontab" {
+				writeCrontab(w, jobs)
+				return nil
+			}
+			manifest := scheduleManifest{Jobs: []manifestJob{}}
+			for _, job := range jobs {
+				m, err := newManifestJob(job)
+				if err != nil {
+					return err
+				}
+				manifest.Jobs = append(manifest.Jobs, m)
+			}
+			enc := yaml.NewEncoder(w)
+			enc.SetIndent(2)
+			if err := enc.Encode(manifest); err != nil {
+				return fmt.Errorf("failed to write manifest: %w", err)
+			}
+			return enc.Close()
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "yaml", "Export format: yaml or crontab")
+	cmd.Flags().StringVar(&out, "out", "", "Write to this file instead of stdout")
+
+	return cmd
+}
+
+// scheduleImportResult is the JSON output of 'scheduler import'.
+type scheduleImportResult struct {
+	Imported []string             `json:"imported"`
+	Skipped  []scheduleImportSkip `json:"skipped"`
+	DryRun   bool                 `json:"dryRun,omitempty"`
+}
+
+type scheduleImportSkip struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+func newSchedulerImportCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import scheduled jobs from a YAML export",
+		Long: `Add the jobs of a manifest written by 'line scheduler export' to the local
+scheduler, for example to mirror a schedule to a backup machine.
+
+The manifest is checked as a whole before anything is added. Jobs already
+in the scheduler (by ID) are skipped, so importing the same manifest again
+is safe, and so are jobs whose time has passed, which would otherwise be
+sent at once. Jobs without an account are imported under the current one.
+Broadcasts ask for confirmation unless --yes is given.`,
+		Example: `  # Preview, then mirror the schedule
+  line scheduler import --file schedule.yaml --dry-run
+  line scheduler import --file schedule.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return withExitCode(ExitUsage, fmt.Errorf("--file is required"))
+			}
+			data, err := readFileOrStdin(file)
+			if err != nil {
+				return err
+			}
+			var manifest scheduleManifest
+			if err := yaml.Unmarshal(data, &manifest); err != nil {
+				return fmt.Errorf("invalid manifest: %w", err)
+			}
+
+			jobs := make([]schedule.Job, 0, len(manifest.Jobs))
+			seen := map[string]bool{}
+			broadcasts := 0
+			for _, m := range manifest.Jobs {
+				job, err := m.job()
+				if err != nil {
+					return fmt.Errorf("invalid manifest: %w", err)
+				}
+				if seen[job.ID] {
+					return fmt.Errorf("invalid manifest: job %s appears twice", job.ID)
+				}
+				seen[job.ID] = true
+				if job.Type == "broadcast" {
+					broadcasts++
+				}
+				jobs = append(jobs, job)
+			}
+
+			store, err := schedule.Open()
+			if err != nil {
+				return err
+			}
+			existing, err := store.List()
+			if err != nil {
+				return fmt.Errorf("failed to list scheduled jobs: %w", err)
+			}
+			have := map[string]bool{}
+			for _, job := range existing {
+				have[job.ID] = true
+			}
+
+			if broadcasts > 0 {
+				if err := confirmDestructive(cmd, "import", fmt.Sprintf("The manifest schedules %d broadcast(s) to ALL followers. Import?", broadcasts)); err != nil {
+					return err
+				}
+			}
+
+			now := clockNow()
+			result := scheduleImportResult{Imported: []string{}, Skipped: []scheduleImportSkip{}, DryRun: flags.DryRun}
+			for _, job := range jobs {
+				switch {
+				case have[job.ID]:
+					result.Skipped = append(result.Skipped, scheduleImportSkip{job.ID, "already scheduled"})
+					continue
+				case !job.At.After(now):
+					result.Skipped = append(result.Skipped, scheduleImportSkip{job.ID, "time has passed"})
+					continue
+				}
+				if job.Account == "" {
+					if job.Account, err = requireAccount(&flags); err != nil {
+						return err
+					}
+				}
+				job.CreatedAt = now.UTC()
+				if !flags.DryRun {
+					if err := store.Add(job); err != nil {
+						return fmt.Errorf("failed to import job %s: %w", job.ID, err)
+					}
+				}
+				result.Imported = append(result.Imported, job.ID)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			verb := "Imported"
+			if flags.DryRun {
+				verb = "Would import"
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %d job(s)", verb, len(result.Imported))
+			if len(result.Imported) > 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), ": %s", strings.Join(result.Imported, ", "))
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			for _, s := range result.Skipped {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Skipped %s: %s\n", s.ID, s.Reason)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Manifest written by 'line scheduler export', or - for stdin (required)")
+
+	return cmd
+}
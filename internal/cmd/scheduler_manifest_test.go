@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/schedule"
+)
+
+func TestSchedulerExportImport_RoundTrip(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := setupSchedule(t, now)
+	jobs := []schedule.Job{
+		{ID: "push1", Account: "shop", Type: "push", To: []string{"U1"}, At: now.Add(24 * time.Hour),
+			Messages: []json.RawMessage{json.RawMessage(`{"type":"text","text":"yes"}`), json.RawMessage(`{"type":"sticker","packageId":"446","stickerId":"1988","n":1704067200000}`)}},
+		{ID: "close1", Account: "shop", Type: schedule.TypeCouponClose, CouponID: "coupon-1", At: now.Add(48 * time.Hour)},
+	}
+	for _, job := range jobs {
+		if err := store.Add(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	cmd := newSchedulerExportCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	manifest := out.String()
+	for _, want := range []string{"id: push1", "type: coupon-close", "packageId: \"446\"", "n: 1704067200000"} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("expected %q in manifest:\n%s", want, manifest)
+		}
+	}
+
+	// Import into an empty store, as on a backup machine
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	cmd = newSchedulerImportCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--file", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !strings.Contains(out.String(), "Imported 2 job(s): push1, close1") {
+		t.Errorf("unexpected import output %q", out.String())
+	}
+	mirror, err := schedule.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := mirror.List()
+	if err != nil || len(got) != 2 {
+		t.Fatalf("expected 2 imported jobs, got %v, %v", got, err)
+	}
+	var first bytes.Buffer
+	if err := json.Compact(&first, got[0].Messages[0]); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != `{"text":"yes","type":"text"}` || !got[0].At.Equal(jobs[0].At) || got[1].CouponID != "coupon-1" {
+		t.Errorf("job changed in the round trip: %+v", got)
+	}
+
+	// A second import skips what is already there
+	out.Reset()
+	cmd = newSchedulerImportCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--file", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !strings.Contains(out.String(), "Imported 0 job(s)") || !strings.Contains(out.String(), "Skipped push1: already scheduled") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
+
+func TestSchedulerExport_Crontab(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	store := setupSchedule(t, now)
+	if err := store.Add(schedule.Job{ID: "b1", Account: "shop", Type: "broadcast", At: time.Date(2024, 7, 1, 9, 30, 0, 0, time.Local),
+		Messages: []json.RawMessage{json.RawMessage(`{"type":"text","text":"hi"}`)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	cmd := newSchedulerExportCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--format", "crontab"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	for _, want := range []string{"# b1: broadcast as shop at 2024-07-01T09:30:00", "30 9 1 7 * line scheduler run --once"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in crontab:\n%s", want, out.String())
+		}
+	}
+
+	cmd = newSchedulerExportCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--format", "toml"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid --format") {
+		t.Errorf("expected invalid --format error, got %v", err)
+	}
+}
+
+func TestSchedulerImport_Validation(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	setupSchedule(t, now)
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+		wantOut  string
+	}{
+		{"unknown type", "jobs:\n  - id: a\n    type: reply\n    at: 2024-07-01T00:00:00Z\n", "unknown type", ""},
+		{"push without recipient", "jobs:\n  - id: a\n    type: push\n    at: 2024-07-01T00:00:00Z\n    messages:\n      - type: text\n        text: hi\n", "exactly one recipient", ""},
+		{"duplicate", "jobs:\n  - id: a\n    type: coupon-close\n    couponId: c\n    at: 2024-07-01T00:00:00Z\n  - id: a\n    type: coupon-close\n    couponId: c\n    at: 2024-07-02T00:00:00Z\n", "appears twice", ""},
+		{"past job", "jobs:\n  - id: old\n    type: coupon-close\n    couponId: c\n    at: 2024-05-01T00:00:00Z\n", "", "Skipped old: time has passed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "m.yaml")
+			if err := os.WriteFile(path, []byte(tt.manifest), 0600); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			cmd := newSchedulerImportCmd()
+			cmd.SetOut(&out)
+			cmd.SetArgs([]string{"--file", path})
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("expected %q, got %q, %v", tt.wantOut, out.String(), err)
+			}
+		})
+	}
+}
@@ -1,9 +1,86 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"golang.org/x/term"
+)
+
+// credentialsPassphraseEnv holds the passphrase of the encrypted
+// credentials file for non-interactive use.
+const credentialsPassphraseEnv = "LINE_CREDENTIALS_PASSPHRASE"
+
+// Environment variables with credentials for CI, used instead of the stored
+// credentials unless --account is given. LINE_CHANNEL_SECRET is also the
+// default webhook secret.
+const (
+	channelAccessTokenEnv = "LINE_CHANNEL_ACCESS_TOKEN"
+	channelIDEnv          = "LINE_CHANNEL_ID"
+	channelSecretEnv      = "LINE_CHANNEL_SECRET"
 )
 
+// envCredentials returns the credentials set in the environment: a channel
+// access token, or a channel ID and secret to issue stateless tokens with.
+func envCredentials() (*secrets.Credentials, bool) {
+	creds := &secrets.Credentials{
+		ChannelAccessToken: strings.TrimSpace(os.Getenv(channelAccessTokenEnv)),
+		ChannelID:          strings.TrimSpace(os.Getenv(channelIDEnv)),
+		ChannelSecret:      strings.TrimSpace(os.Getenv(channelSecretEnv)),
+	}
+	if creds.ChannelAccessToken == "" && !creds.UsesChannelSecret() {
+		return nil, false
+	}
+	return creds, true
+}
+
 func openSecretsStore() (secrets.Store, error) {
-	return secrets.NewKeychainStore()
+	return secrets.Open(configuredCredentialStore(), credentialsPassphrase)
+}
+
+// configuredCredentialStore returns the credential_store config setting.
+func configuredCredentialStore() string {
+	if cfg == nil {
+		return ""
+	}
+	return cfg.CredentialStore
+}
+
+// credentialsPassphrase returns the passphrase of the encrypted credentials
+// file from LINE_CREDENTIALS_PASSPHRASE, or else prompts for it on the
+// terminal, twice if confirm is set.
+func credentialsPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(credentialsPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("credentials file is encrypted: set %s or run in a terminal", credentialsPassphraseEnv)
+	}
+
+	passphrase, err := readPassphrase("Credentials passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := readPassphrase("Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+func readPassphrase(prompt string) (string, error) {
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(data), nil
 }
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audit"
+	"github.com/salmonumbrella/line-official-cli/internal/webui"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd() *cobra.Command {
+	return newServeCmdWithClient(nil)
+}
+
+func newServeCmdWithClient(client *api.Client) *cobra.Command {
+	var listen string
+	var templatesDir string
+	var password string
+
+	cmd := &cobra.Command{
+		Use:         "serve",
+		Short:       "Serve a local web UI for common campaign operations",
+		Annotations: map[string]string{noPagerAnnotation: ""},
+		Long: `Serve a minimal web UI for teammates who need a few campaign operations
+but should not have LINE Official Account Manager access:
+
+  - send a prepared template message (push to one user, or broadcast)
+  - switch which rich menu an alias points to
+  - view this month's message quota
+
+Templates are loaded from --templates DIR: each *.json file holds one
+Messaging API message object and is offered under its file name. Only
+these templates can be sent from the UI.
+
+Sends honour the configured freeze windows, and every send and alias
+switch is recorded in the audit log.
+
+The server listens on localhost by default. Listening on any other
+address requires --password (or LINE_SERVE_PASSWORD), which the browser
+asks for through HTTP basic auth.`,
+		Example: `  # Serve on localhost:8787 with templates from ./templates
+  line serve --templates ./templates
+
+  # Share on the office network, password protected
+  LINE_SERVE_PASSWORD=... line serve --listen :8787 --templates ./templates`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !isLoopbackListen(listen) && password == "" {
+				return fmt.Errorf("--password (or LINE_SERVE_PASSWORD) is required when listening on %s; only localhost may be served without one", listen)
+			}
+
+			var templates map[string]json.RawMessage
+			if templatesDir != "" {
+				var err error
+				templates, err = loadMessageTemplates(templatesDir)
+				if err != nil {
+					return err
+				}
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			errOut := cmd.ErrOrStderr()
+			srv, err := webui.NewServer(c, webui.Options{
+				Templates: templates,
+				Password:  password,
+				CheckSend: checkServeFreeze,
+				Record: func(action string, details map[string]any) {
+					if err := audit.Append(audit.Entry{
+						Time:    clockNow().UTC(),
+						Account: flags.Account,
+						Action:  action,
+						Details: details,
+					}); err != nil {
+						warnf(errOut, "failed to write audit log: %v", err)
+					}
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to start web UI: %w", err)
+			}
+
+			return runServe(cmd, listen, srv.Handler(), len(templates))
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", "127.0.0.1:8787", "Address to listen on")
+	cmd.Flags().StringVar(&templatesDir, "templates", "", "Directory of *.json message templates")
+	cmd.Flags().StringVar(&password, "password", os.Getenv("LINE_SERVE_PASSWORD"), "Password required to use the UI (or LINE_SERVE_PASSWORD env)")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, listen string, handler http.Handler, templateCount int) error {
+	out := cmd.OutOrStdout()
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	_, _ = fmt.Fprintf(out, "Web UI listening on http://%s\n", net.JoinHostPort(host, port))
+	_, _ = fmt.Fprintf(out, "Templates loaded: %d\n", templateCount)
+	_, _ = fmt.Fprintf(out, "Press Ctrl+C to stop\n")
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("server error: %w", err)
+	case <-cmd.Context().Done():
+		_, _ = fmt.Fprintf(out, "\nShutting down...\n")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown error: %w", err)
+	}
+
+	return nil
+}
+
+// checkServeFreeze blocks sends from the web UI during a freeze window.
+// Unlike the CLI there is no override: teammates using the UI should ask
+// someone with CLI access to send during a freeze.
+func checkServeFreeze(sendType string) error {
+	if cfg == nil || cfg.Freeze.IsZero() {
+		return nil
+	}
+	active, err := activeFreeze(cfg.Freeze, sendType, clockNow())
+	if err != nil {
+		return fmt.Errorf("failed to evaluate freeze windows: %w", err)
+	}
+	if active != "" {
+		return fmt.Errorf("%s is blocked by freeze window (%s)", sendType, active)
+	}
+	return nil
+}
+
+// isLoopbackListen reports whether a listen address only accepts local
+// connections. An empty host (":8787") listens on all interfaces.
+func isLoopbackListen(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// loadMessageTemplates reads every *.json file in dir as a single message
+// object, keyed by file name without the extension.
+func loadMessageTemplates(dir string) (map[string]json.RawMessage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.json templates found in %s", dir)
+	}
+
+	templates := make(map[string]json.RawMessage, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		var msg struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", filepath.Base(path), err)
+		}
+		if msg.Type == "" {
+			return nil, fmt.Errorf("invalid template %s: a message object with a \"type\" is required", filepath.Base(path))
+		}
+		templates[strings.TrimSuffix(filepath.Base(path), ".json")] = json.RawMessage(data)
+	}
+	return templates, nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+func TestIsLoopbackListen(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8787": true,
+		"localhost:8787": true,
+		"[::1]:8787":     true,
+		":8787":          false,
+		"0.0.0.0:8787":   false,
+		"192.168.1.5:80": false,
+		"8787":           false,
+	}
+	for listen, want := range tests {
+		if got := isLoopbackListen(listen); got != want {
+			t.Errorf("isLoopbackListen(%q) = %v, want %v", listen, got, want)
+		}
+	}
+}
+
+func TestServeCmd_RequiresPasswordOffLocalhost(t *testing.T) {
+	cmd := newServeCmdWithClient(nil)
+	cmd.SetArgs([]string{"--listen", ":8787", "--password", ""})
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetErr(&strings.Builder{})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--password") {
+		t.Fatalf("expected password error, got %v", err)
+	}
+}
+
+func TestLoadMessageTemplates(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Spring-Sale.json", `{"type":"text","text":"Sale!"}`)
+	write("notes.txt", "ignored")
+
+	templates, err := loadMessageTemplates(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 || string(templates["Spring-Sale"]) != `{"type":"text","text":"Sale!"}` {
+		t.Errorf("unexpected templates: %v", templates)
+	}
+
+	write("broken.json", `{"text":"no type"}`)
+	if _, err := loadMessageTemplates(dir); err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("expected error naming broken.json, got %v", err)
+	}
+
+	if _, err := loadMessageTemplates(t.TempDir()); err == nil {
+		t.Error("expected error for empty directory")
+	}
+}
+
+func TestCheckServeFreeze(t *testing.T) {
+	origCfg, origNow := cfg, clockNow
+	t.Cleanup(func() { cfg, clockNow = origCfg, origNow })
+
+	cfg = &config.Config{Freeze: config.FreezeConfig{
+		Timezone: "UTC",
+		Windows:  []config.FreezeWindow{{Start: "22:00", End: "08:00", Sends: []string{"broadcast"}}},
+	}}
+	clockNow = func() time.Time { return time.Date(2026, 3, 4, 23, 0, 0, 0, time.UTC) }
+
+	if err := checkServeFreeze("broadcast"); err == nil || !strings.Contains(err.Error(), "freeze window") {
+		t.Errorf("expected broadcast to be blocked, got %v", err)
+	}
+	if err := checkServeFreeze("push"); err != nil {
+		t.Errorf("expected push to be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// managerOnlySettings are account settings with no Messaging API, listed by
+// "settings show" so users know where to change them.
+var managerOnlySettings = []string{
+	"Greeting message",
+	"Auto-response messages",
+	"Webhook redelivery",
+	"Webhook error notifications",
+}
+
+// managerURL is LINE Official Account Manager, where the settings above are
+// changed.
+const managerURL = "https://manager.line.biz/"
+
+func newSettingsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "settings",
+		Short: "View account settings",
+		Long: `View the account settings the Messaging API exposes: chat mode,
+mark-as-read mode, and the webhook endpoint.
+
+Greeting messages, auto-responses, webhook redelivery, and webhook error
+notifications have no Messaging API and can only be changed in LINE Official
+Account Manager (` + managerURL + `).`,
+	}
+
+	cmd.AddCommand(newSettingsShowCmd())
+
+	return cmd
+}
+
+func newSettingsShowCmd() *cobra.Command {
+	return newSettingsShowCmdWithClient(nil)
+}
+
+func newSettingsShowCmdWithClient(client *api.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:     "show",
+		Short:   "Show account settings",
+		Example: `  line settings show`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			settings, err := c.GetAccountSettings(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get settings: %w", err)
+			}
+
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(settings)
+			}
+
+			out := cmd.OutOrStdout()
+			webhook := "(not set)"
+			if settings.Webhook.Endpoint != "" {
+				state := "inactive"
+				if settings.Webhook.Active {
+					state = "active"
+				}
+				webhook = fmt.Sprintf("%s (%s)", settings.Webhook.Endpoint, state)
+			}
+			_, _ = fmt.Fprintf(out, "Chat Mode:          %s\n", settings.ChatMode)
+			_, _ = fmt.Fprintf(out, "Mark-as-read Mode:  %s\n", settings.MarkAsReadMode)
+			_, _ = fmt.Fprintf(out, "Webhook:            %s\n", webhook)
+			_, _ = fmt.Fprintf(out, "\nManaged in LINE Official Account Manager (%s):\n", managerURL)
+			for _, s := range managerOnlySettings {
+				_, _ = fmt.Fprintf(out, "  - %s\n", s)
+			}
+			return nil
+		},
+	}
+}
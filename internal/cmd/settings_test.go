@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func settingsServer(t *testing.T) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/info":
+			_ = json.NewEncoder(w).Encode(map[string]any{"chatMode": "bot", "markAsReadMode": "manual"})
+		case "/v2/bot/channel/webhook/endpoint":
+			_ = json.NewEncoder(w).Encode(map[string]any{"endpoint": "https://example.com/hook", "active": true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestSettingsShowCmd_Text(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "text"
+
+	cmd := newSettingsShowCmdWithClient(settingsServer(t))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"Chat Mode:          bot",
+		"Mark-as-read Mode:  manual",
+		"Webhook:            https://example.com/hook (active)",
+		"Greeting message",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSettingsShowCmd_JSON(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	cmd := newSettingsShowCmdWithClient(settingsServer(t))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var settings api.AccountSettings
+	if err := json.Unmarshal(out.Bytes(), &settings); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if settings.ChatMode != "bot" || settings.MarkAsReadMode != "manual" || settings.Webhook.Endpoint != "https://example.com/hook" || !settings.Webhook.Active {
+		t.Errorf("unexpected settings: %+v", settings)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// stdinName is the file name that reads stdin instead, for every --file,
+// --users, and --operations flag.
+const stdinName = "-"
+
+// stdin is where a "-" file name reads from. The root command points it at
+// its input, so tests can set it too.
+var stdin io.Reader = os.Stdin
+
+// readFileOrStdin reads the file at path, or all of stdin when path is "-".
+func readFileOrStdin(path string) ([]byte, error) {
+	if path == stdinName {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// openFileOrStdin opens the file at path, or stdin when path is "-".
+// Closing stdin is a no-op.
+func openFileOrStdin(path string) (io.ReadCloser, error) {
+	if path == stdinName {
+		return io.NopCloser(stdin), nil
+	}
+	return os.Open(path)
+}
+
+// spoolStdin copies stdin to a temporary file when path is "-", for
+// commands that read a file more than once, and returns the path to read.
+// Call cleanup when done with it.
+func spoolStdin(path string) (spooled string, cleanup func(), err error) {
+	if path != stdinName {
+		return path, func() {}, nil
+	}
+	f, err := os.CreateTemp("", "line-stdin-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	cleanup = func() { _ = os.Remove(f.Name()) }
+	_, err = io.Copy(f, stdin)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return f.Name(), cleanup, nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// setStdin points "-" file names at s for the rest of the test.
+func setStdin(t *testing.T, s string) {
+	t.Helper()
+	old := stdin
+	stdin = strings.NewReader(s)
+	t.Cleanup(func() { stdin = old })
+}
+
+func TestReadFileOrStdin(t *testing.T) {
+	setStdin(t, `{"type":"text","text":"hi"}`)
+	data, err := readFileOrStdin("-")
+	if err != nil || string(data) != `{"type":"text","text":"hi"}` {
+		t.Errorf("unexpected %q, %v", data, err)
+	}
+	if _, err := readFileOrStdin("/nonexistent/payload.json"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestMessageValidateCmd_Stdin(t *testing.T) {
+	setStdin(t, `[{"type":"text","text":"hi"}]`)
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages json.RawMessage `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		body = string(req.Messages)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newMessageValidateCmdWithClient(client)
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--type", "push", "--file", "-"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(body, `"text":"hi"`) {
+		t.Errorf("expected the messages from stdin, got %s", body)
+	}
+}
+
+func TestRichMenuBulkLinkCmd_UsersFromStdin(t *testing.T) {
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			UserIDs []string `json:"userIds"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		batches = append(batches, len(req.UserIDs))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	var ids strings.Builder
+	for i := 0; i < 600; i++ {
+		ids.WriteString(testUserID(i) + "\n")
+	}
+	setStdin(t, ids.String())
+
+	var out bytes.Buffer
+	cmd := newRichMenuBulkLinkCmdWithClient(client, nil)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--menu", "richmenu-123", "--users", "-"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(batches) != "[500 100]" {
+		t.Errorf("expected batches of 500, got %v", batches)
+	}
+	if !strings.Contains(out.String(), "to 600 users") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestExpandRecipients_Stdin(t *testing.T) {
+	setStdin(t, testUserID(1)+"\n"+testUserID(2)+"\n")
+	ids, err := expandRecipients([]string{"@-"})
+	if err != nil || len(ids) != 2 {
+		t.Errorf("expected 2 IDs from stdin, got %v, %v", ids, err)
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// stickerListURL is LINE's list of the stickers bots may send, with
+// previews of every sticker.
+const stickerListURL = "https://developers.line.biz/en/docs/messaging-api/sticker-list/"
+
+// stickerPackage is a sticker package bots may send from, with its range
+// of sticker IDs.
+type stickerPackage struct {
+	PackageID string `json:"packageId"`
+	FirstID   int    `json:"firstStickerId"`
+	LastID    int    `json:"lastStickerId"`
+	Animated  bool   `json:"animated"`
+}
+
+// stickerPackages are the packages in LINE's sticker list.
+var stickerPackages = []stickerPackage{
+	{"446", 1988, 2027, false},
+	{"789", 10855, 10894, false},
+	{"1070", 17839, 17878, false},
+	{"6136", 10551376, 10551399, false},
+	{"6325", 10979904, 10979927, false},
+	{"6359", 11069848, 11069871, false},
+	{"6362", 11087920, 11087943, false},
+	{"6370", 11088016, 11088039, false},
+	{"6632", 11825374, 11825397, false},
+	{"8515", 16581242, 16581265, false},
+	{"8522", 16581266, 16581289, false},
+	{"8525", 16581290, 16581313, false},
+	{"11537", 52002734, 52002773, true},
+	{"11538", 51626494, 51626533, true},
+	{"11539", 52114110, 52114149, true},
+}
+
+func newStickerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sticker",
+		Short: "Find stickers to send",
+		Long:  "List the sticker packages bots can send from with message --sticker.",
+	}
+	cmd.AddCommand(newStickerListPackagesCmd())
+	return cmd
+}
+
+func newStickerListPackagesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-packages",
+		Short: "List sticker packages bots can send",
+		Long: `List the sticker packages from LINE's sticker list, with the range of
+sticker IDs in each. Bots can only send these stickers; previews are at
+` + stickerListURL,
+		Example: `  line sticker list-packages
+  line message push --to U1234567890abcdef --sticker 446:1988`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.Output == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(map[string]any{"packages": stickerPackages, "previews": stickerListURL})
+			}
+
+			table := NewTable("PACKAGE", "STICKER IDS", "ANIMATED")
+			for _, p := range stickerPackages {
+				animated := ""
+				if p.Animated {
+					animated = "yes"
+				}
+				table.AddRow(p.PackageID, fmt.Sprintf("%d-%d", p.FirstID, p.LastID), animated)
+			}
+			table.Render(cmd.OutOrStdout())
+			if flags.Output != "table" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\nPreviews: %s\n", stickerListURL)
+			}
+			return nil
+		},
+	}
+}
+
+// parseStickerFlags returns the package and sticker ID from --sticker
+// (<packageId>:<stickerId>) or from --sticker-package and --sticker-id.
+// Both are empty if no sticker is given.
+func parseStickerFlags(sticker, packageID, stickerID string) (string, string, error) {
+	if sticker == "" {
+		if (packageID != "" && stickerID == "") || (packageID == "" && stickerID != "") {
+			return "", "", fmt.Errorf("--sticker-package and --sticker-id must be used together")
+		}
+		return packageID, stickerID, nil
+	}
+	if packageID != "" || stickerID != "" {
+		return "", "", fmt.Errorf("use --sticker or --sticker-package and --sticker-id, not both")
+	}
+	packageID, stickerID, ok := strings.Cut(sticker, ":")
+	if !ok || !isDigits(packageID) || !isDigits(stickerID) {
+		return "", "", fmt.Errorf("invalid --sticker %q: expected <packageId>:<stickerId>, e.g. 446:1988", sticker)
+	}
+	return packageID, stickerID, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+// maxTextEmojis is how many LINE emojis a text message may contain.
+const maxTextEmojis = 20
+
+// emojiPattern matches inline LINE emoji syntax: {emoji:<productId>:<emojiId>}.
+var emojiPattern = regexp.MustCompile(`\{emoji:([^:{}\s]+):([^:{}\s]+)\}`)
+
+// newTextMessage returns a text message for text, replacing each
+// {emoji:<productId>:<emojiId>} with the "$" placeholder LINE draws the
+// emoji in.
+func newTextMessage(text string) (api.TextMessage, error) {
+	msg := api.TextMessage{Type: "text"}
+	var b strings.Builder
+	index := 0 // in UTF-16 code units, as LINE counts
+	last := 0
+	for _, m := range emojiPattern.FindAllStringSubmatchIndex(text, -1) {
+		before := text[last:m[0]]
+		b.WriteString(before)
+		index += len(utf16.Encode([]rune(before)))
+		msg.Emojis = append(msg.Emojis, api.Emoji{Index: index, ProductID: text[m[2]:m[3]], EmojiID: text[m[4]:m[5]]})
+		b.WriteByte('$')
+		index++
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+	if len(msg.Emojis) > maxTextEmojis {
+		return api.TextMessage{}, fmt.Errorf("text has %d emojis, at most %d are allowed", len(msg.Emojis), maxTextEmojis)
+	}
+	msg.Text = b.String()
+	return msg, nil
+}
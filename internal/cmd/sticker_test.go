@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestParseStickerFlags(t *testing.T) {
+	tests := []struct {
+		name                          string
+		sticker, packageID, stickerID string
+		wantPackage, wantSticker      string
+		wantErr                       string
+	}{
+		{"combined", "446:1988", "", "", "446", "1988", ""},
+		{"separate", "", "789", "10855", "789", "10855", ""},
+		{"none", "", "", "", "", "", ""},
+		{"missing sticker ID", "446", "", "", "", "", "expected <packageId>:<stickerId>"},
+		{"not numeric", "446:abc", "", "", "", "", "expected <packageId>:<stickerId>"},
+		{"empty package", ":1988", "", "", "", "", "expected <packageId>:<stickerId>"},
+		{"both forms", "446:1988", "446", "", "", "", "not both"},
+		{"package only", "", "446", "", "", "", "must be used together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, id, err := parseStickerFlags(tt.sticker, tt.packageID, tt.stickerID)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if pkg != tt.wantPackage || id != tt.wantSticker {
+				t.Errorf("got %s:%s, want %s:%s", pkg, id, tt.wantPackage, tt.wantSticker)
+			}
+		})
+	}
+}
+
+func TestNewTextMessage(t *testing.T) {
+	const product = "5ac1bfd5040ab15980c9b435"
+	tests := []struct {
+		name       string
+		text       string
+		wantText   string
+		wantEmojis []api.Emoji
+	}{
+		{"plain", "Hello", "Hello", nil},
+		{
+			"two emojis",
+			"Hi {emoji:" + product + ":001} and {emoji:" + product + ":002}!",
+			"Hi $ and $!",
+			[]api.Emoji{{Index: 3, ProductID: product, EmojiID: "001"}, {Index: 9, ProductID: product, EmojiID: "002"}},
+		},
+		{
+			// 😀 is a surrogate pair, so it counts as two
+			"surrogate pair before emoji",
+			"😀{emoji:" + product + ":010}",
+			"😀$",
+			[]api.Emoji{{Index: 2, ProductID: product, EmojiID: "010"}},
+		},
+		{"not emoji syntax", "{emoji:broken} {emoji::001}", "{emoji:broken} {emoji::001}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := newTextMessage(tt.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if msg.Type != "text" || msg.Text != tt.wantText || !reflect.DeepEqual(msg.Emojis, tt.wantEmojis) {
+				t.Errorf("got %+v, want text %q emojis %+v", msg, tt.wantText, tt.wantEmojis)
+			}
+		})
+	}
+
+	if _, err := newTextMessage(strings.Repeat("{emoji:"+product+":001}", maxTextEmojis+1)); err == nil {
+		t.Error("expected error for too many emojis")
+	}
+}
+
+// captureRequest returns a client whose requests are decoded into body.
+func captureRequest(t *testing.T, body *map[string]any) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, body); err != nil {
+			t.Errorf("invalid request body %s: %v", data, err)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestMessagePushCmd_StickerShorthand(t *testing.T) {
+	var body map[string]any
+	cmd := newMessagePushCmdWithClient(captureRequest(t, &body))
+	cmd.SetArgs([]string{"--to", "U123", "--sticker", "446:1988"})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := body["messages"].([]any)[0].(map[string]any)
+	if msg["type"] != "sticker" || msg["packageId"] != "446" || msg["stickerId"] != "1988" {
+		t.Errorf("unexpected message %v", msg)
+	}
+}
+
+func TestMessageReplyCmd_EmojiAndSticker(t *testing.T) {
+	var body map[string]any
+	client := captureRequest(t, &body)
+
+	cmd := newMessageReplyCmdWithClient(client)
+	cmd.SetArgs([]string{"--token", "reply-token", "--text", "Thanks {emoji:5ac1bfd5040ab15980c9b435:001}"})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg := body["messages"].([]any)[0].(map[string]any)
+	emojis, _ := msg["emojis"].([]any)
+	if msg["text"] != "Thanks $" || len(emojis) != 1 || emojis[0].(map[string]any)["index"] != float64(7) {
+		t.Errorf("unexpected text message %v", msg)
+	}
+
+	cmd = newMessageReplyCmdWithClient(client)
+	cmd.SetArgs([]string{"--token", "reply-token", "--sticker", "11537:52002734"})
+	cmd.SetOut(new(bytes.Buffer))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	msg = body["messages"].([]any)[0].(map[string]any)
+	if msg["type"] != "sticker" || msg["packageId"] != "11537" || msg["stickerId"] != "52002734" {
+		t.Errorf("unexpected sticker message %v", msg)
+	}
+}
+
+func TestStickerListPackagesCmd(t *testing.T) {
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+
+	flags.Output = "text"
+	cmd := newStickerListPackagesCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"446", "1988-2027", "11539", stickerListURL} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	flags.Output = "json"
+	out.Reset()
+	cmd = newStickerListPackagesCmd()
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result struct {
+		Packages []stickerPackage `json:"packages"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(result.Packages) != len(stickerPackages) || result.Packages[0].FirstID != 1988 {
+		t.Errorf("unexpected packages %+v", result.Packages)
+	}
+}
@@ -3,27 +3,60 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
-	"unicode/utf8"
+	"unicode"
+
+	"github.com/salmonumbrella/line-official-cli/internal/output"
 )
 
+// defaultMaxColumnWidth caps table columns unless --max-col-width says
+// otherwise.
+const defaultMaxColumnWidth = 40
+
 // Table provides a simple table formatter for list output.
 // It renders aligned columns with headers and auto-sizes columns based on content.
 type Table struct {
 	headers []string
 	rows    [][]string
 	maxCols int
+
+	// maxWidth caps column widths, noTruncate shows values in full, and
+	// columns selects and orders the columns shown
+	maxWidth   int
+	noTruncate bool
+	columns    []string
+
+	// colors color the values of a column, by header
+	colors map[string]func(output.Palette, string) string
 }
 
 // NewTable creates a new table with the given column headers.
+// Width and column settings come from --max-col-width, --no-truncate,
+// and --columns.
 func NewTable(headers ...string) *Table {
+	maxWidth := flags.MaxColWidth
+	if maxWidth <= 0 {
+		maxWidth = defaultMaxColumnWidth
+	}
 	return &Table{
-		headers: headers,
-		rows:    make([][]string, 0),
-		maxCols: len(headers),
+		headers:    headers,
+		rows:       make([][]string, 0),
+		maxCols:    len(headers),
+		maxWidth:   maxWidth,
+		noTruncate: flags.NoTruncate,
+		columns:    flags.Columns,
+		colors:     map[string]func(output.Palette, string) string{"STATUS": output.Palette.Status},
 	}
 }
 
+// ColorColumn colors the values of the column with header when color is
+// enabled, e.g. output.Palette.Green. STATUS columns are colored by
+// status without it.
+func (t *Table) ColorColumn(header string, color func(output.Palette, string) string) {
+	t.colors[header] = color
+}
+
 // AddRow adds a row of values to the table.
 // If fewer values are provided than headers, remaining columns are left empty.
 // If more values are provided than headers, they are ignored.
@@ -43,41 +76,96 @@ func (t *Table) Render(w io.Writer) {
 		return
 	}
 
+	headers, rows := t.selectColumns()
+
 	// Calculate column widths
-	widths := t.calculateColumnWidths()
+	widths := t.calculateColumnWidths(headers, rows)
 
-	// Print header row
-	t.printRow(w, t.headers, widths)
+	// Print header row, in bold when color is enabled
+	_, _ = fmt.Fprintln(w, bold(w, t.formatRow(headers, widths)))
 
 	// Print separator line
 	t.printSeparator(w, widths)
 
-	// Print data rows
-	for _, row := range t.rows {
-		t.printRow(w, row, widths)
+	// Print data rows, coloring values after padding so escapes do not
+	// count toward the widths
+	colors := make([]func(output.Palette, string) string, len(headers))
+	for i, h := range headers {
+		colors[i] = t.colors[h]
+	}
+	p := palette(w)
+	for _, row := range rows {
+		t.printRow(w, row, widths, colors, p)
 	}
 }
 
-// calculateColumnWidths determines the width for each column.
-// Each column width is the maximum of the header width and all row values,
-// capped at a maximum width.
-func (t *Table) calculateColumnWidths() []int {
-	const maxColumnWidth = 40
+// selectColumns returns the headers and rows of the columns chosen with
+// --columns, in the order given. Names match headers case-insensitively,
+// ignoring spaces, dashes, and underscores, so "rich-menu-id" selects
+// "RICH MENU ID". Unknown names are reported on stderr and skipped.
+func (t *Table) selectColumns() ([]string, [][]string) {
+	if len(t.columns) == 0 {
+		return t.headers, t.rows
+	}
 
-	widths := make([]int, len(t.headers))
+	index := make(map[string]int, len(t.headers))
+	for i, h := range t.headers {
+		index[columnKey(h)] = i
+	}
+	var picked []int
+	for _, name := range t.columns {
+		i, ok := index[columnKey(name)]
+		if !ok {
+			warnf(os.Stderr, "no column %q (columns: %s)", name, strings.ToLower(strings.Join(t.headers, ", ")))
+			continue
+		}
+		picked = append(picked, i)
+	}
+	if len(picked) == 0 {
+		return t.headers, t.rows
+	}
+
+	headers := make([]string, len(picked))
+	for j, i := range picked {
+		headers[j] = t.headers[i]
+	}
+	rows := make([][]string, len(t.rows))
+	for r, row := range t.rows {
+		rows[r] = make([]string, len(picked))
+		for j, i := range picked {
+			rows[r][j] = row[i]
+		}
+	}
+	return headers, rows
+}
+
+func columnKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(name))
+}
+
+// calculateColumnWidths determines the width for each column.
+// Each column width is the maximum display width of the header and all
+// row values, capped at the maximum width unless truncation is off.
+func (t *Table) calculateColumnWidths(headers []string, rows [][]string) []int {
+	widths := make([]int, len(headers))
 
 	// Start with header widths
-	for i, h := range t.headers {
-		widths[i] = utf8.RuneCountInString(h)
+	for i, h := range headers {
+		widths[i] = displayWidth(h)
 	}
 
 	// Check all rows for wider values
-	for _, row := range t.rows {
+	for _, row := range rows {
 		for i, val := range row {
 			if i >= len(widths) {
 				break
 			}
-			valWidth := utf8.RuneCountInString(val)
+			valWidth := displayWidth(val)
 			if valWidth > widths[i] {
 				widths[i] = valWidth
 			}
@@ -85,9 +173,11 @@ func (t *Table) calculateColumnWidths() []int {
 	}
 
 	// Cap at maximum width
-	for i := range widths {
-		if widths[i] > maxColumnWidth {
-			widths[i] = maxColumnWidth
+	if !t.noTruncate {
+		for i := range widths {
+			if widths[i] > t.maxWidth {
+				widths[i] = t.maxWidth
+			}
 		}
 	}
 
@@ -95,13 +185,25 @@ func (t *Table) calculateColumnWidths() []int {
 }
 
 // printRow writes a single row of values with proper column alignment.
-func (t *Table) printRow(w io.Writer, values []string, widths []int) {
+func (t *Table) printRow(w io.Writer, values []string, widths []int, colors []func(output.Palette, string) string, p output.Palette) {
+	parts := make([]string, len(values))
+	for i, val := range values {
+		parts[i] = padOrTruncate(val, widths[i])
+		if colors[i] != nil {
+			parts[i] = colors[i](p, parts[i])
+		}
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(parts, "  "))
+}
+
+// formatRow aligns a row of values to the column widths.
+func (t *Table) formatRow(values []string, widths []int) string {
 	parts := make([]string, len(values))
 	for i, val := range values {
 		width := widths[i]
 		parts[i] = padOrTruncate(val, width)
 	}
-	_, _ = fmt.Fprintln(w, strings.Join(parts, "  "))
+	return strings.Join(parts, "  ")
 }
 
 // printSeparator writes a separator line using Unicode box-drawing dashes.
@@ -113,15 +215,15 @@ func (t *Table) printSeparator(w io.Writer, widths []int) {
 	_, _ = fmt.Fprintln(w, strings.Join(parts, "  "))
 }
 
-// padOrTruncate ensures a string fits exactly within the given width.
-// If the string is too long, it is truncated and "..." is appended.
-// If the string is too short, it is padded with spaces.
+// padOrTruncate ensures a string fills exactly width terminal cells.
+// If the string is too wide, it is truncated and "..." is appended.
+// If the string is too narrow, it is padded with spaces.
 func padOrTruncate(s string, width int) string {
-	runeCount := utf8.RuneCountInString(s)
+	sw := displayWidth(s)
 
-	if runeCount <= width {
+	if sw <= width {
 		// Pad with spaces
-		return s + strings.Repeat(" ", width-runeCount)
+		return s + strings.Repeat(" ", width-sw)
 	}
 
 	// Truncate with ellipsis
@@ -129,9 +231,131 @@ func padOrTruncate(s string, width int) string {
 		return strings.Repeat(".", width)
 	}
 
-	// Take first (width-3) runes and add "..."
-	runes := []rune(s)
-	return string(runes[:width-3]) + "..."
+	// Keep whole characters that fit in width-3 cells and add "...",
+	// padding when a wide character would straddle the cut
+	var b strings.Builder
+	used := 0
+	for _, g := range graphemes(s) {
+		if used+g.width > width-3 {
+			break
+		}
+		b.WriteString(g.text)
+		used += g.width
+	}
+	return b.String() + "..." + strings.Repeat(" ", width-3-used)
+}
+
+// displayWidth returns how many terminal cells s takes: CJK characters and
+// emoji take two, combining marks and joiners none.
+func displayWidth(s string) int {
+	width := 0
+	for _, g := range graphemes(s) {
+		width += g.width
+	}
+	return width
+}
+
+// grapheme is a user-perceived character and its display width.
+type grapheme struct {
+	text  string
+	width int
+}
+
+// graphemes splits s into user-perceived characters. It covers what table
+// values contain in practice (combining marks, variation selectors, skin
+// tones, ZWJ emoji sequences, and flags) rather than all of UAX #29.
+func graphemes(s string) []grapheme {
+	var out []grapheme
+	joined := false // the previous rune was a zero width joiner
+	for i, r := range s {
+		size := len(string(r))
+		if len(out) > 0 {
+			last := &out[len(out)-1]
+			switch {
+			case joined, isExtending(r):
+				last.text += s[i : i+size]
+				if r == '\uFE0F' && last.width == 1 {
+					last.width = 2 // emoji presentation
+				}
+				joined = r == '\u200D'
+				continue
+			case isRegionalIndicator(r) && pendingFlag(last.text):
+				last.text += s[i : i+size]
+				continue
+			}
+		}
+		out = append(out, grapheme{text: s[i : i+size], width: runeWidth(r)})
+		joined = r == '\u200D'
+	}
+	return out
+}
+
+// isExtending reports whether r attaches to the character before it.
+func isExtending(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		r == '\u200D' || // zero width joiner
+		(r >= '\uFE00' && r <= '\uFE0F') || // variation selectors
+		(r >= 0x1F3FB && r <= 0x1F3FF) // skin tone modifiers
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// pendingFlag reports whether text is a lone regional indicator waiting
+// for the second half of a flag.
+func pendingFlag(text string) bool {
+	runes := []rune(text)
+	return len(runes) == 1 && isRegionalIndicator(runes[0])
+}
+
+// runeWidth returns the display width of a single rune.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Cf, r) || unicode.IsControl(r):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// wideRanges are the East Asian Wide and Fullwidth ranges and the emoji
+// shown two cells wide by default.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x18AFF},
+	{0x1B000, 0x1B2FF}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A}, {0x1F1E6, 0x1F1FF}, {0x1F200, 0x1F2FF}, {0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB}, {0x1F900, 0x1F9FF}, {0x1FA70, 0x1FAFF},
+	{0x20000, 0x3FFFD},
+}
+
+func isWide(r rune) bool {
+	if r < 0x1100 {
+		return false
+	}
+	for _, rg := range wideRanges {
+		if r < rg.lo {
+			return false
+		}
+		if r <= rg.hi {
+			return true
+		}
+	}
+	return false
 }
 
 // IsEmpty returns true if the table has no data rows.
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/output"
 )
 
 func TestTable_BasicOutput(t *testing.T) {
@@ -199,16 +201,28 @@ func TestPadOrTruncate(t *testing.T) {
 		},
 		{
 			name:     "unicode characters - padded",
-			input:    "\u4e2d\u6587\u5b57\u7b26", // 4 characters
-			width:    6,
-			expected: "\u4e2d\u6587\u5b57\u7b26  ", // padded to 6
+			input:    "\u4e2d\u6587\u5b57\u7b26", // 4 characters, 8 cells
+			width:    10,
+			expected: "\u4e2d\u6587\u5b57\u7b26  ", // padded to 10 cells
 		},
 		{
 			name:     "unicode characters - truncated",
-			input:    "\u4e2d\u6587\u5b57\u7b26\u6d4b\u8bd5", // 6 characters
-			width:    5,
+			input:    "\u4e2d\u6587\u5b57\u7b26\u6d4b\u8bd5", // 6 characters, 12 cells
+			width:    7,
 			expected: "\u4e2d\u6587...",
 		},
+		{
+			name:     "wide character straddling the cut",
+			input:    "\u4e2d\u6587\u5b57\u7b26",
+			width:    6,
+			expected: "\u4e2d... ",
+		},
+		{
+			name:     "emoji",
+			input:    "\U0001F381 Gift",
+			width:    8,
+			expected: "\U0001F381 Gift ",
+		},
 	}
 
 	for _, tt := range tests {
@@ -311,10 +325,145 @@ func TestCalculateColumnWidths_RowWithMoreColumns(t *testing.T) {
 	// Add row with values that would go beyond the header columns
 	table.rows = append(table.rows, []string{"1", "2", "3", "4"}) // More than headers
 
-	widths := table.calculateColumnWidths()
+	widths := table.calculateColumnWidths(table.headers, table.rows)
 
 	// Should only have widths for the headers
 	if len(widths) != 2 {
 		t.Errorf("expected 2 column widths, got %d", len(widths))
 	}
 }
+
+func TestTable_BoldHeaderWithColor(t *testing.T) {
+	old := flags.Color
+	t.Cleanup(func() { flags.Color = old })
+
+	table := NewTable("NAME")
+	table.AddRow("shop")
+
+	flags.Color = "always"
+	var buf bytes.Buffer
+	table.Render(&buf)
+	if !strings.HasPrefix(buf.String(), "\x1b[1mNAME\x1b[0m\n") {
+		t.Errorf("expected bold header, got %q", buf.String())
+	}
+
+	flags.Color = "auto"
+	buf.Reset()
+	table.Render(&buf)
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no escapes when not writing to a terminal, got %q", buf.String())
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"abc", 3},
+		{"\u30af\u30fc\u30dd\u30f3", 8},                   // katakana
+		{"\uff2c\uff29\uff2e\uff25", 8},                   // fullwidth latin
+		{"\ud55c\uad6d\uc5b4", 6},                         // hangul
+		{"cafe\u0301", 4},                                 // combining accent
+		{"\U0001F389", 2},                                 // emoji
+		{"\u2764\uFE0F", 2},                               // emoji presentation selector
+		{"\U0001F44D\U0001F3FD", 2},                       // skin tone
+		{"\U0001F468\u200D\U0001F469\u200D\U0001F467", 2}, // ZWJ family
+		{"\U0001F1EF\U0001F1F5", 2},                       // flag
+		{"\U0001F1EF\U0001F1F5\U0001F1F9\U0001F1ED", 4},   // two flags
+	}
+	for _, tt := range tests {
+		if got := displayWidth(tt.input); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTable_AlignsWideCharacters(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewTable("NAME", "STATUS")
+	table.AddRow("\u6625\u306e\u30bb\u30fc\u30eb\U0001F338", "READY")
+	table.AddRow("Spring sale", "READY")
+	table.Render(&buf)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := displayWidth(lines[0][:strings.Index(lines[0], "STATUS")])
+	for _, line := range lines[2:] {
+		if got := displayWidth(line[:strings.Index(line, "READY")]); got != want {
+			t.Errorf("STATUS starts at cell %d in %q, want %d", got, line, want)
+		}
+	}
+}
+
+func TestTable_ColumnOptions(t *testing.T) {
+	long := strings.Repeat("x", 30)
+	render := func(tb *Table) string {
+		tb.AddRow("1", long, "READY")
+		var buf bytes.Buffer
+		tb.Render(&buf)
+		return buf.String()
+	}
+
+	table := NewTable("ID", "NAME", "STATUS")
+	table.maxWidth = 10
+	if out := render(table); strings.Contains(out, long) || !strings.Contains(out, "xxxxxxx...") {
+		t.Errorf("expected NAME truncated to 10 cells: %q", out)
+	}
+
+	table = NewTable("ID", "NAME", "STATUS")
+	table.maxWidth = 10
+	table.noTruncate = true
+	if out := render(table); !strings.Contains(out, long) {
+		t.Errorf("expected NAME in full with noTruncate: %q", out)
+	}
+
+	table = NewTable("ID", "RICH MENU ID", "STATUS")
+	table.columns = []string{"status", "rich-menu-id", "missing"}
+	out := render(table)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if strings.TrimRight(lines[0], " ") != "STATUS  RICH MENU ID" {
+		t.Errorf("expected STATUS and RICH MENU ID columns only: %q", out)
+	}
+	if !strings.HasPrefix(lines[2], "READY   "+long) {
+		t.Errorf("unexpected row %q", lines[2])
+	}
+}
+
+func TestNewTable_UsesFlags(t *testing.T) {
+	old := flags
+	t.Cleanup(func() { flags = old })
+	flags.Columns = []string{"name"}
+	flags.MaxColWidth = 12
+	flags.NoTruncate = true
+
+	table := NewTable("ID", "NAME")
+	if table.maxWidth != 12 || !table.noTruncate || len(table.columns) != 1 {
+		t.Errorf("unexpected table settings: %+v", table)
+	}
+
+	flags.MaxColWidth = 0
+	if table := NewTable("ID"); table.maxWidth != defaultMaxColumnWidth {
+		t.Errorf("maxWidth = %d, want the default", table.maxWidth)
+	}
+}
+
+func TestTable_ColorsStatusColumns(t *testing.T) {
+	old := flags.Color
+	t.Cleanup(func() { flags.Color = old })
+	flags.Color = "always"
+
+	table := NewTable("ID", "STATUS", "DEFAULT")
+	table.AddRow("1", "READY", "yes")
+	table.AddRow("22", "FAILED", "")
+	table.ColorColumn("DEFAULT", output.Palette.Green)
+	var buf bytes.Buffer
+	table.Render(&buf)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if want := "1   \x1b[32mREADY \x1b[0m  \x1b[32myes    \x1b[0m"; lines[2] != want {
+		t.Errorf("row = %q, want %q", lines[2], want)
+	}
+	if want := "22  \x1b[31mFAILED\x1b[0m         "; lines[3] != want {
+		t.Errorf("row = %q, want %q", lines[3], want)
+	}
+}
@@ -3,8 +3,12 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+	"github.com/salmonumbrella/line-official-cli/internal/token"
 	"github.com/spf13/cobra"
 )
 
@@ -28,39 +32,91 @@ func newTokenCmd() *cobra.Command {
 }
 
 func newTokenIssueCmd() *cobra.Command {
-	return newTokenIssueCmdWithClient(nil)
+	return newTokenIssueCmdWithClient(nil, nil)
 }
 
-func newTokenIssueCmdWithClient(client *api.Client) *cobra.Command {
+func newTokenIssueCmdWithClient(client *api.Client, store secrets.Store) *cobra.Command {
 	var clientID string
 	var clientSecret string
+	var assertion tokenAssertionOptions
+	var expireDays int
+	var save bool
 
 	cmd := &cobra.Command{
 		Use:   "issue",
-		Short: "Issue a v2 channel access token",
-		Long:  "Issue a short-lived channel access token using client credentials (v2 API).",
+		Short: "Issue a channel access token",
+		Long: `Issue a channel access token.
+
+With --client-secret, issues a short-lived token using client credentials
+(v2 API, valid for 30 days).
+
+With --kid and --private-key, builds and signs a JWT assertion and exchanges
+it for a v2.1 token whose lifetime is set by --expire-days (max 30). The key
+is the RSA private key (PEM or JWK) whose public key is registered for the
+channel in the LINE Developers Console; --kid is the key ID shown there.
+
+Use --save to replace the stored token of the current account (--account,
+or the primary account).`,
 		Example: `  # Issue a new v2 channel access token
   line token issue --client-id 1234567890 --client-secret abc123
 
+  # Issue a v2.1 token signed with your assertion key and store it
+  line token issue --client-id 1234567890 --kid KEY_ID --private-key key.pem --save
+
   # Output as JSON
   line token issue --client-id 1234567890 --client-secret abc123 --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if clientID == "" {
 				return fmt.Errorf("--client-id is required")
 			}
-			if clientSecret == "" {
-				return fmt.Errorf("--client-secret is required")
+			useJWT := assertion.kid != "" || assertion.privateKey != ""
+			if useJWT && clientSecret != "" {
+				return fmt.Errorf("use either --client-secret or --kid/--private-key, not both")
+			}
+			if !useJWT && clientSecret == "" {
+				return fmt.Errorf("--client-secret is required (or use --kid and --private-key)")
+			}
+			if expireDays < 1 || expireDays > 30 {
+				return fmt.Errorf("--expire-days must be between 1 and 30")
 			}
 
 			c := client
 			if c == nil {
 				// Create a client without auth (token endpoints don't use Bearer auth)
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
-			resp, err := c.IssueChannelToken(cmd.Context(), clientID, clientSecret)
-			if err != nil {
-				return fmt.Errorf("failed to issue token: %w", err)
+			var resp *api.TokenResponse
+			var err error
+			if useJWT {
+				var jwt string
+				jwt, err = assertion.sign(clientID, time.Duration(expireDays)*24*time.Hour)
+				if err != nil {
+					return err
+				}
+				resp, err = c.IssueChannelTokenByJWT(cmd.Context(), jwt)
+				if err != nil {
+					return fmt.Errorf("failed to issue token: %w", err)
+				}
+			} else {
+				resp, err = c.IssueChannelToken(cmd.Context(), clientID, clientSecret)
+				if err != nil {
+					return fmt.Errorf("failed to issue token: %w", err)
+				}
+			}
+
+			var savedTo string
+			if save && !flags.DryRun {
+				if store == nil {
+					store, err = openSecretsStore()
+					if err != nil {
+						return fmt.Errorf("failed to open keyring: %w", err)
+					}
+				}
+				savedTo, err = saveAccountToken(store, resp.AccessToken, clientID)
+				if err != nil {
+					return err
+				}
 			}
 
 			if flags.Output == "json" {
@@ -75,17 +131,95 @@ func newTokenIssueCmdWithClient(client *api.Client) *cobra.Command {
 			if resp.KeyID != "" {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Key ID:       %s\n", resp.KeyID)
 			}
+			if savedTo != "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Saved to account %s\n", savedTo)
+			}
 
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&clientID, "client-id", "", "Channel ID (required)")
-	cmd.Flags().StringVar(&clientSecret, "client-secret", "", "Channel secret (required)")
+	cmd.Flags().StringVar(&clientSecret, "client-secret", "", "Channel secret (v2 token)")
+	addTokenAssertionFlags(cmd, &assertion)
+	cmd.Flags().IntVar(&expireDays, "expire-days", 30, "Days until a v2.1 token expires (1-30)")
+	cmd.Flags().BoolVar(&save, "save", false, "Store the issued token in the current account")
 
 	return cmd
 }
 
+// tokenAssertionOptions holds the flags used to sign a v2.1 JWT assertion.
+type tokenAssertionOptions struct {
+	kid        string
+	privateKey string
+}
+
+func addTokenAssertionFlags(cmd *cobra.Command, o *tokenAssertionOptions) {
+	cmd.Flags().StringVar(&o.kid, "kid", "", "Key ID of the registered assertion signing key (v2.1 token)")
+	cmd.Flags().StringVar(&o.privateKey, "private-key", "", "Assertion signing private key file, PEM or JWK (v2.1 token)")
+}
+
+// sign builds a JWT assertion for channelID. lifetime is the validity of
+// the token it will be exchanged for; zero means the maximum.
+func (o tokenAssertionOptions) sign(channelID string, lifetime time.Duration) (string, error) {
+	if o.kid == "" {
+		return "", fmt.Errorf("--kid is required with --private-key")
+	}
+	if o.privateKey == "" {
+		return "", fmt.Errorf("--private-key is required with --kid")
+	}
+	key, err := token.LoadPrivateKeyFile(o.privateKey)
+	if err != nil {
+		return "", err
+	}
+	a := token.Assertion{ChannelID: channelID, KeyID: o.kid, TokenLifetime: lifetime}
+	jwt, err := a.Sign(key, clockNow())
+	if err != nil {
+		return "", fmt.Errorf("failed to build JWT assertion: %w", err)
+	}
+	return jwt, nil
+}
+
+// saveAccountToken replaces the stored token of the current account,
+// keeping its bot name and primary status, and returns the account name.
+func saveAccountToken(store secrets.Store, accessToken, channelID string) (string, error) {
+	account := flags.Account
+	if account == "" {
+		primary, err := store.GetPrimary()
+		if err != nil || primary == "" {
+			return "", fmt.Errorf("no account to save the token to; run line auth login or use --account")
+		}
+		account = primary
+	}
+
+	creds, err := store.Get(account)
+	if err != nil {
+		return "", fmt.Errorf("failed to get credentials for %s: %w", account, err)
+	}
+
+	var info secrets.AccountInfo
+	if accounts, err := store.List(); err == nil {
+		for _, a := range accounts {
+			if strings.EqualFold(a.Name, strings.TrimSpace(account)) {
+				info = a
+				break
+			}
+		}
+	}
+
+	creds.ChannelAccessToken = accessToken
+	creds.ChannelID = channelID
+	if err := store.Set(account, *creds, info.BotName); err != nil {
+		return "", fmt.Errorf("failed to save credentials: %w", err)
+	}
+	if info.IsPrimary {
+		if err := store.SetPrimary(account); err != nil {
+			return "", fmt.Errorf("failed to restore primary account: %w", err)
+		}
+	}
+	return account, nil
+}
+
 func newTokenVerifyCmd() *cobra.Command {
 	return newTokenVerifyCmdWithClient(nil)
 }
@@ -109,7 +243,7 @@ func newTokenVerifyCmdWithClient(client *api.Client) *cobra.Command {
 
 			c := client
 			if c == nil {
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			info, err := c.VerifyChannelToken(cmd.Context(), token)
@@ -158,7 +292,7 @@ func newTokenRevokeCmdWithClient(client *api.Client) *cobra.Command {
 
 			c := client
 			if c == nil {
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			if err := c.RevokeChannelToken(cmd.Context(), token); err != nil {
@@ -207,7 +341,7 @@ func newTokenIssueJWTCmdWithClient(client *api.Client) *cobra.Command {
 
 			c := client
 			if c == nil {
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			resp, err := c.IssueChannelTokenByJWT(cmd.Context(), jwt)
@@ -260,7 +394,7 @@ func newTokenVerifyJWTCmdWithClient(client *api.Client) *cobra.Command {
 
 			c := client
 			if c == nil {
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			info, err := c.VerifyChannelTokenByJWT(cmd.Context(), token)
@@ -317,7 +451,7 @@ func newTokenRevokeJWTCmdWithClient(client *api.Client) *cobra.Command {
 
 			c := client
 			if c == nil {
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			if err := c.RevokeChannelTokenByJWT(cmd.Context(), token, clientID, clientSecret); err != nil {
@@ -351,24 +485,43 @@ func newTokenListKeysCmd() *cobra.Command {
 
 func newTokenListKeysCmdWithClient(client *api.Client) *cobra.Command {
 	var jwt string
+	var clientID string
+	var assertion tokenAssertionOptions
 
 	cmd := &cobra.Command{
-		Use:   "list-keys",
-		Short: "List all valid token key IDs",
-		Long:  "Get all valid channel access token key IDs (v2.1 API).",
+		Use:     "list-keys",
+		Aliases: []string{"list-valid-kids"},
+		Short:   "List all valid token key IDs",
+		Long: `Get the key IDs of all valid v2.1 channel access tokens.
+
+Pass a prebuilt assertion with --jwt, or let the CLI sign one with
+--client-id, --kid, and --private-key.`,
 		Example: `  # List all valid token key IDs
   line token list-keys --jwt eyJhbGciOiJSUzI1NiI...
 
+  # Sign the assertion from your private key
+  line token list-valid-kids --client-id 1234567890 --kid KEY_ID --private-key key.pem
+
   # Output as JSON
   line token list-keys --jwt eyJhbGciOiJSUzI1NiI... --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if jwt == "" {
-				return fmt.Errorf("--jwt is required")
+				if assertion.kid == "" && assertion.privateKey == "" {
+					return fmt.Errorf("--jwt is required (or use --client-id, --kid, and --private-key)")
+				}
+				if clientID == "" {
+					return fmt.Errorf("--client-id is required with --kid and --private-key")
+				}
+				var err error
+				jwt, err = assertion.sign(clientID, 0)
+				if err != nil {
+					return err
+				}
 			}
 
 			c := client
 			if c == nil {
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			kids, err := c.GetAllValidTokenKeyIDs(cmd.Context(), jwt)
@@ -399,7 +552,9 @@ func newTokenListKeysCmdWithClient(client *api.Client) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&jwt, "jwt", "", "JWT assertion (required)")
+	cmd.Flags().StringVar(&jwt, "jwt", "", "JWT assertion")
+	cmd.Flags().StringVar(&clientID, "client-id", "", "Channel ID (to sign the assertion)")
+	addTokenAssertionFlags(cmd, &assertion)
 
 	return cmd
 }
@@ -435,7 +590,7 @@ They are suitable for short-lived operations where revocation is not needed.`,
 			c := client
 			if c == nil {
 				// Create a client without auth (token endpoints don't use Bearer auth)
-				c = api.NewClient("", flags.Debug, flags.DryRun)
+				c = newPlainAPIClient()
 			}
 
 			// Warn about stateless token limitations
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/secrets"
+)
+
+// writeTestPrivateKey writes a fresh RSA key as PEM and returns its path.
+func writeTestPrivateKey(t *testing.T) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// assertionClaims decodes the header and claims of a JWT assertion.
+func assertionClaims(t *testing.T, jwt string) (map[string]any, map[string]any) {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a JWT, got %q", jwt)
+	}
+	var header, claims map[string]any
+	for i, dst := range []*map[string]any{&header, &claims} {
+		data, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return header, claims
+}
+
+func TestTokenIssueCmd_JWT(t *testing.T) {
+	keyPath := writeTestPrivateKey(t)
+
+	var assertion string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth2/v2.1/token" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		assertion = r.PostForm.Get("client_assertion")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "v21-token",
+			"token_type":   "Bearer",
+			"expires_in":   604800,
+			"key_id":       "token-kid",
+		})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newTokenIssueCmdWithClient(client, nil)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--client-id", "1234567890", "--kid", "assert-kid", "--private-key", keyPath, "--expire-days", "7"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "v21-token") {
+		t.Errorf("expected access token in output, got: %s", buf.String())
+	}
+	header, claims := assertionClaims(t, assertion)
+	if header["kid"] != "assert-kid" {
+		t.Errorf("expected kid assert-kid, got %v", header["kid"])
+	}
+	if claims["iss"] != "1234567890" || claims["token_exp"] != float64(7*24*60*60) {
+		t.Errorf("unexpected claims: %v", claims)
+	}
+}
+
+func TestTokenIssueCmd_JWTValidation(t *testing.T) {
+	keyPath := writeTestPrivateKey(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"secret and key", []string{"--client-id", "1", "--client-secret", "s", "--kid", "k", "--private-key", keyPath}, "not both"},
+		{"kid without key", []string{"--client-id", "1", "--kid", "k"}, "--private-key is required"},
+		{"key without kid", []string{"--client-id", "1", "--private-key", keyPath}, "--kid is required"},
+		{"expire days", []string{"--client-id", "1", "--kid", "k", "--private-key", keyPath, "--expire-days", "31"}, "--expire-days"},
+		{"missing key file", []string{"--client-id", "1", "--kid", "k", "--private-key", filepath.Join(t.TempDir(), "none.pem")}, "failed to read private key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTokenIssueCmdWithClient(api.NewClient("", false, false), nil)
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTokenIssueCmd_Save(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "new-token", "token_type": "Bearer", "expires_in": 2592000})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("", false, false)
+	client.SetBaseURL(server.URL)
+
+	store := newMockStore()
+	_ = store.Set("main", secrets.Credentials{ChannelAccessToken: "old-token", ChannelSecret: "keep-me"}, "My Bot")
+	_ = store.Set("other", secrets.Credentials{ChannelAccessToken: "other-token"}, "")
+	_ = store.SetPrimary("main")
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account, flags.Output = "", "text"
+
+	cmd := newTokenIssueCmdWithClient(client, store)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--client-id", "1234567890", "--client-secret", "secret", "--save"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Saved to account main") {
+		t.Errorf("expected save confirmation, got: %s", buf.String())
+	}
+	creds, _ := store.Get("main")
+	if creds.ChannelAccessToken != "new-token" || creds.ChannelID != "1234567890" || creds.ChannelSecret != "keep-me" {
+		t.Errorf("unexpected stored credentials: %+v", creds)
+	}
+	if primary, _ := store.GetPrimary(); primary != "main" {
+		t.Errorf("expected main to stay primary, got %q", primary)
+	}
+	if store.accountMeta["main"].BotName != "My Bot" {
+		t.Errorf("expected bot name to be kept, got %q", store.accountMeta["main"].BotName)
+	}
+	if other, _ := store.Get("other"); other.ChannelAccessToken != "other-token" {
+		t.Error("other account must not change")
+	}
+}
+
+func TestTokenIssueCmd_SaveUnknownAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "new-token"})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Account = "missing"
+
+	cmd := newTokenIssueCmdWithClient(client, newMockStore())
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--client-id", "1", "--client-secret", "s", "--save"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "failed to get credentials for missing") {
+		t.Errorf("expected missing account error, got: %v", err)
+	}
+}
+
+func TestTokenListKeysCmd_SignsAssertion(t *testing.T) {
+	keyPath := writeTestPrivateKey(t)
+
+	var assertion string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertion = r.URL.Query().Get("client_assertion")
+		_ = json.NewEncoder(w).Encode(map[string]any{"kids": []string{"key-1"}})
+	}))
+	defer server.Close()
+
+	client := api.NewClient("", false, false)
+	client.SetBaseURL(server.URL)
+
+	cmd := newTokenListKeysCmdWithClient(client)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--client-id", "1234567890", "--kid", "assert-kid", "--private-key", keyPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "key-1") {
+		t.Errorf("expected key-1 in output, got: %s", buf.String())
+	}
+	header, claims := assertionClaims(t, assertion)
+	if header["kid"] != "assert-kid" || claims["sub"] != "1234567890" {
+		t.Errorf("unexpected assertion: %v %v", header, claims)
+	}
+}
+
+func TestTokenCmd_ListValidKidsAlias(t *testing.T) {
+	found, _, err := newTokenCmd().Find([]string{"list-valid-kids"})
+	if err != nil || found.Name() != "list-keys" {
+		t.Errorf("expected list-valid-kids to resolve to list-keys, got %v, %v", found, err)
+	}
+}
@@ -168,7 +168,7 @@ func TestTokenIssueCmd_Execute(t *testing.T) {
 	client := api.NewClient("", false, false)
 	client.SetBaseURL(server.URL)
 
-	cmd := newTokenIssueCmdWithClient(client)
+	cmd := newTokenIssueCmdWithClient(client, nil)
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
 	cmd.SetErr(buf)
@@ -207,7 +207,7 @@ func TestTokenIssueCmd_ExecuteJSON(t *testing.T) {
 	flags.Output = "json"
 	defer func() { flags.Output = oldOutput }()
 
-	cmd := newTokenIssueCmdWithClient(client)
+	cmd := newTokenIssueCmdWithClient(client, nil)
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
 	cmd.SetErr(buf)
@@ -727,7 +727,7 @@ func TestTokenCmd_APIError(t *testing.T) {
 	client := api.NewClient("", false, false)
 	client.SetBaseURL(server.URL)
 
-	cmd := newTokenIssueCmdWithClient(client)
+	cmd := newTokenIssueCmdWithClient(client, nil)
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
 	cmd.SetErr(buf)
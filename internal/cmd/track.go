@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/salmonumbrella/line-official-cli/internal/ledger"
+	"github.com/spf13/cobra"
+)
+
+// trackSummaryLength is how many characters of a text message are kept in
+// the ledger.
+const trackSummaryLength = 40
+
+// trackFlags are the --track flags of the broadcast and narrowcast commands.
+type trackFlags struct {
+	Track bool
+	Label string
+}
+
+func (t *trackFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&t.Track, "track", false, "Mark the send in the local ledger for 'line insight message-delivery'")
+	cmd.Flags().StringVar(&t.Label, "track-label", "", "Name to record the send under, such as a campaign (implies --track)")
+}
+
+// enabled reports whether the send should be recorded.
+func (t *trackFlags) enabled() bool {
+	return t != nil && (t.Track || t.Label != "")
+}
+
+// trackSend marks a sent broadcast or narrowcast as tracked in the ledger.
+// The send has already happened, so a failure to record it is a warning,
+// not an error.
+func trackSend(cmd *cobra.Command, t *trackFlags, sendType, requestID string, message any) {
+	if !t.enabled() {
+		return
+	}
+	if requestID == "" {
+		warnf(cmd.ErrOrStderr(), "LINE returned no request ID; the %s was not tracked", sendType)
+		return
+	}
+	// The key recordSends records the send under, so the entry is marked
+	// rather than added again
+	account, _ := localAccountKey()
+	err := ledger.Track(ledger.Entry{
+		Time:      clockNow().UTC(),
+		Account:   account,
+		Type:      sendType,
+		RequestID: requestID,
+		Label:     t.Label,
+		Summary:   trackSummary(message),
+	})
+	if err != nil {
+		warnf(cmd.ErrOrStderr(), "the %s was sent but not tracked: %v", sendType, err)
+		return
+	}
+	if flags.Output != "json" {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Tracked %s; fetch its results with 'line insight message-delivery --request-id %s'\n", requestID, requestID)
+	}
+}
+
+// trackSummary describes a message for the ledger: the start of its text,
+// or its type.
+func trackSummary(message any) string {
+	var m struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	data, _ := json.Marshal(message)
+	_ = json.Unmarshal(data, &m)
+	if m.Text == "" {
+		return m.Type
+	}
+	text := strings.Join(strings.Fields(m.Text), " ")
+	if r := []rune(text); len(r) > trackSummaryLength {
+		return string(r[:trackSummaryLength-1]) + "…"
+	}
+	return text
+}
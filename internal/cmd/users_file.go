@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/salmonumbrella/line-official-cli/internal/audiencemanifest"
+	"github.com/salmonumbrella/line-official-cli/internal/userids"
+	"github.com/spf13/cobra"
+)
+
+// defaultMaxUsers is the default --max-users cap on the number of unique
+// user IDs read from a users file.
+const defaultMaxUsers = 10_000_000
+
+// usersFileBatchSize is how many user IDs are held in memory at a time when
+// a users file is not read in API-sized batches.
+const usersFileBatchSize = 10_000
+
+// addMaxUsersFlag adds the --max-users safety cap for users files.
+func addMaxUsersFlag(cmd *cobra.Command, maxUsers *int) {
+	cmd.Flags().IntVar(maxUsers, "max-users", defaultMaxUsers, "Refuse users files with more unique user IDs than this (0 for no limit)")
+}
+
+// addInvalidUsersFlags adds --skip-invalid and --strict, which choose
+// whether user IDs that are not valid are skipped or fail the upload.
+func addInvalidUsersFlags(cmd *cobra.Command, skipInvalid *bool) {
+	cmd.Flags().BoolVar(skipInvalid, "skip-invalid", false, "Skip invalid user IDs, listing them, instead of failing")
+	cmd.Flags().Bool("strict", false, "Fail if any user ID is invalid, listing their line numbers (the default)")
+	cmd.MarkFlagsMutuallyExclusive("skip-invalid", "strict")
+}
+
+// readUsersFile streams the unique user IDs of a users file, or of stdin
+// when path is "-", to fn in batches of at most batchSize.
+func readUsersFile(path string, opts userids.Options, batchSize int, fn func(batch []string) error) (userids.Stats, error) {
+	var stats userids.Stats
+	var err error
+	if path == stdinName {
+		stats, err = userids.Read(stdin, "stdin", opts, batchSize, fn)
+	} else {
+		stats, err = userids.ReadFile(path, opts, batchSize, fn)
+	}
+	if errors.Is(err, userids.ErrTooManyUsers) {
+		return stats, fmt.Errorf("%w; raise --max-users to allow more", err)
+	}
+	var invalid *userids.InvalidError
+	if errors.As(err, &invalid) {
+		return stats, fmt.Errorf("%w; fix them or pass --skip-invalid to leave them out", err)
+	}
+	return stats, err
+}
+
+// cleanUserIDs normalizes, validates, and de-duplicates user IDs given
+// with --users.
+func cleanUserIDs(ids []string, skipInvalid bool) ([]string, userids.Stats, error) {
+	cleaned, stats, err := userids.Clean(ids, userids.Options{SkipInvalid: skipInvalid})
+	if err != nil {
+		return nil, stats, fmt.Errorf("invalid --users: %w; fix them or pass --skip-invalid to leave them out", err)
+	}
+	if len(cleaned) == 0 {
+		return nil, stats, fmt.Errorf("no valid user IDs in --users")
+	}
+	return cleaned, stats, nil
+}
+
+// warnSkippedUsers reports the invalid user IDs skipped with
+// --skip-invalid on stderr.
+func warnSkippedUsers(cmd *cobra.Command, stats userids.Stats, unit string) {
+	if stats.Invalid == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipped %d invalid user ID(s):\n", stats.Invalid)
+	for _, l := range stats.InvalidLines {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  %s %d: %q\n", unit, l.Line, l.Text)
+	}
+	if more := stats.Invalid - len(stats.InvalidLines); more > 0 {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  ... and %d more\n", more)
+	}
+}
+
+// forEachUserBatch passes user IDs to fn in batches of at most batchSize,
+// from override if set (used by tests) or else streamed from usersFile. It
+// returns the number of unique IDs. usersFile is read twice, so stdin must
+// be spooled with spoolStdin first.
+func forEachUserBatch(usersFile string, override []string, maxUsers, batchSize int, fn func(batch []string) error) (int, error) {
+	if override == nil {
+		// Check the whole file first, so a bad line near the end doesn't
+		// leave the operation half done
+		_, err := readUsersFile(usersFile, userids.Options{MaxUsers: maxUsers}, batchSize, func([]string) error { return nil })
+		if errors.Is(err, userids.ErrNoUsers) {
+			return 0, fmt.Errorf("no user IDs found in file")
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read users file: %w", err)
+		}
+		stats, err := readUsersFile(usersFile, userids.Options{MaxUsers: maxUsers}, batchSize, fn)
+		return stats.Unique, err
+	}
+
+	if len(override) == 0 {
+		return 0, fmt.Errorf("no user IDs found in file")
+	}
+	for start := 0; start < len(override); start += batchSize {
+		end := min(start+batchSize, len(override))
+		if err := fn(override[start:end]); err != nil {
+			return start, err
+		}
+	}
+	return len(override), nil
+}
+
+// cleanUsersFile writes the unique, valid user IDs of path to a file with
+// the same name in a new temporary directory, ready to upload. Call cleanup
+// when done with it.
+func cleanUsersFile(path string, opts userids.Options) (cleaned string, stats userids.Stats, cleanup func(), err error) {
+	dir, err := os.MkdirTemp("", "line-users-")
+	if err != nil {
+		return "", userids.Stats{}, nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	cleanup = func() { _ = os.RemoveAll(dir) }
+
+	name := filepath.Base(path)
+	if path == stdinName {
+		name = "users.txt"
+	}
+	cleaned = filepath.Join(dir, name)
+	f, err := os.Create(cleaned)
+	if err != nil {
+		cleanup()
+		return "", userids.Stats{}, nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+
+	stats, err = readUsersFile(path, opts, usersFileBatchSize, func(batch []string) error {
+		for _, id := range batch {
+			if _, err := w.WriteString(id + "\n"); err != nil {
+				return fmt.Errorf("failed to write temporary file: %w", err)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cleanup()
+		return "", stats, nil, err
+	}
+	return cleaned, stats, cleanup, nil
+}
+
+// recordAudienceUploadFile streams a cleaned users file into the account's
+// local manifest for the audience group. Like recordAudienceUpload, errors
+// are ignored so tracking never fails a completed upload.
+func recordAudienceUploadFile(account string, audienceGroupID int64, cleaned string) {
+	if account == "" {
+		return
+	}
+	rec, err := audiencemanifest.NewRecorder(account, audienceGroupID)
+	if err != nil {
+		return
+	}
+	_, _ = readUsersFile(cleaned, userids.Options{}, usersFileBatchSize, func(batch []string) error {
+		for _, id := range batch {
+			if err := rec.Add(id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	_, _ = rec.Close()
+}
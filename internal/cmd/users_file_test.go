@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/audiencemanifest"
+)
+
+// testUserID returns a well-formed user ID for n.
+func testUserID(n int) string {
+	return fmt.Sprintf("U%032x", n)
+}
+
+// writeUsersFile writes ids, one per line, to a file in a temp directory.
+func writeUsersFile(t *testing.T, ids ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "users.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(ids, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRichMenuBulkLinkCmd_StreamsFileInBatches(t *testing.T) {
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			UserIDs []string `json:"userIds"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		batches = append(batches, len(req.UserIDs))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	ids := []string{"# exported from the warehouse"}
+	for i := 0; i < 1100; i++ {
+		ids = append(ids, testUserID(i))
+	}
+	ids = append(ids, testUserID(3), testUserID(4))
+
+	cmd := newRichMenuBulkLinkCmdWithClient(client, nil)
+	cmd.SetArgs([]string{"--menu", "richmenu-123", "--users", writeUsersFile(t, ids...)})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(batches) != "[500 500 100]" {
+		t.Errorf("expected batches of 500, got %v", batches)
+	}
+	if !strings.Contains(out.String(), "Linked rich menu richmenu-123 to 1100 users") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestRichMenuBulkUnlinkCmd_FileErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	many := make([]string, 0, 600)
+	for i := 0; i < 600; i++ {
+		many = append(many, testUserID(i))
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"invalid line", []string{"--users", writeUsersFile(t, append(many, "user_id")...)}, `line 601: invalid user ID "user_id"`},
+		{"too many users", []string{"--users", writeUsersFile(t, many...), "--max-users", "599"}, "raise --max-users"},
+		{"only comments", []string{"--users", writeUsersFile(t, "# nothing here")}, "no user IDs found in file"},
+		{"missing file", []string{"--users", "/nonexistent/users.txt"}, "failed to read users file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRichMenuBulkUnlinkCmdWithClient(client, nil)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+	if requests != 0 {
+		t.Errorf("a bad file must be rejected before any request, got %d requests", requests)
+	}
+}
+
+func TestAudienceAddUsersCmd_FileIsCleanedBeforeUpload(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("expected a file upload: %v", err)
+			return
+		}
+		var b bytes.Buffer
+		_, _ = b.ReadFrom(file)
+		uploaded = b.String()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	origAccount := flags.Account
+	flags.Account = "prod"
+	defer func() { flags.Account = origAccount }()
+
+	path := writeUsersFile(t, "# header", testUserID(1), "", testUserID(2), testUserID(1))
+	cmd := newAudienceAddUsersCmdWithClient(client)
+	cmd.SetArgs([]string{"--id", "12345", "--file", path})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := testUserID(1) + "\n" + testUserID(2); uploaded != want {
+		t.Errorf("uploaded %q, want %q", uploaded, want)
+	}
+	if !strings.Contains(out.String(), "Added 2 users") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	if ids, _ := audiencemanifest.Load("prod", 12345); len(ids) != 2 {
+		t.Errorf("expected 2 IDs in the manifest, got %v", ids)
+	}
+}
+
+func TestAudienceCreateCmd_InvalidUserIDs(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	var uploaded string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if file, _, err := r.FormFile("file"); err == nil {
+			var b bytes.Buffer
+			_, _ = b.ReadFrom(file)
+			uploaded = b.String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"audienceGroupId": 42}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	path := writeUsersFile(t, "user_id", testUserID(1), "  "+strings.ToUpper(testUserID(2)), "U123", testUserID(1))
+
+	run := func(args ...string) (string, error) {
+		cmd := newAudienceCreateCmdWithClient(client)
+		cmd.SetArgs(append([]string{"--name", "Export"}, args...))
+		var errOut bytes.Buffer
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&errOut)
+		err := cmd.Execute()
+		return errOut.String(), err
+	}
+
+	_, err := run("--file", path)
+	if err == nil || !strings.Contains(err.Error(), `line 1: invalid user ID "user_id" (2 invalid user IDs in total, lines 1, 4)`) {
+		t.Errorf("expected every invalid line to be reported, got %v", err)
+	}
+	if _, err := run("--users", testUserID(1)+",bad"); err == nil || !strings.Contains(err.Error(), `entry 2: invalid user ID "bad"`) {
+		t.Errorf("expected the invalid --users entry to be reported, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("invalid IDs must be rejected before any request, got %d", requests)
+	}
+
+	stderr, err := run("--file", path, "--skip-invalid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := testUserID(1) + "\n" + testUserID(2); uploaded != want {
+		t.Errorf("uploaded %q, want %q", uploaded, want)
+	}
+	if !strings.Contains(stderr, "skipped 2 invalid user ID(s)") || !strings.Contains(stderr, `line 4: "U123"`) {
+		t.Errorf("expected skipped IDs to be listed, got: %s", stderr)
+	}
+
+	if _, err := run("--file", path, "--skip-invalid", "--strict"); err == nil {
+		t.Error("expected --skip-invalid and --strict to conflict")
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// watchOutput calls render every interval until interrupted, or count
+// times if count is positive, redrawing the terminal with each result. In
+// JSON mode each result is printed compacted on its own line instead.
+//
+// A render that fails shows its error and watching goes on, so a brief
+// API outage does not end a long-running monitor.
+func watchOutput(cmd *cobra.Command, interval time.Duration, count int, render func(ctx context.Context, w io.Writer) error) error {
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	ctx := cmd.Context()
+	out := cmd.OutOrStdout()
+	title := fmt.Sprintf("Every %s: %s", interval, strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "))
+	for i := 0; count <= 0 || i < count; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+
+		var buf bytes.Buffer
+		err := render(ctx, &buf)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if flags.Output == "json" {
+			line := buf.Bytes()
+			if err != nil {
+				line, _ = json.Marshal(map[string]any{"time": clockNow(), "error": err.Error()})
+			}
+			var compact bytes.Buffer
+			if json.Compact(&compact, line) != nil {
+				compact.Reset()
+				compact.Write(bytes.TrimSpace(line))
+			}
+			_, _ = fmt.Fprintln(out, compact.String())
+			continue
+		}
+
+		// Clear the screen and redraw from the top-left corner
+		_, _ = fmt.Fprint(out, "\033[H\033[2J")
+		_, _ = fmt.Fprintf(out, "%s    %s\n\n", title, clockNow().Format("2006-01-02 15:04:05"))
+		if err != nil {
+			_, _ = fmt.Fprintf(out, "Error: %v\n", err)
+			continue
+		}
+		_, _ = out.Write(buf.Bytes())
+	}
+	return nil
+}
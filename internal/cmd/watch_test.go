@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func TestMessageQuotaCmd_Watch(t *testing.T) {
+	used := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/bot/message/quota":
+			_, _ = w.Write([]byte(`{"type":"limited","value":1000}`))
+		case "/v2/bot/message/quota/consumption":
+			used += 100
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"totalUsage":%d}`, used)))
+		}
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldNow := clockNow
+	defer func() { clockNow = oldNow }()
+	clockNow = func() time.Time { return time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC) }
+
+	var out bytes.Buffer
+	cmd := newMessageQuotaCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--watch", "--interval", "1ms", "--count", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	if n := strings.Count(got, "\033[H\033[2J"); n != 2 {
+		t.Errorf("expected 2 redraws, got %d in %q", n, got)
+	}
+	if !strings.Contains(got, "Every 1ms: quota    2026-03-04 12:00:00") {
+		t.Errorf("expected watch header, got %q", got)
+	}
+	if !strings.Contains(got, "Used: 100 (10.0%)") || !strings.Contains(got, "Used: 200 (20.0%)") {
+		t.Errorf("expected both refreshes, got %q", got)
+	}
+}
+
+func TestRichMenuBatchStatusCmd_WatchJSONKeepsGoingOnError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"phase":"ongoing","acceptedTime":"2026-03-04T12:00:00Z"}`))
+	}))
+	defer server.Close()
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+
+	oldOutput := flags.Output
+	defer func() { flags.Output = oldOutput }()
+	flags.Output = "json"
+
+	var out bytes.Buffer
+	cmd := newRichMenuBatchStatusCmdWithClient(client)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--request", "req-1", "--watch", "--interval", "1ms", "--count", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one JSON line per refresh, got %q", out.String())
+	}
+	var first, second map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil || first["error"] == nil {
+		t.Errorf("expected an error line, got %q (%v)", lines[0], err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil || second["phase"] != "ongoing" {
+		t.Errorf("expected a status line, got %q (%v)", lines[1], err)
+	}
+}
+
+func TestRichMenuBatchStatusCmd_WaitAndWatch(t *testing.T) {
+	cmd := newRichMenuBatchStatusCmdWithClient(api.NewClient("test-token", false, false))
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--request", "req-1", "--wait", "--watch"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
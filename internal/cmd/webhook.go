@@ -19,6 +19,8 @@ func newWebhookCmd() *cobra.Command {
 	cmd.AddCommand(newWebhookSetCmd())
 	cmd.AddCommand(newWebhookTestCmd())
 	cmd.AddCommand(newWebhookServeCmd())
+	cmd.AddCommand(newWebhookListenCmd())
+	cmd.AddCommand(newWebhookFixtureCmd())
 	return cmd
 }
 
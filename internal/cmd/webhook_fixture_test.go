@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runWebhookFixture(t *testing.T, args ...string) (stdout, stderr string) {
+	t.Helper()
+	cmd := newWebhookFixtureCmd()
+	cmd.SetArgs(args)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out.String(), errOut.String()
+}
+
+func TestWebhookFixtureCmd_TextMessage(t *testing.T) {
+	oldFlags, oldNow := flags, clockNow
+	defer func() { flags, clockNow = oldFlags, oldNow }()
+	flags.Output = "text"
+	clockNow = func() time.Time { return time.UnixMilli(1760000000000) }
+
+	out, _ := runWebhookFixture(t, "--type", "message.text", "--text", "hi", "--seed", "7")
+	again, _ := runWebhookFixture(t, "--type", "message.text", "--text", "hi", "--seed", "7")
+	if out != again {
+		t.Error("expected the same seed to give the same fixture")
+	}
+
+	var event LineWebhookEvent
+	if err := json.Unmarshal([]byte(out), &event); err != nil {
+		t.Fatalf("expected a single event, got: %s", out)
+	}
+	if event.Type != "message" || event.Timestamp != 1760000000000 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(event.ReplyToken) {
+		t.Errorf("reply token %q does not look like one", event.ReplyToken)
+	}
+	if event.Source == nil || event.Source.Type != "user" || !regexp.MustCompile(`^U[0-9a-f]{32}$`).MatchString(event.Source.UserID) {
+		t.Errorf("unexpected source: %+v", event.Source)
+	}
+	var message struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(event.Message, &message); err != nil || message.Type != "text" || message.Text != "hi" || len(message.ID) != 18 {
+		t.Errorf("unexpected message: %s", event.Message)
+	}
+}
+
+func TestWebhookFixtureCmd_SignedEnvelope(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Output = "text"
+
+	secret := "test-channel-secret"
+	out, errOut := runWebhookFixture(t, "--type", "follow", "--type", "postback", "--type", "beacon",
+		"--source", "group", "--secret", secret)
+	signature := strings.TrimSpace(strings.TrimPrefix(errOut, "X-Line-Signature:"))
+	if strings.HasSuffix(out, "\n") {
+		t.Error("a signed body must not end with a newline")
+	}
+
+	// The body and signature are accepted as a real request would be
+	handler := &webhookHandler{secret: secret, out: io.Discard, errOut: io.Discard, quiet: true}
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(out))
+	req.Header.Set("X-Line-Signature", signature)
+	rec := httptest.NewRecorder()
+	handler.handleWebhook(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected the signed fixture to be accepted, got %d", rec.Code)
+	}
+
+	var payload LineWebhookPayload
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(payload.Destination, "U") || len(payload.Events) != 3 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	for i, want := range []string{"follow", "postback", "beacon"} {
+		e := payload.Events[i]
+		if e.Type != want || e.Source.Type != "group" || !strings.HasPrefix(e.Source.GroupID, "C") {
+			t.Errorf("event %d: unexpected %+v", i, e)
+		}
+	}
+	if !strings.Contains(string(payload.Events[1].Postback), "action=buy") || !strings.Contains(string(payload.Events[2].Beacon), `"enter"`) {
+		t.Errorf("unexpected postback or beacon: %s %s", payload.Events[1].Postback, payload.Events[2].Beacon)
+	}
+}
+
+func TestWebhookFixtureCmd_JSONSignature(t *testing.T) {
+	oldFlags := flags
+	defer func() { flags = oldFlags }()
+	flags.Output = "json"
+
+	out, _ := runWebhookFixture(t, "--type", "unfollow", "--secret", "s3cret")
+	var result struct {
+		Signature string `json:"signature"`
+		Body      string `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("expected valid JSON, got: %s", out)
+	}
+	handler := &webhookHandler{secret: "s3cret"}
+	if !handler.validateSignature([]byte(result.Body), result.Signature) {
+		t.Error("expected the signature to match the body")
+	}
+	if strings.Contains(result.Body, "replyToken") {
+		t.Error("unfollow events carry no reply token")
+	}
+}
+
+func TestWebhookFixtureCmd_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no type", nil, "at least one --type"},
+		{"unknown type", []string{"--type", "message.video"}, `unknown event type "message.video"`},
+		{"bad source", []string{"--type", "follow", "--source", "channel"}, "invalid --source"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newWebhookFixtureCmd()
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
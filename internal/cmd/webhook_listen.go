@@ -0,0 +1,389 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+type listenFlags struct {
+	Port         int
+	Bind         string
+	Secret       string
+	Insecure     bool
+	Exec         string
+	ExecTemplate string
+	Events       []string
+	Timeout      time.Duration
+	Quiet        bool
+}
+
+func newWebhookListenCmd() *cobra.Command {
+	return newWebhookListenCmdWithClient(nil)
+}
+
+func newWebhookListenCmdWithClient(client *api.Client) *cobra.Command {
+	lf := &listenFlags{}
+
+	cmd := &cobra.Command{
+		Use:         "listen",
+		Short:       "Run a command for every webhook event and reply with its output",
+		Annotations: map[string]string{noPagerAnnotation: ""},
+		Long: `Start a local webhook server that runs a command for every event it
+receives, turning a shell script into a bot.
+
+The command gets the event JSON on stdin and these environment variables:
+
+  EVENT_TYPE     message, postback, follow, ...
+  USER_ID        user who triggered the event
+  REPLY_TOKEN    reply token of the event, if any
+  SOURCE_TYPE    user, group, or room
+  GROUP_ID       group of the event, if any
+  ROOM_ID        room of the event, if any
+  MESSAGE_TYPE   type of the message, for message events
+  MESSAGE_TEXT   text of the message, for text messages
+  POSTBACK_DATA  data of the postback, for postback events
+
+Whatever the command prints to stdout is sent back as a reply. Plain output
+is sent as a text message; output that is a message object, an array of
+message objects, or an object with "messages" is sent as those messages.
+Empty output sends no reply, and so does a command that exits with an error.
+
+--exec runs a program directly. --exec-template runs a shell command built
+from a Go template with the fields .Type, .UserID, .ReplyToken, .SourceType,
+.GroupID, .RoomID, .MessageType, .Text, and .PostbackData; the values are
+shell-quoted, so they are safe to use as arguments.
+
+Because the command runs for every request and replies with the account's
+token, events must be signed: --secret (or LINE_CHANNEL_SECRET) is
+required. --insecure-no-signature accepts unsigned events for local
+testing, and then only on a loopback --bind address. The server listens
+on 127.0.0.1 unless --bind says otherwise; a tunnel such as ngrok forwards
+LINE's requests to it.`,
+		Example: `  # Echo bot: handler.sh prints "You said: $MESSAGE_TEXT"
+  line webhook listen --exec ./handler.sh --secret YOUR_CHANNEL_SECRET
+
+  # Only text messages, through a shell command
+  line webhook listen --event message --exec-template 'fortune -s {{.Text}}' --secret YOUR_CHANNEL_SECRET
+
+  # Reply with a Flex message built by a script
+  line webhook listen --exec ./menu.py --timeout 10s --secret YOUR_CHANNEL_SECRET
+
+  # Try a handler locally with unsigned events from "webhook fixture"
+  line webhook listen --exec ./handler.sh --insecure-no-signature`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (lf.Exec == "") == (lf.ExecTemplate == "") {
+				return withExitCode(ExitUsage, fmt.Errorf("specify exactly one of --exec or --exec-template"))
+			}
+			if lf.Timeout <= 0 {
+				return withExitCode(ExitUsage, fmt.Errorf("--timeout must be positive"))
+			}
+			lf.Secret = getDefault(lf.Secret, os.Getenv(channelSecretEnv))
+			if err := checkListenSecurity(lf); err != nil {
+				return err
+			}
+
+			bridge := &eventBridge{
+				program: lf.Exec,
+				events:  lf.Events,
+				timeout: lf.Timeout,
+				quiet:   lf.Quiet,
+				out:     cmd.OutOrStdout(),
+				errOut:  cmd.ErrOrStderr(),
+			}
+			if lf.ExecTemplate != "" {
+				tmpl, err := template.New("exec").Option("missingkey=error").Parse(lf.ExecTemplate)
+				if err != nil {
+					return withExitCode(ExitUsage, fmt.Errorf("invalid --exec-template: %w", err))
+				}
+				bridge.template = tmpl
+			}
+
+			c := client
+			if c == nil {
+				var err error
+				c, err = newAPIClient()
+				if err != nil {
+					return err
+				}
+			}
+			bridge.client = c
+
+			return runWebhookListen(cmd, lf, bridge)
+		},
+	}
+
+	cmd.Flags().IntVarP(&lf.Port, "port", "p", 8080, "Port to listen on")
+	cmd.Flags().StringVar(&lf.Bind, "bind", "127.0.0.1", "Address to listen on")
+	cmd.Flags().StringVar(&lf.Secret, "secret", "", "Channel secret for signature validation (required; or LINE_CHANNEL_SECRET env)")
+	cmd.Flags().BoolVar(&lf.Insecure, "insecure-no-signature", false, "Accept unsigned events without --secret, on a loopback --bind address only")
+	cmd.Flags().StringVar(&lf.Exec, "exec", "", "Program to run for every event")
+	cmd.Flags().StringVar(&lf.ExecTemplate, "exec-template", "", "Shell command template to run for every event")
+	cmd.Flags().StringSliceVar(&lf.Events, "event", nil, "Only run for these event types (repeatable)")
+	cmd.Flags().DurationVar(&lf.Timeout, "timeout", 30*time.Second, "Time limit for each run of the command")
+	cmd.Flags().BoolVarP(&lf.Quiet, "quiet", "q", false, "Only show errors, no event logging")
+
+	return cmd
+}
+
+// checkListenSecurity refuses to run the command for unsigned events
+// unless --insecure-no-signature is given, and then only on loopback, so
+// nobody else who can reach the port can make the account reply.
+func checkListenSecurity(lf *listenFlags) error {
+	if lf.Secret != "" {
+		return nil
+	}
+	if !lf.Insecure {
+		return withExitCode(ExitUsage, fmt.Errorf("--secret (or %s) is required, since the command runs for every request and replies with the account's token; pass --insecure-no-signature to accept unsigned events on localhost", channelSecretEnv))
+	}
+	if !isLoopbackListen(net.JoinHostPort(lf.Bind, strconv.Itoa(lf.Port))) {
+		return withExitCode(ExitUsage, fmt.Errorf("--insecure-no-signature only listens on a loopback address, not %s; set --secret to listen there", lf.Bind))
+	}
+	return nil
+}
+
+func runWebhookListen(cmd *cobra.Command, lf *listenFlags, bridge *eventBridge) error {
+	out := cmd.OutOrStdout()
+	bridge.ctx = cmd.Context()
+
+	handler := &webhookHandler{
+		secret:  lf.Secret,
+		quiet:   true,
+		out:     out,
+		errOut:  cmd.ErrOrStderr(),
+		started: clockNow(),
+		onEvent: bridge.handle,
+	}
+
+	addr := net.JoinHostPort(lf.Bind, strconv.Itoa(lf.Port))
+	url := fmt.Sprintf("http://%s/webhook", addr)
+	return serveWebhookHandler(cmd, handler, addr, func() {
+		_, _ = fmt.Fprintf(out, "Webhook server listening on %s\n", url)
+		_, _ = fmt.Fprintf(out, "Press Ctrl+C to stop\n")
+		if lf.Secret != "" {
+			_, _ = fmt.Fprintf(out, "Signature validation: enabled\n")
+		} else {
+			warnf(cmd.ErrOrStderr(), "signature validation is disabled (--insecure-no-signature); any local program can trigger replies")
+		}
+		if lf.Exec != "" {
+			_, _ = fmt.Fprintf(out, "Running: %s\n", lf.Exec)
+		} else {
+			_, _ = fmt.Fprintf(out, "Running: %s\n", lf.ExecTemplate)
+		}
+		_, _ = fmt.Fprintf(out, "\n")
+	})
+}
+
+// eventBridge runs a command for webhook events and replies with what the
+// command prints.
+type eventBridge struct {
+	client   *api.Client
+	program  string
+	template *template.Template
+	events   []string
+	timeout  time.Duration
+	quiet    bool
+	out      io.Writer
+	errOut   io.Writer
+
+	// ctx bounds every run; nil means context.Background
+	ctx context.Context
+	// mu keeps log lines of concurrent events apart
+	mu sync.Mutex
+}
+
+// eventFields are the parts of an event handed to the command.
+type eventFields struct {
+	Type         string
+	UserID       string
+	ReplyToken   string
+	SourceType   string
+	GroupID      string
+	RoomID       string
+	MessageType  string
+	Text         string
+	PostbackData string
+}
+
+func newEventFields(event LineWebhookEvent) eventFields {
+	f := eventFields{Type: event.Type, ReplyToken: event.ReplyToken}
+	if event.Source != nil {
+		f.SourceType = event.Source.Type
+		f.UserID = event.Source.UserID
+		f.GroupID = event.Source.GroupID
+		f.RoomID = event.Source.RoomID
+	}
+	if len(event.Message) > 0 {
+		var message struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(event.Message, &message); err == nil {
+			f.MessageType = message.Type
+			f.Text = message.Text
+		}
+	}
+	if len(event.Postback) > 0 {
+		var postback struct {
+			Data string `json:"data"`
+		}
+		if err := json.Unmarshal(event.Postback, &postback); err == nil {
+			f.PostbackData = postback.Data
+		}
+	}
+	return f
+}
+
+// env returns the environment variables describing the event.
+func (f eventFields) env() []string {
+	return []string{
+		"EVENT_TYPE=" + f.Type,
+		"USER_ID=" + f.UserID,
+		"REPLY_TOKEN=" + f.ReplyToken,
+		"SOURCE_TYPE=" + f.SourceType,
+		"GROUP_ID=" + f.GroupID,
+		"ROOM_ID=" + f.RoomID,
+		"MESSAGE_TYPE=" + f.MessageType,
+		"MESSAGE_TEXT=" + f.Text,
+		"POSTBACK_DATA=" + f.PostbackData,
+	}
+}
+
+// quoted returns the fields shell-quoted, for --exec-template.
+func (f eventFields) quoted() eventFields {
+	return eventFields{
+		Type:         shellQuote(f.Type),
+		UserID:       shellQuote(f.UserID),
+		ReplyToken:   shellQuote(f.ReplyToken),
+		SourceType:   shellQuote(f.SourceType),
+		GroupID:      shellQuote(f.GroupID),
+		RoomID:       shellQuote(f.RoomID),
+		MessageType:  shellQuote(f.MessageType),
+		Text:         shellQuote(f.Text),
+		PostbackData: shellQuote(f.PostbackData),
+	}
+}
+
+// handle runs the command for one event and sends its output as a reply.
+func (b *eventBridge) handle(raw json.RawMessage, event LineWebhookEvent) {
+	if len(b.events) > 0 && !slices.Contains(b.events, event.Type) {
+		return
+	}
+	fields := newEventFields(event)
+	label := event.Type
+	if fields.UserID != "" {
+		label += " from " + fields.UserID
+	}
+
+	parent := b.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, b.timeout)
+	defer cancel()
+
+	output, err := b.run(ctx, raw, fields)
+	if err != nil {
+		b.logError("%s: %v", label, err)
+		return
+	}
+	if len(output) == 0 {
+		b.log("%s: no reply", label)
+		return
+	}
+	if fields.ReplyToken == "" {
+		b.logError("%s: output ignored, the event has no reply token", label)
+		return
+	}
+
+	messages := replyMessagesFromOutput(output)
+	_, err = b.client.Post(ctx, "/v2/bot/message/reply", api.ReplyMessageRequest{
+		ReplyToken: fields.ReplyToken,
+		Messages:   messages,
+	})
+	if err != nil {
+		b.logError("%s: failed to reply: %v", label, err)
+		return
+	}
+	b.log("%s: replied with %d message(s)", label, len(messages))
+}
+
+// run runs the command with the event on stdin and returns its trimmed
+// stdout.
+func (b *eventBridge) run(ctx context.Context, raw json.RawMessage, fields eventFields) ([]byte, error) {
+	var c *exec.Cmd
+	if b.template != nil {
+		var line bytes.Buffer
+		if err := b.template.Execute(&line, fields.quoted()); err != nil {
+			return nil, fmt.Errorf("failed to render --exec-template: %w", err)
+		}
+		c = exec.CommandContext(ctx, "sh", "-c", line.String())
+	} else {
+		c = exec.CommandContext(ctx, b.program)
+	}
+	c.Env = append(os.Environ(), fields.env()...)
+	c.Stdin = bytes.NewReader(raw)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	if err := c.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("command timed out after %s", b.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("command failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("command failed: %w", err)
+	}
+	if stderr.Len() > 0 && !b.quiet {
+		b.mu.Lock()
+		_, _ = b.errOut.Write(stderr.Bytes())
+		b.mu.Unlock()
+	}
+	return bytes.TrimSpace(stdout.Bytes()), nil
+}
+
+// replyMessagesFromOutput turns command output into reply messages: JSON
+// message objects are sent as they are, anything else as one text message.
+func replyMessagesFromOutput(output []byte) []any {
+	if json.Valid(output) {
+		if raw, err := parseValidateMessages(output); err == nil && len(raw) > 0 {
+			messages := make([]any, len(raw))
+			for i, m := range raw {
+				messages[i] = m
+			}
+			return messages
+		}
+	}
+	return []any{api.TextMessage{Type: "text", Text: string(output)}}
+}
+
+func (b *eventBridge) log(format string, args ...any) {
+	if b.quiet {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, _ = fmt.Fprintf(b.out, "[%s] %s\n", clockNow().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+func (b *eventBridge) logError(format string, args ...any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, _ = fmt.Fprintf(b.errOut, "[%s] %s\n", clockNow().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+const listenTestEvent = `{"type":"message","replyToken":"rt-1","source":{"type":"user","userId":"U1"},"message":{"type":"text","text":"it's me"},"webhookEventId":"E1"}`
+
+// replyServer records the body of every reply request.
+func replyServer(t *testing.T, replies *[]api.ReplyMessageRequest) *api.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/bot/message/reply" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		var req api.ReplyMessageRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		*replies = append(*replies, req)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func listenTestBridge(t *testing.T, client *api.Client, script string) (*eventBridge, *bytes.Buffer) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "handler.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var errOut bytes.Buffer
+	return &eventBridge{
+		client:  client,
+		program: path,
+		timeout: 5 * time.Second,
+		out:     io.Discard,
+		errOut:  &errOut,
+	}, &errOut
+}
+
+func handleListenTestEvent(b *eventBridge) {
+	var event LineWebhookEvent
+	_ = json.Unmarshal([]byte(listenTestEvent), &event)
+	b.handle(json.RawMessage(listenTestEvent), event)
+}
+
+func TestEventBridge_RepliesWithOutput(t *testing.T) {
+	var replies []api.ReplyMessageRequest
+	b, errOut := listenTestBridge(t, replyServer(t, &replies),
+		`read event; echo "$EVENT_TYPE $USER_ID $REPLY_TOKEN: $MESSAGE_TEXT"; echo "$event" | grep -q webhookEventId && echo stdin ok`)
+	handleListenTestEvent(b)
+
+	if len(replies) != 1 {
+		t.Fatalf("expected 1 reply, got %d (errors: %s)", len(replies), errOut)
+	}
+	if replies[0].ReplyToken != "rt-1" {
+		t.Errorf("unexpected reply token %q", replies[0].ReplyToken)
+	}
+	m, _ := replies[0].Messages[0].(map[string]any)
+	if m["type"] != "text" || m["text"] != "message U1 rt-1: it's me\nstdin ok" {
+		t.Errorf("unexpected reply %v", replies[0].Messages[0])
+	}
+}
+
+func TestEventBridge_JSONOutput(t *testing.T) {
+	var replies []api.ReplyMessageRequest
+	b, _ := listenTestBridge(t, replyServer(t, &replies),
+		`echo '[{"type":"sticker","packageId":"446","stickerId":"1988"},{"type":"text","text":"hi"}]'`)
+	handleListenTestEvent(b)
+
+	if len(replies) != 1 || len(replies[0].Messages) != 2 {
+		t.Fatalf("expected 2 messages in 1 reply, got %+v", replies)
+	}
+	if m, _ := replies[0].Messages[0].(map[string]any); m["type"] != "sticker" {
+		t.Errorf("unexpected first message %v", replies[0].Messages[0])
+	}
+}
+
+func TestEventBridge_NoReply(t *testing.T) {
+	tests := []struct {
+		name, script, wantErr string
+	}{
+		{"empty output", `true`, ""},
+		{"failing command", `echo broken >&2; exit 3`, "command failed: exit status 3: broken"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replies []api.ReplyMessageRequest
+			b, errOut := listenTestBridge(t, replyServer(t, &replies), tt.script)
+			handleListenTestEvent(b)
+			if len(replies) != 0 {
+				t.Errorf("expected no reply, got %+v", replies)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErr) {
+				t.Errorf("errors = %q, want %q", errOut, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventBridge_EventFilter(t *testing.T) {
+	var replies []api.ReplyMessageRequest
+	b, _ := listenTestBridge(t, replyServer(t, &replies), `echo hi`)
+	b.events = []string{"postback"}
+	handleListenTestEvent(b)
+	if len(replies) != 0 {
+		t.Errorf("expected the message event to be skipped, got %+v", replies)
+	}
+}
+
+func TestEventBridge_TemplateQuotesFields(t *testing.T) {
+	var replies []api.ReplyMessageRequest
+	b, errOut := listenTestBridge(t, replyServer(t, &replies), "")
+	b.program = ""
+	b.template = template.Must(template.New("exec").Parse(`printf '%s|%s' {{.Type}} {{.Text}}`))
+	handleListenTestEvent(b)
+
+	if len(replies) != 1 {
+		t.Fatalf("expected 1 reply, got %d (errors: %s)", len(replies), errOut)
+	}
+	if m := replies[0].Messages[0].(map[string]any); m["text"] != "message|it's me" {
+		t.Errorf("unexpected reply %v", m)
+	}
+}
+
+func TestWebhookHandler_DispatchesEvents(t *testing.T) {
+	got := make(chan string, 2)
+	handler := &webhookHandler{
+		quiet:  true,
+		out:    io.Discard,
+		errOut: io.Discard,
+		onEvent: func(raw json.RawMessage, event LineWebhookEvent) {
+			got <- event.Type + " " + string(raw)
+		},
+	}
+	body := `{"destination":"U0","events":[` + listenTestEvent + `]}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.handleWebhook(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	select {
+	case s := <-got:
+		if s != "message "+listenTestEvent {
+			t.Errorf("unexpected event %s", s)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not dispatched")
+	}
+}
+
+func TestWebhookListenCmd_RequiresOneCommand(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"--exec", "./a.sh", "--exec-template", "echo hi"},
+	} {
+		cmd := newWebhookListenCmdWithClient(api.NewClient("token", false, false))
+		cmd.SetArgs(args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "exactly one of --exec or --exec-template") {
+			t.Errorf("args %v: expected usage error, got %v", args, err)
+		}
+	}
+}
+
+func TestWebhookListenCmd_RequiresSignature(t *testing.T) {
+	t.Setenv(channelSecretEnv, "")
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--exec", "./a.sh"}, "--secret (or LINE_CHANNEL_SECRET) is required"},
+		{[]string{"--exec", "./a.sh", "--insecure-no-signature", "--bind", "0.0.0.0"}, "only listens on a loopback address"},
+		{[]string{"--exec", "./a.sh", "--insecure-no-signature", "--bind", ""}, "only listens on a loopback address"},
+	}
+	for _, tt := range tests {
+		cmd := newWebhookListenCmdWithClient(api.NewClient("token", false, false))
+		cmd.SetArgs(tt.args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) || ExitCode(err) != ExitUsage {
+			t.Errorf("args %v: expected usage error containing %q, got %v", tt.args, tt.wantErr, err)
+		}
+	}
+
+	for _, lf := range []listenFlags{
+		{Secret: "s", Bind: "0.0.0.0", Port: 8080},
+		{Insecure: true, Bind: "127.0.0.1", Port: 8080},
+		{Insecure: true, Bind: "localhost", Port: 8080},
+	} {
+		if err := checkListenSecurity(&lf); err != nil {
+			t.Errorf("checkListenSecurity(%+v): unexpected error %v", lf, err)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -59,15 +60,19 @@ func newWebhookServeCmd() *cobra.Command {
 	sf := &serveFlags{}
 
 	cmd := &cobra.Command{
-		Use:   "serve",
-		Short: "Start a local webhook server for development",
+		Use:         "serve",
+		Short:       "Start a local webhook server for development",
+		Annotations: map[string]string{noPagerAnnotation: ""},
 		Long: `Start a local HTTP server to receive LINE webhook events during development.
 
 The server logs incoming webhook events in a human-readable format,
 making it easy to debug and test your LINE bot.
 
 If --secret is provided, the server validates webhook signatures using HMAC-SHA256.
-If --forward is provided, events are forwarded to the specified URL after logging.`,
+If --forward is provided, events are forwarded to the specified URL after logging.
+
+GET /stats returns request and event counts since startup as JSON, which
+"line insight overview --webhook-stats" uses to show the event rate.`,
 		Example: `  # Basic: just log events
   line webhook serve
 
@@ -88,7 +93,7 @@ If --forward is provided, events are forwarded to the specified URL after loggin
 	}
 
 	cmd.Flags().IntVarP(&sf.Port, "port", "p", 8080, "Port to listen on")
-	cmd.Flags().StringVar(&sf.Secret, "secret", "", "Channel secret for signature validation")
+	cmd.Flags().StringVar(&sf.Secret, "secret", "", "Channel secret for signature validation (or LINE_CHANNEL_SECRET env)")
 	cmd.Flags().StringVar(&sf.Forward, "forward", "", "URL to forward events to after logging")
 	cmd.Flags().BoolVarP(&sf.Quiet, "quiet", "q", false, "Only show errors, no event logging")
 
@@ -96,6 +101,7 @@ If --forward is provided, events are forwarded to the specified URL after loggin
 }
 
 func runWebhookServe(cmd *cobra.Command, sf *serveFlags) error {
+	sf.Secret = getDefault(sf.Secret, os.Getenv(channelSecretEnv))
 	out := cmd.OutOrStdout()
 	errOut := cmd.ErrOrStderr()
 
@@ -106,14 +112,35 @@ func runWebhookServe(cmd *cobra.Command, sf *serveFlags) error {
 		quiet:   sf.Quiet,
 		out:     out,
 		errOut:  errOut,
+		started: clockNow(),
 	}
 
+	url := fmt.Sprintf("http://localhost:%d/webhook", sf.Port)
+	return serveWebhookHandler(cmd, handler, fmt.Sprintf(":%d", sf.Port), func() {
+		_, _ = fmt.Fprintf(out, "Webhook server listening on %s\n", url)
+		_, _ = fmt.Fprintf(out, "Press Ctrl+C to stop\n")
+		if sf.Secret != "" {
+			_, _ = fmt.Fprintf(out, "Signature validation: enabled\n")
+		}
+		if sf.Forward != "" {
+			_, _ = fmt.Fprintf(out, "Forwarding to: %s\n", sf.Forward)
+		}
+		_, _ = fmt.Fprintf(out, "\n")
+	})
+}
+
+// serveWebhookHandler serves handler on addr until the command is
+// interrupted, calling banner once the server has started.
+func serveWebhookHandler(cmd *cobra.Command, handler *webhookHandler, addr string, banner func()) error {
+	out := cmd.OutOrStdout()
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/webhook", handler.handleWebhook)
+	mux.HandleFunc("/stats", handler.handleStats)
 	mux.HandleFunc("/", handler.handleRoot)
 
 	server := &http.Server{
-		Addr:    fmt.Sprintf(":%d", sf.Port),
+		Addr:    addr,
 		Handler: mux,
 	}
 
@@ -130,16 +157,7 @@ func runWebhookServe(cmd *cobra.Command, sf *serveFlags) error {
 	}()
 
 	// Print startup message
-	url := fmt.Sprintf("http://localhost:%d/webhook", sf.Port)
-	_, _ = fmt.Fprintf(out, "Webhook server listening on %s\n", url)
-	_, _ = fmt.Fprintf(out, "Press Ctrl+C to stop\n")
-	if sf.Secret != "" {
-		_, _ = fmt.Fprintf(out, "Signature validation: enabled\n")
-	}
-	if sf.Forward != "" {
-		_, _ = fmt.Fprintf(out, "Forwarding to: %s\n", sf.Forward)
-	}
-	_, _ = fmt.Fprintf(out, "\n")
+	banner()
 
 	// Wait for shutdown signal or server error
 	select {
@@ -168,6 +186,31 @@ type webhookHandler struct {
 	quiet   bool
 	out     io.Writer
 	errOut  io.Writer
+
+	// onEvent, when set, is called in its own goroutine for every event
+	// of a valid payload, with the event as received
+	onEvent func(raw json.RawMessage, event LineWebhookEvent)
+
+	// started, requests, and events back the /stats endpoint
+	started  time.Time
+	requests atomic.Int64
+	events   atomic.Int64
+}
+
+// webhookStats is the /stats response.
+type webhookStats struct {
+	Since    time.Time `json:"since"`
+	Requests int64     `json:"requests"`
+	Events   int64     `json:"events"`
+}
+
+func (h *webhookHandler) handleStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(webhookStats{
+		Since:    h.started,
+		Requests: h.requests.Load(),
+		Events:   h.events.Load(),
+	})
 }
 
 func (h *webhookHandler) handleRoot(w http.ResponseWriter, r *http.Request) {
@@ -177,7 +220,7 @@ func (h *webhookHandler) handleRoot(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *webhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	timestamp := clockNow().Format("2006-01-02 15:04:05")
 
 	// Only accept POST requests
 	if r.Method != http.MethodPost {
@@ -210,6 +253,8 @@ func (h *webhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.requests.Add(1)
+
 	// Parse and log events
 	var payload LineWebhookPayload
 	if err := json.Unmarshal(body, &payload); err != nil {
@@ -219,10 +264,14 @@ func (h *webhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 			_, _ = fmt.Fprintf(h.out, "Raw body: %s\n\n", string(body))
 		}
 	} else {
+		h.events.Add(int64(len(payload.Events)))
 		h.logRequest(timestamp, http.StatusOK)
 		if !h.quiet {
 			h.logPayload(&payload)
 		}
+		if h.onEvent != nil {
+			h.dispatchEvents(body, payload.Events)
+		}
 	}
 
 	// Forward to another URL if configured
@@ -236,6 +285,20 @@ func (h *webhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// dispatchEvents hands each event to onEvent. The raw events are decoded
+// again so fields LineWebhookEvent does not model reach the callback too.
+func (h *webhookHandler) dispatchEvents(body []byte, events []LineWebhookEvent) {
+	var raw struct {
+		Events []json.RawMessage `json:"events"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil || len(raw.Events) != len(events) {
+		return
+	}
+	for i, event := range events {
+		go h.onEvent(raw.Events[i], event)
+	}
+}
+
 func (h *webhookHandler) validateSignature(body []byte, signature string) bool {
 	mac := hmac.New(sha256.New, []byte(h.secret))
 	mac.Write(body)
@@ -699,3 +699,28 @@ func TestWebhookHandler_HandleWebhook_RoomWithoutUserID(t *testing.T) {
 		t.Errorf("expected room ID in output, got: %s", output)
 	}
 }
+
+func TestWebhookHandler_HandleStats(t *testing.T) {
+	handler := &webhookHandler{
+		quiet:  true,
+		out:    io.Discard,
+		errOut: io.Discard,
+	}
+
+	body := `{"destination":"U1","events":[{"type":"message"},{"type":"follow"}]}`
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		handler.handleWebhook(httptest.NewRecorder(), req)
+	}
+
+	w := httptest.NewRecorder()
+	handler.handleStats(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+
+	var stats webhookStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+	if stats.Requests != 2 || stats.Events != 4 {
+		t.Errorf("expected 2 requests and 4 events, got %+v", stats)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,15 +13,89 @@ import (
 type Config struct {
 	// Account is the default account name to use
 	Account string `yaml:"account,omitempty"`
-	// Output is the default output format (text, json, or table)
+	// Output is the default output format (text, json, table, yaml, csv, or ndjson)
 	Output string `yaml:"output,omitempty"`
 	// Debug enables debug output by default
 	Debug bool `yaml:"debug,omitempty"`
+	// CredentialStore is where credentials are kept: keychain (the default,
+	// falling back to file if there is no OS keychain), file, or
+	// encrypted-file
+	CredentialStore string `yaml:"credential_store,omitempty"`
+	// Timezone is the IANA zone times are shown and parsed in (default:
+	// local time)
+	Timezone string `yaml:"timezone,omitempty"`
+	// APIBaseURL replaces https://api.line.me, e.g. for a proxy or mock server
+	APIBaseURL string `yaml:"api_base_url,omitempty"`
+	// Color controls colored output: auto (the default), always, or never
+	Color string `yaml:"color,omitempty"`
+	// Proxy is the proxy URL for API requests (default: HTTPS_PROXY and
+	// NO_PROXY from the environment)
+	Proxy string `yaml:"proxy,omitempty"`
+	// CACert is a PEM file of extra CA certificates to trust, such as a
+	// TLS-intercepting proxy's
+	CACert string `yaml:"ca_cert,omitempty"`
+	// Freeze configures time windows during which sends are blocked
+	Freeze FreezeConfig `yaml:"freeze,omitempty"`
+	// Failover configures secondary API hosts used when the primary ones fail
+	Failover FailoverConfig `yaml:"failover,omitempty"`
+	// Budgets are expected durations keyed by command class (list or bulk)
+	// or command path (e.g. "audience add-users"); a command that runs
+	// longer prints a warning with its slowest API calls
+	Budgets map[string]time.Duration `yaml:"budgets,omitempty"`
+	// RateLimits override the client-side request rate per endpoint class
+	// (default, multicast, broadcast, narrowcast, audience, profile,
+	// followers), e.g. "100/s"; "off" disables the limiter
+	RateLimits map[string]string `yaml:"rate_limits,omitempty"`
 
 	// path stores where this config was loaded from (not serialized)
 	path string `yaml:"-"`
 }
 
+// FreezeConfig describes campaign freeze windows enforced client-side
+// before any send command contacts the API.
+type FreezeConfig struct {
+	// Timezone is the IANA zone the windows are evaluated in (default: local time)
+	Timezone string `yaml:"timezone,omitempty"`
+	// Windows are recurring daily time ranges during which sends are blocked
+	Windows []FreezeWindow `yaml:"windows,omitempty"`
+	// HolidaysFile lists dates (YYYY-MM-DD, one per line) that are frozen all day
+	HolidaysFile string `yaml:"holidays_file,omitempty"`
+}
+
+// FreezeWindow is a recurring daily freeze window. Start and End are HH:MM
+// in the freeze timezone; a window whose End is before its Start wraps past
+// midnight (e.g. 22:00-08:00).
+type FreezeWindow struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// Days restricts the window to these weekdays (mon..sun); empty means
+	// every day. A window that wraps past midnight runs into the next day,
+	// so "fri" with 22:00-08:00 also covers early Saturday.
+	Days []string `yaml:"days,omitempty"`
+	// Sends restricts the window to these send types (push, multicast,
+	// broadcast, narrowcast); empty means all of them
+	Sends []string `yaml:"sends,omitempty"`
+}
+
+// FailoverConfig sets secondary base URLs for the LINE API hosts. A host
+// whose requests keep failing is skipped for Cooldown after Threshold
+// consecutive failures.
+type FailoverConfig struct {
+	// API is the secondary base URL for https://api.line.me
+	API string `yaml:"api,omitempty"`
+	// Data is the secondary base URL for https://api-data.line.me
+	Data string `yaml:"data,omitempty"`
+	// Threshold is the consecutive failures that open a host's circuit (default 3)
+	Threshold int `yaml:"threshold,omitempty"`
+	// Cooldown is how long an open circuit skips the host (default 30s)
+	Cooldown time.Duration `yaml:"cooldown,omitempty"`
+}
+
+// IsZero reports whether no freeze rules are configured.
+func (f FreezeConfig) IsZero() bool {
+	return len(f.Windows) == 0 && f.HolidaysFile == ""
+}
+
 // ConfigPath returns the path where this config was loaded from.
 // Returns empty string if config was not loaded from a file.
 func (c *Config) ConfigPath() string {
@@ -76,6 +151,16 @@ func Load() (*Config, error) {
 	return &Config{}, nil
 }
 
+// LoadFile reads the config file at path.
+func LoadFile(path string) (*Config, error) {
+	cfg, err := loadFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.path = path
+	return cfg, nil
+}
+
 // loadFromPath loads config from a specific path.
 func loadFromPath(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -108,10 +193,70 @@ func ExampleConfig() string {
 # Default account name (can be overridden with --account or LINE_ACCOUNT)
 # account: my-account
 
-# Default output format: text, json, or table (can be overridden with --output or LINE_OUTPUT)
+# Default output format: text, json, table, yaml, csv, or ndjson (can be overridden with --output or LINE_OUTPUT)
 # output: text
 
-# Enable debug output by default (can be overridden with --debug)
+# Enable debug output by default (can be overridden with --debug or LINE_DEBUG)
 # debug: false
+
+# Time zone that times are shown and parsed in (can be overridden with
+# LINE_TIMEZONE; default: local time)
+# timezone: Asia/Tokyo
+
+# Base URL for LINE API requests instead of https://api.line.me, e.g. a proxy
+# or mock server (can be overridden with LINE_API_BASE_URL)
+# api_base_url: https://line-proxy.example.com
+
+# Colored output: auto (when writing to a terminal and NO_COLOR is unset),
+# always, or never (can be overridden with --color or LINE_COLOR)
+# color: auto
+
+# Corporate networks: send API requests through this proxy (http, https, or
+# socks5; default HTTPS_PROXY and NO_PROXY from the environment) and trust
+# the CA certificates in this PEM file, e.g. a TLS-intercepting proxy's
+# (can be overridden with --proxy and --ca-cert)
+# proxy: http://proxy.example.com:8080
+# ca_cert: ~/.config/line-cli/corporate-ca.pem
+
+# Where credentials are stored: keychain (OS keychain, the default; falls back
+# to file when none is available), file (plaintext, readable only by you), or
+# encrypted-file (unlocked with LINE_CREDENTIALS_PASSPHRASE or a prompt).
+# Move existing credentials with: line account migrate-store --to encrypted-file
+# credential_store: keychain
+
+# Campaign freeze windows: sends are refused while a window is active unless
+# --override-freeze "reason" is given (overrides are recorded in the audit log)
+# freeze:
+#   timezone: Asia/Tokyo
+#   windows:
+#     - start: "22:00"
+#       end: "08:00"
+#       sends: [broadcast, narrowcast]
+#   holidays_file: ~/.config/line-cli/holidays.txt
+
+# Failover hosts: requests that fail on api.line.me / api-data.line.me are
+# retried on these base URLs, and a host is skipped for the cooldown after
+# repeated failures
+# failover:
+#   api: https://line-proxy.example.com
+#   data: https://line-data-proxy.example.com
+#   threshold: 3
+#   cooldown: 30s
+
+# Timing budgets: warn with the slowest API calls when a command takes longer
+# than expected. Keys are command classes (list: list commands, bulk: richmenu
+# bulk and batch commands) or command paths, which take precedence
+# budgets:
+#   list: 3s
+#   bulk: 10m
+#   audience add-users: 2m
+
+# Client-side rate limits per endpoint class, so bulk commands stay under
+# LINE's limits (defaults: 2000/s, multicast 200/s, broadcast and narrowcast
+# 60/h, audience 60/m). Classes: default, multicast, broadcast, narrowcast,
+# audience, profile, followers. Override with --rate-limit
+# rate_limits:
+#   multicast: 100/s
+#   profile: 500/s
 `
 }
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoad_NoConfigFile(t *testing.T) {
@@ -243,6 +244,133 @@ func TestExampleConfig(t *testing.T) {
 	}
 }
 
+func TestLoad_FreezeConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(tmpDir, AppName)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `freeze:
+  timezone: Asia/Tokyo
+  windows:
+    - start: "22:00"
+      end: "08:00"
+      sends: [broadcast]
+  holidays_file: /tmp/holidays.txt
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Freeze.IsZero() {
+		t.Fatal("expected freeze config to be loaded")
+	}
+	if cfg.Freeze.Timezone != "Asia/Tokyo" {
+		t.Errorf("Freeze.Timezone = %q, want %q", cfg.Freeze.Timezone, "Asia/Tokyo")
+	}
+	if len(cfg.Freeze.Windows) != 1 || cfg.Freeze.Windows[0].Start != "22:00" || cfg.Freeze.Windows[0].Sends[0] != "broadcast" {
+		t.Errorf("unexpected windows: %+v", cfg.Freeze.Windows)
+	}
+	if cfg.Freeze.HolidaysFile != "/tmp/holidays.txt" {
+		t.Errorf("Freeze.HolidaysFile = %q", cfg.Freeze.HolidaysFile)
+	}
+}
+
+func TestLoad_FailoverConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(tmpDir, AppName)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `failover:
+  api: https://line-proxy.example.com
+  threshold: 5
+  cooldown: 2m
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := FailoverConfig{API: "https://line-proxy.example.com", Threshold: 5, Cooldown: 2 * time.Minute}
+	if cfg.Failover != want {
+		t.Errorf("Failover = %+v, want %+v", cfg.Failover, want)
+	}
+}
+
+func TestLoad_BudgetsConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(tmpDir, AppName)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `budgets:
+  list: 3s
+  audience add-users: 10m
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Budgets["list"] != 3*time.Second || cfg.Budgets["audience add-users"] != 10*time.Minute {
+		t.Errorf("Budgets = %v", cfg.Budgets)
+	}
+}
+
+func TestLoad_RateLimitsConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(tmpDir, AppName)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := `rate_limits:
+  multicast: 100/s
+  profile: 500/s
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.RateLimits["multicast"] != "100/s" || cfg.RateLimits["profile"] != "500/s" {
+		t.Errorf("RateLimits = %v", cfg.RateLimits)
+	}
+}
+
 func TestDefaultConfigPath(t *testing.T) {
 	path, err := DefaultConfigPath()
 	if err != nil {
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// settableKeys validates and normalizes the values of the keys that Set
+// can change.
+var settableKeys = map[string]func(value string) (string, error){
+	"account": func(value string) (string, error) { return value, nil },
+	"output": func(value string) (string, error) {
+		switch value {
+		case "text", "json", "table", "yaml", "csv", "ndjson":
+			return value, nil
+		}
+		return "", fmt.Errorf("must be text, json, table, yaml, csv, or ndjson")
+	},
+	"debug": func(value string) (string, error) {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("must be true or false")
+		}
+		return strconv.FormatBool(b), nil
+	},
+	"credential_store": func(value string) (string, error) {
+		switch value {
+		case "keychain", "file", "encrypted-file":
+			return value, nil
+		}
+		return "", fmt.Errorf("must be keychain, file, or encrypted-file")
+	},
+	"timezone": func(value string) (string, error) {
+		if _, err := time.LoadLocation(value); err != nil || value == "" {
+			return "", fmt.Errorf("must be an IANA time zone such as Asia/Tokyo")
+		}
+		return value, nil
+	},
+	"api_base_url": func(value string) (string, error) {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("must be an http or https URL")
+		}
+		return strings.TrimRight(value, "/"), nil
+	},
+	"color": func(value string) (string, error) {
+		switch value {
+		case "auto", "always", "never":
+			return value, nil
+		}
+		return "", fmt.Errorf("must be auto, always, or never")
+	},
+	"proxy": func(value string) (string, error) {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			return "", fmt.Errorf("must be an http, https, or socks5 URL")
+		}
+		return value, nil
+	},
+	"ca_cert": func(value string) (string, error) {
+		if value == "" {
+			return "", fmt.Errorf("must be the path of a PEM file")
+		}
+		return value, nil
+	},
+}
+
+// SettableKeys returns the keys Set accepts.
+func SettableKeys() []string {
+	return []string{"account", "output", "debug", "credential_store", "timezone", "api_base_url", "color", "proxy", "ca_cert"}
+}
+
+// normalizeKey returns key with dashes written as underscores, or an error
+// if it is not a settable key.
+func normalizeKey(key string) (string, error) {
+	key = strings.ReplaceAll(strings.TrimSpace(key), "-", "_")
+	if _, ok := settableKeys[key]; !ok {
+		return "", fmt.Errorf("unknown config key %q (settable: %s)", key, strings.Join(SettableKeys(), ", "))
+	}
+	return key, nil
+}
+
+// Get returns the value of a settable key as written in the config file,
+// and whether it is set. Keys may be written with dashes.
+func (c *Config) Get(key string) (string, bool, error) {
+	key, err := normalizeKey(key)
+	if err != nil {
+		return "", false, err
+	}
+	var value string
+	switch key {
+	case "account":
+		value = c.Account
+	case "output":
+		value = c.Output
+	case "debug":
+		// false cannot be told apart from unset
+		if c.Debug {
+			value = "true"
+		}
+	case "credential_store":
+		value = c.CredentialStore
+	case "timezone":
+		value = c.Timezone
+	case "api_base_url":
+		value = c.APIBaseURL
+	case "color":
+		value = c.Color
+	case "proxy":
+		value = c.Proxy
+	case "ca_cert":
+		value = c.CACert
+	}
+	return value, value != "", nil
+}
+
+// Validate checks the settable values of a loaded config, so a hand-edited
+// file can be checked the same way Set checks its input.
+func (c *Config) Validate() error {
+	for _, key := range SettableKeys() {
+		value, ok, _ := c.Get(key)
+		if !ok {
+			continue
+		}
+		if _, err := settableKeys[key](value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Set writes key: value to the config file in use, or creates one at the
+// first config path, keeping the rest of the file (including comments)
+// as it is. Keys may be written with dashes (credential-store). It returns
+// the path written.
+func Set(key, value string) (string, error) {
+	key, err := normalizeKey(key)
+	if err != nil {
+		return "", err
+	}
+	value, err = settableKeys[key](strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	path, err := WritablePath()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	doc, err := readDocument(path)
+	if err != nil {
+		return "", err
+	}
+	setMappingValue(doc.Content[0], key, value)
+	if err := writeDocument(path, doc); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Unset removes key from the config file in use, keeping the rest of the
+// file as it is. It returns the path of the file and whether the key was
+// there.
+func Unset(key string) (string, bool, error) {
+	key, err := normalizeKey(key)
+	if err != nil {
+		return "", false, err
+	}
+	path, err := WritablePath()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	doc, err := readDocument(path)
+	if err != nil {
+		return "", false, err
+	}
+	if !removeMappingKey(doc.Content[0], key) {
+		return path, false, nil
+	}
+	if err := writeDocument(path, doc); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// readDocument parses the config file at path, or returns an empty mapping
+// document if it does not exist.
+func readDocument(path string) (*yaml.Node, error) {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to update config %s: top level is not a mapping", path)
+	}
+	return &doc, nil
+}
+
+// writeDocument encodes doc to path, creating the config directory if needed.
+func writeDocument(path string, doc *yaml.Node) error {
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	_ = enc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// setMappingValue sets key to a scalar value in a mapping node, replacing an
+// existing value or appending the key.
+func setMappingValue(m *yaml.Node, key, value string) {
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	if key == "debug" {
+		valueNode.Tag = "!!bool"
+	} else {
+		valueNode.Tag = "!!str"
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			valueNode.HeadComment = m.Content[i+1].HeadComment
+			valueNode.LineComment = m.Content[i+1].LineComment
+			m.Content[i+1] = valueNode
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+}
+
+// removeMappingKey deletes key and its value from a mapping node, reporting
+// whether it was there.
+func removeMappingKey(m *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// WritablePath returns the config file in use, or the first config path if
+// there is none yet.
+func WritablePath() (string, error) {
+	paths, err := configPaths()
+	if err != nil {
+		return "", err
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return paths[0], nil
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet_CreatesFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := Set("credential-store", "file")
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if want := filepath.Join(tmpDir, AppName, "config.yaml"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.CredentialStore != "file" {
+		t.Errorf("CredentialStore = %q", cfg.CredentialStore)
+	}
+}
+
+func TestSet_KeepsExistingContent(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(tmpDir, AppName)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `# My settings
+account: shop # the main shop
+output: json
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Set("account", "staging"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := Set("debug", "1"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(configDir, "config.yaml"))
+	got := string(data)
+	for _, want := range []string{"# My settings", "account: staging # the main shop", "output: json", "debug: true"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in config, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestSet_Validation(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct{ key, value string }{
+		{"credential_store", "vault"},
+		{"output", "xml"},
+		{"debug", "maybe"},
+		{"timezone", "Mars/Olympus"},
+		{"api_base_url", "line-proxy.example.com"},
+		{"color", "sometimes"},
+		{"proxy", "proxy.example.com:8080"},
+		{"ca_cert", ""},
+		{"freeze", "x"},
+	}
+	for _, tt := range tests {
+		if _, err := Set(tt.key, tt.value); err == nil {
+			t.Errorf("Set(%q, %q): expected error", tt.key, tt.value)
+		}
+	}
+}
+
+func TestSet_NormalizesValues(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := Set("api-base-url", "https://proxy.example.com/"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := Set("timezone", "Asia/Tokyo"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.APIBaseURL != "https://proxy.example.com" || cfg.Timezone != "Asia/Tokyo" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if v, ok, err := cfg.Get("api-base-url"); err != nil || !ok || v != "https://proxy.example.com" {
+		t.Errorf("Get = %q, %v, %v", v, ok, err)
+	}
+	if _, ok, _ := cfg.Get("color"); ok {
+		t.Error("expected color to be unset")
+	}
+	if _, _, err := cfg.Get("freeze"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestUnset(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := filepath.Join(tmpDir, AppName)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `# My settings
+account: shop
+timezone: Asia/Tokyo # for campaigns
+output: json
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, removed, err := Unset("timezone"); err != nil || !removed {
+		t.Fatalf("Unset = %v, %v", removed, err)
+	}
+	if _, removed, err := Unset("timezone"); err != nil || removed {
+		t.Fatalf("second Unset = %v, %v", removed, err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(configDir, "config.yaml"))
+	got := string(data)
+	if strings.Contains(got, "timezone") {
+		t.Errorf("expected timezone to be removed, got:\n%s", got)
+	}
+	for _, want := range []string{"# My settings", "account: shop", "output: json"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q to be kept, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Config{Output: "json", Timezone: "UTC", Color: "never"}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (&Config{Color: "rainbow"}).Validate(); err == nil || !strings.Contains(err.Error(), "color") {
+		t.Errorf("expected color error, got %v", err)
+	}
+}
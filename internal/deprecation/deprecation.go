@@ -0,0 +1,82 @@
+// Package deprecation remembers which API deprecation notices have been
+// shown, so each endpoint's notice is printed at most once per day.
+package deprecation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// mu serializes read-modify-write cycles on the state file within a process.
+var mu sync.Mutex
+
+// Path returns the location of the notice state file.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "deprecations.json"), nil
+}
+
+// Load returns the day (YYYY-MM-DD) each endpoint's notice was last shown.
+// A missing file yields an empty map.
+func Load() (map[string]string, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deprecation notices: %w", err)
+	}
+	shown := map[string]string{}
+	if err := json.Unmarshal(data, &shown); err != nil {
+		return nil, fmt.Errorf("invalid deprecation notices file: %w", err)
+	}
+	return shown, nil
+}
+
+func save(shown map[string]string) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(shown, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Claim reports whether the notice for endpoint should be shown at now,
+// and records it as shown if so. It returns false if the notice was
+// already shown on the same local day.
+func Claim(endpoint string, now time.Time) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	shown, err := Load()
+	if err != nil {
+		return false, err
+	}
+	today := now.Format("2006-01-02")
+	if shown[endpoint] == today {
+		return false, nil
+	}
+	shown[endpoint] = today
+	return true, save(shown)
+}
@@ -0,0 +1,51 @@
+package deprecation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClaim_OncePerDay(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	if ok, err := Claim("GET /v2/bot/info", now); err != nil || !ok {
+		t.Fatalf("first Claim() = %v, %v; want true", ok, err)
+	}
+	if ok, _ := Claim("GET /v2/bot/info", now.Add(3*time.Hour)); ok {
+		t.Error("expected second claim on the same day to be refused")
+	}
+	if ok, _ := Claim("POST /v2/bot/message/push", now.Add(3*time.Hour)); !ok {
+		t.Error("expected a different endpoint to be claimed")
+	}
+	if ok, _ := Claim("GET /v2/bot/info", now.Add(24*time.Hour)); !ok {
+		t.Error("expected claim to succeed again the next day")
+	}
+
+	shown, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shown["GET /v2/bot/info"] != "2026-03-02" {
+		t.Errorf("unexpected state: %v", shown)
+	}
+}
+
+func TestLoad_InvalidFile(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Claim("GET /v2/bot/info", time.Now()); err == nil {
+		t.Error("expected error for invalid state file")
+	}
+}
@@ -0,0 +1,278 @@
+// Package followersnapshot keeps snapshots of an account's follower IDs, so
+// the users gained and lost between two snapshots can be reported.
+//
+// Snapshots are kept per account as plain text files, one user ID per line
+// in sorted order after a # header line, so they are also valid users
+// files. Sorted files let two snapshots be compared by merging them line by
+// line, whatever their size.
+package followersnapshot
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// idLayout formats the time a snapshot was taken into its ID.
+const idLayout = "20060102T150405Z"
+
+// ErrNotFound is returned by Find when no snapshot matches.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Snapshot describes a stored snapshot.
+type Snapshot struct {
+	ID        string    `json:"id"`
+	TakenAt   time.Time `json:"takenAt"`
+	Followers int       `json:"followers"`
+	Path      string    `json:"path"`
+}
+
+// Dir returns the directory holding an account's snapshots.
+func Dir(account string) (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "follower-snapshots", url.PathEscape(account)), nil
+}
+
+// Save stores ids as a snapshot taken at takenAt. ids is sorted in place,
+// and an ID listed twice, as when a follower is added between pages, is
+// stored once.
+func Save(account string, takenAt time.Time, ids []string) (Snapshot, error) {
+	dir, err := Dir(account)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	takenAt = takenAt.UTC().Truncate(time.Second)
+	snap := Snapshot{ID: takenAt.Format(idLayout), TakenAt: takenAt}
+	snap.Path = filepath.Join(dir, snap.ID+".txt")
+	if _, err := os.Stat(snap.Path); err == nil {
+		return Snapshot{}, fmt.Errorf("snapshot %s already exists", snap.ID)
+	}
+
+	sort.Strings(ids)
+	ids = slices.Compact(ids)
+	snap.Followers = len(ids)
+	f, err := os.CreateTemp(dir, ".snapshot-")
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	_, err = fmt.Fprintf(w, "# followers %d taken %s\n", len(ids), takenAt.Format(time.RFC3339))
+	for _, id := range ids {
+		if err != nil {
+			break
+		}
+		_, err = w.WriteString(id + "\n")
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), snap.Path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return Snapshot{}, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// List returns an account's snapshots, oldest first.
+func List(account string) ([]Snapshot, error) {
+	dir, err := Dir(account)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	snaps := []Snapshot{}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".txt")
+		if e.IsDir() || !ok {
+			continue
+		}
+		takenAt, err := time.Parse(idLayout, id)
+		if err != nil {
+			continue
+		}
+		snap := Snapshot{ID: id, TakenAt: takenAt, Path: filepath.Join(dir, e.Name())}
+		if snap.Followers, err = readCount(snap.Path); err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+	// IDs sort in the order they were taken
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].ID < snaps[j].ID })
+	return snaps, nil
+}
+
+// readCount reads the follower count from a snapshot's header.
+func readCount(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	header, _ := bufio.NewReader(f).ReadString('\n')
+	fields := strings.Fields(header)
+	if len(fields) < 3 || fields[0] != "#" || fields[1] != "followers" {
+		return 0, fmt.Errorf("invalid snapshot %s", path)
+	}
+	n, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return 0, fmt.Errorf("invalid snapshot %s", path)
+	}
+	return n, nil
+}
+
+// Find returns the snapshot ref names: a snapshot ID, "latest", or a date
+// (2006-01-02) or time (RFC 3339) for the last snapshot taken by then.
+func Find(account, ref string) (Snapshot, error) {
+	snaps, err := List(account)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if len(snaps) == 0 {
+		return Snapshot{}, fmt.Errorf("%w: no snapshots have been taken", ErrNotFound)
+	}
+	if ref == "latest" {
+		return snaps[len(snaps)-1], nil
+	}
+	for _, s := range snaps {
+		if s.ID == ref {
+			return s, nil
+		}
+	}
+
+	var by time.Time
+	if t, err := time.Parse(time.RFC3339, ref); err == nil {
+		by = t
+	} else if t, err := time.ParseInLocation("2006-01-02", ref, time.Local); err == nil {
+		by = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	} else {
+		return Snapshot{}, fmt.Errorf("%w: %q (use a snapshot ID, latest, or a date)", ErrNotFound, ref)
+	}
+	for i := len(snaps) - 1; i >= 0; i-- {
+		if !snaps[i].TakenAt.After(by) {
+			return snaps[i], nil
+		}
+	}
+	return Snapshot{}, fmt.Errorf("%w: none taken by %s", ErrNotFound, ref)
+}
+
+// Compare merges two snapshots, calling gained for each ID only in to and
+// lost for each ID only in from, in sorted order.
+func Compare(from, to Snapshot, gained, lost func(id string) error) error {
+	a, err := openIDs(from.Path)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+	b, err := openIDs(to.Path)
+	if err != nil {
+		return err
+	}
+	defer b.close()
+
+	x, xok := a.next()
+	y, yok := b.next()
+	for xok || yok {
+		switch {
+		case !yok || (xok && x < y):
+			if err := lost(x); err != nil {
+				return err
+			}
+			x, xok = a.next()
+		case !xok || y < x:
+			if err := gained(y); err != nil {
+				return err
+			}
+			y, yok = b.next()
+		default:
+			x, xok = a.next()
+			y, yok = b.next()
+		}
+	}
+	if err := a.err(); err != nil {
+		return err
+	}
+	return b.err()
+}
+
+// idScanner reads the IDs of a snapshot file.
+type idScanner struct {
+	f *os.File
+	s *bufio.Scanner
+}
+
+func openIDs(path string) (*idScanner, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return &idScanner{f: f, s: bufio.NewScanner(f)}, nil
+}
+
+func (s *idScanner) next() (string, bool) {
+	for s.s.Scan() {
+		if line := strings.TrimSpace(s.s.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+func (s *idScanner) err() error {
+	if err := s.s.Err(); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *idScanner) close() { _ = s.f.Close() }
+
+// CountAccount returns how many snapshots are stored for account.
+func CountAccount(account string) (int, error) {
+	snaps, err := List(account)
+	return len(snaps), err
+}
+
+// RemoveAccount deletes every snapshot for account and returns how many
+// were removed.
+func RemoveAccount(account string) (int, error) {
+	n, err := CountAccount(account)
+	if err != nil {
+		return 0, err
+	}
+	dir, err := Dir(account)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to remove follower snapshots: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,106 @@
+package followersnapshot
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveListFind(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(48 * time.Hour)
+	if _, err := Save("prod", second, []string{"U3", "U1", "U3"}); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := Save("prod", first, []string{"U2", "U1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.ID != "20260301T090000Z" || snap.Followers != 2 {
+		t.Errorf("unexpected snapshot: %+v", snap)
+	}
+	data, _ := os.ReadFile(snap.Path)
+	if want := "# followers 2 taken 2026-03-01T09:00:00Z\nU1\nU2\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+	if _, err := Save("prod", first, []string{"U1"}); err == nil {
+		t.Error("expected an error saving over an existing snapshot")
+	}
+
+	snaps, err := List("prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 || snaps[0].ID != "20260301T090000Z" || snaps[1].Followers != 2 {
+		t.Fatalf("unexpected list: %+v", snaps)
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"latest", "20260303T090000Z"},
+		{"20260301T090000Z", "20260301T090000Z"},
+		{"2026-03-02T00:00:00Z", "20260301T090000Z"},
+		{"2026-03-04", "20260303T090000Z"},
+	}
+	for _, tt := range tests {
+		got, err := Find("prod", tt.ref)
+		if err != nil || got.ID != tt.want {
+			t.Errorf("Find(%q) = %v, %v; want %s", tt.ref, got.ID, err, tt.want)
+		}
+	}
+	for _, ref := range []string{"2026-02-01T00:00:00Z", "yesterday"} {
+		if _, err := Find("prod", ref); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Find(%q) error = %v, want ErrNotFound", ref, err)
+		}
+	}
+	if _, err := Find("staging", "latest"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an account without snapshots, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	from, err := Save("prod", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), []string{"U1", "U2", "U4", "U6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := Save("prod", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), []string{"U2", "U3", "U4", "U7", "U8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gained, lost []string
+	err = Compare(from, to,
+		func(id string) error { gained = append(gained, id); return nil },
+		func(id string) error { lost = append(lost, id); return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(gained, ","); got != "U3,U7,U8" {
+		t.Errorf("gained = %s", got)
+	}
+	if got := strings.Join(lost, ","); got != "U1,U6" {
+		t.Errorf("lost = %s", got)
+	}
+}
+
+func TestRemoveAccount(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if _, err := Save("prod", time.Now(), []string{"U1"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := RemoveAccount("prod"); err != nil || n != 1 {
+		t.Errorf("RemoveAccount = %d, %v; want 1", n, err)
+	}
+	if n, _ := CountAccount("prod"); n != 0 {
+		t.Errorf("expected no snapshots left, got %d", n)
+	}
+}
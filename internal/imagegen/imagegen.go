@@ -0,0 +1,121 @@
+// Package imagegen renders placeholder rich menu images so layouts can be
+// tested before final artwork is available.
+package imagegen
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Cell is a labeled rectangle in a placeholder image.
+type Cell struct {
+	X, Y, Width, Height int
+	// Label is drawn large in the center of the cell
+	Label string
+	// Detail is drawn smaller beneath the label (e.g. the action type)
+	Detail string
+}
+
+var (
+	background = color.RGBA{0xEE, 0xEE, 0xEE, 0xFF}
+	border     = color.RGBA{0x33, 0x33, 0x33, 0xFF}
+	ink        = color.RGBA{0x22, 0x22, 0x22, 0xFF}
+	// fills cycles through light colors so neighbouring cells are distinguishable
+	fills = []color.RGBA{
+		{0xB3, 0xE5, 0xFC, 0xFF},
+		{0xC8, 0xE6, 0xC9, 0xFF},
+		{0xFF, 0xE0, 0xB2, 0xFF},
+		{0xE1, 0xBE, 0xE7, 0xFF},
+		{0xFF, 0xF9, 0xC4, 0xFF},
+		{0xF8, 0xBB, 0xD0, 0xFF},
+	}
+)
+
+// Grid renders a width x height image with one filled, bordered, and
+// labeled rectangle per cell. Text uses a built-in bitmap font, so only
+// ASCII characters are shown; other characters are drawn as '?'.
+func Grid(width, height int, cells []Cell) *image.Paletted {
+	palette := color.Palette{background, border, ink}
+	for _, f := range fills {
+		palette = append(palette, f)
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	stroke := max(2, min(width, height)/200)
+	for i, cell := range cells {
+		r := image.Rect(cell.X, cell.Y, cell.X+cell.Width, cell.Y+cell.Height).Intersect(img.Bounds())
+		if r.Empty() {
+			continue
+		}
+		draw.Draw(img, r, &image.Uniform{C: border}, image.Point{}, draw.Src)
+		draw.Draw(img, r.Inset(stroke), &image.Uniform{C: fills[i%len(fills)]}, image.Point{}, draw.Src)
+
+		labelScale := fitScale(cell.Label, r.Dx()*8/10, r.Dy()*3/10)
+		detailScale := max(1, labelScale/2)
+		labelHeight := glyphHeight * labelScale
+		detailHeight := 0
+		if cell.Detail != "" {
+			detailHeight = glyphHeight*detailScale + labelHeight/2
+		}
+
+		top := r.Min.Y + (r.Dy()-labelHeight-detailHeight)/2
+		drawText(img, fitText(cell.Label, r.Dx()*9/10, labelScale), r, top, labelScale)
+		if cell.Detail != "" {
+			drawText(img, fitText(cell.Detail, r.Dx()*9/10, detailScale), r, top+labelHeight+labelHeight/2, detailScale)
+		}
+	}
+
+	return img
+}
+
+// textWidth returns the pixel width of s at the given scale, including one
+// column of spacing between characters.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+1) - 1) * scale
+}
+
+// fitScale returns the largest scale at which s fits in maxWidth x maxHeight.
+func fitScale(s string, maxWidth, maxHeight int) int {
+	scale := max(1, maxHeight/glyphHeight)
+	if w := textWidth(s, 1); w > 0 {
+		scale = min(scale, maxWidth/w)
+	}
+	return max(1, scale)
+}
+
+// fitText truncates s so it fits in maxWidth at the given scale.
+func fitText(s string, maxWidth, scale int) string {
+	runes := []rune(s)
+	if textWidth(s, scale) <= maxWidth {
+		return s
+	}
+	for len(runes) > 0 && textWidth(string(runes)+"..", scale) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + ".."
+}
+
+// drawText draws s horizontally centered within r with its top edge at y.
+func drawText(img draw.Image, s string, r image.Rectangle, y, scale int) {
+	x := r.Min.X + (r.Dx()-textWidth(s, scale))/2
+	ch := &image.Uniform{C: ink}
+	for _, c := range s {
+		g := glyph(c)
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if g[row]&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale).Intersect(r)
+				draw.Draw(img, px, ch, image.Point{}, draw.Src)
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
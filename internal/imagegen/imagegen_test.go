@@ -0,0 +1,48 @@
+package imagegen
+
+import (
+	"image"
+	"testing"
+)
+
+func TestGrid_FillsCells(t *testing.T) {
+	img := Grid(800, 270, []Cell{
+		{X: 0, Y: 0, Width: 400, Height: 270, Label: "Left", Detail: "message"},
+		{X: 400, Y: 0, Width: 400, Height: 270, Label: "Right"},
+	})
+
+	if img.Bounds() != image.Rect(0, 0, 800, 270) {
+		t.Fatalf("unexpected bounds: %v", img.Bounds())
+	}
+	// Cell corners are drawn as borders and interiors use distinct fills
+	if img.At(0, 0) != border {
+		t.Errorf("expected border at cell corner, got %v", img.At(0, 0))
+	}
+	if img.At(20, 20) != fills[0] || img.At(420, 20) != fills[1] {
+		t.Errorf("expected distinct cell fills, got %v and %v", img.At(20, 20), img.At(420, 20))
+	}
+
+	// The label is drawn somewhere in the middle of the first cell
+	inked := false
+	for y := 0; y < 270 && !inked; y++ {
+		for x := 0; x < 400; x++ {
+			if img.At(x, y) == ink {
+				inked = true
+				break
+			}
+		}
+	}
+	if !inked {
+		t.Error("expected label text to be drawn")
+	}
+}
+
+func TestFitText(t *testing.T) {
+	if got := fitText("Help", 100, 1); got != "Help" {
+		t.Errorf("fitText() = %q, want unchanged", got)
+	}
+	got := fitText("A very long label", textWidth("A very..", 2), 2)
+	if got != "A very.." {
+		t.Errorf("fitText() = %q, want %q", got, "A very..")
+	}
+}
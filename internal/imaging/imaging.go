@@ -0,0 +1,254 @@
+// Package imaging fits images to LINE's upload limits: it resizes them to
+// exact dimensions and re-encodes them until they are small enough,
+// reporting each transformation it applies.
+//
+// Only the standard library codecs are used. PNG images are recompressed
+// and then reduced to a 256 color palette; JPEG images, and PNG images
+// still too large after that, are encoded as JPEG at decreasing quality.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// Content types of the encoded images.
+const (
+	PNG  = "image/png"
+	JPEG = "image/jpeg"
+)
+
+// jpegQualities are tried in order until the image is small enough.
+var jpegQualities = []int{90, 80, 70, 60, 50, 40}
+
+// Options are the limits an image is fitted to.
+type Options struct {
+	// Width and Height are the exact dimensions of the result
+	Width, Height int
+	// MaxBytes is the largest encoded size allowed
+	MaxBytes int
+}
+
+// Result is a fitted image.
+type Result struct {
+	Data        []byte
+	ContentType string
+	// Steps describe the transformations applied, in order. An image that
+	// already fit is returned unchanged with no steps.
+	Steps []string
+}
+
+// Fit decodes a PNG or JPEG image and transforms it to meet opts.
+func Fit(data []byte, opts Options) (*Result, error) {
+	if opts.Width <= 0 || opts.Height <= 0 || opts.MaxBytes <= 0 {
+		return nil, errors.New("invalid fit options: width, height, and size limit must be positive")
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: could not decode PNG or JPEG: %w", err)
+	}
+	contentType := "image/" + format
+	if contentType != PNG && contentType != JPEG {
+		return nil, fmt.Errorf("unsupported image format %s: use PNG or JPEG", format)
+	}
+
+	b := img.Bounds()
+	if b.Dx() == opts.Width && b.Dy() == opts.Height && len(data) <= opts.MaxBytes {
+		return &Result{Data: data, ContentType: contentType}, nil
+	}
+
+	r := &Result{}
+	if b.Dx() != opts.Width || b.Dy() != opts.Height {
+		var cropped bool
+		img, cropped = Resize(img, opts.Width, opts.Height)
+		step := fmt.Sprintf("resized from %dx%d to %dx%d", b.Dx(), b.Dy(), opts.Width, opts.Height)
+		if cropped {
+			step += ", cropping the edges to keep the aspect ratio"
+		}
+		r.Steps = append(r.Steps, step)
+	}
+
+	if contentType == PNG {
+		encoded, err := encodePNG(img)
+		if err != nil {
+			return nil, err
+		}
+		if len(encoded) <= opts.MaxBytes {
+			r.Data, r.ContentType = encoded, PNG
+			r.Steps = append(r.Steps, fmt.Sprintf("re-encoded as PNG (%s)", formatBytes(len(encoded))))
+			return r, nil
+		}
+
+		reduced := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(reduced, reduced.Bounds(), img, img.Bounds().Min)
+		if encoded, err = encodePNG(reduced); err != nil {
+			return nil, err
+		}
+		if len(encoded) <= opts.MaxBytes {
+			r.Data, r.ContentType = encoded, PNG
+			r.Steps = append(r.Steps, fmt.Sprintf("reduced to a 256 color palette (%s)", formatBytes(len(encoded))))
+			return r, nil
+		}
+	}
+
+	if hasAlpha(img) {
+		img = flatten(img, color.White)
+		r.Steps = append(r.Steps, "filled transparent areas with white for JPEG")
+	}
+	for _, quality := range jpegQualities {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("failed to encode JPEG: %w", err)
+		}
+		if buf.Len() <= opts.MaxBytes {
+			r.Data, r.ContentType = buf.Bytes(), JPEG
+			r.Steps = append(r.Steps, fmt.Sprintf("re-encoded as JPEG at quality %d (%s)", quality, formatBytes(buf.Len())))
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("could not get the image under %s, even as JPEG at quality %d", formatBytes(opts.MaxBytes), jpegQualities[len(jpegQualities)-1])
+}
+
+// Resize scales img to width x height. When the aspect ratio differs, the
+// image is scaled to cover the target and the overflowing edges are
+// cropped evenly, which is reported as cropped.
+func Resize(img image.Image, width, height int) (dst *image.RGBA, cropped bool) {
+	src := img.Bounds()
+	sw, sh := src.Dx(), src.Dy()
+
+	// Crop the source to the target aspect ratio, centered
+	crop := src
+	if sw*height > sh*width {
+		w := sh * width / height
+		crop.Min.X += (sw - w) / 2
+		crop.Max.X = crop.Min.X + w
+	} else if sw*height < sh*width {
+		h := sw * height / width
+		crop.Min.Y += (sh - h) / 2
+		crop.Max.Y = crop.Min.Y + h
+	}
+	cropped = crop != src
+
+	rgba := image.NewRGBA(crop)
+	draw.Draw(rgba, crop, img, crop.Min, draw.Src)
+
+	dst = image.NewRGBA(image.Rect(0, 0, width, height))
+	if crop.Dx() >= width && crop.Dy() >= height {
+		boxScale(dst, rgba)
+	} else {
+		bilinearScale(dst, rgba)
+	}
+	return dst, cropped
+}
+
+// boxScale shrinks src into dst, averaging the source pixels each
+// destination pixel covers.
+func boxScale(dst, src *image.RGBA) {
+	sb := src.Bounds()
+	dw, dh := dst.Bounds().Dx(), dst.Bounds().Dy()
+	for y := 0; y < dh; y++ {
+		y0 := sb.Min.Y + y*sb.Dy()/dh
+		y1 := max(sb.Min.Y+(y+1)*sb.Dy()/dh, y0+1)
+		for x := 0; x < dw; x++ {
+			x0 := sb.Min.X + x*sb.Dx()/dw
+			x1 := max(sb.Min.X+(x+1)*sb.Dx()/dw, x0+1)
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					p := src.Pix[i : i+4 : i+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+					i += 4
+				}
+			}
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(b / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+}
+
+// bilinearScale enlarges src into dst, interpolating between the four
+// nearest source pixels.
+func bilinearScale(dst, src *image.RGBA) {
+	sb := src.Bounds()
+	dw, dh := dst.Bounds().Dx(), dst.Bounds().Dy()
+	fx := float64(sb.Dx()) / float64(dw)
+	fy := float64(sb.Dy()) / float64(dh)
+	for y := 0; y < dh; y++ {
+		sy := (float64(y)+0.5)*fy - 0.5
+		y0 := clamp(int(sy), 0, sb.Dy()-1)
+		y1 := clamp(y0+1, 0, sb.Dy()-1)
+		wy := clampf(sy - float64(y0))
+		for x := 0; x < dw; x++ {
+			sx := (float64(x)+0.5)*fx - 0.5
+			x0 := clamp(int(sx), 0, sb.Dx()-1)
+			x1 := clamp(x0+1, 0, sb.Dx()-1)
+			wx := clampf(sx - float64(x0))
+
+			p00 := src.PixOffset(sb.Min.X+x0, sb.Min.Y+y0)
+			p10 := src.PixOffset(sb.Min.X+x1, sb.Min.Y+y0)
+			p01 := src.PixOffset(sb.Min.X+x0, sb.Min.Y+y1)
+			p11 := src.PixOffset(sb.Min.X+x1, sb.Min.Y+y1)
+			o := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				top := float64(src.Pix[p00+c])*(1-wx) + float64(src.Pix[p10+c])*wx
+				bottom := float64(src.Pix[p01+c])*(1-wx) + float64(src.Pix[p11+c])*wx
+				dst.Pix[o+c] = uint8(top*(1-wy) + bottom*wy + 0.5)
+			}
+		}
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
+
+func clampf(v float64) float64 {
+	return min(max(v, 0), 1)
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// hasAlpha reports whether any pixel of img is not fully opaque.
+func hasAlpha(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return !o.Opaque()
+	}
+	return true
+}
+
+// flatten draws img over a background color, removing transparency.
+func flatten(img image.Image, bg color.Color) image.Image {
+	out := image.NewRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Over)
+	return out
+}
+
+func formatBytes(n int) string {
+	if n >= 1024*1024 {
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+	return fmt.Sprintf("%d KB", (n+1023)/1024)
+}
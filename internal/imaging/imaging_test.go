@@ -0,0 +1,143 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func encode(t *testing.T, img image.Image, format string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// noise returns an image that compresses badly.
+func noise(width, height int) *image.RGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.Intn(256))
+		if i%4 == 3 {
+			img.Pix[i] = 255
+		}
+	}
+	return img
+}
+
+func decode(t *testing.T, r *Result) image.Config {
+	t.Helper()
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(r.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if "image/"+format != r.ContentType {
+		t.Errorf("data is %s but ContentType is %s", format, r.ContentType)
+	}
+	return cfg
+}
+
+func TestFit_Unchanged(t *testing.T) {
+	data := encode(t, image.NewGray(image.Rect(0, 0, 800, 540)), "png")
+	r, err := Fit(data, Options{Width: 800, Height: 540, MaxBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r.Data, data) || r.ContentType != PNG || len(r.Steps) != 0 {
+		t.Errorf("expected the image unchanged, got %s %v", r.ContentType, r.Steps)
+	}
+}
+
+func TestFit_ResizesAndCrops(t *testing.T) {
+	data := encode(t, image.NewGray(image.Rect(0, 0, 1600, 1600)), "png")
+	r, err := Fit(data, Options{Width: 800, Height: 540, MaxBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg := decode(t, r); cfg.Width != 800 || cfg.Height != 540 {
+		t.Errorf("got %dx%d", cfg.Width, cfg.Height)
+	}
+	if len(r.Steps) != 2 || r.Steps[0] != "resized from 1600x1600 to 800x540, cropping the edges to keep the aspect ratio" ||
+		!strings.HasPrefix(r.Steps[1], "re-encoded as PNG") {
+		t.Errorf("unexpected steps %q", r.Steps)
+	}
+}
+
+func TestFit_ReducesPNGPalette(t *testing.T) {
+	data := encode(t, noise(1000, 690), "png")
+	r, err := Fit(data, Options{Width: 1000, Height: 690, MaxBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ContentType != PNG || len(r.Data) > 1<<20 {
+		t.Errorf("got %s of %d bytes", r.ContentType, len(r.Data))
+	}
+	if len(r.Steps) != 1 || !strings.HasPrefix(r.Steps[0], "reduced to a 256 color palette") {
+		t.Errorf("unexpected steps %q", r.Steps)
+	}
+}
+
+func TestFit_StepsJPEGQuality(t *testing.T) {
+	data := encode(t, noise(800, 540), "jpeg")
+	r, err := Fit(data, Options{Width: 800, Height: 540, MaxBytes: 150 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ContentType != JPEG || len(r.Data) > 150*1024 {
+		t.Errorf("got %s of %d bytes", r.ContentType, len(r.Data))
+	}
+	if len(r.Steps) != 1 || !strings.HasPrefix(r.Steps[0], "re-encoded as JPEG at quality") {
+		t.Errorf("unexpected steps %q", r.Steps)
+	}
+
+	if _, err := Fit(data, Options{Width: 800, Height: 540, MaxBytes: 1024}); err == nil || !strings.Contains(err.Error(), "could not get the image under 1 KB") {
+		t.Errorf("expected an error when nothing fits, got %v", err)
+	}
+}
+
+func TestFit_InvalidImage(t *testing.T) {
+	if _, err := Fit([]byte("not an image"), Options{Width: 800, Height: 540, MaxBytes: 1 << 20}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			src.Set(x, y, color.RGBA{R: uint8(x * 60), A: 255})
+		}
+	}
+
+	down, cropped := Resize(src, 2, 1)
+	if cropped {
+		t.Error("expected no crop for the same aspect ratio")
+	}
+	if got := down.RGBAAt(0, 0).R; got != 30 {
+		t.Errorf("box average = %d, want 30", got)
+	}
+
+	up, cropped := Resize(src, 8, 4)
+	if cropped || up.Bounds().Dx() != 8 || up.RGBAAt(0, 0).A != 255 {
+		t.Errorf("unexpected enlargement %v %v", up.Bounds(), cropped)
+	}
+
+	square, cropped := Resize(src, 2, 2)
+	if !cropped || square.RGBAAt(0, 0).R != 60 {
+		t.Errorf("expected the center columns, got R=%d cropped=%v", square.RGBAAt(0, 0).R, cropped)
+	}
+}
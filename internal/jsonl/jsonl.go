@@ -0,0 +1,195 @@
+// Package jsonl stores local records as an append-only file of JSON
+// objects, one per line, shared by all accounts. The audit log and the
+// send ledger are kept this way.
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// defaultMaxLine is the longest record read when a store sets no MaxLine.
+const defaultMaxLine = 1024 * 1024
+
+// Store is a file of records of type E in the data directory. Records are
+// appended; removing records rewrites the file.
+type Store[E any] struct {
+	// Name describes the store in errors, such as "audit log"
+	Name string
+	// File is the file name in the data directory
+	File string
+	// MaxLine is the longest record read, in bytes; zero means 1 MiB
+	MaxLine int
+	// Account returns the account a record was made for
+	Account func(E) string
+
+	// mu serializes writes within a process, since commands such as
+	// send-batch record concurrently
+	mu sync.Mutex
+}
+
+// Path returns the location of the store's file.
+func (s *Store[E]) Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, s.File), nil
+}
+
+// Append adds a record, creating the file if needed.
+func (s *Store[E]) Append(record E) error {
+	path, err := s.Path()
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s path: %w", s.Name, err)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", s.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", s.Name, err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", s.Name, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// ForAccount returns the records made for account, oldest first.
+func (s *Store[E]) ForAccount(account string) ([]E, error) {
+	records, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	var matched []E
+	for _, r := range records {
+		if s.Account(r) == account {
+			matched = append(matched, r)
+		}
+	}
+	return matched, nil
+}
+
+// CountAccount returns the number of records made for account.
+func (s *Store[E]) CountAccount(account string) (int, error) {
+	records, err := s.ForAccount(account)
+	return len(records), err
+}
+
+// RemoveAccount rewrites the file without the records made for account
+// and returns how many were removed.
+func (s *Store[E]) RemoveAccount(account string) (int, error) {
+	return s.Remove(func(r E) bool { return s.Account(r) == account })
+}
+
+// Remove rewrites the file without the records drop matches and returns
+// how many were removed.
+func (s *Store[E]) Remove(drop func(E) bool) (int, error) {
+	removed := 0
+	err := s.Rewrite(func(records []E) ([]E, bool) {
+		kept := records[:0]
+		for _, r := range records {
+			if drop(r) {
+				removed++
+				continue
+			}
+			kept = append(kept, r)
+		}
+		return kept, removed > 0
+	})
+	if err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// Rewrite replaces the records with those update returns. When update
+// reports no change, the file is left as it is.
+func (s *Store[E]) Rewrite(update func([]E) ([]E, bool)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	records, changed := update(records)
+	if !changed {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s entry: %w", s.Name, err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+	path, err := s.Path()
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s path: %w", s.Name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", s.Name, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to rewrite %s: %w", s.Name, err)
+	}
+	return nil
+}
+
+// readAll returns every record in the file. A missing file is empty.
+func (s *Store[E]) readAll() ([]E, error) {
+	path, err := s.Path()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s path: %w", s.Name, err)
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", s.Name, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	maxLine := s.MaxLine
+	if maxLine == 0 {
+		maxLine = defaultMaxLine
+	}
+	var records []E
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var r E
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("invalid %s entry: %w", s.Name, err)
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.Name, err)
+	}
+	return records, nil
+}
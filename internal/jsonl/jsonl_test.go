@@ -0,0 +1,69 @@
+package jsonl
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	Account string `json:"account"`
+	N       int    `json:"n"`
+}
+
+func newStore() *Store[record] {
+	return &Store[record]{Name: "test log", File: "test.jsonl", Account: func(r record) string { return r.Account }}
+}
+
+func TestStore(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	s := newStore()
+
+	if n, err := s.CountAccount("a"); err != nil || n != 0 {
+		t.Fatalf("CountAccount() on a missing file = %d, %v", n, err)
+	}
+	for i, account := range []string{"a", "b", "a", "a"} {
+		if err := s.Append(record{Account: account, N: i}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	records, err := s.ForAccount("a")
+	if err != nil || len(records) != 3 || records[0].N != 0 || records[2].N != 3 {
+		t.Fatalf("ForAccount() = %+v, %v", records, err)
+	}
+	if n, err := s.Remove(func(r record) bool { return r.N == 2 }); err != nil || n != 1 {
+		t.Fatalf("Remove() = %d, %v", n, err)
+	}
+	if n, err := s.RemoveAccount("a"); err != nil || n != 2 {
+		t.Fatalf("RemoveAccount() = %d, %v", n, err)
+	}
+	if n, err := s.RemoveAccount("a"); err != nil || n != 0 {
+		t.Errorf("RemoveAccount() of a removed account = %d, %v", n, err)
+	}
+	if n, err := s.CountAccount("b"); err != nil || n != 1 {
+		t.Errorf("CountAccount() of the other account = %d, %v", n, err)
+	}
+}
+
+func TestStore_InvalidLine(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	s := newStore()
+	if err := s.Append(record{Account: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := s.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("\nnot json\n")
+	_ = f.Close()
+
+	if _, err := s.ForAccount("a"); err == nil || !strings.Contains(err.Error(), "invalid test log entry") {
+		t.Errorf("ForAccount() error = %v", err)
+	}
+}
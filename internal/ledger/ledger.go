@@ -0,0 +1,116 @@
+// Package ledger keeps a local record of every message the CLI sends, so
+// past sends can be looked up without LINE's console. Broadcasts and
+// narrowcasts sent with --track are marked, so their delivery results can
+// be fetched later.
+//
+// The ledger is a local file with one JSON object per line, shared by all
+// accounts.
+package ledger
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/jsonl"
+)
+
+// Entry is one sent request.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Account string    `json:"account,omitempty"`
+	// Type is push, multicast, broadcast, narrowcast, reply, or pnp
+	Type      string `json:"type"`
+	RequestID string `json:"requestId,omitempty"`
+	// Recipients is the number of users the request named; zero for
+	// broadcasts and narrowcasts
+	Recipients  int    `json:"recipients"`
+	PayloadHash string `json:"payloadHash,omitempty"`
+	// Summary is the start of the first message, to tell sends apart
+	Summary  string          `json:"summary,omitempty"`
+	Messages json.RawMessage `json:"messages,omitempty"`
+	// Tracked is set for sends made with --track or --track-label
+	Tracked bool `json:"tracked,omitempty"`
+	// Label is a name given with --track-label, such as a campaign name
+	Label string `json:"label,omitempty"`
+}
+
+var store = jsonl.Store[Entry]{
+	Name: "ledger",
+	File: "ledger.jsonl",
+	// Entries hold whole messages, so allow long lines
+	MaxLine: 16 * 1024 * 1024,
+	Account: func(e Entry) string { return e.Account },
+}
+
+// Path returns the location of the ledger file.
+func Path() (string, error) {
+	return store.Path()
+}
+
+// Append adds an entry to the ledger, creating the file if needed. A zero
+// Time is replaced with the current UTC time.
+func Append(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	return store.Append(entry)
+}
+
+// Track marks the recorded send of entry.Account with entry.RequestID as
+// tracked under entry.Label. A send that was not recorded, such as one
+// made by a client without a ledger, is added from entry.
+func Track(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	return store.Rewrite(func(entries []Entry) ([]Entry, bool) {
+		for i := range entries {
+			e := &entries[i]
+			if e.Account == entry.Account && e.RequestID == entry.RequestID {
+				e.Tracked, e.Label = true, entry.Label
+				return entries, true
+			}
+		}
+		entry.Tracked = true
+		return append(entries, entry), true
+	})
+}
+
+// ForAccount returns the entries recorded for account, oldest first.
+func ForAccount(account string) ([]Entry, error) {
+	return store.ForAccount(account)
+}
+
+// Find returns the entry of account with the request ID, or nil if there
+// is none.
+func Find(account, requestID string) (*Entry, error) {
+	entries, err := ForAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].RequestID == requestID {
+			return &entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CountAccount returns the number of entries recorded for account.
+func CountAccount(account string) (int, error) {
+	return store.CountAccount(account)
+}
+
+// RemoveAccount rewrites the ledger without the entries recorded for
+// account and returns how many were removed.
+func RemoveAccount(account string) (int, error) {
+	return store.RemoveAccount(account)
+}
+
+// RemoveBefore rewrites the ledger without the entries of account sent
+// before the given time and returns how many were removed.
+func RemoveBefore(account string, before time.Time) (int, error) {
+	return store.Remove(func(e Entry) bool {
+		return e.Account == account && e.Time.Before(before)
+	})
+}
@@ -0,0 +1,78 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendFindRemove(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if entries, err := ForAccount("shop"); err != nil || len(entries) != 0 {
+		t.Fatalf("ForAccount() on a missing ledger = %v, %v", entries, err)
+	}
+
+	day := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{Time: day, Account: "shop", Type: "push", RequestID: "req-1", Recipients: 1},
+		{Time: day, Account: "other", Type: "broadcast", RequestID: "req-2"},
+		{Time: day.AddDate(0, 0, 2), Account: "shop", Type: "multicast", RequestID: "req-3", Recipients: 40},
+		{Account: "shop", Type: "reply"},
+	} {
+		if err := Append(e); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	entries, err := ForAccount("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].RequestID != "req-1" || entries[2].Time.IsZero() {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+	if e, err := Find("shop", "req-3"); err != nil || e == nil || e.Recipients != 40 {
+		t.Errorf("Find() = %+v, %v", e, err)
+	}
+	if e, err := Find("shop", "req-2"); err != nil || e != nil {
+		t.Errorf("Find() of another account's send = %+v, %v", e, err)
+	}
+
+	if n, err := RemoveBefore("shop", day.AddDate(0, 0, 1)); err != nil || n != 1 {
+		t.Fatalf("RemoveBefore() = %d, %v", n, err)
+	}
+	if n, err := RemoveAccount("shop"); err != nil || n != 2 {
+		t.Fatalf("RemoveAccount() = %d, %v", n, err)
+	}
+	if n, err := CountAccount("other"); err != nil || n != 1 {
+		t.Errorf("CountAccount() of the other account = %d, %v", n, err)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if err := Append(Entry{Account: "shop", Type: "broadcast", RequestID: "req-1", Summary: "Sale (+1 more)"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Track(Entry{Account: "shop", Type: "broadcast", RequestID: "req-1", Label: "spring", Summary: "Sale"}); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if err := Track(Entry{Account: "shop", Type: "narrowcast", RequestID: "req-2"}); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+
+	entries, err := ForAccount("shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the recorded send to be marked, not added again: %+v", entries)
+	}
+	if e := entries[0]; !e.Tracked || e.Label != "spring" || e.Summary != "Sale (+1 more)" {
+		t.Errorf("unexpected tracked send: %+v", e)
+	}
+	if e := entries[1]; !e.Tracked || e.RequestID != "req-2" || e.Time.IsZero() {
+		t.Errorf("unexpected added send: %+v", e)
+	}
+}
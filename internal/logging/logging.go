@@ -0,0 +1,144 @@
+// Package logging builds the slog loggers the CLI and API client log
+// through: a terse format for people reading a terminal, and slog's text and
+// JSON formats for log files and collectors such as cron and journald.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel parses debug, info, warn (or warning), or error.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn, or error", s)
+}
+
+// New returns a logger writing records at level and above to w in slog's
+// text or JSON format, with timestamps.
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch format {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q: must be text or json", format)
+}
+
+// NewTerminal returns a logger for a person reading w: no timestamps, and
+// the level as a prefix the way the CLI has always printed it
+// ("[DEBUG] ...", "Warning: ...", "Error: ..."), followed by the attributes.
+func NewTerminal(w io.Writer, level slog.Level) *slog.Logger {
+	h := &terminalHandler{w: w, level: level, mu: &sync.Mutex{}, buf: &bytes.Buffer{}}
+	h.attrs = slog.NewTextHandler(h.buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// The prefix and message are written by Handle
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return slog.New(h)
+}
+
+// Discard returns a logger that drops every record.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// OpenFile opens path for appending log records, creating it and its
+// directory if needed. Log records can hold user IDs, so the file is
+// readable only by its owner.
+func OpenFile(path string) (*os.File, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	return f, nil
+}
+
+// terminalHandler formats records as "<prefix><message> key=value ...". The
+// attributes are formatted by a text handler into a buffer shared by every
+// handler derived from the same logger.
+type terminalHandler struct {
+	w     io.Writer
+	level slog.Level
+	attrs slog.Handler
+
+	mu  *sync.Mutex
+	buf *bytes.Buffer
+}
+
+func (h *terminalHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *terminalHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.buf.Reset()
+	if err := h.attrs.Handle(ctx, r); err != nil {
+		return err
+	}
+	line := levelPrefix(r.Level) + r.Message
+	if attrs := strings.TrimSpace(h.buf.String()); attrs != "" {
+		line += " " + attrs
+	}
+	_, err := io.WriteString(h.w, line+"\n")
+	return err
+}
+
+func (h *terminalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = h.attrs.WithAttrs(attrs)
+	return &c
+}
+
+func (h *terminalHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.attrs = h.attrs.WithGroup(name)
+	return &c
+}
+
+func levelPrefix(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "Error: "
+	case level >= slog.LevelWarn:
+		return "Warning: "
+	case level >= slog.LevelInfo:
+		return ""
+	}
+	return "[DEBUG] "
+}
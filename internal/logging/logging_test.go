@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"warning", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestNewTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewTerminal(&buf, slog.LevelDebug).With("component", "api")
+
+	logger.Debug("http request", "method", "GET", "url", "https://api.line.me/v2/bot/info")
+	logger.Info("sent")
+	logger.Warn("token expires soon")
+	logger.Error("failed", "status", 500)
+
+	want := `[DEBUG] http request component=api method=GET url=https://api.line.me/v2/bot/info
+sent component=api
+Warning: token expires soon component=api
+Error: failed component=api status=500
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestNewTerminal_FiltersByLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewTerminal(&buf, slog.LevelWarn)
+	logger.Debug("hidden")
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if buf.String() != "Warning: shown\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, slog.LevelInfo, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("hidden")
+	logger.Info("retrying request", "status", 429)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected one JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["level"] != "INFO" || record["msg"] != "retrying request" || record["status"] != float64(429) || record["time"] == nil {
+		t.Errorf("unexpected record: %v", record)
+	}
+
+	if _, err := New(&buf, slog.LevelInfo, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestOpenFile_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "line.log")
+	for _, msg := range []string{"first", "second"} {
+		f, err := OpenFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger, _ := New(f, slog.LevelInfo, FormatText)
+		logger.Info(msg)
+		_ = f.Close()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "msg=second") {
+		t.Errorf("unexpected log file:\n%s", data)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("log file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,412 @@
+package messagespec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits checked by Lint in addition to those of template messages.
+const (
+	MaxMessages            = 5
+	MaxTextMessage         = 5000
+	MaxFlexAltText         = 1500
+	MaxImagemapActions     = 50
+	MaxImagemapText        = 400
+	MaxFlexCarouselBubbles = 12
+	MaxImageCarouselColumn = 10
+	// MaxFlexDepth is how deeply flex boxes may nest before Lint warns.
+	// LINE limits the size of a flex message rather than its depth, but
+	// layouts this deep are usually a generator bug and render poorly.
+	MaxFlexDepth = 10
+)
+
+// Levels of lint findings.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// Rule is a check made by Lint.
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules are the checks Lint makes, in the order they are documented.
+var Rules = []Rule{
+	{"message-count", "A request has 1 to 5 messages."},
+	{"message-type", "Each message is an object with a supported type and its required fields."},
+	{"text-length", "Texts, labels, and postback data stay within their character limits."},
+	{"alt-text", "Template, flex, and imagemap messages have an altText within its limit."},
+	{"url-scheme", "Image URLs use https, and URI actions use http, https, line, or tel."},
+	{"action-count", "Templates, carousels, and imagemaps have the number of actions and columns LINE accepts."},
+	{"flex-depth", "Flex boxes are not nested too deeply."},
+	{"quick-reply", "Quick replies have 1 to 13 action items of supported types."},
+}
+
+// Finding is a problem Lint found. Path locates it like the property paths
+// of the API's own errors, e.g. messages[0].template.actions[1].label.
+type Finding struct {
+	Rule    string `json:"rule"`
+	Level   string `json:"level"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Lint checks messages against the Messaging API's limits without calling
+// the API, reporting every problem rather than stopping at the first.
+func Lint(messages []json.RawMessage) []Finding {
+	l := &linter{}
+	if len(messages) < 1 || len(messages) > MaxMessages {
+		l.errorf("message-count", "messages", "a request has 1 to %d messages, got %d", MaxMessages, len(messages))
+	}
+	for i, raw := range messages {
+		path := fmt.Sprintf("messages[%d]", i)
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			l.errorf("message-type", path, "invalid JSON: %v", err)
+			continue
+		}
+		msg, ok := v.(map[string]any)
+		if !ok {
+			l.errorf("message-type", path, "expected a message object")
+			continue
+		}
+		l.message(path, msg)
+	}
+	return l.findings
+}
+
+type linter struct {
+	findings []Finding
+}
+
+func (l *linter) add(rule, level, path, format string, args ...any) {
+	l.findings = append(l.findings, Finding{Rule: rule, Level: level, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) errorf(rule, path, format string, args ...any) {
+	l.add(rule, LevelError, path, format, args...)
+}
+
+func (l *linter) message(path string, msg map[string]any) {
+	switch typ := str(msg["type"]); typ {
+	case "text", "textV2":
+		l.requiredLen(path+".text", msg["text"], MaxTextMessage)
+	case "image", "video":
+		l.imageURL(path+".originalContentUrl", msg["originalContentUrl"], true)
+		l.imageURL(path+".previewImageUrl", msg["previewImageUrl"], true)
+	case "audio":
+		l.imageURL(path+".originalContentUrl", msg["originalContentUrl"], true)
+	case "sticker", "location":
+	case "imagemap":
+		l.altText(path, msg["altText"], MaxFlexAltText)
+		l.imageURL(path+".baseUrl", msg["baseUrl"], true)
+		actions := list(msg["actions"])
+		if len(actions) < 1 || len(actions) > MaxImagemapActions {
+			l.errorf("action-count", path+".actions", "imagemaps need 1 to %d actions, got %d", MaxImagemapActions, len(actions))
+		}
+		for i, a := range actions {
+			ap := fmt.Sprintf("%s.actions[%d]", path, i)
+			action, _ := a.(map[string]any)
+			switch str(action["type"]) {
+			case "uri":
+				l.uri(ap+".linkUri", action["linkUri"])
+			case "message":
+				l.requiredLen(ap+".text", action["text"], MaxImagemapText)
+			}
+		}
+	case "template":
+		l.altText(path, msg["altText"], MaxAltText)
+		template, ok := msg["template"].(map[string]any)
+		if !ok {
+			l.errorf("message-type", path+".template", "template is required")
+			break
+		}
+		l.template(path+".template", template)
+	case "flex":
+		l.altText(path, msg["altText"], MaxFlexAltText)
+		contents, ok := msg["contents"].(map[string]any)
+		if !ok {
+			l.errorf("message-type", path+".contents", "contents is required")
+			break
+		}
+		l.flexContainer(path+".contents", contents)
+	case "":
+		l.errorf("message-type", path+".type", "type is required")
+	default:
+		l.errorf("message-type", path+".type", "unsupported message type %q", typ)
+	}
+
+	if qr, ok := msg["quickReply"]; ok {
+		l.quickReply(path+".quickReply", qr)
+	}
+}
+
+func (l *linter) template(path string, t map[string]any) {
+	hasHeading := str(t["title"]) != "" || str(t["thumbnailImageUrl"]) != ""
+	switch typ := str(t["type"]); typ {
+	case TypeConfirm:
+		l.requiredLen(path+".text", t["text"], MaxConfirmText)
+		l.actions(path+".actions", t["actions"], ConfirmActionsCount, ConfirmActionsCount, "confirm templates")
+	case TypeButtons:
+		maxText := MaxButtonsText
+		if hasHeading {
+			maxText = MaxTextWithTitle
+		}
+		l.heading(path, t)
+		l.requiredLen(path+".text", t["text"], maxText)
+		l.actions(path+".actions", t["actions"], 1, MaxButtonsActions, "buttons templates")
+	case TypeCarousel:
+		columns := list(t["columns"])
+		if len(columns) < 1 || len(columns) > MaxCarouselColumns {
+			l.errorf("action-count", path+".columns", "carousel templates need 1 to %d columns, got %d", MaxCarouselColumns, len(columns))
+		}
+		for i, c := range columns {
+			cp := fmt.Sprintf("%s.columns[%d]", path, i)
+			column, _ := c.(map[string]any)
+			maxText := MaxCarouselText
+			if str(column["title"]) != "" || str(column["thumbnailImageUrl"]) != "" {
+				maxText = MaxTextWithTitle
+			}
+			l.heading(cp, column)
+			l.requiredLen(cp+".text", column["text"], maxText)
+			l.actions(cp+".actions", column["actions"], 1, MaxCarouselActions, "carousel columns")
+		}
+	case "image_carousel":
+		columns := list(t["columns"])
+		if len(columns) < 1 || len(columns) > MaxImageCarouselColumn {
+			l.errorf("action-count", path+".columns", "image carousels need 1 to %d columns, got %d", MaxImageCarouselColumn, len(columns))
+		}
+		for i, c := range columns {
+			cp := fmt.Sprintf("%s.columns[%d]", path, i)
+			column, _ := c.(map[string]any)
+			l.imageURL(cp+".imageUrl", column["imageUrl"], true)
+			l.action(cp+".action", column["action"], false)
+		}
+	case "":
+		l.errorf("message-type", path+".type", "template type is required")
+	default:
+		l.errorf("message-type", path+".type", "unsupported template type %q", typ)
+	}
+}
+
+func (l *linter) heading(path string, t map[string]any) {
+	l.optionalLen(path+".title", t["title"], MaxTitle)
+	l.imageURL(path+".thumbnailImageUrl", t["thumbnailImageUrl"], false)
+}
+
+func (l *linter) actions(path string, v any, minCount, maxCount int, what string) {
+	actions := list(v)
+	if len(actions) < minCount || len(actions) > maxCount {
+		if minCount == maxCount {
+			l.errorf("action-count", path, "%s need exactly %d actions, got %d", what, minCount, len(actions))
+		} else {
+			l.errorf("action-count", path, "%s need %d to %d actions, got %d", what, minCount, maxCount, len(actions))
+		}
+	}
+	for i, a := range actions {
+		l.action(fmt.Sprintf("%s[%d]", path, i), a, true)
+	}
+}
+
+// action checks an action object; template actions need a label, flex
+// and quick reply actions are checked by their callers.
+func (l *linter) action(path string, v any, needLabel bool) {
+	a, ok := v.(map[string]any)
+	if !ok {
+		l.errorf("message-type", path, "expected an action object")
+		return
+	}
+	if needLabel {
+		l.requiredLen(path+".label", a["label"], MaxLabel)
+	} else {
+		l.optionalLen(path+".label", a["label"], MaxLabel)
+	}
+	switch typ := str(a["type"]); typ {
+	case "message":
+		l.requiredLen(path+".text", a["text"], MaxActionText)
+	case "uri":
+		l.uri(path+".uri", a["uri"])
+		if alt, ok := a["altUri"].(map[string]any); ok {
+			l.uri(path+".altUri.desktop", alt["desktop"])
+		}
+	case "postback":
+		l.requiredLen(path+".data", a["data"], MaxPostbackData)
+		l.optionalLen(path+".displayText", a["displayText"], MaxActionText)
+		l.optionalLen(path+".text", a["text"], MaxActionText)
+	case "datetimepicker":
+		l.requiredLen(path+".data", a["data"], MaxPostbackData)
+	case "clipboard":
+		l.requiredLen(path+".clipboardText", a["clipboardText"], MaxClipboardText)
+	case "camera", "cameraRoll", "location", "richmenuswitch":
+	case "":
+		l.errorf("message-type", path+".type", "action type is required")
+	default:
+		l.errorf("message-type", path+".type", "unsupported action type %q", typ)
+	}
+}
+
+func (l *linter) flexContainer(path string, c map[string]any) {
+	switch typ := str(c["type"]); typ {
+	case "bubble":
+		for _, block := range []string{"header", "hero", "body", "footer"} {
+			if component, ok := c[block].(map[string]any); ok {
+				l.flexComponent(path+"."+block, component, 0)
+			}
+		}
+		if a, ok := c["action"]; ok {
+			l.action(path+".action", a, false)
+		}
+	case "carousel":
+		bubbles := list(c["contents"])
+		if len(bubbles) < 1 || len(bubbles) > MaxFlexCarouselBubbles {
+			l.errorf("action-count", path+".contents", "flex carousels need 1 to %d bubbles, got %d", MaxFlexCarouselBubbles, len(bubbles))
+		}
+		for i, b := range bubbles {
+			bubble, _ := b.(map[string]any)
+			l.flexContainer(fmt.Sprintf("%s.contents[%d]", path, i), bubble)
+		}
+	case "":
+		l.errorf("message-type", path+".type", "flex container type is required")
+	default:
+		l.errorf("message-type", path+".type", "unsupported flex container type %q (use bubble or carousel)", typ)
+	}
+}
+
+// flexComponent checks a component and, for boxes, its contents; depth
+// is the number of boxes it is nested in.
+func (l *linter) flexComponent(path string, c map[string]any, depth int) {
+	if a, ok := c["action"]; ok {
+		l.action(path+".action", a, false)
+	}
+	switch str(c["type"]) {
+	case "box":
+		if depth+1 > MaxFlexDepth {
+			l.add("flex-depth", LevelWarning, path, "boxes are nested %d levels deep, more than %d", depth+1, MaxFlexDepth)
+			return
+		}
+		for i, child := range list(c["contents"]) {
+			if component, ok := child.(map[string]any); ok {
+				l.flexComponent(fmt.Sprintf("%s.contents[%d]", path, i), component, depth+1)
+			}
+		}
+	case "image", "icon":
+		l.imageURL(path+".url", c["url"], true)
+	case "video":
+		l.imageURL(path+".url", c["url"], true)
+		l.imageURL(path+".previewUrl", c["previewUrl"], true)
+	case "button":
+		l.action(path+".action", c["action"], true)
+	}
+}
+
+func (l *linter) quickReply(path string, v any) {
+	qr, _ := v.(map[string]any)
+	items := list(qr["items"])
+	if len(items) < 1 || len(items) > MaxQuickReplyItems {
+		l.errorf("quick-reply", path+".items", "quick replies need 1 to %d items, got %d", MaxQuickReplyItems, len(items))
+	}
+	for i, it := range items {
+		ip := fmt.Sprintf("%s.items[%d]", path, i)
+		item, _ := it.(map[string]any)
+		if str(item["type"]) != "action" {
+			l.errorf("quick-reply", ip+".type", "quick reply items must have type \"action\", got %q", str(item["type"]))
+		}
+		l.imageURL(ip+".imageUrl", item["imageUrl"], false)
+		action, _ := item["action"].(map[string]any)
+		if typ := str(action["type"]); typ != "" && !quickReplyActions[typ] {
+			l.errorf("quick-reply", ip+".action.type", "action type %q is not supported in quick replies", typ)
+			continue
+		}
+		l.action(ip+".action", item["action"], true)
+	}
+}
+
+func (l *linter) altText(path string, v any, limit int) {
+	s := str(v)
+	if s == "" {
+		l.errorf("alt-text", path+".altText", "altText is required")
+		return
+	}
+	if n := utf8.RuneCountInString(s); n > limit {
+		l.errorf("alt-text", path+".altText", "altText is %d characters, at most %d are allowed", n, limit)
+	}
+}
+
+func (l *linter) requiredLen(path string, v any, limit int) {
+	if str(v) == "" {
+		l.errorf("message-type", path, "%s is required", lastField(path))
+		return
+	}
+	l.optionalLen(path, v, limit)
+}
+
+func (l *linter) optionalLen(path string, v any, limit int) {
+	if n := utf8.RuneCountInString(str(v)); n > limit {
+		l.errorf("text-length", path, "%s is %d characters, at most %d are allowed", lastField(path), n, limit)
+	}
+}
+
+// imageURL checks that a media URL uses https.
+func (l *linter) imageURL(path string, v any, required bool) {
+	s := str(v)
+	if s == "" {
+		if required {
+			l.errorf("message-type", path, "%s is required", lastField(path))
+		}
+		return
+	}
+	if len(s) > MaxImageURL {
+		l.errorf("text-length", path, "%s is %d characters, at most %d are allowed", lastField(path), len(s), MaxImageURL)
+	}
+	if u, err := url.Parse(s); err != nil || u.Scheme != "https" {
+		l.errorf("url-scheme", path, "%s must use https, got %q", lastField(path), s)
+	}
+}
+
+// uri checks the target of a URI action.
+func (l *linter) uri(path string, v any) {
+	s := str(v)
+	if s == "" {
+		l.errorf("message-type", path, "%s is required", lastField(path))
+		return
+	}
+	if len(s) > MaxURI {
+		l.errorf("text-length", path, "%s is %d characters, at most %d are allowed", lastField(path), len(s), MaxURI)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		l.errorf("url-scheme", path, "%s is not a valid URL: %q", lastField(path), s)
+		return
+	}
+	switch u.Scheme {
+	case "http", "https", "line", "tel":
+	default:
+		l.errorf("url-scheme", path, "%s must use http, https, line, or tel, got %q", lastField(path), s)
+	}
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func list(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+// lastField returns the last property name of path.
+func lastField(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
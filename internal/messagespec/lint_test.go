@@ -0,0 +1,89 @@
+package messagespec
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func lintOne(t *testing.T, message string) []Finding {
+	t.Helper()
+	return Lint([]json.RawMessage{json.RawMessage(message)})
+}
+
+func TestLint(t *testing.T) {
+	deep := `{"type":"box","layout":"vertical","contents":[]}`
+	for i := 0; i < MaxFlexDepth; i++ {
+		deep = `{"type":"box","layout":"vertical","contents":[` + deep + `]}`
+	}
+
+	tests := []struct {
+		name     string
+		message  string
+		wantRule string
+		wantPath string
+	}{
+		{"text", `{"type":"text","text":"Hello"}`, "", ""},
+		{"long text", `{"type":"text","text":"` + strings.Repeat("a", MaxTextMessage+1) + `"}`, "text-length", "messages[0].text"},
+		{"no type", `{"text":"Hello"}`, "message-type", "messages[0].type"},
+		{"http image", `{"type":"image","originalContentUrl":"http://example.com/a.jpg","previewImageUrl":"https://example.com/p.jpg"}`, "url-scheme", "messages[0].originalContentUrl"},
+		{"template without altText", `{"type":"template","template":{"type":"confirm","text":"Sure?","actions":[{"type":"message","label":"Yes","text":"yes"},{"type":"message","label":"No","text":"no"}]}}`, "alt-text", "messages[0].altText"},
+		{"buttons five actions", `{"type":"template","altText":"Pick","template":{"type":"buttons","text":"Pick","actions":[` +
+			strings.TrimSuffix(strings.Repeat(`{"type":"message","label":"A","text":"a"},`, 5), ",") + `]}}`, "action-count", "messages[0].template.actions"},
+		{"ftp uri action", `{"type":"template","altText":"Go","template":{"type":"buttons","text":"Go","actions":[{"type":"uri","label":"Go","uri":"ftp://example.com"}]}}`, "url-scheme", "messages[0].template.actions[0].uri"},
+		{"long label", `{"type":"template","altText":"Go","template":{"type":"buttons","text":"Go","actions":[{"type":"message","label":"` + strings.Repeat("l", 21) + `","text":"x"}]}}`, "text-length", "messages[0].template.actions[0].label"},
+		{"flex", `{"type":"flex","altText":"Card","contents":{"type":"bubble","body":{"type":"box","layout":"vertical","contents":[{"type":"text","text":"Hi"}]}}}`, "", ""},
+		{"deep flex", `{"type":"flex","altText":"Card","contents":{"type":"bubble","body":` + deep + `}}`, "flex-depth", ""},
+		{"flex button uri", `{"type":"flex","altText":"Card","contents":{"type":"bubble","footer":{"type":"box","layout":"vertical","contents":[{"type":"button","action":{"type":"uri","label":"Go","uri":"javascript:alert(1)"}}]}}}`, "url-scheme", "messages[0].contents.footer.contents[0].action.uri"},
+		{"quick reply too many", `{"type":"text","text":"Pick","quickReply":{"items":[` +
+			strings.TrimSuffix(strings.Repeat(`{"type":"action","action":{"type":"message","label":"A","text":"a"}},`, 14), ",") + `]}}`, "quick-reply", "messages[0].quickReply.items"},
+		{"quick reply unsupported action", `{"type":"text","text":"Pick","quickReply":{"items":[{"type":"action","action":{"type":"richmenuswitch","label":"A"}}]}}`, "quick-reply", "messages[0].quickReply.items[0].action.type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := lintOne(t, tt.message)
+			if tt.wantRule == "" {
+				if len(findings) != 0 {
+					t.Errorf("expected no findings, got %+v", findings)
+				}
+				return
+			}
+			for _, f := range findings {
+				if f.Rule == tt.wantRule && (tt.wantPath == "" || f.Path == tt.wantPath) {
+					return
+				}
+			}
+			t.Errorf("expected a %s finding at %q, got %+v", tt.wantRule, tt.wantPath, findings)
+		})
+	}
+}
+
+func TestLint_ReportsEveryProblem(t *testing.T) {
+	messages := make([]json.RawMessage, 6)
+	for i := range messages {
+		messages[i] = json.RawMessage(`{"type":"template","template":{"type":"buttons","actions":[]}}`)
+	}
+	findings := Lint(messages)
+	rules := map[string]int{}
+	for _, f := range findings {
+		rules[f.Rule]++
+		if f.Level != LevelError {
+			t.Errorf("expected errors only, got %+v", f)
+		}
+	}
+	if rules["message-count"] != 1 || rules["alt-text"] != 6 || rules["action-count"] != 6 || rules["message-type"] != 6 {
+		t.Errorf("unexpected findings by rule %v", rules)
+	}
+}
+
+func TestRules_CoverFindings(t *testing.T) {
+	known := map[string]bool{}
+	for _, r := range Rules {
+		known[r.ID] = true
+	}
+	for _, f := range Lint([]json.RawMessage{json.RawMessage(`{"type":"flex","contents":{"type":"carousel","contents":[]}}`), json.RawMessage(`[]`)}) {
+		if !known[f.Rule] {
+			t.Errorf("finding uses undocumented rule %q", f.Rule)
+		}
+	}
+}
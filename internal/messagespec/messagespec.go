@@ -0,0 +1,310 @@
+// Package messagespec builds LINE template messages and checks them against
+// the limits of the Messaging API, so mistakes are reported before anything
+// is sent.
+package messagespec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Template types.
+const (
+	TypeConfirm  = "confirm"
+	TypeButtons  = "buttons"
+	TypeCarousel = "carousel"
+)
+
+// Limits of template messages.
+const (
+	MaxAltText          = 400
+	MaxLabel            = 20
+	MaxActionText       = 300
+	MaxURI              = 1000
+	MaxPostbackData     = 300
+	MaxTitle            = 40
+	MaxConfirmText      = 240
+	MaxButtonsText      = 160
+	MaxCarouselText     = 120
+	MaxTextWithTitle    = 60 // buttons and carousel text when there is a title or image
+	MaxImageURL         = 2000
+	MaxButtonsActions   = 4
+	MaxCarouselActions  = 3
+	MaxCarouselColumns  = 10
+	ConfirmActionsCount = 2
+)
+
+// Action is a template action: what happens when a button is tapped.
+type Action struct {
+	Type        string `json:"type"`
+	Label       string `json:"label,omitempty"`
+	Text        string `json:"text,omitempty"`
+	URI         string `json:"uri,omitempty"`
+	Data        string `json:"data,omitempty"`
+	DisplayText string `json:"displayText,omitempty"`
+	// Datetime picker actions
+	Mode    string `json:"mode,omitempty"`
+	Initial string `json:"initial,omitempty"`
+	Max     string `json:"max,omitempty"`
+	Min     string `json:"min,omitempty"`
+	// Clipboard actions
+	ClipboardText string `json:"clipboardText,omitempty"`
+}
+
+// Column is one column of a carousel template.
+type Column struct {
+	ThumbnailImageURL string   `json:"thumbnailImageUrl,omitempty"`
+	Title             string   `json:"title,omitempty"`
+	Text              string   `json:"text"`
+	Actions           []Action `json:"actions"`
+}
+
+// Template is a confirm, buttons, or carousel template. Confirm templates
+// use Text and Actions; buttons templates add Title and
+// ThumbnailImageURL; carousel templates use only Columns.
+type Template struct {
+	Type              string   `json:"type"`
+	ThumbnailImageURL string   `json:"thumbnailImageUrl,omitempty"`
+	Title             string   `json:"title,omitempty"`
+	Text              string   `json:"text,omitempty"`
+	Actions           []Action `json:"actions,omitempty"`
+	Columns           []Column `json:"columns,omitempty"`
+}
+
+// TemplateMessage is a message of type "template".
+type TemplateMessage struct {
+	Type     string   `json:"type"`
+	AltText  string   `json:"altText"`
+	Template Template `json:"template"`
+}
+
+// NewTemplateMessage returns a template message for t. An empty altText
+// defaults to the template's text (the first column's for carousels).
+func NewTemplateMessage(altText string, t Template) TemplateMessage {
+	if altText == "" {
+		altText = t.Text
+		if t.Type == TypeCarousel && len(t.Columns) > 0 {
+			altText = t.Columns[0].Text
+		}
+		if utf8.RuneCountInString(altText) > MaxAltText {
+			altText = string([]rune(altText)[:MaxAltText])
+		}
+	}
+	return TemplateMessage{Type: "template", AltText: altText, Template: t}
+}
+
+// ParseTemplateMessage reads a template message from JSON, either a whole
+// message ({"type":"template",...}) or just its template object.
+func ParseTemplateMessage(data []byte) (TemplateMessage, error) {
+	var probe struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return TemplateMessage{}, fmt.Errorf("invalid template JSON: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if probe.Type == "template" {
+		var msg TemplateMessage
+		if err := dec.Decode(&msg); err != nil {
+			return TemplateMessage{}, fmt.Errorf("invalid template message: %w", err)
+		}
+		return msg, nil
+	}
+	var t Template
+	if err := dec.Decode(&t); err != nil {
+		return TemplateMessage{}, fmt.Errorf("invalid template: %w", err)
+	}
+	return NewTemplateMessage("", t), nil
+}
+
+// ParseAction parses the action shorthand used by command flags:
+//
+//	message:<label>:<text>
+//	uri:<label>:<uri>
+//	postback:<label>:<data>
+//
+// Labels cannot contain colons; the last part may.
+func ParseAction(s string) (Action, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return Action{}, fmt.Errorf("invalid action %q: expected <type>:<label>:<value>, e.g. message:Yes:yes", s)
+	}
+	a := Action{Type: parts[0], Label: parts[1]}
+	switch a.Type {
+	case "message":
+		a.Text = parts[2]
+	case "uri":
+		a.URI = parts[2]
+	case "postback":
+		a.Data = parts[2]
+	default:
+		return Action{}, fmt.Errorf("invalid action %q: type must be message, uri, or postback", s)
+	}
+	return a, nil
+}
+
+// Validate checks the message against the Messaging API's limits. The
+// error names the offending field, e.g. template.columns[1].actions[0].label.
+func (m TemplateMessage) Validate() error {
+	if m.Type != "template" {
+		return fmt.Errorf("type must be \"template\", got %q", m.Type)
+	}
+	if m.AltText == "" {
+		return fmt.Errorf("altText is required")
+	}
+	if err := maxLen("altText", m.AltText, MaxAltText); err != nil {
+		return err
+	}
+	return m.Template.Validate()
+}
+
+// Validate checks the template against the limits of its type.
+func (t Template) Validate() error {
+	switch t.Type {
+	case TypeConfirm:
+		if t.Text == "" {
+			return fmt.Errorf("template.text is required")
+		}
+		if err := maxLen("template.text", t.Text, MaxConfirmText); err != nil {
+			return err
+		}
+		if len(t.Actions) != ConfirmActionsCount {
+			return fmt.Errorf("confirm templates need exactly %d actions, got %d", ConfirmActionsCount, len(t.Actions))
+		}
+		return validateActions("template.actions", t.Actions)
+
+	case TypeButtons:
+		if t.Text == "" {
+			return fmt.Errorf("template.text is required")
+		}
+		if err := validateHeading("template", t.Title, t.ThumbnailImageURL, t.Text, MaxButtonsText); err != nil {
+			return err
+		}
+		if len(t.Actions) < 1 || len(t.Actions) > MaxButtonsActions {
+			return fmt.Errorf("buttons templates need 1 to %d actions, got %d", MaxButtonsActions, len(t.Actions))
+		}
+		return validateActions("template.actions", t.Actions)
+
+	case TypeCarousel:
+		if len(t.Columns) < 1 || len(t.Columns) > MaxCarouselColumns {
+			return fmt.Errorf("carousel templates need 1 to %d columns, got %d", MaxCarouselColumns, len(t.Columns))
+		}
+		first := t.Columns[0]
+		for i, c := range t.Columns {
+			field := fmt.Sprintf("template.columns[%d]", i)
+			if c.Text == "" {
+				return fmt.Errorf("%s.text is required", field)
+			}
+			if err := validateHeading(field, c.Title, c.ThumbnailImageURL, c.Text, MaxCarouselText); err != nil {
+				return err
+			}
+			if len(c.Actions) < 1 || len(c.Actions) > MaxCarouselActions {
+				return fmt.Errorf("%s needs 1 to %d actions, got %d", field, MaxCarouselActions, len(c.Actions))
+			}
+			// LINE requires every column to have the same layout
+			if len(c.Actions) != len(first.Actions) {
+				return fmt.Errorf("%s has %d actions, but columns[0] has %d: every column needs the same number", field, len(c.Actions), len(first.Actions))
+			}
+			if (c.Title == "") != (first.Title == "") || (c.ThumbnailImageURL == "") != (first.ThumbnailImageURL == "") {
+				return fmt.Errorf("%s: either every column or none has a title, and likewise an image", field)
+			}
+			if err := validateActions(field+".actions", c.Actions); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "":
+		return fmt.Errorf("template.type is required")
+	default:
+		return fmt.Errorf("unsupported template type %q (use confirm, buttons, or carousel)", t.Type)
+	}
+}
+
+// validateHeading checks the title, image, and text of a buttons template or
+// carousel column; text gets less room under a title or image.
+func validateHeading(field, title, imageURL, text string, maxText int) error {
+	if err := maxLen(field+".title", title, MaxTitle); err != nil {
+		return err
+	}
+	if imageURL != "" {
+		if err := validateURL(field+".thumbnailImageUrl", imageURL, MaxImageURL, "https"); err != nil {
+			return err
+		}
+	}
+	if title != "" || imageURL != "" {
+		maxText = MaxTextWithTitle
+	}
+	return maxLen(field+".text", text, maxText)
+}
+
+func validateActions(field string, actions []Action) error {
+	for i, a := range actions {
+		if err := a.validate(fmt.Sprintf("%s[%d]", field, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a Action) validate(field string) error {
+	if a.Label == "" {
+		return fmt.Errorf("%s.label is required", field)
+	}
+	if err := maxLen(field+".label", a.Label, MaxLabel); err != nil {
+		return err
+	}
+	switch a.Type {
+	case "message":
+		if a.Text == "" {
+			return fmt.Errorf("%s.text is required for message actions", field)
+		}
+		return maxLen(field+".text", a.Text, MaxActionText)
+	case "uri":
+		return validateURL(field+".uri", a.URI, MaxURI, "http", "https", "line", "tel")
+	case "postback":
+		if a.Data == "" {
+			return fmt.Errorf("%s.data is required for postback actions", field)
+		}
+		if err := maxLen(field+".data", a.Data, MaxPostbackData); err != nil {
+			return err
+		}
+		return maxLen(field+".displayText", a.DisplayText, MaxActionText)
+	case "":
+		return fmt.Errorf("%s.type is required", field)
+	default:
+		return fmt.Errorf("%s.type %q is not supported (use message, uri, or postback)", field, a.Type)
+	}
+}
+
+func maxLen(field, s string, limit int) error {
+	if n := utf8.RuneCountInString(s); n > limit {
+		return fmt.Errorf("%s is %d characters, at most %d are allowed", field, n, limit)
+	}
+	return nil
+}
+
+func validateURL(field, raw string, limit int, schemes ...string) error {
+	if raw == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	if len(raw) > limit {
+		return fmt.Errorf("%s is %d characters, at most %d are allowed", field, len(raw), limit)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid URL: %q", field, raw)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must use %s, got %q", field, strings.Join(schemes, ", "), raw)
+}
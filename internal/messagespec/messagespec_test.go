@@ -0,0 +1,116 @@
+package messagespec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Action
+		wantErr bool
+	}{
+		{"message:Yes:yes", Action{Type: "message", Label: "Yes", Text: "yes"}, false},
+		{"uri:Open:https://example.com/a?b=c", Action{Type: "uri", Label: "Open", URI: "https://example.com/a?b=c"}, false},
+		{"postback:Buy:action=buy:item=1", Action{Type: "postback", Label: "Buy", Data: "action=buy:item=1"}, false},
+		{"message:Yes", Action{}, true},
+		{"message::yes", Action{}, true},
+		{"camera:Take:photo", Action{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAction(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAction(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAction(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func yesNo() []Action {
+	return []Action{{Type: "message", Label: "Yes", Text: "yes"}, {Type: "message", Label: "No", Text: "no"}}
+}
+
+func column(title string, actions int) Column {
+	c := Column{Title: title, Text: "Column text"}
+	for i := 0; i < actions; i++ {
+		c.Actions = append(c.Actions, Action{Type: "postback", Label: "Pick", Data: "pick"})
+	}
+	return c
+}
+
+func TestTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Template
+		wantErr string
+	}{
+		{"confirm", Template{Type: TypeConfirm, Text: "Sure?", Actions: yesNo()}, ""},
+		{"confirm one action", Template{Type: TypeConfirm, Text: "Sure?", Actions: yesNo()[:1]}, "exactly 2 actions"},
+		{"confirm long text", Template{Type: TypeConfirm, Text: strings.Repeat("a", 241), Actions: yesNo()}, "template.text is 241 characters"},
+		{"buttons", Template{Type: TypeButtons, Text: strings.Repeat("a", 160), Actions: yesNo()}, ""},
+		{"buttons text under title", Template{Type: TypeButtons, Title: "Menu", Text: strings.Repeat("a", 61), Actions: yesNo()}, "at most 60"},
+		{"buttons five actions", Template{Type: TypeButtons, Text: "Pick", Actions: append(yesNo(), yesNo()[0], yesNo()[0], yesNo()[0])}, "1 to 4 actions"},
+		{"buttons http image", Template{Type: TypeButtons, Text: "Pick", ThumbnailImageURL: "http://example.com/a.jpg", Actions: yesNo()}, "must use https"},
+		{"long label", Template{Type: TypeButtons, Text: "Pick", Actions: []Action{{Type: "message", Label: strings.Repeat("l", 21), Text: "x"}}}, "template.actions[0].label is 21 characters"},
+		{"uri scheme", Template{Type: TypeButtons, Text: "Pick", Actions: []Action{{Type: "uri", Label: "Go", URI: "ftp://example.com"}}}, "must use http, https, line, tel"},
+		{"postback without data", Template{Type: TypeButtons, Text: "Pick", Actions: []Action{{Type: "postback", Label: "Go"}}}, "data is required"},
+		{"carousel", Template{Type: TypeCarousel, Columns: []Column{column("A", 2), column("B", 2)}}, ""},
+		{"carousel no columns", Template{Type: TypeCarousel}, "1 to 10 columns"},
+		{"carousel uneven actions", Template{Type: TypeCarousel, Columns: []Column{column("A", 2), column("B", 1)}}, "columns[1] has 1 actions"},
+		{"carousel uneven titles", Template{Type: TypeCarousel, Columns: []Column{column("A", 1), column("", 1)}}, "every column or none has a title"},
+		{"carousel four actions", Template{Type: TypeCarousel, Columns: []Column{column("A", 4)}}, "1 to 3 actions"},
+		{"unknown type", Template{Type: "image_carousel"}, "unsupported template type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewTemplateMessage("Alt text", tt.t).Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewTemplateMessage_AltText(t *testing.T) {
+	msg := NewTemplateMessage("", Template{Type: TypeConfirm, Text: "Sure?"})
+	if msg.Type != "template" || msg.AltText != "Sure?" {
+		t.Errorf("got %+v", msg)
+	}
+	msg = NewTemplateMessage("", Template{Type: TypeCarousel, Columns: []Column{{Text: "First"}, {Text: "Second"}}})
+	if msg.AltText != "First" {
+		t.Errorf("carousel alt text = %q, want first column's text", msg.AltText)
+	}
+	msg = NewTemplateMessage("Custom", Template{Type: TypeConfirm, Text: "Sure?"})
+	if msg.AltText != "Custom" {
+		t.Errorf("alt text = %q, want Custom", msg.AltText)
+	}
+}
+
+func TestParseTemplateMessage(t *testing.T) {
+	msg, err := ParseTemplateMessage([]byte(`{"type":"template","altText":"Pick","template":{"type":"confirm","text":"Sure?","actions":[]}}`))
+	if err != nil || msg.AltText != "Pick" || msg.Template.Type != TypeConfirm {
+		t.Errorf("whole message: got %+v, %v", msg, err)
+	}
+
+	msg, err = ParseTemplateMessage([]byte(`{"type":"buttons","text":"Menu","actions":[{"type":"message","label":"A","text":"a"}]}`))
+	if err != nil || msg.Type != "template" || msg.AltText != "Menu" || len(msg.Template.Actions) != 1 {
+		t.Errorf("template only: got %+v, %v", msg, err)
+	}
+
+	if _, err := ParseTemplateMessage([]byte(`{"type":"buttons","txt":"typo"}`)); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := ParseTemplateMessage([]byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
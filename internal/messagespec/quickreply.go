@@ -0,0 +1,124 @@
+package messagespec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Limits of quick replies.
+const (
+	MaxQuickReplyItems = 13
+	MaxClipboardText   = 1000
+)
+
+// QuickReplyItem is one quick reply button.
+type QuickReplyItem struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"imageUrl,omitempty"`
+	Action   Action `json:"action"`
+}
+
+// quickReplyActions are the action types quick reply buttons support.
+var quickReplyActions = map[string]bool{
+	"message": true, "postback": true, "uri": true, "datetimepicker": true,
+	"camera": true, "cameraRoll": true, "location": true, "clipboard": true,
+}
+
+// ParseQuickReply reads and validates quick reply items from JSON: either
+// the array of items or a {"items": [...]} object. It returns the items as
+// given, so fields this package does not model are sent unchanged.
+func ParseQuickReply(data []byte) (json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var wrapper struct {
+			Items json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("invalid quick reply JSON: %w", err)
+		}
+		data = wrapper.Items
+	}
+
+	var items []QuickReplyItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid quick reply JSON: expected an array of items: %w", err)
+	}
+	if err := ValidateQuickReply(items); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
+
+// ValidateQuickReply checks quick reply items against LINE's limits: 1 to
+// 13 items, each an action of a type quick replies support.
+func ValidateQuickReply(items []QuickReplyItem) error {
+	if len(items) < 1 || len(items) > MaxQuickReplyItems {
+		return fmt.Errorf("quick reply needs 1 to %d items, got %d", MaxQuickReplyItems, len(items))
+	}
+	for i, item := range items {
+		field := fmt.Sprintf("quickReply.items[%d]", i)
+		if item.Type != "action" {
+			return fmt.Errorf("%s.type must be \"action\", got %q", field, item.Type)
+		}
+		if item.ImageURL != "" {
+			if err := validateURL(field+".imageUrl", item.ImageURL, MaxImageURL, "https"); err != nil {
+				return err
+			}
+		}
+		if err := item.Action.validateQuickReply(field + ".action"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a Action) validateQuickReply(field string) error {
+	if a.Type == "" {
+		return fmt.Errorf("%s.type is required", field)
+	}
+	if !quickReplyActions[a.Type] {
+		return fmt.Errorf("%s.type %q is not supported in quick replies", field, a.Type)
+	}
+	switch a.Type {
+	case "message", "postback", "uri":
+		return a.validate(field)
+	case "datetimepicker":
+		if a.Data == "" {
+			return fmt.Errorf("%s.data is required for datetimepicker actions", field)
+		}
+		if a.Mode != "date" && a.Mode != "time" && a.Mode != "datetime" {
+			return fmt.Errorf("%s.mode must be date, time, or datetime, got %q", field, a.Mode)
+		}
+	case "clipboard":
+		if a.ClipboardText == "" {
+			return fmt.Errorf("%s.clipboardText is required for clipboard actions", field)
+		}
+		if err := maxLen(field+".clipboardText", a.ClipboardText, MaxClipboardText); err != nil {
+			return err
+		}
+	}
+	if a.Label == "" {
+		return fmt.Errorf("%s.label is required", field)
+	}
+	return maxLen(field+".label", a.Label, MaxLabel)
+}
+
+// WithQuickReply returns message, one of the message types the API
+// accepts, as JSON with items attached as its quick reply.
+func WithQuickReply(message any, items json.RawMessage) (json.RawMessage, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("message is not a JSON object: %w", err)
+	}
+	quickReply, err := json.Marshal(map[string]json.RawMessage{"items": items})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode quick reply: %w", err)
+	}
+	fields["quickReply"] = quickReply
+	return json.Marshal(fields)
+}
@@ -0,0 +1,80 @@
+package messagespec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func quickReplyJSON(n int, action string) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"type":"action","action":%s}`, action)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestParseQuickReply(t *testing.T) {
+	message := `{"type":"message","label":"Yes","text":"yes"}`
+	tests := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{
+		{"array", quickReplyJSON(2, message), ""},
+		{"items object", `{"items":` + quickReplyJSON(1, message) + `}`, ""},
+		{"thirteen items", quickReplyJSON(13, message), ""},
+		{"fourteen items", quickReplyJSON(14, message), "1 to 13 items"},
+		{"empty", `[]`, "1 to 13 items"},
+		{"camera", quickReplyJSON(1, `{"type":"camera","label":"Camera"}`), ""},
+		{"datetimepicker", quickReplyJSON(1, `{"type":"datetimepicker","label":"When","data":"d","mode":"date"}`), ""},
+		{"datetimepicker mode", quickReplyJSON(1, `{"type":"datetimepicker","label":"When","data":"d","mode":"week"}`), "mode must be date, time, or datetime"},
+		{"unsupported action", quickReplyJSON(1, `{"type":"richmenuswitch","label":"Menu","richMenuAliasId":"a","data":"d"}`), "not supported in quick replies"},
+		{"long label", quickReplyJSON(1, `{"type":"location","label":"`+strings.Repeat("l", 21)+`"}`), "label is 21 characters"},
+		{"item type", `[{"type":"button","action":` + message + `}]`, `type must be "action"`},
+		{"http image", `[{"type":"action","imageUrl":"http://example.com/i.png","action":` + message + `}]`, "must use https"},
+		{"not JSON", `nope`, "invalid quick reply JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuickReply([]byte(tt.in))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithQuickReply(t *testing.T) {
+	// Fields this package does not model are kept
+	items, err := ParseQuickReply([]byte(`[{"type":"action","action":{"type":"message","label":"Yes","text":"yes","extra":1}}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := WithQuickReply(map[string]string{"type": "text", "text": "Hi"}, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var msg struct {
+		Type       string `json:"type"`
+		QuickReply struct {
+			Items []struct {
+				Action map[string]any `json:"action"`
+			} `json:"items"`
+		} `json:"quickReply"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	if msg.Type != "text" || len(msg.QuickReply.Items) != 1 || msg.QuickReply.Items[0].Action["extra"] != float64(1) {
+		t.Errorf("unexpected message %s", data)
+	}
+}
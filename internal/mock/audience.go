@@ -0,0 +1,286 @@
+package mock
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// firstAudienceID is the ID of the first audience created; LINE's IDs are
+// large integers.
+const firstAudienceID = 1000000000
+
+func (s *Server) routeAudience(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 1 && rest[0] == "upload":
+		s.handleAudienceUpload(w, r)
+	case len(rest) == 2 && rest[0] == "upload" && rest[1] == "byFile":
+		s.handleAudienceUploadFile(w, r)
+	case len(rest) == 1 && rest[0] == "list":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		s.handleAudienceList(w, r)
+	case len(rest) >= 1 && len(rest) <= 2:
+		id, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil {
+			notImplemented(w, r)
+			return
+		}
+		action := ""
+		if len(rest) == 2 {
+			action = rest[1]
+		}
+		s.handleAudience(w, r, id, action)
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// audienceGroup describes an audience as LINE does.
+func audienceGroup(a *audience) map[string]any {
+	return map[string]any{
+		"audienceGroupId": a.ID,
+		"type":            "UPLOAD",
+		"description":     a.Description,
+		"status":          "READY",
+		"audienceCount":   len(a.Users),
+		"created":         a.Created,
+		"permission":      "READ_WRITE",
+		"createRoute":     "MESSAGING_API",
+		"isIfaAudience":   false,
+	}
+}
+
+func checkDescription(description string) []errorDetail {
+	if description == "" || utf8.RuneCountInString(description) > 120 {
+		return []errorDetail{{Message: "Length must be between 1 and 120", Property: "description"}}
+	}
+	return nil
+}
+
+func checkAudienceUsers(ids []string) []errorDetail {
+	var details []errorDetail
+	for i, id := range ids {
+		if !userIDPattern.MatchString(id) {
+			details = append(details, errorDetail{Message: "Invalid user ID", Property: fmt.Sprintf("audiences[%d].id", i)})
+		}
+	}
+	return details
+}
+
+// handleAudienceUpload creates an audience (POST) or adds users to one
+// (PUT) from a JSON list of user IDs.
+func (s *Server) handleAudienceUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		methodNotAllowed(w)
+		return
+	}
+	var req struct {
+		AudienceGroupID int64  `json:"audienceGroupId"`
+		Description     string `json:"description"`
+		Audiences       []struct {
+			ID string `json:"id"`
+		} `json:"audiences"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	ids := make([]string, len(req.Audiences))
+	for i, a := range req.Audiences {
+		ids[i] = a.ID
+	}
+	if r.Method == http.MethodPost {
+		s.createAudience(w, req.Description, ids)
+		return
+	}
+	s.addAudienceUsers(w, req.AudienceGroupID, ids)
+}
+
+// handleAudienceUploadFile is handleAudienceUpload for a file of user IDs,
+// one per line.
+func (s *Server) handleAudienceUploadFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		methodNotAllowed(w)
+		return
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid multipart form: "+err.Error())
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "The request has 1 error(s)", errorDetail{Message: "Must not be empty", Property: "file"})
+		return
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to read the file")
+		return
+	}
+	var ids []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			ids = append(ids, line)
+		}
+	}
+	if r.Method == http.MethodPost {
+		s.createAudience(w, r.FormValue("description"), ids)
+		return
+	}
+	id, err := strconv.ParseInt(r.FormValue("audienceGroupId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "The request has 1 error(s)", errorDetail{Message: "Must be a number", Property: "audienceGroupId"})
+		return
+	}
+	s.addAudienceUsers(w, id, ids)
+}
+
+func (s *Server) createAudience(w http.ResponseWriter, description string, ids []string) {
+	details := append(checkDescription(description), checkAudienceUsers(ids)...)
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body has %d error(s)", len(details)), details...)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := &audience{
+		ID:          firstAudienceID + int64(len(s.state.audienceOrder)) + 1,
+		Description: description,
+		Created:     s.opts.Now().Unix(),
+		Users:       map[string]bool{},
+		Jobs:        1,
+	}
+	for s.state.audiences[a.ID] != nil {
+		a.ID++
+	}
+	for _, id := range ids {
+		a.Users[id] = true
+	}
+	s.state.audiences[a.ID] = a
+	s.state.audienceOrder = append(s.state.audienceOrder, a.ID)
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"audienceGroupId": a.ID,
+		"type":            "UPLOAD",
+		"description":     a.Description,
+		"created":         a.Created,
+	})
+}
+
+func (s *Server) addAudienceUsers(w http.ResponseWriter, audienceID int64, ids []string) {
+	details := checkAudienceUsers(ids)
+	if len(ids) == 0 {
+		details = append(details, errorDetail{Message: "Must not be empty", Property: "audiences"})
+	}
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body has %d error(s)", len(details)), details...)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.state.audiences[audienceID]
+	if a == nil {
+		writeError(w, http.StatusBadRequest, "audience group not found")
+		return
+	}
+	for _, id := range ids {
+		a.Users[id] = true
+	}
+	a.Jobs++
+	writeJSON(w, http.StatusAccepted, map[string]any{})
+}
+
+func (s *Server) handleAudienceList(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 40 {
+		size = 20
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := len(s.state.audienceOrder)
+	start := min((page-1)*size, total)
+	end := min(start+size, total)
+	groups := make([]map[string]any, 0, end-start)
+	for _, id := range s.state.audienceOrder[start:end] {
+		groups = append(groups, audienceGroup(s.state.audiences[id]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"audienceGroups":                   groups,
+		"hasNextPage":                      end < total,
+		"totalCount":                       total,
+		"readWriteAudienceGroupTotalCount": total,
+		"page":                             page,
+		"size":                             size,
+	})
+}
+
+func (s *Server) handleAudience(w http.ResponseWriter, r *http.Request, id int64, action string) {
+	var req struct {
+		Description string `json:"description"`
+	}
+	if action == "updateDescription" {
+		if r.Method != http.MethodPut {
+			methodNotAllowed(w)
+			return
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if details := checkDescription(req.Description); len(details) > 0 {
+			writeError(w, http.StatusBadRequest, "The request body has 1 error(s)", details...)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.state.audiences[id]
+	if a == nil {
+		writeError(w, http.StatusNotFound, "audience group not found")
+		return
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		jobs := make([]map[string]any, a.Jobs)
+		for i := range jobs {
+			jobs[i] = map[string]any{
+				"audienceGroupJobId": a.ID*10 + int64(i),
+				"audienceGroupId":    a.ID,
+				"type":               "DIFF_ADD",
+				"jobStatus":          "FINISHED",
+				"created":            a.Created,
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"audienceGroup": audienceGroup(a), "jobs": jobs})
+	case action == "" && r.Method == http.MethodDelete:
+		delete(s.state.audiences, id)
+		for i, other := range s.state.audienceOrder {
+			if other == id {
+				s.state.audienceOrder = append(s.state.audienceOrder[:i], s.state.audienceOrder[i+1:]...)
+				break
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	case action == "updateDescription":
+		a.Description = req.Description
+		writeJSON(w, http.StatusOK, map[string]any{})
+	case action == "activate" && r.Method == http.MethodPut:
+		writeJSON(w, http.StatusAccepted, map[string]any{})
+	case action == "" || action == "activate":
+		methodNotAllowed(w)
+	default:
+		notImplemented(w, r)
+	}
+}
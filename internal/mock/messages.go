@@ -0,0 +1,304 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+)
+
+// jst is the time zone LINE counts quotas and delivery dates in.
+var jst = time.FixedZone("JST", 9*60*60)
+
+// messageTypes are the message types LINE accepts.
+var messageTypes = map[string]bool{
+	"text": true, "sticker": true, "image": true, "video": true, "audio": true,
+	"location": true, "imagemap": true, "template": true, "flex": true, "textV2": true,
+}
+
+func (s *Server) routeMessage(w http.ResponseWriter, r *http.Request, rest []string, requestID string) {
+	if len(rest) == 0 {
+		notImplemented(w, r)
+		return
+	}
+	switch {
+	case len(rest) == 1 && (rest[0] == "push" || rest[0] == "multicast" || rest[0] == "broadcast" || rest[0] == "narrowcast" || rest[0] == "reply"):
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		s.handleSend(w, r, rest[0], requestID)
+	case len(rest) == 1 && rest[0] == "quota":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		if s.opts.Quota > 0 {
+			writeJSON(w, http.StatusOK, map[string]any{"type": "limited", "value": s.opts.Quota})
+		} else {
+			writeJSON(w, http.StatusOK, map[string]any{"type": "none"})
+		}
+	case len(rest) == 2 && rest[0] == "quota" && rest[1] == "consumption":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		s.mu.Lock()
+		used := s.consumedLocked()
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"totalUsage": used})
+	case len(rest) == 2 && rest[0] == "validate":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		var req struct {
+			Messages []json.RawMessage `json:"messages"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		if details := checkMessages(req.Messages); len(details) > 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body has %d error(s)", len(details)), details...)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	case len(rest) == 2 && rest[0] == "progress" && rest[1] == "narrowcast":
+		s.handleNarrowcastProgress(w, r)
+	case len(rest) == 2 && rest[0] == "delivery":
+		s.handleDeliveryStats(w, r, rest[1])
+	default:
+		notImplemented(w, r)
+	}
+}
+
+// handleSend accepts a push, multicast, broadcast, narrowcast, or reply.
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request, sendType, requestID string) {
+	var req struct {
+		To         json.RawMessage   `json:"to"`
+		ReplyToken string            `json:"replyToken"`
+		Messages   []json.RawMessage `json:"messages"`
+		Recipient  json.RawMessage   `json:"recipient"`
+		Filter     json.RawMessage   `json:"filter"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	details := checkMessages(req.Messages)
+
+	sent := SentMessage{
+		RequestID: requestID,
+		Type:      sendType,
+		Time:      s.opts.Now(),
+		Messages:  req.Messages,
+		Recipient: req.Recipient,
+		Filter:    req.Filter,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch sendType {
+	case "push":
+		var to string
+		if err := json.Unmarshal(req.To, &to); err != nil || !chatIDPattern.MatchString(to) {
+			details = append(details, errorDetail{Message: "Invalid user, group, or room ID", Property: "to"})
+		}
+		sent.To, sent.Recipients = []string{to}, 1
+	case "multicast":
+		var to []string
+		if err := json.Unmarshal(req.To, &to); err != nil || len(to) == 0 || len(to) > 500 {
+			details = append(details, errorDetail{Message: "Size must be between 1 and 500", Property: "to"})
+		}
+		for i, id := range to {
+			if !userIDPattern.MatchString(id) {
+				details = append(details, errorDetail{Message: "Invalid user ID", Property: fmt.Sprintf("to[%d]", i)})
+			}
+		}
+		sent.To, sent.Recipients = to, len(to)
+	case "broadcast":
+		sent.Recipients = s.opts.Followers
+	case "narrowcast":
+		sent.Recipients = s.opts.Followers
+		var recipient struct {
+			Type            string `json:"type"`
+			AudienceGroupID int64  `json:"audienceGroupId"`
+		}
+		if len(req.Recipient) > 0 && json.Unmarshal(req.Recipient, &recipient) == nil && recipient.Type == "audience" {
+			a := s.state.audiences[recipient.AudienceGroupID]
+			if a == nil {
+				writeError(w, http.StatusBadRequest, "Audience group not found", errorDetail{Message: "Audience group not found", Property: "recipient.audienceGroupId"})
+				return
+			}
+			sent.Recipients = len(a.Users)
+		}
+	case "reply":
+		if req.ReplyToken == "" {
+			details = append(details, errorDetail{Message: "Must not be empty", Property: "replyToken"})
+		}
+	}
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body has %d error(s)", len(details)), details...)
+		return
+	}
+
+	if sendType != "reply" && s.opts.Quota > 0 && s.consumedLocked()+sent.Recipients > s.opts.Quota {
+		writeError(w, http.StatusTooManyRequests, "You have reached your monthly limit.")
+		return
+	}
+	s.state.messages = append(s.state.messages, sent)
+
+	if sendType == "push" || sendType == "reply" {
+		sentMessages := make([]map[string]any, len(req.Messages))
+		for i := range req.Messages {
+			sentMessages[i] = map[string]any{"id": fmt.Sprintf("%d%03d", sent.Time.UnixMilli(), i), "quoteToken": fmt.Sprintf("mock-quote-%s-%d", requestID, i)}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"sentMessages": sentMessages})
+		return
+	}
+	if sendType == "narrowcast" {
+		writeJSON(w, http.StatusAccepted, map[string]any{})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+// checkMessages returns what LINE would object to in a messages array.
+func checkMessages(messages []json.RawMessage) []errorDetail {
+	if len(messages) == 0 || len(messages) > 5 {
+		return []errorDetail{{Message: "Size must be between 1 and 5", Property: "messages"}}
+	}
+	var details []errorDetail
+	for i, raw := range messages {
+		prop := fmt.Sprintf("messages[%d]", i)
+		var m map[string]any
+		if err := json.Unmarshal(raw, &m); err != nil {
+			details = append(details, errorDetail{Message: "Must be an object", Property: prop})
+			continue
+		}
+		msgType, _ := m["type"].(string)
+		if !messageTypes[msgType] {
+			details = append(details, errorDetail{Message: "Invalid message type", Property: prop + ".type"})
+			continue
+		}
+		switch msgType {
+		case "text", "textV2":
+			text, _ := m["text"].(string)
+			if text == "" {
+				details = append(details, errorDetail{Message: "Must not be empty", Property: prop + ".text"})
+			} else if n := utf8.RuneCountInString(text); n > 5000 {
+				details = append(details, errorDetail{Message: "Length must be between 0 and 5000", Property: prop + ".text"})
+			}
+		case "flex", "template", "imagemap":
+			if alt, _ := m["altText"].(string); alt == "" {
+				details = append(details, errorDetail{Message: "Must not be empty", Property: prop + ".altText"})
+			}
+		case "image", "video":
+			for _, key := range []string{"originalContentUrl", "previewImageUrl"} {
+				if u, _ := m[key].(string); len(u) < 8 || u[:8] != "https://" {
+					details = append(details, errorDetail{Message: "Must be an HTTPS URL", Property: prop + "." + key})
+				}
+			}
+		}
+	}
+	return details
+}
+
+// consumedLocked returns the messages counted against the quota this
+// month. s.mu must be held.
+func (s *Server) consumedLocked() int {
+	now := s.opts.Now().In(jst)
+	total := 0
+	for _, m := range s.state.messages {
+		t := m.Time.In(jst)
+		if m.Type != "reply" && t.Year() == now.Year() && t.Month() == now.Month() {
+			total += m.Recipients
+		}
+	}
+	return total
+}
+
+func (s *Server) findMessage(requestID string) *SentMessage {
+	for i := range s.state.messages {
+		if s.state.messages[i].RequestID == requestID {
+			return &s.state.messages[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleNarrowcastProgress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.findMessage(r.URL.Query().Get("requestId"))
+	if m == nil || m.Type != "narrowcast" {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"phase":         "succeeded",
+		"successCount":  m.Recipients,
+		"failureCount":  0,
+		"targetCount":   m.Recipients,
+		"acceptedTime":  m.Time.UTC().Format(time.RFC3339Nano),
+		"completedTime": m.Time.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// handleDeliveryStats counts the messages of a type sent on a JST date.
+func (s *Server) handleDeliveryStats(w http.ResponseWriter, r *http.Request, sendType string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	date := r.URL.Query().Get("date")
+	if _, err := time.Parse("20060102", date); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid date", errorDetail{Message: "Must be yyyyMMdd", Property: "date"})
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, m := range s.state.messages {
+		if m.Type == sendType && m.Time.In(jst).Format("20060102") == date {
+			total += m.Recipients
+			if sendType == "reply" {
+				total++
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ready", "success": total})
+}
+
+func (s *Server) routeInsight(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) != 2 || rest[0] != "message" || rest[1] != "event" {
+		notImplemented(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requestID := r.URL.Query().Get("requestId")
+	m := s.findMessage(requestID)
+	if m == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"overview": map[string]any{
+			"requestId": requestID,
+			"timestamp": m.Time.Unix(),
+			"delivered": m.Recipients,
+		},
+		"messages": []any{},
+		"clicks":   []any{},
+	})
+}
@@ -0,0 +1,313 @@
+// Package mock is an in-memory stand-in for the parts of the LINE
+// Messaging API that the CLI uses: messages, rich menus, and audiences. It
+// lets scripts be rehearsed end to end without touching a real channel.
+//
+// State lives only in memory and is lost when the server stops. Requests
+// are checked the way LINE checks them where it matters to a rehearsal
+// (unknown IDs, malformed messages, user ID format, the monthly quota), and
+// answered with LINE's error format. Anything the mock does not implement
+// answers 404 with a message saying so.
+//
+// Two endpoints outside the API inspect and clear the state:
+// GET /mock/state and POST /mock/reset.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options configure a Server.
+type Options struct {
+	// Quota is the monthly message limit; 0 means unlimited
+	Quota int
+	// Followers is how many users a broadcast reaches
+	Followers int
+	// Now returns the current time; nil means time.Now
+	Now func() time.Time
+	// Log receives one line per request; nil disables logging
+	Log io.Writer
+}
+
+// Server answers Messaging API requests from in-memory state.
+type Server struct {
+	opts Options
+
+	mu    sync.Mutex
+	state *state
+	seq   int
+	logMu sync.Mutex
+}
+
+// NewServer returns a Server with empty state.
+func NewServer(opts Options) *Server {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Server{opts: opts, state: newState()}
+}
+
+// SentMessage is a message request the mock accepted.
+type SentMessage struct {
+	RequestID string            `json:"requestId"`
+	Type      string            `json:"type"`
+	Time      time.Time         `json:"time"`
+	To        []string          `json:"to,omitempty"`
+	Recipient json.RawMessage   `json:"recipient,omitempty"`
+	Filter    json.RawMessage   `json:"filter,omitempty"`
+	Messages  []json.RawMessage `json:"messages"`
+	// Recipients is how many users the request reached, which counts
+	// against the quota for everything but replies
+	Recipients int `json:"recipients"`
+}
+
+type richMenu struct {
+	Menu        json.RawMessage
+	Name        string
+	Image       []byte
+	ContentType string
+}
+
+type audience struct {
+	ID          int64
+	Description string
+	Created     int64
+	Users       map[string]bool
+	Jobs        int
+}
+
+type state struct {
+	messages      []SentMessage
+	richMenus     map[string]*richMenu
+	menuOrder     []string
+	aliases       map[string]string
+	defaultMenu   string
+	userMenus     map[string]string
+	audiences     map[int64]*audience
+	audienceOrder []int64
+}
+
+func newState() *state {
+	return &state{
+		richMenus: map[string]*richMenu{},
+		aliases:   map[string]string{},
+		userMenus: map[string]string{},
+		audiences: map[int64]*audience{},
+	}
+}
+
+// apiError is LINE's error response body.
+type apiError struct {
+	Message string        `json:"message"`
+	Details []errorDetail `json:"details,omitempty"`
+}
+
+type errorDetail struct {
+	Message  string `json:"message"`
+	Property string `json:"property,omitempty"`
+}
+
+var (
+	userIDPattern = regexp.MustCompile(`^U[0-9a-f]{32}$`)
+	chatIDPattern = regexp.MustCompile(`^[UCR][0-9a-f]{32}$`)
+)
+
+// ServeHTTP answers one request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	start := s.opts.Now()
+	s.mu.Lock()
+	s.seq++
+	requestID := fmt.Sprintf("%08x-0000-4000-8000-%012x", start.Unix()&0xffffffff, s.seq)
+	s.mu.Unlock()
+	rec.Header().Set("X-Line-Request-Id", requestID)
+
+	s.route(rec, r, requestID)
+
+	if s.opts.Log != nil {
+		s.logMu.Lock()
+		_, _ = fmt.Fprintf(s.opts.Log, "[%s] %s %s -> %d\n", start.Format("15:04:05"), r.Method, r.URL.RequestURI(), rec.status)
+		s.logMu.Unlock()
+	}
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, requestID string) {
+	path := strings.Trim(r.URL.Path, "/")
+	switch {
+	case path == "mock/state":
+		s.handleState(w, r)
+		return
+	case path == "mock/reset":
+		s.handleReset(w, r)
+		return
+	case path == "oauth2/v3/token" || path == "v2/oauth/accessToken" || path == "oauth2/v2.1/token":
+		s.handleToken(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "Authentication failed. Confirm that the access token in the authorization header is valid.")
+		return
+	}
+
+	seg := strings.Split(path, "/")
+	if len(seg) < 3 || seg[0] != "v2" || seg[1] != "bot" {
+		notImplemented(w, r)
+		return
+	}
+	rest := seg[2:]
+	switch rest[0] {
+	case "info":
+		s.handleBotInfo(w, r)
+	case "profile":
+		s.handleProfile(w, r, rest[1:])
+	case "message":
+		s.routeMessage(w, r, rest[1:], requestID)
+	case "insight":
+		s.routeInsight(w, r, rest[1:])
+	case "richmenu":
+		s.routeRichMenu(w, r, rest[1:])
+	case "user":
+		s.routeUser(w, r, rest[1:])
+	case "audienceGroup":
+		s.routeAudience(w, r, rest[1:])
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	menus := make([]map[string]any, 0, len(s.state.menuOrder))
+	for _, id := range s.state.menuOrder {
+		m := s.state.richMenus[id]
+		menus = append(menus, map[string]any{"richMenuId": id, "name": m.Name, "hasImage": m.Image != nil})
+	}
+	audiences := make([]map[string]any, 0, len(s.state.audienceOrder))
+	for _, id := range s.state.audienceOrder {
+		a := s.state.audiences[id]
+		audiences = append(audiences, map[string]any{"audienceGroupId": id, "description": a.Description, "audienceCount": len(a.Users)})
+	}
+	messages := s.state.messages
+	if messages == nil {
+		messages = []SentMessage{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"messages":          messages,
+		"quotaUsed":         s.consumedLocked(),
+		"richMenus":         menus,
+		"aliases":           s.state.aliases,
+		"defaultRichMenuId": s.state.defaultMenu,
+		"userRichMenus":     s.state.userMenus,
+		"audiences":         audiences,
+	})
+}
+
+func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	s.mu.Lock()
+	s.state = newState()
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+// handleToken issues a token for any client credentials.
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	s.mu.Lock()
+	s.seq++
+	token := fmt.Sprintf("mock-token-%d", s.seq)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"access_token": token, "expires_in": 900, "token_type": "Bearer"})
+}
+
+func (s *Server) handleBotInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"userId":         "U" + strings.Repeat("0", 31) + "1",
+		"basicId":        "@mock",
+		"displayName":    "Mock Bot",
+		"chatMode":       "bot",
+		"markAsReadMode": "auto",
+	})
+}
+
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) != 1 {
+		notImplemented(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	if !userIDPattern.MatchString(rest[0]) {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"userId":      rest[0],
+		"displayName": "User " + rest[0][len(rest[0])-4:],
+		"language":    "en",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string, details ...errorDetail) {
+	writeJSON(w, status, apiError{Message: message, Details: details})
+}
+
+func notImplemented(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, fmt.Sprintf("The mock server does not implement %s %s", r.Method, r.URL.Path))
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// decodeBody reads a JSON request body into v, answering 400 if it is not
+// valid JSON.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "The request body has 1 error(s)", errorDetail{Message: "Invalid JSON: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// statusRecorder remembers the status written, for the request log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,303 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+func testUserID(n int) string {
+	return fmt.Sprintf("U%032x", n)
+}
+
+func newTestClient(t *testing.T, opts Options) (*api.Client, *Server) {
+	t.Helper()
+	if opts.Now == nil {
+		opts.Now = func() time.Time { return time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC) }
+	}
+	mock := NewServer(opts)
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	return client, mock
+}
+
+func getState(t *testing.T, client *api.Client) map[string]any {
+	t.Helper()
+	data, err := client.Get(context.Background(), "/mock/state")
+	if err != nil {
+		t.Fatalf("GET /mock/state error = %v", err)
+	}
+	var state map[string]any
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestMessagesAndQuota(t *testing.T) {
+	client, _ := newTestClient(t, Options{Quota: 10, Followers: 5})
+	ctx := context.Background()
+
+	if err := client.SendMessage(ctx, "push", testUserID(1), nil, api.TextMessage{Type: "text", Text: "hi"}); err != nil {
+		t.Fatalf("push error = %v", err)
+	}
+	if err := client.SendMessage(ctx, "multicast", "", []string{testUserID(1), testUserID(2)}, api.TextMessage{Type: "text", Text: "hi"}); err != nil {
+		t.Fatalf("multicast error = %v", err)
+	}
+	resp, err := client.Broadcast(ctx, api.BroadcastMessageRequest{Messages: []any{api.TextMessage{Type: "text", Text: "all"}}})
+	if err != nil {
+		t.Fatalf("broadcast error = %v", err)
+	}
+	if resp.RequestID == "" {
+		t.Error("expected a request ID for the broadcast")
+	}
+
+	consumption, err := client.GetMessageConsumption(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consumption.TotalUsage != 8 {
+		t.Errorf("totalUsage = %d, want 8", consumption.TotalUsage)
+	}
+
+	// A second broadcast would go over the quota of 10
+	_, err = client.Broadcast(ctx, api.BroadcastMessageRequest{Messages: []any{api.TextMessage{Type: "text", Text: "again"}}})
+	if err == nil || !strings.Contains(err.Error(), "monthly limit") {
+		t.Errorf("expected the monthly limit error, got %v", err)
+	}
+
+	events, err := client.GetMessageEventStats(ctx, resp.RequestID)
+	if err != nil {
+		t.Fatalf("GetMessageEventStats() error = %v", err)
+	}
+	if events.Overview == nil || events.Overview.Delivered != 5 {
+		t.Errorf("unexpected overview: %+v", events.Overview)
+	}
+
+	state := getState(t, client)
+	if messages := state["messages"].([]any); len(messages) != 3 {
+		t.Errorf("expected 3 recorded messages, got %d", len(messages))
+	}
+}
+
+func TestMessagesRejectedLikeLINE(t *testing.T) {
+	client, _ := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		send func() error
+		want string
+	}{
+		{"invalid push target", func() error {
+			return client.SendMessage(ctx, "push", "not-a-user", nil, api.TextMessage{Type: "text", Text: "hi"})
+		}, "to"},
+		{"empty text", func() error {
+			return client.SendMessage(ctx, "push", testUserID(1), nil, api.TextMessage{Type: "text"})
+		}, "messages[0].text"},
+		{"unknown type", func() error {
+			return client.SendMessage(ctx, "push", testUserID(1), nil, map[string]string{"type": "bogus"})
+		}, "messages[0].type"},
+		{"invalid multicast ID", func() error {
+			return client.SendMessage(ctx, "multicast", "", []string{testUserID(1), "U123"}, api.TextMessage{Type: "text", Text: "hi"})
+		}, "to[1]"},
+		{"unknown audience", func() error {
+			_, err := client.NarrowcastTextMessage(ctx, "hi", 42)
+			return err
+		}, "Audience group not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.send()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if messages := getState(t, client)["messages"].([]any); len(messages) != 0 {
+		t.Errorf("rejected messages were recorded: %v", messages)
+	}
+}
+
+func TestRichMenuLifecycle(t *testing.T) {
+	client, _ := newTestClient(t, Options{})
+	ctx := context.Background()
+
+	menu := api.CreateRichMenuRequest{
+		Size:        api.RichMenuSize{Width: 2500, Height: 843},
+		Name:        "main",
+		ChatBarText: "Menu",
+		Areas: []api.RichMenuArea{{
+			Bounds: api.RichMenuBounds{Width: 2500, Height: 843},
+			Action: json.RawMessage(`{"type":"message","text":"hi"}`),
+		}},
+	}
+	id, err := client.CreateRichMenu(ctx, menu)
+	if err != nil {
+		t.Fatalf("CreateRichMenu() error = %v", err)
+	}
+
+	got, err := client.GetRichMenu(ctx, id)
+	if err != nil || got.RichMenuID != id || got.Name != "main" {
+		t.Fatalf("GetRichMenu() = %+v, %v", got, err)
+	}
+
+	// The default menu needs an image first
+	if err := client.SetDefaultRichMenu(ctx, id); err == nil {
+		t.Error("expected an error setting a menu without an image as default")
+	}
+	if err := client.UploadRichMenuImage(ctx, id, "image/png", []byte("png")); err != nil {
+		t.Fatalf("UploadRichMenuImage() error = %v", err)
+	}
+	if err := client.UploadRichMenuImage(ctx, id, "image/png", []byte("png")); err == nil {
+		t.Error("expected an error uploading a second image")
+	}
+	if err := client.SetDefaultRichMenu(ctx, id); err != nil {
+		t.Fatalf("SetDefaultRichMenu() error = %v", err)
+	}
+	if def, err := client.GetDefaultRichMenuID(ctx); err != nil || def != id {
+		t.Errorf("GetDefaultRichMenuID() = %q, %v", def, err)
+	}
+
+	if err := client.CreateRichMenuAlias(ctx, "main-alias", id); err != nil {
+		t.Fatalf("CreateRichMenuAlias() error = %v", err)
+	}
+	if err := client.CreateRichMenuAlias(ctx, "main-alias", id); err == nil {
+		t.Error("expected an error creating a duplicate alias")
+	}
+	if err := client.LinkRichMenuToUsers(ctx, id, []string{testUserID(1), testUserID(2)}); err != nil {
+		t.Fatalf("LinkRichMenuToUsers() error = %v", err)
+	}
+	if linked, err := client.GetUserRichMenu(ctx, testUserID(2)); err != nil || linked != id {
+		t.Errorf("GetUserRichMenu() = %q, %v", linked, err)
+	}
+
+	// Deleting the menu clears everything that pointed at it
+	if err := client.DeleteRichMenu(ctx, id); err != nil {
+		t.Fatalf("DeleteRichMenu() error = %v", err)
+	}
+	if aliases, err := client.ListRichMenuAliases(ctx); err != nil || len(aliases) != 0 {
+		t.Errorf("ListRichMenuAliases() after delete = %v, %v", aliases, err)
+	}
+	if _, err := client.GetDefaultRichMenuID(ctx); err == nil {
+		t.Error("expected no default rich menu after delete")
+	}
+	if _, err := client.GetRichMenu(ctx, id); err == nil {
+		t.Error("expected an error getting a deleted menu")
+	}
+}
+
+func TestRichMenuValidation(t *testing.T) {
+	client, _ := newTestClient(t, Options{})
+	_, err := client.CreateRichMenu(context.Background(), api.CreateRichMenuRequest{
+		Size:        api.RichMenuSize{Width: 100, Height: 843},
+		Name:        "bad",
+		ChatBarText: "This is far too long for a chat bar",
+	})
+	if err == nil {
+		t.Fatal("expected an error for an invalid rich menu")
+	}
+	for _, want := range []string{"size.width", "chatBarText", "areas"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestAudiencesAndNarrowcast(t *testing.T) {
+	client, _ := newTestClient(t, Options{Followers: 100})
+	ctx := context.Background()
+
+	created, err := client.CreateAudienceGroup(ctx, "VIPs", []string{testUserID(1), testUserID(2)})
+	if err != nil {
+		t.Fatalf("CreateAudienceGroup() error = %v", err)
+	}
+	if _, err := client.CreateAudienceGroup(ctx, "Bad", []string{"U123"}); err == nil {
+		t.Error("expected an error for an invalid user ID")
+	}
+
+	path := filepath.Join(t.TempDir(), "users.txt")
+	if err := os.WriteFile(path, []byte(testUserID(3)+"\n"+testUserID(1)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddUsersToAudienceFromFile(ctx, created.AudienceGroupID, path, ""); err != nil {
+		t.Fatalf("AddUsersToAudienceFromFile() error = %v", err)
+	}
+
+	detail, err := client.GetAudienceGroup(ctx, created.AudienceGroupID)
+	if err != nil {
+		t.Fatalf("GetAudienceGroup() error = %v", err)
+	}
+	if g := detail.AudienceGroup; g == nil || g.AudienceCount == nil || *g.AudienceCount != 3 {
+		t.Errorf("unexpected audience group: %+v", detail.AudienceGroup)
+	}
+
+	resp, err := client.NarrowcastTextMessage(ctx, "hi", created.AudienceGroupID)
+	if err != nil {
+		t.Fatalf("NarrowcastTextMessage() error = %v", err)
+	}
+	progress, err := client.GetNarrowcastProgress(ctx, resp.RequestID)
+	if err != nil {
+		t.Fatalf("GetNarrowcastProgress() error = %v", err)
+	}
+	if progress["phase"] != "succeeded" || progress["successCount"] != float64(3) {
+		t.Errorf("unexpected progress: %v", progress)
+	}
+
+	groups, err := client.GetAudienceGroups(ctx)
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("GetAudienceGroups() = %v, %v", groups, err)
+	}
+	if err := client.DeleteAudienceGroup(ctx, created.AudienceGroupID); err != nil {
+		t.Fatalf("DeleteAudienceGroup() error = %v", err)
+	}
+	if _, err := client.GetAudienceGroup(ctx, created.AudienceGroupID); err == nil {
+		t.Error("expected an error getting a deleted audience")
+	}
+}
+
+func TestAuthResetAndUnimplemented(t *testing.T) {
+	mock := NewServer(Options{})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v2/bot/info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without a token = %d, want 401", resp.StatusCode)
+	}
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(server.URL)
+	ctx := context.Background()
+
+	_, err = client.Get(ctx, "/v2/bot/followers/ids")
+	if err == nil || !strings.Contains(err.Error(), "does not implement") {
+		t.Errorf("expected a not implemented error, got %v", err)
+	}
+
+	if err := client.SendMessage(ctx, "push", testUserID(1), nil, api.TextMessage{Type: "text", Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Post(ctx, "/mock/reset", nil); err != nil {
+		t.Fatalf("POST /mock/reset error = %v", err)
+	}
+	if messages := getState(t, client)["messages"].([]any); len(messages) != 0 {
+		t.Errorf("messages after reset = %v", messages)
+	}
+}
@@ -0,0 +1,398 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+)
+
+// maxRichMenuImageSize is the largest rich menu image LINE accepts.
+const maxRichMenuImageSize = 1 << 20
+
+type richMenuBody struct {
+	Size struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"size"`
+	Name        string            `json:"name"`
+	ChatBarText string            `json:"chatBarText"`
+	Areas       []json.RawMessage `json:"areas"`
+}
+
+// checkRichMenu returns what LINE would object to in a rich menu.
+func checkRichMenu(raw json.RawMessage) (richMenuBody, []errorDetail) {
+	var m richMenuBody
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return m, []errorDetail{{Message: "Invalid JSON: " + err.Error()}}
+	}
+	var details []errorDetail
+	if m.Size.Width < 800 || m.Size.Width > 2500 {
+		details = append(details, errorDetail{Message: "Must be between 800 and 2500", Property: "size.width"})
+	}
+	if m.Size.Height < 250 {
+		details = append(details, errorDetail{Message: "Must be at least 250", Property: "size.height"})
+	}
+	if m.Name == "" || utf8.RuneCountInString(m.Name) > 300 {
+		details = append(details, errorDetail{Message: "Length must be between 1 and 300", Property: "name"})
+	}
+	if m.ChatBarText == "" || utf8.RuneCountInString(m.ChatBarText) > 14 {
+		details = append(details, errorDetail{Message: "Length must be between 1 and 14", Property: "chatBarText"})
+	}
+	if len(m.Areas) == 0 || len(m.Areas) > 20 {
+		details = append(details, errorDetail{Message: "Size must be between 1 and 20", Property: "areas"})
+	}
+	return m, details
+}
+
+func (s *Server) routeRichMenu(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 0:
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		s.handleCreateRichMenu(w, r)
+	case len(rest) == 1 && rest[0] == "list":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		menus := make([]json.RawMessage, 0, len(s.state.menuOrder))
+		for _, id := range s.state.menuOrder {
+			menus = append(menus, s.state.richMenus[id].Menu)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"richmenus": menus})
+	case len(rest) == 1 && rest[0] == "validate":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Failed to read the request body")
+			return
+		}
+		if _, details := checkRichMenu(raw); len(details) > 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body has %d error(s)", len(details)), details...)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	case len(rest) >= 1 && rest[0] == "alias":
+		s.routeRichMenuAlias(w, r, rest[1:])
+	case len(rest) == 2 && rest[0] == "bulk" && (rest[1] == "link" || rest[1] == "unlink"):
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		s.handleBulkLink(w, r, rest[1] == "link")
+	case len(rest) == 1 && rest[0] != "batch":
+		s.handleRichMenu(w, r, rest[0])
+	case len(rest) == 2 && rest[1] == "content":
+		s.handleRichMenuImage(w, r, rest[0])
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) handleCreateRichMenu(w http.ResponseWriter, r *http.Request) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to read the request body")
+		return
+	}
+	body, details := checkRichMenu(raw)
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body has %d error(s)", len(details)), details...)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.state.richMenus) >= 1000 {
+		writeError(w, http.StatusBadRequest, "Reached the maximum number of rich menus (1000)")
+		return
+	}
+	s.seq++
+	id := fmt.Sprintf("richmenu-%032x", s.seq)
+
+	// Store the menu as LINE returns it: the request plus its ID
+	var menu map[string]any
+	_ = json.Unmarshal(raw, &menu)
+	menu["richMenuId"] = id
+	stored, _ := json.Marshal(menu)
+
+	s.state.richMenus[id] = &richMenu{Menu: stored, Name: body.Name}
+	s.state.menuOrder = append(s.state.menuOrder, id)
+	writeJSON(w, http.StatusOK, map[string]any{"richMenuId": id})
+}
+
+func (s *Server) handleRichMenu(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.state.richMenus[id]
+	if m == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(m.Menu)
+	case http.MethodDelete:
+		s.deleteRichMenuLocked(id)
+		writeJSON(w, http.StatusOK, map[string]any{})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// deleteRichMenuLocked removes a rich menu and everything that points at
+// it, as LINE does. s.mu must be held.
+func (s *Server) deleteRichMenuLocked(id string) {
+	delete(s.state.richMenus, id)
+	for i, other := range s.state.menuOrder {
+		if other == id {
+			s.state.menuOrder = append(s.state.menuOrder[:i], s.state.menuOrder[i+1:]...)
+			break
+		}
+	}
+	for alias, menu := range s.state.aliases {
+		if menu == id {
+			delete(s.state.aliases, alias)
+		}
+	}
+	for user, menu := range s.state.userMenus {
+		if menu == id {
+			delete(s.state.userMenus, user)
+		}
+	}
+	if s.state.defaultMenu == id {
+		s.state.defaultMenu = ""
+	}
+}
+
+func (s *Server) handleRichMenuImage(w http.ResponseWriter, r *http.Request, id string) {
+	var data []byte
+	if r.Method == http.MethodPost {
+		var err error
+		data, err = io.ReadAll(io.LimitReader(r.Body, maxRichMenuImageSize+1))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Failed to read the request body")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.state.richMenus[id]
+	if m == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		contentType := r.Header.Get("Content-Type")
+		switch {
+		case contentType != "image/png" && contentType != "image/jpeg":
+			writeError(w, http.StatusUnsupportedMediaType, "The image must be PNG or JPEG")
+		case len(data) > maxRichMenuImageSize:
+			writeError(w, http.StatusRequestEntityTooLarge, "The image must be 1 MB or smaller")
+		case len(data) == 0:
+			writeError(w, http.StatusBadRequest, "The image is empty")
+		case m.Image != nil:
+			writeError(w, http.StatusBadRequest, "An image has already been uploaded to the richmenu")
+		default:
+			m.Image, m.ContentType = data, contentType
+			writeJSON(w, http.StatusOK, map[string]any{})
+		}
+	case http.MethodGet:
+		if m.Image == nil {
+			writeError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		w.Header().Set("Content-Type", m.ContentType)
+		_, _ = w.Write(m.Image)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) routeRichMenuAlias(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 0:
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		var req struct {
+			RichMenuAliasID string `json:"richMenuAliasId"`
+			RichMenuID      string `json:"richMenuId"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case req.RichMenuAliasID == "":
+			writeError(w, http.StatusBadRequest, "The request body has 1 error(s)", errorDetail{Message: "Must not be empty", Property: "richMenuAliasId"})
+		case s.state.richMenus[req.RichMenuID] == nil:
+			writeError(w, http.StatusBadRequest, "richmenu not found")
+		case s.state.aliases[req.RichMenuAliasID] != "":
+			writeError(w, http.StatusBadRequest, "conflict richmenu alias id")
+		default:
+			s.state.aliases[req.RichMenuAliasID] = req.RichMenuID
+			writeJSON(w, http.StatusOK, map[string]any{})
+		}
+	case len(rest) == 1 && rest[0] == "list":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		aliases := make([]map[string]string, 0, len(s.state.aliases))
+		for alias, menu := range s.state.aliases {
+			aliases = append(aliases, map[string]string{"richMenuAliasId": alias, "richMenuId": menu})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"aliases": aliases})
+	case len(rest) == 1:
+		s.handleRichMenuAlias(w, r, rest[0])
+	default:
+		notImplemented(w, r)
+	}
+}
+
+func (s *Server) handleRichMenuAlias(w http.ResponseWriter, r *http.Request, alias string) {
+	var req struct {
+		RichMenuID string `json:"richMenuId"`
+	}
+	if r.Method == http.MethodPost && !decodeBody(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	menu, ok := s.state.aliases[alias]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]string{"richMenuAliasId": alias, "richMenuId": menu})
+	case http.MethodPost:
+		if s.state.richMenus[req.RichMenuID] == nil {
+			writeError(w, http.StatusBadRequest, "richmenu not found")
+			return
+		}
+		s.state.aliases[alias] = req.RichMenuID
+		writeJSON(w, http.StatusOK, map[string]any{})
+	case http.MethodDelete:
+		delete(s.state.aliases, alias)
+		writeJSON(w, http.StatusOK, map[string]any{})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleBulkLink(w http.ResponseWriter, r *http.Request, link bool) {
+	var req struct {
+		RichMenuID string   `json:"richMenuId"`
+		UserIDs    []string `json:"userIds"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	var details []errorDetail
+	if len(req.UserIDs) == 0 || len(req.UserIDs) > 500 {
+		details = append(details, errorDetail{Message: "Size must be between 1 and 500", Property: "userIds"})
+	}
+	for i, id := range req.UserIDs {
+		if !userIDPattern.MatchString(id) {
+			details = append(details, errorDetail{Message: "Invalid user ID", Property: fmt.Sprintf("userIds[%d]", i)})
+		}
+	}
+	if len(details) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body has %d error(s)", len(details)), details...)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if link && s.state.richMenus[req.RichMenuID] == nil {
+		writeError(w, http.StatusBadRequest, "richmenu not found")
+		return
+	}
+	for _, id := range req.UserIDs {
+		if link {
+			s.state.userMenus[id] = req.RichMenuID
+		} else {
+			delete(s.state.userMenus, id)
+		}
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{})
+}
+
+func (s *Server) routeUser(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) < 2 || rest[1] != "richmenu" || len(rest) > 3 {
+		notImplemented(w, r)
+		return
+	}
+	user := rest[0]
+	if user != "all" && !userIDPattern.MatchString(user) {
+		writeError(w, http.StatusBadRequest, "The value for the 'userId' parameter is invalid")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(rest) == 3 {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		m := s.state.richMenus[rest[2]]
+		if m == nil {
+			writeError(w, http.StatusNotFound, "richmenu not found")
+			return
+		}
+		if m.Image == nil {
+			writeError(w, http.StatusBadRequest, "must upload richmenu image before applying it to user")
+			return
+		}
+		if user == "all" {
+			s.state.defaultMenu = rest[2]
+		} else {
+			s.state.userMenus[user] = rest[2]
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+		return
+	}
+
+	current := s.state.defaultMenu
+	if user != "all" {
+		current = s.state.userMenus[user]
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if current == "" {
+			writeError(w, http.StatusNotFound, "the user has no richmenu")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"richMenuId": current})
+	case http.MethodDelete:
+		if user == "all" {
+			s.state.defaultMenu = ""
+		} else {
+			delete(s.state.userMenus, user)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	default:
+		methodNotAllowed(w)
+	}
+}
@@ -0,0 +1,122 @@
+package output
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ANSI escapes used by Palette.
+const (
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// Color modes of --color.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ColorEnabled reports whether output written to w should be colored in
+// mode: always with ColorAlways, never with ColorNever, and otherwise only
+// when w is a terminal and NO_COLOR is unset.
+func ColorEnabled(mode string, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Palette colors text when Enabled; the zero Palette leaves text alone.
+type Palette struct {
+	Enabled bool
+}
+
+func (p Palette) wrap(code, s string) string {
+	if !p.Enabled || strings.TrimSpace(s) == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// Bold makes s bold.
+func (p Palette) Bold(s string) string { return p.wrap(ansiBold, s) }
+
+// Green colors s green, for success.
+func (p Palette) Green(s string) string { return p.wrap(ansiGreen, s) }
+
+// Red colors s red, for failure.
+func (p Palette) Red(s string) string { return p.wrap(ansiRed, s) }
+
+// Yellow colors s yellow, for work still in progress.
+func (p Palette) Yellow(s string) string { return p.wrap(ansiYellow, s) }
+
+// Status colors a status by what it means: READY and SUCCEEDED green,
+// FAILED and EXPIRED red, IN_PROGRESS and PENDING yellow. The status may
+// be padded; unknown statuses are left alone.
+func (p Palette) Status(s string) string {
+	switch statusKind(strings.TrimSpace(s)) {
+	case statusGood:
+		return p.Green(s)
+	case statusBad:
+		return p.Red(s)
+	case statusPending:
+		return p.Yellow(s)
+	}
+	return s
+}
+
+type statusClass int
+
+const (
+	statusOther statusClass = iota
+	statusGood
+	statusBad
+	statusPending
+)
+
+// statusClasses groups the statuses and phases LINE and this CLI report.
+var statusClasses = map[string]statusClass{
+	"READY":          statusGood,
+	"SUCCEEDED":      statusGood,
+	"SUCCESS":        statusGood,
+	"COMPLETED":      statusGood,
+	"DONE":           statusGood,
+	"SENT":           statusGood,
+	"ACTIVE":         statusGood,
+	"RUNNING":        statusGood,
+	"AVAILABLE":      statusGood,
+	"FAILED":         statusBad,
+	"FAILURE":        statusBad,
+	"ERROR":          statusBad,
+	"EXPIRED":        statusBad,
+	"OUT_OF_SERVICE": statusBad,
+	"UNAVAILABLE":    statusBad,
+	"IN_PROGRESS":    statusPending,
+	"ACTIVATING":     statusPending,
+	"PENDING":        statusPending,
+	"WAITING":        statusPending,
+	"SENDING":        statusPending,
+	"ONGOING":        statusPending,
+	"PROCESSING":     statusPending,
+	"SCHEDULED":      statusPending,
+	"UNREADY":        statusPending,
+}
+
+func statusKind(s string) statusClass {
+	return statusClasses[strings.ToUpper(s)]
+}
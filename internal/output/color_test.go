@@ -0,0 +1,46 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	t.Setenv("NO_COLOR", "")
+	if !ColorEnabled(ColorAlways, &buf) {
+		t.Error("expected color with always")
+	}
+	if ColorEnabled(ColorNever, &buf) || ColorEnabled(ColorAuto, &buf) {
+		t.Error("expected no color with never, or auto without a terminal")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if !ColorEnabled(ColorAlways, &buf) {
+		t.Error("expected always to win over NO_COLOR")
+	}
+}
+
+func TestPalette_Status(t *testing.T) {
+	p := Palette{Enabled: true}
+	tests := []struct {
+		status, want string
+	}{
+		{"READY", "\x1b[32mREADY\x1b[0m"},
+		{"succeeded", "\x1b[32msucceeded\x1b[0m"},
+		{"FAILED  ", "\x1b[31mFAILED  \x1b[0m"},
+		{"EXPIRED", "\x1b[31mEXPIRED\x1b[0m"},
+		{"IN_PROGRESS", "\x1b[33mIN_PROGRESS\x1b[0m"},
+		{"pending", "\x1b[33mpending\x1b[0m"},
+		{"DRAFT", "DRAFT"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := p.Status(tt.status); got != tt.want {
+			t.Errorf("Status(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+
+	if got := (Palette{}).Status("READY"); got != "READY" {
+		t.Errorf("disabled palette colored %q", got)
+	}
+}
@@ -0,0 +1,134 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// record is one CSV row: flattened column names in order and their values.
+type record struct {
+	columns []string
+	values  map[string]string
+}
+
+func (r *record) set(column, value string) {
+	if _, ok := r.values[column]; !ok {
+		r.columns = append(r.columns, column)
+	}
+	r.values[column] = value
+}
+
+func writeCSV(w io.Writer, values []*yaml.Node) error {
+	var records []*record
+	for _, v := range values {
+		for _, item := range recordNodes(v) {
+			r := &record{values: map[string]string{}}
+			flatten(r, "", item)
+			records = append(records, r)
+		}
+	}
+
+	// Columns are the union of all records' columns, in first-seen order
+	var header []string
+	seen := map[string]bool{}
+	for _, r := range records {
+		for _, c := range r.columns {
+			if !seen[c] {
+				seen[c] = true
+				header = append(header, c)
+			}
+		}
+	}
+	if len(header) == 0 {
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(header)
+	for _, r := range records {
+		row := make([]string, len(header))
+		for i, c := range header {
+			row[i] = r.values[c]
+		}
+		_ = cw.Write(row)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// recordNodes returns the records in a JSON value: the items of an array,
+// the items of the only array field of an object, or the value itself.
+func recordNodes(v *yaml.Node) []*yaml.Node {
+	switch v.Kind {
+	case yaml.SequenceNode:
+		return v.Content
+	case yaml.MappingNode:
+		var list *yaml.Node
+		for i := 1; i < len(v.Content); i += 2 {
+			if v.Content[i].Kind == yaml.SequenceNode {
+				if list != nil {
+					return []*yaml.Node{v}
+				}
+				list = v.Content[i]
+			}
+		}
+		if list != nil {
+			return list.Content
+		}
+	}
+	return []*yaml.Node{v}
+}
+
+// flatten adds the columns of v to r. Objects add one column per field,
+// named prefix.field; arrays of scalars are joined with ";" and other
+// arrays add prefix.index columns.
+func flatten(r *record, prefix string, v *yaml.Node) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch v.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(v.Content); i += 2 {
+			flatten(r, join(v.Content[i].Value), v.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		scalars := make([]string, 0, len(v.Content))
+		for _, item := range v.Content {
+			if item.Kind != yaml.ScalarNode {
+				for i, item := range v.Content {
+					flatten(r, join(fmt.Sprint(i)), item)
+				}
+				return
+			}
+			scalars = append(scalars, scalarValue(item))
+		}
+		r.set(columnName(prefix), strings.Join(scalars, ";"))
+	default:
+		r.set(columnName(prefix), scalarValue(v))
+	}
+}
+
+// columnName names the column of a top-level scalar, which has no field name.
+func columnName(prefix string) string {
+	if prefix == "" {
+		return "value"
+	}
+	return prefix
+}
+
+func scalarValue(v *yaml.Node) string {
+	if v.Tag == "!!null" {
+		return ""
+	}
+	return v.Value
+}
@@ -0,0 +1,224 @@
+// Package output converts the JSON that commands print into other formats,
+// so every command that supports --output json also supports yaml and csv
+// without format switches of its own, and filters it with a Query.
+//
+// Conversion keeps the field order of the JSON. YAML output has one
+// document per JSON value. NDJSON output has one compact JSON line per
+// record, with scalars wrapped as {"value": ...}. CSV output has one row
+// per record: a top-level
+// array is a list of records, as is the only array field of a top-level
+// object (such as {"members": [...], "next": "..."}); anything else is a
+// single record. Nested objects become dotted columns (profile.name).
+//
+// Palette colors text output, statuses by what they mean, for writers
+// where ColorEnabled allows it.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats converted from JSON. JSON itself is only written by a Writer
+// with a query.
+const (
+	JSON   = "json"
+	YAML   = "yaml"
+	CSV    = "csv"
+	NDJSON = "ndjson"
+)
+
+// Convert writes the JSON values in data to w in format.
+func Convert(w io.Writer, format string, data []byte) error {
+	return convert(w, format, nil, data)
+}
+
+func convert(w io.Writer, format string, query *Query, data []byte) error {
+	values, err := decode(data)
+	if err != nil {
+		return err
+	}
+	if query != nil {
+		if values, err = query.apply(values); err != nil {
+			return err
+		}
+	}
+	switch format {
+	case YAML:
+		return writeYAML(w, values)
+	case CSV:
+		return writeCSV(w, values)
+	case NDJSON:
+		return writeNDJSON(w, values)
+	case JSON:
+		if query != nil {
+			return writeJSON(w, values)
+		}
+	}
+	return fmt.Errorf("unsupported output format %q", format)
+}
+
+// Writer buffers JSON written by a command and converts it when closed.
+// Without a query, output that is not JSON, such as a confirmation
+// message, is written unchanged.
+type Writer struct {
+	out    io.Writer
+	format string
+	query  *Query
+	buf    bytes.Buffer
+}
+
+// NewWriter returns a Writer that converts to format on out.
+func NewWriter(out io.Writer, format string) *Writer {
+	return &Writer{out: out, format: format}
+}
+
+// NewQueryWriter returns a Writer that writes the results of query on out
+// in format (json, yaml, csv, or ndjson).
+func NewQueryWriter(out io.Writer, format string, query *Query) *Writer {
+	return &Writer{out: out, format: format, query: query}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+// Close converts everything written so far and writes it out.
+func (w *Writer) Close() error {
+	data := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var out bytes.Buffer
+	if err := convert(&out, w.format, w.query, data); err != nil {
+		if errors.Is(err, errNotJSON) {
+			if w.query != nil {
+				return fmt.Errorf("cannot apply --query %s: command output is not JSON", w.query)
+			}
+			_, err = w.out.Write(data)
+		}
+		return err
+	}
+	_, err := w.out.Write(out.Bytes())
+	return err
+}
+
+var errNotJSON = errors.New("output is not JSON")
+
+// decode parses a stream of JSON values into YAML nodes, keeping the order
+// of object fields.
+func decode(data []byte) ([]*yaml.Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values []*yaml.Node
+	for {
+		node, err := decodeValue(dec)
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errNotJSON, err)
+		}
+		values = append(values, node)
+	}
+}
+
+func decodeValue(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, scalar("!!str", key.(string)), value)
+			}
+			_, err := dec.Token() // }
+			return node, err
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				value, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, value)
+			}
+			_, err := dec.Token() // ]
+			return node, err
+		}
+		return nil, fmt.Errorf("unexpected %v", v)
+	case string:
+		return scalar("!!str", v), nil
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return scalar("!!int", v.String()), nil
+		}
+		return scalar("!!float", v.String()), nil
+	case bool:
+		if v {
+			return scalar("!!bool", "true"), nil
+		}
+		return scalar("!!bool", "false"), nil
+	case nil:
+		return scalar("!!null", "null"), nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+func scalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+func writeYAML(w io.Writer, values []*yaml.Node) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	}
+	return enc.Close()
+}
+
+// writeNDJSON writes each record of values as one line of compact JSON.
+func writeNDJSON(w io.Writer, values []*yaml.Node) error {
+	for _, v := range values {
+		for _, record := range recordNodes(v) {
+			if record.Kind == yaml.ScalarNode {
+				record = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Tag: "!!str", Value: "value"}, record,
+				}}
+			}
+			var b strings.Builder
+			encodeJSON(&b, record, "")
+			var line bytes.Buffer
+			if err := json.Compact(&line, []byte(b.String())); err != nil {
+				return fmt.Errorf("failed to encode NDJSON: %w", err)
+			}
+			line.WriteByte('\n')
+			if _, err := w.Write(line.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
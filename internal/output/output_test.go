@@ -0,0 +1,162 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConvert_YAMLKeepsFieldOrder(t *testing.T) {
+	data := []byte(`{"name":"Shop","id":42,"ratio":0.5,"active":true,"note":null,"code":"007","tags":["a","b"]}`)
+
+	var buf bytes.Buffer
+	if err := Convert(&buf, YAML, data); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	want := `name: Shop
+id: 42
+ratio: 0.5
+active: true
+note: null
+code: "007"
+tags:
+  - a
+  - b
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestConvert_YAMLMultipleValues(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Convert(&buf, YAML, []byte("{\"a\":1}\n{\"a\":2}\n")); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if want := "a: 1\n---\na: 2\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestConvert_CSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "array of objects",
+			data: `[{"id":"1","name":"Spring, sale"},{"id":"2","extra":true}]`,
+			want: "id,name,extra\n1,\"Spring, sale\",\n2,,true\n",
+		},
+		{
+			name: "only array field of an object",
+			data: `{"members":[{"userId":"U1"},{"userId":"U2"}],"next":"token"}`,
+			want: "userId\nU1\nU2\n",
+		},
+		{
+			name: "single object with nested fields",
+			data: `{"id":"rm1","size":{"width":2500,"height":843},"note":null}`,
+			want: "id,size.width,size.height,note\nrm1,2500,843,\n",
+		},
+		{
+			name: "object with several arrays is one record",
+			data: `{"ids":["a","b"],"names":["x"]}`,
+			want: "ids,names\na;b,x\n",
+		},
+		{
+			name: "arrays of objects are indexed",
+			data: `[{"areas":[{"x":0},{"x":10}]}]`,
+			want: "areas.0.x,areas.1.x\n0,10\n",
+		},
+		{
+			name: "array of scalars",
+			data: `["U1","U2"]`,
+			want: "value\nU1\nU2\n",
+		},
+		{
+			name: "empty array",
+			data: `[]`,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Convert(&buf, CSV, []byte(tt.data)); err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_PassesThroughNonJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, YAML)
+	_, _ = w.Write([]byte("Deleted rich menu rm1\n"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if buf.String() != "Deleted rich menu rm1\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestWriter_ConvertsOnClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, CSV)
+	_, _ = w.Write([]byte(`[{"id":"1"},`))
+	_, _ = w.Write([]byte(`{"id":"2"}]` + "\n"))
+	if buf.Len() != 0 {
+		t.Error("expected nothing to be written before Close")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if buf.String() != "id\n1\n2\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestConvert_UnsupportedFormat(t *testing.T) {
+	if err := Convert(&bytes.Buffer{}, "xml", []byte(`{}`)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestConvert_NDJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "only array field of an object",
+			data: `{"members":[{"userId":"U1","tags":["a"]},{"userId":"U2"}],"next":"token"}`,
+			want: "{\"userId\":\"U1\",\"tags\":[\"a\"]}\n{\"userId\":\"U2\"}\n",
+		},
+		{
+			name: "scalars are wrapped",
+			data: `{"userIds":["U1","U2"],"count":2}`,
+			want: "{\"value\":\"U1\"}\n{\"value\":\"U2\"}\n",
+		},
+		{
+			name: "single object",
+			data: `{"id":"rm1","size":{"width":2500}}`,
+			want: "{\"id\":\"rm1\",\"size\":{\"width\":2500}}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Convert(&buf, NDJSON, []byte(tt.data)); err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Query selects values from JSON output with a subset of jq path syntax:
+//
+//	.                  the whole value
+//	.name  .["name"]   an object field (null if missing)
+//	.[0]   .[-1]       an array element, counting from the end if negative
+//	.[]                every element of an array (or value of an object)
+//
+// Steps chain, as in .richmenus[].size.width.
+type Query struct {
+	expr  string
+	steps []queryStep
+}
+
+type queryStep struct {
+	field   string
+	index   int
+	isIndex bool
+	iterate bool
+}
+
+// ParseQuery parses a query expression.
+func ParseQuery(expr string) (*Query, error) {
+	q := &Query{expr: expr}
+	s := strings.TrimSpace(expr)
+	if !strings.HasPrefix(s, ".") {
+		return nil, fmt.Errorf("invalid query %q: must start with '.'", expr)
+	}
+	for s != "" && s != "." {
+		switch {
+		case strings.HasPrefix(s, ".["):
+			s = s[1:]
+		case strings.HasPrefix(s, "["):
+		case strings.HasPrefix(s, "."):
+			n := 1
+			for n < len(s) && isIdentByte(s[n], n == 1) {
+				n++
+			}
+			if n == 1 {
+				return nil, fmt.Errorf("invalid query %q: expected a field name after '.'", expr)
+			}
+			q.steps = append(q.steps, queryStep{field: s[1:n]})
+			s = s[n:]
+			continue
+		default:
+			return nil, fmt.Errorf("invalid query %q: unexpected %q", expr, s)
+		}
+
+		end := strings.IndexByte(s, ']')
+		if strings.HasPrefix(s, `["`) {
+			// A quoted key may contain ']'
+			end = strings.Index(s, `"]`)
+			if end > 0 {
+				end++
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("invalid query %q: missing ']'", expr)
+		}
+		inner := strings.TrimSpace(s[1:end])
+		s = s[end+1:]
+		switch {
+		case inner == "":
+			q.steps = append(q.steps, queryStep{iterate: true})
+		case strings.HasPrefix(inner, `"`):
+			key, err := strconv.Unquote(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query %q: bad key %s", expr, inner)
+			}
+			q.steps = append(q.steps, queryStep{field: key})
+		default:
+			i, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query %q: bad index %s", expr, inner)
+			}
+			q.steps = append(q.steps, queryStep{index: i, isIndex: true})
+		}
+	}
+	return q, nil
+}
+
+func isIdentByte(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+// String returns the query expression.
+func (q *Query) String() string {
+	return q.expr
+}
+
+// apply runs the query on each value and returns all results in order.
+func (q *Query) apply(values []*yaml.Node) ([]*yaml.Node, error) {
+	for _, step := range q.steps {
+		var next []*yaml.Node
+		for _, v := range values {
+			results, err := step.apply(v)
+			if err != nil {
+				return nil, fmt.Errorf("query %s: %w", q.expr, err)
+			}
+			next = append(next, results...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func (s queryStep) apply(v *yaml.Node) ([]*yaml.Node, error) {
+	switch {
+	case s.iterate:
+		switch v.Kind {
+		case yaml.SequenceNode:
+			return v.Content, nil
+		case yaml.MappingNode:
+			var values []*yaml.Node
+			for i := 1; i < len(v.Content); i += 2 {
+				values = append(values, v.Content[i])
+			}
+			return values, nil
+		}
+		return nil, fmt.Errorf("cannot iterate over %s", kindName(v))
+	case s.isIndex:
+		if isNull(v) {
+			return []*yaml.Node{v}, nil
+		}
+		if v.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("cannot index %s with a number", kindName(v))
+		}
+		i := s.index
+		if i < 0 {
+			i += len(v.Content)
+		}
+		if i < 0 || i >= len(v.Content) {
+			return []*yaml.Node{scalar("!!null", "null")}, nil
+		}
+		return []*yaml.Node{v.Content[i]}, nil
+	default:
+		if isNull(v) {
+			return []*yaml.Node{v}, nil
+		}
+		if v.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("cannot get field %q of %s", s.field, kindName(v))
+		}
+		for i := 0; i+1 < len(v.Content); i += 2 {
+			if v.Content[i].Value == s.field {
+				return []*yaml.Node{v.Content[i+1]}, nil
+			}
+		}
+		return []*yaml.Node{scalar("!!null", "null")}, nil
+	}
+}
+
+func isNull(v *yaml.Node) bool {
+	return v.Kind == yaml.ScalarNode && v.Tag == "!!null"
+}
+
+func kindName(v *yaml.Node) string {
+	switch v.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "an array"
+	}
+	switch v.Tag {
+	case "!!str":
+		return "a string"
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "a boolean"
+	}
+	return "a number"
+}
+
+// writeJSON writes each value on its own: strings raw, so they can be used
+// directly in scripts, and anything else as indented JSON.
+func writeJSON(w io.Writer, values []*yaml.Node) error {
+	for _, v := range values {
+		if v.Kind == yaml.ScalarNode && v.Tag == "!!str" {
+			if _, err := fmt.Fprintln(w, v.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		var buf strings.Builder
+		encodeJSON(&buf, v, "")
+		if _, err := fmt.Fprintln(w, buf.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeJSON writes v as JSON indented by two spaces, keeping field order.
+func encodeJSON(b *strings.Builder, v *yaml.Node, indent string) {
+	inner := indent + "  "
+	switch v.Kind {
+	case yaml.MappingNode:
+		if len(v.Content) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		b.WriteString("{\n")
+		for i := 0; i+1 < len(v.Content); i += 2 {
+			key, _ := json.Marshal(v.Content[i].Value)
+			b.WriteString(inner)
+			b.Write(key)
+			b.WriteString(": ")
+			encodeJSON(b, v.Content[i+1], inner)
+			if i+2 < len(v.Content) {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent + "}")
+	case yaml.SequenceNode:
+		if len(v.Content) == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteString("[\n")
+		for i, item := range v.Content {
+			b.WriteString(inner)
+			encodeJSON(b, item, inner)
+			if i+1 < len(v.Content) {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent + "]")
+	default:
+		if v.Tag == "!!str" {
+			s, _ := json.Marshal(v.Value)
+			b.Write(s)
+			return
+		}
+		b.WriteString(v.Value)
+	}
+}
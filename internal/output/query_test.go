@@ -0,0 +1,110 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const richMenus = `{
+  "richmenus": [
+    {"richMenuId": "rm1", "size": {"width": 2500, "height": 1686}, "selected": true},
+    {"richMenuId": "rm2", "size": {"width": 2500, "height": 843}, "selected": false}
+  ],
+  "defaultRichMenu": "rm1",
+  "odd key": {"a]b": 1}
+}`
+
+func runQuery(t *testing.T, format, expr, data string) (string, error) {
+	t.Helper()
+	q, err := ParseQuery(expr)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q): %v", expr, err)
+	}
+	var buf bytes.Buffer
+	w := NewQueryWriter(&buf, format, q)
+	_, _ = w.Write([]byte(data))
+	err = w.Close()
+	return buf.String(), err
+}
+
+func TestQuery_JSON(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{".defaultRichMenu", "rm1\n"},
+		{".richmenus[].richMenuId", "rm1\nrm2\n"},
+		{".richmenus[0].size.height", "1686\n"},
+		{".richmenus[-1].selected", "false\n"},
+		{".richmenus[5]", "null\n"},
+		{".missing.field", "null\n"},
+		{`.["odd key"]["a]b"]`, "1\n"},
+		{".richmenus[1].size", "{\n  \"width\": 2500,\n  \"height\": 843\n}\n"},
+		{".richmenus[0].size[]", "2500\n1686\n"},
+		{".richmenus | length", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if tt.want == "" {
+				if _, err := ParseQuery(tt.expr); err == nil {
+					t.Errorf("expected parse error")
+				}
+				return
+			}
+			got, err := runQuery(t, JSON, tt.expr, richMenus)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuery_WholeValue(t *testing.T) {
+	got, err := runQuery(t, JSON, ".", `{"a":"x","b":[1,"two",null]}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "{\n  \"a\": \"x\",\n  \"b\": [\n    1,\n    \"two\",\n    null\n  ]\n}\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestQuery_OtherFormats(t *testing.T) {
+	got, err := runQuery(t, CSV, ".richmenus[]", richMenus)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "richMenuId,size.width,size.height,selected\nrm1,2500,1686,true\nrm2,2500,843,false\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got, err = runQuery(t, YAML, ".richmenus[0].size", richMenus)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "width: 2500\nheight: 1686\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestQuery_Errors(t *testing.T) {
+	if _, err := runQuery(t, JSON, ".defaultRichMenu[]", richMenus); err == nil || !strings.Contains(err.Error(), "cannot iterate over a string") {
+		t.Errorf("expected iterate error, got %v", err)
+	}
+	if _, err := runQuery(t, JSON, ".richmenus.id", richMenus); err == nil || !strings.Contains(err.Error(), "cannot get field") {
+		t.Errorf("expected field error, got %v", err)
+	}
+	if _, err := runQuery(t, JSON, ".id", "Deleted rich menu rm1\n"); err == nil || !strings.Contains(err.Error(), "not JSON") {
+		t.Errorf("expected not JSON error, got %v", err)
+	}
+	for _, expr := range []string{"", "richmenus", ".[", ".[x]", `.["unterminated]`, ".a..b"} {
+		if _, err := ParseQuery(expr); err == nil {
+			t.Errorf("ParseQuery(%q): expected error", expr)
+		}
+	}
+}
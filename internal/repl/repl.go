@@ -0,0 +1,304 @@
+// Package repl reads the command lines of an interactive session: on a
+// terminal with line editing, history, and Tab completion, and otherwise
+// one line at a time, so a session can also be fed through a pipe.
+package repl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// HistorySize is how many lines the up arrow reaches back.
+const HistorySize = 100
+
+// ErrInterrupted is returned by ReadLine when Ctrl+C abandons the line
+// being typed.
+var ErrInterrupted = errors.New("interrupted")
+
+// Options configure a Reader.
+type Options struct {
+	// In and Out are the terminal, or any reader and writer for a session
+	// that is not interactive
+	In  io.Reader
+	Out io.Writer
+	// History holds lines of earlier sessions, oldest first. Lines starting
+	// with a space are left out of the history, as in shells.
+	History []string
+	// Complete returns the candidates for the last of words, the word being
+	// typed ("" when Tab is pressed after a space). The candidates need not
+	// start with it: those that do not are dropped.
+	Complete func(words []string) []string
+}
+
+// Reader reads command lines.
+type Reader struct {
+	opts    Options
+	history []string
+
+	// On a terminal: the line editor, its input and output, and the
+	// terminal's file descriptor
+	term *term.Terminal
+	io   *terminalIO
+	fd   int
+
+	// Otherwise
+	lines *bufio.Reader
+}
+
+// NewReader returns a Reader for opts.In, with line editing if both
+// opts.In and opts.Out are terminals.
+func NewReader(opts Options) *Reader {
+	r := &Reader{opts: opts}
+	for _, line := range opts.History {
+		r.remember(line)
+	}
+	in, inOK := opts.In.(*os.File)
+	out, outOK := opts.Out.(*os.File)
+	if inOK && outOK && term.IsTerminal(int(in.Fd())) && term.IsTerminal(int(out.Fd())) {
+		r.fd = int(in.Fd())
+		r.io = &terminalIO{in: in, out: out}
+		r.newTerminal()
+	} else {
+		r.lines = bufio.NewReader(opts.In)
+	}
+	return r
+}
+
+// Interactive reports whether lines are read from a terminal.
+func (r *Reader) Interactive() bool {
+	return r.term != nil
+}
+
+// History returns the lines of earlier sessions and of this one, oldest
+// first, up to HistorySize.
+func (r *Reader) History() []string {
+	return append([]string(nil), r.history...)
+}
+
+// ReadLine reads the next line, without its line ending. On a terminal it
+// shows prompt first, and returns ErrInterrupted when Ctrl+C is pressed.
+// At the end of input, or when Ctrl+D is pressed on an empty line, it
+// returns io.EOF.
+func (r *Reader) ReadLine(prompt string) (string, error) {
+	if r.term == nil {
+		line, err := r.lines.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return strings.TrimRight(line, "\r\n"), err
+	}
+
+	// Raw mode only while a line is typed, so commands write to a normal
+	// terminal
+	state, err := term.MakeRaw(r.fd)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = term.Restore(r.fd, state) }()
+	if w, h, err := term.GetSize(r.fd); err == nil && w > 0 {
+		_ = r.term.SetSize(w, h)
+	}
+
+	r.term.SetPrompt(prompt)
+	r.io.interrupted = false
+	line, err := r.term.ReadLine()
+	if err == io.EOF && r.io.interrupted {
+		// The line editor keeps the abandoned line, so start afresh
+		_, _ = io.WriteString(r.io.out, "^C\r\n")
+		r.newTerminal()
+		return "", ErrInterrupted
+	}
+	if err == term.ErrPasteIndicator {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	r.remember(line)
+	return line, nil
+}
+
+// remember adds line to the history, except blank lines, repeats of the
+// last line, and as in shells, lines starting with a space.
+func (r *Reader) remember(line string) {
+	line = strings.TrimRightFunc(line, unicode.IsSpace)
+	if line == "" || strings.HasPrefix(line, " ") || !printable(line) {
+		return
+	}
+	if n := len(r.history); n > 0 && r.history[n-1] == line {
+		return
+	}
+	r.history = append(r.history, line)
+	if len(r.history) > HistorySize {
+		r.history = r.history[len(r.history)-HistorySize:]
+	}
+}
+
+// newTerminal starts a line editor with the history. The editor's history
+// cannot be set directly, so the lines are typed into it with its output
+// discarded.
+func (r *Reader) newTerminal() {
+	var seed strings.Builder
+	for _, line := range r.history {
+		seed.WriteString(line + "\r")
+	}
+	r.io.seed = []byte(seed.String())
+	r.term = term.NewTerminal(r.io, "")
+	for range r.history {
+		if _, err := r.term.ReadLine(); err != nil {
+			break
+		}
+	}
+	r.io.seed = nil
+	r.term.AutoCompleteCallback = r.complete
+}
+
+// complete handles Tab: it completes the word before the cursor as far as
+// the candidates agree, and lists them when they go separate ways.
+func (r *Reader) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || r.opts.Complete == nil {
+		return "", 0, false
+	}
+	head, tail := line[:pos], line[pos:]
+	start := strings.LastIndexFunc(head, unicode.IsSpace) + 1
+	partial := head[start:]
+	if strings.ContainsAny(partial, `'"\`) {
+		return line, pos, true
+	}
+	words, err := Split(head[:start])
+	if err != nil {
+		return line, pos, true
+	}
+
+	var matches []string
+	for _, c := range r.opts.Complete(append(words, partial)) {
+		if strings.HasPrefix(c, partial) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return line, pos, true
+	case 1:
+		completed := head[:start] + matches[0] + " "
+		return completed + tail, len(completed), true
+	}
+	if prefix := commonPrefix(matches); len(prefix) > len(partial) {
+		completed := head[:start] + prefix
+		return completed + tail, len(completed), true
+	}
+	sort.Strings(matches)
+	_, _ = r.term.Write([]byte(strings.Join(matches, "  ") + "\n"))
+	return line, pos, true
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func printable(s string) bool {
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// terminalIO is what the line editor reads and writes: the terminal, or
+// while seed is left, the seed with output discarded. It notes when Ctrl+C
+// was read, which the editor reports as io.EOF like Ctrl+D.
+type terminalIO struct {
+	in          io.Reader
+	out         io.Writer
+	seed        []byte
+	interrupted bool
+}
+
+func (t *terminalIO) Read(p []byte) (int, error) {
+	if len(t.seed) > 0 {
+		n := copy(p, t.seed)
+		t.seed = t.seed[n:]
+		return n, nil
+	}
+	n, err := t.in.Read(p)
+	if bytes.IndexByte(p[:n], 3) >= 0 {
+		t.interrupted = true
+	}
+	return n, err
+}
+
+func (t *terminalIO) Write(p []byte) (int, error) {
+	if t.seed != nil {
+		return len(p), nil
+	}
+	return t.out.Write(p)
+}
+
+// Split splits a command line into words the way a shell does: at
+// whitespace outside quotes. Single quotes keep everything up to the
+// closing quote; in double quotes and outside quotes, a backslash escapes
+// the next character.
+func Split(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, c := range line {
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case unicode.IsSpace(c):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("line ends with a backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
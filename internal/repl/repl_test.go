@@ -0,0 +1,113 @@
+package repl
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  bot   info ", []string{"bot", "info"}},
+		{`message push --to U1 --text "Hello there"`, []string{"message", "push", "--to", "U1", "--text", "Hello there"}},
+		{`--text 'it''s' --x=a\ b`, []string{"--text", "its", "--x=a b"}},
+		{`--text "say \"hi\"" ''`, []string{"--text", `say "hi"`, ""}},
+		{`'a\b'`, []string{`a\b`}},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{`--text "open`, `it's`, `trailing\`} {
+		if _, err := Split(in); err == nil {
+			t.Errorf("Split(%q): expected an error", in)
+		}
+	}
+}
+
+func TestReadLine_NotATerminal(t *testing.T) {
+	r := NewReader(Options{In: strings.NewReader("bot info\r\n\nexit"), Out: io.Discard})
+	if r.Interactive() {
+		t.Fatal("a string reader is not a terminal")
+	}
+	var lines []string
+	for {
+		line, err := r.ReadLine("line> ")
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	if want := []string{"bot info", "", "exit"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
+
+func TestRemember(t *testing.T) {
+	history := []string{"bot info", "bot info", " ", " auth login --token secret", "bad\x1b[A"}
+	for i := 0; i < HistorySize; i++ {
+		history = append(history, "richmenu list")
+	}
+	history = append(history, "quota")
+	r := NewReader(Options{In: strings.NewReader(""), Out: io.Discard, History: history})
+	if got, want := r.History(), []string{"bot info", "richmenu list", "quota"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("history = %q, want %q", got, want)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	var gotWords []string
+	var out bytes.Buffer
+	r := &Reader{opts: Options{Complete: func(words []string) []string {
+		gotWords = words
+		return []string{"richmenu", "room", "reply", "bot"}
+	}}}
+	r.term = term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{strings.NewReader(""), &out}, "> ")
+
+	tests := []struct {
+		line    string
+		pos     int
+		want    string
+		wantPos int
+	}{
+		{"ri", 2, "richmenu ", 9},
+		{"b --x", 1, "bot  --x", 4},
+		{"r", 1, "r", 1},
+		{"re", 2, "reply ", 6},
+		{"zz", 2, "zz", 2},
+		{`"ri`, 3, `"ri`, 3},
+	}
+	for _, tt := range tests {
+		got, pos, ok := r.complete(tt.line, tt.pos, '\t')
+		if !ok || got != tt.want || pos != tt.wantPos {
+			t.Errorf("complete(%q, %d) = %q, %d, %v; want %q, %d", tt.line, tt.pos, got, pos, ok, tt.want, tt.wantPos)
+		}
+	}
+	if !strings.Contains(out.String(), "reply  richmenu  room") {
+		t.Errorf("expected the candidates to be listed, got %q", out.String())
+	}
+
+	if _, _, ok := r.complete("ri", 2, 'x'); ok {
+		t.Error("only Tab should complete")
+	}
+	r.complete(`message push --text "a b" --to `, 31, '\t')
+	if want := []string{"message", "push", "--text", "a b", "--to", ""}; !reflect.DeepEqual(gotWords, want) {
+		t.Errorf("words = %q, want %q", gotWords, want)
+	}
+}
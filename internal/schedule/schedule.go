@@ -0,0 +1,273 @@
+// Package schedule keeps messages queued for sending at a later time, and
+// coupons queued for closing. The Messaging API has no scheduling of its
+// own, so jobs are stored locally, one JSON file each, and run by
+// 'line scheduler run'.
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// Job states. A pending job waits for its time, a sending job has been
+// claimed by a scheduler, and a failed job was attempted and is kept until
+// it is cancelled.
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusFailed  = "failed"
+)
+
+// TypeCouponClose is the type of a job that closes a coupon instead of
+// sending messages.
+const TypeCouponClose = "coupon-close"
+
+// File extensions of pending or failed jobs, and of claimed jobs.
+const (
+	jobExt     = ".json"
+	sendingExt = ".sending"
+)
+
+// ErrNotFound is returned for a job that does not exist, or that a
+// scheduler has already claimed.
+var ErrNotFound = errors.New("scheduled job not found")
+
+// Job is a message send, or a coupon close, queued for a later time.
+type Job struct {
+	ID      string `json:"id"`
+	Account string `json:"account,omitempty"`
+	// Type is push, multicast, broadcast, or TypeCouponClose
+	Type      string            `json:"type"`
+	To        []string          `json:"to,omitempty"`
+	Messages  []json.RawMessage `json:"messages,omitempty"`
+	CouponID  string            `json:"couponId,omitempty"`
+	At        time.Time         `json:"at"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Status    string            `json:"status"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// Due reports whether a pending job should be sent at now.
+func (j Job) Due(now time.Time) bool {
+	return j.Status == StatusPending && !j.At.After(now)
+}
+
+// Dir returns the directory that holds scheduled jobs.
+func Dir() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "schedule"), nil
+}
+
+// Store is a directory of scheduled jobs.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store of the jobs in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Open returns the store in the default directory.
+func Open() (*Store, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve schedule directory: %w", err)
+	}
+	return NewStore(dir), nil
+}
+
+// Add saves a new pending job.
+func (s *Store) Add(job Job) error {
+	if job.ID == "" || strings.ContainsAny(job.ID, `/\.`) {
+		return fmt.Errorf("invalid job ID %q", job.ID)
+	}
+	if _, err := os.Stat(s.path(job.ID, jobExt)); err == nil {
+		return fmt.Errorf("scheduled job %s already exists", job.ID)
+	}
+	job.Status = StatusPending
+	return s.write(job, jobExt)
+}
+
+// List returns every job, soonest first.
+func (s *Store) List() ([]Job, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule directory: %w", err)
+	}
+
+	var jobs []Job
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != jobExt && ext != sendingExt) {
+			continue
+		}
+		job, err := s.read(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if ext == sendingExt {
+			job.Status = StatusSending
+		}
+		jobs = append(jobs, job)
+	}
+	sort.SliceStable(jobs, func(i, k int) bool {
+		if !jobs[i].At.Equal(jobs[k].At) {
+			return jobs[i].At.Before(jobs[k].At)
+		}
+		return jobs[i].ID < jobs[k].ID
+	})
+	return jobs, nil
+}
+
+// Cancel removes a pending or failed job.
+func (s *Store) Cancel(id string) (Job, error) {
+	path := s.path(id, jobExt)
+	job, err := s.read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Job{}, ErrNotFound
+	}
+	if err != nil {
+		return Job{}, err
+	}
+	if err := os.Remove(path); errors.Is(err, os.ErrNotExist) {
+		return Job{}, ErrNotFound
+	} else if err != nil {
+		return Job{}, fmt.Errorf("failed to remove scheduled job: %w", err)
+	}
+	return job, nil
+}
+
+// CountAccount returns the number of jobs, of any status, queued under
+// account.
+func (s *Store) CountAccount(account string) (int, error) {
+	jobs, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, job := range jobs {
+		if job.Account == account {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// RemoveAccount removes the pending and failed jobs queued under account,
+// so a scheduler no longer runs them, and returns how many it removed.
+// Jobs a scheduler has already claimed are left to finish.
+func (s *Store) RemoveAccount(account string) (int, error) {
+	jobs, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, job := range jobs {
+		if job.Account != account || job.Status == StatusSending {
+			continue
+		}
+		if _, err := s.Cancel(job.ID); errors.Is(err, ErrNotFound) {
+			continue // claimed or cancelled meanwhile
+		} else if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Claim marks a pending job as being sent, so that neither another
+// scheduler nor Cancel can act on it. It returns ErrNotFound if the job is
+// gone or already claimed.
+func (s *Store) Claim(id string) (Job, error) {
+	sending := s.path(id, sendingExt)
+	if err := os.Rename(s.path(id, jobExt), sending); errors.Is(err, os.ErrNotExist) {
+		return Job{}, ErrNotFound
+	} else if err != nil {
+		return Job{}, fmt.Errorf("failed to claim scheduled job: %w", err)
+	}
+	job, err := s.read(sending)
+	if err != nil {
+		return Job{}, err
+	}
+	job.Status = StatusSending
+	return job, nil
+}
+
+// Finish records the outcome of a claimed job. A sent job is removed; a
+// failed one is kept with its error so that it shows up in List.
+func (s *Store) Finish(job Job, sendErr error) error {
+	if sendErr != nil {
+		job.Status = StatusFailed
+		job.Error = sendErr.Error()
+		if err := s.write(job, jobExt); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(s.path(job.ID, sendingExt)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove scheduled job: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) path(id, ext string) string {
+	return filepath.Join(s.dir, id+ext)
+}
+
+func (s *Store) read(path string) (Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Job{}, err
+		}
+		return Job{}, fmt.Errorf("failed to read scheduled job: %w", err)
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return Job{}, fmt.Errorf("invalid scheduled job %s: %w", filepath.Base(path), err)
+	}
+	return job, nil
+}
+
+// write saves job through a temporary file, so a scheduler listing the
+// directory never reads a partial job.
+func (s *Store) write(job Job, ext string) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduled job: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create schedule directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".job-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write scheduled job: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write scheduled job: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write scheduled job: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(job.ID, ext)); err != nil {
+		return fmt.Errorf("failed to write scheduled job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testJob(id string, at time.Time) Job {
+	return Job{
+		ID:       id,
+		Type:     "push",
+		To:       []string{"U123"},
+		Messages: []json.RawMessage{json.RawMessage(`{"type":"text","text":"Hi"}`)},
+		At:       at,
+	}
+}
+
+func TestStore_Lifecycle(t *testing.T) {
+	s := NewStore(t.TempDir())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	jobs, err := s.List()
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("expected empty store, got %v, %v", jobs, err)
+	}
+
+	for _, job := range []Job{testJob("later", now.Add(time.Hour)), testJob("due", now)} {
+		if err := s.Add(job); err != nil {
+			t.Fatalf("Add(%s): %v", job.ID, err)
+		}
+	}
+	if err := s.Add(testJob("due", now)); err == nil {
+		t.Error("expected error adding a duplicate ID")
+	}
+	if err := s.Add(testJob("../x", now)); err == nil {
+		t.Error("expected error for an ID with a path")
+	}
+
+	jobs, err = s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != "due" || jobs[1].ID != "later" {
+		t.Fatalf("expected jobs soonest first, got %+v", jobs)
+	}
+	if !jobs[0].Due(now) || jobs[1].Due(now) {
+		t.Errorf("unexpected due state: %v, %v", jobs[0].Due(now), jobs[1].Due(now))
+	}
+
+	job, err := s.Claim("due")
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if _, err := s.Claim("due"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound claiming twice, got %v", err)
+	}
+	if _, err := s.Cancel("due"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound cancelling a claimed job, got %v", err)
+	}
+	if jobs, _ := s.List(); jobs[0].Status != StatusSending {
+		t.Errorf("expected claimed job to be listed as sending, got %s", jobs[0].Status)
+	}
+
+	if err := s.Finish(job, errors.New("boom")); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	jobs, _ = s.List()
+	if len(jobs) != 2 || jobs[0].Status != StatusFailed || jobs[0].Error != "boom" {
+		t.Fatalf("expected failed job to be kept, got %+v", jobs)
+	}
+	if jobs[0].Due(now) {
+		t.Error("failed job should not be due")
+	}
+
+	if _, err := s.Cancel("due"); err != nil {
+		t.Errorf("Cancel failed job: %v", err)
+	}
+	job, err = s.Claim("later")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Finish(job, nil); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ := s.List(); len(jobs) != 0 {
+		t.Errorf("expected sent job to be removed, got %+v", jobs)
+	}
+}
+
+func TestStore_RemoveAccount(t *testing.T) {
+	s := NewStore(t.TempDir())
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	for _, job := range []Job{testJob("a1", now), testJob("a2", now), testJob("b1", now)} {
+		job.Account = "a"
+		if job.ID == "b1" {
+			job.Account = "b"
+		}
+		if err := s.Add(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Claim("a2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := s.CountAccount("a"); err != nil || n != 2 {
+		t.Errorf("CountAccount(a) = %d, %v; want 2", n, err)
+	}
+	if n, err := s.RemoveAccount("a"); err != nil || n != 1 {
+		t.Errorf("RemoveAccount(a) = %d, %v; want 1, leaving the claimed job", n, err)
+	}
+	jobs, err := s.List()
+	if err != nil || len(jobs) != 2 || jobs[0].ID != "a2" || jobs[1].ID != "b1" {
+		t.Errorf("expected a2 (sending) and b1 to remain, got %v, %v", jobs, err)
+	}
+}
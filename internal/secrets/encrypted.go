@@ -0,0 +1,182 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// ErrWrongPassphrase is returned when the encrypted credentials file cannot
+// be decrypted.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted credentials file")
+
+// PassphraseFunc returns the passphrase of the encrypted credentials file.
+// confirm is true when a new file is about to be created, so an interactive
+// prompt should ask twice.
+type PassphraseFunc func(confirm bool) (string, error)
+
+const (
+	encryptedFileVersion = 1
+	encryptedFileKDF     = "pbkdf2-sha256"
+	encryptedFileCipher  = "aes-256-gcm"
+	// encryptedFileAAD binds the ciphertext to this file format
+	encryptedFileAAD = "line-cli credentials v1"
+)
+
+// pbkdf2Iterations is the work factor for new files (OWASP's 2023
+// recommendation for PBKDF2-HMAC-SHA256). Tests lower it.
+var pbkdf2Iterations = 600_000
+
+// EncryptedFilePath returns the location of the encrypted credentials file.
+func EncryptedFilePath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials.enc"), nil
+}
+
+// NewEncryptedFileStore creates a store that keeps credentials in the file
+// at path, encrypted with AES-256-GCM under a key derived from the
+// passphrase with PBKDF2. The passphrase is asked for once, the first time
+// the file is read or created.
+func NewEncryptedFileStore(path string, passphrase PassphraseFunc) *KeyringStore {
+	codec := &encryptedCodec{passphrase: passphrase}
+	return &KeyringStore{ring: &fileKeyring{path: path, codec: codec}, kind: StoreEncryptedFile}
+}
+
+func openDefaultEncryptedFileStore(passphrase PassphraseFunc) (*KeyringStore, error) {
+	if passphrase == nil {
+		return nil, fmt.Errorf("no passphrase available for the encrypted credentials file")
+	}
+	path, err := EncryptedFilePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials file path: %w", err)
+	}
+	return NewEncryptedFileStore(path, passphrase), nil
+}
+
+// encryptedEnvelope is the stored form of an encrypted credentials file.
+type encryptedEnvelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Cipher     string `json:"cipher"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// encryptedCodec encrypts a credentials file. The derived key is kept for
+// the life of the store, so the passphrase is needed only once; the
+// fileKeyring lock serializes access.
+type encryptedCodec struct {
+	passphrase PassphraseFunc
+
+	secret     string
+	salt       []byte
+	iterations int
+	key        []byte
+}
+
+func (c *encryptedCodec) decode(data []byte) ([]byte, error) {
+	var env encryptedEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted credentials file: %w", err)
+	}
+	if env.Version != encryptedFileVersion || env.KDF != encryptedFileKDF || env.Cipher != encryptedFileCipher {
+		return nil, fmt.Errorf("unsupported encrypted credentials file (version %d, %s, %s)", env.Version, env.KDF, env.Cipher)
+	}
+
+	if err := c.deriveKey(env.Salt, env.Iterations, false); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(c.key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, env.Nonce, env.Ciphertext, []byte(encryptedFileAAD))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plain, nil
+}
+
+func (c *encryptedCodec) encode(plain []byte) ([]byte, error) {
+	if c.key == nil {
+		// A new file: pick a salt and ask for the passphrase with confirmation
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		if err := c.deriveKey(salt, pbkdf2Iterations, true); err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := newAEAD(c.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	data, err := json.MarshalIndent(encryptedEnvelope{
+		Version:    encryptedFileVersion,
+		KDF:        encryptedFileKDF,
+		Iterations: c.iterations,
+		Cipher:     encryptedFileCipher,
+		Salt:       c.salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plain, []byte(encryptedFileAAD)),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal encrypted credentials file: %w", err)
+	}
+	return data, nil
+}
+
+// deriveKey sets the key for salt and iterations, asking for the passphrase
+// if it is not known yet.
+func (c *encryptedCodec) deriveKey(salt []byte, iterations int, confirm bool) error {
+	if c.key != nil && bytes.Equal(c.salt, salt) && c.iterations == iterations {
+		return nil
+	}
+	if len(salt) == 0 || iterations <= 0 {
+		return fmt.Errorf("invalid encrypted credentials file: missing salt or iterations")
+	}
+	if c.secret == "" {
+		secret, err := c.passphrase(confirm)
+		if err != nil {
+			return err
+		}
+		if secret == "" {
+			return fmt.Errorf("passphrase cannot be empty")
+		}
+		c.secret = secret
+	}
+	key, err := pbkdf2.Key(sha256.New, c.secret, salt, iterations, 32)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+	c.salt, c.iterations, c.key = salt, iterations, key
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,175 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fastKDF lowers the PBKDF2 work factor for the duration of a test.
+func fastKDF(t *testing.T) {
+	t.Helper()
+	old := pbkdf2Iterations
+	pbkdf2Iterations = 1000
+	t.Cleanup(func() { pbkdf2Iterations = old })
+}
+
+// fixedPassphrase returns a PassphraseFunc that records how it was called.
+func fixedPassphrase(passphrase string, calls *[]bool) PassphraseFunc {
+	return func(confirm bool) (string, error) {
+		*calls = append(*calls, confirm)
+		return passphrase, nil
+	}
+}
+
+func TestEncryptedFileStore_RoundTrip(t *testing.T) {
+	fastKDF(t)
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	var calls []bool
+	store := NewEncryptedFileStore(path, fixedPassphrase("correct horse", &calls))
+	if accounts, err := store.List(); err != nil || len(accounts) != 0 {
+		t.Fatalf("expected no accounts, got %v, %v", accounts, err)
+	}
+	if len(calls) != 0 {
+		t.Error("expected no prompt before the file exists")
+	}
+
+	if err := store.Set("shop", Credentials{ChannelAccessToken: "secret-token"}, "My Shop"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("stateless", Credentials{ChannelID: "1234", ChannelSecret: "channel-secret"}, ""); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if len(calls) != 1 || !calls[0] {
+		t.Errorf("expected one confirmed prompt when creating the file, got %v", calls)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-token", "channel-secret", "My Shop"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("file contains %q in plaintext", secret)
+		}
+	}
+	var env encryptedEnvelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Version != 1 || env.Iterations != 1000 {
+		t.Errorf("unexpected envelope: %+v, %v", env, err)
+	}
+
+	// A new store (a later command) decrypts what the first one wrote
+	calls = nil
+	reopened := NewEncryptedFileStore(path, fixedPassphrase("correct horse", &calls))
+	creds, err := reopened.Get("shop")
+	if err != nil || creds.ChannelAccessToken != "secret-token" {
+		t.Fatalf("Get = %v, %v", creds, err)
+	}
+	creds, err = reopened.Get("stateless")
+	if err != nil || creds.ChannelSecret != "channel-secret" {
+		t.Fatalf("Get = %v, %v", creds, err)
+	}
+	if primary, _ := reopened.GetPrimary(); primary != "shop" {
+		t.Errorf("expected shop to be primary, got %q", primary)
+	}
+	if len(calls) != 1 || calls[0] {
+		t.Errorf("expected one unconfirmed prompt when opening, got %v", calls)
+	}
+
+	// Writing keeps the salt, so the passphrase is not asked again
+	if err := reopened.Delete("stateless"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("expected no further prompts, got %v", calls)
+	}
+}
+
+func TestEncryptedFileStore_WrongPassphrase(t *testing.T) {
+	fastKDF(t)
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	var calls []bool
+	store := NewEncryptedFileStore(path, fixedPassphrase("right", &calls))
+	if err := store.Set("shop", Credentials{ChannelAccessToken: "secret-token"}, ""); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	before, _ := os.ReadFile(path)
+
+	wrong := NewEncryptedFileStore(path, fixedPassphrase("wrong", &calls))
+	if _, err := wrong.Get("shop"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+	if err := wrong.Set("other", Credentials{ChannelAccessToken: "t"}, ""); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected Set to fail with ErrWrongPassphrase, got %v", err)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Error("expected the file to be unchanged")
+	}
+}
+
+func TestEncryptedFileStore_Tampered(t *testing.T) {
+	fastKDF(t)
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	var calls []bool
+	store := NewEncryptedFileStore(path, fixedPassphrase("right", &calls))
+	if err := store.Set("shop", Credentials{ChannelAccessToken: "secret-token"}, ""); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var env encryptedEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatal(err)
+	}
+	env.Ciphertext[0] ^= 0xff
+	data, _ = json.Marshal(env)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := NewEncryptedFileStore(path, fixedPassphrase("right", &calls))
+	if _, err := reopened.Get("shop"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestEncryptedFileStore_PassphraseErrors(t *testing.T) {
+	fastKDF(t)
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	failing := NewEncryptedFileStore(path, func(bool) (string, error) { return "", errors.New("no terminal") })
+	if err := failing.Set("shop", Credentials{ChannelAccessToken: "t"}, ""); err == nil || !strings.Contains(err.Error(), "no terminal") {
+		t.Fatalf("expected passphrase error, got %v", err)
+	}
+	empty := NewEncryptedFileStore(path, func(bool) (string, error) { return "", nil })
+	if err := empty.Set("shop", Credentials{ChannelAccessToken: "t"}, ""); err == nil {
+		t.Fatal("expected error for empty passphrase")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected no file to be written")
+	}
+}
+
+func TestMigrate_ToEncryptedFile(t *testing.T) {
+	fastKDF(t)
+	from, _ := newTestFileStore(t)
+	_ = from.Set("shop", Credentials{ChannelAccessToken: "secret-token"}, "My Shop")
+
+	var calls []bool
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	to := NewEncryptedFileStore(path, fixedPassphrase("right", &calls))
+	if _, err := Migrate(from, to); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	reopened := NewEncryptedFileStore(path, fixedPassphrase("right", &calls))
+	if creds, err := reopened.Get("shop"); err != nil || creds.ChannelAccessToken != "secret-token" {
+		t.Fatalf("Get = %v, %v", creds, err)
+	}
+}
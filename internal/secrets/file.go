@@ -0,0 +1,206 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/99designs/keyring"
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// FilePath returns the location of the plaintext credentials file.
+func FilePath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials.json"), nil
+}
+
+// NewFileStore creates a store that keeps credentials unencrypted in the
+// JSON file at path, created with permissions 0600. Use it only where no OS
+// keychain is available, such as headless servers.
+func NewFileStore(path string) *KeyringStore {
+	return &KeyringStore{ring: &fileKeyring{path: path}, kind: StoreFile}
+}
+
+// legacyFileDir is where earlier versions kept credentials, encrypted with
+// an empty password, when no OS keychain was available.
+const legacyFileDir = "~/.line-cli/credentials"
+
+// StoreLegacyFile is the kind of the store opened by NewLegacyFileStore.
+const StoreLegacyFile = "legacy-file"
+
+// NewLegacyFileStore opens the encrypted credentials directory used by
+// earlier versions without an OS keychain, so its accounts can be migrated.
+func NewLegacyFileStore() (*KeyringStore, error) {
+	ring, err := keyring.Open(keyring.Config{
+		ServiceName:      serviceName,
+		AllowedBackends:  []keyring.BackendType{keyring.FileBackend},
+		FileDir:          legacyFileDir,
+		FilePasswordFunc: keyring.FixedStringPrompt(""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open legacy credentials: %w", err)
+	}
+	return &KeyringStore{ring: ring, kind: StoreLegacyFile}, nil
+}
+
+func openDefaultFileStore() (*KeyringStore, error) {
+	path, err := FilePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials file path: %w", err)
+	}
+	return NewFileStore(path), nil
+}
+
+// fileKeyring is a keyring.Keyring kept in a single JSON object of key to
+// item data. Items are JSON themselves, so the file stays readable unless
+// codec encrypts it.
+type fileKeyring struct {
+	path  string
+	codec fileCodec // nil for plaintext
+	mu    sync.Mutex
+}
+
+// fileCodec converts a credentials file between its stored form and the
+// JSON object of items.
+type fileCodec interface {
+	decode(data []byte) ([]byte, error)
+	encode(plain []byte) ([]byte, error)
+}
+
+func (k *fileKeyring) load() (map[string]json.RawMessage, error) {
+	items := map[string]json.RawMessage{}
+	data, err := os.ReadFile(k.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return items, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	if len(data) == 0 {
+		return items, nil
+	}
+	if k.codec != nil {
+		if data, err = k.codec.decode(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file %s: %w", k.path, err)
+	}
+	return items, nil
+}
+
+func (k *fileKeyring) save(items map[string]json.RawMessage) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials file: %w", err)
+	}
+	if k.codec != nil {
+		if data, err = k.codec.encode(data); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(k.path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+
+	// Write to a temporary file first so a failed write never truncates
+	// the existing credentials
+	tmp, err := os.CreateTemp(filepath.Dir(k.path), ".credentials-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := tmp.Chmod(0600); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), k.path); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+func (k *fileKeyring) Get(key string) (keyring.Item, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return keyring.Item{}, err
+	}
+	data, ok := items[key]
+	if !ok {
+		return keyring.Item{}, keyring.ErrKeyNotFound
+	}
+	return keyring.Item{Key: key, Data: data}, nil
+}
+
+func (k *fileKeyring) GetMetadata(key string) (keyring.Metadata, error) {
+	if _, err := k.Get(key); err != nil {
+		return keyring.Metadata{}, err
+	}
+	return keyring.Metadata{Item: &keyring.Item{Key: key}}, nil
+}
+
+func (k *fileKeyring) Set(item keyring.Item) error {
+	if !json.Valid(item.Data) {
+		return fmt.Errorf("credentials file only stores JSON items")
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return err
+	}
+	items[item.Key] = item.Data
+	return k.save(items)
+}
+
+func (k *fileKeyring) Remove(key string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := items[key]; !ok {
+		return keyring.ErrKeyNotFound
+	}
+	delete(items, key)
+	return k.save(items)
+}
+
+func (k *fileKeyring) Keys() ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	items, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func newTestFileStore(t *testing.T) (*KeyringStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "line-cli", "credentials.json")
+	return NewFileStore(path), path
+}
+
+func TestFileStore(t *testing.T) {
+	store, path := newTestFileStore(t)
+
+	if accounts, err := store.List(); err != nil || len(accounts) != 0 {
+		t.Fatalf("expected no accounts before the file exists, got %v, %v", accounts, err)
+	}
+
+	if err := store.Set("Shop", Credentials{ChannelAccessToken: "token1"}, "My Shop"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("staging", Credentials{ChannelID: "1234", ChannelSecret: "secret"}, ""); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	creds, err := store.Get("shop")
+	if err != nil || creds.ChannelAccessToken != "token1" {
+		t.Fatalf("Get = %v, %v", creds, err)
+	}
+	if primary, _ := store.GetPrimary(); primary != "shop" {
+		t.Errorf("expected the first account to be primary, got %q", primary)
+	}
+	if err := store.SetPrimary("staging"); err != nil {
+		t.Fatalf("SetPrimary: %v", err)
+	}
+	if primary, _ := store.GetPrimary(); primary != "staging" {
+		t.Errorf("expected staging to be primary, got %q", primary)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"channel_access_token": "token1"`) {
+		t.Errorf("expected readable JSON, got %s", data)
+	}
+
+	if err := store.Delete("shop"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("shop"); err == nil {
+		t.Error("expected deleted account to be gone")
+	}
+	if err := store.Delete("shop"); err != nil {
+		t.Errorf("expected deleting twice to succeed, got %v", err)
+	}
+}
+
+func TestFileStore_CorruptFile(t *testing.T) {
+	store, path := newTestFileStore(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("shop", Credentials{ChannelAccessToken: "token1"}, ""); err == nil {
+		t.Fatal("expected error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "not json" {
+		t.Error("expected a corrupt file to be left alone")
+	}
+}
+
+func TestOpen_UnknownKind(t *testing.T) {
+	if _, err := Open("vault", nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	from, _ := newTestFileStore(t)
+	to, _ := newTestFileStore(t)
+	_ = from.Set("shop", Credentials{ChannelAccessToken: "token1"}, "My Shop")
+	_ = from.Set("staging", Credentials{ChannelAccessToken: "token2"}, "")
+	_ = from.SetPrimary("staging")
+
+	moved, err := Migrate(from, to)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if len(moved) != 2 {
+		t.Errorf("expected 2 moved accounts, got %v", moved)
+	}
+	if accounts, _ := from.List(); len(accounts) != 0 {
+		t.Errorf("expected source to be empty, got %v", accounts)
+	}
+
+	accounts, _ := to.List()
+	for _, acc := range accounts {
+		if acc.Name == "shop" && (acc.BotName != "My Shop" || acc.CreatedAt.IsZero()) {
+			t.Errorf("expected metadata to be kept, got %+v", acc)
+		}
+	}
+	if primary, _ := to.GetPrimary(); primary != "staging" {
+		t.Errorf("expected staging to stay primary, got %q", primary)
+	}
+}
+
+func TestMigrate_KeepsDestinationPrimary(t *testing.T) {
+	from, _ := newTestFileStore(t)
+	to, _ := newTestFileStore(t)
+	_ = from.Set("shop", Credentials{ChannelAccessToken: "token1"}, "")
+	_ = to.Set("prod", Credentials{ChannelAccessToken: "token2"}, "")
+
+	if _, err := Migrate(from, to); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	accounts, _ := to.List()
+	for _, acc := range accounts {
+		if acc.IsPrimary != (acc.Name == "prod") {
+			t.Errorf("expected only prod to be primary, got %+v", acc)
+		}
+	}
+}
+
+func TestMigrate_Conflict(t *testing.T) {
+	from, _ := newTestFileStore(t)
+	to, _ := newTestFileStore(t)
+	_ = from.Set("shop", Credentials{ChannelAccessToken: "new"}, "")
+	_ = from.Set("other", Credentials{ChannelAccessToken: "other"}, "")
+	_ = to.Set("shop", Credentials{ChannelAccessToken: "old"}, "")
+
+	if _, err := Migrate(from, to); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if creds, _ := to.Get("shop"); creds.ChannelAccessToken != "old" {
+		t.Error("expected destination account to be kept")
+	}
+	if accounts, _ := from.List(); len(accounts) != 2 {
+		t.Errorf("expected nothing to be moved, got %d left", len(accounts))
+	}
+}
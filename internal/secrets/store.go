@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -30,6 +31,12 @@ type Credentials struct {
 	ChannelSecret      string `json:"channel_secret,omitempty"`
 }
 
+// UsesChannelSecret reports whether the account has no stored token and
+// authenticates by issuing stateless tokens from its channel ID and secret.
+func (c Credentials) UsesChannelSecret() bool {
+	return c.ChannelAccessToken == "" && c.ChannelID != "" && c.ChannelSecret != ""
+}
+
 // AccountInfo represents a stored account
 type AccountInfo struct {
 	Name      string
@@ -48,29 +55,83 @@ type Store interface {
 	GetPrimary() (string, error)
 }
 
-// KeychainStore implements Store using the system keychain
-type KeychainStore struct {
+// Credential store kinds, as set with credential_store in the config file.
+const (
+	// StoreKeychain keeps credentials in the OS keychain, falling back to
+	// the plaintext file when none is available
+	StoreKeychain = "keychain"
+	// StoreFile keeps credentials in a plaintext file readable only by the
+	// current user
+	StoreFile = "file"
+	// StoreEncryptedFile keeps credentials in a file encrypted with a
+	// passphrase
+	StoreEncryptedFile = "encrypted-file"
+)
+
+// ErrNoKeychain is returned by NewKeychainStore when the system has no
+// supported OS keychain.
+var ErrNoKeychain = errors.New("no OS keychain available")
+
+// keychainBackends are the OS keychains credentials may be kept in.
+var keychainBackends = []keyring.BackendType{
+	keyring.WinCredBackend,
+	keyring.KeychainBackend,
+	keyring.SecretServiceBackend,
+	keyring.KWalletBackend,
+}
+
+// KeyringStore implements Store on top of a keyring: the OS keychain or a
+// plaintext file.
+type KeyringStore struct {
 	ring keyring.Keyring
+	kind string
 }
 
-// NewKeychainStore creates a new keychain-backed store
-func NewKeychainStore() (*KeychainStore, error) {
+// NewKeychainStore creates a store backed by the OS keychain (macOS
+// Keychain, Windows Credential Manager, or libsecret/KWallet on Linux).
+func NewKeychainStore() (*KeyringStore, error) {
 	ring, err := keyring.Open(keyring.Config{
 		ServiceName:              serviceName,
 		KeychainTrustApplication: true,
-		// Fallback to file-based if no keychain available
-		FileDir:          "~/.line-cli/credentials",
-		FilePasswordFunc: keyring.FixedStringPrompt(""),
+		AllowedBackends:          keychainBackends,
 	})
+	if errors.Is(err, keyring.ErrNoAvailImpl) {
+		return nil, ErrNoKeychain
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open keyring: %w", err)
 	}
 
-	return &KeychainStore{ring: ring}, nil
+	return &KeyringStore{ring: ring, kind: StoreKeychain}, nil
+}
+
+// Open returns the store of the given kind. The keychain store (the default
+// for an empty kind) falls back to the file store if the system has no OS
+// keychain. passphrase is only used by the encrypted file store.
+func Open(kind string, passphrase PassphraseFunc) (*KeyringStore, error) {
+	switch kind {
+	case "", StoreKeychain:
+		store, err := NewKeychainStore()
+		if errors.Is(err, ErrNoKeychain) {
+			return openDefaultFileStore()
+		}
+		return store, err
+	case StoreFile:
+		return openDefaultFileStore()
+	case StoreEncryptedFile:
+		return openDefaultEncryptedFileStore(passphrase)
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (use %s, %s, or %s)", kind, StoreKeychain, StoreFile, StoreEncryptedFile)
+	}
+}
+
+// Kind returns where the store keeps credentials: StoreKeychain or StoreFile.
+func (s *KeyringStore) Kind() string {
+	return s.kind
 }
 
 // Set stores credentials for an account
-func (s *KeychainStore) Set(name string, creds Credentials, botName string) error {
+func (s *KeyringStore) Set(name string, creds Credentials, botName string) error {
 	name = normalize(name)
 
 	// Check if this is the first account (auto-set as primary)
@@ -106,7 +167,7 @@ func (s *KeychainStore) Set(name string, creds Credentials, botName string) erro
 }
 
 // Get retrieves credentials for an account
-func (s *KeychainStore) Get(name string) (*Credentials, error) {
+func (s *KeyringStore) Get(name string) (*Credentials, error) {
 	name = normalize(name)
 	if name == "" {
 		return nil, fmt.Errorf("account name cannot be empty")
@@ -135,7 +196,7 @@ func (s *KeychainStore) Get(name string) (*Credentials, error) {
 }
 
 // Delete removes credentials for an account
-func (s *KeychainStore) Delete(name string) error {
+func (s *KeyringStore) Delete(name string) error {
 	name = normalize(name)
 
 	err := s.ring.Remove(tokenKey(name))
@@ -149,7 +210,7 @@ func (s *KeychainStore) Delete(name string) error {
 }
 
 // List returns all stored accounts
-func (s *KeychainStore) List() ([]AccountInfo, error) {
+func (s *KeyringStore) List() ([]AccountInfo, error) {
 	keys, err := s.ring.Keys()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list accounts: %w", err)
@@ -186,7 +247,7 @@ func (s *KeychainStore) List() ([]AccountInfo, error) {
 }
 
 // SetPrimary sets the specified account as the primary account
-func (s *KeychainStore) SetPrimary(name string) error {
+func (s *KeyringStore) SetPrimary(name string) error {
 	name = normalize(name)
 
 	// Get all keys
@@ -244,7 +305,7 @@ func (s *KeychainStore) SetPrimary(name string) error {
 // If no account is explicitly marked as primary, it falls back to returning
 // the first account in the list. This ensures single-account setups work
 // without requiring explicit primary designation.
-func (s *KeychainStore) GetPrimary() (string, error) {
+func (s *KeyringStore) GetPrimary() (string, error) {
 	accounts, err := s.List()
 	if err != nil {
 		return "", err
@@ -289,3 +350,64 @@ func parseTokenKey(key string) (string, bool) {
 func normalize(s string) string {
 	return strings.ToLower(strings.TrimSpace(s))
 }
+
+// Migrate moves every account from one store to another, keeping each
+// account's metadata. It refuses to overwrite accounts the destination
+// already has. If the destination already has a primary account, it stays
+// primary. Accounts are removed from the source only after all of them
+// have been copied. It returns the names of the moved accounts.
+func Migrate(from, to *KeyringStore) ([]string, error) {
+	keys, err := from.ring.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	existing, err := to.List()
+	if err != nil {
+		return nil, err
+	}
+	hasPrimary := false
+	taken := map[string]bool{}
+	for _, acc := range existing {
+		taken[acc.Name] = true
+		hasPrimary = hasPrimary || acc.IsPrimary
+	}
+
+	var items []keyring.Item
+	var names []string
+	for _, key := range keys {
+		name, ok := parseTokenKey(key)
+		if !ok {
+			continue
+		}
+		if taken[name] {
+			return nil, fmt.Errorf("account %s already exists in the %s store", name, to.kind)
+		}
+		item, err := from.ring.Get(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials for %s: %w", name, err)
+		}
+		if hasPrimary {
+			var stored storedCredentials
+			if err := json.Unmarshal(item.Data, &stored); err == nil && stored.IsPrimary {
+				stored.IsPrimary = false
+				if item.Data, err = json.Marshal(stored); err != nil {
+					return nil, fmt.Errorf("failed to marshal credentials for %s: %w", name, err)
+				}
+			}
+		}
+		items = append(items, keyring.Item{Key: key, Data: item.Data})
+		names = append(names, name)
+	}
+
+	for i, item := range items {
+		if err := to.ring.Set(item); err != nil {
+			return nil, fmt.Errorf("failed to store credentials for %s: %w", names[i], err)
+		}
+	}
+	for i, item := range items {
+		if err := from.ring.Remove(item.Key); err != nil && err != keyring.ErrKeyNotFound {
+			return names, fmt.Errorf("copied all accounts, but failed to remove %s from the %s store: %w", names[i], from.kind, err)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,167 @@
+// Package token builds the signed JWT assertions used to issue v2.1
+// channel access tokens.
+//
+// See https://developers.line.biz/en/docs/messaging-api/generate-json-web-token/
+package token
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// Audience is the required "aud" claim of an assertion.
+const Audience = "https://api.line.me/"
+
+// MaxTokenLifetime is the longest validity LINE allows for a v2.1 token.
+const MaxTokenLifetime = 30 * 24 * time.Hour
+
+// assertionLifetime is how long the assertion itself is accepted; LINE
+// rejects assertions valid for more than 30 minutes.
+const assertionLifetime = 30 * time.Minute
+
+// Assertion describes a JWT assertion for a channel.
+type Assertion struct {
+	// ChannelID is the channel ID, used as both issuer and subject
+	ChannelID string
+	// KeyID is the kid returned when the public key was registered
+	KeyID string
+	// TokenLifetime is how long the issued access token stays valid. Zero
+	// means MaxTokenLifetime.
+	TokenLifetime time.Duration
+}
+
+// Sign returns the assertion as a compact RS256 JWT issued at now.
+func (a Assertion) Sign(key *rsa.PrivateKey, now time.Time) (string, error) {
+	if a.ChannelID == "" {
+		return "", fmt.Errorf("channel ID is required")
+	}
+	if a.KeyID == "" {
+		return "", fmt.Errorf("key ID is required")
+	}
+	lifetime := a.TokenLifetime
+	if lifetime == 0 {
+		lifetime = MaxTokenLifetime
+	}
+	if lifetime < time.Second || lifetime > MaxTokenLifetime {
+		return "", fmt.Errorf("token lifetime must be between 1 second and 30 days")
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": a.KeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":       a.ChannelID,
+		"sub":       a.ChannelID,
+		"aud":       Audience,
+		"exp":       now.Add(assertionLifetime).Unix(),
+		"token_exp": int64(lifetime / time.Second),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign assertion: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// LoadPrivateKeyFile reads an RSA private key from a PEM or JWK file.
+func LoadPrivateKeyFile(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	return ParsePrivateKey(data)
+}
+
+// ParsePrivateKey parses an RSA private key in PEM (PKCS #1 or PKCS #8) or
+// JWK form, as produced by the LINE Developers key generation guide.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		return parseJWK([]byte(trimmed))
+	}
+
+	block, _ := pem.Decode([]byte(trimmed))
+	if block == nil {
+		return nil, fmt.Errorf("private key is neither PEM nor JWK")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key must be RSA")
+	}
+	return key, nil
+}
+
+// jwk holds the RSA private key members of a JSON Web Key (RFC 7518 6.3).
+type jwk struct {
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	D   string `json:"d"`
+	P   string `json:"p"`
+	Q   string `json:"q"`
+}
+
+func parseJWK(data []byte) (*rsa.PrivateKey, error) {
+	var k jwk
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("invalid JWK: %w", err)
+	}
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("JWK must have kty RSA, got %q", k.Kty)
+	}
+
+	var n, e, d, p, q *big.Int
+	for _, m := range []struct {
+		name string
+		val  string
+		dst  **big.Int
+	}{{"n", k.N, &n}, {"e", k.E, &e}, {"d", k.D, &d}, {"p", k.P, &p}, {"q", k.Q, &q}} {
+		if m.val == "" {
+			return nil, fmt.Errorf("JWK is missing %q; is this the private key?", m.name)
+		}
+		b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(m.val, "="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK member %q: %w", m.name, err)
+		}
+		*m.dst = new(big.Int).SetBytes(b)
+	}
+	if !e.IsInt64() {
+		return nil, fmt.Errorf("invalid JWK exponent")
+	}
+
+	key := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: n, E: int(e.Int64())},
+		D:         d,
+		Primes:    []*big.Int{p, q},
+	}
+	if err := key.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid JWK: %w", err)
+	}
+	key.Precompute()
+	return key, nil
+}
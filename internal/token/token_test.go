@@ -0,0 +1,158 @@
+package token
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestAssertionSign(t *testing.T) {
+	key := testKey(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	jwt, err := Assertion{ChannelID: "1234567890", KeyID: "kid-1", TokenLifetime: 24 * time.Hour}.Sign(key, now)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 JWT parts, got %d", len(parts))
+	}
+
+	var header map[string]string
+	decodePart(t, parts[0], &header)
+	if header["alg"] != "RS256" || header["typ"] != "JWT" || header["kid"] != "kid-1" {
+		t.Errorf("unexpected header: %v", header)
+	}
+
+	var claims map[string]any
+	decodePart(t, parts[1], &claims)
+	want := map[string]any{
+		"iss":       "1234567890",
+		"sub":       "1234567890",
+		"aud":       Audience,
+		"exp":       float64(now.Add(30 * time.Minute).Unix()),
+		"token_exp": float64(86400),
+	}
+	for k, v := range want {
+		if claims[k] != v {
+			t.Errorf("claim %s = %v, want %v", k, claims[k], v)
+		}
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+}
+
+func TestAssertionSign_Validation(t *testing.T) {
+	key := testKey(t)
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		a    Assertion
+	}{
+		{"missing channel", Assertion{KeyID: "k"}},
+		{"missing kid", Assertion{ChannelID: "1"}},
+		{"lifetime too long", Assertion{ChannelID: "1", KeyID: "k", TokenLifetime: 31 * 24 * time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.a.Sign(key, now); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	// Zero lifetime defaults to the maximum
+	jwt, err := Assertion{ChannelID: "1", KeyID: "k"}.Sign(key, now)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	var claims map[string]any
+	decodePart(t, strings.Split(jwt, ".")[1], &claims)
+	if claims["token_exp"] != float64(30*24*60*60) {
+		t.Errorf("token_exp = %v, want 30 days", claims["token_exp"])
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := testKey(t)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	jwkJSON, _ := json.Marshal(map[string]string{
+		"kty": "RSA", "alg": "RS256",
+		"n": b64(key.N), "e": b64(big.NewInt(int64(key.E))), "d": b64(key.D),
+		"p": b64(key.Primes[0]), "q": b64(key.Primes[1]),
+	})
+
+	inputs := map[string][]byte{
+		"pkcs1 pem": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		"pkcs8 pem": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		"jwk":       jwkJSON,
+	}
+	for name, data := range inputs {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePrivateKey(data)
+			if err != nil {
+				t.Fatalf("ParsePrivateKey() error = %v", err)
+			}
+			if !got.Equal(key) {
+				t.Error("parsed key does not match")
+			}
+		})
+	}
+
+	publicJWK, _ := json.Marshal(map[string]string{"kty": "RSA", "n": b64(key.N), "e": "AQAB"})
+	for name, data := range map[string][]byte{
+		"garbage":    []byte("not a key"),
+		"public jwk": publicJWK,
+		"ec jwk":     []byte(`{"kty":"EC"}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParsePrivateKey(data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func decodePart(t *testing.T, part string, v any) {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,252 @@
+// Package tokenhealth records per-account API authentication outcomes so
+// that revoked or failing channel access tokens can be flagged before they
+// break an important send.
+package tokenhealth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/line-official-cli/internal/config"
+)
+
+// Record tracks authentication outcomes for one account's current token.
+type Record struct {
+	// TokenID is a fingerprint of the token the record applies to; the record
+	// is reset when the account's token changes
+	TokenID string `json:"tokenId"`
+	// FirstSeen is when this token was first used
+	FirstSeen     time.Time `json:"firstSeen"`
+	LastSuccess   time.Time `json:"lastSuccess,omitzero"`
+	LastFailure   time.Time `json:"lastFailure,omitzero"`
+	FailureDay    string    `json:"failureDay,omitempty"`
+	FailuresToday int       `json:"failuresToday,omitempty"`
+	// ExpiresAt is when the token expires, as LINE reported it at
+	// CheckedAt; zero for tokens that do not expire
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+	CheckedAt time.Time `json:"checkedAt,omitzero"`
+}
+
+// ExpiryWindow is how close to its expiry a token is warned about.
+const ExpiryWindow = 24 * time.Hour
+
+// CheckInterval is how often a token's expiry is checked with LINE.
+const CheckInterval = 24 * time.Hour
+
+// mu serializes read-modify-write cycles on the health file within a process.
+var mu sync.Mutex
+
+// Path returns the location of the token health file.
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "token-health.json"), nil
+}
+
+// TokenID returns a non-reversible fingerprint of a token.
+func TokenID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Load returns the records for all accounts. A missing file yields an
+// empty map.
+func Load() (map[string]*Record, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]*Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token health: %w", err)
+	}
+	records := map[string]*Record{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid token health file: %w", err)
+	}
+	return records, nil
+}
+
+func save(records map[string]*Record) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Get returns the record for account if it applies to tokenID.
+func Get(account, tokenID string) (*Record, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	records, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	rec := records[account]
+	if rec == nil || rec.TokenID != tokenID {
+		return nil, nil
+	}
+	return rec, nil
+}
+
+// Forget removes the record for account and reports whether one existed.
+func Forget(account string) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	records, err := Load()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := records[account]; !ok {
+		return false, nil
+	}
+	delete(records, account)
+	return true, save(records)
+}
+
+// Observe records the outcome of an authenticated request at now.
+func Observe(account, tokenID string, success bool, now time.Time) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	records, err := Load()
+	if err != nil {
+		return err
+	}
+
+	rec := records[account]
+	if rec == nil || rec.TokenID != tokenID {
+		rec = &Record{TokenID: tokenID, FirstSeen: now}
+		records[account] = rec
+	}
+
+	if success {
+		rec.LastSuccess = now
+	} else {
+		today := now.Format("2006-01-02")
+		if rec.FailureDay != today {
+			rec.FailureDay = today
+			rec.FailuresToday = 0
+		}
+		rec.FailuresToday++
+		rec.LastFailure = now
+	}
+	return save(records)
+}
+
+// RecordExpiry records when the account's token expires, as checked at now.
+func RecordExpiry(account, tokenID string, expiresAt, now time.Time) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	records, err := Load()
+	if err != nil {
+		return err
+	}
+
+	rec := records[account]
+	if rec == nil || rec.TokenID != tokenID {
+		rec = &Record{TokenID: tokenID, FirstSeen: now}
+		records[account] = rec
+	}
+	rec.ExpiresAt, rec.CheckedAt = expiresAt, now
+	return save(records)
+}
+
+// NeedsExpiryCheck reports whether the token's expiry has not been checked
+// within CheckInterval.
+func NeedsExpiryCheck(rec *Record, now time.Time) bool {
+	return rec == nil || now.Sub(rec.CheckedAt) >= CheckInterval
+}
+
+// ExpiryWarning returns a human-readable warning for account if its token
+// expires within ExpiryWindow or has expired, or else an empty string.
+func ExpiryWarning(account string, rec *Record, now time.Time) string {
+	if rec == nil || rec.ExpiresAt.IsZero() {
+		return ""
+	}
+	left := rec.ExpiresAt.Sub(now)
+	switch {
+	case left <= 0:
+		return fmt.Sprintf("token for '%s' expired %s; issue a new one and run: line auth login", account, ago(-left))
+	case left < ExpiryWindow:
+		h := int(left.Hours())
+		in := "in less than an hour"
+		if h > 0 {
+			in = fmt.Sprintf("in %d %s", h, plural(h, "hour", "hours"))
+		}
+		return fmt.Sprintf("token for '%s' expires %s (%s)", account, in, rec.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	}
+	return ""
+}
+
+// Warning returns a human-readable warning for account, or an empty string
+// if the token looks healthy. A token is flagged when it has failed today
+// or when its most recent use failed.
+func Warning(account string, rec *Record, now time.Time) string {
+	if rec == nil {
+		return ""
+	}
+
+	failedToday := rec.FailureDay == now.Format("2006-01-02") && rec.FailuresToday > 0
+	lastFailed := !rec.LastFailure.IsZero() && rec.LastFailure.After(rec.LastSuccess)
+	if !failedToday && !lastFailed {
+		return ""
+	}
+
+	msg := fmt.Sprintf("token for '%s'", account)
+	if failedToday {
+		msg += fmt.Sprintf(" has failed %d %s today", rec.FailuresToday, plural(rec.FailuresToday, "time", "times"))
+	} else {
+		msg += fmt.Sprintf(" failed on its last use %s", ago(now.Sub(rec.LastFailure)))
+	}
+	if rec.LastSuccess.IsZero() {
+		msg += ", no successful request recorded"
+	} else {
+		msg += fmt.Sprintf(", last success %s", ago(now.Sub(rec.LastSuccess)))
+	}
+	return msg
+}
+
+// ago formats a duration as a coarse "N units ago" string.
+func ago(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return "less than an hour ago"
+	case d < 24*time.Hour:
+		h := int(d.Hours())
+		return fmt.Sprintf("%d %s ago", h, plural(h, "hour", "hours"))
+	default:
+		days := int(d.Hours() / 24)
+		return fmt.Sprintf("%d %s ago", days, plural(days, "day", "days"))
+	}
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,108 @@
+package tokenhealth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObserveAndWarning(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	tokenID := TokenID("token-a")
+	start := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	if err := Observe("prod", tokenID, true, start); err != nil {
+		t.Fatalf("Observe() error = %v", err)
+	}
+
+	now := start.Add(26 * 24 * time.Hour)
+	for i := 0; i < 3; i++ {
+		if err := Observe("prod", tokenID, false, now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("Observe() error = %v", err)
+		}
+	}
+
+	rec, err := Get("prod", tokenID)
+	if err != nil || rec == nil {
+		t.Fatalf("Get() = %v, %v", rec, err)
+	}
+	want := "token for 'prod' has failed 3 times today, last success 26 days ago"
+	if got := Warning("prod", rec, now.Add(time.Hour)); got != want {
+		t.Errorf("Warning() = %q, want %q", got, want)
+	}
+
+	// The next day the count resets but the last use still failed
+	tomorrow := now.Add(24 * time.Hour)
+	want = "token for 'prod' failed on its last use 23 hours ago, last success 27 days ago"
+	if got := Warning("prod", rec, tomorrow); got != want {
+		t.Errorf("Warning() = %q, want %q", got, want)
+	}
+
+	// A success clears the warning
+	if err := Observe("prod", tokenID, true, tomorrow); err != nil {
+		t.Fatal(err)
+	}
+	rec, _ = Get("prod", tokenID)
+	if got := Warning("prod", rec, tomorrow); got != "" {
+		t.Errorf("expected no warning after success, got %q", got)
+	}
+}
+
+func TestObserve_ResetsOnTokenChange(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+
+	if err := Observe("prod", TokenID("old"), false, now); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _ := Get("prod", TokenID("new")); rec != nil {
+		t.Errorf("expected no record for new token, got %+v", rec)
+	}
+
+	if err := Observe("prod", TokenID("new"), true, now); err != nil {
+		t.Fatal(err)
+	}
+	rec, _ := Get("prod", TokenID("new"))
+	if rec == nil || rec.FailuresToday != 0 || rec.LastSuccess.IsZero() {
+		t.Errorf("expected fresh record for new token, got %+v", rec)
+	}
+}
+
+func TestRecordExpiryAndWarning(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	tokenID := TokenID("token-a")
+	now := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	if rec, _ := Get("prod", tokenID); !NeedsExpiryCheck(rec, now) {
+		t.Error("expected an unchecked token to need a check")
+	}
+	if err := RecordExpiry("prod", tokenID, now.Add(5*time.Hour+30*time.Minute), now); err != nil {
+		t.Fatalf("RecordExpiry() error = %v", err)
+	}
+	rec, err := Get("prod", tokenID)
+	if err != nil || rec == nil {
+		t.Fatalf("Get() = %v, %v", rec, err)
+	}
+	if NeedsExpiryCheck(rec, now.Add(time.Hour)) || !NeedsExpiryCheck(rec, now.Add(CheckInterval)) {
+		t.Error("expected the expiry to be checked once per interval")
+	}
+
+	if got := ExpiryWarning("prod", rec, now); !strings.HasPrefix(got, "token for 'prod' expires in 5 hours (") {
+		t.Errorf("ExpiryWarning() = %q", got)
+	}
+	if got := ExpiryWarning("prod", rec, now.Add(-2*ExpiryWindow)); got != "" {
+		t.Errorf("expected no warning a day before, got %q", got)
+	}
+	if got := ExpiryWarning("prod", rec, now.Add(30*time.Hour)); got != "token for 'prod' expired 1 day ago; issue a new one and run: line auth login" {
+		t.Errorf("ExpiryWarning() = %q", got)
+	}
+
+	// Tokens that do not expire are never warned about
+	if err := RecordExpiry("prod", tokenID, time.Time{}, now); err != nil {
+		t.Fatal(err)
+	}
+	rec, _ = Get("prod", tokenID)
+	if got := ExpiryWarning("prod", rec, now); got != "" {
+		t.Errorf("expected no warning for a long-lived token, got %q", got)
+	}
+}
@@ -0,0 +1,268 @@
+// Package tui draws a full-screen terminal dashboard of titled panes that
+// refresh periodically. It needs nothing beyond ANSI escapes and
+// golang.org/x/term, so it works in any terminal the CLI itself runs in.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// Pane is one box of the dashboard.
+type Pane struct {
+	Title string
+	Lines []string
+	// Err replaces Lines when the pane could not be fetched
+	Err error
+}
+
+// Source fetches the panes for one refresh. It should not fail as a whole:
+// a section that cannot be fetched is reported in its pane's Err.
+type Source func(ctx context.Context) []Pane
+
+// Options configure Run.
+type Options struct {
+	Title    string
+	Interval time.Duration
+	// In is the terminal read for key presses; Out is the terminal drawn on
+	In  *os.File
+	Out *os.File
+	// Now is the clock shown in the header (default time.Now)
+	Now func() time.Time
+}
+
+// Escape sequences used to take over and restore the terminal.
+const (
+	enterAltScreen = "\033[?1049h\033[?25l"
+	exitAltScreen  = "\033[?25h\033[?1049l"
+	clearScreen    = "\033[H\033[2J"
+)
+
+// minPaneWidth is the narrowest a pane gets before the layout falls back
+// to a single column.
+const minPaneWidth = 36
+
+// Run shows the dashboard until q, Esc, or Ctrl+C is pressed or ctx is
+// done. Panes refresh every Interval, and r refreshes them at once.
+func Run(ctx context.Context, opts Options, source Source) error {
+	if opts.Interval <= 0 {
+		return fmt.Errorf("refresh interval must be positive")
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	inFd, outFd := int(opts.In.Fd()), int(opts.Out.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return fmt.Errorf("the dashboard needs an interactive terminal")
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer func() { _ = term.Restore(inFd, state) }()
+	_, _ = io.WriteString(opts.Out, enterAltScreen)
+	defer func() { _, _ = io.WriteString(opts.Out, exitAltScreen) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	keys := make(chan byte)
+	go readKeys(ctx, opts.In, keys)
+
+	var (
+		mu        sync.Mutex
+		panes     []Pane
+		updated   time.Time
+		loading   bool
+		refreshed = make(chan struct{}, 1)
+	)
+	refresh := func() {
+		mu.Lock()
+		if loading {
+			mu.Unlock()
+			return
+		}
+		loading = true
+		mu.Unlock()
+		go func() {
+			p := source(ctx)
+			mu.Lock()
+			panes, updated, loading = p, opts.Now(), false
+			mu.Unlock()
+			select {
+			case refreshed <- struct{}{}:
+			default:
+			}
+		}()
+	}
+	draw := func() {
+		width, height, err := term.GetSize(outFd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		mu.Lock()
+		status := "Loading..."
+		if !updated.IsZero() {
+			status = "Updated " + updated.Format("15:04:05")
+		}
+		if loading && !updated.IsZero() {
+			status += ", refreshing..."
+		}
+		status += fmt.Sprintf("   every %s   r refresh   q quit", opts.Interval)
+		screen := Render(opts.Title, panes, width, height, status)
+		mu.Unlock()
+		// Raw mode turns off the translation of \n to \r\n
+		_, _ = io.WriteString(opts.Out, clearScreen+strings.ReplaceAll(screen, "\n", "\r\n"))
+	}
+
+	refresh()
+	draw()
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	// Redraw now and then to pick up terminal resizes
+	redraw := time.NewTicker(time.Second)
+	defer redraw.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case k := <-keys:
+			switch k {
+			case 'q', 'Q', 0x03, 0x1b: // Ctrl+C, Esc
+				return nil
+			case 'r', 'R':
+				refresh()
+				draw()
+			}
+		case <-ticker.C:
+			refresh()
+		case <-refreshed:
+			draw()
+		case <-redraw.C:
+			draw()
+		}
+	}
+}
+
+// readKeys sends each byte read from in to keys until ctx is done.
+func readKeys(ctx context.Context, in io.Reader, keys chan<- byte) {
+	buf := make([]byte, 16)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, b := range buf[:n] {
+			select {
+			case keys <- b:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Render lays out panes in a grid of boxes for a terminal of the given
+// size: two columns when there is room, otherwise one. title heads the
+// screen and status is shown on the last line. Lines that do not fit are
+// truncated.
+func Render(title string, panes []Pane, width, height int, status string) string {
+	var b strings.Builder
+	b.WriteString(fit(title, width) + "\n\n")
+
+	cols := 2
+	if width < 2*minPaneWidth+1 {
+		cols = 1
+	}
+	paneWidth := width / cols
+	if cols == 2 {
+		paneWidth = (width - 1) / 2
+	}
+
+	lines := 2
+	for i := 0; i < len(panes); i += cols {
+		row := panes[i:min(i+cols, len(panes))]
+		boxes := make([][]string, len(row))
+		rowHeight := 0
+		for k, p := range row {
+			boxes[k] = box(p, paneWidth)
+			rowHeight = max(rowHeight, len(boxes[k]))
+		}
+		for l := 0; l < rowHeight; l++ {
+			if lines >= height-2 {
+				break
+			}
+			parts := make([]string, len(row))
+			for k := range row {
+				if l < len(boxes[k]) {
+					parts[k] = boxes[k][l]
+				} else {
+					parts[k] = strings.Repeat(" ", paneWidth)
+				}
+			}
+			b.WriteString(strings.TrimRight(strings.Join(parts, " "), " ") + "\n")
+			lines++
+		}
+	}
+
+	for ; lines < height-1; lines++ {
+		b.WriteString("\n")
+	}
+	b.WriteString(fit(status, width))
+	return b.String()
+}
+
+// box draws a pane as the lines of a box exactly width runes wide.
+func box(p Pane, width int) []string {
+	inner := width - 4
+	if inner < 1 {
+		inner = 1
+	}
+	top := "┌─ " + fit(p.Title, inner-1) + " "
+	top += strings.Repeat("─", max(0, width-1-utf8.RuneCountInString(top))) + "┐"
+
+	body := p.Lines
+	if p.Err != nil {
+		body = []string{"Error: " + p.Err.Error()}
+	}
+	if len(body) == 0 {
+		body = []string{""}
+	}
+	out := []string{top}
+	for _, l := range body {
+		out = append(out, "│ "+pad(fit(l, inner), inner)+" │")
+	}
+	return append(out, "└"+strings.Repeat("─", max(0, width-2))+"┘")
+}
+
+// fit truncates s to width runes, marking the cut with an ellipsis.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// pad fills s with spaces to width runes.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+}
+
+// Bar draws a usage bar width cells wide, filled for fraction (0 to 1).
+func Bar(fraction float64, width int) string {
+	fraction = max(0, min(1, fraction))
+	filled := int(fraction*float64(width) + 0.5)
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRender_TwoColumns(t *testing.T) {
+	panes := []Pane{
+		{Title: "Quota", Lines: []string{"Used 10 of 100"}},
+		{Title: "Followers", Lines: []string{"Followers: 5", "Blocks: 1"}},
+		{Title: "Webhook", Err: errors.New("unauthorized")},
+	}
+	screen := Render("My Bot", panes, 80, 20, "q quit")
+	lines := strings.Split(screen, "\n")
+
+	if len(lines) != 20 {
+		t.Fatalf("expected the screen to fill 20 lines, got %d:\n%s", len(lines), screen)
+	}
+	if lines[0] != "My Bot" || lines[19] != "q quit" {
+		t.Errorf("unexpected title or status line:\n%s", screen)
+	}
+	if !strings.HasPrefix(lines[2], "┌─ Quota ") || !strings.Contains(lines[2], "┐ ┌─ Followers ") {
+		t.Errorf("expected two panes side by side, got %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[4], "└") || !strings.Contains(lines[4], "│ Blocks: 1") {
+		t.Errorf("unexpected row %q", lines[4])
+	}
+	// The shorter pane is padded so the second column stays aligned
+	if !strings.HasPrefix(lines[5], strings.Repeat(" ", 40)+"└") {
+		t.Errorf("unexpected row %q", lines[5])
+	}
+	if !strings.Contains(screen, "│ Error: unauthorized") {
+		t.Errorf("expected pane error, got:\n%s", screen)
+	}
+	for _, l := range lines {
+		if n := utf8.RuneCountInString(l); n > 80 {
+			t.Errorf("line is %d runes wide: %q", n, l)
+		}
+	}
+}
+
+func TestRender_NarrowTerminal(t *testing.T) {
+	panes := []Pane{
+		{Title: "Quota", Lines: []string{strings.Repeat("x", 100)}},
+		{Title: "Followers", Lines: []string{"5"}},
+	}
+	screen := Render("My Bot", panes, 40, 12, "status")
+	lines := strings.Split(screen, "\n")
+
+	if !strings.HasPrefix(lines[2], "┌─ Quota") || !strings.HasPrefix(lines[5], "┌─ Followers") {
+		t.Errorf("expected panes stacked in one column:\n%s", screen)
+	}
+	if !strings.Contains(lines[3], "…") {
+		t.Errorf("expected long line to be truncated, got %q", lines[3])
+	}
+	for _, l := range lines[2:8] {
+		if n := utf8.RuneCountInString(l); n != 40 {
+			t.Errorf("expected box lines 40 runes wide, got %d: %q", n, l)
+		}
+	}
+}
+
+func TestRender_ClipsToHeight(t *testing.T) {
+	panes := []Pane{{Title: "Long", Lines: make([]string, 50)}}
+	screen := Render("Title", panes, 80, 10, "status")
+	if n := strings.Count(screen, "\n") + 1; n != 10 {
+		t.Errorf("expected 10 lines, got %d", n)
+	}
+	if !strings.HasSuffix(screen, "\nstatus") {
+		t.Errorf("expected status on the last line, got %q", screen)
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		want     string
+	}{
+		{0, "[░░░░]"},
+		{0.5, "[██░░]"},
+		{1, "[████]"},
+		{2, "[████]"},
+		{-1, "[░░░░]"},
+	}
+	for _, tt := range tests {
+		if got := Bar(tt.fraction, 4); got != tt.want {
+			t.Errorf("Bar(%v) = %q, want %q", tt.fraction, got, tt.want)
+		}
+	}
+}
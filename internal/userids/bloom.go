@@ -0,0 +1,54 @@
+package userids
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// falsePositiveRate is the chance that a new ID is mistaken for a repeat.
+// At this rate a ten-million-line file is expected to lose about one ID,
+// while the filter needs only about 4 bytes per ID.
+const falsePositiveRate = 1e-7
+
+// bloomFilter is a fixed-size set that may report an ID it has not seen as
+// present, at about falsePositiveRate, but never misses one it has.
+type bloomFilter struct {
+	bits  []uint64
+	m     uint64
+	k     uint64
+	seed1 maphash.Seed
+	seed2 maphash.Seed
+}
+
+// newBloomFilter returns a filter sized for n IDs.
+func newBloomFilter(n int) *bloomFilter {
+	if n < 1024 {
+		n = 1024
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	return &bloomFilter{
+		bits:  make([]uint64, (m+63)/64),
+		m:     m,
+		k:     k,
+		seed1: maphash.MakeSeed(),
+		seed2: maphash.MakeSeed(),
+	}
+}
+
+// addIfAbsent adds s and reports whether it was (probably) already present.
+func (b *bloomFilter) addIfAbsent(s string) bool {
+	// Double hashing: the i-th probe is h1 + i*h2
+	h1 := maphash.String(b.seed1, s)
+	h2 := maphash.String(b.seed2, s) | 1
+	present := true
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			present = false
+			b.bits[word] |= mask
+		}
+	}
+	return present
+}
@@ -0,0 +1,62 @@
+package userids
+
+import "encoding/hex"
+
+// Set is an exact set of user IDs. Valid IDs are kept as their 16 bytes
+// rather than as strings, so a set of ten million IDs fits in a few hundred
+// megabytes where the bloom filter behind Reader could drop a few of them.
+type Set struct {
+	ids   map[[16]byte]struct{}
+	other map[string]struct{}
+}
+
+// NewSet returns an empty Set.
+func NewSet() *Set {
+	return &Set{ids: map[[16]byte]struct{}{}}
+}
+
+// key returns the compact form of a valid ID.
+func key(id string) ([16]byte, bool) {
+	var k [16]byte
+	if len(id) != 33 || id[0] != 'U' {
+		return k, false
+	}
+	if _, err := hex.Decode(k[:], []byte(id[1:])); err != nil {
+		return k, false
+	}
+	return k, true
+}
+
+// Add adds id and reports whether it was not already present.
+func (s *Set) Add(id string) bool {
+	if k, ok := key(id); ok {
+		if _, ok := s.ids[k]; ok {
+			return false
+		}
+		s.ids[k] = struct{}{}
+		return true
+	}
+	if s.other == nil {
+		s.other = map[string]struct{}{}
+	}
+	if _, ok := s.other[id]; ok {
+		return false
+	}
+	s.other[id] = struct{}{}
+	return true
+}
+
+// Has reports whether id is in the set.
+func (s *Set) Has(id string) bool {
+	if k, ok := key(id); ok {
+		_, ok := s.ids[k]
+		return ok
+	}
+	_, ok := s.other[id]
+	return ok
+}
+
+// Len returns the number of IDs in the set.
+func (s *Set) Len() int {
+	return len(s.ids) + len(s.other)
+}
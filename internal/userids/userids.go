@@ -0,0 +1,302 @@
+// Package userids streams LINE user IDs from files too large to load at
+// once, such as multi-million-line data warehouse exports.
+//
+// Files hold one user ID per line. Blank lines and lines starting with #
+// are skipped. Every other line must be a valid user ID (U followed by 32
+// hex digits) after Normalize. An invalid line fails the read, reporting
+// the line numbers of every invalid line in the file, unless invalid lines
+// are skipped. Repeated IDs are detected with a bloom filter, so memory
+// stays bounded however large the file is.
+package userids
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrTooManyUsers is returned when a file holds more unique IDs than the
+// configured maximum.
+var ErrTooManyUsers = errors.New("too many user IDs")
+
+// ErrNoUsers is returned by ReadFile when a file holds no user IDs.
+var ErrNoUsers = errors.New("file contains no user IDs")
+
+// userIDPattern matches a LINE user ID.
+var userIDPattern = regexp.MustCompile(`^U[0-9a-f]{32}$`)
+
+// defaultExpectedUsers sizes the duplicate filter when the input size is
+// unknown.
+const defaultExpectedUsers = 1_000_000
+
+// bytesPerLine is the size of a user ID line including the newline, used to
+// estimate how many IDs a file holds from its size.
+const bytesPerLine = 34
+
+// Options configures a Reader.
+type Options struct {
+	// MaxUsers makes reading fail with ErrTooManyUsers once more than this
+	// many unique IDs have been read. Zero means no limit.
+	MaxUsers int
+	// ExpectedUsers sizes the duplicate filter. Zero means a default of one
+	// million, or MaxUsers if that is smaller.
+	ExpectedUsers int
+	// SkipInvalid skips lines that are not user IDs, counting them in
+	// Stats, instead of failing with an *InvalidError.
+	SkipInvalid bool
+}
+
+// Stats counts what a Reader has read so far.
+type Stats struct {
+	// Unique is the number of distinct IDs returned
+	Unique int `json:"unique"`
+	// Duplicates is the number of IDs skipped because they were repeated
+	Duplicates int `json:"duplicates"`
+	// Invalid is the number of lines that were not user IDs, and
+	// InvalidLines the first MaxReportedInvalid of them
+	Invalid      int           `json:"invalid,omitempty"`
+	InvalidLines []InvalidLine `json:"invalidLines,omitempty"`
+}
+
+// MaxReportedInvalid is how many invalid lines are kept for reporting.
+const MaxReportedInvalid = 20
+
+// InvalidLine is a line that is not a user ID.
+type InvalidLine struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// InvalidError reports the invalid lines of an input read without
+// SkipInvalid. Reading stops at the first invalid line, but the rest of
+// the input is scanned so every invalid line is counted.
+type InvalidError struct {
+	// Unit names a position in the input: "line" for files and "entry"
+	// for lists
+	Unit string
+	// Lines are the first MaxReportedInvalid invalid lines
+	Lines []InvalidLine
+	// Count is the number of invalid lines
+	Count int
+}
+
+func (e *InvalidError) Error() string {
+	first := e.Lines[0]
+	msg := fmt.Sprintf("%s %d: invalid user ID %q", e.Unit, first.Line, first.Text)
+	if e.Count == 1 {
+		return msg
+	}
+	numbers := make([]string, len(e.Lines))
+	for i, l := range e.Lines {
+		numbers[i] = strconv.Itoa(l.Line)
+	}
+	list := strings.Join(numbers, ", ")
+	if e.Count > len(e.Lines) {
+		list += ", ..."
+	}
+	units := e.Unit + "s"
+	if e.Unit == "entry" {
+		units = "entries"
+	}
+	return fmt.Sprintf("%s (%d invalid user IDs in total, %s %s)", msg, e.Count, units, list)
+}
+
+// Normalize cleans up a user ID as exported by spreadsheets and databases:
+// it trims whitespace, a byte order mark, and surrounding quotes, and
+// lowercases the hex digits. It does not check the result is valid.
+func Normalize(s string) string {
+	s = strings.TrimSpace(strings.TrimPrefix(s, "\uFEFF"))
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	if len(s) == 33 && (s[0] == 'U' || s[0] == 'u') {
+		return "U" + strings.ToLower(s[1:])
+	}
+	return s
+}
+
+// Valid reports whether id is a LINE user ID.
+func Valid(id string) bool {
+	return userIDPattern.MatchString(id)
+}
+
+// Reader returns the unique, valid user IDs of a stream one at a time.
+// Its use mirrors bufio.Scanner:
+//
+//	r := userids.NewReader(f, userids.Options{})
+//	for r.Next() {
+//		use(r.ID())
+//	}
+//	if err := r.Err(); err != nil { ... }
+type Reader struct {
+	scanner     *bufio.Scanner
+	seen        *bloomFilter
+	max         int
+	skipInvalid bool
+	unit        string
+	line        int
+	id          string
+	err         error
+	stats       Stats
+}
+
+// NewReader returns a Reader that reads from r.
+func NewReader(r io.Reader, opts Options) *Reader {
+	expected := opts.ExpectedUsers
+	if expected <= 0 {
+		expected = defaultExpectedUsers
+	}
+	if opts.MaxUsers > 0 && opts.MaxUsers < expected {
+		expected = opts.MaxUsers
+	}
+	return &Reader{
+		scanner:     bufio.NewScanner(r),
+		seen:        newBloomFilter(expected),
+		max:         opts.MaxUsers,
+		skipInvalid: opts.SkipInvalid,
+		unit:        "line",
+	}
+}
+
+// Next advances to the next unique user ID. It returns false at the end of
+// the input or on the first invalid line, after which Err reports why.
+func (r *Reader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		id := Normalize(text)
+		if !Valid(id) {
+			r.addInvalid(text)
+			if r.skipInvalid {
+				continue
+			}
+			r.err = r.invalidError()
+			return false
+		}
+		if r.seen.addIfAbsent(id) {
+			r.stats.Duplicates++
+			continue
+		}
+		if r.max > 0 && r.stats.Unique >= r.max {
+			r.err = fmt.Errorf("%w: more than %d", ErrTooManyUsers, r.max)
+			return false
+		}
+		r.stats.Unique++
+		r.id = id
+		return true
+	}
+	if err := r.scanner.Err(); err != nil {
+		r.err = fmt.Errorf("line %d: %w", r.line+1, err)
+	}
+	return false
+}
+
+func (r *Reader) addInvalid(text string) {
+	r.stats.Invalid++
+	if len(r.stats.InvalidLines) < MaxReportedInvalid {
+		if len(text) > 40 {
+			text = text[:40] + "..."
+		}
+		r.stats.InvalidLines = append(r.stats.InvalidLines, InvalidLine{Line: r.line, Text: text})
+	}
+}
+
+// invalidError scans the rest of the input for invalid lines and returns
+// an error listing them.
+func (r *Reader) invalidError() error {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || Valid(Normalize(text)) {
+			continue
+		}
+		r.addInvalid(text)
+	}
+	return &InvalidError{Unit: r.unit, Lines: r.stats.InvalidLines, Count: r.stats.Invalid}
+}
+
+// ID returns the user ID found by the last call to Next.
+func (r *Reader) ID() string {
+	return r.id
+}
+
+// Err returns the first error encountered, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Stats returns the counts so far.
+func (r *Reader) Stats() Stats {
+	return r.stats
+}
+
+// ReadFile streams the unique user IDs of path to fn in batches of at most
+// batchSize, so only one batch is held in memory at a time. A file without
+// any user IDs is an error.
+func ReadFile(path string, opts Options, batchSize int, fn func(batch []string) error) (Stats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if info, err := f.Stat(); err == nil && opts.ExpectedUsers == 0 {
+		opts.ExpectedUsers = int(info.Size()/bytesPerLine) + 1
+	}
+	return Read(f, path, opts, batchSize, fn)
+}
+
+// Read is ReadFile for a stream, such as stdin, whose size is not known up
+// front. name identifies the stream in errors.
+func Read(in io.Reader, name string, opts Options, batchSize int, fn func(batch []string) error) (Stats, error) {
+	r := NewReader(in, opts)
+	batch := make([]string, 0, batchSize)
+	for r.Next() {
+		batch = append(batch, r.ID())
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return r.Stats(), err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := r.Err(); err != nil {
+		return r.Stats(), fmt.Errorf("%s: %w", name, err)
+	}
+	if len(batch) > 0 {
+		if err := fn(batch); err != nil {
+			return r.Stats(), err
+		}
+	}
+	if r.Stats().Unique == 0 {
+		return r.Stats(), ErrNoUsers
+	}
+	return r.Stats(), nil
+}
+
+// Clean normalizes, validates, and de-duplicates a list of user IDs, such
+// as one given on the command line. Invalid entries are reported by their
+// 1-based position.
+func Clean(ids []string, opts Options) ([]string, Stats, error) {
+	if opts.ExpectedUsers == 0 {
+		opts.ExpectedUsers = len(ids)
+	}
+	r := NewReader(strings.NewReader(strings.Join(ids, "\n")), opts)
+	r.unit = "entry"
+	cleaned := make([]string, 0, len(ids))
+	for r.Next() {
+		cleaned = append(cleaned, r.ID())
+	}
+	return cleaned, r.Stats(), r.Err()
+}
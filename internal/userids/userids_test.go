@@ -0,0 +1,251 @@
+package userids
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testID(n int) string {
+	return fmt.Sprintf("U%032x", n)
+}
+
+func TestReader(t *testing.T) {
+	input := strings.Join([]string{
+		"# exported 2026-03-01",
+		testID(1),
+		"",
+		"  " + testID(2) + "\r",
+		testID(1),
+		testID(3),
+		testID(2),
+	}, "\n")
+
+	r := NewReader(strings.NewReader(input), Options{})
+	var got []string
+	for r.Next() {
+		got = append(got, r.ID())
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{testID(1), testID(2), testID(3)}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if s := r.Stats(); s.Unique != 3 || s.Duplicates != 2 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+}
+
+func TestReader_InvalidLine(t *testing.T) {
+	input := testID(1) + "\nuser_id,name\n" + testID(2) + "\n"
+	r := NewReader(strings.NewReader(input), Options{})
+	n := 0
+	for r.Next() {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("expected 1 ID before the invalid line, got %d", n)
+	}
+	if err := r.Err(); err == nil || !strings.Contains(err.Error(), `line 2: invalid user ID "user_id,name"`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReader_ReportsEveryInvalidLine(t *testing.T) {
+	input := strings.Join([]string{testID(1), "user_id", testID(2), "U123", "", "# note", "n/a"}, "\n")
+	r := NewReader(strings.NewReader(input), Options{})
+	for r.Next() {
+	}
+	var invalid *InvalidError
+	if !errors.As(r.Err(), &invalid) {
+		t.Fatalf("expected an InvalidError, got %v", r.Err())
+	}
+	if invalid.Count != 3 || len(invalid.Lines) != 3 || invalid.Lines[2] != (InvalidLine{Line: 7, Text: "n/a"}) {
+		t.Errorf("unexpected invalid lines: %+v", invalid)
+	}
+	want := `line 2: invalid user ID "user_id" (3 invalid user IDs in total, lines 2, 4, 7)`
+	if r.Err().Error() != want {
+		t.Errorf("got %q, want %q", r.Err(), want)
+	}
+}
+
+func TestReader_SkipInvalid(t *testing.T) {
+	input := strings.Join([]string{"user_id", testID(1), "bad", testID(1), testID(2)}, "\n")
+	r := NewReader(strings.NewReader(input), Options{SkipInvalid: true})
+	var got []string
+	for r.Next() {
+		got = append(got, r.ID())
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %v, want 2 IDs", got)
+	}
+	s := r.Stats()
+	if s.Unique != 2 || s.Duplicates != 1 || s.Invalid != 2 || len(s.InvalidLines) != 2 || s.InvalidLines[1].Line != 3 {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	id := testID(0xabc)
+	tests := []struct{ in, want string }{
+		{id, id},
+		{"  " + id + "\t", id},
+		{"\uFEFF" + id, id},
+		{`"` + id + `"`, id},
+		{"'" + id + "'", id},
+		{"U" + strings.ToUpper(id[1:]), id},
+		{"u" + id[1:], id},
+		{"U123", "U123"},
+	}
+	for _, tt := range tests {
+		got := Normalize(tt.in)
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if tt.want == id && !Valid(got) {
+			t.Errorf("Normalize(%q) is not valid", tt.in)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	ids, stats, err := Clean([]string{testID(1), " " + testID(1), strings.ToUpper(testID(2))}, Options{})
+	if err != nil || len(ids) != 2 || stats.Duplicates != 1 {
+		t.Errorf("Clean() = %v, %+v, %v", ids, stats, err)
+	}
+
+	_, _, err = Clean([]string{testID(1), "U1", "U2"}, Options{})
+	if err == nil || err.Error() != `entry 2: invalid user ID "U1" (2 invalid user IDs in total, entries 2, 3)` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReader_MaxUsers(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 5; i++ {
+		b.WriteString(testID(i) + "\n" + testID(i) + "\n")
+	}
+
+	// Duplicates don't count towards the limit
+	r := NewReader(strings.NewReader(b.String()), Options{MaxUsers: 5})
+	for r.Next() {
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("expected 5 unique IDs to fit a limit of 5, got %v", err)
+	}
+
+	r = NewReader(strings.NewReader(b.String()), Options{MaxUsers: 4})
+	for r.Next() {
+	}
+	if err := r.Err(); !errors.Is(err, ErrTooManyUsers) {
+		t.Errorf("expected ErrTooManyUsers, got %v", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.txt")
+	var b strings.Builder
+	for i := 0; i < 1200; i++ {
+		b.WriteString(testID(i) + "\n")
+	}
+	b.WriteString(testID(7) + "\n")
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var sizes []int
+	stats, err := ReadFile(path, Options{}, 500, func(batch []string) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(sizes) != "[500 500 200]" {
+		t.Errorf("unexpected batch sizes: %v", sizes)
+	}
+	if stats.Unique != 1200 || stats.Duplicates != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	wantErr := errors.New("upload failed")
+	if _, err := ReadFile(path, Options{}, 500, func([]string) error { return wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+}
+
+func TestReadFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(empty, []byte("# header only\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	noop := func([]string) error { return nil }
+
+	if _, err := ReadFile(empty, Options{}, 10, noop); !errors.Is(err, ErrNoUsers) {
+		t.Errorf("expected ErrNoUsers, got %v", err)
+	}
+	if _, err := ReadFile(filepath.Join(dir, "missing.txt"), Options{}, 10, noop); err == nil || !strings.Contains(err.Error(), "failed to read file") {
+		t.Errorf("expected read error, got %v", err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	in := strings.NewReader(testID(1) + "\n" + testID(2) + "\n" + testID(1) + "\n")
+	var got []string
+	stats, err := Read(in, "stdin", Options{}, 10, func(batch []string) error {
+		got = append(got, batch...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || stats.Duplicates != 1 {
+		t.Errorf("unexpected IDs %v, stats %+v", got, stats)
+	}
+
+	_, err = Read(strings.NewReader("bad\n"), "stdin", Options{}, 10, func([]string) error { return nil })
+	if err == nil || !strings.HasPrefix(err.Error(), "stdin: ") {
+		t.Errorf("expected an error naming stdin, got %v", err)
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	const n = 20_000
+	b := newBloomFilter(n)
+	for i := 0; i < n; i++ {
+		if b.addIfAbsent(testID(i)) {
+			t.Fatalf("new ID %d reported as present", i)
+		}
+	}
+	for i := 0; i < n; i++ {
+		if !b.addIfAbsent(testID(i)) {
+			t.Fatalf("ID %d not reported as present", i)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet()
+	if !s.Add(testID(1)) || !s.Add(testID(2)) || !s.Add("not-an-id") {
+		t.Fatal("Add of new IDs should report true")
+	}
+	if s.Add(testID(1)) || s.Add("not-an-id") {
+		t.Error("Add of a repeated ID should report false")
+	}
+	if !s.Has(testID(2)) || !s.Has("not-an-id") || s.Has(testID(3)) {
+		t.Error("Has reported the wrong membership")
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len = %d, want 3", s.Len())
+	}
+}
@@ -0,0 +1,271 @@
+// Package webui serves a minimal web page for the most common campaign
+// operations: sending a prepared message, switching a rich menu alias, and
+// viewing the message quota. It is meant for teammates who need those
+// operations but should not get LINE Official Account Manager access.
+package webui
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+	"github.com/salmonumbrella/line-official-cli/internal/auth"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Templates maps a template name to a single Messaging API message
+	// object. Only these messages can be sent.
+	Templates map[string]json.RawMessage
+	// Password, if set, is required through HTTP basic auth (any user name)
+	Password string
+	// CheckSend returns an error if a send of the given type ("push" or
+	// "broadcast") is not allowed right now, e.g. during a freeze window
+	CheckSend func(sendType string) error
+	// Record is called after each successful change, e.g. to write an
+	// audit log entry
+	Record func(action string, details map[string]any)
+}
+
+// Server handles the web UI and its JSON endpoints.
+type Server struct {
+	client    *api.Client
+	opts      Options
+	csrfToken string
+	page      *template.Template
+}
+
+// NewServer returns a server that performs operations with client.
+func NewServer(client *api.Client, opts Options) (*Server, error) {
+	csrfToken, err := auth.NewCSRFToken()
+	if err != nil {
+		return nil, err
+	}
+	page, err := template.New("page").Parse(pageTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page template: %w", err)
+	}
+	return &Server{client: client, opts: opts, csrfToken: csrfToken, page: page}, nil
+}
+
+// Handler returns the HTTP handler for the UI.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/api/quota", s.handleQuota)
+	mux.HandleFunc("/api/richmenus", s.handleRichMenus)
+	mux.HandleFunc("/api/send", s.handleSend)
+	mux.HandleFunc("/api/alias", s.handleAlias)
+	return s.requirePassword(mux)
+}
+
+// requirePassword enforces HTTP basic auth when a password is configured.
+func (s *Server) requirePassword(next http.Handler) http.Handler {
+	if s.opts.Password == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, password, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(s.opts.Password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="line serve"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) templateNames() []string {
+	names := make([]string, 0, len(s.opts.Templates))
+	for name := range s.opts.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := map[string]any{
+		"CSRFToken": s.csrfToken,
+		"Templates": s.templateNames(),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = s.page.Execute(w, data)
+}
+
+func (s *Server) handleQuota(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	quota, err := s.client.GetMessageQuota(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("Failed to get quota: %v", err))
+		return
+	}
+	usage, err := s.client.GetMessageConsumption(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("Failed to get quota usage: %v", err))
+		return
+	}
+
+	auth.WriteJSON(w, http.StatusOK, map[string]any{
+		"type":  quota.Type,
+		"limit": quota.Value,
+		"used":  usage.TotalUsage,
+	})
+}
+
+func (s *Server) handleRichMenus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	aliases, err := s.client.ListRichMenuAliases(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("Failed to list aliases: %v", err))
+		return
+	}
+	menus, err := s.client.GetRichMenuList(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("Failed to list rich menus: %v", err))
+		return
+	}
+
+	menuList := make([]map[string]string, 0, len(menus))
+	for _, m := range menus {
+		menuList = append(menuList, map[string]string{"richMenuId": m.RichMenuID, "name": m.Name})
+	}
+	if aliases == nil {
+		aliases = []api.RichMenuAlias{}
+	}
+
+	auth.WriteJSON(w, http.StatusOK, map[string]any{
+		"aliases": aliases,
+		"menus":   menuList,
+	})
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	if !s.checkPost(w, r) {
+		return
+	}
+
+	var req struct {
+		Template string `json:"template"`
+		Type     string `json:"type"`
+		To       string `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	message, ok := s.opts.Templates[req.Template]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Unknown template %q", req.Template))
+		return
+	}
+	switch req.Type {
+	case "push":
+		if req.To == "" {
+			writeError(w, http.StatusBadRequest, "A recipient user ID is required for push")
+			return
+		}
+	case "broadcast":
+		req.To = ""
+	default:
+		writeError(w, http.StatusBadRequest, "Type must be push or broadcast")
+		return
+	}
+
+	if s.opts.CheckSend != nil {
+		if err := s.opts.CheckSend(req.Type); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+	}
+
+	if err := s.client.SendMessage(r.Context(), req.Type, req.To, nil, message); err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("Send failed: %v", err))
+		return
+	}
+
+	details := map[string]any{"template": req.Template, "type": req.Type, "remoteAddr": r.RemoteAddr}
+	if req.To != "" {
+		details["to"] = req.To
+	}
+	s.record("serve.send", details)
+
+	auth.WriteJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+func (s *Server) handleAlias(w http.ResponseWriter, r *http.Request) {
+	if !s.checkPost(w, r) {
+		return
+	}
+
+	var req struct {
+		AliasID    string `json:"aliasId"`
+		RichMenuID string `json:"richMenuId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.AliasID == "" || req.RichMenuID == "" {
+		writeError(w, http.StatusBadRequest, "Alias and rich menu are required")
+		return
+	}
+
+	if err := s.client.UpdateRichMenuAlias(r.Context(), req.AliasID, req.RichMenuID); err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("Failed to switch alias: %v", err))
+		return
+	}
+
+	s.record("serve.richmenu-alias", map[string]any{
+		"aliasId":    req.AliasID,
+		"richMenuId": req.RichMenuID,
+		"remoteAddr": r.RemoteAddr,
+	})
+
+	auth.WriteJSON(w, http.StatusOK, map[string]any{"success": true})
+}
+
+// checkPost rejects requests that are not POSTs carrying the CSRF token.
+func (s *Server) checkPost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if r.Header.Get("X-CSRF-Token") != s.csrfToken {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (s *Server) record(action string, details map[string]any) {
+	if s.opts.Record != nil {
+		s.opts.Record(action, details)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	auth.WriteJSON(w, status, map[string]any{
+		"success": false,
+		"error":   msg,
+	})
+}
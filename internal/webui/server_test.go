@@ -0,0 +1,212 @@
+package webui
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/line-official-cli/internal/api"
+)
+
+// newTestServer returns a UI server backed by a fake LINE API that records
+// the paths of requests it receives.
+func newTestServer(t *testing.T, opts Options) (*Server, *httptest.Server, *[]string) {
+	t.Helper()
+	var calls []string
+	lineAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		switch r.URL.Path {
+		case "/v2/bot/message/quota":
+			_, _ = w.Write([]byte(`{"type":"limited","value":1000}`))
+		case "/v2/bot/message/quota/consumption":
+			_, _ = w.Write([]byte(`{"totalUsage":250}`))
+		case "/v2/bot/richmenu/alias/list":
+			_, _ = w.Write([]byte(`{"aliases":[{"richMenuAliasId":"main","richMenuId":"richmenu-a"}]}`))
+		case "/v2/bot/richmenu/list":
+			_, _ = w.Write([]byte(`{"richmenus":[{"richMenuId":"richmenu-a","name":"A"},{"richMenuId":"richmenu-b","name":"B"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(lineAPI.Close)
+
+	client := api.NewClient("test-token", false, false)
+	client.SetBaseURL(lineAPI.URL)
+
+	srv, err := NewServer(client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui := httptest.NewServer(srv.Handler())
+	t.Cleanup(ui.Close)
+	return srv, ui, &calls
+}
+
+func postJSON(t *testing.T, url, csrf string, body any) (*http.Response, map[string]any) {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(string(data)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-CSRF-Token", csrf)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var result map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return resp, result
+}
+
+func TestServer_Page(t *testing.T) {
+	srv, ui, _ := newTestServer(t, Options{Templates: map[string]json.RawMessage{
+		"spring-sale": json.RawMessage(`{"type":"text","text":"Sale!"}`),
+	}})
+
+	resp, err := http.Get(ui.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+
+	for _, want := range []string{"spring-sale", srv.csrfToken} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected page to contain %q", want)
+		}
+	}
+}
+
+func TestServer_QuotaAndRichMenus(t *testing.T) {
+	_, ui, _ := newTestServer(t, Options{})
+
+	var quota map[string]any
+	resp, err := http.Get(ui.URL + "/api/quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&quota)
+	_ = resp.Body.Close()
+	if quota["type"] != "limited" || quota["limit"] != float64(1000) || quota["used"] != float64(250) {
+		t.Errorf("unexpected quota: %v", quota)
+	}
+
+	var menus struct {
+		Aliases []api.RichMenuAlias `json:"aliases"`
+		Menus   []map[string]string `json:"menus"`
+	}
+	resp, err = http.Get(ui.URL + "/api/richmenus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&menus)
+	_ = resp.Body.Close()
+	if len(menus.Aliases) != 1 || menus.Aliases[0].RichMenuAliasID != "main" || len(menus.Menus) != 2 {
+		t.Errorf("unexpected rich menus: %+v", menus)
+	}
+}
+
+func TestServer_Send(t *testing.T) {
+	var recorded []map[string]any
+	srv, ui, calls := newTestServer(t, Options{
+		Templates: map[string]json.RawMessage{"hello": json.RawMessage(`{"type":"text","text":"Hello"}`)},
+		Record: func(action string, details map[string]any) {
+			details["action"] = action
+			recorded = append(recorded, details)
+		},
+	})
+
+	resp, result := postJSON(t, ui.URL+"/api/send", srv.csrfToken, map[string]string{"template": "hello", "type": "push", "to": "U123"})
+	if resp.StatusCode != http.StatusOK || result["success"] != true {
+		t.Fatalf("unexpected response: %d %v", resp.StatusCode, result)
+	}
+	if len(*calls) != 1 || !strings.HasPrefix((*calls)[0], "POST /v2/bot/message/push") || !strings.Contains((*calls)[0], `"text":"Hello"`) {
+		t.Errorf("unexpected API calls: %v", *calls)
+	}
+	if len(recorded) != 1 || recorded[0]["action"] != "serve.send" || recorded[0]["to"] != "U123" {
+		t.Errorf("unexpected record: %v", recorded)
+	}
+}
+
+func TestServer_SendRejected(t *testing.T) {
+	srv, ui, calls := newTestServer(t, Options{
+		Templates: map[string]json.RawMessage{"hello": json.RawMessage(`{"type":"text","text":"Hello"}`)},
+		CheckSend: func(sendType string) error {
+			if sendType == "broadcast" {
+				return errors.New("broadcast is blocked by freeze window (launch)")
+			}
+			return nil
+		},
+	})
+
+	tests := []struct {
+		name   string
+		csrf   string
+		body   map[string]string
+		status int
+	}{
+		{"missing csrf", "", map[string]string{"template": "hello", "type": "push", "to": "U1"}, http.StatusForbidden},
+		{"unknown template", srv.csrfToken, map[string]string{"template": "nope", "type": "push", "to": "U1"}, http.StatusBadRequest},
+		{"push without recipient", srv.csrfToken, map[string]string{"template": "hello", "type": "push"}, http.StatusBadRequest},
+		{"multicast", srv.csrfToken, map[string]string{"template": "hello", "type": "multicast"}, http.StatusBadRequest},
+		{"freeze", srv.csrfToken, map[string]string{"template": "hello", "type": "broadcast"}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := postJSON(t, ui.URL+"/api/send", tt.csrf, tt.body)
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+	if len(*calls) != 0 {
+		t.Errorf("rejected sends must not reach the API, got: %v", *calls)
+	}
+}
+
+func TestServer_Alias(t *testing.T) {
+	var recorded []string
+	srv, ui, calls := newTestServer(t, Options{
+		Record: func(action string, details map[string]any) { recorded = append(recorded, action) },
+	})
+
+	resp, result := postJSON(t, ui.URL+"/api/alias", srv.csrfToken, map[string]string{"aliasId": "main", "richMenuId": "richmenu-b"})
+	if resp.StatusCode != http.StatusOK || result["success"] != true {
+		t.Fatalf("unexpected response: %d %v", resp.StatusCode, result)
+	}
+	if len(*calls) != 1 || !strings.HasPrefix((*calls)[0], "POST /v2/bot/richmenu/alias/main") || !strings.Contains((*calls)[0], "richmenu-b") {
+		t.Errorf("unexpected API calls: %v", *calls)
+	}
+	if len(recorded) != 1 || recorded[0] != "serve.richmenu-alias" {
+		t.Errorf("unexpected records: %v", recorded)
+	}
+}
+
+func TestServer_Password(t *testing.T) {
+	_, ui, _ := newTestServer(t, Options{Password: "s3cret"})
+
+	resp, err := http.Get(ui.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without credentials, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ui.URL+"/", nil)
+	req.SetBasicAuth("teammate", "s3cret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 with password, got %d", resp.StatusCode)
+	}
+}
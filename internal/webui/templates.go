@@ -0,0 +1,232 @@
+package webui
+
+const pageTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>LINE CLI - Campaign Operations</title>
+    <style>
+        :root {
+            --bg: #0F0F0F;
+            --bg-card: #1A1A1A;
+            --bg-input: #252525;
+            --border: #333333;
+            --text: #FFFFFF;
+            --text-secondary: #B3B3B3;
+            --line-green: #06C755;
+            --line-green-dark: #05A847;
+            --error: #EF4444;
+        }
+
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;
+            background: var(--bg);
+            color: var(--text);
+            padding: 2rem 1.5rem;
+        }
+
+        main { max-width: 560px; margin: 0 auto; display: grid; gap: 1.25rem; }
+        h1 { font-size: 1.25rem; font-weight: 600; }
+        h2 { font-size: 1rem; font-weight: 600; margin-bottom: 0.75rem; }
+
+        section {
+            background: var(--bg-card);
+            border: 1px solid var(--border);
+            border-radius: 12px;
+            padding: 1.25rem;
+        }
+
+        label { display: block; font-size: 0.85rem; color: var(--text-secondary); margin: 0.75rem 0 0.35rem; }
+        select, input {
+            width: 100%;
+            padding: 0.6rem 0.75rem;
+            background: var(--bg-input);
+            border: 1px solid var(--border);
+            border-radius: 8px;
+            color: var(--text);
+            font-size: 0.95rem;
+        }
+
+        button {
+            margin-top: 1rem;
+            padding: 0.6rem 1.25rem;
+            background: var(--line-green);
+            border: none;
+            border-radius: 8px;
+            color: #FFFFFF;
+            font-weight: 600;
+            cursor: pointer;
+        }
+        button:hover { background: var(--line-green-dark); }
+        button:disabled { opacity: 0.5; cursor: default; }
+
+        .quota { font-size: 1.5rem; font-weight: 600; }
+        .muted { color: var(--text-secondary); font-size: 0.85rem; }
+        .status { margin-top: 0.75rem; font-size: 0.9rem; min-height: 1.2em; }
+        .status.ok { color: var(--line-green); }
+        .status.error { color: var(--error); }
+        .hidden { display: none; }
+    </style>
+</head>
+<body>
+<main>
+    <h1>Campaign Operations</h1>
+
+    <section>
+        <h2>Message Quota</h2>
+        <div class="quota" id="quota">Loading...</div>
+        <div class="muted" id="quota-detail"></div>
+    </section>
+
+    <section>
+        <h2>Send a Template Message</h2>
+        {{if .Templates}}
+        <label for="template">Template</label>
+        <select id="template">
+            {{range .Templates}}<option value="{{.}}">{{.}}</option>{{end}}
+        </select>
+        <label for="send-type">Send to</label>
+        <select id="send-type">
+            <option value="push">One user (push)</option>
+            <option value="broadcast">All followers (broadcast)</option>
+        </select>
+        <div id="to-field">
+            <label for="to">User ID</label>
+            <input id="to" placeholder="U1234567890abcdef...">
+        </div>
+        <button id="send">Send</button>
+        <div class="status" id="send-status"></div>
+        {{else}}
+        <p class="muted">No templates loaded. Start the server with --templates DIR.</p>
+        {{end}}
+    </section>
+
+    <section>
+        <h2>Switch Rich Menu Alias</h2>
+        <label for="alias">Alias</label>
+        <select id="alias"></select>
+        <label for="menu">Rich menu</label>
+        <select id="menu"></select>
+        <button id="switch">Switch</button>
+        <div class="status" id="alias-status"></div>
+    </section>
+</main>
+
+<script>
+    const csrfToken = '{{.CSRFToken}}';
+
+    function setStatus(id, ok, text) {
+        const el = document.getElementById(id);
+        el.className = 'status ' + (ok ? 'ok' : 'error');
+        el.textContent = text;
+    }
+
+    async function post(path, body) {
+        const response = await fetch(path, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json', 'X-CSRF-Token': csrfToken },
+            body: JSON.stringify(body)
+        });
+        const data = await response.json();
+        if (!data.success) {
+            throw new Error(data.error || 'Request failed');
+        }
+        return data;
+    }
+
+    async function loadQuota() {
+        try {
+            const response = await fetch('/api/quota');
+            const data = await response.json();
+            if (data.error) throw new Error(data.error);
+            if (data.type === 'limited') {
+                document.getElementById('quota').textContent = data.used + ' / ' + data.limit;
+                document.getElementById('quota-detail').textContent = (data.limit - data.used) + ' messages left this month';
+            } else {
+                document.getElementById('quota').textContent = data.used + ' sent';
+                document.getElementById('quota-detail').textContent = 'No monthly limit';
+            }
+        } catch (err) {
+            document.getElementById('quota').textContent = 'Unavailable';
+            document.getElementById('quota-detail').textContent = err.message;
+        }
+    }
+
+    let aliases = [];
+
+    async function loadRichMenus() {
+        try {
+            const response = await fetch('/api/richmenus');
+            const data = await response.json();
+            if (data.error) throw new Error(data.error);
+            aliases = data.aliases;
+            const aliasSelect = document.getElementById('alias');
+            const menuSelect = document.getElementById('menu');
+            aliasSelect.replaceChildren(...aliases.map(a => new Option(a.richMenuAliasId, a.richMenuAliasId)));
+            menuSelect.replaceChildren(...data.menus.map(m => new Option(m.name + ' (' + m.richMenuId + ')', m.richMenuId)));
+            showCurrentMenu();
+        } catch (err) {
+            setStatus('alias-status', false, err.message);
+        }
+    }
+
+    function showCurrentMenu() {
+        const current = aliases.find(a => a.richMenuAliasId === document.getElementById('alias').value);
+        if (current) {
+            document.getElementById('menu').value = current.richMenuId;
+        }
+    }
+
+    document.getElementById('alias').addEventListener('change', showCurrentMenu);
+
+    document.getElementById('switch').addEventListener('click', async (e) => {
+        const aliasId = document.getElementById('alias').value;
+        const richMenuId = document.getElementById('menu').value;
+        e.target.disabled = true;
+        try {
+            await post('/api/alias', { aliasId, richMenuId });
+            setStatus('alias-status', true, 'Alias ' + aliasId + ' now points to ' + richMenuId);
+            await loadRichMenus();
+        } catch (err) {
+            setStatus('alias-status', false, err.message);
+        } finally {
+            e.target.disabled = false;
+        }
+    });
+
+    const sendButton = document.getElementById('send');
+    if (sendButton) {
+        const typeSelect = document.getElementById('send-type');
+        typeSelect.addEventListener('change', () => {
+            document.getElementById('to-field').classList.toggle('hidden', typeSelect.value !== 'push');
+        });
+
+        sendButton.addEventListener('click', async () => {
+            const template = document.getElementById('template').value;
+            const type = typeSelect.value;
+            const to = document.getElementById('to').value.trim();
+            if (type === 'broadcast' && !confirm('Broadcast "' + template + '" to ALL followers?')) {
+                return;
+            }
+            sendButton.disabled = true;
+            try {
+                await post('/api/send', { template, type, to });
+                setStatus('send-status', true, 'Sent "' + template + '"' + (type === 'push' ? ' to ' + to : ' to all followers'));
+                loadQuota();
+            } catch (err) {
+                setStatus('send-status', false, err.message);
+            } finally {
+                sendButton.disabled = false;
+            }
+        });
+    }
+
+    loadQuota();
+    loadRichMenus();
+</script>
+</body>
+</html>
+`